@@ -9,14 +9,17 @@ import (
 	"fmt"
 	golog "log"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/migrations"
 	"code.gitea.io/gitea/modules/doctor"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/urfave/cli"
 	"xorm.io/xorm"
@@ -60,6 +63,7 @@ var CmdDoctor = cli.Command{
 	},
 	Subcommands: []cli.Command{
 		cmdRecreateTable,
+		cmdDoctorStorage,
 	},
 }
 
@@ -81,6 +85,79 @@ You should back-up your database before doing this and ensure that your database
 	Action: runRecreateTable,
 }
 
+var cmdDoctorStorage = cli.Command{
+	Name:  "storage",
+	Usage: "Check the consistency of database records and storage objects",
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "type, t",
+			Usage: "Type of stored files to check (default: all).  Allowed types: '" + strings.Join(doctor.StorageCategories, "', '") + "'",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output the report as JSON",
+		},
+		cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Delete orphaned objects and repair the records of missing objects where possible",
+		},
+	},
+	Description: `Cross-checks the database records of attachments, avatars, repository avatars, LFS objects, repository archives and package blobs against the configured storage and reports missing objects, orphaned objects and objects whose size differs from the database.
+
+With --fix orphaned objects are deleted, avatars whose objects are missing are reset and repository archives which are missing or broken are removed so they are generated again. Missing attachments, LFS objects and package blobs can't be repaired and are only reported.`,
+	Action: runDoctorStorage,
+}
+
+func runDoctorStorage(ctx *cli.Context) error {
+	stdCtx, cancel := installSignals()
+	defer cancel()
+
+	if err := initDB(stdCtx); err != nil {
+		return err
+	}
+	if err := storage.Init(); err != nil {
+		return err
+	}
+
+	categories := ctx.StringSlice("type")
+	for i, category := range categories {
+		categories[i] = strings.ToLower(strings.TrimSpace(category))
+	}
+
+	report, err := doctor.CheckStorageConsistency(stdCtx, categories, ctx.Bool("fix"))
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		bs, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(string(bs))
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	_, _ = fmt.Fprintln(w, "Category\tIssue\tPath\tExpected Size\tActual Size\tFixed")
+	for _, category := range report.Categories {
+		for _, issue := range category.Issues {
+			fixed := strconv.FormatBool(issue.Fixed)
+			if issue.FixError != "" {
+				fixed = issue.FixError
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", category.Category, issue.Type, issue.Path, issue.ExpectedSize, issue.ActualSize, fixed)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, category := range report.Categories {
+		fmt.Printf("%s: %d records, %d objects, %d issues\n", category.Category, category.Records, category.Objects, len(category.Issues))
+	}
+	return nil
+}
+
 func runRecreateTable(ctx *cli.Context) error {
 	// Redirect the default golog to here
 	golog.SetFlags(0)
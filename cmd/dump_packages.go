@@ -0,0 +1,122 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/migrations"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/urfave/cli"
+)
+
+// CmdDumpPackages represents the available dump packages sub-command.
+var CmdDumpPackages = cli.Command{
+	Name:        "dump-packages",
+	Usage:       "Dump all packages into an archive",
+	Description: "This is a command for exporting the metadata and the files of all packages into a zip archive which can be imported with restore-packages.",
+	Action:      runDumpPackages,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "file, f",
+			Value: "gitea-packages.zip",
+			Usage: "Name of the archive to create",
+		},
+	},
+}
+
+// CmdRestorePackages represents the available restore packages sub-command.
+var CmdRestorePackages = cli.Command{
+	Name:        "restore-packages",
+	Usage:       "Restore packages from an archive",
+	Description: "This is a command for importing packages from an archive created by dump-packages. Owners must exist already and existing versions are skipped.",
+	Action:      runRestorePackages,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "file, f",
+			Value: "gitea-packages.zip",
+			Usage: "Name of the archive to restore from",
+		},
+	},
+}
+
+func initPackagesCommand(ctx context.Context) error {
+	if err := initDB(ctx); err != nil {
+		return err
+	}
+
+	log.Info("AppPath: %s", setting.AppPath)
+	log.Info("AppWorkPath: %s", setting.AppWorkPath)
+	log.Info("Custom path: %s", setting.CustomPath)
+	log.Info("Log path: %s", setting.LogRootPath)
+	log.Info("Configuration file: %s", setting.CustomConf)
+
+	if err := db.InitEngineWithMigration(ctx, migrations.EnsureUpToDate); err != nil {
+		return err
+	}
+
+	return storage.Init()
+}
+
+func runDumpPackages(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	if err := initPackagesCommand(ctx); err != nil {
+		return err
+	}
+
+	fileName := c.String("file")
+	f, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", fileName, err)
+	}
+	defer f.Close()
+
+	if err := packages_service.ExportPackages(ctx, f); err != nil {
+		return fmt.Errorf("unable to dump packages: %w", err)
+	}
+
+	log.Info("Packages dumped to %s", fileName)
+	fmt.Printf("Packages dumped to %s\n", fileName)
+	return f.Close()
+}
+
+func runRestorePackages(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	if err := initPackagesCommand(ctx); err != nil {
+		return err
+	}
+
+	fileName := c.String("file")
+	f, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", fileName, err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	result, err := packages_service.ImportPackages(ctx, f, fi.Size())
+	if err != nil {
+		return fmt.Errorf("unable to restore packages: %w", err)
+	}
+
+	log.Info("Restored %d package versions, skipped %d", result.ImportedVersions, result.SkippedVersions)
+	fmt.Printf("Restored %d package versions, skipped %d\n", result.ImportedVersions, result.SkippedVersions)
+	return nil
+}
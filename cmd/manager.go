@@ -27,6 +27,7 @@ var (
 			subcmdFlushQueues,
 			subcmdLogging,
 			subCmdProcesses,
+			subcmdRecalculateRepoStats,
 		},
 	}
 	subcmdShutdown = cli.Command{
@@ -0,0 +1,103 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/private"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/urfave/cli"
+)
+
+var subcmdRecalculateRepoStats = cli.Command{
+	Name:  "recalculate-repo-stats",
+	Usage: "Recalculate the statistics of all repositories in the running process",
+	Description: `The repositories are recalculated in the background by the workers of the repo_stats_recalculation queue.
+A recalculation interrupted by a restart is resumed. The kinds of statistics are:
+
+  size       the size of the git repository and its LFS objects
+  languages  the language statistics of the default branch
+  commits    the cached commit count of the default branch
+  counters   the numbers of issues, pulls, stars, watches and the label and milestone counters`,
+	Action: runRecalculateRepoStats,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "kind, k",
+			Value: nil,
+			Usage: "Kind of statistics to recalculate, can be repeated - will default to all kinds",
+		},
+		cli.BoolFlag{
+			Name:  "status",
+			Usage: "Show the progress of the latest recalculation instead of starting a new one",
+		},
+		cli.BoolFlag{
+			Name:  "cancel",
+			Usage: "Cancel the running recalculation",
+		},
+		cli.BoolFlag{
+			Name:  "wait",
+			Usage: "Report the progress until the recalculation is finished",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Value: 5 * time.Second,
+			Usage: "Interval of the progress reports when waiting",
+		},
+		cli.BoolFlag{
+			Name: "debug",
+		},
+	},
+}
+
+func runRecalculateRepoStats(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setup("manager", c.Bool("debug"))
+
+	var r *api.RepoStatsRecalculation
+	var statusCode int
+	var msg string
+	switch {
+	case c.Bool("cancel"):
+		r, statusCode, msg = private.CancelRepoStatsRecalculation(ctx)
+	case c.Bool("status"):
+		r, statusCode, msg = private.GetRepoStatsRecalculation(ctx)
+	default:
+		r, statusCode, msg = private.RecalculateRepoStats(ctx, c.StringSlice("kind"))
+	}
+	if statusCode != http.StatusOK {
+		return fail(msg, "")
+	}
+	fmt.Fprintln(os.Stdout, formatRepoStatsRecalculation(r))
+
+	if !c.Bool("wait") {
+		return nil
+	}
+	for r.Status == "running" {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.Duration("interval")):
+		}
+		r, statusCode, msg = private.GetRepoStatsRecalculation(ctx)
+		if statusCode != http.StatusOK {
+			return fail(msg, "")
+		}
+		fmt.Fprintln(os.Stdout, formatRepoStatsRecalculation(r))
+	}
+	return nil
+}
+
+func formatRepoStatsRecalculation(r *api.RepoStatsRecalculation) string {
+	return fmt.Sprintf("Recalculation %d of %s: %s - %d of %d repositories queued, %d processed, %d failed",
+		r.ID, strings.Join(r.Kinds, ", "), r.Status, r.Queued, r.Total, r.Processed, r.Failed)
+}
@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"strings"
 
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
@@ -80,6 +82,18 @@ func runACME(listenAddr string, m http.Handler) error {
 
 	magic.Issuers = []certmagic.Issuer{myACME}
 
+	if setting.Packages.Enabled {
+		// the certificates of the custom domains of the container registries are obtained on their first use
+		magic.OnDemand = &certmagic.OnDemandConfig{
+			DecisionFunc: func(name string) error {
+				if _, err := packages_model.GetContainerDomainByDomain(db.DefaultContext, name); err != nil {
+					return fmt.Errorf("%s is not a container registry domain: %w", name, err)
+				}
+				return nil
+			},
+		}
+	}
+
 	// this obtains certificates or renews them if necessary
 	err := magic.ManageSync(graceful.GetManager().HammerContext(), []string{setting.Domain})
 	if err != nil {
@@ -8,7 +8,10 @@ import (
 	"crypto/tls"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
@@ -184,9 +187,56 @@ func runHTTPS(network, listenAddr, name, certFile, keyFile string, m http.Handle
 		return err
 	}
 
+	if setting.Packages.Enabled && setting.Packages.ContainerDomainCertPath != "" {
+		tlsConfig.GetCertificate = containerDomainCertificates.get
+	}
+
 	return graceful.HTTPListenAndServeTLSConfig(network, listenAddr, name, tlsConfig, m, useProxyProtocol, proxyProtocolTLSBridging)
 }
 
 func runHTTPSWithTLSConfig(network, listenAddr, name string, tlsConfig *tls.Config, m http.Handler, useProxyProtocol, proxyProtocolTLSBridging bool) error {
 	return graceful.HTTPListenAndServeTLSConfig(network, listenAddr, name, tlsConfig, m, useProxyProtocol, proxyProtocolTLSBridging)
 }
+
+type cachedCertificate struct {
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// domainCertificates serves the certificates of the custom domains of the container registries,
+// read from the <domain>.crt and <domain>.key files of the certificate path. Changed files are reloaded.
+type domainCertificates struct {
+	mu    sync.Mutex
+	certs map[string]*cachedCertificate
+}
+
+var containerDomainCertificates = &domainCertificates{certs: make(map[string]*cachedCertificate)}
+
+func (dc *domainCertificates) get(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(hello.ServerName)
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, nil
+	}
+
+	certFile := filepath.Join(setting.Packages.ContainerDomainCertPath, name+".crt")
+	keyFile := filepath.Join(setting.Packages.ContainerDomainCertPath, name+".key")
+	fi, err := os.Stat(certFile)
+	if err != nil {
+		// no certificate for the domain, the default certificate is used
+		return nil, nil
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if cached, has := dc.certs[name]; has && cached.modTime.Equal(fi.ModTime()) {
+		return cached.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		log.Error("Failed to load the certificate of the container registry domain %s: %v", name, err)
+		return nil, nil
+	}
+	dc.certs[name] = &cachedCertificate{cert: &cert, modTime: fi.ModTime()}
+	return &cert, nil
+}
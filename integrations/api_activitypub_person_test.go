@@ -106,6 +106,18 @@ func TestActivityPubPersonInbox(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 
+		// Disabled activity types are rejected
+		setting.Federation.DisabledActivityTypes = []string{"Follow"}
+		defer func() {
+			setting.Federation.DisabledActivityTypes = nil
+		}()
+		resp, err = c.Post([]byte(`{"type":"Follow"}`), user2inboxurl)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		resp, err = c.Post([]byte(`{"type":"Like"}`), user2inboxurl)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
 		// Unsigned request fails
 		req := NewRequest(t, "POST", user2inboxurl)
 		MakeRequest(t, req, http.StatusInternalServerError)
@@ -0,0 +1,99 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/auth/source/pam"
+	"code.gitea.io/gitea/services/auth/source/smtp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminAuthSources(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// nothing listens on port 1, the source is unreachable
+	unreachable := &auth_model.Source{
+		Type:     auth_model.SMTP,
+		Name:     "unreachable-smtp",
+		IsActive: true,
+		Cfg: &smtp.Source{
+			Auth: "PLAIN",
+			Addr: "127.0.0.1",
+			Port: 1,
+		},
+	}
+	assert.NoError(t, auth_model.CreateSource(unreachable))
+
+	notCheckable := &auth_model.Source{
+		Type:             auth_model.PAM,
+		Name:             "pam",
+		IsActive:         true,
+		FallbackSourceID: unreachable.ID,
+		Cfg:              &pam.Source{ServiceName: "gitea"},
+	}
+	assert.NoError(t, auth_model.CreateSource(notCheckable))
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	t.Run("List", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestf(t, "GET", "/api/v1/admin/auth_sources?token=%s", token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var sources []*api.AuthSource
+		DecodeJSON(t, resp, &sources)
+		if assert.Len(t, sources, 2) {
+			assert.Equal(t, "unreachable-smtp", sources[0].Name)
+			assert.Equal(t, "smtp", sources[0].Type)
+			assert.Equal(t, "pam", sources[1].Type)
+			assert.Equal(t, unreachable.ID, sources[1].FallbackSourceID)
+			assert.Equal(t, "unknown", sources[1].Health.Status)
+		}
+	})
+
+	t.Run("CheckHealth", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestf(t, "POST", "/api/v1/admin/auth_sources/%d/check_health?token=%s", unreachable.ID, token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var source *api.AuthSource
+		DecodeJSON(t, resp, &source)
+		assert.Equal(t, "unhealthy", source.Health.Status)
+		assert.NotEmpty(t, source.Health.Error)
+		assert.NotNil(t, source.Health.CheckedAt)
+		assert.Nil(t, source.Health.LastHealthyAt)
+
+		unittest.AssertExistsAndLoadBean(t, &auth_model.SourceHealth{SourceID: unreachable.ID, IsHealthy: false})
+
+		req = NewRequestf(t, "GET", "/api/v1/admin/auth_sources/%d?token=%s", unreachable.ID, token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &source)
+		assert.Equal(t, "unhealthy", source.Health.Status)
+
+		req = NewRequestf(t, "POST", "/api/v1/admin/auth_sources/%d/check_health?token=%s", notCheckable.ID, token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestf(t, "GET", "/api/v1/admin/auth_sources/%d?token=%s", 9999, token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/auth_sources?token=%s", getUserToken(t, "user2")))
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
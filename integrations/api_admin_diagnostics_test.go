@@ -0,0 +1,105 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/diagnostics"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminDiagnostics(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(enabled bool, threshold time.Duration) {
+		setting.Diagnostics.EnableRequestLog = enabled
+		setting.Diagnostics.SlowQueryThreshold = threshold
+		diagnostics.Reset()
+	}(setting.Diagnostics.EnableRequestLog, setting.Diagnostics.SlowQueryThreshold)
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	diagnostics.Reset()
+	setting.Diagnostics.EnableRequestLog = true
+	setting.Diagnostics.SlowQueryThreshold = time.Nanosecond
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1?token="+token)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/not-existing?token="+token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	setting.Diagnostics.SlowQueryThreshold = 0
+
+	t.Run("Requests", func(t *testing.T) {
+		req := NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?route=/api/v1/repos/&token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var requests []*api.DiagnosticRequest
+		DecodeJSON(t, resp, &requests)
+		assert.Len(t, requests, 2)
+		assert.Equal(t, "/api/v1/repos/user2/not-existing", requests[0].Path)
+		assert.Equal(t, http.StatusNotFound, requests[0].Status)
+		assert.Equal(t, "/api/v1/repos/user2/repo1", requests[1].Path)
+		assert.Equal(t, "/api/v1/repos/{username}/{reponame}", requests[1].Route)
+		assert.Equal(t, "GET", requests[1].Method)
+		assert.Equal(t, "user1", requests[1].User)
+		assert.Equal(t, http.StatusOK, requests[1].Status)
+		assert.NotZero(t, requests[1].Size)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?route=/api/v1/repos/&min_status=400&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &requests)
+		assert.Len(t, requests, 1)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?limit=1&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &requests)
+		assert.Len(t, requests, 1)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?route=/api/v1/repos/&format=csv&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, "time,method,route,path,user,status,duration_ms,size", lines[0])
+		assert.Contains(t, lines[2], ",GET,/api/v1/repos/{username}/{reponame},/api/v1/repos/user2/repo1,user1,200,")
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?min_duration=invalid&token="+token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?format=xml&token="+token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("SlowQueries", func(t *testing.T) {
+		req := NewRequest(t, "GET", "/api/v1/admin/diagnostics/slow_queries?token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var queries []*api.DiagnosticSlowQuery
+		DecodeJSON(t, resp, &queries)
+		assert.NotEmpty(t, queries)
+		for _, q := range queries {
+			assert.NotEmpty(t, q.SQL)
+		}
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/slow_queries?min_duration=1h&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &queries)
+		assert.Empty(t, queries)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/slow_queries?format=csv&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.True(t, strings.HasPrefix(resp.Body.String(), "time,duration_ms,sql,error\n"))
+	})
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", "/api/v1/admin/diagnostics/requests?token="+token)
+	MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	federation_model "code.gitea.io/gitea/models/federation"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminFederatedInstances(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	url := fmt.Sprintf("/api/v1/admin/federation/instances/Spam.Example.com?token=%s", token)
+
+	req := NewRequestWithJSON(t, "PUT", url, &api.SetFederatedInstanceOption{Policy: "block", Reason: "spam"})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var instance *api.FederatedInstance
+	DecodeJSON(t, resp, &instance)
+	assert.Equal(t, "spam.example.com", instance.Host)
+	assert.Equal(t, "block", instance.Policy)
+	assert.Equal(t, "spam", instance.Reason)
+	unittest.AssertExistsAndLoadBean(t, &federation_model.Instance{Host: "spam.example.com", Policy: federation_model.InstancePolicyBlock})
+
+	req = NewRequestWithJSON(t, "PUT", url, &api.SetFederatedInstanceOption{Policy: "allow"})
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &instance)
+	assert.Equal(t, "allow", instance.Policy)
+
+	req = NewRequestWithJSON(t, "PUT", url, &api.SetFederatedInstanceOption{Policy: "ignore"})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/admin/federation/instances/example.org?token=%s", token), &api.SetFederatedInstanceOption{Policy: "block"})
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/federation/instances?token=%s", token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var instances []*api.FederatedInstance
+	DecodeJSON(t, resp, &instances)
+	assert.Len(t, instances, 2)
+	assert.Equal(t, "example.org", instances[0].Host)
+	assert.Equal(t, "spam.example.com", instances[1].Host)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/federation/instances?policy=block&token=%s", token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &instances)
+	assert.Len(t, instances, 1)
+	assert.Equal(t, "example.org", instances[0].Host)
+
+	req = NewRequest(t, "DELETE", url)
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &federation_model.Instance{Host: "spam.example.com"})
+
+	req = NewRequest(t, "DELETE", url)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	t.Run("NoAdmin", func(t *testing.T) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/federation/instances?token=%s", token))
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
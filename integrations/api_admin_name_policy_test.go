@@ -0,0 +1,125 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	user_service "code.gitea.io/gitea/services/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminNamePolicies(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	policiesURL := fmt.Sprintf("/api/v1/admin/name_policies?token=%s", token)
+
+	req := NewRequestWithJSON(t, "POST", policiesURL, &api.CreateNamePolicyOption{Kind: "reserved", Pattern: "staff-*"})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var reserved *api.NamePolicy
+	DecodeJSON(t, resp, &reserved)
+	assert.Equal(t, "reserved", reserved.Kind)
+	assert.Equal(t, "staff-*", reserved.Pattern)
+
+	req = NewRequestWithJSON(t, "POST", policiesURL, &api.CreateNamePolicyOption{Kind: "service_prefix", Pattern: "bot-"})
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithJSON(t, "POST", policiesURL, &api.CreateNamePolicyOption{Kind: "reserved", Pattern: "[staff"})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", policiesURL)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var policies []*api.NamePolicy
+	DecodeJSON(t, resp, &policies)
+	assert.Len(t, policies, 2)
+
+	createUser := func(name string, serviceAccount bool, status int) {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/admin/users?token=%s", token), &api.CreateUserOption{
+			Username:       name,
+			Email:          name + "@example.com",
+			Password:       "password",
+			ServiceAccount: serviceAccount,
+		})
+		MakeRequest(t, req, status)
+	}
+	createUser("staff-alice", false, http.StatusUnprocessableEntity)
+	createUser("bot-deploy", false, http.StatusUnprocessableEntity)
+	createUser("deploy", true, http.StatusUnprocessableEntity)
+	createUser("bot-deploy", true, http.StatusCreated)
+	createUser("alice", false, http.StatusCreated)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs?token=%s", token), &api.CreateOrgOption{UserName: "staff-team"})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/name_policies/%d?token=%s", reserved.ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	MakeRequest(t, req, http.StatusNotFound)
+	createUser("staff-alice", false, http.StatusCreated)
+}
+
+func TestAPIAdminNameClaims(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	claimsURL := fmt.Sprintf("/api/v1/admin/name_claims?token=%s", token)
+
+	// organizations and active users can't be claimed
+	req := NewRequestWithJSON(t, "POST", claimsURL, &api.CreateNameClaimOption{Username: "user3"})
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", claimsURL, &api.CreateNameClaimOption{Username: "user1"})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	defer func(grace time.Duration) {
+		setting.Admin.NameClaimGracePeriod = grace
+	}(setting.Admin.NameClaimGracePeriod)
+	setting.Admin.NameClaimGracePeriod = 0
+
+	req = NewRequestWithJSON(t, "POST", claimsURL, &api.CreateNameClaimOption{Username: "user4", Claimant: "user5"})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var claim *api.NameClaim
+	DecodeJSON(t, resp, &claim)
+	assert.Equal(t, "user4", claim.Name)
+	assert.Equal(t, "pending", claim.Status)
+	assert.Equal(t, "user5", claim.Claimant.UserName)
+
+	req = NewRequestWithJSON(t, "POST", claimsURL, &api.CreateNameClaimOption{Username: "user4"})
+	MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithJSON(t, "POST", claimsURL, &api.CreateNameClaimOption{Username: "user8"})
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var cancelled *api.NameClaim
+	DecodeJSON(t, resp, &cancelled)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/name_claims/%d?token=%s", cancelled.ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	assert.NoError(t, user_service.ProcessNameClaims(db.DefaultContext))
+
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4, Name: "dormant-4"})
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5, Name: "user4"})
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 8, Name: "user8"})
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/name_claims?status=completed&token=%s", token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var claims []*api.NameClaim
+	DecodeJSON(t, resp, &claims)
+	if assert.Len(t, claims, 1) {
+		assert.Equal(t, claim.ID, claims[0].ID)
+	}
+}
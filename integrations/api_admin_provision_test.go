@@ -0,0 +1,89 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	auth_model "code.gitea.io/gitea/models/auth"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminProvisioning(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/admin/users/user2/keys?token="+token, &api.CreateKeyOption{
+		Key:   "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC4cn+iXnA4KvcQYSV88vGn0Yi91vG47t1P7okprVmhNTkipNRIHWr6WdCO4VDr/cvsRkuVJAsLO2enwjGWWueOO6BodiBgyAOZ/5t5nJNMCNuLGT5UIo/RI1b0WRQwxEZTRjt6mFNw6lH14wRd8ulsr9toSWBPMOGWoYs1PDeDL0JuTjL+tr1SZi/EyxCngpYszKdXllJEHyI79KQgeD0Vt3pTrkbNVTOEcCNqZePSVmUH8X8Vhugz3bnE0/iE9Pb5fkWO9c4AnM1FgI/8Bvp27Fw2ShryIXuR6kKvUqhVMTuOSDHwu6A8jLE5Owt3GAYugDpDYuwTVNGrHLXKpPzrGGPE/jPmaLCMZcsdkec95dYeU3zKODEm8UQZFhmJmDeWVJ36nGrGZHL4J5aTTaeFUJmmXDaJYiJ+K2/ioKgXqnXvltu0A9R8/LGy4nrTJRr4JMLuJFoUXvGm1gXQ70w2LSpk6yl71RNC0hCtsBe8BP8IhYCM0EP5jh7eCMQZNvM= nocomment\n",
+		Title: "onboarding-key",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var key api.PublicKey
+	DecodeJSON(t, resp, &key)
+	unittest.AssertExistsAndLoadBean(t, &asymkey_model.PublicKey{ID: key.ID, OwnerID: 2})
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/users/user2/tokens?token="+token, &api.CreateAccessTokenOption{
+		Name:  "onboarding-token",
+		Scope: "package:read",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var accessToken api.AccessToken
+	DecodeJSON(t, resp, &accessToken)
+	assert.NotEmpty(t, accessToken.Token)
+	unittest.AssertExistsAndLoadBean(t, &auth_model.AccessToken{ID: accessToken.ID, UID: 2, Name: "onboarding-token"})
+
+	// failed operations are not recorded
+	session.MakeRequest(t, req, http.StatusBadRequest)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/users/user2/gpg_keys?token="+token, &api.CreateGPGKeyOption{
+		ArmoredKey: "not a key",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	unittest.AssertExistsAndLoadBean(t, &packages_model.PackageAuditLog{Scope: packages_model.AuditScopeAdmin, Action: packages_model.AuditActionProvisionSSHKey, ActorID: 1, OwnerID: 2})
+	unittest.AssertExistsAndLoadBean(t, &packages_model.PackageAuditLog{Scope: packages_model.AuditScopeAdmin, Action: packages_model.AuditActionProvisionToken, ActorID: 1, OwnerID: 2, Detail: "onboarding-token (package:read)"})
+
+	req = NewRequest(t, "GET", "/api/v1/admin/packages/audit?scope=admin&owner=user2&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+	var logs []*api.PackageAuditLog
+	DecodeJSON(t, resp, &logs)
+	if assert.Len(t, logs, 2) {
+		assert.Equal(t, "admin", logs[0].Scope)
+		assert.Equal(t, "provision_token", logs[0].Action)
+		assert.Equal(t, "user1", logs[0].Actor)
+		assert.Equal(t, "user2", logs[0].Owner)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/admin/packages/audit?scope=admin&action=provision_gpg_key&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &logs)
+	assert.Empty(t, logs)
+
+	// the package scope is listed by default
+	req = NewRequest(t, "GET", "/api/v1/admin/packages/audit?owner=user2&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &logs)
+	assert.Empty(t, logs)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/packages/audit?scope=unknown&token="+token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only site admins can provision credentials and read the audit log
+	userSession := loginUser(t, "user2")
+	userToken := getTokenForLoggedInUser(t, userSession)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/users/user4/tokens?token="+userToken, &api.CreateAccessTokenOption{Name: "foreign"})
+	userSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "GET", "/api/v1/admin/packages/audit?scope=admin&token="+userToken)
+	userSession.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminRecalculateRepoStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	token := getUserToken(t, "user1")
+	urlStr := "/api/v1/admin/repos/stats/recalculation?token=" + token
+
+	req := NewRequest(t, "GET", urlStr)
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "DELETE", urlStr)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// only site admins can recalculate the statistics
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/repos/stats/recalculation?token="+getUserToken(t, "user2"), &api.RecalculateRepoStatsOption{})
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.RecalculateRepoStatsOption{Kinds: []string{"counters", "unknown"}})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	_, err := db.GetEngine(db.DefaultContext).Exec("UPDATE `repository` SET num_stars = 42, num_issues = 42 WHERE id = ?", repo1.ID)
+	assert.NoError(t, err)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.RecalculateRepoStatsOption{Kinds: []string{"Counters"}})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var r api.RepoStatsRecalculation
+	DecodeJSON(t, resp, &r)
+	assert.Equal(t, []string{"counters"}, r.Kinds)
+	assert.EqualValues(t, unittest.GetCount(t, &repo_model.Repository{}), r.Total)
+
+	assert.Eventually(t, func() bool {
+		req := NewRequest(t, "GET", urlStr)
+		resp := MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &r)
+		return r.Status == "finished"
+	}, 30*time.Second, 100*time.Millisecond)
+	assert.Equal(t, r.Total, r.Processed)
+	assert.NotNil(t, r.Finished)
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo1.ID})
+	assert.Equal(t, repo1.NumStars, repo.NumStars)
+	assert.Equal(t, repo1.NumIssues, repo.NumIssues)
+
+	// nothing is running anymore
+	req = NewRequest(t, "DELETE", urlStr)
+	MakeRequest(t, req, http.StatusNotFound)
+}
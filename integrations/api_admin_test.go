@@ -7,9 +7,12 @@ package integrations
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/json"
@@ -132,6 +135,106 @@ func TestAPIListUsers(t *testing.T) {
 	assert.Equal(t, numberOfUsers, len(users))
 }
 
+func TestAPIListUsersFilters(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	listUsers := func(t *testing.T, query string) []api.User {
+		req := NewRequestf(t, "GET", "/api/v1/admin/users?limit=50&token=%s&%s", token, query)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var users []api.User
+		DecodeJSON(t, resp, &users)
+		return users
+	}
+
+	// user1 is the only user that has logged in
+	users := listUsers(t, "never_logged_in=false")
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "user1", users[0].UserName)
+	}
+	assert.Len(t, listUsers(t, "never_logged_in=true"), unittest.GetCount(t, &user_model.User{}, "type = 0")-1)
+
+	future := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+	assert.Len(t, listUsers(t, "last_login_before="+future), 1)
+	assert.Empty(t, listUsers(t, "last_login_before=2000-01-01T00:00:00Z"))
+
+	users = listUsers(t, "two_factor_enabled=true")
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "user24", users[0].UserName)
+	}
+
+	assert.Len(t, listUsers(t, "source_type=local"), unittest.GetCount(t, &user_model.User{}, "type = 0"))
+	assert.Empty(t, listUsers(t, "source_type=ldap"))
+
+	req := NewRequestf(t, "GET", "/api/v1/admin/users?token=%s&source_type=unknown", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestf(t, "GET", "/api/v1/admin/users?token=%s&last_login_before=yesterday", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPIBulkUsers(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/admin/users/bulk?token=%s", token)
+
+	bulk := func(t *testing.T, opts api.AdminBulkUsersOption) map[string]api.AdminBulkUserResult {
+		req := NewRequestWithJSON(t, "POST", urlStr, &opts)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var results []api.AdminBulkUserResult
+		DecodeJSON(t, resp, &results)
+		assert.Len(t, results, len(opts.Usernames))
+		byName := make(map[string]api.AdminBulkUserResult, len(results))
+		for _, result := range results {
+			byName[result.Username] = result
+		}
+		return byName
+	}
+
+	results := bulk(t, api.AdminBulkUsersOption{
+		Usernames: []string{"user8", "user1", "user-does-not-exist", "org3"},
+		Action:    "disable",
+	})
+	assert.True(t, results["user8"].Success)
+	assert.False(t, results["user1"].Success)
+	assert.False(t, results["user-does-not-exist"].Success)
+	assert.False(t, results["org3"].Success)
+	assert.NotEmpty(t, results["org3"].Error)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user8", ProhibitLogin: true})
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user1", ProhibitLogin: false})
+
+	results = bulk(t, api.AdminBulkUsersOption{
+		Usernames: []string{"user9"},
+		Action:    "force_password_reset",
+	})
+	assert.True(t, results["user9"].Success)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user9", MustChangePassword: true})
+
+	// user12 owns repositories and can only be deleted once they are transferred
+	results = bulk(t, api.AdminBulkUsersOption{
+		Usernames: []string{"user12"},
+		Action:    "delete",
+	})
+	assert.False(t, results["user12"].Success)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user12"})
+
+	results = bulk(t, api.AdminBulkUsersOption{
+		Usernames:       []string{"user12"},
+		Action:          "delete",
+		TransferReposTo: "user2",
+	})
+	assert.True(t, results["user12"].Success)
+	unittest.AssertNotExistsBean(t, &user_model.User{Name: "user12"})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 10, OwnerID: 2, OwnerName: "user2"})
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.AdminBulkUsersOption{
+		Usernames: []string{"user13"},
+		Action:    "unknown",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func TestAPIListUsersNotLoggedIn(t *testing.T) {
 	defer prepareTestEnv(t)()
 	req := NewRequest(t, "GET", "/api/v1/admin/users")
@@ -0,0 +1,67 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminTwoFactorRecovery(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	token := getUserToken(t, "user1")
+
+	// user2 is not enrolled in two-factor authentication
+	req := NewRequest(t, "POST", "/api/v1/admin/users/user2/2fa/recovery?token="+token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "POST", "/api/v1/admin/users/user24/2fa/recovery?token="+getUserToken(t, "user2"))
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "POST", "/api/v1/admin/users/user24/2fa/recovery?token="+token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var recovery *api.TwoFactorRecovery
+	DecodeJSON(t, resp, &recovery)
+	assert.NotEmpty(t, recovery.Code)
+
+	unittest.AssertExistsAndLoadBean(t, &packages_model.PackageAuditLog{Scope: packages_model.AuditScopeAdmin, Action: packages_model.AuditActionIssueTwoFactorRecovery, ActorID: 1, OwnerID: 24})
+
+	useCode := func(t *testing.T, code string, expectedStatus int) *TestSession {
+		session := loginUserWithPassword(t, "user24", userPassword)
+		req := NewRequestWithValues(t, "POST", "/user/two_factor/scratch", map[string]string{
+			"_csrf": GetCSRF(t, session, "/user/two_factor/scratch"),
+			"token": code,
+		})
+		resp := session.MakeRequest(t, req, expectedStatus)
+		if expectedStatus == http.StatusSeeOther {
+			assert.Equal(t, "/user/settings/security", test.RedirectURL(resp))
+		}
+		return session
+	}
+
+	// only security keys are allowed, which disables the TOTP passcodes but not the recovery code
+	assert.NoError(t, user_model.SetUserSetting(24, user_model.SettingsKeySecurityKeysOnly, "true"))
+
+	session := loginUserWithPassword(t, "user24", userPassword)
+	req = NewRequest(t, "GET", "/user/two_factor")
+	resp = session.MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, "/user/webauthn", test.RedirectURL(resp))
+
+	useCode(t, "WRONGCODE", http.StatusOK)
+	useCode(t, recovery.Code, http.StatusSeeOther)
+	unittest.AssertExistsAndLoadBean(t, &packages_model.PackageAuditLog{Scope: packages_model.AuditScopeAdmin, Action: packages_model.AuditActionUseTwoFactorRecovery, ActorID: 24, OwnerID: 24})
+
+	// the code can only be used once
+	useCode(t, recovery.Code, http.StatusOK)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminUsage(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	// no report was created yet
+	req := NewRequest(t, "GET", "/api/v1/admin/usage?token="+token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	_, err := activities_model.CreateUsageReport(db.DefaultContext)
+	assert.NoError(t, err)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/usage?token="+token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var report *api.UsageReport
+	DecodeJSON(t, resp, &report)
+	assert.NotNil(t, report.Storage)
+	assert.EqualValues(t, unittest.GetCount(t, &repo_model.Repository{}), report.Repositories)
+	assert.NotZero(t, report.Users)
+	assert.NotZero(t, report.Organizations)
+	assert.Len(t, report.Growth, 1)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/usage?since=2000-01-01&until=2000-01-31&token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &report)
+	assert.Empty(t, report.Growth)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/usage?since=invalid&token="+token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", "/api/v1/admin/usage?token="+token)
+	MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/models/webhook"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgDefaultHooks(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/hooks/defaults?token="+token, &api.CreateHookOption{
+		Type: "gitea",
+		Config: api.CreateHookOptionConfig{
+			"content_type": "json",
+			"url":          "http://example.com/default",
+		},
+		Events: []string{"push"},
+		Active: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var hook api.Hook
+	DecodeJSON(t, resp, &hook)
+	unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{ID: hook.ID, OrgID: 3, IsDefaultWebhook: true})
+
+	// the default hook is not listed or editable as a regular hook of the organization
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/hooks?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var hooks []*api.Hook
+	DecodeJSON(t, resp, &hooks)
+	for _, h := range hooks {
+		assert.NotEqual(t, hook.ID, h.ID)
+	}
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/user3/hooks/%d?token=%s", hook.ID, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/hooks/defaults?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &hooks)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, hook.ID, hooks[0].ID)
+	}
+
+	// new repositories get a copy
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+token, &api.CreateRepoOption{Name: "default-hook-repo"})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: repo.ID, DefaultWebhookID: hook.ID, URL: "http://example.com/default"})
+
+	// existing repositories get a copy when the hook is applied
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/user3/hooks/defaults/%d/apply?token=%s", hook.ID, token), &api.ApplyDefaultHookOption{
+		Pattern: "repo*",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var result api.ApplyDefaultHookResult
+	DecodeJSON(t, resp, &result)
+	assert.Equal(t, 3, result.Applied)
+	repo3 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user3", LowerName: "repo3"})
+	unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: repo3.ID, DefaultWebhookID: hook.ID})
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/user3/hooks/defaults/%d/apply?token=%s", hook.ID, token), &api.ApplyDefaultHookOption{})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &result)
+	assert.Equal(t, 0, result.Applied)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/hooks/defaults/%d?token=%s", hook.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &webhook.Webhook{ID: hook.ID})
+	// the copies are kept
+	unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: repo3.ID, DefaultWebhookID: hook.ID})
+}
+
+func TestAPIAdminDefaultHooks(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/admin/hooks/defaults?token="+token, &api.CreateHookOption{
+		Type: "gitea",
+		Config: api.CreateHookOptionConfig{
+			"content_type": "json",
+			"url":          "http://example.com/admin-default",
+		},
+		Active: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var hook api.Hook
+	DecodeJSON(t, resp, &hook)
+
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/hooks/defaults/%d?token=%s", hook.ID, token), &api.EditHookOption{
+		Events: []string{"push", "create"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &hook)
+	assert.ElementsMatch(t, []string{"push", "create"}, hook.Events)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/admin/hooks/defaults/%d/apply?token=%s", hook.ID, token), &api.ApplyDefaultHookOption{
+		Pattern: "user2/repo1",
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var result api.ApplyDefaultHookResult
+	DecodeJSON(t, resp, &result)
+	assert.Equal(t, 1, result.Applied)
+	unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: 1, DefaultWebhookID: hook.ID})
+
+	// only site admins can manage the admin-default hooks
+	userSession := loginUser(t, "user2")
+	userToken := getTokenForLoggedInUser(t, userSession)
+	req = NewRequest(t, "GET", "/api/v1/admin/hooks/defaults?token="+userToken)
+	userSession.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/hooks/defaults/%d?token=%s", hook.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/hooks/defaults/%d?token=%s", hook.ID, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -6,9 +6,15 @@ package integrations
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateForkNoLogin(t *testing.T) {
@@ -16,3 +22,79 @@ func TestCreateForkNoLogin(t *testing.T) {
 	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks", &api.CreateForkOption{})
 	MakeRequest(t, req, http.StatusUnauthorized)
 }
+
+func TestAPIForkNetwork(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token4 := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+		token5 := getTokenForLoggedInUser(t, loginUser(t, "user5"))
+
+		// user2/repo1 <- user4/repo1 <- user5/repo1
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token4, &api.CreateForkOption{})
+		MakeRequest(t, req, http.StatusAccepted)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user4/repo1/forks?token="+token5, &api.CreateForkOption{})
+		MakeRequest(t, req, http.StatusAccepted)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user5/repo1/forks/network?token="+token5)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var network api.ForkNetwork
+		DecodeJSON(t, resp, &network)
+		assert.Equal(t, "user2/repo1", network.Repository.FullName)
+		if assert.Len(t, network.Forks, 1) {
+			assert.Equal(t, "user4/repo1", network.Forks[0].Repository.FullName)
+			if assert.Len(t, network.Forks[0].Forks, 1) {
+				assert.Equal(t, "user5/repo1", network.Forks[0].Forks[0].Repository.FullName)
+				assert.Empty(t, network.Forks[0].Forks[0].Forks)
+			}
+		}
+
+		t.Run("Compare", func(t *testing.T) {
+			user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user5"})
+			fork := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user5", LowerName: "repo1"})
+			_, err := createFile(user5, fork, "fork-only.txt")
+			assert.NoError(t, err)
+
+			req := NewRequest(t, "GET", "/api/v1/repos/user5/repo1/fork/compare?token="+token5)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var comparison api.ForkComparison
+			DecodeJSON(t, resp, &comparison)
+			assert.Equal(t, "user2/repo1", comparison.Root.FullName)
+			assert.Equal(t, "master", comparison.Branch)
+			assert.Equal(t, "master", comparison.RootBranch)
+			assert.NotEmpty(t, comparison.MergeBase)
+			assert.Equal(t, 1, comparison.AheadBy)
+			assert.Equal(t, 0, comparison.BehindBy)
+
+			req = NewRequest(t, "GET", "/api/v1/repos/user5/repo1/fork/compare?branch=not-existing&token="+token5)
+			MakeRequest(t, req, http.StatusNotFound)
+			req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/fork/compare?token="+token5)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("Detach", func(t *testing.T) {
+			req := NewRequest(t, "POST", "/api/v1/repos/user5/repo1/fork/detach?token="+token4)
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequest(t, "POST", "/api/v1/repos/user4/repo1/fork/detach?token="+token4)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var repo api.Repository
+			DecodeJSON(t, resp, &repo)
+			assert.False(t, repo.Fork)
+			assert.Nil(t, repo.Parent)
+
+			req = NewRequest(t, "POST", "/api/v1/repos/user4/repo1/fork/detach?token="+token4)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			// the forks of the detached repository stay in its network
+			req = NewRequest(t, "GET", "/api/v1/repos/user5/repo1/forks/network?token="+token5)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &network)
+			assert.Equal(t, "user4/repo1", network.Repository.FullName)
+			assert.Len(t, network.Forks, 1)
+
+			req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/forks/network?token="+token5)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &network)
+			assert.Empty(t, network.Forks)
+		})
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueAttachments(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	_ = issue.LoadRepo(db.DefaultContext)
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.Repo.OwnerID})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/assets", owner.Name, issue.Repo.Name, issue.Index)
+
+	var uploaded api.Attachment
+	t.Run("Upload", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("attachment", "notes.txt")
+		_, _ = part.Write([]byte("some notes"))
+		_ = writer.Close()
+
+		req := NewRequestWithBody(t, "POST", urlStr+"?token="+token, body)
+		req.Header.Add("Content-Type", writer.FormDataContentType())
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		DecodeJSON(t, resp, &uploaded)
+		assert.Equal(t, "notes.txt", uploaded.Name)
+		assert.EqualValues(t, 10, uploaded.Size)
+
+		attach := unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{UUID: uploaded.UUID})
+		assert.Equal(t, issue.ID, attach.IssueID)
+		assert.EqualValues(t, 0, attach.CommentID)
+	})
+
+	t.Run("Paste", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "POST", urlStr+"/paste?token="+token, bytes.NewReader([]byte("pasted")))
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithBody(t, "POST", urlStr+"/paste?name=pasted.txt&token="+token, bytes.NewReader([]byte("pasted")))
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var pasted api.PastedAttachment
+		DecodeJSON(t, resp, &pasted)
+		assert.Equal(t, "pasted.txt", pasted.Attachment.Name)
+		assert.Equal(t, "[pasted.txt](/attachments/"+pasted.Attachment.UUID+")", pasted.Markdown)
+
+		req = NewRequestWithBody(t, "POST", urlStr+"/paste?name=forbidden.exe&token="+token, bytes.NewReader([]byte("pasted")))
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/files/report.txt" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("remote report"))
+		}))
+		defer server.Close()
+
+		req := NewRequestWithJSON(t, "POST", urlStr+"/url?token="+token, &api.CreateAttachmentFromURLOptions{
+			URL: server.URL + "/files/report.txt",
+		})
+		// loopback hosts are not allowed by default
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		defer func(allowList string) {
+			setting.Attachment.FetchAllowedHostList = allowList
+		}(setting.Attachment.FetchAllowedHostList)
+		setting.Attachment.FetchAllowedHostList = "loopback"
+
+		req = NewRequestWithJSON(t, "POST", urlStr+"/url?token="+token, &api.CreateAttachmentFromURLOptions{
+			URL: server.URL + "/files/report.txt",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var attachment api.Attachment
+		DecodeJSON(t, resp, &attachment)
+		assert.Equal(t, "report.txt", attachment.Name)
+		assert.EqualValues(t, 13, attachment.Size)
+
+		req = NewRequestWithJSON(t, "POST", urlStr+"/url?token="+token, &api.CreateAttachmentFromURLOptions{
+			URL: server.URL + "/files/missing.txt",
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", urlStr+"/url?token="+token, &api.CreateAttachmentFromURLOptions{
+			URL: "file:///etc/passwd",
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", urlStr+"?token="+token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var attachments []*api.Attachment
+		DecodeJSON(t, resp, &attachments)
+		// the fixture attachment and the uploaded, pasted and fetched ones
+		assert.Len(t, attachments, 4)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d?token=%s", urlStr, uploaded.ID, token))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var attachment api.Attachment
+		DecodeJSON(t, resp, &attachment)
+		assert.Equal(t, uploaded.UUID, attachment.UUID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		otherSession := loginUser(t, "user4")
+		otherToken := getTokenForLoggedInUser(t, otherSession)
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", urlStr, uploaded.ID, otherToken))
+		otherSession.MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", urlStr, uploaded.ID, token))
+		session.MakeRequest(t, req, http.StatusNoContent)
+		unittest.AssertNotExistsBean(t, &repo_model.Attachment{ID: uploaded.ID})
+	})
+}
+
+func TestAPIIssueCommentAttachments(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 2})
+	_ = comment.LoadIssue()
+	issue := comment.Issue
+	_ = issue.LoadRepo(db.DefaultContext)
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.Repo.OwnerID})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/comments/%d/assets", owner.Name, issue.Repo.Name, comment.ID)
+
+	req := NewRequestWithBody(t, "POST", urlStr+"/paste?name=image.png&token="+token, bytes.NewReader([]byte("\x89PNG\r\n\x1a\n")))
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var pasted api.PastedAttachment
+	DecodeJSON(t, resp, &pasted)
+	assert.Equal(t, "![image.png](/attachments/"+pasted.Attachment.UUID+")", pasted.Markdown)
+
+	attach := unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{UUID: pasted.Attachment.UUID})
+	assert.Equal(t, issue.ID, attach.IssueID)
+	assert.Equal(t, comment.ID, attach.CommentID)
+
+	req = NewRequest(t, "GET", urlStr+"?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var attachments []*api.Attachment
+	DecodeJSON(t, resp, &attachments)
+	found := false
+	for _, a := range attachments {
+		found = found || a.UUID == pasted.Attachment.UUID
+	}
+	assert.True(t, found)
+
+	// attachments of the comment are not attachments of the issue
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/assets/%d?token=%s", owner.Name, issue.Repo.Name, issue.Index, attach.ID, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", urlStr, attach.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueConfig(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+		urlConfig := "/api/v1/repos/user2/repo1/issue_config"
+		urlTemplates := "/api/v1/repos/user2/repo1/issue_templates"
+
+		t.Run("Default", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", urlConfig), http.StatusOK)
+
+			var config api.IssueConfig
+			DecodeJSON(t, resp, &config)
+			assert.True(t, config.BlankIssuesEnabled)
+			assert.Empty(t, config.ContactLinks)
+		})
+
+		for _, file := range []struct {
+			TreePath string
+			Content  string
+		}{
+			{".gitea/ISSUE_TEMPLATE/bug.md", "---\nname: Bug\nabout: Report a bug\n---\nBug"},
+			{".gitea/ISSUE_TEMPLATE/feature.md", "---\nname: Feature\nabout: Request a feature\n---\nFeature"},
+			{".gitea/ISSUE_TEMPLATE/config.yml", `blank_issues_enabled: false
+contact_links:
+  - name: Security issues
+    url: https://example.com/security
+    about: Please report security issues here
+order:
+  - feature.md
+`},
+		} {
+			_, err := createFileInBranch(user, repo, file.TreePath, repo.DefaultBranch, file.Content)
+			assert.NoError(t, err)
+		}
+
+		t.Run("Config", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", urlConfig), http.StatusOK)
+
+			var config api.IssueConfig
+			DecodeJSON(t, resp, &config)
+			assert.False(t, config.BlankIssuesEnabled)
+			assert.Equal(t, []api.IssueConfigContactLink{
+				{Name: "Security issues", URL: "https://example.com/security", About: "Please report security issues here"},
+			}, config.ContactLinks)
+			assert.Equal(t, []string{"feature.md"}, config.Order)
+		})
+
+		t.Run("TemplateOrder", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", urlTemplates), http.StatusOK)
+
+			var templates []api.IssueTemplate
+			DecodeJSON(t, resp, &templates)
+			assert.Len(t, templates, 2)
+			assert.Equal(t, "feature.md", templates[0].FileName)
+			assert.Equal(t, "bug.md", templates[1].FileName)
+		})
+
+		t.Run("BlankIssuesDisabled", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			session := loginUser(t, user.Name)
+
+			resp := session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/issues/new"), http.StatusSeeOther)
+			assert.Contains(t, resp.Header().Get("Location"), "/user2/repo1/issues/new/choose")
+
+			session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/issues/new?template=bug.md"), http.StatusOK)
+
+			resp = session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/issues/new/choose"), http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "https://example.com/security")
+		})
+	})
+}
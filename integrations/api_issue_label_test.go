@@ -111,6 +111,50 @@ func TestAPIAddIssueLabels(t *testing.T) {
 	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: 2})
 }
 
+func TestAPIAddIssueScopedLabels(t *testing.T) {
+	assert.NoError(t, unittest.LoadFixtures())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	createLabel := func(name string) *api.Label {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/labels?token=%s", owner.Name, repo.Name, token), &api.CreateLabelOption{
+			Name:  name,
+			Color: "#00aabb",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var apiLabel *api.Label
+		DecodeJSON(t, resp, &apiLabel)
+		return apiLabel
+	}
+	high := createLabel("priority::high")
+	low := createLabel("priority::low")
+	assert.Equal(t, "priority", high.Scope)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/labels?token=%s", owner.Name, repo.Name, issue.Index, token)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.IssueLabelsOption{Labels: []int64{high.ID}})
+	session.MakeRequest(t, req, http.StatusOK)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.IssueLabelsOption{Labels: []int64{low.ID}})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiLabels []*api.Label
+	DecodeJSON(t, resp, &apiLabels)
+	for _, label := range apiLabels {
+		assert.NotEqual(t, high.ID, label.ID)
+	}
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: low.ID})
+
+	req = NewRequestWithJSON(t, "PUT", urlStr, &api.IssueLabelsOption{Labels: []int64{high.ID, low.ID}})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func TestAPIReplaceIssueLabels(t *testing.T) {
 	assert.NoError(t, unittest.LoadFixtures())
 
@@ -0,0 +1,145 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueModeration(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: comment.IssueID})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	token := getUserToken(t, owner.Name)
+	readerToken := getUserToken(t, "user5")
+
+	commentURL := fmt.Sprintf("/api/v1/repos/%s/%s/issues/comments/%d", owner.Name, repo.Name, comment.ID)
+	lockURL := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/lock", owner.Name, repo.Name, issue.Index)
+
+	t.Run("Revisions", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", commentURL+"?token="+token, &api.EditIssueCommentOption{
+			Body: "edited by a moderator",
+		})
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", commentURL+"/revisions")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var revisions []*api.CommentRevision
+		DecodeJSON(t, resp, &revisions)
+		assert.Len(t, revisions, 2)
+		assert.True(t, revisions[0].IsFirst)
+		assert.Equal(t, "good work!", revisions[0].Body)
+		assert.EqualValues(t, comment.PosterID, revisions[0].Editor.ID)
+		assert.False(t, revisions[1].IsFirst)
+		assert.Equal(t, "edited by a moderator", revisions[1].Body)
+		assert.Equal(t, owner.ID, revisions[1].Editor.ID)
+	})
+
+	t.Run("Hide", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", commentURL+"/hide?token="+readerToken, &api.HideIssueCommentOption{Reason: "spam"})
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "PUT", commentURL+"/hide?token="+token, &api.HideIssueCommentOption{Reason: "unknown"})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", commentURL+"/hide?token="+token, &api.HideIssueCommentOption{Reason: "spam"})
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var apiComment *api.Comment
+		DecodeJSON(t, resp, &apiComment)
+		assert.True(t, apiComment.Hidden)
+		assert.Equal(t, "spam", apiComment.HiddenReason)
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: comment.ID, HiddenByID: owner.ID, HiddenReason: "spam"})
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/issues/%d", owner.Name, repo.Name, issue.Index))
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "hidden-comment")
+
+		req = NewRequest(t, "DELETE", commentURL+"/hide?token="+token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", commentURL)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &apiComment)
+		assert.False(t, apiComment.Hidden)
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", lockURL+"?token="+readerToken, &api.LockIssueOption{})
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "PUT", lockURL+"?token="+token, &api.LockIssueOption{Duration: "soon"})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", lockURL+"?token="+token, &api.LockIssueOption{Reason: "Too heated", Duration: "24h"})
+		MakeRequest(t, req, http.StatusNoContent)
+
+		locked := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID})
+		assert.True(t, locked.IsLocked)
+		assert.NotZero(t, locked.LockedUntilUnix)
+
+		req = NewRequestWithJSON(t, "PUT", lockURL+"?token="+token, &api.LockIssueOption{})
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "DELETE", lockURL+"?token="+token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		locked = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID})
+		assert.False(t, locked.IsLocked)
+		assert.Zero(t, locked.LockedUntilUnix)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		logURL := fmt.Sprintf("/api/v1/repos/%s/%s/moderation/log", owner.Name, repo.Name)
+
+		req := NewRequest(t, "GET", logURL+"?token="+readerToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "GET", logURL+"?token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var logs []*api.ModerationLog
+		DecodeJSON(t, resp, &logs)
+		assert.Len(t, logs, 5)
+		for i, action := range []string{"unlock", "lock", "unhide_comment", "hide_comment", "edit_comment"} {
+			assert.Equal(t, action, logs[i].Action)
+			assert.Equal(t, owner.ID, logs[i].Doer.ID)
+			assert.Equal(t, issue.Index, logs[i].IssueIndex)
+		}
+		assert.Equal(t, "Too heated", logs[1].Reason)
+		assert.Equal(t, "spam", logs[3].Reason)
+		assert.Equal(t, comment.ID, logs[3].CommentID)
+
+		req = NewRequest(t, "GET", logURL+"?action=lock&token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &logs)
+		assert.Len(t, logs, 1)
+
+		req = NewRequest(t, "GET", logURL+"?action=unknown&token="+token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReactionAndCommentWebhookPayload(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/hooks?token="+token, &api.CreateHookOption{
+			Type: "gitea",
+			Config: api.CreateHookOptionConfig{
+				"content_type": "json",
+				"url":          "http://example.com/",
+			},
+			Events: []string{"reaction", "issue_comment_edited"},
+			Active: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var hook api.Hook
+		DecodeJSON(t, resp, &hook)
+		assert.Contains(t, hook.Events, "reaction")
+		assert.Contains(t, hook.Events, "issue_comment")
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/reactions?token="+token, &api.EditReactionOption{Reaction: "heart"})
+		session.MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/comments/2/reactions?token="+token, &api.EditReactionOption{Reaction: "rocket"})
+		session.MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/user2/repo1/issues/1/reactions?token="+token, &api.EditReactionOption{Reaction: "heart"})
+		session.MakeRequest(t, req, http.StatusOK)
+		// removing a reaction which doesn't exist sends no event
+		req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/user2/repo1/issues/1/reactions?token="+token, &api.EditReactionOption{Reaction: "hooray"})
+		session.MakeRequest(t, req, http.StatusOK)
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/repo1/issues/comments/%d?token=%s", 2, token), &api.EditIssueCommentOption{Body: "good work, edited"})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		hookTasks, err := webhook.HookTasks(hook.ID, 1)
+		assert.NoError(t, err)
+		assert.Len(t, hookTasks, 4)
+
+		decodeReaction := func(t *testing.T, task *webhook.HookTask) *api.ReactionPayload {
+			assert.Equal(t, webhook.HookEventReaction, task.EventType)
+			var payload api.ReactionPayload
+			assert.NoError(t, json.Unmarshal([]byte(task.PayloadContent), &payload))
+			return &payload
+		}
+
+		issueReaction := decodeReaction(t, hookTasks[3])
+		assert.Equal(t, api.HookReactionCreated, issueReaction.Action)
+		assert.Equal(t, "heart", issueReaction.Reaction.Reaction)
+		assert.EqualValues(t, 1, issueReaction.Issue.Index)
+		assert.Nil(t, issueReaction.Comment)
+		assert.Equal(t, "user2", issueReaction.Sender.UserName)
+
+		commentReaction := decodeReaction(t, hookTasks[2])
+		assert.Equal(t, api.HookReactionCreated, commentReaction.Action)
+		assert.Equal(t, "rocket", commentReaction.Reaction.Reaction)
+		if assert.NotNil(t, commentReaction.Comment) {
+			assert.EqualValues(t, 2, commentReaction.Comment.ID)
+		}
+
+		deleted := decodeReaction(t, hookTasks[1])
+		assert.Equal(t, api.HookReactionDeleted, deleted.Action)
+		assert.Equal(t, "heart", deleted.Reaction.Reaction)
+
+		assert.Equal(t, webhook.HookEventIssueCommentEdited, hookTasks[0].EventType)
+		var edited api.IssueCommentPayload
+		assert.NoError(t, json.Unmarshal([]byte(hookTasks[0].PayloadContent), &edited))
+		assert.Equal(t, api.HookIssueCommentEdited, edited.Action)
+		assert.Equal(t, "good work!", edited.Changes.Body.From)
+		assert.Equal(t, "good work, edited", edited.Comment.Body)
+	})
+}
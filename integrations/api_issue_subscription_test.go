@@ -74,3 +74,51 @@ func TestAPIIssueSubscriptions(t *testing.T) {
 	session.MakeRequest(t, req, http.StatusOK)
 	testSubscription(issue5, true)
 }
+
+func TestAPIIssueSubscriptionReasons(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	urlFormat := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/subscriptions/%%s?token=%s", repo.OwnerName, repo.Name, issue.Index, token)
+
+	testReason := func(userName, reason string) {
+		req := NewRequest(t, "GET", fmt.Sprintf(urlFormat, userName))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		wi := new(api.WatchInfo)
+		DecodeJSON(t, resp, wi)
+
+		assert.Equal(t, reason != "", wi.Subscribed)
+		if reason == "" {
+			assert.Nil(t, wi.Reason)
+		} else {
+			assert.EqualValues(t, reason, wi.Reason)
+		}
+	}
+
+	testReason("user1", "author")
+	testReason("user2", "assigned")
+	testReason("user4", "")
+
+	// user4 is a member of a team of the repository
+	req := NewRequest(t, "PUT", fmt.Sprintf(urlFormat, "user4"))
+	session.MakeRequest(t, req, http.StatusCreated)
+	testReason("user4", "team")
+
+	// user5 is no member of the organization
+	req = NewRequest(t, "PUT", fmt.Sprintf(urlFormat, "user5"))
+	session.MakeRequest(t, req, http.StatusForbidden)
+	testReason("user5", "")
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf(urlFormat, "user2"))
+	session.MakeRequest(t, req, http.StatusCreated)
+	testReason("user2", "")
+
+	req = NewRequest(t, "PUT", fmt.Sprintf(urlFormat, "user2"))
+	session.MakeRequest(t, req, http.StatusCreated)
+	testReason("user2", "manual")
+}
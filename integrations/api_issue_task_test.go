@@ -0,0 +1,118 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueTasks(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	token := getUserToken(t, owner.Name)
+
+	body := "Checklist:\n\n- [ ] first\n- [x] second\n\n```\n- [ ] not a task\n```\n"
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues?token=%s", owner.Name, repo.Name, token), &api.CreateIssueOption{
+		Title: "issue with tasks",
+		Body:  body,
+	})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.Equal(t, 2, apiIssue.TaskProgress.Total)
+	assert.Equal(t, 1, apiIssue.TaskProgress.Completed)
+
+	checked, unchecked := true, false
+	first, second := "first", "second"
+
+	tasksURL := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/tasks", owner.Name, repo.Name, apiIssue.Index)
+
+	t.Run("List", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", tasksURL)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var tasks []*api.IssueTask
+		DecodeJSON(t, resp, &tasks)
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, 0, tasks[0].Index)
+		assert.Equal(t, "first", tasks[0].Text)
+		assert.False(t, tasks[0].Checked)
+		assert.Equal(t, 1, tasks[1].Index)
+		assert.Equal(t, "second", tasks[1].Text)
+		assert.True(t, tasks[1].Checked)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/0?token=%s", tasksURL, token), &api.EditIssueTaskOption{
+			Checked: &checked,
+			Text:    &first,
+		})
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var task *api.IssueTask
+		DecodeJSON(t, resp, &task)
+		assert.Equal(t, 0, task.Index)
+		assert.True(t, task.Checked)
+
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: apiIssue.ID})
+		assert.Equal(t, "Checklist:\n\n- [x] first\n- [x] second\n\n```\n- [ ] not a task\n```\n", issue.Content)
+
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/1?token=%s", tasksURL, token), &api.EditIssueTaskOption{
+			Checked: &unchecked,
+		})
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", owner.Name, repo.Name, apiIssue.Index))
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &apiIssue)
+		assert.Equal(t, 2, apiIssue.TaskProgress.Total)
+		assert.Equal(t, 1, apiIssue.TaskProgress.Completed)
+	})
+
+	t.Run("TextMismatch", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/0?token=%s", tasksURL, token), &api.EditIssueTaskOption{
+			Checked: &unchecked,
+			Text:    &second,
+		})
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("NotExist", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/2?token=%s", tasksURL, token), &api.EditIssueTaskOption{
+			Checked: &checked,
+		})
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NoPermission", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/0?token=%s", tasksURL, getUserToken(t, "user5")), &api.EditIssueTaskOption{
+			Checked: &unchecked,
+		})
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
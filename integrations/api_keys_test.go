@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/perm"
@@ -98,6 +99,41 @@ func TestCreateReadWriteDeployKey(t *testing.T) {
 	})
 }
 
+func TestCreateExpiringDeployKey(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{Name: "repo1"})
+	repoOwner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+
+	session := loginUser(t, repoOwner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	keysURL := fmt.Sprintf("/api/v1/repos/%s/%s/keys?token=%s", repoOwner.Name, repo.Name, token)
+
+	past := time.Now().Add(-time.Hour)
+	rawKeyBody := api.CreateKeyOption{
+		Title:     "expiring",
+		Key:       "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC4cn+iXnA4KvcQYSV88vGn0Yi91vG47t1P7okprVmhNTkipNRIHWr6WdCO4VDr/cvsRkuVJAsLO2enwjGWWueOO6BodiBgyAOZ/5t5nJNMCNuLGT5UIo/RI1b0WRQwxEZTRjt6mFNw6lH14wRd8ulsr9toSWBPMOGWoYs1PDeDL0JuTjL+tr1SZi/EyxCngpYszKdXllJEHyI79KQgeD0Vt3pTrkbNVTOEcCNqZePSVmUH8X8Vhugz3bnE0/iE9Pb5fkWO9c4AnM1FgI/8Bvp27Fw2ShryIXuR6kKvUqhVMTuOSDHwu6A8jLE5Owt3GAYugDpDYuwTVNGrHLXKpPzrGGPE/jPmaLCMZcsdkec95dYeU3zKODEm8UQZFhmJmDeWVJ36nGrGZHL4J5aTTaeFUJmmXDaJYiJ+K2/ioKgXqnXvltu0A9R8/LGy4nrTJRr4JMLuJFoUXvGm1gXQ70w2LSpk6yl71RNC0hCtsBe8BP8IhYCM0EP5jh7eCMQZNvM= nocomment\n",
+		ReadOnly:  true,
+		ExpiresAt: &past,
+	}
+	req := NewRequestWithJSON(t, "POST", keysURL, rawKeyBody)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	rawKeyBody.ExpiresAt = &expires
+	req = NewRequestWithJSON(t, "POST", keysURL, rawKeyBody)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+
+	var newDeployKey api.DeployKey
+	DecodeJSON(t, resp, &newDeployKey)
+	assert.NotNil(t, newDeployKey.ExpiresAt)
+	assert.Equal(t, expires.Unix(), newDeployKey.ExpiresAt.Unix())
+	assert.Nil(t, newDeployKey.LastUsed)
+
+	key := unittest.AssertExistsAndLoadBean(t, &asymkey_model.DeployKey{ID: newDeployKey.ID})
+	assert.EqualValues(t, expires.Unix(), key.ExpiresUnix)
+	assert.False(t, key.IsExpired())
+}
+
 func TestCreateUserKey(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user1"})
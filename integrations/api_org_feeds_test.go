@@ -0,0 +1,91 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgActivityFeeds(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	url := "/api/v1/orgs/" + org.Name + "/activities/feeds"
+
+	t.Run("Anonymous", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", url)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var feeds []*api.Activity
+		DecodeJSON(t, resp, &feeds)
+		assert.Empty(t, feeds)
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", url+"?token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+		var feeds []*api.Activity
+		DecodeJSON(t, resp, &feeds)
+		if assert.Len(t, feeds, 1) {
+			assert.EqualValues(t, 2, feeds[0].ID)
+			assert.Equal(t, "rename_repo", feeds[0].OpType)
+			assert.EqualValues(t, user.ID, feeds[0].ActUserID)
+			assert.EqualValues(t, 3, feeds[0].RepoID)
+		}
+	})
+
+	t.Run("TypeFilter", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", url+"?op_type=create_repo&token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var feeds []*api.Activity
+		DecodeJSON(t, resp, &feeds)
+		assert.Empty(t, feeds)
+
+		req = NewRequest(t, "GET", url+"?op_type=invalid&token="+token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestAPIOrgNotifications(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	url := "/api/v1/orgs/" + org.Name + "/notifications"
+
+	req := NewRequest(t, "GET", url)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// the notifications of user2 all belong to repositories of user2
+	req = NewRequest(t, "GET", url+"?all=true&token="+token)
+	resp := MakeRequest(t, req, http.StatusOK)
+
+	var threads []*api.NotificationThread
+	DecodeJSON(t, resp, &threads)
+	assert.Empty(t, threads)
+}
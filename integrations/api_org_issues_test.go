@@ -0,0 +1,90 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgListIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	token := getUserToken(t, "user2")
+
+	listIssues := func(t *testing.T, query string) []*api.Issue {
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/%s/issues?state=all&%s", org.Name, query))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var apiIssues []*api.Issue
+		DecodeJSON(t, resp, &apiIssues)
+		return apiIssues
+	}
+
+	t.Run("Member", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		apiIssues := listIssues(t, "token="+token)
+		assert.NotEmpty(t, apiIssues)
+
+		hasPrivate := false
+		for _, issue := range apiIssues {
+			assert.Equal(t, org.Name, issue.Repo.Owner)
+			repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.Repo.ID})
+			hasPrivate = hasPrivate || repo.IsPrivate
+		}
+		assert.True(t, hasPrivate)
+
+		for _, issue := range listIssues(t, "type=pulls&token="+token) {
+			assert.NotNil(t, issue.PullRequest)
+		}
+		for _, issue := range listIssues(t, "type=issues&token="+token) {
+			assert.Nil(t, issue.PullRequest)
+		}
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		for _, issue := range listIssues(t, "") {
+			assert.Equal(t, org.Name, issue.Repo.Owner)
+			repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.Repo.ID})
+			assert.False(t, repo.IsPrivate)
+		}
+	})
+}
+
+func TestAPIOrgListPullRequests(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	token := getUserToken(t, "user2")
+
+	req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/%s/pulls?state=all&token=%s", org.Name, token))
+	resp := MakeRequest(t, req, http.StatusOK)
+
+	var apiPulls []*api.PullRequest
+	DecodeJSON(t, resp, &apiPulls)
+	assert.NotEmpty(t, apiPulls)
+	for _, pr := range apiPulls {
+		assert.Equal(t, org.Name, pr.Base.Repository.Owner.UserName)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/%s/pulls?state=all", org.Name))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiPulls)
+	for _, pr := range apiPulls {
+		assert.False(t, pr.Base.Repository.Private)
+	}
+}
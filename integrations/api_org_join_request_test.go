@@ -0,0 +1,126 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgJoinRequest(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	ownerToken := getUserToken(t, "user2")
+	requesterToken := getUserToken(t, "user5")
+
+	baseURL := "/api/v1/orgs/user3/join_requests"
+
+	createRequest := func(t *testing.T, team string, expectedStatus int) *api.OrgJoinRequest {
+		req := NewRequestWithJSON(t, "POST", baseURL+"?token="+requesterToken, &api.CreateOrgJoinRequestOption{
+			Team:    team,
+			Message: "I would like to help",
+		})
+		resp := MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusCreated {
+			return nil
+		}
+
+		var jr *api.OrgJoinRequest
+		DecodeJSON(t, resp, &jr)
+		return jr
+	}
+
+	t.Run("RejectAndWithdraw", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		jr := createRequest(t, "", http.StatusCreated)
+		assert.Nil(t, jr.Team)
+
+		url := fmt.Sprintf("%s/%d", baseURL, jr.ID)
+
+		// a team must be chosen for requests without team
+		req := NewRequestWithJSON(t, "POST", url+"/approve?token="+ownerToken, &api.ApproveOrgJoinRequestOption{})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "POST", url+"/reject?token="+ownerToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &jr)
+		assert.Equal(t, "rejected", jr.Status)
+
+		jr = createRequest(t, "", http.StatusCreated)
+		url = fmt.Sprintf("%s/%d", baseURL, jr.ID)
+
+		// other users can't see the request
+		req = NewRequest(t, "GET", url+"?token="+getUserToken(t, "user4"))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", url+"?token="+requesterToken)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "DELETE", url+"?token="+requesterToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", url+"?token="+requesterToken)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Approve", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		jr := createRequest(t, "team1", http.StatusCreated)
+		assert.Equal(t, "user5", jr.User.UserName)
+		assert.Equal(t, "user3", jr.Organization.UserName)
+		assert.Equal(t, "team1", jr.Team.Name)
+		assert.Equal(t, "pending", jr.Status)
+
+		createRequest(t, "team1", http.StatusConflict)
+		createRequest(t, "unknown", http.StatusUnprocessableEntity)
+
+		// only owners can see the queue
+		req := NewRequest(t, "GET", baseURL+"?token="+requesterToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "GET", baseURL+"?status=pending&token="+ownerToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var jrs []*api.OrgJoinRequest
+		DecodeJSON(t, resp, &jrs)
+		assert.Len(t, jrs, 1)
+		assert.Equal(t, jr.ID, jrs[0].ID)
+
+		url := fmt.Sprintf("%s/%d", baseURL, jr.ID)
+
+		req = NewRequestWithJSON(t, "POST", url+"/approve?token="+requesterToken, &api.ApproveOrgJoinRequestOption{})
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "POST", url+"/approve?token="+ownerToken, &api.ApproveOrgJoinRequestOption{})
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &jr)
+		assert.Equal(t, "approved", jr.Status)
+		assert.Equal(t, "user2", jr.Reviewer.UserName)
+
+		isMember, err := organization.IsTeamMember(db.DefaultContext, 3, 2, 5)
+		assert.NoError(t, err)
+		assert.True(t, isMember)
+
+		req = NewRequest(t, "POST", url+"/reject?token="+ownerToken)
+		MakeRequest(t, req, http.StatusConflict)
+
+		// user5 is a member of team1 now
+		createRequest(t, "team1", http.StatusConflict)
+
+		req = NewRequest(t, "GET", "/api/v1/user/join_requests?status=approved&token="+requesterToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &jrs)
+		assert.Len(t, jrs, 1)
+		assert.Equal(t, jr.ID, jrs[0].ID)
+	})
+}
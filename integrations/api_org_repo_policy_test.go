@@ -0,0 +1,114 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgRepoPolicy(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2")
+		memberToken := getUserToken(t, "user4")
+
+		policyURL := "/api/v1/orgs/org3/repo_policy?token="
+		createURL := "/api/v1/orgs/org3/repos?token=" + token
+
+		t.Run("Set", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", policyURL+token)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequestWithJSON(t, "PUT", policyURL+memberToken, &api.SetRepoPolicyOption{RequireTopic: true})
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequestWithJSON(t, "PUT", policyURL+token, &api.SetRepoPolicyOption{NamePattern: "svc-("})
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			req = NewRequestWithJSON(t, "PUT", policyURL+token, &api.SetRepoPolicyOption{RequiredProperties: []string{"cost-center"}})
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/properties?token="+token, &api.CreatePropertyDefinitionOption{Name: "cost-center"})
+			MakeRequest(t, req, http.StatusCreated)
+
+			req = NewRequestWithJSON(t, "PUT", policyURL+token, &api.SetRepoPolicyOption{
+				NamePattern:            "svc-[a-z]+",
+				NamePatternDescription: "names of services start with svc-",
+				RequireTopic:           true,
+				RequiredProperties:     []string{"Cost-Center", "cost-center"},
+			})
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "GET", policyURL+memberToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var policy api.RepoPolicy
+			DecodeJSON(t, resp, &policy)
+			assert.Equal(t, "svc-[a-z]+", policy.NamePattern)
+			assert.True(t, policy.RequireTopic)
+			assert.Equal(t, []string{"cost-center"}, policy.RequiredProperties)
+		})
+
+		t.Run("CreateRepo", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			for _, tc := range []struct {
+				opt     *api.CreateRepoOption
+				message string
+			}{
+				{
+					opt:     &api.CreateRepoOption{Name: "svc-api-2", Topics: []string{"go"}, Properties: map[string]string{"cost-center": "42"}},
+					message: "names of services start with svc-",
+				},
+				{
+					opt:     &api.CreateRepoOption{Name: "svc-api", Properties: map[string]string{"cost-center": "42"}},
+					message: "at least one topic",
+				},
+				{
+					opt:     &api.CreateRepoOption{Name: "svc-api", Topics: []string{"go"}},
+					message: "custom property cost-center",
+				},
+			} {
+				req := NewRequestWithJSON(t, "POST", createURL, tc.opt)
+				resp := MakeRequest(t, req, http.StatusUnprocessableEntity)
+				errMap := make(map[string]interface{})
+				DecodeJSON(t, resp, &errMap)
+				assert.Contains(t, errMap["message"], tc.message)
+			}
+
+			req := NewRequestWithJSON(t, "POST", createURL, &api.CreateRepoOption{Name: "svc-api", Topics: []string{"Go", "infra"}, Properties: map[string]string{"cost-center": "42"}})
+			MakeRequest(t, req, http.StatusCreated)
+
+			repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "org3", LowerName: "svc-api"})
+			assert.ElementsMatch(t, []string{"go", "infra"}, repo.Topics)
+			props, err := repo_model.GetRepoProperties(db.DefaultContext, repo.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{"cost-center": "42"}, props)
+
+			// repositories of users are not affected
+			req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos?token="+token, &api.CreateRepoOption{Name: "any-name"})
+			MakeRequest(t, req, http.StatusCreated)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "DELETE", policyURL+token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequestWithJSON(t, "POST", createURL, &api.CreateRepoOption{Name: "any-name"})
+			MakeRequest(t, req, http.StatusCreated)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgServiceAccounts(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user2 is an owner of user3
+	token := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+	accountsURL := "/api/v1/orgs/user3/service_accounts"
+
+	req := NewRequestWithJSON(t, "POST", accountsURL+"?token="+token, &api.CreateServiceAccountOption{
+		Username: "ci-bot",
+		FullName: "CI",
+	})
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var account *api.ServiceAccount
+	DecodeJSON(t, resp, &account)
+	assert.Equal(t, "ci-bot", account.UserName)
+	assert.False(t, account.Disabled)
+
+	bot := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: account.ID, Type: user_model.UserTypeBot})
+	unittest.AssertExistsAndLoadBean(t, &organization.ServiceAccount{OrgID: 3, UserID: bot.ID})
+
+	req = NewRequest(t, "GET", "/api/v1/users/ci-bot")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiUser *api.User
+	DecodeJSON(t, resp, &apiUser)
+	assert.True(t, apiUser.IsServiceAccount)
+
+	// a service account can't sign in with a password
+	req = NewRequest(t, "GET", "/api/v1/user")
+	req.SetBasicAuth("ci-bot", "password")
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// only owners manage service accounts
+	req = NewRequest(t, "GET", accountsURL+"?token="+getTokenForLoggedInUser(t, loginUser(t, "user4")))
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "GET", accountsURL+"?token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var accounts []*api.ServiceAccount
+	DecodeJSON(t, resp, &accounts)
+	assert.Len(t, accounts, 1)
+
+	req = NewRequestWithJSON(t, "POST", accountsURL+"/ci-bot/tokens?token="+token, &api.CreateAccessTokenOption{Name: "deploy"})
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var botToken *api.AccessToken
+	DecodeJSON(t, resp, &botToken)
+
+	req = NewRequest(t, "GET", "/api/v1/user?token="+botToken.Token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiUser)
+	assert.Equal(t, "ci-bot", apiUser.UserName)
+
+	t.Run("RotateToken", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "POST", fmt.Sprintf("%s/ci-bot/tokens/%d/rotate?token=%s", accountsURL, botToken.ID, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+		var rotated *api.AccessToken
+		DecodeJSON(t, resp, &rotated)
+		assert.Equal(t, botToken.ID, rotated.ID)
+		assert.Equal(t, "deploy", rotated.Name)
+		assert.NotEqual(t, botToken.Token, rotated.Token)
+
+		req = NewRequest(t, "GET", "/api/v1/user?token="+botToken.Token)
+		MakeRequest(t, req, http.StatusUnauthorized)
+		req = NewRequest(t, "GET", "/api/v1/user?token="+rotated.Token)
+		MakeRequest(t, req, http.StatusOK)
+		botToken = rotated
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		disabled := true
+		req := NewRequestWithJSON(t, "PATCH", accountsURL+"/ci-bot?token="+token, &api.EditServiceAccountOption{Disabled: &disabled})
+		resp := MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &account)
+		assert.True(t, account.Disabled)
+
+		req = NewRequest(t, "GET", "/api/v1/user?token="+botToken.Token)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", accountsURL+"/ci-bot?token="+token)
+		MakeRequest(t, req, http.StatusNoContent)
+		unittest.AssertNotExistsBean(t, &user_model.User{ID: bot.ID})
+		unittest.AssertNotExistsBean(t, &organization.ServiceAccount{UserID: bot.ID})
+
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
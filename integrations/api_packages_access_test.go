@@ -0,0 +1,167 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAccess(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	uploadPackage := func(t *testing.T, owner, name string) string {
+		url := fmt.Sprintf("/api/packages/%s/generic/%s/1.0.0/file.bin", owner, name)
+		req := NewRequestWithBody(t, "PUT", url, bytes.NewReader([]byte{1, 2, 3}))
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+		return url
+	}
+
+	setVisibility := func(t *testing.T, owner, name, visibility string) {
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/packages/%s/generic/%s/access?token=%s", owner, name, token), &api.EditPackageAccessOption{
+			Visibility: visibility,
+		})
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var access *api.PackageAccess
+		DecodeJSON(t, resp, &access)
+		assert.Equal(t, visibility, access.Visibility)
+	}
+
+	download := func(t *testing.T, url, username string, expectedStatus int) {
+		req := NewRequest(t, "GET", url)
+		if username != "" {
+			AddBasicAuthHeader(req, username)
+		}
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	t.Run("PrivatePackage", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		url := uploadPackage(t, user.Name, "private-package")
+		download(t, url, "", http.StatusOK)
+
+		setVisibility(t, user.Name, "private-package", "private")
+
+		download(t, url, "", http.StatusUnauthorized)
+		download(t, url, "user4", http.StatusUnauthorized)
+		download(t, url, user.Name, http.StatusOK)
+
+		listPackages := func(t *testing.T, username string) []*api.Package {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s?type=generic&token=%s", user.Name, getUserToken(t, username)))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var apiPackages []*api.Package
+			DecodeJSON(t, resp, &apiPackages)
+			return apiPackages
+		}
+		assert.Empty(t, listPackages(t, "user4"))
+		assert.Len(t, listPackages(t, user.Name), 1)
+
+		// teams can only be granted access to packages of organizations
+		req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/packages/%s/generic/private-package/access/teams/Owners?token=%s", user.Name, token), &api.PackageTeamAccessOption{
+			Permission: "read",
+		})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// only the owner can change the access settings
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/packages/%s/generic/private-package/access?token=%s", user.Name, getUserToken(t, "user4")), &api.EditPackageAccessOption{
+			Visibility: "public",
+		})
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("PublicPackageOfPrivateOrg", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		url := uploadPackage(t, org.Name, "public-package")
+
+		org.Visibility = api.VisibleTypePrivate
+		assert.NoError(t, user_model.UpdateUserCols(db.DefaultContext, org, "visibility"))
+		defer func() {
+			org.Visibility = api.VisibleTypePublic
+			assert.NoError(t, user_model.UpdateUserCols(db.DefaultContext, org, "visibility"))
+		}()
+
+		download(t, url, "", http.StatusUnauthorized)
+
+		setVisibility(t, org.Name, "public-package", "public")
+
+		download(t, url, "", http.StatusOK)
+		download(t, url, "user5", http.StatusOK)
+
+		// the package is readable but other packages of the org are not
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/public-package/1.0.1/file.bin", org.Name), bytes.NewReader([]byte{1, 2, 3}))
+		AddBasicAuthHeader(req, "user5")
+		MakeRequest(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("TeamAccess", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		team := &organization.Team{
+			OrgID:      org.ID,
+			Name:       "package-team",
+			AccessMode: perm.AccessModeRead,
+		}
+		assert.NoError(t, models.NewTeam(team))
+		assert.NoError(t, models.AddTeamMember(team, 5))
+
+		url := uploadPackage(t, org.Name, "team-package")
+		setVisibility(t, org.Name, "team-package", "private")
+
+		download(t, url, "user5", http.StatusUnauthorized)
+
+		accessURL := fmt.Sprintf("/api/v1/packages/%s/generic/team-package/access", org.Name)
+		teamURL := fmt.Sprintf("%s/teams/%s?token=%s", accessURL, team.Name, token)
+
+		req := NewRequestWithJSON(t, "PUT", teamURL, &api.PackageTeamAccessOption{
+			Permission: "write",
+		})
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", accessURL, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+		var access *api.PackageAccess
+		DecodeJSON(t, resp, &access)
+		assert.Equal(t, "private", access.Visibility)
+		if assert.Len(t, access.Teams, 1) {
+			assert.Equal(t, team.Name, access.Teams[0].Team.Name)
+			assert.Equal(t, "write", access.Teams[0].Permission)
+		}
+
+		download(t, url, "user5", http.StatusOK)
+
+		req = NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/team-package/1.0.1/file.bin", org.Name), bytes.NewReader([]byte{1, 2, 3}))
+		AddBasicAuthHeader(req, "user5")
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequest(t, "DELETE", teamURL)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "DELETE", teamURL)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		download(t, url, "user5", http.StatusUnauthorized)
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAggregatedRegistry(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	nonMemberOrg := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 6})
+
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	packageName := "aggregated-package"
+	packageVersion := "1.0.1"
+	filename := fmt.Sprintf("%s-%s.tgz", packageName, packageVersion)
+
+	req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, packageName), strings.NewReader(`{
+		"_id": "`+packageName+`",
+		"name": "`+packageName+`",
+		"dist-tags": {
+			"latest": "`+packageVersion+`"
+		},
+		"versions": {
+			"`+packageVersion+`": {
+				"name": "`+packageName+`",
+				"version": "`+packageVersion+`",
+				"dist": {
+					"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+					"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+				}
+			}
+		},
+		"_attachments": {
+			"`+filename+`": {
+				"data": "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+			}
+		}
+	}`))
+	req = AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusCreated)
+
+	sharesURL := fmt.Sprintf("/api/v1/packages/%s/npm/%s/shares", user.Name, packageName)
+	aggregateURL := fmt.Sprintf("/api/packages/%s/aggregate/npm/%s", org.Name, packageName)
+
+	getMetadata := func(t *testing.T, expectedStatus int) *npm.PackageMetadata {
+		req := NewRequest(t, "GET", aggregateURL)
+		req = AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+
+		var result *npm.PackageMetadata
+		DecodeJSON(t, resp, &result)
+		return result
+	}
+
+	t.Run("UserOwner", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/aggregate/npm/%s", user.Name, packageName))
+		req = AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NotShared", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		getMetadata(t, http.StatusNotFound)
+	})
+
+	t.Run("Share", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", fmt.Sprintf("%s/%s?token=%s", sharesURL, nonMemberOrg.Name, token))
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "PUT", fmt.Sprintf("%s/%s?token=%s", sharesURL, "does-not-exist", token))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "PUT", fmt.Sprintf("%s/%s?token=%s", sharesURL, org.Name, token))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", sharesURL, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var orgs []*api.Organization
+		DecodeJSON(t, resp, &orgs)
+		assert.Len(t, orgs, 1)
+		assert.Equal(t, org.Name, orgs[0].UserName)
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		result := getMetadata(t, http.StatusOK)
+		assert.Equal(t, packageName, result.Name)
+		assert.Contains(t, result.Versions, packageVersion)
+		assert.Equal(t, fmt.Sprintf("%s%s/-/%s/%s", setting.AppURL, aggregateURL[1:], packageVersion, filename), result.Versions[packageVersion].Dist.Tarball)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/-/%s/%s", aggregateURL, packageVersion, filename))
+		req = AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusOK)
+	})
+
+	t.Run("Unshare", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%s?token=%s", sharesURL, org.Name, token))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%s?token=%s", sharesURL, org.Name, token))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		getMetadata(t, http.StatusNotFound)
+	})
+}
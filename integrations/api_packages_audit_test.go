@@ -0,0 +1,108 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAuditLog(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	token := getUserToken(t, user.Name)
+	adminToken := getUserToken(t, "user1")
+
+	packageName := "audit-package"
+	packageVersion := "1.0.0"
+	filename := "file.bin"
+	url := fmt.Sprintf("/api/packages/%s/generic/%s/%s/%s", user.Name, packageName, packageVersion, filename)
+	userAgent := "audit-client/1.0"
+
+	req := NewRequestWithBody(t, "PUT", url+"?token="+token, bytes.NewReader([]byte{1, 2, 3}))
+	req.Header.Set("User-Agent", userAgent)
+	MakeRequest(t, req, http.StatusCreated)
+
+	// anonymous downloads are not recorded
+	req = NewRequest(t, "GET", url)
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", url+"?token="+token)
+	req.Header.Set("User-Agent", userAgent)
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/packages/%s/generic/%s/%s?token=%s", user.Name, packageName, packageVersion, token))
+	req.Header.Set("User-Agent", userAgent)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	listAuditLogs := func(t *testing.T, query string) []*api.PackageAuditLog {
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/packages/audit?token=%s&%s", adminToken, query))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var logs []*api.PackageAuditLog
+		DecodeJSON(t, resp, &logs)
+		return logs
+	}
+
+	t.Run("List", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		logs := listAuditLogs(t, "owner="+user.Name+"&name="+packageName)
+		assert.Len(t, logs, 3)
+		for i, action := range []string{"delete", "download", "publish"} {
+			assert.Equal(t, action, logs[i].Action)
+			assert.Equal(t, user.Name, logs[i].Owner)
+			assert.Equal(t, user.ID, logs[i].ActorID)
+			assert.Equal(t, user.Name, logs[i].Actor)
+			assert.Equal(t, "generic", logs[i].PackageType)
+			assert.Equal(t, packageVersion, logs[i].Version)
+			assert.Equal(t, userAgent, logs[i].UserAgent)
+			assert.NotEmpty(t, logs[i].IP)
+		}
+		assert.Equal(t, filename, logs[1].Filename)
+		assert.Equal(t, filename, logs[2].Filename)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		logs := listAuditLogs(t, "owner="+user.Name+"&action=download")
+		assert.Len(t, logs, 1)
+
+		logs = listAuditLogs(t, "actor=user1&name="+packageName)
+		assert.Empty(t, logs)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/packages/audit?token=%s&action=unknown", adminToken))
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/packages/audit?token=%s&owner=does-not-exist", adminToken))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NoAdmin", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/admin/packages/audit?token="+token)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("WebUI", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		session := loginUser(t, "user1")
+		req := NewRequest(t, "GET", "/admin/packages/audit?owner="+user.Name)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), userAgent)
+	})
+}
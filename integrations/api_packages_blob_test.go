@@ -0,0 +1,102 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageExistingBlob(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	content := []byte{1, 2, 3, 4}
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	unknownHash := strings.Repeat("0", 64)
+
+	packageURL := fmt.Sprintf("/api/packages/%s/generic/blob-package", user.Name)
+	blobURL := fmt.Sprintf("/api/v1/packages/%s/blobs/sha256/", user.Name)
+
+	req := NewRequest(t, "GET", blobURL+hash)
+	AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithBody(t, "PUT", packageURL+"/1.0.0/file.bin", bytes.NewReader(content))
+	AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusCreated)
+
+	t.Run("Check", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", blobURL+strings.ToUpper(hash))
+		AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var blob *api.PackageBlob
+		DecodeJSON(t, resp, &blob)
+		assert.Equal(t, hash, blob.SHA256)
+		assert.EqualValues(t, len(content), blob.Size)
+
+		req = NewRequest(t, "GET", blobURL+unknownHash)
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// only users who can publish packages of the owner can check for content
+		req = NewRequest(t, "GET", blobURL+hash)
+		AddBasicAuthHeader(req, "user4")
+		MakeRequest(t, req, http.StatusForbidden)
+
+		// content of other owners is not disclosed
+		req = NewRequest(t, "GET", "/api/v1/packages/user4/blobs/sha256/"+hash)
+		AddBasicAuthHeader(req, "user4")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", packageURL+"/1.0.1/file.bin?sha256="+unknownHash)
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "PUT", "/api/packages/user4/generic/blob-package/1.0.0/file.bin?sha256="+hash)
+		AddBasicAuthHeader(req, "user4")
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "PUT", packageURL+"/1.0.1/file.bin?sha256="+hash)
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		pvs, err := packages.GetVersionsByPackageName(db.DefaultContext, user.ID, packages.TypeGeneric, "blob-package")
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 2)
+
+		blobIDs := make([]int64, 0, len(pvs))
+		for _, pv := range pvs {
+			pfs, err := packages.GetFilesByVersionID(db.DefaultContext, pv.ID)
+			assert.NoError(t, err)
+			assert.Len(t, pfs, 1)
+			blobIDs = append(blobIDs, pfs[0].BlobID)
+		}
+		assert.Equal(t, blobIDs[0], blobIDs[1])
+
+		req = NewRequest(t, "GET", packageURL+"/1.0.1/file.bin")
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+	})
+}
@@ -18,6 +18,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	composer_module "code.gitea.io/gitea/modules/packages/composer"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/packages/composer"
 
 	"github.com/stretchr/testify/assert"
@@ -173,6 +174,51 @@ func TestPackageComposer(t *testing.T) {
 		}
 	})
 
+	t.Run("SearchServiceFields", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/search.json?order_by=downloads", url))
+		req = AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result composer.SearchResultResponse
+		DecodeJSON(t, resp, &result)
+
+		assert.Len(t, result.Results, 1)
+		assert.Equal(t, packageName, result.Results[0].Name)
+		if assert.NotNil(t, result.Results[0].Description) {
+			assert.Equal(t, packageDescription, *result.Results[0].Description)
+		}
+		if assert.NotNil(t, result.Results[0].Downloads) {
+			assert.Equal(t, int64(1), *result.Results[0].Downloads)
+		}
+		assert.NotEmpty(t, result.Results[0].URL)
+		assert.NotNil(t, result.Results[0].Repository)
+		assert.Nil(t, result.Results[0].Abandoned)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/search.json?order_by=name&fields=downloads", url))
+		req = AddBasicAuthHeader(req, user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		result = composer.SearchResultResponse{}
+		DecodeJSON(t, resp, &result)
+
+		assert.Len(t, result.Results, 1)
+		assert.Equal(t, packageName, result.Results[0].Name)
+		assert.Nil(t, result.Results[0].Description)
+		assert.Empty(t, result.Results[0].URL)
+		assert.Nil(t, result.Results[0].Repository)
+		assert.NotNil(t, result.Results[0].Downloads)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/search.json?order_by=stars", url))
+		req = AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/search.json?fields=stars", url))
+		req = AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+	})
+
 	t.Run("EnumeratePackages", func(t *testing.T) {
 		defer PrintCurrentTest(t)()
 
@@ -210,5 +256,88 @@ func TestPackageComposer(t *testing.T) {
 		assert.Equal(t, packageAuthor, pkgs[0].Authors[0].Name)
 		assert.Equal(t, "zip", pkgs[0].Dist.Type)
 		assert.Equal(t, "7b40bfd6da811b2b78deec1e944f156dbb2c747b", pkgs[0].Dist.Checksum)
+		assert.Equal(t, map[string]interface{}{"downloads": float64(1)}, pkgs[0].Extra[composer.DownloadsExtraKey])
+	})
+}
+
+func TestPackageComposerVendorGrants(t *testing.T) {
+	defer prepareTestEnv(t)()
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	writer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+	token := getTokenForLoggedInUser(t, loginUser(t, owner.Name))
+
+	upload := func(t *testing.T, packageName string, expectedStatus int) {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		w, _ := archive.Create("composer.json")
+		w.Write([]byte(`{"name": "` + packageName + `", "version": "1.0.0"}`))
+		archive.Close()
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/composer", org.Name), &buf)
+		req = AddBasicAuthHeader(req, writer.Name)
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	grantURL := func(vendor, team string) string {
+		return fmt.Sprintf("/api/v1/packages/%s/vendors/%s/teams/%s?token=%s", org.Name, vendor, team, token)
+	}
+
+	t.Run("Grant", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", grantURL("acme", "test_team"))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "PUT", grantURL("Acme!", "test_team"))
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "PUT", grantURL("acme", "unknown"))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		// the writer is no member of test_team
+		upload(t, "acme/reserved", http.StatusForbidden)
+		upload(t, "other/unreserved", http.StatusCreated)
+
+		req := NewRequest(t, "PUT", grantURL("acme", "team1"))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		upload(t, "acme/reserved", http.StatusCreated)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/vendors?token=%s", org.Name, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var grants []*api.PackageVendorGrant
+		DecodeJSON(t, resp, &grants)
+		assert.Len(t, grants, 2)
+		for _, grant := range grants {
+			assert.Equal(t, "acme", grant.Vendor)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", grantURL("acme", "team1"))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", grantURL("acme", "team1"))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NoPermission", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/vendors?token=%s", org.Name, getTokenForLoggedInUser(t, loginUser(t, writer.Name))))
+		MakeRequest(t, req, http.StatusForbidden)
 	})
 }
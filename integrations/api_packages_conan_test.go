@@ -404,6 +404,45 @@ func TestPackageConan(t *testing.T) {
 				}
 			})
 
+			t.Run("RecipeCaseInsensitive", func(t *testing.T) {
+				defer PrintCurrentTest(t)()
+
+				for _, query := range []string{"conanpackage", "CONANPACKAGE", "conan*", "CONAN*"} {
+					req := NewRequest(t, "GET", fmt.Sprintf("%s/v1/conans/search?q=%s", url, stdurl.QueryEscape(query)))
+					resp := MakeRequest(t, req, http.StatusOK)
+
+					var result *conan_router.SearchResult
+					DecodeJSON(t, resp, &result)
+					assert.Len(t, result.Results, 5, "query %s", query)
+				}
+			})
+
+			t.Run("RecipePrivate", func(t *testing.T) {
+				defer PrintCurrentTest(t)()
+
+				p, err := packages.GetPackageByName(db.DefaultContext, user.ID, packages.TypeConan, name)
+				assert.NoError(t, err)
+				assert.NoError(t, packages.SetPackageVisibility(db.DefaultContext, p.ID, packages.VisibilityPrivate))
+				defer func() {
+					assert.NoError(t, packages.SetPackageVisibility(db.DefaultContext, p.ID, packages.VisibilityPublic))
+				}()
+
+				req := NewRequest(t, "GET", fmt.Sprintf("%s/v1/conans/search?q=%s", url, name))
+				resp := MakeRequest(t, req, http.StatusOK)
+
+				var result *conan_router.SearchResult
+				DecodeJSON(t, resp, &result)
+				assert.Empty(t, result.Results)
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/v1/conans/search?q=%s", url, name))
+				req = addTokenAuthHeader(req, token)
+				resp = MakeRequest(t, req, http.StatusOK)
+
+				result = nil
+				DecodeJSON(t, resp, &result)
+				assert.Len(t, result.Results, 5)
+			})
+
 			t.Run("Package", func(t *testing.T) {
 				defer PrintCurrentTest(t)()
 
@@ -0,0 +1,205 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	conda_module "code.gitea.io/gitea/modules/packages/conda"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageConda(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "test_package"
+	packageVersion := "1.0.1"
+
+	channel := "test-channel"
+	root := fmt.Sprintf("/api/packages/%s/conda", user.Name)
+
+	createArchive := func(subdir string) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		content := []byte(`{"name":"` + packageName + `","version":"` + packageVersion + `","subdir":"` + subdir + `","build":"0"}`)
+		tw.WriteHeader(&tar.Header{
+			Name: "info/index.json",
+			Mode: 0o600,
+			Size: int64(len(content)),
+		})
+		tw.Write(content)
+		tw.Close()
+		return buf.Bytes()
+	}
+
+	t.Run("Upload", func(t *testing.T) {
+		tarContent := createArchive("noarch")
+
+		t.Run(".tar.bz2", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			var buf bytes.Buffer
+			bw, _ := bzip2.NewWriter(&buf, nil)
+			io.Copy(bw, bytes.NewReader(tarContent))
+			bw.Close()
+
+			filename := fmt.Sprintf("%s-%s.tar.bz2", packageName, packageVersion)
+
+			req := NewRequestWithBody(t, "PUT", root+"/"+filename, bytes.NewReader(buf.Bytes()))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", root+"/"+filename, bytes.NewReader(buf.Bytes()))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			req = NewRequestWithBody(t, "PUT", root+"/"+filename, bytes.NewReader(buf.Bytes()))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeConda)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Nil(t, pd.SemVer)
+			assert.IsType(t, &conda_module.VersionMetadata{}, pd.Metadata)
+			assert.Equal(t, packageName, pd.Package.Name)
+			assert.Equal(t, packageVersion, pd.Version.Version)
+		})
+
+		t.Run(".conda", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			var infoBuf bytes.Buffer
+			zsw, _ := zstd.NewWriter(&infoBuf)
+			io.Copy(zsw, bytes.NewReader(tarContent))
+			zsw.Close()
+
+			var buf bytes.Buffer
+			zpw := zip.NewWriter(&buf)
+			w, _ := zpw.Create("info-x.tar.zst")
+			w.Write(infoBuf.Bytes())
+			zpw.Close()
+
+			fullName := channel + "/" + packageName
+			filename := fmt.Sprintf("%s-%s.conda", packageName, packageVersion)
+
+			req := NewRequestWithBody(t, "PUT", root+"/"+channel+"/"+filename, bytes.NewReader(buf.Bytes()))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageName(db.DefaultContext, user.ID, packages.TypeConda, fullName)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Equal(t, fullName, pd.Package.Name)
+			assert.Equal(t, packageName, pd.PackageProperties.GetByName(conda_module.PropertyName))
+			assert.Equal(t, channel, pd.PackageProperties.GetByName(conda_module.PropertyChannel))
+			assert.Len(t, pd.Files, 1)
+			assert.Equal(t, "noarch", pd.Files[0].Properties.GetByName(conda_module.PropertySubdir))
+		})
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/noarch/%s-%s-0.tar.bz2", root, packageName, packageVersion))
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/noarch/%s-%s-0.conda", root, channel, packageName, packageVersion))
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/linux-64/%s-%s-0.tar.bz2", root, packageName, packageVersion))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("EnumeratePackages", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		type PackageInfo struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			Subdir     string `json:"subdir"`
+			HashSHA256 string `json:"sha256"`
+		}
+
+		type RepoData struct {
+			Info struct {
+				Subdir string `json:"subdir"`
+			} `json:"info"`
+			Packages      map[string]*PackageInfo `json:"packages"`
+			PackagesConda map[string]*PackageInfo `json:"packages.conda"`
+		}
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/noarch/repodata.json", root))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+		var result RepoData
+		DecodeJSON(t, resp, &result)
+
+		assert.Equal(t, "noarch", result.Info.Subdir)
+		assert.Len(t, result.Packages, 1)
+		assert.Empty(t, result.PackagesConda)
+		pi := result.Packages[fmt.Sprintf("%s-%s-0.tar.bz2", packageName, packageVersion)]
+		assert.NotNil(t, pi)
+		assert.Equal(t, packageName, pi.Name)
+		assert.Equal(t, packageVersion, pi.Version)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/noarch/repodata.json", root, channel))
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		result = RepoData{}
+		DecodeJSON(t, resp, &result)
+
+		assert.Empty(t, result.Packages)
+		assert.Len(t, result.PackagesConda, 1)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/noarch/repodata.json.bz2", root))
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "application/x-bzip2", resp.Header().Get("Content-Type"))
+	})
+
+	t.Run("PrivatePackage", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		p, err := packages.GetPackageByName(db.DefaultContext, user.ID, packages.TypeConda, packageName)
+		assert.NoError(t, err)
+		assert.NoError(t, packages.SetPackageVisibility(db.DefaultContext, p.ID, packages.VisibilityPrivate))
+		defer func() {
+			assert.NoError(t, packages.SetPackageVisibility(db.DefaultContext, p.ID, packages.VisibilityPublic))
+		}()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/noarch/repodata.json", root))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/noarch/%s-%s-0.tar.bz2", root, packageName, packageVersion))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/noarch/repodata.json", root))
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/noarch/repodata.json", root, channel))
+		MakeRequest(t, req, http.StatusOK)
+	})
+}
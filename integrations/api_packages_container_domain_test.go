@@ -0,0 +1,114 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageContainerDomain(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	domain := "registry.example.com"
+	domainURL := "http://" + domain
+	url := fmt.Sprintf("/api/v1/packages/%s/container_domain", user.Name)
+
+	adminToken := getUserToken(t, admin.Name)
+	userToken := getUserToken(t, user.Name)
+
+	t.Run("Set", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s?token=%s", url, userToken), &api.SetPackageContainerDomainOption{Domain: domain})
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s?token=%s", url, adminToken), &api.SetPackageContainerDomainOption{Domain: "invalid domain"})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s?token=%s", url, adminToken), &api.SetPackageContainerDomainOption{Domain: domain})
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var d *api.PackageContainerDomain
+		DecodeJSON(t, resp, &d)
+		assert.Equal(t, domain, d.Domain)
+
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/packages/user3/container_domain?token=%s", adminToken), &api.SetPackageContainerDomainOption{Domain: domain})
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", url, userToken))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var d *api.PackageContainerDomain
+		DecodeJSON(t, resp, &d)
+		assert.Equal(t, domain, d.Domain)
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", domainURL+"/v2")
+		resp := MakeRequest(t, req, http.StatusUnauthorized)
+		assert.Equal(t, `Bearer realm="`+domainURL+`/v2/token",service="container_registry",scope="*"`, resp.Header().Get("WWW-Authenticate"))
+
+		req = NewRequest(t, "GET", domainURL+"/explore/repos")
+		MakeRequest(t, req, http.StatusNotFound)
+
+		type TokenResponse struct {
+			Token string `json:"token"`
+		}
+
+		req = NewRequest(t, "GET", domainURL+"/v2/token")
+		req = AddBasicAuthHeader(req, user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		tokenResponse := &TokenResponse{}
+		DecodeJSON(t, resp, &tokenResponse)
+		token := fmt.Sprintf("Bearer %s", tokenResponse.Token)
+
+		blobDigest := "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+		blobContent, _ := base64.StdEncoding.DecodeString(`H4sIAAAJbogA/2IYBaNgFIxYAAgAAP//Lq+17wAEAAA=`)
+
+		req = NewRequestWithBody(t, "POST", fmt.Sprintf("%s/v2/test/blobs/uploads?digest=%s", domainURL, blobDigest), bytes.NewReader(blobContent))
+		addTokenAuthHeader(req, token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		assert.Equal(t, "/v2/test/blobs/"+blobDigest, resp.Header().Get("Location"))
+
+		req = NewRequest(t, "HEAD", fmt.Sprintf("/v2/%s/test/blobs/%s", user.Name, blobDigest))
+		addTokenAuthHeader(req, token)
+		MakeRequest(t, req, http.StatusOK)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", url, userToken))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", url, userToken))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		_, err := packages_model.GetContainerDomainByDomain(db.DefaultContext, domain)
+		assert.ErrorIs(t, err, packages_model.ErrContainerDomainNotExist)
+	})
+}
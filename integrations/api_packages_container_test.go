@@ -326,6 +326,24 @@ func TestPackageContainer(t *testing.T) {
 						assert.Equal(t, manifestDigest, resp.Header().Get("Docker-Content-Digest"))
 						assert.Equal(t, manifestContent, resp.Body.String())
 					})
+
+					t.Run("GetManifestServeDirect", func(t *testing.T) {
+						defer PrintCurrentTest(t)()
+
+						setting.Packages.Storage.ServeDirect = true
+						defer func() {
+							setting.Packages.Storage.ServeDirect = false
+						}()
+
+						// manifests are never redirected to the storage
+						req := NewRequest(t, "GET", fmt.Sprintf("%s/manifests/%s", url, tag))
+						addTokenAuthHeader(req, userToken)
+						resp := MakeRequest(t, req, http.StatusOK)
+
+						assert.Equal(t, oci.MediaTypeDockerManifest, resp.Header().Get("Content-Type"))
+						assert.Equal(t, manifestDigest, resp.Header().Get("Docker-Content-Digest"))
+						assert.Equal(t, manifestContent, resp.Body.String())
+					})
 				})
 			}
 
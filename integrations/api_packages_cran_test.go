@@ -0,0 +1,238 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	cran_module "code.gitea.io/gitea/modules/packages/cran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageCran(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "test.package"
+	packageVersion := "1.0.3"
+	packageAuthor := "KN4CK3R"
+	packageDescription := "Gitea Test Package"
+
+	createDescription := func(name, version string) []byte {
+		var buf bytes.Buffer
+		fmt.Fprintln(&buf, "Package:", name)
+		fmt.Fprintln(&buf, "Version:", version)
+		fmt.Fprintln(&buf, "Description:", packageDescription)
+		fmt.Fprintln(&buf, "Imports: abc,\n def")
+		fmt.Fprintln(&buf, "Author:", packageAuthor)
+		fmt.Fprintln(&buf, "License: GPL (>= 2)")
+		fmt.Fprintln(&buf, "NeedsCompilation: no")
+		return buf.Bytes()
+	}
+
+	root := fmt.Sprintf("/api/packages/%s/cran", user.Name)
+
+	t.Run("Source", func(t *testing.T) {
+		createArchive := func(filename string, content []byte) *bytes.Buffer {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+			hdr := &tar.Header{
+				Name: filename,
+				Mode: 0o600,
+				Size: int64(len(content)),
+			}
+			tw.WriteHeader(hdr)
+			tw.Write(content)
+			tw.Close()
+			gw.Close()
+			return &buf
+		}
+
+		t.Run("Upload", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			uploadURL := root + "/src"
+
+			req := NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader([]byte{}))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"dummy.txt",
+				[]byte{},
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"package/DESCRIPTION",
+				createDescription(packageName, packageVersion),
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeCran)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Nil(t, pd.SemVer)
+			assert.IsType(t, &cran_module.Metadata{}, pd.Metadata)
+			assert.Equal(t, packageName, pd.Package.Name)
+			assert.Equal(t, packageVersion, pd.Version.Version)
+			assert.Len(t, pd.Files, 1)
+			assert.Equal(t, fmt.Sprintf("%s_%s.tar.gz", packageName, packageVersion), pd.Files[0].File.Name)
+			assert.Equal(t, cran_module.TypeSource, pd.Files[0].Properties.GetByName(cran_module.PropertyType))
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"package/DESCRIPTION",
+				createDescription(packageName, packageVersion),
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/src/contrib/%s_%s.tar.gz", root, packageName, packageVersion))
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/src/contrib/%s_%s.zip", root, packageName, packageVersion))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Enumerate", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", root+"/src/contrib/PACKAGES")
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			assert.Contains(t, resp.Header().Get("Content-Type"), "text/plain")
+
+			body := resp.Body.String()
+			assert.Contains(t, body, fmt.Sprintf("Package: %s", packageName))
+			assert.Contains(t, body, fmt.Sprintf("Version: %s", packageVersion))
+			assert.Contains(t, body, "Imports: abc, def")
+			assert.Contains(t, body, "MD5sum: ")
+
+			req = NewRequest(t, "GET", root+"/src/contrib/PACKAGES.gz")
+			resp = MakeRequest(t, req, http.StatusOK)
+
+			assert.Equal(t, "application/x-gzip", resp.Header().Get("Content-Type"))
+
+			gr, err := gzip.NewReader(resp.Body)
+			assert.NoError(t, err)
+			content, err := io.ReadAll(gr)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(content))
+		})
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		createArchive := func(filename string, content []byte) *bytes.Buffer {
+			var buf bytes.Buffer
+			archive := zip.NewWriter(&buf)
+			w, _ := archive.Create(filename)
+			w.Write(content)
+			archive.Close()
+			return &buf
+		}
+
+		t.Run("Upload", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			uploadURL := root + "/bin"
+
+			req := NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader([]byte{}))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"package/DESCRIPTION",
+				createDescription(packageName, packageVersion),
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			uploadURL += "?platform=windows&rversion=4.2"
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"dummy.txt",
+				[]byte{},
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"package/DESCRIPTION",
+				createDescription(packageName, packageVersion),
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeCran)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Len(t, pd.Files, 2)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, createArchive(
+				"package/DESCRIPTION",
+				createDescription(packageName, packageVersion),
+			))
+			req = AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			cases := []struct {
+				Platform       string
+				RVersion       string
+				ExpectedStatus int
+			}{
+				{"osx", "4.2", http.StatusNotFound},
+				{"windows", "4.1", http.StatusNotFound},
+				{"windows", "4.2", http.StatusOK},
+			}
+
+			for _, c := range cases {
+				req := NewRequest(t, "GET", fmt.Sprintf("%s/bin/%s/contrib/%s/%s_%s.zip", root, c.Platform, c.RVersion, packageName, packageVersion))
+				MakeRequest(t, req, c.ExpectedStatus)
+			}
+		})
+
+		t.Run("Enumerate", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", root+"/bin/osx/contrib/4.2/PACKAGES")
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Empty(t, resp.Body.String())
+
+			req = NewRequest(t, "GET", root+"/bin/windows/contrib/4.2/PACKAGES")
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), fmt.Sprintf("Package: %s", packageName))
+
+			req = NewRequest(t, "GET", root+"/bin/windows/contrib/4.2/PACKAGES.gz")
+			MakeRequest(t, req, http.StatusOK)
+		})
+	})
+}
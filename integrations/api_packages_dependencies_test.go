@@ -0,0 +1,128 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageDependencies(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	token := fmt.Sprintf("Bearer %s", getTokenForLoggedInUser(t, loginUser(t, user.Name)))
+
+	uploadNpmPackage := func(t *testing.T, name, version, dependencies string) {
+		data := "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+
+		upload := `{
+			"_id": "` + name + `",
+			"name": "` + name + `",
+			"dist-tags": {
+				"latest": "` + version + `"
+			},
+			"versions": {
+				"` + version + `": {
+					"name": "` + name + `",
+					"version": "` + version + `",
+					` + dependencies + `
+					"dist": {
+						"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+						"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+					}
+				}
+			},
+			"_attachments": {
+				"` + name + `-` + version + `.tgz": {
+					"data": "` + data + `"
+				}
+			}
+		}`
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, name), strings.NewReader(upload))
+		req = addTokenAuthHeader(req, token)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	uploadNpmPackage(t, "dependency-lib", "1.0.0", "")
+	uploadNpmPackage(t, "dependency-app", "1.0.0", `"dependencies": {"dependency-lib": "^1.0.0"}, "devDependencies": {"dependency-tool": "~2.0"},`)
+
+	t.Run("Dependencies", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-app/1.0.0/dependencies", user.Name))
+		req = addTokenAuthHeader(req, token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var deps []*api.PackageDependency
+		DecodeJSON(t, resp, &deps)
+		assert.Equal(t, []*api.PackageDependency{
+			{Kind: "runtime", Name: "dependency-lib", VersionRange: "^1.0.0"},
+			{Kind: "development", Name: "dependency-tool", VersionRange: "~2.0"},
+		}, deps)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-lib/1.0.0/dependencies", user.Name))
+		req = addTokenAuthHeader(req, token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &deps)
+		assert.Empty(t, deps)
+	})
+
+	t.Run("Dependents", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-lib/dependents", user.Name))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var dependents []*api.PackageDependent
+		DecodeJSON(t, resp, &dependents)
+		assert.Len(t, dependents, 1)
+		assert.Equal(t, "dependency-app", dependents[0].Package.Name)
+		assert.Equal(t, "1.0.0", dependents[0].Package.Version)
+		assert.Equal(t, "^1.0.0", dependents[0].Dependency.VersionRange)
+		assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-app/dependents", user.Name))
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &dependents)
+		assert.Empty(t, dependents)
+	})
+
+	t.Run("View", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/npm/dependency-lib/1.0.0", user.Name))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, fmt.Sprintf(`a[href$="/%s/-/packages/npm/dependency-app/1.0.0"]`, user.Name), true)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-app/1.0.0", user.Name))
+		req = addTokenAuthHeader(req, token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/npm/dependency-lib/dependents", user.Name))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var dependents []*api.PackageDependent
+		DecodeJSON(t, resp, &dependents)
+		assert.Empty(t, dependents)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageVersionDiff(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageURL := fmt.Sprintf("/api/packages/%s/generic/diff-package", user.Name)
+	upload := func(version, filename, content string) {
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/%s/%s", packageURL, version, filename), strings.NewReader(content))
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	upload("1.0.0", "README.md", "line 1\nline 2\n")
+	upload("1.0.0", "removed.txt", "removed\n")
+	upload("1.0.0", "unchanged.txt", "unchanged\n")
+	upload("1.1.0", "README.md", "line 1\nline two\n")
+	upload("1.1.0", "added.bin", "\x00\x01\x02")
+	upload("1.1.0", "unchanged.txt", "unchanged\n")
+
+	compareURL := fmt.Sprintf("/api/v1/packages/%s/generic/diff-package/compare", user.Name)
+
+	t.Run("FileList", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", compareURL+"/1.0.0/1.1.0")
+		AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var diff *api.PackageVersionDiff
+		DecodeJSON(t, resp, &diff)
+		assert.Equal(t, "1.0.0", diff.From)
+		assert.Equal(t, "1.1.0", diff.To)
+		assert.Equal(t, []*api.PackageFileDiff{
+			{Path: "README.md", Status: "modified", OldSize: 14, NewSize: 16},
+			{Path: "added.bin", Status: "added", NewSize: 3},
+			{Path: "removed.txt", Status: "removed", OldSize: 8},
+		}, diff.Files)
+	})
+
+	t.Run("Content", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", compareURL+"/1.0.0/1.1.0?content=true")
+		AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var diff *api.PackageVersionDiff
+		DecodeJSON(t, resp, &diff)
+		if assert.Len(t, diff.Files, 3) {
+			assert.Equal(t, "--- a/README.md\n+++ b/README.md\n@@ -1,2 +1,2 @@\n line 1\n-line 2\n+line two\n", diff.Files[0].Patch)
+			assert.Empty(t, diff.Files[1].Patch, "binary files have no patch")
+			assert.Equal(t, "--- a/removed.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-removed\n", diff.Files[2].Patch)
+		}
+	})
+
+	t.Run("NotExist", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", compareURL+"/1.0.0/2.0.0")
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+	t.Run("Web", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		session := loginUser(t, user.Name)
+		webURL := fmt.Sprintf("/%s/-/packages/generic/diff-package", user.Name)
+
+		// the previous version is compared by default
+		resp := session.MakeRequest(t, NewRequest(t, "GET", webURL+"/1.1.0/compare"), http.StatusOK)
+		body := resp.Body.String()
+		assert.Contains(t, body, "README.md")
+		assert.Contains(t, body, "added.bin")
+		assert.Contains(t, body, "removed.txt")
+		assert.NotContains(t, body, "unchanged.txt")
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", webURL+"/1.0.0/compare?base=1.1.0"), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "added.bin")
+
+		// there is no version before the first one
+		resp = session.MakeRequest(t, NewRequest(t, "GET", webURL+"/1.0.0/compare"), http.StatusOK)
+		assert.NotContains(t, resp.Body.String(), "removed.txt")
+
+		session.MakeRequest(t, NewRequest(t, "GET", webURL+"/1.1.0/compare?base=2.0.0"), http.StatusNotFound)
+	})
+}
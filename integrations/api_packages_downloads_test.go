@@ -0,0 +1,130 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageDownloadStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "download-package"
+	packageURL := fmt.Sprintf("/api/packages/%s/generic/%s", user.Name, packageName)
+	statsURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s", user.Name, packageName)
+
+	for _, version := range []string{"1.0.0", "1.0.1"} {
+		for _, filename := range []string{"file.bin", "file.txt"} {
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/%s/%s", packageURL, version, filename), bytes.NewReader([]byte{1, 2, 3}))
+			AddBasicAuthHeader(req, user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+	}
+
+	download := func(t *testing.T, version, filename, userAgent string) {
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s", packageURL, version, filename))
+		req.Header.Set("User-Agent", userAgent)
+		MakeRequest(t, req, http.StatusOK)
+	}
+
+	download(t, "1.0.0", "file.bin", "curl/7.81.0")
+	download(t, "1.0.0", "file.bin", "curl/7.81.0")
+	download(t, "1.0.0", "file.txt", "Mozilla/5.0 (X11; Linux x86_64; rv:106.0) Gecko/20100101 Firefox/106.0")
+	download(t, "1.0.1", "file.bin", "")
+
+	getStats := func(t *testing.T, url string, expectedStatus int) *api.PackageDownloadStats {
+		req := NewRequest(t, "GET", url)
+		resp := MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+
+		var stats *api.PackageDownloadStats
+		DecodeJSON(t, resp, &stats)
+		return stats
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+
+	t.Run("Version", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		stats := getStats(t, statsURL+"/1.0.0/downloads", http.StatusOK)
+		assert.EqualValues(t, 3, stats.Total)
+		assert.Equal(t, today, stats.Until)
+		assert.Len(t, stats.Days, 30)
+		assert.Equal(t, today, stats.Days[29].Date)
+		assert.EqualValues(t, 3, stats.Days[29].Count)
+		assert.Empty(t, stats.Versions)
+
+		if assert.Len(t, stats.Clients, 2) {
+			assert.Equal(t, "cli", stats.Clients[0].Client)
+			assert.EqualValues(t, 2, stats.Clients[0].Count)
+			assert.Equal(t, "browser", stats.Clients[1].Client)
+			assert.EqualValues(t, 1, stats.Clients[1].Count)
+		}
+		if assert.Len(t, stats.Files, 2) {
+			assert.Equal(t, "file.bin", stats.Files[0].Name)
+			assert.EqualValues(t, 2, stats.Files[0].Count)
+			assert.Equal(t, "file.txt", stats.Files[1].Name)
+			assert.EqualValues(t, 1, stats.Files[1].Count)
+		}
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		stats := getStats(t, statsURL+"/downloads", http.StatusOK)
+		assert.EqualValues(t, 4, stats.Total)
+		assert.Empty(t, stats.Files)
+
+		if assert.Len(t, stats.Versions, 2) {
+			assert.Equal(t, "1.0.0", stats.Versions[0].Version)
+			assert.EqualValues(t, 3, stats.Versions[0].Count)
+			assert.Equal(t, "1.0.1", stats.Versions[1].Version)
+			assert.EqualValues(t, 1, stats.Versions[1].Count)
+		}
+		if assert.Len(t, stats.Clients, 3) {
+			assert.Equal(t, "unknown", stats.Clients[2].Client)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		stats := getStats(t, statsURL+"/downloads?since=2020-01-01&until=2020-01-07", http.StatusOK)
+		assert.EqualValues(t, 0, stats.Total)
+		assert.Equal(t, "2020-01-01", stats.Since)
+		assert.Equal(t, "2020-01-07", stats.Until)
+		assert.Len(t, stats.Days, 7)
+
+		getStats(t, statsURL+"/downloads?since=yesterday", http.StatusUnprocessableEntity)
+		getStats(t, statsURL+"/downloads?since=2020-01-07&until=2020-01-01", http.StatusUnprocessableEntity)
+		getStats(t, statsURL+"/downloads?since=2020-01-01&until=2022-01-01", http.StatusUnprocessableEntity)
+	})
+
+	t.Run("DeleteVersion", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", packageURL+"/1.0.0")
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		stats := getStats(t, statsURL+"/downloads", http.StatusOK)
+		assert.EqualValues(t, 1, stats.Total)
+		assert.Len(t, stats.Versions, 1)
+	})
+}
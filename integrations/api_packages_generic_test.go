@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -125,6 +126,27 @@ func TestPackageGeneric(t *testing.T) {
 			req := NewRequest(t, "GET", url+"/not.found")
 			MakeRequest(t, req, http.StatusNotFound)
 		})
+
+		t.Run("ServeDirect", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			setting.Packages.Storage.ServeDirect = true
+			defer func() {
+				setting.Packages.Storage.ServeDirect = false
+			}()
+
+			req := NewRequest(t, "GET", url+"/"+filename)
+			if setting.Packages.Storage.Type == "minio" {
+				resp := MakeRequest(t, req, http.StatusFound)
+				assert.Contains(t, resp.Header().Get("Location"), filename)
+			} else {
+				// local storage does not support presigned urls and serves the content itself
+				resp := MakeRequest(t, req, http.StatusOK)
+				assert.Equal(t, content, resp.Body.Bytes())
+			}
+
+			checkDownloadCount(3)
+		})
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -0,0 +1,188 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPackageMirror(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	defer func(allowList string) {
+		setting.Packages.MirrorAllowedHostList = allowList
+	}(setting.Packages.MirrorAllowedHostList)
+	setting.Packages.MirrorAllowedHostList = "loopback"
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getTokenForLoggedInUser(t, loginUser(t, user.Name))
+
+	packageName := "@scope/mirrored"
+	tarball := []byte("mirrored package content")
+	sum := sha512.Sum512(tarball)
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + packageName:
+			packument := &npm_module.PackageMetadata{
+				Name:     packageName,
+				DistTags: map[string]string{"latest": "2.0.0", "stable": "1.0.0"},
+				Versions: map[string]*npm_module.PackageMetadataVersion{},
+			}
+			for _, v := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+				packument.Versions[v] = &npm_module.PackageMetadataVersion{
+					Name:    packageName,
+					Version: v,
+					Dist: npm_module.PackageDistribution{
+						Integrity: integrity,
+						Tarball:   fmt.Sprintf("http://%s/tarballs/%s", r.Host, v),
+					},
+				}
+			}
+			_ = json.NewEncoder(w).Encode(packument)
+		case "/tarballs/1.0.0", "/tarballs/1.1.0", "/tarballs/2.0.0":
+			_, _ = w.Write(tarball)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	root := fmt.Sprintf("/api/v1/packages/%s/mirrors", user.Name)
+
+	t.Run("CreateInvalid", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		for _, opts := range []*api.CreatePackageMirrorOption{
+			{Type: "maven", UpstreamURL: upstream.URL, Rules: []*api.PackageMirrorRule{{Name: packageName}}},
+			{Type: "npm", UpstreamURL: "ftp://example.com", Rules: []*api.PackageMirrorRule{{Name: packageName}}},
+			{Type: "npm", UpstreamURL: upstream.URL, Rules: []*api.PackageMirrorRule{{Name: packageName, VersionRange: "^1.0"}}},
+			{Type: "npm", UpstreamURL: upstream.URL, Rules: []*api.PackageMirrorRule{{Name: packageName}}, Interval: "1m"},
+		} {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", root, token), opts)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		}
+	})
+
+	var mirror *api.PackageMirror
+
+	t.Run("Create", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", root, token), &api.CreatePackageMirrorOption{
+			Type:        "npm",
+			UpstreamURL: upstream.URL,
+			Rules:       []*api.PackageMirrorRule{{Name: packageName, VersionRange: ">= 1.0, < 2"}},
+		})
+		resp := MakeRequest(t, req, http.StatusCreated)
+		DecodeJSON(t, resp, &mirror)
+		assert.Equal(t, "npm", mirror.Type)
+		assert.Equal(t, "24h0m0s", mirror.Interval)
+		assert.Nil(t, mirror.LastSync)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", root, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		var mirrors []*api.PackageMirror
+		DecodeJSON(t, resp, &mirrors)
+		assert.Len(t, mirrors, 1)
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "POST", fmt.Sprintf("%s/%d/sync?token=%s", root, mirror.ID, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+		var result *api.PackageMirrorSyncResult
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, []string{packageName + "@1.0.0", packageName + "@1.1.0"}, result.Synced)
+		assert.Empty(t, result.Errors)
+
+		pvs, err := packages_model.GetVersionsByPackageName(db.DefaultContext, user.ID, packages_model.TypeNpm, packageName)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 2)
+
+		tags := make(map[string]string)
+		for _, pv := range pvs {
+			pvps, err := packages_model.GetPropertiesByName(db.DefaultContext, packages_model.PropertyTypeVersion, pv.ID, npm_module.TagProperty)
+			assert.NoError(t, err)
+			for _, pvp := range pvps {
+				tags[pvp.Value] = pv.Version
+			}
+		}
+		// the upstream latest version is not mirrored
+		assert.Equal(t, map[string]string{"latest": "1.1.0", "stable": "1.0.0"}, tags)
+
+		// a second sync has nothing to do
+		req = NewRequest(t, "POST", fmt.Sprintf("%s/%d/sync?token=%s", root, mirror.ID, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &result)
+		assert.Empty(t, result.Synced)
+		assert.Empty(t, result.Errors)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d?token=%s", root, mirror.ID, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &mirror)
+		assert.NotNil(t, mirror.LastSync)
+		assert.Empty(t, mirror.LastError)
+	})
+
+	t.Run("SyncError", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		interval := "2h"
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d?token=%s", root, mirror.ID, token), &api.EditPackageMirrorOption{
+			Rules:    []*api.PackageMirrorRule{{Name: packageName}, {Name: "missing"}},
+			Interval: &interval,
+		})
+		resp := MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &mirror)
+		assert.Equal(t, "2h0m0s", mirror.Interval)
+		assert.Len(t, mirror.Rules, 2)
+
+		req = NewRequest(t, "POST", fmt.Sprintf("%s/%d/sync?token=%s", root, mirror.ID, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		var result *api.PackageMirrorSyncResult
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, []string{packageName + "@2.0.0"}, result.Synced)
+		assert.Len(t, result.Errors, 1)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d?token=%s", root, mirror.ID, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &mirror)
+		assert.Contains(t, mirror.LastError, "missing")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", root, mirror.ID, token))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d?token=%s", root, mirror.ID, token))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// the mirrored packages are kept
+		pvs, err := packages_model.GetVersionsByPackageName(db.DefaultContext, user.ID, packages_model.TypeNpm, packageName)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 3)
+	})
+}
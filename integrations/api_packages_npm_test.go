@@ -18,6 +18,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/packages/npm"
 	"code.gitea.io/gitea/modules/setting"
+	npm_router "code.gitea.io/gitea/routers/api/packages/npm"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -156,6 +157,26 @@ func TestPackageNpm(t *testing.T) {
 		assert.Equal(t, "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==", pmv.Dist.Integrity)
 		assert.Equal(t, "aaa7eaf852a948b0aa05afeda35b1badca155d90", pmv.Dist.Shasum)
 		assert.Equal(t, fmt.Sprintf("%s%s/-/%s/%s", setting.AppURL, root[1:], packageVersion, filename), pmv.Dist.Tarball)
+		assert.EqualValues(t, 1, pmv.Downloads)
+	})
+
+	t.Run("PackageDownloads", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/-/downloads/%s", user.Name, "does-not-exist"))
+		req = addTokenAuthHeader(req, token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/-/downloads/%s", user.Name, packageName))
+		req = addTokenAuthHeader(req, token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result npm_router.PackageDownloadsResponse
+		DecodeJSON(t, resp, &result)
+
+		assert.Equal(t, packageName, result.Package)
+		assert.EqualValues(t, 1, result.Downloads)
+		assert.Equal(t, map[string]int64{packageVersion: 1}, result.Versions)
 	})
 
 	t.Run("AddTag", func(t *testing.T) {
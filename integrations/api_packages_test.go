@@ -8,14 +8,19 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	container_model "code.gitea.io/gitea/models/packages/container"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	packages_service "code.gitea.io/gitea/services/packages"
 
@@ -111,6 +116,42 @@ func TestPackageAPI(t *testing.T) {
 		})
 	})
 
+	t.Run("EditPackage", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		url := fmt.Sprintf("/api/v1/packages/%s/generic/%s/%s", user.Name, packageName, packageVersion)
+		notes := "- Fixed the handling of **empty** input"
+
+		req := NewRequestWithJSON(t, "PATCH", url+"?token="+getUserToken(t, "user5"), &api.EditPackageOption{ReleaseNotes: &notes})
+		MakeRequest(t, req, http.StatusForbidden)
+
+		tooLarge := strings.Repeat("a", packages_module.MaxReleaseNotesSize+1)
+		req = NewRequestWithJSON(t, "PATCH", url+"?token="+token, &api.EditPackageOption{ReleaseNotes: &tooLarge})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PATCH", url+"?token="+token, &api.EditPackageOption{ReleaseNotes: &notes})
+		resp := MakeRequest(t, req, http.StatusOK)
+		var p *api.Package
+		DecodeJSON(t, resp, &p)
+		assert.Equal(t, notes, p.ReleaseNotes)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/generic/%s/%s", user.Name, packageName, packageVersion))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "<strong>empty</strong>")
+
+		// options which are not set don't change the release notes
+		req = NewRequestWithJSON(t, "PATCH", url+"?token="+token, &api.EditPackageOption{})
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &p)
+		assert.Equal(t, notes, p.ReleaseNotes)
+
+		empty := ""
+		req = NewRequestWithJSON(t, "PATCH", url+"?token="+token, &api.EditPackageOption{ReleaseNotes: &empty})
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &p)
+		assert.Empty(t, p.ReleaseNotes)
+	})
+
 	t.Run("ListPackageFiles", func(t *testing.T) {
 		defer PrintCurrentTest(t)()
 
@@ -165,3 +206,263 @@ func TestPackageCleanup(t *testing.T) {
 	_, err = packages_model.GetInternalVersionByNameAndVersion(db.DefaultContext, 2, packages_model.TypeContainer, "test", container_model.UploadVersion)
 	assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
 }
+
+func TestPackageAccessTokenScope(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	createToken := func(t *testing.T, name string, scope auth_model.AccessTokenScope, ownerID int64) string {
+		token := &auth_model.AccessToken{
+			UID:     user.ID,
+			Name:    name,
+			Scope:   scope,
+			OwnerID: ownerID,
+		}
+		assert.NoError(t, auth_model.NewAccessToken(token))
+		return token.Token
+	}
+
+	readToken := createToken(t, "package-read", auth_model.AccessTokenScopePackageRead, 0)
+	writeToken := createToken(t, "package-write", auth_model.AccessTokenScopePackageWrite, 0)
+	otherOwnerToken := createToken(t, "package-write-other-owner", auth_model.AccessTokenScopePackageWrite, 3)
+
+	url := fmt.Sprintf("/api/packages/%s/generic/test-package/1.0.0/file.bin", user.Name)
+
+	upload := func(t *testing.T, token string, expectedStatus int) {
+		req := NewRequestWithBody(t, "PUT", url, bytes.NewReader([]byte{1}))
+		req.Header.Set("Authorization", "token "+token)
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		upload(t, readToken, http.StatusUnauthorized)
+	})
+
+	t.Run("OtherOwner", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		upload(t, otherOwnerToken, http.StatusUnauthorized)
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		upload(t, writeToken, http.StatusCreated)
+
+		req := NewRequest(t, "GET", url)
+		req.Header.Set("Authorization", "token "+readToken)
+		MakeRequest(t, req, http.StatusOK)
+	})
+
+	t.Run("NonPackageEndpoint", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		for _, token := range []string{readToken, writeToken} {
+			req := NewRequest(t, "GET", "/api/v1/user?token="+token)
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequest(t, "GET", "/api/v1/user")
+			req.SetBasicAuth(user.Name, token)
+			MakeRequest(t, req, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestPackageAnonymousRead(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	fileURL := fmt.Sprintf("/api/packages/%s/generic/test-package/1.0.0/file.bin", user.Name)
+
+	req := NewRequestWithBody(t, "PUT", fileURL, bytes.NewReader([]byte{1}))
+	AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusCreated)
+
+	setVisibility := func(t *testing.T, visibility api.VisibleType) {
+		user.Visibility = visibility
+		assert.NoError(t, user_model.UpdateUserCols(db.DefaultContext, user, "visibility"))
+	}
+
+	assert.NoError(t, user_model.SetUserSetting(user.ID, user_model.SettingsKeyPackagesAnonymousRead, "true"))
+
+	t.Run("LimitedOwner", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		setVisibility(t, api.VisibleTypeLimited)
+		defer setVisibility(t, api.VisibleTypePublic)
+
+		req := NewRequest(t, "GET", fileURL)
+		MakeRequest(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("RequireSignInView", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		setting.Service.RequireSignInView = true
+		defer func() {
+			setting.Service.RequireSignInView = false
+		}()
+
+		req := NewRequest(t, "GET", fileURL)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/test-package/1.0.1/file.bin", user.Name), bytes.NewReader([]byte{1}))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		assert.NoError(t, user_model.DeleteUserSetting(user.ID, user_model.SettingsKeyPackagesAnonymousRead))
+
+		req = NewRequest(t, "GET", fileURL)
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequest(t, "GET", fileURL)
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusOK)
+
+		assert.NoError(t, user_model.SetUserSetting(user.ID, user_model.SettingsKeyPackagesAnonymousRead, "true"))
+
+		for _, visibility := range []api.VisibleType{api.VisibleTypeLimited, api.VisibleTypePrivate} {
+			setVisibility(t, visibility)
+
+			req = NewRequest(t, "GET", fileURL)
+			MakeRequest(t, req, http.StatusUnauthorized)
+		}
+		setVisibility(t, api.VisibleTypePublic)
+	})
+
+	t.Run("Registries", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		assert.NoError(t, user_model.DeleteUserSetting(user.ID, user_model.SettingsKeyPackagesAnonymousRead))
+		defer setVisibility(t, api.VisibleTypePublic)
+
+		root := fmt.Sprintf("/api/packages/%s", user.Name)
+
+		packageName := "@scope/test-package"
+		npmURL := fmt.Sprintf("%s/npm/%s", root, url.QueryEscape(packageName))
+
+		req := NewRequestWithBody(t, "PUT", npmURL, strings.NewReader(`{
+			"_id": "`+packageName+`",
+			"name": "`+packageName+`",
+			"dist-tags": {
+				"latest": "1.0.0"
+			},
+			"versions": {
+				"1.0.0": {
+					"name": "`+packageName+`",
+					"version": "1.0.0",
+					"dist": {
+						"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+						"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+					}
+				}
+			},
+			"_attachments": {
+				"`+packageName+`-1.0.0.tgz": {
+					"data": "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+				}
+			}
+		}`))
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		reads := []string{
+			root + "/composer/packages.json",
+			root + "/composer/list.json",
+			root + "/nuget/index.json",
+			root + "/nuget/query",
+			npmURL,
+			npmURL + "/-/1.0.0/test-package-1.0.0.tgz",
+		}
+
+		writes := []string{
+			root + "/composer",
+			root + "/nuget/",
+			fmt.Sprintf("%s/npm/%s", root, url.QueryEscape("@scope/other-package")),
+		}
+
+		checkAnonymous := func(t *testing.T, readable bool) {
+			for _, u := range reads {
+				expected := http.StatusUnauthorized
+				if readable {
+					expected = http.StatusOK
+				}
+				MakeRequest(t, NewRequest(t, "GET", u), expected)
+			}
+			for _, u := range writes {
+				req := NewRequestWithBody(t, "PUT", u, bytes.NewReader([]byte{}))
+				MakeRequest(t, req, http.StatusUnauthorized)
+			}
+		}
+
+		setting.Service.RequireSignInView = true
+		defer func() {
+			setting.Service.RequireSignInView = false
+		}()
+
+		checkAnonymous(t, false)
+
+		assert.NoError(t, user_model.SetUserSetting(user.ID, user_model.SettingsKeyPackagesAnonymousRead, "true"))
+
+		t.Run("Public", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			checkAnonymous(t, true)
+		})
+
+		t.Run("Limited", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			setVisibility(t, api.VisibleTypeLimited)
+
+			checkAnonymous(t, false)
+		})
+	})
+}
+
+func TestPackageImmutableVersions(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getTokenForLoggedInUser(t, loginUser(t, user.Name))
+	adminToken := getTokenForLoggedInUser(t, loginUser(t, "user1"))
+
+	url := fmt.Sprintf("/api/packages/%s/generic/test-package/1.0.0", user.Name)
+	apiURL := fmt.Sprintf("/api/v1/packages/%s/generic/test-package/1.0.0", user.Name)
+
+	for _, filename := range []string{"file.bin", "file2.bin"} {
+		req := NewRequestWithBody(t, "PUT", url+"/"+filename, bytes.NewReader([]byte{1}))
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	assert.NoError(t, user_model.SetUserSetting(user.ID, user_model.SettingsKeyPackagesImmutableVersions, "true"))
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", url+"/file.bin")
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "DELETE", url)
+		AddBasicAuthHeader(req, user.Name)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", apiURL, token))
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("ForceDelete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s?force=true&token=%s", apiURL, token))
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s?force=true&token=%s", apiURL, adminToken))
+		MakeRequest(t, req, http.StatusNoContent)
+
+		_, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeGeneric, "test-package", "1.0.0")
+		assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageDumpRestore(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "transfer-package"
+	packageVersion := "1.0.0"
+	filename := "file.bin"
+	content := []byte{1, 2, 3}
+
+	url := fmt.Sprintf("/api/packages/%s/generic/%s/%s/%s", user.Name, packageName, packageVersion, filename)
+
+	req := NewRequestWithBody(t, "PUT", url, bytes.NewReader(content))
+	AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", url)
+	AddBasicAuthHeader(req, user.Name)
+	MakeRequest(t, req, http.StatusOK)
+
+	pv, err := packages.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages.TypeGeneric, packageName, packageVersion)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, pv.DownloadCount)
+
+	var buf bytes.Buffer
+	assert.NoError(t, packages_service.ExportPackages(db.DefaultContext, &buf))
+
+	t.Run("SkipExisting", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		result, err := packages_service.ImportPackages(db.DefaultContext, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.ImportedVersions)
+		assert.Equal(t, 1, result.SkippedVersions)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		assert.NoError(t, packages_service.RemovePackageVersion(db.DefaultContext, user, pv))
+
+		result, err := packages_service.ImportPackages(db.DefaultContext, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedVersions)
+
+		restored, err := packages.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages.TypeGeneric, packageName, packageVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, pv.CreatorID, restored.CreatorID)
+		assert.Equal(t, pv.CreatedUnix, restored.CreatedUnix)
+		assert.Equal(t, pv.DownloadCount, restored.DownloadCount)
+
+		pfs, err := packages.GetFilesByVersionID(db.DefaultContext, restored.ID)
+		assert.NoError(t, err)
+		assert.Len(t, pfs, 1)
+		assert.Equal(t, filename, pfs[0].Name)
+
+		req := NewRequest(t, "GET", url)
+		AddBasicAuthHeader(req, user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		_, err := packages_service.ImportPackages(db.DefaultContext, bytes.NewReader([]byte{1}), 1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullRequestFiles(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+	var files []*api.ChangedFile
+	DecodeJSON(t, resp, &files)
+	assert.Equal(t, []*api.ChangedFile{{
+		Filename:  "iso-8859-1.txt",
+		Status:    "added",
+		Additions: 10,
+		Changes:   10,
+	}}, files)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files?page=2&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	assert.Empty(t, files)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files/hunks?path=iso-8859-1.txt&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var hunks api.ChangedFileHunks
+	DecodeJSON(t, resp, &hunks)
+	assert.Equal(t, "iso-8859-1.txt", hunks.Filename)
+	assert.False(t, hunks.IsIncomplete)
+	if assert.Len(t, hunks.Hunks, 1) {
+		hunk := hunks.Hunks[0]
+		assert.Equal(t, "@@ -0,0 +1,10 @@", hunk.Header)
+		assert.Equal(t, 1, hunk.NewStart)
+		assert.Equal(t, 10, hunk.NewLines)
+		if assert.Len(t, hunk.Lines, 10) {
+			assert.Equal(t, "add", hunk.Lines[0].Type)
+			assert.Equal(t, 1, hunk.Lines[0].NewNumber)
+			assert.Equal(t, "", hunk.Lines[1].Content)
+		}
+	}
+
+	// binary metadata is only added for binary files
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files/hunks?path=iso-8859-1.txt&binary=true&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	hunks = api.ChangedFileHunks{}
+	DecodeJSON(t, resp, &hunks)
+	assert.False(t, hunks.IsBinary)
+	assert.Nil(t, hunks.Binary)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files/hunks?path=README.md&token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/3/files/hunks?token="+token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/forms"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullMergeChecklist(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: 2})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	checklistURL := fmt.Sprintf("/api/v1/repos/%s/%s/merge_checklist", owner.Name, repo.Name)
+	pullChecklistURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/checklist", owner.Name, repo.Name, pr.Index)
+
+	createItem := func(name string) *api.MergeChecklistItem {
+		req := NewRequestWithJSON(t, "POST", checklistURL+"?token="+token, &api.CreateMergeChecklistItemOption{Name: name})
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var item *api.MergeChecklistItem
+		DecodeJSON(t, resp, &item)
+		assert.Equal(t, name, item.Name)
+		return item
+	}
+	docs := createItem("Docs updated")
+	qa := createItem("QA sign-off")
+
+	req := NewRequestWithJSON(t, "POST", checklistURL+"?token="+token, &api.CreateMergeChecklistItemOption{Name: "QA sign-off"})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", checklistURL+"?token="+token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var items []*api.MergeChecklistItem
+	DecodeJSON(t, resp, &items)
+	assert.Len(t, items, 2)
+
+	t.Run("ReadersCannotCheck", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		token4 := getUserToken(t, "user4")
+		req := NewRequest(t, "PUT", fmt.Sprintf("%s/%d?token=%s", pullChecklistURL, docs.ID, token4))
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("MergeBlocked", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", fmt.Sprintf("%s/%d?token=%s", pullChecklistURL, docs.ID, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+		var checklist *api.PullMergeChecklist
+		DecodeJSON(t, resp, &checklist)
+		assert.False(t, checklist.Complete)
+		if assert.Len(t, checklist.Items, 2) {
+			assert.True(t, checklist.Items[0].Checked)
+			assert.Equal(t, owner.Name, checklist.Items[0].CheckedBy.UserName)
+			assert.False(t, checklist.Items[1].Checked)
+		}
+
+		req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner.Name, repo.Name, pr.Index, token), &forms.MergePullRequestForm{
+			Do: string(repo_model.MergeStyleMerge),
+		})
+		MakeRequest(t, req, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("CheckAndUncheck", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", fmt.Sprintf("%s/%d?token=%s", pullChecklistURL, qa.ID, token))
+		resp := MakeRequest(t, req, http.StatusOK)
+		var checklist *api.PullMergeChecklist
+		DecodeJSON(t, resp, &checklist)
+		assert.True(t, checklist.Complete)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", pullChecklistURL, qa.ID, token))
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &checklist)
+		assert.False(t, checklist.Complete)
+
+		req = NewRequest(t, "GET", pullChecklistURL)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &checklist)
+		assert.False(t, checklist.Complete)
+	})
+
+	t.Run("DeleteItem", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%d?token=%s", checklistURL, qa.ID, token))
+		MakeRequest(t, req, http.StatusNoContent)
+		unittest.AssertNotExistsBean(t, &issues_model.MergeChecklistItem{ID: qa.ID})
+
+		req = NewRequest(t, "GET", pullChecklistURL)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var checklist *api.PullMergeChecklist
+		DecodeJSON(t, resp, &checklist)
+		assert.True(t, checklist.Complete)
+
+		req = NewRequest(t, "PUT", fmt.Sprintf("%s/%d?token=%s", pullChecklistURL, qa.ID, token))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullReviewStats(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	assert.NoError(t, pull_service.AggregateReviewStats(db.DefaultContext))
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/stats?since=2000-01-01&until=2000-01-01")
+	resp := MakeRequest(t, req, http.StatusOK)
+
+	var stats *api.PullReviewStats
+	DecodeJSON(t, resp, &stats)
+	assert.Equal(t, "2000-01-01", stats.Since)
+	assert.Equal(t, "2000-01-01", stats.Until)
+	assert.EqualValues(t, 2, stats.Opened)
+	assert.EqualValues(t, 1, stats.TimeToFirstReview.Count)
+	assert.Len(t, stats.Sizes, 5)
+	if assert.NotEmpty(t, stats.Reviewers) {
+		assert.Equal(t, "user1", stats.Reviewers[0].Reviewer.UserName)
+		assert.EqualValues(t, 2, stats.Reviewers[0].Reviews)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/stats?since=2000-01-02&until=2000-01-01")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/stats?since=2000-01-01&until=2002-01-01")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// private repositories are not visible
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo2/pulls/stats")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/pulls/stats")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &stats)
+	assert.NotNil(t, stats.TimeToMerge)
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReleaseWebhookPayload(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+		session := loginUser(t, owner.LowerName)
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/hooks?token=%s", owner.Name, repo.Name, token), &api.CreateHookOption{
+			Type: "gitea",
+			Config: api.CreateHookOptionConfig{
+				"content_type": "json",
+				"url":          "http://example.com/",
+			},
+			Events: []string{"release_published"},
+			Active: true,
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var hook api.Hook
+		DecodeJSON(t, resp, &hook)
+		assert.Contains(t, hook.Events, "release")
+
+		createNewReleaseUsingAPI(t, session, token, owner, repo, "v1.0.0", "master", "v1.0.0", "first")
+
+		_, err := createFileInBranch(owner, repo, "changelog.txt", "master", "changes")
+		assert.NoError(t, err)
+
+		release := createNewReleaseUsingAPI(t, session, token, owner, repo, "v1.1.0", "master", "v1.1.0", "second")
+
+		t.Run("AssetChecksum", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			content := []byte("release asset")
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile("attachment", "asset.bin")
+			_, _ = part.Write(content)
+			_ = writer.Close()
+
+			req := NewRequestWithBody(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d/assets?token=%s", owner.Name, repo.Name, release.ID, token), body)
+			req.Header.Add("Content-Type", writer.FormDataContentType())
+			resp := session.MakeRequest(t, req, http.StatusCreated)
+
+			var attachment api.Attachment
+			DecodeJSON(t, resp, &attachment)
+			sum := sha256.Sum256(content)
+			assert.Equal(t, hex.EncodeToString(sum[:]), attachment.SHA256)
+		})
+
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d?token=%s", owner.Name, repo.Name, release.ID, token), &api.EditReleaseOption{
+			Title: "v1.1.0 edited",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		hookTasks, err := webhook.HookTasks(hook.ID, 1)
+		assert.NoError(t, err)
+		assert.Len(t, hookTasks, 3)
+
+		decodePayload := func(t *testing.T, task *webhook.HookTask) *api.ReleasePayload {
+			var payload api.ReleasePayload
+			assert.NoError(t, json.Unmarshal([]byte(task.PayloadContent), &payload))
+			return &payload
+		}
+
+		t.Run("Published", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			assert.Equal(t, webhook.HookEventReleasePublished, hookTasks[2].EventType)
+			first := decodePayload(t, hookTasks[2])
+			assert.Equal(t, api.HookReleasePublished, first.Action)
+			assert.Equal(t, api.ReleaseChannelStable, first.Channel)
+			assert.NotNil(t, first.Changelog)
+			// the latest release of the fixtures
+			assert.Equal(t, "v1.1", first.Changelog.PreviousTag)
+
+			assert.Equal(t, webhook.HookEventReleasePublished, hookTasks[1].EventType)
+			second := decodePayload(t, hookTasks[1])
+			assert.NotNil(t, second.Changelog)
+			assert.Equal(t, "v1.0.0", second.Changelog.PreviousTag)
+			assert.Contains(t, second.Changelog.CompareURL, "/compare/v1.0.0...v1.1.0")
+			assert.Len(t, second.Changelog.Commits, 1)
+			assert.Contains(t, second.Changelog.Body, second.Changelog.Commits[0].Message)
+		})
+
+		t.Run("Edited", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			assert.Equal(t, webhook.HookEventReleaseEdited, hookTasks[0].EventType)
+			edited := decodePayload(t, hookTasks[0])
+			assert.Equal(t, api.HookReleaseUpdated, edited.Action)
+			assert.Equal(t, "v1.1.0 edited", edited.Release.Title)
+			assert.Len(t, edited.Release.Attachments, 1)
+			assert.NotEmpty(t, edited.Release.Attachments[0].SHA256)
+		})
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
@@ -230,3 +232,53 @@ func TestAPIDeleteReleaseByTagName(t *testing.T) {
 	req = NewRequestf(t, http.MethodDelete, fmt.Sprintf("/api/v1/repos/%s/%s/tags/release-tag?token=%s", owner.Name, repo.Name, token))
 	_ = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPICompareReleases(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+
+	createNewReleaseUsingAPI(t, session, token, owner, repo, "v2.0.0", "master", "v2.0.0", "")
+	resp, err := createFileInBranch(owner, repo, "changelog.txt", "master", "changes")
+	assert.NoError(t, err)
+	createNewReleaseUsingAPI(t, session, token, owner, repo, "v2.1.0", "master", "v2.1.0", "")
+
+	// pretend the merged pull request 1 created the new commit
+	_, err = db.GetEngine(db.DefaultContext).ID(1).Cols("merged_commit_id").
+		Update(&issues_model.PullRequest{MergedCommitID: resp.Commit.SHA})
+	assert.NoError(t, err)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/releases/compare/v2.0.0/v2.1.0?token=%s", owner.Name, repo.Name, token)
+	res := session.MakeRequest(t, req, http.StatusOK)
+	var comparison api.ReleaseComparison
+	DecodeJSON(t, res, &comparison)
+	assert.EqualValues(t, "v2.0.0", comparison.From)
+	assert.EqualValues(t, "v2.1.0", comparison.To)
+	assert.EqualValues(t, resp.Commit.SHA, comparison.ToCommit)
+	assert.EqualValues(t, 1, comparison.TotalCommits)
+	if assert.Len(t, comparison.Commits, 1) {
+		assert.EqualValues(t, resp.Commit.SHA, comparison.Commits[0].SHA)
+	}
+	if assert.Len(t, comparison.PullRequests, 1) {
+		assert.EqualValues(t, 1, comparison.PullRequests[0].ID)
+	}
+	if assert.Len(t, comparison.Contributors, 1) {
+		assert.EqualValues(t, 1, comparison.Contributors[0].Commits)
+		if assert.NotNil(t, comparison.Contributors[0].User) {
+			assert.EqualValues(t, owner.ID, comparison.Contributors[0].User.ID)
+		}
+	}
+
+	// no changes in the other direction
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/releases/compare/v2.1.0/v2.0.0?token=%s", owner.Name, repo.Name, token)
+	res = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, res, &comparison)
+	assert.EqualValues(t, 0, comparison.TotalCommits)
+	assert.Empty(t, comparison.PullRequests)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/releases/compare/v2.0.0/non-existing?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
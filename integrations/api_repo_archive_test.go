@@ -5,17 +5,26 @@
 package integrations
 
 import (
+	"archive/tar"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
 )
 
 func TestAPIDownloadArchive(t *testing.T) {
@@ -47,7 +56,188 @@ func TestAPIDownloadArchive(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, 382, len(bs))
 
-	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master", user2.Name, repo.Name))
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.rar", user2.Name, repo.Name))
 	link.RawQuery = url.Values{"token": {token}}.Encode()
 	MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusBadRequest)
 }
+
+func TestAPIDownloadArchiveRedirect(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getUserToken(t, user2.LowerName)
+
+	archiveURL := fmt.Sprintf("/api/v1/repos/%s/%s/archive/", user2.Name, repo.Name)
+	commitID := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	cases := []struct {
+		Path     string
+		Format   string
+		Location string
+	}{
+		{"master", "", "master.tar.gz"},
+		{"master", "zip", "master.zip"},
+		{"master.zip", "tar.xz", ""},
+		{"65f1bf27bc", "", commitID + ".tar.gz"},
+		{"65f1bf27bc.zip", "", commitID + ".zip"},
+		{"65f1bf27bc.zip.sha256", "", commitID + ".zip.sha256"},
+		{commitID + ".zip", "", ""},
+	}
+	for _, c := range cases {
+		link, _ := url.Parse(archiveURL + c.Path)
+		query := url.Values{"token": {token}}
+		if c.Format != "" {
+			query.Set("format", c.Format)
+		}
+		link.RawQuery = query.Encode()
+
+		if c.Location == "" {
+			MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+			continue
+		}
+
+		resp := MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusFound)
+		assert.Equal(t, fmt.Sprintf("%s%s%s?token=%s", setting.AppURL, archiveURL[1:], c.Location, token), resp.Header().Get("Location"), c.Path)
+	}
+
+	link, _ := url.Parse(archiveURL + "master")
+	link.RawQuery = url.Values{"token": {token}, "format": {"rar"}}.Encode()
+	MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusBadRequest)
+
+	link, _ = url.Parse(archiveURL + "ffffffffff.zip")
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusNotFound)
+}
+
+func TestDownloadArchiveRedirect(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	commitID := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	resp := MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/master"), http.StatusFound)
+	assert.Equal(t, "/user2/repo1/archive/master.tar.gz", resp.Header().Get("Location"))
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/master?format=zip"), http.StatusFound)
+	assert.Equal(t, "/user2/repo1/archive/master.zip", resp.Header().Get("Location"))
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/65f1bf27bc.zip"), http.StatusFound)
+	assert.Equal(t, "/user2/repo1/archive/"+commitID+".zip", resp.Header().Get("Location"))
+
+	MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/master.zip"), http.StatusOK)
+	MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/master.rar"), http.StatusBadRequest)
+	MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/master?format=rar"), http.StatusBadRequest)
+	MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/archive/ffffffffff.zip"), http.StatusNotFound)
+}
+
+func TestAPIDownloadArchiveCompressedTar(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user2.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+
+	readTar := func(t *testing.T, r io.Reader) []string {
+		var names []string
+		tr := tar.NewReader(r)
+		for {
+			hd, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			if err != nil {
+				break
+			}
+			names = append(names, hd.Name)
+		}
+		return names
+	}
+
+	link, _ := url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.tar.zst", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp := MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	zr, err := zstd.NewReader(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, readTar(t, zr), "repo1/README.md")
+	zr.Close()
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.tar.xz", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp = MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	xr, err := xz.NewReader(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, readTar(t, xr), "repo1/README.md")
+}
+
+func TestAPIDownloadArchiveChecksum(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user2.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+
+	link, _ := url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.zip", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp := MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	bs, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	sum256 := sha256.Sum256(bs)
+	sum512 := sha512.Sum512(bs)
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.zip.sha256", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp = MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	assert.Equal(t, hex.EncodeToString(sum256[:])+"  repo1-master.zip\n", resp.Body.String())
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.zip.sha512", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp = MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	assert.Equal(t, hex.EncodeToString(sum512[:])+"  repo1-master.zip\n", resp.Body.String())
+
+	// archive signatures are disabled by default
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.zip.asc", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusNotFound)
+}
+
+func TestAPIArchiveAsync(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user2.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+
+	link, _ := url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.tar.xz", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp := MakeRequest(t, NewRequest(t, "POST", link.String()), NoExpectedStatus)
+	assert.Contains(t, []int{http.StatusOK, http.StatusAccepted}, resp.Code)
+
+	var status *api.RepoArchiveStatus
+	DecodeJSON(t, resp, &status)
+	assert.NotEmpty(t, status.CommitID)
+	assert.Equal(t, fmt.Sprintf("%sapi/v1/repos/%s/%s/archive_status/master.tar.xz", setting.AppURL, user2.Name, repo.Name), status.StatusURL)
+	assert.Equal(t, fmt.Sprintf("%sapi/v1/repos/%s/%s/archive/master.tar.xz", setting.AppURL, user2.Name, repo.Name), status.DownloadURL)
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive_status/master.tar.xz", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	assert.Eventually(t, func() bool {
+		resp := MakeRequest(t, NewRequest(t, "GET", link.String()), NoExpectedStatus)
+		DecodeJSON(t, resp, &status)
+		return resp.Code == http.StatusOK && status.Status == "ready"
+	}, 10*time.Second, 100*time.Millisecond)
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master.tar.xz", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	resp = MakeRequest(t, NewRequest(t, "POST", link.String()), http.StatusOK)
+	DecodeJSON(t, resp, &status)
+	assert.Equal(t, "ready", status.Status)
+
+	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive_status/unknown.zip", user2.Name, repo.Name))
+	link.RawQuery = url.Values{"token": {token}}.Encode()
+	MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusNotFound)
+}
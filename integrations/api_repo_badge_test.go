@@ -0,0 +1,73 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoBadges(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		getBadge := func(t *testing.T, path string) *api.Badge {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/badges/"+path)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var badge *api.Badge
+			DecodeJSON(t, resp, &badge)
+			assert.Equal(t, 1, badge.SchemaVersion)
+			return badge
+		}
+
+		t.Run("Release", func(t *testing.T) {
+			badge := getBadge(t, "release")
+			assert.Equal(t, "release", badge.Label)
+			assert.Equal(t, "v1.1", badge.Message)
+		})
+
+		t.Run("Issues", func(t *testing.T) {
+			badge := getBadge(t, "issues")
+			assert.Equal(t, "issues", badge.Label)
+			assert.Equal(t, "1 open", badge.Message)
+		})
+
+		t.Run("Build", func(t *testing.T) {
+			badge := getBadge(t, "build")
+			assert.Equal(t, "build", badge.Label)
+			assert.Equal(t, "unknown", badge.Message)
+
+			ctx := NewAPITestContext(t, "user2", "repo1")
+			doAPICreateCommitStatus(ctx, "65f1bf27bc3bf70f64657658635e66094edbcb4d", api.CommitStatusSuccess)(t)
+
+			badge = getBadge(t, "build?branch=master")
+			assert.Equal(t, "success", badge.Message)
+			assert.Equal(t, "brightgreen", badge.Color)
+
+			badge = getBadge(t, "build?branch=not-exist")
+			assert.Equal(t, "unknown", badge.Message)
+		})
+
+		t.Run("License", func(t *testing.T) {
+			badge := getBadge(t, "license")
+			assert.Equal(t, "license", badge.Label)
+			assert.Equal(t, "none", badge.Message)
+		})
+
+		t.Run("Package", func(t *testing.T) {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/badges/packages/generic/not-exist")
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("PrivateRepo", func(t *testing.T) {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo2/badges/issues")
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIBlobDiff(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user2"})
+		user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user4"})
+		token2 := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+		token4 := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token4, &api.CreateForkOption{})
+		MakeRequest(t, req, http.StatusAccepted)
+
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", LowerName: "repo1"})
+		fork := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user4", LowerName: "repo1"})
+		_, err := createFileInBranch(user2, repo, "config.ini", "master", "a = 1\nb = 2\nc = 3\n")
+		assert.NoError(t, err)
+		_, err = createFileInBranch(user4, fork, "config.ini", "master", "a = 1\nb = 4\nc = 3\n")
+		assert.NoError(t, err)
+
+		urlStr := "/api/v1/repos/user2/repo1/diff/blobs?token=" + token2
+
+		t.Run("Fork", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master:config.ini&head_repo=user4/repo1")
+			resp := MakeRequest(t, req, http.StatusOK)
+			var diff api.BlobDiff
+			DecodeJSON(t, resp, &diff)
+			assert.Equal(t, "user2/repo1", diff.Base.Repository)
+			assert.Equal(t, "user4/repo1", diff.Head.Repository)
+			assert.Equal(t, "master", diff.Head.Ref)
+			assert.Equal(t, "config.ini", diff.Head.Path)
+			assert.NotEqual(t, diff.Base.SHA, diff.Head.SHA)
+			assert.False(t, diff.Identical)
+			assert.Equal(t, 1, diff.Additions)
+			assert.Equal(t, 1, diff.Deletions)
+			if assert.Len(t, diff.Hunks, 1) {
+				assert.Equal(t, 1, diff.Hunks[0].OldStart)
+				assert.Equal(t, &api.DiffHunkLine{Type: "delete", Content: "b = 2", OldNumber: 2}, diff.Hunks[0].Lines[1])
+				assert.Equal(t, &api.DiffHunkLine{Type: "add", Content: "b = 4", NewNumber: 2}, diff.Hunks[0].Lines[2])
+			}
+
+			req = NewRequest(t, "GET", urlStr+"&base=master:config.ini&head="+diff.Head.SHA+"&head_repo=user4/repo1&format=diff&context=0")
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "--- a/config.ini\n+++ b/"+diff.Head.SHA+"\n@@ -2 +2 @@\n-b = 2\n+b = 4\n")
+		})
+
+		t.Run("Identical", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master:/config.ini")
+			resp := MakeRequest(t, req, http.StatusOK)
+			var diff api.BlobDiff
+			DecodeJSON(t, resp, &diff)
+			assert.True(t, diff.Identical)
+			assert.Empty(t, diff.Hunks)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", urlStr+"&base=master:config.ini")
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+			req = NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master")
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+			req = NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master:missing.ini")
+			MakeRequest(t, req, http.StatusNotFound)
+			req = NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master:README.md&head_repo=user2/repo2")
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+			req = NewRequest(t, "GET", urlStr+"&base=master:config.ini&head=master:config.ini&head_repo=user2/not-existing")
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
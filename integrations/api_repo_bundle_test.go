@@ -0,0 +1,92 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUploadBundle(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx := NewAPITestContext(t, "user2", "bundle-upload")
+
+		resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/master.bundle?token="+ctx.Token), http.StatusOK)
+		bundle, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+
+		uploadBundle := func(t *testing.T, urlStr string, content []byte, expectedStatus int) *httptest.ResponseRecorder {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile("bundle", "repo1.bundle")
+			_, _ = part.Write(content)
+			_ = writer.Close()
+
+			req := NewRequestWithBody(t, "POST", urlStr, body)
+			req.Header.Add("Content-Type", writer.FormDataContentType())
+			return MakeRequest(t, req, expectedStatus)
+		}
+
+		t.Run("CreateRepo", doAPICreateRepository(ctx, true))
+		bundleURL := "/api/v1/repos/user2/bundle-upload/bundle?token=" + ctx.Token
+
+		t.Run("Initialize", func(t *testing.T) {
+			resp := uploadBundle(t, bundleURL, bundle, http.StatusOK)
+			var updates []*api.BundleRefUpdate
+			DecodeJSON(t, resp, &updates)
+			if assert.Len(t, updates, 1) {
+				assert.Equal(t, "refs/heads/bundle", updates[0].Ref)
+				assert.Empty(t, updates[0].OldSHA)
+				assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", updates[0].NewSHA)
+			}
+
+			repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", LowerName: "bundle-upload"})
+			gitRepo, err := git.OpenRepository(git.DefaultContext, repo.RepoPath())
+			assert.NoError(t, err)
+			defer gitRepo.Close()
+			commitID, err := gitRepo.GetBranchCommitID("bundle")
+			assert.NoError(t, err)
+			assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", commitID)
+		})
+
+		t.Run("Unchanged", func(t *testing.T) {
+			resp := uploadBundle(t, bundleURL, bundle, http.StatusOK)
+			var updates []*api.BundleRefUpdate
+			DecodeJSON(t, resp, &updates)
+			assert.Empty(t, updates)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			uploadBundle(t, bundleURL, []byte("not a bundle"), http.StatusUnprocessableEntity)
+		})
+
+		t.Run("TooLarge", func(t *testing.T) {
+			defer func(maxSize int64) {
+				setting.Repository.Upload.BundleMaxSize = maxSize
+			}(setting.Repository.Upload.BundleMaxSize)
+			setting.Repository.Upload.BundleMaxSize = 0
+
+			uploadBundle(t, bundleURL, bundle, http.StatusRequestEntityTooLarge)
+		})
+
+		t.Run("NoWriteAccess", func(t *testing.T) {
+			token := getUserToken(t, "user4")
+			uploadBundle(t, "/api/v1/repos/user2/repo1/bundle?token="+token, bundle, http.StatusForbidden)
+		})
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCollaborationExpiry(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	token := getUserToken(t, owner.Name)
+	collaboratorURL := fmt.Sprintf("/api/v1/repos/%s/%s/collaborators/%s?token=%s", owner.Name, repo.Name, user4.Name, token)
+	collaborationsURL := fmt.Sprintf("/api/v1/repos/%s/%s/collaborations?token=%s", owner.Name, repo.Name, token)
+
+	listCollaborations := func(t *testing.T, url string) []*api.Collaboration {
+		req := NewRequest(t, "GET", url)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var collaborations []*api.Collaboration
+		DecodeJSON(t, resp, &collaborations)
+		return collaborations
+	}
+
+	t.Run("PastExpiry", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		expires := time.Now().Add(-time.Hour)
+		req := NewRequestWithJSON(t, "PUT", collaboratorURL, &api.AddCollaboratorOption{ExpiresAt: &expires})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		unittest.AssertNotExistsBean(t, &repo_model.Collaboration{RepoID: repo.ID, UserID: user4.ID})
+	})
+
+	expires := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	t.Run("AddWithExpiry", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", collaboratorURL, &api.AddCollaboratorOption{ExpiresAt: &expires})
+		MakeRequest(t, req, http.StatusNoContent)
+
+		collaborations := listCollaborations(t, collaborationsURL)
+		if assert.Len(t, collaborations, 1) {
+			assert.Equal(t, user4.Name, collaborations[0].User.UserName)
+			assert.Nil(t, collaborations[0].Repository)
+			assert.Equal(t, "write", collaborations[0].Permission)
+			if assert.NotNil(t, collaborations[0].ExpiresAt) {
+				assert.Equal(t, expires.Unix(), collaborations[0].ExpiresAt.Unix())
+			}
+			assert.False(t, collaborations[0].Reminded)
+		}
+
+		// updating the permission keeps the expiry
+		permission := "read"
+		req = NewRequestWithJSON(t, "PUT", collaboratorURL, &api.AddCollaboratorOption{Permission: &permission})
+		MakeRequest(t, req, http.StatusNoContent)
+
+		collaboration := unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: repo.ID, UserID: user4.ID})
+		assert.EqualValues(t, expires.Unix(), collaboration.ExpiresUnix)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		assert.Len(t, listCollaborations(t, collaborationsURL+"&only_expiring=true"), 1)
+		assert.Len(t, listCollaborations(t, collaborationsURL+"&expires_before="+expires.Add(time.Hour).Format(time.RFC3339)), 1)
+		assert.Empty(t, listCollaborations(t, collaborationsURL+"&expires_before="+expires.Add(-time.Hour).Format(time.RFC3339)))
+
+		req := NewRequest(t, "GET", collaborationsURL+"&expires_before=tomorrow")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestf(t, "GET", "/api/v1/admin/collaborations?only_expiring=true&token=%s", token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		collaborations := listCollaborations(t, fmt.Sprintf("/api/v1/admin/collaborations?only_expiring=true&token=%s", getUserToken(t, "user1")))
+		if assert.Len(t, collaborations, 1) && assert.NotNil(t, collaborations[0].Repository) {
+			assert.Equal(t, repo.FullName(), collaborations[0].Repository.FullName)
+		}
+	})
+
+	t.Run("RemoveExpiry", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", collaboratorURL, &api.AddCollaboratorOption{ExpiresAt: &time.Time{}})
+		MakeRequest(t, req, http.StatusNoContent)
+
+		collaborations := listCollaborations(t, collaborationsURL)
+		if assert.Len(t, collaborations, 1) {
+			assert.Nil(t, collaborations[0].ExpiresAt)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/models/webhook"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoConfig(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/user2/repo1/config?token=" + token
+
+	t.Run("Export", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", urlStr)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var config api.RepoConfig
+		DecodeJSON(t, resp, &config)
+		if assert.NotNil(t, config.Settings) && assert.NotNil(t, config.Settings.HasIssues) {
+			assert.True(t, *config.Settings.HasIssues)
+		}
+		assert.Nil(t, config.Settings.Name)
+		if assert.Len(t, config.Labels, 2) {
+			assert.Equal(t, "label1", config.Labels[0].Name)
+			assert.Equal(t, "#abcdef", config.Labels[0].Color)
+		}
+		assert.Len(t, config.Webhooks, 2)
+		assert.NotNil(t, config.BranchProtections)
+		assert.NotNil(t, config.Collaborators)
+
+		req = NewRequest(t, "GET", urlStr+"&format=yaml")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Header().Get("Content-Type"), "yaml")
+		assert.Contains(t, resp.Body.String(), "- name: label1\n")
+	})
+
+	t.Run("ApplyYAML", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		doc := `
+labels:
+  - name: label1
+    color: "#112233"
+  - name: bug
+    color: ee0701
+    description: Something is not working
+branch_protections:
+  - branch_name: master
+    required_approvals: 1
+collaborators:
+  - username: user4
+    permission: write
+`
+		for i := 0; i < 2; i++ {
+			req := NewRequestWithBody(t, "PUT", urlStr, bytes.NewBufferString(doc))
+			req.Header.Set("Content-Type", "application/x-yaml")
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			var config api.RepoConfig
+			DecodeJSON(t, resp, &config)
+			assert.Len(t, config.Labels, 2)
+			assert.Len(t, config.BranchProtections, 1)
+			assert.Len(t, config.Collaborators, 1)
+		}
+
+		// existing items are updated in place, items which are not listed are removed
+		label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+		assert.Equal(t, "#112233", label.Color)
+		unittest.AssertNotExistsBean(t, &issues_model.Label{ID: 2})
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: 1, Name: "bug", Color: "#ee0701"})
+		protection := unittest.AssertExistsAndLoadBean(t, &git_model.ProtectedBranch{RepoID: 1, BranchName: "master"})
+		assert.EqualValues(t, 1, protection.RequiredApprovals)
+		unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: 1, UserID: 4, Mode: perm.AccessModeWrite})
+
+		// omitted sections are left untouched
+		unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{ID: 1, RepoID: 1})
+		unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{ID: 2, RepoID: 1})
+	})
+
+	t.Run("ApplyJSON", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		protection := unittest.AssertExistsAndLoadBean(t, &git_model.ProtectedBranch{RepoID: 1, BranchName: "master"})
+
+		req := NewRequestWithJSON(t, "PUT", urlStr, &api.RepoConfig{
+			BranchProtections: []*api.CreateBranchProtectionOption{{
+				BranchName:        "master",
+				RequiredApprovals: 2,
+			}},
+			Webhooks: []*api.CreateHookOption{{
+				Type: "gitea",
+				Config: api.CreateHookOptionConfig{
+					"content_type": "json",
+					"url":          "http://example.com/config",
+				},
+				Active: true,
+			}},
+			Collaborators: []*api.RepoConfigCollaborator{},
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		updated := unittest.AssertExistsAndLoadBean(t, &git_model.ProtectedBranch{ID: protection.ID})
+		assert.EqualValues(t, 2, updated.RequiredApprovals)
+		unittest.AssertNotExistsBean(t, &webhook.Webhook{ID: 1})
+		unittest.AssertNotExistsBean(t, &webhook.Webhook{ID: 2})
+		unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: 1, URL: "http://example.com/config"})
+		unittest.AssertNotExistsBean(t, &repo_model.Collaboration{RepoID: 1, UserID: 4})
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: 1, Name: "bug"})
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", urlStr, &api.RepoConfig{
+			Collaborators: []*api.RepoConfigCollaborator{{Username: "user4", Permission: "owner"}},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.RepoConfig{
+			Labels: []*api.CreateLabelOption{{Name: "bug", Color: "#000000"}, {Name: "bug", Color: "#ffffff"}},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+
+		// a later invalid section must not leave the earlier sections applied
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.RepoConfig{
+			Labels:            []*api.CreateLabelOption{{Name: "config", Color: "#000000"}},
+			BranchProtections: []*api.CreateBranchProtectionOption{{BranchName: "master", PushWhitelistUsernames: []string{"user-not-exist"}}},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.RepoConfig{
+			Labels:        []*api.CreateLabelOption{{Name: "config", Color: "#000000"}},
+			Collaborators: []*api.RepoConfigCollaborator{{Username: "user-not-exist", Permission: "write"}},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+		unittest.AssertNotExistsBean(t, &issues_model.Label{RepoID: 1, Name: "config"})
+
+		otherSession := loginUser(t, "user4")
+		otherToken := getTokenForLoggedInUser(t, otherSession)
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/config?token="+otherToken)
+		otherSession.MakeRequest(t, req, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetFileOutline(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/outline/README.md?ref=master")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var outline *api.FileOutline
+	DecodeJSON(t, resp, &outline)
+	assert.Equal(t, "README.md", outline.Path)
+	assert.Equal(t, "4b4851ad51df6a7d9f25c979345979eaeb5b349f", outline.SHA)
+	assert.Equal(t, "Markdown", outline.Language)
+	assert.Equal(t, []*api.OutlineSymbol{
+		{Name: "repo1", Kind: "heading", Line: 1, Children: []*api.OutlineSymbol{}},
+	}, outline.Symbols)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/outline/not-existing.md")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the file is shown with an outline
+	req = NewRequest(t, "GET", "/user2/repo1/src/branch/master/README.md?display=source")
+	resp = MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "#L1", htmlDoc.doc.Find(".file-outline-menu a.item").AttrOr("href", ""))
+}
@@ -110,6 +110,35 @@ func TestAPIReposGitCommitListDifferentBranch(t *testing.T) {
 	compareCommitFiles(t, []string{"readme.md"}, apiData[0].Files)
 }
 
+func TestAPIReposGitCommitListExcluded(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	// Login as User2.
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// Test getting the commits of master which are not reachable from its first commit
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token="+token+"&sha=master&not=5099b81332712fe655e34e8dd63574f503f61811&limit=1", user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var apiData []api.Commit
+	DecodeJSON(t, resp, &apiData)
+
+	assert.Len(t, apiData, 1)
+	assert.EqualValues(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", apiData[0].CommitMeta.SHA)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token="+token+"&sha=master&not=5099b81332712fe655e34e8dd63574f503f61811&limit=1&page=2", user.Name)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiData)
+
+	assert.Len(t, apiData, 1)
+	assert.EqualValues(t, "27566bd5738fc8b4e3fef3c5e72cce608537bd95", apiData[0].CommitMeta.SHA)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token="+token+"&not=0000000000000000000000000000000000000001", user.Name)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
+
 func TestDownloadCommitDiffOrPatch(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
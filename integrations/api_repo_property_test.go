@@ -0,0 +1,88 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoProperties(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/properties?token="+token, &api.CreatePropertyDefinitionOption{
+		Name:          "Service-Tier",
+		Description:   "support level of the service",
+		AllowedValues: []string{"gold", "silver", "gold"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var def api.PropertyDefinition
+	DecodeJSON(t, resp, &def)
+	assert.Equal(t, "service-tier", def.Name)
+	assert.Equal(t, []string{"gold", "silver"}, def.AllowedValues)
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/properties?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var defs []*api.PropertyDefinition
+	DecodeJSON(t, resp, &defs)
+	assert.Len(t, defs, 1)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/repo3/properties?token="+token, &api.EditRepoPropertiesOption{
+		Properties: map[string]string{"service-tier": "bronze"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/repo3/properties?token="+token, &api.EditRepoPropertiesOption{
+		Properties: map[string]string{"cost-center": "4711"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/repo3/properties?token="+token, &api.EditRepoPropertiesOption{
+		Properties: map[string]string{"service-tier": "gold"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var props map[string]string
+	DecodeJSON(t, resp, &props)
+	assert.Equal(t, map[string]string{"service-tier": "gold"}, props)
+
+	// the properties are part of the repository, e.g. in webhook payloads
+	req = NewRequest(t, "GET", "/api/v1/repos/user3/repo3?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.Equal(t, map[string]string{"service-tier": "gold"}, repo.Properties)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/search?property=service-tier:gold&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var result api.SearchResults
+	DecodeJSON(t, resp, &result)
+	if assert.Len(t, result.Data, 1) {
+		assert.Equal(t, "repo3", result.Data[0].Name)
+	}
+	req = NewRequest(t, "GET", "/api/v1/repos/search?property=service-tier:silver&token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &result)
+	assert.Empty(t, result.Data)
+	req = NewRequest(t, "GET", "/api/v1/repos/search?property=service-tier&token="+token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "DELETE", "/api/v1/orgs/user3/properties/service-tier?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &repo_model.RepoProperty{Name: "service-tier"})
+
+	// only owners may define properties
+	otherSession := loginUser(t, "user4")
+	otherToken := getTokenForLoggedInUser(t, otherSession)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/properties?token="+otherToken, &api.CreatePropertyDefinitionOption{Name: "cost-center"})
+	otherSession.MakeRequest(t, req, http.StatusForbidden)
+}
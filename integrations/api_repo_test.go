@@ -5,18 +5,23 @@
 package integrations
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -458,6 +463,95 @@ func TestAPIOrgRepoCreate(t *testing.T) {
 	}
 }
 
+func TestAPIOrgRepoCreateProvisioned(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		url := fmt.Sprintf("/api/v1/orgs/user3/repos?token=%s", token)
+
+		t.Run("Provisioned", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "POST", url, &api.CreateRepoOption{
+				Name:           "repo-provisioned",
+				DefaultBranch:  "main",
+				ReadmeContent:  "# Provisioned",
+				LicenseContent: "Custom License",
+				Files: []*api.CreateRepoFileOption{
+					{Path: "docs/index.md", Content: base64.StdEncoding.EncodeToString([]byte("Documentation"))},
+				},
+				Labels: []*api.CreateLabelOption{
+					{Name: "provisioned", Color: "ee0701"},
+				},
+				BranchProtections: []*api.CreateBranchProtectionOption{
+					{BranchName: "main", RequiredApprovals: 1},
+				},
+				Webhooks: []*api.CreateHookOption{
+					{
+						Type:   "gitea",
+						Config: api.CreateHookOptionConfig{"url": "http://example.com/", "content_type": "json"},
+						Active: true,
+					},
+				},
+			})
+			resp := session.MakeRequest(t, req, http.StatusCreated)
+
+			var repo api.Repository
+			DecodeJSON(t, resp, &repo)
+			assert.False(t, repo.Empty)
+			assert.Equal(t, "main", repo.DefaultBranch)
+
+			for treePath, content := range map[string]string{
+				"README.md":     "# Provisioned",
+				"LICENSE":       "Custom License",
+				"docs/index.md": "Documentation",
+			} {
+				req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo-provisioned/raw/%s?token=%s", treePath, token)
+				resp = session.MakeRequest(t, req, http.StatusOK)
+				assert.Equal(t, content, resp.Body.String())
+			}
+
+			unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: repo.ID, Name: "provisioned", Color: "#ee0701"})
+			pb := unittest.AssertExistsAndLoadBean(t, &git_model.ProtectedBranch{RepoID: repo.ID, BranchName: "main"})
+			assert.EqualValues(t, 1, pb.RequiredApprovals)
+			unittest.AssertExistsAndLoadBean(t, &webhook.Webhook{RepoID: repo.ID, URL: "http://example.com/"})
+		})
+
+		t.Run("InvalidFilePath", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "POST", url, &api.CreateRepoOption{
+				Name: "repo-invalid-file",
+				Files: []*api.CreateRepoFileOption{
+					{Path: ".git/config", Content: ""},
+				},
+			})
+			session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			unittest.AssertNotExistsBean(t, &repo_model.Repository{OwnerName: "user3", LowerName: "repo-invalid-file"})
+		})
+
+		t.Run("Rollback", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "POST", url, &api.CreateRepoOption{
+				Name:          "repo-rollback",
+				AutoInit:      true,
+				DefaultBranch: "main",
+				Labels: []*api.CreateLabelOption{
+					{Name: "provisioned", Color: "ee0701"},
+				},
+				BranchProtections: []*api.CreateBranchProtectionOption{
+					{BranchName: "missing"},
+				},
+			})
+			session.MakeRequest(t, req, http.StatusNotFound)
+
+			unittest.AssertNotExistsBean(t, &repo_model.Repository{OwnerName: "user3", LowerName: "repo-rollback"})
+		})
+	})
+}
+
 func TestAPIRepoCreateConflict(t *testing.T) {
 	onGiteaRun(t, testAPIRepoCreateConflict)
 }
@@ -627,6 +721,98 @@ func TestAPIRejectTransfer(t *testing.T) {
 	assert.Equal(t, "user2", apiRepo.Owner.UserName)
 }
 
+func TestAPIScheduledTransfer(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/user/repos?token=%s", token), &api.CreateRepoOption{
+		Name:     "scheduled-transfer",
+		AutoInit: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	apiRepo := new(api.Repository)
+	DecodeJSON(t, resp, apiRepo)
+	transferURL := fmt.Sprintf("/api/v1/repos/%s/%s/transfer", user.Name, apiRepo.Name)
+
+	past := time.Now().Add(-time.Hour)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", transferURL, token), &api.TransferRepoOption{
+		NewOwner:    "user4",
+		ScheduledAt: &past,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	scheduled := time.Now().Add(time.Hour).Truncate(time.Second)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s?token=%s", transferURL, token), &api.TransferRepoOption{
+		NewOwner:    "user4",
+		ScheduledAt: &scheduled,
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+
+	// the new owner lists the pending transfer
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/user/transfers?token=%s", token4))
+	resp = session4.MakeRequest(t, req, http.StatusOK)
+	var transfers []*api.RepoTransfer
+	DecodeJSON(t, resp, &transfers)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, apiRepo.ID, transfers[0].Repository.ID)
+	assert.Equal(t, "user4", transfers[0].Recipient.UserName)
+	assert.True(t, scheduled.Equal(*transfers[0].ScheduledAt))
+	assert.Nil(t, transfers[0].AcceptedAt)
+
+	// accepting a scheduled transfer doesn't execute it
+	req = NewRequest(t, "POST", fmt.Sprintf("%s/accept?token=%s", transferURL, token4))
+	resp = session4.MakeRequest(t, req, http.StatusAccepted)
+	DecodeJSON(t, resp, apiRepo)
+	assert.Equal(t, user.Name, apiRepo.Owner.UserName)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", transferURL, token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	apiTransfer := new(api.RepoTransfer)
+	DecodeJSON(t, resp, apiTransfer)
+	assert.NotNil(t, apiTransfer.AcceptedAt)
+
+	// removing the schedule executes the accepted transfer
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s?token=%s", transferURL, token), &api.EditRepoTransferOption{})
+	resp = session.MakeRequest(t, req, http.StatusAccepted)
+	DecodeJSON(t, resp, apiRepo)
+	assert.Equal(t, "user4", apiRepo.Owner.UserName)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/user/transfers?token=%s", token4))
+	resp = session4.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &transfers)
+	assert.Empty(t, transfers)
+}
+
+func TestAPICancelTransfer(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := transfer(t)
+	transferURL := fmt.Sprintf("/api/v1/repos/%s/%s/transfer", repo.OwnerName, repo.Name)
+
+	// the new owner can't cancel the transfer
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", transferURL, token))
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s?token=%s", transferURL, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", transferURL, token))
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo.ID})
+	assert.Equal(t, repo_model.RepositoryReady, repo.Status)
+}
+
 func TestAPIGenerateRepo(t *testing.T) {
 	defer prepareTestEnv(t)()
 
@@ -0,0 +1,116 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGlobalSearch(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	t.Run("Anonymous", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/search?q=repo2&type=repo&type=user")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var results *api.GlobalSearchResults
+		DecodeJSON(t, resp, &results)
+		assert.Len(t, results.Counts, 2)
+		assert.Contains(t, results.Counts, "repo")
+		assert.Contains(t, results.Counts, "user")
+		assert.Nil(t, results.Issues)
+		assert.Nil(t, results.Packages)
+		for _, repo := range results.Repositories {
+			assert.False(t, repo.Private)
+			assert.NotEqual(t, "user2/repo2", repo.FullName)
+		}
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		token := getUserToken(t, "user2")
+		req := NewRequest(t, "GET", "/api/v1/search?q=repo2&type=repo&token="+token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var results *api.GlobalSearchResults
+		DecodeJSON(t, resp, &results)
+		assert.Len(t, results.Counts, 1)
+		assert.EqualValues(t, len(results.Repositories), results.Counts["repo"])
+		found := false
+		for _, repo := range results.Repositories {
+			if repo.FullName == "user2/repo2" {
+				found = true
+			}
+		}
+		assert.True(t, found, "private repository of the owner is found")
+	})
+
+	t.Run("PackagesOfLimitedOwner", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		// the packages of the limited organization are only visible to restricted users which are members
+		p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+			OwnerID:   22,
+			Type:      packages_model.TypeGeneric,
+			Name:      "limited-package",
+			LowerName: "limited-package",
+		})
+		assert.NoError(t, err)
+		_, err = packages_model.GetOrInsertVersion(db.DefaultContext, &packages_model.PackageVersion{
+			PackageID:    p.ID,
+			CreatorID:    1,
+			Version:      "1.0.0",
+			LowerVersion: "1.0.0",
+			MetadataJSON: "null",
+		})
+		assert.NoError(t, err)
+
+		search := func(t *testing.T, username string) []*api.Package {
+			req := NewRequest(t, "GET", "/api/v1/search?q=limited-package&type=package&token="+getUserToken(t, username))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var results *api.GlobalSearchResults
+			DecodeJSON(t, resp, &results)
+			return results.Packages
+		}
+
+		assert.Len(t, search(t, "user2"), 1)
+		// user29 is restricted and no member of limited_org
+		assert.Empty(t, search(t, "user29"))
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/search?q=user&type=user&limit=2")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var results *api.GlobalSearchResults
+		DecodeJSON(t, resp, &results)
+		assert.Len(t, results.Users, 2)
+		assert.Greater(t, results.Counts["user"], int64(2))
+		assert.Contains(t, resp.Header().Get("Link"), `rel="next"`)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/search?q=repo&type=unknown")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", "/api/v1/search?q=")
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
@@ -6,8 +6,14 @@ package integrations
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
@@ -91,3 +97,71 @@ func TestDownloadRawTextFileWithMimeTypeMapping(t *testing.T) {
 	delete(setting.MimeTypeMap.Map, ".xml")
 	setting.MimeTypeMap.Enabled = false
 }
+
+func TestDownloadImmutableBlob(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	t.Run("Public", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/user2/repo1/raw/blob/4b4851ad51df6a7d9f25c979345979eaeb5b349f/README.md")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		assert.Equal(t, "# repo1\n\nDescription for repo1", resp.Body.String())
+		assert.Equal(t, "public, max-age=31536000, immutable", resp.Header().Get("Cache-Control"))
+		assert.Equal(t, `"4b4851ad51df6a7d9f25c979345979eaeb5b349f"`, resp.Header().Get("ETag"))
+
+		req = NewRequest(t, "GET", "/user2/repo1/raw/blob/4b4851ad51df6a7d9f25c979345979eaeb5b349f/README.md")
+		req.Header.Set("If-None-Match", `"4b4851ad51df6a7d9f25c979345979eaeb5b349f"`)
+		MakeRequest(t, req, http.StatusNotModified)
+
+		// abbreviated hashes are not immutable
+		req = NewRequest(t, "GET", "/user2/repo1/raw/blob/4b4851ad51df/README.md")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		link := "/user2/repo2/raw/blob/6395b68e1feebb1e4c657b4f9f6ba2676a283c0b/image.svg"
+
+		req := NewRequest(t, "GET", link)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		session := loginUser(t, "user2")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "private, max-age=31536000, immutable", resp.Header().Get("Cache-Control"))
+		assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		defer func(expiry time.Duration) {
+			setting.Repository.Raw.SignedURLExpiry = expiry
+		}(setting.Repository.Raw.SignedURLExpiry)
+		setting.Repository.Raw.SignedURLExpiry = time.Hour
+
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+
+		link := strings.TrimPrefix(repo_module.ImmutableRawLink(repo, "6395b68e1feebb1e4c657b4f9f6ba2676a283c0b", "image.svg"), strings.TrimSuffix(setting.AppURL, "/"))
+		assert.Contains(t, link, "signature=")
+
+		req := NewRequest(t, "GET", link)
+		resp := MakeRequest(t, req, http.StatusOK)
+		// the response must not be cached longer than the signature is valid
+		cacheControl := resp.Header().Get("Cache-Control")
+		assert.True(t, strings.HasPrefix(cacheControl, "private, max-age="), cacheControl)
+		maxAge, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(cacheControl, "private, max-age="), ", immutable"))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, maxAge, 3600)
+		assert.Greater(t, maxAge, 3500)
+
+		// the signature is only valid for the signed blob
+		req = NewRequest(t, "GET", strings.Replace(link, "6395b68e1feebb1e4c657b4f9f6ba2676a283c0b", "4b4851ad51df6a7d9f25c979345979eaeb5b349f", 1))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", strings.Replace(link, "signature=", "signature=0", 1))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
@@ -12,6 +12,10 @@ import (
 	"strings"
 	"testing"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -160,3 +164,29 @@ func TestPullBranchDelete(t *testing.T) {
 		session.MakeRequest(t, req, http.StatusOK)
 	})
 }
+
+func TestPullCreate_TemplateChooser(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		_, err := createFileInBranch(user2, repo1, ".gitea/PULL_REQUEST_TEMPLATE/hotfix.md", "master",
+			"---\nname: Hotfix\nabout: Urgent fixes\n---\nHotfix of ${HeadBranch} into ${BaseBranch}, keeps $HOME and ${Unknown}\n")
+		assert.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		testEditFileToNewBranch(t, session, "user2", "repo1", "master", "hotfix-1", "README.md", "Hotfix\n")
+
+		req := NewRequest(t, "GET", "/user2/repo1/compare/master...hotfix-1")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		link, exists := htmlDoc.doc.Find(".pull-request-template .menu a.item").Attr("href")
+		assert.True(t, exists)
+		assert.Equal(t, "/user2/repo1/compare/master...hotfix-1?template=hotfix.md", link)
+		assert.NotContains(t, htmlDoc.doc.Find("textarea[name=content]").Text(), "Hotfix of")
+
+		req = NewRequest(t, "GET", link)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find("textarea[name=content]").Text(), "Hotfix of hotfix-1 into master, keeps $HOME and ${Unknown}")
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoHealthFilesFromMetaRepo(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		metaRepo, err := repo_service.CreateRepository(user, user, repo_module.CreateRepoOptions{
+			Name:          context.MetaRepoName,
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "main",
+			InitialFiles: map[string][]byte{
+				"SECURITY.md":                       []byte("# Security policy"),
+				".gitea/ISSUE_TEMPLATE/question.md": []byte("---\nname: Question\nabout: Ask a question\n---\nQuestion"),
+				".github/FUNDING.yml":               []byte("liberapay: gitea\n"),
+			},
+		})
+		assert.NoError(t, err)
+
+		t.Run("Home", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", "/user2/repo1"), http.StatusOK)
+			body := resp.Body.String()
+			assert.Contains(t, body, "/user2/.gitea/src/branch/main/SECURITY.md")
+			assert.Contains(t, body, "https://liberapay.com/gitea")
+		})
+
+		t.Run("IssueTemplates", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issue_templates"), http.StatusOK)
+
+			var templates []api.IssueTemplate
+			DecodeJSON(t, resp, &templates)
+			assert.Len(t, templates, 1)
+			assert.Equal(t, "question.md", templates[0].FileName)
+		})
+
+		t.Run("OwnFilesTakePrecedence", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			repo, err := repo_service.CreateRepository(user, user, repo_module.CreateRepoOptions{
+				Name:          "health-files",
+				AutoInit:      true,
+				Readme:        "Default",
+				DefaultBranch: "main",
+				InitialFiles: map[string][]byte{
+					"docs/SECURITY.md": []byte("# Own security policy"),
+				},
+			})
+			assert.NoError(t, err)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", repo.Link()), http.StatusOK)
+			body := resp.Body.String()
+			assert.Contains(t, body, "/user2/health-files/src/branch/main/docs/SECURITY.md")
+			assert.NotContains(t, body, "/user2/.gitea/src/branch/main/SECURITY.md")
+		})
+
+		t.Run("PrivateMetaRepo", func(t *testing.T) {
+			defer PrintCurrentTest(t)()
+
+			metaRepo.IsPrivate = true
+			assert.NoError(t, repo_model.UpdateRepositoryCols(db.DefaultContext, metaRepo, "is_private"))
+
+			resp := MakeRequest(t, NewRequest(t, "GET", "/user2/repo1"), http.StatusOK)
+			assert.NotContains(t, resp.Body.String(), "/user2/.gitea/src/branch/main/SECURITY.md")
+		})
+	})
+}
@@ -75,6 +75,8 @@ arguments - which can alternatively be run by running the subcommand web.`
 		cmd.CmdDocs,
 		cmd.CmdDumpRepository,
 		cmd.CmdRestoreRepository,
+		cmd.CmdDumpPackages,
+		cmd.CmdRestorePackages,
 	}
 	// Now adjust these commands to add our global configuration options
 
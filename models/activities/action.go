@@ -66,6 +66,53 @@ const (
 	ActionPullRequestReadyForReview                       // 26
 )
 
+var actionTypeNames = map[ActionType]string{
+	ActionCreateRepo:                "create_repo",
+	ActionRenameRepo:                "rename_repo",
+	ActionStarRepo:                  "star_repo",
+	ActionWatchRepo:                 "watch_repo",
+	ActionCommitRepo:                "commit_repo",
+	ActionCreateIssue:               "create_issue",
+	ActionCreatePullRequest:         "create_pull_request",
+	ActionTransferRepo:              "transfer_repo",
+	ActionPushTag:                   "push_tag",
+	ActionCommentIssue:              "comment_issue",
+	ActionMergePullRequest:          "merge_pull_request",
+	ActionCloseIssue:                "close_issue",
+	ActionReopenIssue:               "reopen_issue",
+	ActionClosePullRequest:          "close_pull_request",
+	ActionReopenPullRequest:         "reopen_pull_request",
+	ActionDeleteTag:                 "delete_tag",
+	ActionDeleteBranch:              "delete_branch",
+	ActionMirrorSyncPush:            "mirror_sync_push",
+	ActionMirrorSyncCreate:          "mirror_sync_create",
+	ActionMirrorSyncDelete:          "mirror_sync_delete",
+	ActionApprovePullRequest:        "approve_pull_request",
+	ActionRejectPullRequest:         "reject_pull_request",
+	ActionCommentPull:               "comment_pull",
+	ActionPublishRelease:            "publish_release",
+	ActionPullReviewDismissed:       "pull_review_dismissed",
+	ActionPullRequestReadyForReview: "pull_request_ready_for_review",
+}
+
+// String returns the name of the action type
+func (at ActionType) String() string {
+	if name, ok := actionTypeNames[at]; ok {
+		return name
+	}
+	return "action-" + strconv.Itoa(int(at))
+}
+
+// ActionTypeFromString returns the action type with the given name
+func ActionTypeFromString(name string) (ActionType, bool) {
+	for at, n := range actionTypeNames {
+		if n == name {
+			return at, true
+		}
+	}
+	return 0, false
+}
+
 // Action represents user operation type and other information to
 // repository. It implemented interface base.Actioner so that can be
 // used in template render.
@@ -324,6 +371,9 @@ type GetFeedsOptions struct {
 	OnlyPerformedBy bool                   // only actions performed by requested user
 	IncludeDeleted  bool                   // include deleted actions
 	Date            string                 // the day we want activity for: YYYY-MM-DD
+	OpTypes         []ActionType           // only actions of these types
+	CreatedAfter    int64                  // only actions created after this unix timestamp
+	CreatedBefore   int64                  // only actions created before this unix timestamp
 }
 
 // GetFeeds returns actions according to the provided options
@@ -357,6 +407,22 @@ func GetFeeds(ctx context.Context, opts GetFeedsOptions) (ActionList, error) {
 	return actions, nil
 }
 
+// CountFeeds returns the number of actions according to the provided options
+func CountFeeds(ctx context.Context, opts GetFeedsOptions) (int64, error) {
+	if opts.RequestedUser == nil && opts.RequestedTeam == nil && opts.RequestedRepo == nil {
+		return 0, fmt.Errorf("need at least one of these filters: RequestedUser, RequestedTeam, RequestedRepo")
+	}
+
+	cond, err := activityQueryCondition(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.GetEngine(ctx).Where(cond).
+		Join("INNER", "repository", "`repository`.id = `action`.repo_id").
+		Count(new(Action))
+}
+
 // ActivityReadable return whether doer can read activities of user
 func ActivityReadable(user, doer *user_model.User) bool {
 	return !user.KeepActivityPrivate ||
@@ -423,6 +489,16 @@ func activityQueryCondition(opts GetFeedsOptions) (builder.Cond, error) {
 		cond = cond.And(builder.Eq{"is_deleted": false})
 	}
 
+	if len(opts.OpTypes) > 0 {
+		cond = cond.And(builder.In("`action`.op_type", opts.OpTypes))
+	}
+	if opts.CreatedAfter != 0 {
+		cond = cond.And(builder.Gte{"`action`.created_unix": opts.CreatedAfter})
+	}
+	if opts.CreatedBefore != 0 {
+		cond = cond.And(builder.Lte{"`action`.created_unix": opts.CreatedBefore})
+	}
+
 	if opts.Date != "" {
 		dateLow, err := time.ParseInLocation("2006-01-02", opts.Date, setting.DefaultUILocation)
 		if err != nil {
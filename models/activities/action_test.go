@@ -136,6 +136,43 @@ func TestGetFeeds2(t *testing.T) {
 	assert.Len(t, actions, 0)
 }
 
+func TestGetFeedsFilters(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 10})
+
+	opts := activities_model.GetFeedsOptions{
+		RequestedUser:  user,
+		Actor:          user,
+		IncludePrivate: true,
+		OpTypes:        []activities_model.ActionType{activities_model.ActionCreateRepo},
+		CreatedAfter:   1603011000,
+	}
+	actions, err := activities_model.GetFeeds(db.DefaultContext, opts)
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 2) {
+		assert.EqualValues(t, 7, actions[0].ID)
+		assert.EqualValues(t, 6, actions[1].ID)
+	}
+	count, err := activities_model.CountFeeds(db.DefaultContext, opts)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	opts.OpTypes = []activities_model.ActionType{activities_model.ActionCloseIssue}
+	actions, err = activities_model.GetFeeds(db.DefaultContext, opts)
+	assert.NoError(t, err)
+	assert.Len(t, actions, 0)
+}
+
+func TestActionTypeFromString(t *testing.T) {
+	at, ok := activities_model.ActionTypeFromString("create_repo")
+	assert.True(t, ok)
+	assert.Equal(t, activities_model.ActionCreateRepo, at)
+	assert.Equal(t, "create_repo", at.String())
+
+	_, ok = activities_model.ActionTypeFromString("unknown")
+	assert.False(t, ok)
+}
+
 func TestActivityReadable(t *testing.T) {
 	tt := []struct {
 		desc   string
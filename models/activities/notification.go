@@ -86,6 +86,7 @@ type FindNotificationOptions struct {
 	db.ListOptions
 	UserID            int64
 	RepoID            int64
+	RepoOwnerID       int64
 	IssueID           int64
 	Status            []NotificationStatus
 	Source            []NotificationSource
@@ -102,6 +103,9 @@ func (opts *FindNotificationOptions) ToCond() builder.Cond {
 	if opts.RepoID != 0 {
 		cond = cond.And(builder.Eq{"notification.repo_id": opts.RepoID})
 	}
+	if opts.RepoOwnerID != 0 {
+		cond = cond.And(builder.In("notification.repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": opts.RepoOwnerID})))
+	}
 	if opts.IssueID != 0 {
 		cond = cond.And(builder.Eq{"notification.issue_id": opts.IssueID})
 	}
@@ -239,6 +243,20 @@ func createOrUpdateIssueNotifications(ctx context.Context, issueID, commentID, n
 		for _, id := range issueParticipants {
 			toNotify[id] = struct{}{}
 		}
+		issueAssignees, err := issues_model.GetAssigneeIDsByIssue(ctx, issueID)
+		if err != nil {
+			return err
+		}
+		for _, id := range issueAssignees {
+			toNotify[id] = struct{}{}
+		}
+		issueMentions, err := issues_model.GetMentionedUserIDsByIssueID(ctx, issueID)
+		if err != nil {
+			return err
+		}
+		for _, id := range issueMentions {
+			toNotify[id] = struct{}{}
+		}
 
 		// dont notify user who cause notification
 		delete(toNotify, notificationAuthorID)
@@ -268,6 +286,10 @@ func createOrUpdateIssueNotifications(ctx context.Context, issueID, commentID, n
 
 			return err
 		}
+		// service accounts don't read notifications
+		if user.IsBot() {
+			continue
+		}
 		if issue.IsPull && !access_model.CheckRepoUnitUser(ctx, issue.Repo, user, unit.TypePullRequests) {
 			continue
 		}
@@ -0,0 +1,198 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activities
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// usageReportTopConsumers is the number of owners with the highest storage use kept in a report
+const usageReportTopConsumers = 10
+
+// UsageReport is a snapshot of the storage use and the number of repositories, users and organizations of the instance.
+// The reports are created periodically to track the growth over time.
+type UsageReport struct {
+	ID             int64              `xorm:"pk autoincr"`
+	GitSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+	LFSSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+	PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+	AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+	NumRepos       int64              `xorm:"NOT NULL DEFAULT 0"`
+	NumUsers       int64              `xorm:"NOT NULL DEFAULT 0"`
+	NumOrgs        int64              `xorm:"NOT NULL DEFAULT 0"`
+	TopConsumers   []*UsageConsumer   `xorm:"JSON TEXT"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// UsageConsumer is the storage use of an owner
+type UsageConsumer struct {
+	OwnerID     int64
+	OwnerName   string
+	RepoSize    int64 // git and LFS
+	PackageSize int64
+}
+
+func init() {
+	db.RegisterModel(new(UsageReport))
+}
+
+// TotalSize returns the storage use of all categories
+func (r *UsageReport) TotalSize() int64 {
+	return r.GitSize + r.LFSSize + r.PackageSize + r.AttachmentSize
+}
+
+// TotalSize returns the storage use of the owner
+func (c *UsageConsumer) TotalSize() int64 {
+	return c.RepoSize + c.PackageSize
+}
+
+type ownerSize struct {
+	OwnerID int64
+	Size    int64
+}
+
+// CreateUsageReport aggregates the current usage of the instance and stores it as a new report
+func CreateUsageReport(ctx context.Context) (*UsageReport, error) {
+	e := db.GetEngine(ctx)
+	r := &UsageReport{}
+
+	repoSize, err := e.SumInt(new(repo_model.Repository), "size")
+	if err != nil {
+		return nil, err
+	}
+	// the size of a repository includes its LFS objects
+	if r.LFSSize, err = e.SumInt(new(git_model.LFSMetaObject), "size"); err != nil {
+		return nil, err
+	}
+	r.GitSize = repoSize - r.LFSSize
+	if r.GitSize < 0 {
+		r.GitSize = 0
+	}
+	if r.PackageSize, err = e.SumInt(new(packages_model.PackageBlob), "size"); err != nil {
+		return nil, err
+	}
+	if r.AttachmentSize, err = e.SumInt(new(repo_model.Attachment), "size"); err != nil {
+		return nil, err
+	}
+
+	if r.NumRepos, err = repo_model.CountRepositories(ctx, repo_model.CountRepositoryOptions{}); err != nil {
+		return nil, err
+	}
+	r.NumUsers = user_model.CountUsers(nil)
+	if r.NumOrgs, err = organization.CountOrgs(organization.FindOrgOptions{IncludePrivate: true}); err != nil {
+		return nil, err
+	}
+
+	if r.TopConsumers, err = findTopConsumers(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, db.Insert(ctx, r)
+}
+
+// findTopConsumers returns the owners with the highest storage use
+func findTopConsumers(ctx context.Context) ([]*UsageConsumer, error) {
+	e := db.GetEngine(ctx)
+
+	repoSizes := make([]*ownerSize, 0, 10)
+	if err := e.Table("repository").
+		Select("owner_id, SUM(size) AS size").
+		GroupBy("owner_id").
+		Find(&repoSizes); err != nil {
+		return nil, err
+	}
+	packageSizes := make([]*ownerSize, 0, 10)
+	if err := e.Table("package_file").
+		Select("package.owner_id, SUM(package_blob.size) AS size").
+		Join("INNER", "package_blob", "package_blob.id = package_file.blob_id").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		GroupBy("package.owner_id").
+		Find(&packageSizes); err != nil {
+		return nil, err
+	}
+
+	consumers := make(map[int64]*UsageConsumer, len(repoSizes))
+	getConsumer := func(ownerID int64) *UsageConsumer {
+		c, ok := consumers[ownerID]
+		if !ok {
+			c = &UsageConsumer{OwnerID: ownerID}
+			consumers[ownerID] = c
+		}
+		return c
+	}
+	for _, s := range repoSizes {
+		getConsumer(s.OwnerID).RepoSize = s.Size
+	}
+	for _, s := range packageSizes {
+		getConsumer(s.OwnerID).PackageSize = s.Size
+	}
+
+	top := make([]*UsageConsumer, 0, len(consumers))
+	for _, c := range consumers {
+		if c.TotalSize() > 0 {
+			top = append(top, c)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].TotalSize() == top[j].TotalSize() {
+			return top[i].OwnerID < top[j].OwnerID
+		}
+		return top[i].TotalSize() > top[j].TotalSize()
+	})
+	if len(top) > usageReportTopConsumers {
+		top = top[:usageReportTopConsumers]
+	}
+
+	ownerIDs := make([]int64, 0, len(top))
+	for _, c := range top {
+		ownerIDs = append(ownerIDs, c.OwnerID)
+	}
+	owners, err := user_model.GetUsersByIDs(ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range owners {
+		consumers[owner.ID].OwnerName = owner.Name
+	}
+	return top, nil
+}
+
+// GetLatestUsageReport returns the most recent usage report, nil if no report was created yet
+func GetLatestUsageReport(ctx context.Context) (*UsageReport, error) {
+	r := new(UsageReport)
+	has, err := db.GetEngine(ctx).OrderBy("created_unix DESC, id DESC").Get(r)
+	if err != nil || !has {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FindUsageReports returns the usage reports created in the time range, since inclusive and until exclusive, oldest first
+func FindUsageReports(ctx context.Context, since, until timeutil.TimeStamp) ([]*UsageReport, error) {
+	reports := make([]*UsageReport, 0, 30)
+	return reports, db.GetEngine(ctx).
+		Where("created_unix >= ? AND created_unix < ?", since, until).
+		OrderBy("created_unix, id").
+		Find(&reports)
+}
+
+// DeleteUsageReportsOlderThan deletes the usage reports created before olderThan
+func DeleteUsageReportsOlderThan(ctx context.Context, olderThan time.Duration) error {
+	_, err := db.GetEngine(ctx).
+		Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).
+		Delete(new(UsageReport))
+	return err
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activities_test
+
+import (
+	"testing"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUsageReport(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Cols("size").Update(&repo_model.Repository{Size: 1000})
+	assert.NoError(t, err)
+	_, err = db.GetEngine(db.DefaultContext).ID(3).Cols("size").Update(&repo_model.Repository{Size: 3000})
+	assert.NoError(t, err)
+
+	r, err := activities_model.CreateUsageReport(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4000, r.GitSize)
+	assert.EqualValues(t, 0, r.LFSSize)
+	assert.EqualValues(t, unittest.GetCount(t, &repo_model.Repository{}), r.NumRepos)
+	assert.EqualValues(t, user_model.CountUsers(nil), r.NumUsers)
+	assert.EqualValues(t, unittest.GetCount(t, &organization.Organization{Type: user_model.UserTypeOrganization}), r.NumOrgs)
+
+	// repo 3 is owned by the organization user3, repo 1 by user2
+	if assert.Len(t, r.TopConsumers, 2) {
+		assert.Equal(t, "user3", r.TopConsumers[0].OwnerName)
+		assert.EqualValues(t, 3000, r.TopConsumers[0].TotalSize())
+		assert.Equal(t, "user2", r.TopConsumers[1].OwnerName)
+		assert.EqualValues(t, 1000, r.TopConsumers[1].TotalSize())
+	}
+
+	latest, err := activities_model.GetLatestUsageReport(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.NotNil(t, latest) {
+		assert.Equal(t, r.ID, latest.ID)
+		assert.Len(t, latest.TopConsumers, 2)
+	}
+
+	reports, err := activities_model.FindUsageReports(db.DefaultContext, r.CreatedUnix, r.CreatedUnix+1)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	reports, err = activities_model.FindUsageReports(db.DefaultContext, r.CreatedUnix+1, timeutil.TimeStampNow()+2)
+	assert.NoError(t, err)
+	assert.Empty(t, reports)
+
+	assert.NoError(t, activities_model.DeleteUsageReportsOlderThan(db.DefaultContext, time.Hour))
+	unittest.AssertExistsAndLoadBean(t, &activities_model.UsageReport{ID: r.ID})
+	assert.NoError(t, activities_model.DeleteUsageReportsOlderThan(db.DefaultContext, -time.Hour))
+	unittest.AssertNotExistsBean(t, &activities_model.UsageReport{ID: r.ID})
+}
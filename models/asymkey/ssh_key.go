@@ -48,6 +48,9 @@ type PublicKey struct {
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	ExpiresUnix       timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RemindedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 	Verified          bool               `xorm:"NOT NULL DEFAULT false"`
@@ -59,8 +62,13 @@ func init() {
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (key *PublicKey) AfterLoad() {
-	key.HasUsed = key.UpdatedUnix > key.CreatedUnix
-	key.HasRecentActivity = key.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.HasUsed = key.LastUsedUnix > 0
+	key.HasRecentActivity = key.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+}
+
+// IsExpired returns true if the key has an expiry date which has passed
+func (key *PublicKey) IsExpired() bool {
+	return key.ExpiresUnix > 0 && key.ExpiresUnix <= timeutil.TimeStampNow()
 }
 
 // OmitEmail returns content of public key without email address.
@@ -231,8 +239,10 @@ func UpdatePublicKeyUpdated(id int64) error {
 		return ErrKeyNotExist{id}
 	}
 
-	_, err := db.GetEngine(db.DefaultContext).ID(id).Cols("updated_unix").Update(&PublicKey{
-		UpdatedUnix: timeutil.TimeStampNow(),
+	now := timeutil.TimeStampNow()
+	_, err := db.GetEngine(db.DefaultContext).ID(id).Cols("updated_unix", "last_used_unix").Update(&PublicKey{
+		UpdatedUnix:  now,
+		LastUsedUnix: now,
 	})
 	if err != nil {
 		return err
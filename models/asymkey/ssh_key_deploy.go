@@ -38,14 +38,22 @@ type DeployKey struct {
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	ExpiresUnix       timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	RemindedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 }
 
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (key *DeployKey) AfterLoad() {
-	key.HasUsed = key.UpdatedUnix > key.CreatedUnix
-	key.HasRecentActivity = key.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	key.HasUsed = key.LastUsedUnix > 0
+	key.HasRecentActivity = key.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+}
+
+// IsExpired returns true if the key has an expiry date which has passed
+func (key *DeployKey) IsExpired() bool {
+	return key.ExpiresUnix > 0 && key.ExpiresUnix <= timeutil.TimeStampNow()
 }
 
 // GetContent gets associated public key content.
@@ -0,0 +1,64 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package asymkey
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// SetPublicKeyExpiry sets the time after which the key can't be used anymore, zero removes the expiry.
+// The reminder is reset so that it is sent again for the new expiry.
+func SetPublicKeyExpiry(ctx context.Context, keyID int64, expires timeutil.TimeStamp) error {
+	_, err := db.GetEngine(ctx).ID(keyID).Cols("expires_unix", "reminded_unix").Update(&PublicKey{ExpiresUnix: expires})
+	return err
+}
+
+// SetDeployKeyExpiry sets the time after which the deploy key can't be used anymore, zero removes the expiry.
+// The reminder is reset so that it is sent again for the new expiry.
+func SetDeployKeyExpiry(ctx context.Context, id int64, expires timeutil.TimeStamp) error {
+	_, err := db.GetEngine(ctx).ID(id).Cols("expires_unix", "reminded_unix").Update(&DeployKey{ExpiresUnix: expires})
+	return err
+}
+
+func expiringKeysCond(before timeutil.TimeStamp) builder.Cond {
+	return builder.Gt{"expires_unix": 0}.
+		And(builder.Lt{"expires_unix": before}).
+		And(builder.Eq{"reminded_unix": 0})
+}
+
+// FindExpiringPublicKeys returns the user and principal keys which expire before the given time and were not reminded about yet
+func FindExpiringPublicKeys(ctx context.Context, before timeutil.TimeStamp) ([]*PublicKey, error) {
+	keys := make([]*PublicKey, 0, 10)
+	return keys, db.GetEngine(ctx).
+		Where(expiringKeysCond(before).And(builder.Neq{"type": KeyTypeDeploy})).
+		Asc("expires_unix").
+		Find(&keys)
+}
+
+// FindExpiringDeployKeys returns the deploy keys which expire before the given time and were not reminded about yet
+func FindExpiringDeployKeys(ctx context.Context, before timeutil.TimeStamp) ([]*DeployKey, error) {
+	keys := make([]*DeployKey, 0, 10)
+	return keys, db.GetEngine(ctx).
+		Where(expiringKeysCond(before)).
+		Asc("expires_unix").
+		Find(&keys)
+}
+
+// MarkPublicKeyReminded stores that the reminder about the expiry was sent
+func MarkPublicKeyReminded(ctx context.Context, keyID int64) error {
+	_, err := db.GetEngine(ctx).ID(keyID).Cols("reminded_unix").Update(&PublicKey{RemindedUnix: timeutil.TimeStampNow()})
+	return err
+}
+
+// MarkDeployKeyReminded stores that the reminder about the expiry was sent
+func MarkDeployKeyReminded(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Cols("reminded_unix").Update(&DeployKey{RemindedUnix: timeutil.TimeStampNow()})
+	return err
+}
@@ -6,6 +6,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -101,6 +102,11 @@ func RegisterTypeConfig(typ Type, exemplar Config) {
 	}
 }
 
+// HealthChecker configurations provide CheckHealth to check if the external service is reachable
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
 // SourceSettable configurations can have their authSource set on them
 type SourceSettable interface {
 	SetAuthSource(*Source)
@@ -115,6 +121,9 @@ type Source struct {
 	IsSyncEnabled bool               `xorm:"INDEX NOT NULL DEFAULT false"`
 	Cfg           convert.Conversion `xorm:"TEXT"`
 
+	// FallbackSourceID is the source used to authenticate users of this source while it is unreachable
+	FallbackSourceID int64 `xorm:"NOT NULL DEFAULT 0"`
+
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -178,6 +187,12 @@ func (source *Source) IsSSPI() bool {
 	return source.Type == SSPI
 }
 
+// IsHealthCheckable returns true if the health of this source can be checked.
+func (source *Source) IsHealthCheckable() bool {
+	_, ok := source.Cfg.(HealthChecker)
+	return ok
+}
+
 // HasTLS returns true of this source supports TLS.
 func (source *Source) HasTLS() bool {
 	hasTLSer, ok := source.Cfg.(HasTLSer)
@@ -306,6 +321,28 @@ func GetSourceByID(id int64) (*Source, error) {
 	return source, nil
 }
 
+// GetFallbackSources returns the active fallback sources of the source in the order they should be tried
+func GetFallbackSources(source *Source) ([]*Source, error) {
+	visited := map[int64]bool{source.ID: true}
+	sources := make([]*Source, 0, 1)
+	for id := source.FallbackSourceID; id != 0 && !visited[id]; {
+		visited[id] = true
+
+		fallback, err := GetSourceByID(id)
+		if err != nil {
+			if IsErrSourceNotExist(err) {
+				break
+			}
+			return nil, err
+		}
+		if fallback.IsActive {
+			sources = append(sources, fallback)
+		}
+		id = fallback.FallbackSourceID
+	}
+	return sources, nil
+}
+
 // UpdateSource updates a Source record in DB.
 func UpdateSource(source *Source) error {
 	var originalSource *Source
@@ -0,0 +1,89 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SourceHealth represents the result of the last health check of a login source
+type SourceHealth struct {
+	SourceID        int64  `xorm:"pk"`
+	IsHealthy       bool   `xorm:"NOT NULL DEFAULT true"`
+	Error           string `xorm:"TEXT"`
+	CheckedUnix     timeutil.TimeStamp
+	LastHealthyUnix timeutil.TimeStamp
+}
+
+// TableName xorm will read the table name from this method
+func (SourceHealth) TableName() string {
+	return "login_source_health"
+}
+
+func init() {
+	db.RegisterModel(new(SourceHealth))
+}
+
+// IsChecked returns true if the source has been checked at least once
+func (h *SourceHealth) IsChecked() bool {
+	return h.CheckedUnix != 0
+}
+
+// GetSourceHealth returns the health of the login source.
+// A source which has never been checked is considered healthy.
+func GetSourceHealth(ctx context.Context, sourceID int64) (*SourceHealth, error) {
+	h := &SourceHealth{SourceID: sourceID}
+	has, err := db.GetEngine(ctx).Get(h)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return &SourceHealth{SourceID: sourceID, IsHealthy: true}, nil
+	}
+	return h, nil
+}
+
+// GetSourceHealthMap returns the health of all checked login sources mapped by source id
+func GetSourceHealthMap(ctx context.Context) (map[int64]*SourceHealth, error) {
+	healths := make(map[int64]*SourceHealth)
+	return healths, db.GetEngine(ctx).Find(&healths)
+}
+
+// UpdateSourceHealth stores the result of a health check of the login source
+func UpdateSourceHealth(ctx context.Context, sourceID int64, checkErr error) (*SourceHealth, error) {
+	h := &SourceHealth{SourceID: sourceID}
+	return h, db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+
+		has, err := e.Get(h)
+		if err != nil {
+			return err
+		}
+
+		h.CheckedUnix = timeutil.TimeStampNow()
+		h.IsHealthy = checkErr == nil
+		h.Error = ""
+		if checkErr != nil {
+			h.Error = checkErr.Error()
+		} else {
+			h.LastHealthyUnix = h.CheckedUnix
+		}
+
+		if has {
+			_, err = e.ID(sourceID).AllCols().Update(h)
+		} else {
+			_, err = e.Insert(h)
+		}
+		return err
+	}, ctx)
+}
+
+// DeleteSourceHealth deletes the health of the login source
+func DeleteSourceHealth(ctx context.Context, sourceID int64) error {
+	_, err := db.GetEngine(ctx).ID(sourceID).Delete(&SourceHealth{})
+	return err
+}
@@ -5,6 +5,7 @@
 package auth_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -59,3 +60,70 @@ func TestDumpAuthSource(t *testing.T) {
 
 	assert.Contains(t, sb.String(), `"Provider":"ConvertibleSourceName"`)
 }
+
+func TestGetFallbackSources(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	auth_model.RegisterTypeConfig(auth_model.OAuth2, new(TestSource))
+
+	createSource := func(name string, isActive bool, fallbackID int64) *auth_model.Source {
+		source := &auth_model.Source{
+			Type:             auth_model.OAuth2,
+			Name:             name,
+			IsActive:         isActive,
+			FallbackSourceID: fallbackID,
+			Cfg:              &TestSource{},
+		}
+		assert.NoError(t, auth_model.CreateSource(source))
+		return source
+	}
+
+	primary := createSource("primary", true, 0)
+	inactive := createSource("inactive", false, primary.ID)
+	secondary := createSource("secondary", true, inactive.ID)
+
+	sources, err := auth_model.GetFallbackSources(primary)
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+
+	// primary -> secondary -> inactive -> primary
+	primary.FallbackSourceID = secondary.ID
+	assert.NoError(t, auth_model.UpdateSource(primary))
+
+	sources, err = auth_model.GetFallbackSources(primary)
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, secondary.ID, sources[0].ID)
+	}
+}
+
+func TestSourceHealth(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	health, err := auth_model.GetSourceHealth(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.True(t, health.IsHealthy)
+	assert.False(t, health.IsChecked())
+
+	health, err = auth_model.UpdateSourceHealth(db.DefaultContext, 1, nil)
+	assert.NoError(t, err)
+	assert.True(t, health.IsHealthy)
+	assert.True(t, health.IsChecked())
+	assert.Equal(t, health.CheckedUnix, health.LastHealthyUnix)
+
+	_, err = auth_model.UpdateSourceHealth(db.DefaultContext, 1, errors.New("connection refused"))
+	assert.NoError(t, err)
+
+	health, err = auth_model.GetSourceHealth(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.False(t, health.IsHealthy)
+	assert.Equal(t, "connection refused", health.Error)
+	assert.NotZero(t, health.LastHealthyUnix)
+
+	healths, err := auth_model.GetSourceHealthMap(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Len(t, healths, 1)
+
+	assert.NoError(t, auth_model.DeleteSourceHealth(db.DefaultContext, 1))
+	unittest.AssertNotExistsBean(t, &auth_model.SourceHealth{SourceID: 1})
+}
@@ -50,6 +50,40 @@ func (err ErrAccessTokenEmpty) Error() string {
 
 var successfulAccessTokenCache *lru.Cache
 
+// AccessTokenScope represents the scope of an access token
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeAll grants full access to the account
+	AccessTokenScopeAll AccessTokenScope = ""
+	// AccessTokenScopePackageRead only grants read access to packages
+	AccessTokenScopePackageRead AccessTokenScope = "package:read"
+	// AccessTokenScopePackageWrite only grants read and write access to packages
+	AccessTokenScopePackageWrite AccessTokenScope = "package:write"
+)
+
+// AccessTokenScopes are the available access token scopes
+var AccessTokenScopes = []AccessTokenScope{
+	AccessTokenScopeAll,
+	AccessTokenScopePackageRead,
+	AccessTokenScopePackageWrite,
+}
+
+// IsValid checks if the scope is known
+func (s AccessTokenScope) IsValid() bool {
+	for _, scope := range AccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPackageScope checks if the token is restricted to the package registry
+func (s AccessTokenScope) IsPackageScope() bool {
+	return s == AccessTokenScopePackageRead || s == AccessTokenScopePackageWrite
+}
+
 // AccessToken represents a personal access token.
 type AccessToken struct {
 	ID             int64 `xorm:"pk autoincr"`
@@ -58,7 +92,9 @@ type AccessToken struct {
 	Token          string `xorm:"-"`
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
-	TokenLastEight string `xorm:"token_last_eight"`
+	TokenLastEight string           `xorm:"token_last_eight"`
+	Scope          AccessTokenScope `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	OwnerID        int64            `xorm:"NOT NULL DEFAULT 0"` // restricts a package scoped token to the packages of this owner, 0 allows all owners
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -87,8 +123,8 @@ func init() {
 	})
 }
 
-// NewAccessToken creates new access token.
-func NewAccessToken(t *AccessToken) error {
+// generateSecret sets a new random token and its hash
+func (t *AccessToken) generateSecret() error {
 	salt, err := util.CryptoRandomString(10)
 	if err != nil {
 		return err
@@ -97,7 +133,24 @@ func NewAccessToken(t *AccessToken) error {
 	t.Token = base.EncodeSha1(gouuid.New().String())
 	t.TokenHash = HashToken(t.Token, t.TokenSalt)
 	t.TokenLastEight = t.Token[len(t.Token)-8:]
-	_, err = db.GetEngine(db.DefaultContext).Insert(t)
+	return nil
+}
+
+// NewAccessToken creates new access token.
+func NewAccessToken(t *AccessToken) error {
+	if err := t.generateSecret(); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(db.DefaultContext).Insert(t)
+	return err
+}
+
+// RegenerateAccessToken replaces the secret of an access token, the previous token stops working immediately.
+func RegenerateAccessToken(t *AccessToken) error {
+	if err := t.generateSecret(); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(db.DefaultContext).ID(t.ID).Cols("token_hash", "token_salt", "token_last_eight").Update(t)
 	return err
 }
 
@@ -216,6 +269,18 @@ func CountAccessTokens(opts ListAccessTokensOptions) (int64, error) {
 	return sess.Count(&AccessToken{})
 }
 
+// GetAccessTokenByID returns the access token of the user with the given ID.
+func GetAccessTokenByID(id, userID int64) (*AccessToken, error) {
+	t := &AccessToken{UID: userID}
+	has, err := db.GetEngine(db.DefaultContext).ID(id).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAccessTokenNotExist{}
+	}
+	return t, nil
+}
+
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
 	cnt, err := db.GetEngine(db.DefaultContext).ID(id).Delete(&AccessToken{
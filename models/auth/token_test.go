@@ -130,3 +130,24 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, auth_model.IsErrAccessTokenNotExist(err))
 }
+
+func TestRegenerateAccessToken(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	token, err := auth_model.GetAccessTokenBySHA("4c6f36e6cf498e2a448662f915d932c09c5a146c")
+	assert.NoError(t, err)
+
+	token, err = auth_model.GetAccessTokenByID(token.ID, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, auth_model.RegenerateAccessToken(token))
+	assert.Len(t, token.Token, 40)
+
+	_, err = auth_model.GetAccessTokenBySHA("4c6f36e6cf498e2a448662f915d932c09c5a146c")
+	assert.True(t, auth_model.IsErrAccessTokenNotExist(err))
+	regenerated, err := auth_model.GetAccessTokenBySHA(token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, regenerated.ID)
+
+	_, err = auth_model.GetAccessTokenByID(token.ID, 2)
+	assert.True(t, auth_model.IsErrAccessTokenNotExist(err))
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base32"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// TwoFactorRecovery is a one-time code issued by an administrator
+// to let a user who lost access to the second factor sign in.
+type TwoFactorRecovery struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UID         int64              `xorm:"UNIQUE NOT NULL"`
+	TokenSalt   string             `xorm:"NOT NULL"`
+	TokenHash   string             `xorm:"NOT NULL"`
+	IssuerID    int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(TwoFactorRecovery))
+}
+
+// CreateTwoFactorRecovery issues a recovery code for the user which replaces a previously issued one.
+// The code is only returned here, just its hash is stored.
+func CreateTwoFactorRecovery(ctx context.Context, uid, issuerID int64, expiry time.Duration) (string, *TwoFactorRecovery, error) {
+	tokenBytes, err := util.CryptoRandomBytes(10)
+	if err != nil {
+		return "", nil, err
+	}
+	// the same chars as the scratch codes, without ambiguous chars like `0`, `O`, `1`, `I`.
+	const base32Chars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	token := base32.NewEncoding(base32Chars).WithPadding(base32.NoPadding).EncodeToString(tokenBytes)
+
+	r := &TwoFactorRecovery{
+		UID:         uid,
+		IssuerID:    issuerID,
+		ExpiresUnix: timeutil.TimeStamp(time.Now().Add(expiry).Unix()),
+	}
+	r.TokenSalt, err = util.CryptoRandomString(10)
+	if err != nil {
+		return "", nil, err
+	}
+	r.TokenHash = HashToken(token, r.TokenSalt)
+
+	return token, r, db.WithTx(func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("uid = ?", uid).Delete(new(TwoFactorRecovery)); err != nil {
+			return err
+		}
+		return db.Insert(ctx, r)
+	}, ctx)
+}
+
+// UseTwoFactorRecovery verifies the recovery code of the user and invalidates it on success.
+// Expired codes are never valid.
+func UseTwoFactorRecovery(ctx context.Context, uid int64, token string) (bool, error) {
+	if len(token) == 0 {
+		return false, nil
+	}
+
+	r := new(TwoFactorRecovery)
+	has, err := db.GetEngine(ctx).Where("uid = ? AND expires_unix > ?", uid, timeutil.TimeStampNow()).Get(r)
+	if err != nil || !has {
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(r.TokenHash), []byte(HashToken(token, r.TokenSalt))) != 1 {
+		return false, nil
+	}
+
+	// a concurrent use of the same code deletes nothing
+	n, err := db.GetEngine(ctx).ID(r.ID).Delete(new(TwoFactorRecovery))
+	return n == 1, err
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorRecovery(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	first, _, err := auth_model.CreateTwoFactorRecovery(db.DefaultContext, 24, 1, time.Hour)
+	assert.NoError(t, err)
+	code, r, err := auth_model.CreateTwoFactorRecovery(db.DefaultContext, 24, 1, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, code)
+	assert.NotEqual(t, code, r.TokenHash)
+	unittest.AssertCount(t, &auth_model.TwoFactorRecovery{UID: 24}, 1)
+
+	// the first code was replaced
+	ok, err := auth_model.UseTwoFactorRecovery(db.DefaultContext, 24, first)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = auth_model.UseTwoFactorRecovery(db.DefaultContext, 2, code)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = auth_model.UseTwoFactorRecovery(db.DefaultContext, 24, code)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the code is valid once
+	ok, err = auth_model.UseTwoFactorRecovery(db.DefaultContext, 24, code)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// expired codes are invalid
+	code, _, err = auth_model.CreateTwoFactorRecovery(db.DefaultContext, 24, 1, -time.Minute)
+	assert.NoError(t, err)
+	ok, err = auth_model.UseTwoFactorRecovery(db.DefaultContext, 24, code)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
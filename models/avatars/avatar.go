@@ -149,24 +149,24 @@ func generateEmailAvatarLink(email string, size int, final bool) string {
 		return DefaultAvatarLink()
 	}
 
-	var err error
-	if setting.EnableFederatedAvatar && setting.LibravatarService != nil {
+	if !final && (setting.Avatar.EnableProxy || setting.EnableFederatedAvatar && setting.LibravatarService != nil) {
+		// the external avatar is looked up or proxied by the instance (use a 302 redirection link or the proxy)
 		emailHash := saveEmailHash(email)
-		if final {
-			// for final link, we can spend more time on slow external query
-			var avatarURL *url.URL
-			if avatarURL, err = LibravatarURL(email); err != nil {
-				return DefaultAvatarLink()
-			}
-			return generateRecognizedAvatarURL(*avatarURL, size)
-		}
-		// for non-final link, we should return fast (use a 302 redirection link)
 		urlStr := setting.AppSubURL + "/avatar/" + url.PathEscape(emailHash)
 		if size > 0 {
 			urlStr += "?size=" + strconv.Itoa(size)
 		}
 		return urlStr
-	} else if !setting.DisableGravatar {
+	}
+
+	if setting.EnableFederatedAvatar && setting.LibravatarService != nil {
+		// for final link, we can spend more time on slow external query
+		if avatarURL, err := LibravatarURL(email); err == nil {
+			return generateRecognizedAvatarURL(*avatarURL, size)
+		}
+		// the lookup failed, the fallback host of the federation is used like for a domain without own avatar service
+	}
+	if !setting.DisableGravatar {
 		// copy GravatarSourceURL, because we will modify its Path.
 		avatarURLCopy := *setting.GravatarSourceURL
 		avatarURLCopy.Path = path.Join(avatarURLCopy.Path, HashEmail(email))
@@ -138,6 +138,7 @@ func InitEngine(ctx context.Context) error {
 	// so use log file to instead print to stdout.
 	xormEngine.SetLogger(NewXORMLogger(setting.Database.LogSQL))
 	xormEngine.ShowSQL(setting.Database.LogSQL)
+	xormEngine.AddHook(&SlowQueryHook{})
 	xormEngine.SetMaxOpenConns(setting.Database.MaxOpenConns)
 	xormEngine.SetMaxIdleConns(setting.Database.MaxIdleConns)
 	xormEngine.SetConnMaxLifetime(setting.Database.ConnMaxLifetime)
@@ -0,0 +1,46 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/diagnostics"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm/contexts"
+)
+
+// SlowQueryHook keeps the queries taking longer than the configured threshold for the diagnostics
+type SlowQueryHook struct{}
+
+var _ contexts.Hook = &SlowQueryHook{}
+
+// BeforeProcess implements contexts.Hook
+func (*SlowQueryHook) BeforeProcess(c *contexts.ContextHook) (context.Context, error) {
+	return c.Ctx, nil
+}
+
+// AfterProcess implements contexts.Hook
+func (*SlowQueryHook) AfterProcess(c *contexts.ContextHook) error {
+	threshold := setting.Diagnostics.SlowQueryThreshold
+	if threshold <= 0 || c.ExecuteTime < threshold {
+		return nil
+	}
+
+	q := &diagnostics.SlowQuery{
+		Time:     time.Now().Add(-c.ExecuteTime),
+		SQL:      c.SQL,
+		Duration: c.ExecuteTime,
+	}
+	if c.Err != nil {
+		q.Error = c.Err.Error()
+	}
+	diagnostics.RecordSlowQuery(q)
+	log.Warn("[Slow SQL Query] %s took %v", c.SQL, c.ExecuteTime)
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(Instance))
+}
+
+// ErrInstanceNotExist represents a "InstanceNotExist" kind of error.
+type ErrInstanceNotExist struct {
+	Host string
+}
+
+// IsErrInstanceNotExist checks if an error is a ErrInstanceNotExist.
+func IsErrInstanceNotExist(err error) bool {
+	_, ok := err.(ErrInstanceNotExist)
+	return ok
+}
+
+func (err ErrInstanceNotExist) Error() string {
+	return fmt.Sprintf("federated instance does not exist [host: %s]", err.Host)
+}
+
+// ErrInstanceNotAllowed represents a "InstanceNotAllowed" kind of error.
+// It is returned if this instance may not federate with the host.
+type ErrInstanceNotAllowed struct {
+	Host string
+}
+
+// IsErrInstanceNotAllowed checks if an error is a ErrInstanceNotAllowed.
+func IsErrInstanceNotAllowed(err error) bool {
+	_, ok := err.(ErrInstanceNotAllowed)
+	return ok
+}
+
+func (err ErrInstanceNotAllowed) Error() string {
+	return fmt.Sprintf("federation with instance is not allowed [host: %s]", err.Host)
+}
+
+// InstancePolicy defines if an instance may federate with this instance
+type InstancePolicy string
+
+// List of instance policies
+const (
+	InstancePolicyAllow InstancePolicy = "allow"
+	InstancePolicyBlock InstancePolicy = "block"
+)
+
+// IsValid checks if the policy is known
+func (p InstancePolicy) IsValid() bool {
+	return p == InstancePolicyAllow || p == InstancePolicyBlock
+}
+
+// Instance is an entry of the federation allowlist or blocklist.
+// The entry applies to the host and all of its subdomains.
+type Instance struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Host        string             `xorm:"UNIQUE NOT NULL"`
+	Policy      InstancePolicy     `xorm:"NOT NULL"`
+	Reason      string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL"`
+}
+
+// TableName sets the table name of the instance entries
+func (Instance) TableName() string {
+	return "federated_instance"
+}
+
+// NormalizeHost lower cases the host and removes a trailing dot
+func NormalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// SetInstancePolicy adds the instance to the allowlist or blocklist or updates its existing entry.
+// It returns true if a new entry was created.
+func SetInstancePolicy(ctx context.Context, host string, policy InstancePolicy, reason string) (*Instance, bool, error) {
+	host = NormalizeHost(host)
+
+	e := db.GetEngine(ctx)
+
+	instance := &Instance{}
+	has, err := e.Where("host = ?", host).Get(instance)
+	if err != nil {
+		return nil, false, err
+	}
+	if has {
+		instance.Policy = policy
+		instance.Reason = reason
+		_, err = e.ID(instance.ID).Cols("policy", "reason").Update(instance)
+		return instance, false, err
+	}
+
+	instance = &Instance{
+		Host:   host,
+		Policy: policy,
+		Reason: reason,
+	}
+	_, err = e.Insert(instance)
+	return instance, true, err
+}
+
+// GetInstance gets the entry of the host
+func GetInstance(ctx context.Context, host string) (*Instance, error) {
+	host = NormalizeHost(host)
+
+	instance := &Instance{}
+	has, err := db.GetEngine(ctx).Where("host = ?", host).Get(instance)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrInstanceNotExist{host}
+	}
+	return instance, nil
+}
+
+// DeleteInstance removes the entry of the host
+func DeleteInstance(ctx context.Context, host string) error {
+	host = NormalizeHost(host)
+
+	n, err := db.GetEngine(ctx).Where("host = ?", host).Delete(&Instance{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInstanceNotExist{host}
+	}
+	return nil
+}
+
+// FindInstancesOptions filters the instance entries
+type FindInstancesOptions struct {
+	db.ListOptions
+	Policy InstancePolicy
+}
+
+// FindInstances gets the instance entries ordered by host
+func FindInstances(ctx context.Context, opts *FindInstancesOptions) ([]*Instance, int64, error) {
+	sess := db.GetEngine(ctx).OrderBy("host")
+	if opts.Policy != "" {
+		sess = sess.Where("policy = ?", opts.Policy)
+	}
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	instances := make([]*Instance, 0, 10)
+	count, err := sess.FindAndCount(&instances)
+	return instances, count, err
+}
+
+// domainCandidates returns the host and all its parent domains, the most specific first
+func domainCandidates(host string) []string {
+	candidates := []string{host}
+	for {
+		idx := strings.IndexByte(host, '.')
+		if idx < 0 {
+			return candidates
+		}
+		host = host[idx+1:]
+		candidates = append(candidates, host)
+	}
+}
+
+// IsInstanceAllowed checks if this instance may federate with the host.
+// The most specific entry of the host or one of its parent domains decides. If there is none,
+// the host is allowed unless the federation is restricted to the allowlist.
+func IsInstanceAllowed(ctx context.Context, host string) (bool, error) {
+	host = NormalizeHost(host)
+	// the port is not part of the entries
+	if idx := strings.LastIndexByte(host, ':'); idx >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:idx]
+	}
+
+	candidates := domainCandidates(host)
+
+	instances := make([]*Instance, 0, len(candidates))
+	if err := db.GetEngine(ctx).In("host", candidates).Find(&instances); err != nil {
+		return false, err
+	}
+
+	for _, candidate := range candidates {
+		for _, instance := range instances {
+			if instance.Host == candidate {
+				return instance.Policy == InstancePolicyAllow, nil
+			}
+		}
+	}
+	return !setting.Federation.RequireAllowlist, nil
+}
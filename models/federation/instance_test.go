@@ -0,0 +1,67 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInstanceAllowed(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, created, err := SetInstancePolicy(db.DefaultContext, "Example.com", InstancePolicyBlock, "spam")
+	assert.NoError(t, err)
+	assert.True(t, created)
+	_, _, err = SetInstancePolicy(db.DefaultContext, "good.example.com", InstancePolicyAllow, "")
+	assert.NoError(t, err)
+	_, _, err = SetInstancePolicy(db.DefaultContext, "partner.org", InstancePolicyAllow, "")
+	assert.NoError(t, err)
+
+	test := func(host string, expected bool) {
+		allowed, err := IsInstanceAllowed(db.DefaultContext, host)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, allowed, host)
+	}
+
+	test("example.com", false)
+	test("EXAMPLE.com.", false)
+	test("sub.example.com", false)
+	test("example.com:8443", false)
+	test("good.example.com", true)
+	test("deep.good.example.com", true)
+	test("notexample.com", true)
+	test("partner.org", true)
+
+	defer func(old bool) {
+		setting.Federation.RequireAllowlist = old
+	}(setting.Federation.RequireAllowlist)
+	setting.Federation.RequireAllowlist = true
+
+	test("notexample.com", false)
+	test("partner.org", true)
+	test("sub.example.com", false)
+
+	instance, created, err := SetInstancePolicy(db.DefaultContext, "example.com", InstancePolicyAllow, "")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, InstancePolicyAllow, instance.Policy)
+	test("sub.example.com", true)
+
+	assert.NoError(t, DeleteInstance(db.DefaultContext, "example.com"))
+	assert.True(t, IsErrInstanceNotExist(DeleteInstance(db.DefaultContext, "example.com")))
+	test("sub.example.com", false)
+
+	instances, count, err := FindInstances(db.DefaultContext, &FindInstancesOptions{Policy: InstancePolicyAllow})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Equal(t, "good.example.com", instances[0].Host)
+	assert.Equal(t, "partner.org", instances[1].Host)
+}
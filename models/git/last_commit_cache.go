@@ -0,0 +1,110 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// LastCommitCache represents the cached id of the last commit which changed an entry of a tree
+type LastCommitCache struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	CacheKey    string             `xorm:"UNIQUE NOT NULL"`
+	CommitID    string             `xorm:"VARCHAR(40) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(LastCommitCache))
+}
+
+// dbLastCommitStore is a git.Cache which stores last commit information of a repository in the database
+type dbLastCommitStore struct {
+	repoID int64
+}
+
+// Put stores the commit id for the key, the timeout is ignored as the entries get removed by a cron task
+func (s *dbLastCommitStore) Put(key string, val interface{}, timeout int64) error {
+	commitID, ok := val.(string)
+	if !ok {
+		return nil
+	}
+
+	return insertLastCommitCache(db.DefaultContext, s.repoID, key, commitID)
+}
+
+// insertLastCommitCache stores the entry unless an entry for the key exists already.
+// The entry of a key never changes, so concurrent requests storing the same entry are no conflict.
+func insertLastCommitCache(ctx context.Context, repoID int64, key, commitID string) (err error) {
+	now := timeutil.TimeStampNow()
+	switch {
+	case setting.Database.UseSQLite3 || setting.Database.UsePostgreSQL:
+		_, err = db.Exec(ctx, "INSERT INTO `last_commit_cache` (repo_id, cache_key, commit_id, created_unix) "+
+			"VALUES (?,?,?,?) ON CONFLICT (cache_key) DO NOTHING",
+			repoID, key, commitID, now)
+	case setting.Database.UseMySQL:
+		_, err = db.Exec(ctx, "INSERT INTO `last_commit_cache` (repo_id, cache_key, commit_id, created_unix) "+
+			"VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE cache_key = cache_key",
+			repoID, key, commitID, now)
+	case setting.Database.UseMSSQL:
+		_, err = db.Exec(ctx, "MERGE `last_commit_cache` WITH (HOLDLOCK) as target "+
+			"USING (SELECT ? AS cache_key) AS src "+
+			"ON src.cache_key = target.cache_key "+
+			"WHEN NOT MATCHED THEN INSERT (repo_id, cache_key, commit_id, created_unix) "+
+			"VALUES (?, src.cache_key, ?, ?);",
+			key, repoID, commitID, now)
+	default:
+		return fmt.Errorf("database type not supported")
+	}
+	return err
+}
+
+// Get returns the stored commit id for the key or nil
+func (s *dbLastCommitStore) Get(key string) interface{} {
+	lcc := &LastCommitCache{}
+	has, err := db.GetEngine(db.DefaultContext).Where("cache_key = ?", key).Get(lcc)
+	if err != nil {
+		log.Error("Unable to get last commit cache entry %s: %v", key, err)
+		return nil
+	}
+	if !has {
+		return nil
+	}
+	return lcc.CommitID
+}
+
+// DeleteOldLastCommitCache deletes the stored last commit information which is older than olderThan
+func DeleteOldLastCommitCache(ctx context.Context, olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+
+	_, err := db.GetEngine(ctx).
+		Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).
+		Delete(new(LastCommitCache))
+	return err
+}
+
+// GetLastCommitStore returns the configured store for the last commit information of a repository
+func GetLastCommitStore(repoID int64) git.Cache {
+	if setting.CacheService.LastCommit.Store == setting.LastCommitStoreDatabase {
+		return &dbLastCommitStore{repoID: repoID}
+	}
+
+	if c := cache.GetCache(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastCommitDatabaseStore(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldStore := setting.CacheService.LastCommit.Store
+	setting.CacheService.LastCommit.Store = setting.LastCommitStoreDatabase
+	defer func() {
+		setting.CacheService.LastCommit.Store = oldStore
+	}()
+
+	store := git_model.GetLastCommitStore(1)
+	assert.NotNil(t, store)
+
+	assert.Nil(t, store.Get("key"))
+
+	assert.NoError(t, store.Put("key", "65f1bf27bc3bf70f64657658635e66094edbcb4d", 0))
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", store.Get("key"))
+
+	// an existing entry is kept
+	assert.NoError(t, store.Put("key", "6543bc8e8e4a4a40e2ac9c7d1deeb7c6e9a4d4b8", 0))
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", store.Get("key"))
+
+	unittest.AssertCount(t, &git_model.LastCommitCache{RepoID: 1}, 1)
+}
+
+func TestDeleteOldLastCommitCache(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldStore := setting.CacheService.LastCommit.Store
+	setting.CacheService.LastCommit.Store = setting.LastCommitStoreDatabase
+	defer func() {
+		setting.CacheService.LastCommit.Store = oldStore
+	}()
+
+	store := git_model.GetLastCommitStore(1)
+	assert.NoError(t, store.Put("key", "65f1bf27bc3bf70f64657658635e66094edbcb4d", 0))
+
+	assert.NoError(t, git_model.DeleteOldLastCommitCache(db.DefaultContext, time.Hour))
+	unittest.AssertCount(t, &git_model.LastCommitCache{RepoID: 1}, 1)
+
+	_, err := db.GetEngine(db.DefaultContext).Where("repo_id = ?", 1).Cols("created_unix").NoAutoTime().
+		Update(&git_model.LastCommitCache{CreatedUnix: timeutil.TimeStampNow().Add(-7200)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, git_model.DeleteOldLastCommitCache(db.DefaultContext, time.Hour))
+	unittest.AssertCount(t, &git_model.LastCommitCache{RepoID: 1}, 0)
+}
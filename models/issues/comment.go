@@ -275,6 +275,12 @@ type Comment struct {
 	ReviewID    int64   `xorm:"index"`
 	Invalidated bool
 
+	// HiddenByID is the moderator who hid the comment, 0 if the comment is not hidden
+	HiddenByID   int64            `xorm:"NOT NULL DEFAULT 0"`
+	HiddenBy     *user_model.User `xorm:"-"`
+	HiddenReason string
+	HiddenUnix   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+
 	// Reference an issue or pull from another comment, issue or PR
 	// All information is about the origin of the reference
 	RefRepoID    int64                 `xorm:"index"` // Repo where the referencing
@@ -544,6 +550,24 @@ func (c *Comment) LoadPoster() error {
 	return c.loadPoster(db.DefaultContext)
 }
 
+// IsHidden returns true if the comment was hidden by a moderator
+func (c *Comment) IsHidden() bool {
+	return c.HiddenByID != 0
+}
+
+// LoadHiddenBy loads the moderator who hid the comment
+func (c *Comment) LoadHiddenBy(ctx context.Context) (err error) {
+	if !c.IsHidden() || c.HiddenBy != nil {
+		return nil
+	}
+	c.HiddenBy, err = user_model.GetUserByIDCtx(ctx, c.HiddenByID)
+	if user_model.IsErrUserNotExist(err) {
+		c.HiddenBy = user_model.NewGhostUser()
+		return nil
+	}
+	return err
+}
+
 // LoadAttachments loads attachments (it never returns error, the error during `GetAttachmentsByCommentIDCtx` is ignored)
 func (c *Comment) LoadAttachments() error {
 	if len(c.Attachments) > 0 {
@@ -1153,6 +1177,12 @@ func UpdateComment(c *Comment, doer *user_model.User) error {
 	return nil
 }
 
+// UpdateCommentHidden stores the hidden state of the comment without changing its update time
+func UpdateCommentHidden(ctx context.Context, c *Comment) error {
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("hidden_by_id", "hidden_reason", "hidden_unix").NoAutoTime().Update(c)
+	return err
+}
+
 // DeleteComment deletes the comment
 func DeleteComment(ctx context.Context, comment *Comment) error {
 	e := db.GetEngine(ctx)
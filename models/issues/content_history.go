@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models/avatars"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"xorm.io/builder"
@@ -51,9 +52,10 @@ func SaveIssueContentHistory(ctx context.Context, posterID, issueID, commentID i
 		log.Error("can not save issue content history. err=%v", err)
 		return err
 	}
-	// We only keep at most 20 history revisions now. It is enough in most cases.
-	// If there is a special requirement to keep more, we can consider introducing a new setting option then, but not now.
-	KeepLimitedContentHistory(ctx, issueID, commentID, 20)
+	// The number of kept revisions is limited by default, it can be disabled to keep the full history for moderation.
+	if setting.Repository.Issue.MaxContentRevisions > 0 {
+		KeepLimitedContentHistory(ctx, issueID, commentID, setting.Repository.Issue.MaxContentRevisions)
+	}
 	return nil
 }
 
@@ -166,6 +168,15 @@ func FetchIssueContentHistoryList(dbCtx context.Context, issueID, commentID int6
 	return res, nil
 }
 
+// FindIssueContentHistories returns the revisions of the content of an issue (commentID = 0) or a comment, oldest first
+func FindIssueContentHistories(dbCtx context.Context, issueID, commentID int64) ([]*ContentHistory, error) {
+	res := make([]*ContentHistory, 0, 5)
+	return res, db.GetEngine(dbCtx).
+		Where(builder.Eq{"issue_id": issueID, "comment_id": commentID}).
+		OrderBy("edited_unix ASC, id ASC").
+		Find(&res)
+}
+
 // HasIssueContentHistory check if a ContentHistory entry exists
 func HasIssueContentHistory(dbCtx context.Context, issueID, commentID int64) (bool, error) {
 	exists, err := db.GetEngine(dbCtx).Cols("id").Exist(&ContentHistory{
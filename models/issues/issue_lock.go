@@ -5,8 +5,13 @@
 package issues
 
 import (
+	"context"
+
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // IssueLockOptions defines options for locking and/or unlocking an issue/PR
@@ -14,6 +19,8 @@ type IssueLockOptions struct {
 	Doer   *user_model.User
 	Issue  *Issue
 	Reason string
+	// Until is the time the lock expires, 0 for a lock without expiry
+	Until timeutil.TimeStamp
 }
 
 // LockIssue locks an issue. This would limit commenting abilities to
@@ -34,10 +41,15 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 
 	opts.Issue.IsLocked = lock
 	var commentType CommentType
+	var action ModerationAction
 	if opts.Issue.IsLocked {
 		commentType = CommentTypeLock
+		action = ModerationActionLock
+		opts.Issue.LockedUntilUnix = opts.Until
 	} else {
 		commentType = CommentTypeUnlock
+		action = ModerationActionUnlock
+		opts.Issue.LockedUntilUnix = 0
 	}
 
 	ctx, committer, err := db.TxContext()
@@ -46,7 +58,7 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	}
 	defer committer.Close()
 
-	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked"); err != nil {
+	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked", "locked_until_unix"); err != nil {
 		return err
 	}
 
@@ -61,5 +73,25 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 		return err
 	}
 
+	if err := InsertModerationLog(ctx, &ModerationLog{
+		RepoID:  opts.Issue.RepoID,
+		IssueID: opts.Issue.ID,
+		DoerID:  opts.Doer.ID,
+		Action:  action,
+		Reason:  opts.Reason,
+	}); err != nil {
+		return err
+	}
+
 	return committer.Commit()
 }
+
+// FindExpiredIssueLocks returns the locked issues whose timed lock has expired
+func FindExpiredIssueLocks(ctx context.Context) ([]*Issue, error) {
+	issues := make([]*Issue, 0, 10)
+	return issues, db.GetEngine(ctx).
+		Where(builder.Eq{"is_locked": true}).
+		And(builder.Gt{"locked_until_unix": 0}).
+		And(builder.Lte{"locked_until_unix": timeutil.TimeStampNow()}).
+		Find(&issues)
+}
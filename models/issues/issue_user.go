@@ -61,6 +61,22 @@ func UpdateIssueUserByRead(uid, issueID int64) error {
 	return err
 }
 
+// IsUserMentionedInIssue returns true if the user was mentioned in the issue or one of its comments
+func IsUserMentionedInIssue(ctx context.Context, issueID, userID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("issue_id = ? AND uid = ? AND is_mentioned = ?", issueID, userID, true).Exist(new(IssueUser))
+}
+
+// GetMentionedUserIDsByIssueID returns the IDs of the users mentioned in an issue
+// but skips joining with `user` for performance reasons.
+// User permissions must be verified elsewhere if required.
+func GetMentionedUserIDsByIssueID(ctx context.Context, issueID int64) ([]int64, error) {
+	userIDs := make([]int64, 0, 5)
+	return userIDs, db.GetEngine(ctx).Table("issue_user").
+		Cols("uid").
+		Where("issue_id = ? AND is_mentioned = ?", issueID, true).
+		Find(&userIDs)
+}
+
 // UpdateIssueUsersByMentions updates issue-user pairs by mentioning.
 func UpdateIssueUsersByMentions(ctx context.Context, issueID int64, uids []int64) error {
 	for _, uid := range uids {
@@ -11,6 +11,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // IssueWatch is connection request for receiving issue notification.
@@ -19,6 +20,7 @@ type IssueWatch struct {
 	UserID      int64              `xorm:"UNIQUE(watch) NOT NULL"`
 	IssueID     int64              `xorm:"UNIQUE(watch) NOT NULL"`
 	IsWatching  bool               `xorm:"NOT NULL"`
+	Reason      IssueWatchReason   `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
 	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL"`
 }
@@ -27,12 +29,32 @@ func init() {
 	db.RegisterModel(new(IssueWatch))
 }
 
+// IssueWatchReason describes why a user receives the notifications of an issue
+type IssueWatchReason string
+
+// Possible reasons of an issue subscription, ordered by their precedence
+const (
+	IssueWatchReasonNone        IssueWatchReason = ""
+	IssueWatchReasonManual      IssueWatchReason = "manual"     // the user subscribed to the issue
+	IssueWatchReasonTeam        IssueWatchReason = "team"       // the user was subscribed by another member of the repository
+	IssueWatchReasonAuthor      IssueWatchReason = "author"     // the user opened the issue
+	IssueWatchReasonAssigned    IssueWatchReason = "assigned"   // the user is assigned to the issue
+	IssueWatchReasonMentioned   IssueWatchReason = "mentioned"  // the user was mentioned in the issue
+	IssueWatchReasonComment     IssueWatchReason = "comment"    // the user commented on the issue
+	IssueWatchReasonRepoWatched IssueWatchReason = "repository" // the user watches the repository
+)
+
 // IssueWatchList contains IssueWatch
 type IssueWatchList []*IssueWatch
 
 // CreateOrUpdateIssueWatch set watching for a user and issue
 func CreateOrUpdateIssueWatch(userID, issueID int64, isWatching bool) error {
-	iw, exists, err := GetIssueWatch(db.DefaultContext, userID, issueID)
+	return CreateOrUpdateIssueWatchWithReason(db.DefaultContext, userID, issueID, isWatching, IssueWatchReasonManual)
+}
+
+// CreateOrUpdateIssueWatchWithReason set watching for a user and issue and records why the user was subscribed
+func CreateOrUpdateIssueWatchWithReason(ctx context.Context, userID, issueID int64, isWatching bool, reason IssueWatchReason) error {
+	iw, exists, err := GetIssueWatch(ctx, userID, issueID)
 	if err != nil {
 		return err
 	}
@@ -42,15 +64,17 @@ func CreateOrUpdateIssueWatch(userID, issueID int64, isWatching bool) error {
 			UserID:     userID,
 			IssueID:    issueID,
 			IsWatching: isWatching,
+			Reason:     reason,
 		}
 
-		if _, err := db.GetEngine(db.DefaultContext).Insert(iw); err != nil {
+		if _, err := db.GetEngine(ctx).Insert(iw); err != nil {
 			return err
 		}
 	} else {
 		iw.IsWatching = isWatching
+		iw.Reason = reason
 
-		if _, err := db.GetEngine(db.DefaultContext).ID(iw.ID).Cols("is_watching", "updated_unix").Update(iw); err != nil {
+		if _, err := db.GetEngine(ctx).ID(iw.ID).Cols("is_watching", "reason", "updated_unix").Update(iw); err != nil {
 			return err
 		}
 	}
@@ -69,20 +93,57 @@ func GetIssueWatch(ctx context.Context, userID, issueID int64) (iw *IssueWatch,
 }
 
 // CheckIssueWatch check if an user is watching an issue
-// it takes participants and repo watch into account
+// it takes participants, assignees, mentions and repo watch into account
 func CheckIssueWatch(user *user_model.User, issue *Issue) (bool, error) {
-	iw, exist, err := GetIssueWatch(db.DefaultContext, user.ID, issue.ID)
+	reason, err := GetIssueWatchReason(db.DefaultContext, user, issue)
+	return reason != IssueWatchReasonNone, err
+}
+
+// GetIssueWatchReason returns why a user is watching an issue, IssueWatchReasonNone is returned if the user is not watching it.
+// An explicit subscription or unsubscription takes precedence over all other reasons.
+func GetIssueWatchReason(ctx context.Context, user *user_model.User, issue *Issue) (IssueWatchReason, error) {
+	iw, exist, err := GetIssueWatch(ctx, user.ID, issue.ID)
 	if err != nil {
-		return false, err
+		return IssueWatchReasonNone, err
 	}
 	if exist {
-		return iw.IsWatching, nil
+		if !iw.IsWatching {
+			return IssueWatchReasonNone, nil
+		}
+		if iw.Reason == IssueWatchReasonNone {
+			return IssueWatchReasonManual, nil
+		}
+		return iw.Reason, nil
 	}
-	w, err := repo_model.GetWatch(db.DefaultContext, user.ID, issue.RepoID)
+
+	if issue.PosterID == user.ID {
+		return IssueWatchReasonAuthor, nil
+	}
+	if isAssigned, err := IsUserAssignedToIssue(ctx, issue, user); err != nil {
+		return IssueWatchReasonNone, err
+	} else if isAssigned {
+		return IssueWatchReasonAssigned, nil
+	}
+	if isMentioned, err := IsUserMentionedInIssue(ctx, issue.ID, user.ID); err != nil {
+		return IssueWatchReasonNone, err
+	} else if isMentioned {
+		return IssueWatchReasonMentioned, nil
+	}
+	participants, err := issue.GetParticipantIDsByIssue(ctx)
 	if err != nil {
-		return false, err
+		return IssueWatchReasonNone, err
+	}
+	if util.IsInt64InSlice(user.ID, participants) {
+		return IssueWatchReasonComment, nil
+	}
+	w, err := repo_model.GetWatch(ctx, user.ID, issue.RepoID)
+	if err != nil {
+		return IssueWatchReasonNone, err
+	}
+	if repo_model.IsWatchMode(w.Mode) {
+		return IssueWatchReasonRepoWatched, nil
 	}
-	return repo_model.IsWatchMode(w.Mode) || IsUserParticipantsOfIssue(user, issue), nil
+	return IssueWatchReasonNone, nil
 }
 
 // GetIssueWatchersIDs returns IDs of subscribers or explicit unsubscribers to a given issue id
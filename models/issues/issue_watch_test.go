@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -66,3 +67,26 @@ func TestGetIssueWatchers(t *testing.T) {
 	// Issue has one watcher
 	assert.Len(t, iws, 1)
 }
+
+func TestGetIssueWatchReason(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	test := func(userID, issueID int64, expected issues_model.IssueWatchReason) {
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: userID})
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issueID})
+		reason, err := issues_model.GetIssueWatchReason(db.DefaultContext, user, issue)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, reason)
+	}
+
+	// explicit subscriptions without a reason were made by the users themselves
+	test(9, 1, issues_model.IssueWatchReasonManual)
+	test(2, 2, issues_model.IssueWatchReasonNone)
+	test(1, 1, issues_model.IssueWatchReasonAuthor)
+	test(2, 6, issues_model.IssueWatchReasonAssigned)
+	test(4, 1, issues_model.IssueWatchReasonMentioned)
+	test(1, 4, issues_model.IssueWatchReasonNone)
+
+	assert.NoError(t, issues_model.CreateOrUpdateIssueWatchWithReason(db.DefaultContext, 4, 1, true, issues_model.IssueWatchReasonTeam))
+	test(4, 1, issues_model.IssueWatchReasonTeam)
+}
@@ -69,6 +69,21 @@ func (err ErrLabelNotExist) Error() string {
 	return fmt.Sprintf("label does not exist [label_id: %d]", err.LabelID)
 }
 
+// ErrLabelScopeConflict represents a "LabelScopeConflict" kind of error.
+type ErrLabelScopeConflict struct {
+	Scope string
+}
+
+// IsErrLabelScopeConflict checks if an error is a ErrLabelScopeConflict.
+func IsErrLabelScopeConflict(err error) bool {
+	_, ok := err.(ErrLabelScopeConflict)
+	return ok
+}
+
+func (err ErrLabelScopeConflict) Error() string {
+	return fmt.Sprintf("only one label of a scope can be set [scope: %s]", err.Scope)
+}
+
 // LabelColorPattern is a regexp witch can validate LabelColor
 var LabelColorPattern = regexp.MustCompile("^#?(?:[0-9a-fA-F]{6}|[0-9a-fA-F]{3})$")
 
@@ -148,6 +163,34 @@ func (label *Label) BelongsToRepo() bool {
 	return label.RepoID > 0
 }
 
+// LabelScopeSeparator separates the scope from the value in the name of a scoped label
+const LabelScopeSeparator = "::"
+
+// IsScoped returns true if the label is a scoped label like "priority::high".
+// An issue can only have one label of a scope.
+func (label *Label) IsScoped() bool {
+	return label.Scope() != ""
+}
+
+// Scope returns the scope of the label, the part of the name before the last separator.
+// It returns an empty string for labels which are not scoped.
+func (label *Label) Scope() string {
+	i := strings.LastIndex(label.Name, LabelScopeSeparator)
+	if i <= 0 || i+len(LabelScopeSeparator) == len(label.Name) {
+		return ""
+	}
+	return label.Name[:i]
+}
+
+// ScopedName returns the part of the name after the scope
+func (label *Label) ScopedName() string {
+	scope := label.Scope()
+	if scope == "" {
+		return label.Name
+	}
+	return label.Name[len(scope)+len(LabelScopeSeparator):]
+}
+
 // SrgbToLinear converts a component of an sRGB color to its linear intensity
 // See: https://en.wikipedia.org/wiki/SRGB#The_reverse_transformation_(sRGB_to_CIE_XYZ)
 func SrgbToLinear(color uint8) float64 {
@@ -193,6 +236,22 @@ func (label *Label) ForegroundColor() template.CSS {
 	return template.CSS("#000")
 }
 
+// CheckLabelScopes returns an ErrLabelScopeConflict if several of the labels have the same scope
+func CheckLabelScopes(labels []*Label) error {
+	scopes := make(map[string]int64, len(labels))
+	for _, label := range labels {
+		scope := label.Scope()
+		if scope == "" {
+			continue
+		}
+		if id, ok := scopes[scope]; ok && id != label.ID {
+			return ErrLabelScopeConflict{Scope: scope}
+		}
+		scopes[scope] = label.ID
+	}
+	return nil
+}
+
 // NewLabel creates a new label
 func NewLabel(ctx context.Context, label *Label) error {
 	if !LabelColorPattern.MatchString(label.Color) {
@@ -583,6 +642,10 @@ func HasIssueLabel(ctx context.Context, issueID, labelID int64) bool {
 // newIssueLabel this function creates a new label it does not check if the label is valid for the issue
 // YOU MUST CHECK THIS BEFORE THIS FUNCTION
 func newIssueLabel(ctx context.Context, issue *Issue, label *Label, doer *user_model.User) (err error) {
+	if err = removeScopedIssueLabels(ctx, issue, label, doer); err != nil {
+		return err
+	}
+
 	if err = db.Insert(ctx, &IssueLabel{
 		IssueID: issue.ID,
 		LabelID: label.ID,
@@ -609,7 +672,30 @@ func newIssueLabel(ctx context.Context, issue *Issue, label *Label, doer *user_m
 	return updateLabelCols(ctx, label, "num_issues", "num_closed_issue")
 }
 
+// removeScopedIssueLabels removes the labels of the issue which have the same scope as the given label
+func removeScopedIssueLabels(ctx context.Context, issue *Issue, label *Label, doer *user_model.User) error {
+	scope := label.Scope()
+	if scope == "" {
+		return nil
+	}
+
+	labels, err := GetLabelsByIssueID(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, l := range labels {
+		if l.ID == label.ID || l.Scope() != scope {
+			continue
+		}
+		if err := deleteIssueLabel(ctx, issue, l, doer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewIssueLabel creates a new issue-label relation.
+// Labels of the issue with the same scope as the new label are removed.
 func NewIssueLabel(issue *Issue, label *Label, doer *user_model.User) (err error) {
 	if HasIssueLabel(db.DefaultContext, issue.ID, label.ID) {
 		return nil
@@ -642,7 +728,8 @@ func NewIssueLabel(issue *Issue, label *Label, doer *user_model.User) (err error
 	return committer.Commit()
 }
 
-// newIssueLabels add labels to an issue. It will check if the labels are valid for the issue
+// newIssueLabels add labels to an issue. It will check if the labels are valid for the issue.
+// If several labels have the same scope, the last one wins.
 func newIssueLabels(ctx context.Context, issue *Issue, labels []*Label, doer *user_model.User) (err error) {
 	if err = issue.LoadRepo(ctx); err != nil {
 		return err
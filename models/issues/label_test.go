@@ -35,6 +35,35 @@ func TestLabel_ForegroundColor(t *testing.T) {
 	assert.Equal(t, template.CSS("#fff"), label.ForegroundColor())
 }
 
+func TestLabel_Scope(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Scope      string
+		ScopedName string
+	}{
+		{"label", "", "label"},
+		{"priority::high", "priority", "high"},
+		{"type::bug::critical", "type::bug", "critical"},
+		{"::high", "", "::high"},
+		{"priority::", "", "priority::"},
+		{"a:b", "", "a:b"},
+	}
+	for _, c := range cases {
+		label := &issues_model.Label{Name: c.Name}
+		assert.Equal(t, c.Scope, label.Scope(), c.Name)
+		assert.Equal(t, c.Scope != "", label.IsScoped(), c.Name)
+		assert.Equal(t, c.ScopedName, label.ScopedName(), c.Name)
+	}
+
+	assert.NoError(t, issues_model.CheckLabelScopes([]*issues_model.Label{
+		{ID: 1, Name: "priority::high"}, {ID: 2, Name: "status::open"}, {ID: 3, Name: "bug"}, {ID: 4, Name: "feature"},
+	}))
+	err := issues_model.CheckLabelScopes([]*issues_model.Label{
+		{ID: 1, Name: "priority::high"}, {ID: 2, Name: "priority::low"},
+	})
+	assert.True(t, issues_model.IsErrLabelScopeConflict(err))
+}
+
 func TestNewLabels(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	labels := []*issues_model.Label{
@@ -324,6 +353,35 @@ func TestNewIssueLabel(t *testing.T) {
 	unittest.CheckConsistencyFor(t, &issues_model.Issue{}, &issues_model.Label{})
 }
 
+func TestNewIssueLabel_Scoped(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	high := &issues_model.Label{RepoID: issue.RepoID, Name: "priority::high", Color: "#ff0000"}
+	low := &issues_model.Label{RepoID: issue.RepoID, Name: "priority::low", Color: "#00ff00"}
+	status := &issues_model.Label{RepoID: issue.RepoID, Name: "status::open", Color: "#0000ff"}
+	assert.NoError(t, issues_model.NewLabels(high, low, status))
+
+	assert.NoError(t, issues_model.NewIssueLabel(issue, high, doer))
+	assert.NoError(t, issues_model.NewIssueLabel(issue, status, doer))
+	assert.NoError(t, issues_model.NewIssueLabel(issue, low, doer))
+
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: low.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: status.ID})
+	// the label from the fixtures is not scoped and is kept
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: 1})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{
+		Type:     issues_model.CommentTypeLabel,
+		PosterID: doer.ID,
+		IssueID:  issue.ID,
+		LabelID:  high.ID,
+		Content:  "",
+	})
+	unittest.CheckConsistencyFor(t, &issues_model.Issue{}, &issues_model.Label{})
+}
+
 func TestNewIssueLabels(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	label1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
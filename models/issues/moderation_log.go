@@ -0,0 +1,166 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(ModerationLog))
+}
+
+// ModerationAction is the kind of a moderation of issues and comments
+type ModerationAction string
+
+// List of moderation actions
+const (
+	ModerationActionHideComment   ModerationAction = "hide_comment"
+	ModerationActionUnhideComment ModerationAction = "unhide_comment"
+	ModerationActionEditComment   ModerationAction = "edit_comment"
+	ModerationActionDeleteComment ModerationAction = "delete_comment"
+	ModerationActionLock          ModerationAction = "lock"
+	ModerationActionUnlock        ModerationAction = "unlock"
+)
+
+// IsValid checks if the moderation action is known
+func (a ModerationAction) IsValid() bool {
+	switch a {
+	case ModerationActionHideComment, ModerationActionUnhideComment, ModerationActionEditComment,
+		ModerationActionDeleteComment, ModerationActionLock, ModerationActionUnlock:
+		return true
+	}
+	return false
+}
+
+// CommentHideReasons are the reasons a comment can be hidden for
+var CommentHideReasons = []string{"spam", "abuse", "off-topic", "outdated", "duplicate", "resolved"}
+
+// IsValidCommentHideReason checks if the reason is one of CommentHideReasons
+func IsValidCommentHideReason(reason string) bool {
+	for _, r := range CommentHideReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ModerationLog records a moderation of an issue or a comment.
+// Edits and deletions of comments are only recorded if they are done by someone else than the poster.
+type ModerationLog struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	IssueID     int64              `xorm:"INDEX NOT NULL"`
+	CommentID   int64              `xorm:"NOT NULL DEFAULT 0"` // 0 for moderations of the issue
+	DoerID      int64              `xorm:"INDEX NOT NULL"`
+	Action      ModerationAction   `xorm:"INDEX NOT NULL"`
+	Reason      string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+
+	Doer  *user_model.User `xorm:"-"`
+	Issue *Issue           `xorm:"-"`
+}
+
+// InsertModerationLog stores a moderation log entry
+func InsertModerationLog(ctx context.Context, l *ModerationLog) error {
+	_, err := db.GetEngine(ctx).Insert(l)
+	return err
+}
+
+// GetLatestModerationLog returns the latest entry of the issue with the action, nil if there is none
+func GetLatestModerationLog(ctx context.Context, issueID int64, action ModerationAction) (*ModerationLog, error) {
+	l := &ModerationLog{}
+	has, err := db.GetEngine(ctx).
+		Where(builder.Eq{"issue_id": issueID, "action": action}).
+		OrderBy("created_unix DESC, id DESC").
+		Get(l)
+	if err != nil || !has {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ModerationLogList is a list of moderation log entries
+type ModerationLogList []*ModerationLog
+
+// LoadAttributes loads the doers and the issues of the entries, deleted doers are replaced by the ghost user
+func (logs ModerationLogList) LoadAttributes(ctx context.Context) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	doerIDs := make([]int64, 0, len(logs))
+	issueIDs := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		doerIDs = append(doerIDs, l.DoerID)
+		issueIDs = append(issueIDs, l.IssueID)
+	}
+
+	doers := make(map[int64]*user_model.User, len(doerIDs))
+	if err := db.GetEngine(ctx).In("id", doerIDs).Find(&doers); err != nil {
+		return err
+	}
+	issues := make(map[int64]*Issue, len(issueIDs))
+	if err := db.GetEngine(ctx).In("id", issueIDs).Find(&issues); err != nil {
+		return err
+	}
+
+	for _, l := range logs {
+		l.Doer = doers[l.DoerID]
+		if l.Doer == nil {
+			l.Doer = user_model.NewGhostUser()
+		}
+		l.Issue = issues[l.IssueID]
+	}
+	return nil
+}
+
+// ModerationLogSearchOptions filters the moderation log
+type ModerationLogSearchOptions struct {
+	RepoID  int64
+	IssueID int64
+	DoerID  int64
+	Action  ModerationAction
+	db.Paginator
+}
+
+func (opts *ModerationLogSearchOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.IssueID != 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	if opts.DoerID != 0 {
+		cond = cond.And(builder.Eq{"doer_id": opts.DoerID})
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	return cond
+}
+
+// SearchModerationLogs gets the moderation log entries matching the search options, newest first
+func SearchModerationLogs(ctx context.Context, opts *ModerationLogSearchOptions) (ModerationLogList, int64, error) {
+	sess := db.GetEngine(ctx).
+		Where(opts.toConds()).
+		OrderBy("created_unix DESC, id DESC")
+
+	if opts.Paginator != nil {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	logs := make(ModerationLogList, 0, 10)
+	count, err := sess.FindAndCount(&logs)
+	return logs, count, err
+}
@@ -192,6 +192,12 @@ func DeletePullsByBaseRepoID(ctx context.Context, repoID int64) error {
 		return err
 	}
 
+	// Delete merge checklist checks
+	if _, err := db.GetEngine(ctx).In("pull_id", deleteCond).
+		Delete(&MergeChecklistCheck{}); err != nil {
+		return err
+	}
+
 	_, err := db.DeleteByBean(ctx, &PullRequest{BaseRepoID: repoID})
 	return err
 }
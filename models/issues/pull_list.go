@@ -7,6 +7,7 @@ package issues
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"code.gitea.io/gitea/models/db"
 	access_model "code.gitea.io/gitea/models/perm/access"
@@ -16,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -28,8 +30,8 @@ type PullRequestsOptions struct {
 	MilestoneID int64
 }
 
-func listPullRequestStatement(baseRepoID int64, opts *PullRequestsOptions) (*xorm.Session, error) {
-	sess := db.GetEngine(db.DefaultContext).Where("pull_request.base_repo_id=?", baseRepoID)
+func listPullRequestStatement(baseRepoCond builder.Cond, opts *PullRequestsOptions) (*xorm.Session, error) {
+	sess := db.GetEngine(db.DefaultContext).Where(baseRepoCond)
 
 	sess.Join("INNER", "issue", "pull_request.issue_id = issue.id")
 	switch opts.State {
@@ -127,11 +129,20 @@ func GetPullRequestIDsByCheckStatus(status PullRequestStatus) ([]int64, error) {
 
 // PullRequests returns all pull requests for a base Repo by the given conditions
 func PullRequests(baseRepoID int64, opts *PullRequestsOptions) ([]*PullRequest, int64, error) {
+	return findPullRequests(builder.Eq{"pull_request.base_repo_id": baseRepoID}, opts)
+}
+
+// PullRequestsInRepos returns all pull requests for the base repos by the given conditions
+func PullRequestsInRepos(baseRepoIDs []int64, opts *PullRequestsOptions) ([]*PullRequest, int64, error) {
+	return findPullRequests(builder.In("pull_request.base_repo_id", baseRepoIDs), opts)
+}
+
+func findPullRequests(baseRepoCond builder.Cond, opts *PullRequestsOptions) ([]*PullRequest, int64, error) {
 	if opts.Page <= 0 {
 		opts.Page = 1
 	}
 
-	countSession, err := listPullRequestStatement(baseRepoID, opts)
+	countSession, err := listPullRequestStatement(baseRepoCond, opts)
 	if err != nil {
 		log.Error("listPullRequestStatement: %v", err)
 		return nil, 0, err
@@ -142,7 +153,7 @@ func PullRequests(baseRepoID int64, opts *PullRequestsOptions) ([]*PullRequest,
 		return nil, maxResults, err
 	}
 
-	findSession, err := listPullRequestStatement(baseRepoID, opts)
+	findSession, err := listPullRequestStatement(baseRepoCond, opts)
 	sortIssuesSession(findSession, opts.SortType, 0)
 	if err != nil {
 		log.Error("listPullRequestStatement: %v", err)
@@ -153,6 +164,37 @@ func PullRequests(baseRepoID int64, opts *PullRequestsOptions) ([]*PullRequest,
 	return prs, maxResults, findSession.Find(&prs)
 }
 
+// GetMergedPullRequestsByMergedCommitIDs returns the merged pull requests of a base repository
+// whose merge commit is one of the given commits, ordered by merge time.
+func GetMergedPullRequestsByMergedCommitIDs(ctx context.Context, baseRepoID int64, commitIDs []string) (PullRequestList, error) {
+	prs := make(PullRequestList, 0, 10)
+	left := len(commitIDs)
+	for left > 0 {
+		limit := db.DefaultMaxInSize
+		if left < limit {
+			limit = left
+		}
+		batch := make(PullRequestList, 0, 10)
+		if err := db.GetEngine(ctx).
+			Where("base_repo_id = ? AND has_merged = ?", baseRepoID, true).
+			In("merged_commit_id", commitIDs[:limit]).
+			Find(&batch); err != nil {
+			return nil, err
+		}
+		prs = append(prs, batch...)
+		left -= limit
+		commitIDs = commitIDs[limit:]
+	}
+
+	sort.SliceStable(prs, func(i, j int) bool {
+		if prs[i].MergedUnix != prs[j].MergedUnix {
+			return prs[i].MergedUnix < prs[j].MergedUnix
+		}
+		return prs[i].ID < prs[j].ID
+	})
+	return prs, prs.loadAttributes(ctx)
+}
+
 // PullRequestList defines a list of pull requests
 type PullRequestList []*PullRequest
 
@@ -0,0 +1,194 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MergeChecklistItem is an item of the merge checklist of a repository.
+// All items have to be checked on a pull request before it can be merged.
+type MergeChecklistItem struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	Name        string             `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+// MergeChecklistCheck records that an item of the merge checklist was checked on a pull request
+type MergeChecklistCheck struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PullID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	ItemID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(MergeChecklistItem))
+	db.RegisterModel(new(MergeChecklistCheck))
+}
+
+// ErrMergeChecklistItemNotExist represents a "MergeChecklistItemNotExist" kind of error.
+type ErrMergeChecklistItemNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrMergeChecklistItemNotExist checks if an error is a ErrMergeChecklistItemNotExist.
+func IsErrMergeChecklistItemNotExist(err error) bool {
+	_, ok := err.(ErrMergeChecklistItemNotExist)
+	return ok
+}
+
+func (err ErrMergeChecklistItemNotExist) Error() string {
+	return fmt.Sprintf("merge checklist item does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+// ErrMergeChecklistItemAlreadyExist represents a "MergeChecklistItemAlreadyExist" kind of error.
+type ErrMergeChecklistItemAlreadyExist struct {
+	Name string
+}
+
+// IsErrMergeChecklistItemAlreadyExist checks if an error is a ErrMergeChecklistItemAlreadyExist.
+func IsErrMergeChecklistItemAlreadyExist(err error) bool {
+	_, ok := err.(ErrMergeChecklistItemAlreadyExist)
+	return ok
+}
+
+func (err ErrMergeChecklistItemAlreadyExist) Error() string {
+	return fmt.Sprintf("merge checklist item already exists [name: %s]", err.Name)
+}
+
+// MergeChecklistItemState is an item of the merge checklist together with its state on a pull request
+type MergeChecklistItemState struct {
+	*MergeChecklistItem
+	Check *MergeChecklistCheck
+}
+
+// IsChecked returns true if the item was checked on the pull request
+func (s *MergeChecklistItemState) IsChecked() bool {
+	return s.Check != nil
+}
+
+// CreateMergeChecklistItem adds an item to the merge checklist of a repository
+func CreateMergeChecklistItem(ctx context.Context, item *MergeChecklistItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return fmt.Errorf("empty merge checklist item name")
+	}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND name = ?", item.RepoID, item.Name).Exist(new(MergeChecklistItem))
+	if err != nil {
+		return err
+	} else if has {
+		return ErrMergeChecklistItemAlreadyExist{item.Name}
+	}
+	return db.Insert(ctx, item)
+}
+
+// GetMergeChecklistItem returns the item of the merge checklist of a repository
+func GetMergeChecklistItem(ctx context.Context, repoID, id int64) (*MergeChecklistItem, error) {
+	item := new(MergeChecklistItem)
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(item)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMergeChecklistItemNotExist{ID: id, RepoID: repoID}
+	}
+	return item, nil
+}
+
+// GetMergeChecklistItems returns the merge checklist of a repository
+func GetMergeChecklistItems(ctx context.Context, repoID int64) ([]*MergeChecklistItem, error) {
+	items := make([]*MergeChecklistItem, 0, 5)
+	return items, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("id").Find(&items)
+}
+
+// DeleteMergeChecklistItem removes an item from the merge checklist of a repository together with its checks
+func DeleteMergeChecklistItem(ctx context.Context, repoID, id int64) error {
+	return db.WithTx(func(ctx context.Context) error {
+		n, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(MergeChecklistItem))
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return ErrMergeChecklistItemNotExist{ID: id, RepoID: repoID}
+		}
+		_, err = db.GetEngine(ctx).Where("item_id = ?", id).Delete(new(MergeChecklistCheck))
+		return err
+	}, ctx)
+}
+
+// GetMergeChecklistState returns the items of the merge checklist of the repository of a pull request with their state
+func GetMergeChecklistState(ctx context.Context, pr *PullRequest) ([]*MergeChecklistItemState, error) {
+	items, err := GetMergeChecklistItems(ctx, pr.BaseRepoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return []*MergeChecklistItemState{}, nil
+	}
+
+	checks := make([]*MergeChecklistCheck, 0, len(items))
+	if err := db.GetEngine(ctx).Where("pull_id = ?", pr.ID).Find(&checks); err != nil {
+		return nil, err
+	}
+	checkMap := make(map[int64]*MergeChecklistCheck, len(checks))
+	for _, c := range checks {
+		checkMap[c.ItemID] = c
+	}
+
+	states := make([]*MergeChecklistItemState, 0, len(items))
+	for _, item := range items {
+		states = append(states, &MergeChecklistItemState{
+			MergeChecklistItem: item,
+			Check:              checkMap[item.ID],
+		})
+	}
+	return states, nil
+}
+
+// IsMergeChecklistComplete returns true if all items of the merge checklist are checked on the pull request
+func IsMergeChecklistComplete(ctx context.Context, pr *PullRequest) (bool, error) {
+	states, err := GetMergeChecklistState(ctx, pr)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range states {
+		if !s.IsChecked() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CheckMergeChecklistItem checks an item of the merge checklist on a pull request, checking it again does nothing
+func CheckMergeChecklistItem(ctx context.Context, pr *PullRequest, itemID, doerID int64) error {
+	if _, err := GetMergeChecklistItem(ctx, pr.BaseRepoID, itemID); err != nil {
+		return err
+	}
+	has, err := db.GetEngine(ctx).Where("pull_id = ? AND item_id = ?", pr.ID, itemID).Exist(new(MergeChecklistCheck))
+	if err != nil || has {
+		return err
+	}
+	return db.Insert(ctx, &MergeChecklistCheck{
+		PullID: pr.ID,
+		ItemID: itemID,
+		DoerID: doerID,
+	})
+}
+
+// UncheckMergeChecklistItem unchecks an item of the merge checklist on a pull request
+func UncheckMergeChecklistItem(ctx context.Context, pr *PullRequest, itemID int64) error {
+	if _, err := GetMergeChecklistItem(ctx, pr.BaseRepoID, itemID); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("pull_id = ? AND item_id = ?", pr.ID, itemID).Delete(new(MergeChecklistCheck))
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeChecklist(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: 1})
+
+	complete, err := issues_model.IsMergeChecklistComplete(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.True(t, complete, "an empty checklist is complete")
+
+	docs := &issues_model.MergeChecklistItem{RepoID: pr.BaseRepoID, Name: " Docs updated "}
+	assert.NoError(t, issues_model.CreateMergeChecklistItem(db.DefaultContext, docs))
+	assert.Equal(t, "Docs updated", docs.Name)
+	qa := &issues_model.MergeChecklistItem{RepoID: pr.BaseRepoID, Name: "QA sign-off"}
+	assert.NoError(t, issues_model.CreateMergeChecklistItem(db.DefaultContext, qa))
+
+	err = issues_model.CreateMergeChecklistItem(db.DefaultContext, &issues_model.MergeChecklistItem{RepoID: pr.BaseRepoID, Name: "QA sign-off"})
+	assert.True(t, issues_model.IsErrMergeChecklistItemAlreadyExist(err))
+
+	assert.NoError(t, issues_model.CheckMergeChecklistItem(db.DefaultContext, pr, docs.ID, 2))
+	assert.NoError(t, issues_model.CheckMergeChecklistItem(db.DefaultContext, pr, docs.ID, 2))
+
+	states, err := issues_model.GetMergeChecklistState(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	if assert.Len(t, states, 2) {
+		assert.True(t, states[0].IsChecked())
+		assert.EqualValues(t, 2, states[0].Check.DoerID)
+		assert.False(t, states[1].IsChecked())
+	}
+	complete, err = issues_model.IsMergeChecklistComplete(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	assert.NoError(t, issues_model.CheckMergeChecklistItem(db.DefaultContext, pr, qa.ID, 2))
+	complete, err = issues_model.IsMergeChecklistComplete(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.True(t, complete)
+
+	assert.NoError(t, issues_model.UncheckMergeChecklistItem(db.DefaultContext, pr, qa.ID))
+	complete, err = issues_model.IsMergeChecklistComplete(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	// items of other repositories can't be checked
+	other := &issues_model.MergeChecklistItem{RepoID: pr.BaseRepoID + 1, Name: "Other"}
+	assert.NoError(t, issues_model.CreateMergeChecklistItem(db.DefaultContext, other))
+	err = issues_model.CheckMergeChecklistItem(db.DefaultContext, pr, other.ID, 2)
+	assert.True(t, issues_model.IsErrMergeChecklistItemNotExist(err))
+
+	assert.NoError(t, issues_model.DeleteMergeChecklistItem(db.DefaultContext, pr.BaseRepoID, qa.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.MergeChecklistCheck{ItemID: qa.ID})
+	complete, err = issues_model.IsMergeChecklistComplete(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.True(t, complete)
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// PullReviewStat holds the review metrics of a pull request.
+// The metrics are computed by the review statistics aggregation from the pull request, its reviews and its commits.
+type PullReviewStat struct {
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"INDEX NOT NULL"`
+	IssueID         int64              `xorm:"UNIQUE NOT NULL"`
+	PosterID        int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL"`           // creation of the pull request
+	FirstReviewUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"` // first review by someone else than the poster, 0 if not reviewed
+	MergedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"` // 0 if not merged
+	Additions       int64              `xorm:"NOT NULL DEFAULT 0"`
+	Deletions       int64              `xorm:"NOT NULL DEFAULT 0"`
+	ChangedFiles    int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"INDEX updated"` // last aggregation
+}
+
+func init() {
+	db.RegisterModel(new(PullReviewStat))
+}
+
+// IsReviewed returns true if the pull request got a review
+func (s *PullReviewStat) IsReviewed() bool {
+	return s.FirstReviewUnix != 0
+}
+
+// IsMerged returns true if the pull request was merged
+func (s *PullReviewStat) IsMerged() bool {
+	return s.MergedUnix != 0
+}
+
+// ChangedLines returns the number of changed lines of the pull request
+func (s *PullReviewStat) ChangedLines() int64 {
+	return s.Additions + s.Deletions
+}
+
+// FindPullRequestsForReviewStats returns the pull requests after the pull request id whose review statistics
+// are missing or outdated, in ascending order of their id
+func FindPullRequestsForReviewStats(ctx context.Context, afterID int64, limit int) ([]*PullRequest, error) {
+	prs := make([]*PullRequest, 0, limit)
+	return prs, db.GetEngine(ctx).
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Join("LEFT", "pull_review_stat", "pull_review_stat.issue_id = pull_request.issue_id").
+		Where(builder.Gt{"pull_request.id": afterID}).
+		And(builder.Or(
+			builder.IsNull{"pull_review_stat.id"},
+			builder.Expr("issue.updated_unix >= pull_review_stat.updated_unix"),
+		)).
+		OrderBy("pull_request.id ASC").
+		Limit(limit).
+		Find(&prs)
+}
+
+// GetFirstReviewUnix returns the time of the first submitted review of the pull request issue
+// by someone else than the poster, 0 if there is none
+func GetFirstReviewUnix(ctx context.Context, issueID, posterID int64) (timeutil.TimeStamp, error) {
+	review := &Review{}
+	has, err := db.GetEngine(ctx).
+		Where(builder.Eq{"issue_id": issueID}).
+		And(builder.Neq{"reviewer_id": posterID}).
+		And(builder.In("type", ReviewTypeApprove, ReviewTypeComment, ReviewTypeReject)).
+		OrderBy("created_unix ASC").
+		Get(review)
+	if err != nil || !has {
+		return 0, err
+	}
+	return review.CreatedUnix, nil
+}
+
+// SavePullReviewStat inserts or updates the review statistics of a pull request
+func SavePullReviewStat(ctx context.Context, stat *PullReviewStat) error {
+	e := db.GetEngine(ctx)
+
+	existing := &PullReviewStat{}
+	has, err := e.Where("issue_id = ?", stat.IssueID).Cols("id").Get(existing)
+	if err != nil {
+		return err
+	}
+	if !has {
+		_, err = e.Insert(stat)
+		return err
+	}
+	stat.ID = existing.ID
+	_, err = e.ID(stat.ID).AllCols().Update(stat)
+	return err
+}
+
+// ReviewStatsOptions selects the pull requests and reviews to aggregate
+type ReviewStatsOptions struct {
+	RepoID   int64
+	RepoCond builder.Cond       // condition on the repository table, used if RepoID is 0
+	Since    timeutil.TimeStamp // inclusive
+	Until    timeutil.TimeStamp // exclusive
+}
+
+func (opts *ReviewStatsOptions) repoCond(column string) builder.Cond {
+	if opts.RepoID != 0 {
+		return builder.Eq{column: opts.RepoID}
+	}
+	return builder.In(column, builder.Select("id").From("repository").Where(opts.RepoCond))
+}
+
+func (opts *ReviewStatsOptions) rangeCond(column string) builder.Cond {
+	return builder.And(builder.Gte{column: opts.Since}, builder.Lt{column: opts.Until})
+}
+
+// CountOpenedPullReviewStats counts the pull requests opened in the range
+func CountOpenedPullReviewStats(ctx context.Context, opts *ReviewStatsOptions) (int64, error) {
+	return db.GetEngine(ctx).
+		Where(opts.repoCond("repo_id")).
+		And(opts.rangeCond("created_unix")).
+		Count(&PullReviewStat{})
+}
+
+// FindReviewedPullReviewStats returns the statistics of the pull requests first reviewed in the range
+func FindReviewedPullReviewStats(ctx context.Context, opts *ReviewStatsOptions) ([]*PullReviewStat, error) {
+	stats := make([]*PullReviewStat, 0, 10)
+	return stats, db.GetEngine(ctx).
+		Where(opts.repoCond("repo_id")).
+		And(opts.rangeCond("first_review_unix")).
+		Find(&stats)
+}
+
+// FindMergedPullReviewStats returns the statistics of the pull requests merged in the range
+func FindMergedPullReviewStats(ctx context.Context, opts *ReviewStatsOptions) ([]*PullReviewStat, error) {
+	stats := make([]*PullReviewStat, 0, 10)
+	return stats, db.GetEngine(ctx).
+		Where(opts.repoCond("repo_id")).
+		And(opts.rangeCond("merged_unix")).
+		Find(&stats)
+}
+
+// ReviewerReviewCount is the number of reviews of a type submitted by a reviewer
+type ReviewerReviewCount struct {
+	ReviewerID int64
+	Type       ReviewType
+	Count      int64
+}
+
+// GetReviewCountsPerReviewer counts the submitted reviews of pull requests in the range per reviewer and type
+func GetReviewCountsPerReviewer(ctx context.Context, opts *ReviewStatsOptions) ([]*ReviewerReviewCount, error) {
+	counts := make([]*ReviewerReviewCount, 0, 10)
+	return counts, db.GetEngine(ctx).
+		Table("review").
+		Select("review.reviewer_id, review.type, COUNT(*) AS count").
+		Join("INNER", "issue", "issue.id = review.issue_id").
+		Where(opts.repoCond("issue.repo_id")).
+		And(opts.rangeCond("review.created_unix")).
+		And(builder.Gt{"review.reviewer_id": 0}).
+		And(builder.In("review.type", ReviewTypeApprove, ReviewTypeComment, ReviewTypeReject)).
+		GroupBy("review.reviewer_id, review.type").
+		Find(&counts)
+}
@@ -413,6 +413,72 @@ var migrations = []Migration{
 	NewMigration("Add badges to users", createUserBadgesTable),
 	// v225 -> v226
 	NewMigration("Alter gpg_key/public_key content TEXT fields to MEDIUMTEXT", alterPublicGPGKeyContentFieldsToMediumText),
+	// v226 -> v227
+	NewMigration("Create last commit cache table", createLastCommitCacheTable),
+	// v227 -> v228
+	NewMigration("Add scope and owner to access tokens", addScopeToAccessToken),
+	// v228 -> v229
+	NewMigration("Add SHA256 checksum to attachments", addHashSHA256ToAttachment),
+	// v229 -> v230
+	NewMigration("Add per-package visibility and team access", addPackageVisibilityAndTeamAccess),
+	// v230 -> v231
+	NewMigration("Add package download statistics", createPackageDownloadTable),
+	// v231 -> v232
+	NewMigration("Add login source health checks and fallback sources", addLoginSourceHealthAndFallback),
+	// v232 -> v233
+	NewMigration("Add organization join requests", createOrgJoinRequestTable),
+	// v233 -> v234
+	NewMigration("Add expiry to collaborations", addExpiryToCollaboration),
+	// v234 -> v235
+	NewMigration("Create pages deployment table", createPagesDeploymentTable),
+	// v235 -> v236
+	NewMigration("Create pull review stat table", createPullReviewStatTable),
+	// v236 -> v237
+	NewMigration("Create package share table", createPackageShareTable),
+	// v237 -> v238
+	NewMigration("Add last used and expiry to SSH keys", addLastUsedAndExpiryToSSHKeys),
+	// v238 -> v239
+	NewMigration("Add schedule and acceptance to repository transfers", addScheduleToRepoTransfer),
+	// v239 -> v240
+	NewMigration("Create package audit log table", createPackageAuditLogTable),
+	// v240 -> v241
+	NewMigration("Add comment moderation and timed issue locks", addCommentModeration),
+	// v241 -> v242
+	NewMigration("Add package dependency table", addPackageDependencyTable),
+	// v242 -> v243
+	NewMigration("Add federated instance table", addFederatedInstanceTable),
+	// v243 -> v244
+	NewMigration("Add package mirror table", addPackageMirrorTable),
+	// v244 -> v245
+	NewMigration("Add organization default webhooks", addDefaultWebhookColumns),
+	// v245 -> v246
+	NewMigration("Add repository custom properties", addRepoPropertyTables),
+	// v246 -> v247
+	NewMigration("Create audit log table", createAuditLogTable),
+	// v247 -> v248
+	NewMigration("Add name policy and name claim tables", addNamePolicyTables),
+	// v248 -> v249
+	NewMigration("Add service account table", addServiceAccountTable),
+	// v249 -> v250
+	NewMigration("Add merge checklist tables", addMergeChecklistTables),
+	// v250 -> v251
+	NewMigration("Add usage report table", addUsageReportTable),
+	// v251 -> v252
+	NewMigration("Add two-factor recovery table", addTwoFactorRecoveryTable),
+	// v252 -> v253
+	NewMigration("Add package vendor grant table", addPackageVendorGrantTable),
+	// v253 -> v254
+	NewMigration("Add package container domain table", addPackageContainerDomainTable),
+	// v254 -> v255
+	NewMigration("Add reason column to issue watch", addReasonToIssueWatch),
+	// v255 -> v256
+	NewMigration("Add repository policies of organizations", addRepoPolicyTable),
+	// v256 -> v257
+	NewMigration("Add repository statistics recalculation table", addStatsRecalculationTable),
+	// v257 -> v258
+	NewMigration("Add release notes to package versions", addReleaseNotesToPackageVersion),
+	// v258 -> v259
+	NewMigration("Add scope to package audit log", addScopeToPackageAuditLog),
 }
 
 // GetCurrentDBVersion returns the current db version
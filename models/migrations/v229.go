@@ -0,0 +1,24 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addPackageVisibilityAndTeamAccess(x *xorm.Engine) error {
+	type Package struct {
+		Visibility int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type PackageTeam struct {
+		ID         int64 `xorm:"pk autoincr"`
+		PackageID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		TeamID     int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		AccessMode int   `xorm:"NOT NULL"`
+	}
+
+	return x.Sync2(new(Package), new(PackageTeam))
+}
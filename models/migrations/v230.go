@@ -0,0 +1,24 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPackageDownloadTable(x *xorm.Engine) error {
+	type PackageDownload struct {
+		ID        int64              `xorm:"pk autoincr"`
+		VersionID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		FileID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Day       timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Client    string             `xorm:"UNIQUE(s) NOT NULL"`
+		Count     int64              `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(PackageDownload))
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type addLoginSourceHealthAndFallbackSource struct {
+	FallbackSourceID int64 `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// TableName sets the name of this table
+func (*addLoginSourceHealthAndFallbackSource) TableName() string {
+	return "login_source"
+}
+
+type addLoginSourceHealthAndFallbackHealth struct {
+	SourceID        int64  `xorm:"pk"`
+	IsHealthy       bool   `xorm:"NOT NULL DEFAULT true"`
+	Error           string `xorm:"TEXT"`
+	CheckedUnix     timeutil.TimeStamp
+	LastHealthyUnix timeutil.TimeStamp
+}
+
+// TableName sets the name of this table
+func (*addLoginSourceHealthAndFallbackHealth) TableName() string {
+	return "login_source_health"
+}
+
+func addLoginSourceHealthAndFallback(x *xorm.Engine) error {
+	return x.Sync2(new(addLoginSourceHealthAndFallbackSource), new(addLoginSourceHealthAndFallbackHealth))
+}
@@ -0,0 +1,27 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createOrgJoinRequestTable(x *xorm.Engine) error {
+	type JoinRequest struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL"`
+		TeamID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		Message     string             `xorm:"TEXT"`
+		Status      int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ReviewerID  int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(JoinRequest))
+}
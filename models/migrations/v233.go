@@ -0,0 +1,20 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addExpiryToCollaboration(x *xorm.Engine) error {
+	type Collaboration struct {
+		ExpiresUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RemindedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(Collaboration))
+}
@@ -0,0 +1,26 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPagesDeploymentTable(x *xorm.Engine) error {
+	type PagesDeployment struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RepoID       int64  `xorm:"UNIQUE NOT NULL"`
+		CommitID     string `xorm:"VARCHAR(40)"`
+		Directory    string
+		Status       int    `xorm:"NOT NULL DEFAULT 0"`
+		Error        string `xorm:"TEXT"`
+		DeployedUnix timeutil.TimeStamp
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(PagesDeployment))
+}
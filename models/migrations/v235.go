@@ -0,0 +1,29 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPullReviewStatTable(x *xorm.Engine) error {
+	type PullReviewStat struct {
+		ID              int64              `xorm:"pk autoincr"`
+		RepoID          int64              `xorm:"INDEX NOT NULL"`
+		IssueID         int64              `xorm:"UNIQUE NOT NULL"`
+		PosterID        int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		FirstReviewUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		MergedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Additions       int64              `xorm:"NOT NULL DEFAULT 0"`
+		Deletions       int64              `xorm:"NOT NULL DEFAULT 0"`
+		ChangedFiles    int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(PullReviewStat))
+}
@@ -0,0 +1,22 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPackageShareTable(x *xorm.Engine) error {
+	type PackageShare struct {
+		ID          int64              `xorm:"pk autoincr"`
+		PackageID   int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		OrgID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(PackageShare))
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addLastUsedAndExpiryToSSHKeys(x *xorm.Engine) error {
+	type PublicKey struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		ExpiresUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RemindedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type DeployKey struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		ExpiresUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RemindedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(PublicKey), new(DeployKey)); err != nil {
+		return err
+	}
+
+	// the update time of a key was only changed when the key was used
+	if _, err := x.Exec("UPDATE public_key SET last_used_unix = updated_unix WHERE updated_unix > created_unix"); err != nil {
+		return err
+	}
+	_, err := x.Exec("UPDATE deploy_key SET last_used_unix = updated_unix WHERE updated_unix > created_unix")
+	return err
+}
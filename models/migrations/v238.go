@@ -0,0 +1,21 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addScheduleToRepoTransfer(x *xorm.Engine) error {
+	type RepoTransfer struct {
+		AcceptorID    int64
+		AcceptedUnix  timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		ScheduledUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(RepoTransfer))
+}
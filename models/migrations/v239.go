@@ -0,0 +1,31 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createPackageAuditLogTable(x *xorm.Engine) error {
+	type PackageAuditLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		Action      string             `xorm:"INDEX NOT NULL"`
+		ActorID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ActorName   string             `xorm:"NOT NULL DEFAULT ''"`
+		PackageType string             `xorm:"NOT NULL DEFAULT ''"`
+		PackageName string             `xorm:"NOT NULL DEFAULT ''"`
+		Version     string             `xorm:"NOT NULL DEFAULT ''"`
+		Filename    string             `xorm:"NOT NULL DEFAULT ''"`
+		Detail      string             `xorm:"TEXT"`
+		IP          string             `xorm:"NOT NULL DEFAULT ''"`
+		UserAgent   string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(PackageAuditLog))
+}
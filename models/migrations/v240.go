@@ -0,0 +1,36 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCommentModeration(x *xorm.Engine) error {
+	type Comment struct {
+		HiddenByID   int64 `xorm:"NOT NULL DEFAULT 0"`
+		HiddenReason string
+		HiddenUnix   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type Issue struct {
+		LockedUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type ModerationLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		CommentID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		DoerID      int64              `xorm:"INDEX NOT NULL"`
+		Action      string             `xorm:"INDEX NOT NULL"`
+		Reason      string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(Comment), new(Issue), new(ModerationLog))
+}
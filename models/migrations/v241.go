@@ -0,0 +1,22 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addPackageDependencyTable(x *xorm.Engine) error {
+	type PackageDependency struct {
+		ID           int64  `xorm:"pk autoincr"`
+		VersionID    int64  `xorm:"INDEX NOT NULL"`
+		Kind         string `xorm:"NOT NULL"`
+		Name         string `xorm:"NOT NULL"`
+		LowerName    string `xorm:"INDEX NOT NULL"`
+		VersionRange string
+	}
+
+	return x.Sync2(new(PackageDependency))
+}
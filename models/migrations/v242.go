@@ -0,0 +1,24 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addFederatedInstanceTable(x *xorm.Engine) error {
+	type FederatedInstance struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Host        string             `xorm:"UNIQUE NOT NULL"`
+		Policy      string             `xorm:"NOT NULL"`
+		Reason      string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL"`
+	}
+
+	return x.Sync2(new(FederatedInstance))
+}
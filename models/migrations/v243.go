@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPackageMirrorTable(x *xorm.Engine) error {
+	type PackageMirrorRule struct {
+		Name         string `json:"name"`
+		VersionRange string `json:"version_range,omitempty"`
+	}
+
+	type PackageMirror struct {
+		ID           int64                `xorm:"pk autoincr"`
+		OwnerID      int64                `xorm:"INDEX NOT NULL"`
+		Type         string               `xorm:"NOT NULL"`
+		UpstreamURL  string               `xorm:"TEXT NOT NULL"`
+		Rules        []*PackageMirrorRule `xorm:"JSON TEXT"`
+		CreatorID    int64                `xorm:"NOT NULL DEFAULT 0"`
+		Interval     time.Duration
+		NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+		LastSyncUnix timeutil.TimeStamp
+		LastError    string             `xorm:"TEXT"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(PackageMirror))
+}
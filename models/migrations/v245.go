@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPropertyTables(x *xorm.Engine) error {
+	type PropertyDefinition struct {
+		ID            int64              `xorm:"pk autoincr"`
+		OwnerID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name          string             `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+		Description   string             `xorm:"TEXT"`
+		AllowedValues []string           `xorm:"TEXT JSON"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type RepoProperty struct {
+		ID     int64  `xorm:"pk autoincr"`
+		RepoID int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Name   string `xorm:"UNIQUE(s) INDEX VARCHAR(50) NOT NULL"`
+		Value  string `xorm:"INDEX VARCHAR(255) NOT NULL"`
+	}
+
+	if err := x.Sync2(new(PropertyDefinition), new(RepoProperty)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
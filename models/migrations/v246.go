@@ -0,0 +1,27 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func createAuditLogTable(x *xorm.Engine) error {
+	type AuditLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Action      string             `xorm:"INDEX NOT NULL"`
+		ActorID     int64              `xorm:"INDEX NOT NULL"`
+		ActorName   string             `xorm:"NOT NULL DEFAULT ''"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		UserName    string             `xorm:"NOT NULL DEFAULT ''"`
+		Detail      string             `xorm:"TEXT"`
+		IP          string             `xorm:"NOT NULL DEFAULT ''"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(AuditLog))
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addNamePolicyTables(x *xorm.Engine) error {
+	type NamePolicy struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Kind        string             `xorm:"INDEX NOT NULL"`
+		Pattern     string             `xorm:"NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+
+	type NameClaim struct {
+		ID           int64              `xorm:"pk autoincr"`
+		UserID       int64              `xorm:"INDEX NOT NULL"`
+		Name         string             `xorm:"INDEX NOT NULL"`
+		ClaimantID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		DoerID       int64              `xorm:"NOT NULL"`
+		Status       string             `xorm:"INDEX NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created NOT NULL"`
+		DeadlineUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(NamePolicy), new(NameClaim))
+}
@@ -0,0 +1,30 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMergeChecklistTables(x *xorm.Engine) error {
+	type MergeChecklistItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+
+	type MergeChecklistCheck struct {
+		ID          int64              `xorm:"pk autoincr"`
+		PullID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		ItemID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+
+	return x.Sync2(new(MergeChecklistItem), new(MergeChecklistCheck))
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addUsageReportTable(x *xorm.Engine) error {
+	type UsageReport struct {
+		ID             int64              `xorm:"pk autoincr"`
+		GitSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		LFSSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+		AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+		NumRepos       int64              `xorm:"NOT NULL DEFAULT 0"`
+		NumUsers       int64              `xorm:"NOT NULL DEFAULT 0"`
+		NumOrgs        int64              `xorm:"NOT NULL DEFAULT 0"`
+		TopConsumers   string             `xorm:"TEXT"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(UsageReport))
+}
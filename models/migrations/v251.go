@@ -0,0 +1,25 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addTwoFactorRecoveryTable(x *xorm.Engine) error {
+	type TwoFactorRecovery struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UID         int64              `xorm:"UNIQUE NOT NULL"`
+		TokenSalt   string             `xorm:"NOT NULL"`
+		TokenHash   string             `xorm:"NOT NULL"`
+		IssuerID    int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+		ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync2(new(TwoFactorRecovery))
+}
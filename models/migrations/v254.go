@@ -0,0 +1,25 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addReasonToIssueWatch(x *xorm.Engine) error {
+	type IssueWatch struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"UNIQUE(watch) NOT NULL"`
+		IssueID     int64              `xorm:"UNIQUE(watch) NOT NULL"`
+		IsWatching  bool               `xorm:"NOT NULL"`
+		Reason      string             `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL"`
+	}
+
+	return x.Sync2(new(IssueWatch))
+}
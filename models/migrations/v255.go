@@ -0,0 +1,26 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPolicyTable(x *xorm.Engine) error {
+	type RepoPolicy struct {
+		ID                     int64              `xorm:"pk autoincr"`
+		OrgID                  int64              `xorm:"UNIQUE NOT NULL"`
+		NamePattern            string             `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+		NamePatternDescription string             `xorm:"TEXT"`
+		RequireTopic           bool               `xorm:"NOT NULL DEFAULT false"`
+		RequiredProperties     []string           `xorm:"TEXT JSON"`
+		CreatedUnix            timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix            timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync2(new(RepoPolicy))
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStatsRecalculationTable(x *xorm.Engine) error {
+	type StatsRecalculation struct {
+		ID           int64              `xorm:"pk autoincr"`
+		DoerID       int64              `xorm:"NOT NULL DEFAULT 0"`
+		Kinds        []string           `xorm:"TEXT JSON"`
+		Status       int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		MaxRepoID    int64              `xorm:"NOT NULL DEFAULT 0"`
+		LastRepoID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		Total        int64              `xorm:"NOT NULL DEFAULT 0"`
+		Queued       int64              `xorm:"NOT NULL DEFAULT 0"`
+		Processed    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Failed       int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+		FinishedUnix timeutil.TimeStamp
+	}
+
+	return x.Sync2(new(StatsRecalculation))
+}
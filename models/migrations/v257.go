@@ -0,0 +1,17 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addReleaseNotesToPackageVersion(x *xorm.Engine) error {
+	type PackageVersion struct {
+		ReleaseNotes string `xorm:"TEXT"`
+	}
+
+	return x.Sync2(new(PackageVersion))
+}
@@ -0,0 +1,23 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addScopeToPackageAuditLog(x *xorm.Engine) error {
+	type PackageAuditLog struct {
+		Scope string `xorm:"INDEX NOT NULL DEFAULT 'package'"`
+	}
+
+	if err := x.Sync2(new(PackageAuditLog)); err != nil {
+		return err
+	}
+
+	// drop the orphaned table introduced in `v246`, admin actions are now recorded in the package audit log
+	_ = x.DropTables("audit_log")
+	return nil
+}
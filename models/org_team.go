@@ -15,6 +15,7 @@ import (
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
@@ -446,6 +447,19 @@ func DeleteTeam(t *organization.Team) error {
 		return err
 	}
 
+	// Delete join requests for the team.
+	if err := organization.DeleteJoinRequestsByTeamID(ctx, t.ID); err != nil {
+		return err
+	}
+
+	// Delete package access of the team.
+	if err := packages_model.DeletePackageTeamsByTeamID(ctx, t.ID); err != nil {
+		return err
+	}
+	if err := packages_model.DeleteVendorGrantsByTeamID(ctx, t.ID); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(organization.Team)); err != nil {
 		return err
@@ -0,0 +1,178 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package organization
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(JoinRequest))
+}
+
+// JoinRequestStatus is the review state of a join request
+type JoinRequestStatus int
+
+const (
+	// JoinRequestStatusPending the request waits for the review of an owner
+	JoinRequestStatusPending JoinRequestStatus = iota
+	// JoinRequestStatusApproved the request was approved and the user was added
+	JoinRequestStatusApproved
+	// JoinRequestStatusRejected the request was rejected
+	JoinRequestStatusRejected
+)
+
+// String returns the name of the status
+func (s JoinRequestStatus) String() string {
+	switch s {
+	case JoinRequestStatusApproved:
+		return "approved"
+	case JoinRequestStatusRejected:
+		return "rejected"
+	}
+	return "pending"
+}
+
+// ErrJoinRequestNotExist represents a "JoinRequestNotExist" kind of error.
+type ErrJoinRequestNotExist struct {
+	ID int64
+}
+
+// IsErrJoinRequestNotExist checks if an error is a ErrJoinRequestNotExist.
+func IsErrJoinRequestNotExist(err error) bool {
+	_, ok := err.(ErrJoinRequestNotExist)
+	return ok
+}
+
+func (err ErrJoinRequestNotExist) Error() string {
+	return fmt.Sprintf("join request does not exist [id: %d]", err.ID)
+}
+
+// ErrJoinRequestAlreadyExist represents a "JoinRequestAlreadyExist" kind of error.
+type ErrJoinRequestAlreadyExist struct {
+	OrgID  int64
+	TeamID int64
+	UserID int64
+}
+
+// IsErrJoinRequestAlreadyExist checks if an error is a ErrJoinRequestAlreadyExist.
+func IsErrJoinRequestAlreadyExist(err error) bool {
+	_, ok := err.(ErrJoinRequestAlreadyExist)
+	return ok
+}
+
+func (err ErrJoinRequestAlreadyExist) Error() string {
+	return fmt.Sprintf("pending join request already exists [org_id: %d, team_id: %d, user_id: %d]", err.OrgID, err.TeamID, err.UserID)
+}
+
+// JoinRequest represents the request of a user to join an organization or one of its teams
+type JoinRequest struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	TeamID      int64              `xorm:"NOT NULL DEFAULT 0"` // 0 if the reviewer chooses the team
+	UserID      int64              `xorm:"INDEX NOT NULL"`
+	Message     string             `xorm:"TEXT"`
+	Status      JoinRequestStatus  `xorm:"INDEX NOT NULL DEFAULT 0"`
+	ReviewerID  int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsPending returns true if the request was not reviewed yet
+func (jr *JoinRequest) IsPending() bool {
+	return jr.Status == JoinRequestStatusPending
+}
+
+// CreateJoinRequest creates a new pending join request if the user has no pending request for the same team
+func CreateJoinRequest(ctx context.Context, jr *JoinRequest) error {
+	return db.WithTx(func(ctx context.Context) error {
+		exists, err := db.GetEngine(ctx).Where(builder.Eq{
+			"org_id":  jr.OrgID,
+			"team_id": jr.TeamID,
+			"user_id": jr.UserID,
+			"status":  JoinRequestStatusPending,
+		}).Exist(&JoinRequest{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrJoinRequestAlreadyExist{OrgID: jr.OrgID, TeamID: jr.TeamID, UserID: jr.UserID}
+		}
+
+		jr.Status = JoinRequestStatusPending
+		return db.Insert(ctx, jr)
+	}, ctx)
+}
+
+// GetJoinRequestByID gets the join request of the organization with the given id
+func GetJoinRequestByID(ctx context.Context, orgID, id int64) (*JoinRequest, error) {
+	jr := &JoinRequest{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND org_id = ?", id, orgID).Get(jr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrJoinRequestNotExist{ID: id}
+	}
+	return jr, nil
+}
+
+// FindJoinRequestsOptions represents the options to search join requests
+type FindJoinRequestsOptions struct {
+	db.ListOptions
+	OrgID    int64
+	UserID   int64
+	Statuses []JoinRequestStatus // all statuses if empty
+}
+
+func (opts *FindJoinRequestsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OrgID != 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
+	}
+	if opts.UserID != 0 {
+		cond = cond.And(builder.Eq{"user_id": opts.UserID})
+	}
+	if len(opts.Statuses) > 0 {
+		cond = cond.And(builder.In("status", opts.Statuses))
+	}
+	return cond
+}
+
+// FindJoinRequests gets the join requests matching the options, newest first
+func FindJoinRequests(ctx context.Context, opts *FindJoinRequestsOptions) ([]*JoinRequest, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.toConds()).Desc("created_unix").Desc("id")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	jrs := make([]*JoinRequest, 0, 10)
+	count, err := sess.FindAndCount(&jrs)
+	return jrs, count, err
+}
+
+// UpdateJoinRequestStatus stores the review state of the join request
+func UpdateJoinRequestStatus(ctx context.Context, jr *JoinRequest) error {
+	_, err := db.GetEngine(ctx).ID(jr.ID).Cols("team_id", "status", "reviewer_id").Update(jr)
+	return err
+}
+
+// DeleteJoinRequestByID deletes a join request
+func DeleteJoinRequestByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&JoinRequest{})
+	return err
+}
+
+// DeleteJoinRequestsByTeamID deletes the join requests for a team
+func DeleteJoinRequestsByTeamID(ctx context.Context, teamID int64) error {
+	_, err := db.GetEngine(ctx).Where("team_id = ?", teamID).Delete(&JoinRequest{})
+	return err
+}
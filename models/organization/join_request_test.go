@@ -0,0 +1,58 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinRequest(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	jr := &organization.JoinRequest{OrgID: 3, TeamID: 2, UserID: 5, Message: "Please"}
+	assert.NoError(t, organization.CreateJoinRequest(db.DefaultContext, jr))
+	assert.NotZero(t, jr.ID)
+	assert.True(t, jr.IsPending())
+
+	err := organization.CreateJoinRequest(db.DefaultContext, &organization.JoinRequest{OrgID: 3, TeamID: 2, UserID: 5})
+	assert.True(t, organization.IsErrJoinRequestAlreadyExist(err))
+
+	// a request for another team is allowed
+	assert.NoError(t, organization.CreateJoinRequest(db.DefaultContext, &organization.JoinRequest{OrgID: 3, UserID: 5}))
+
+	jrs, count, err := organization.FindJoinRequests(db.DefaultContext, &organization.FindJoinRequestsOptions{
+		OrgID:    3,
+		Statuses: []organization.JoinRequestStatus{organization.JoinRequestStatusPending},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, jrs, 2)
+
+	jr.Status = organization.JoinRequestStatusRejected
+	jr.ReviewerID = 2
+	assert.NoError(t, organization.UpdateJoinRequestStatus(db.DefaultContext, jr))
+
+	jr, err = organization.GetJoinRequestByID(db.DefaultContext, 3, jr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, organization.JoinRequestStatusRejected, jr.Status)
+	assert.EqualValues(t, 2, jr.ReviewerID)
+
+	// a rejected request does not block a new one
+	assert.NoError(t, organization.CreateJoinRequest(db.DefaultContext, &organization.JoinRequest{OrgID: 3, TeamID: 2, UserID: 5}))
+
+	_, err = organization.GetJoinRequestByID(db.DefaultContext, 6, jr.ID)
+	assert.True(t, organization.IsErrJoinRequestNotExist(err))
+
+	assert.NoError(t, organization.DeleteJoinRequestsByTeamID(db.DefaultContext, 2))
+	_, count, err = organization.FindJoinRequests(db.DefaultContext, &organization.FindJoinRequestsOptions{UserID: 5})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
@@ -248,6 +248,10 @@ func CreateOrganization(org *Organization, owner *user_model.User) (err error) {
 		return err
 	}
 
+	if err = user_model.CheckNamePolicies(db.DefaultContext, org.Name, false); err != nil {
+		return err
+	}
+
 	isExist, err := user_model.IsUserExist(db.DefaultContext, 0, org.Name)
 	if err != nil {
 		return err
@@ -356,11 +360,18 @@ func DeleteOrganization(ctx context.Context, org *Organization) error {
 		return fmt.Errorf("%s is a user not an organization", org.Name)
 	}
 
+	if err := disableServiceAccounts(ctx, org.ID); err != nil {
+		return fmt.Errorf("disableServiceAccounts: %v", err)
+	}
+
 	if err := db.DeleteBeans(ctx,
 		&Team{OrgID: org.ID},
 		&OrgUser{OrgID: org.ID},
 		&TeamUser{OrgID: org.ID},
 		&TeamUnit{OrgID: org.ID},
+		&JoinRequest{OrgID: org.ID},
+		&RepoPolicy{OrgID: org.ID},
+		&repo_model.PropertyDefinition{OwnerID: org.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
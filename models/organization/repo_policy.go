@@ -0,0 +1,184 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package organization
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(RepoPolicy))
+}
+
+// RepoPolicyRule is a requirement of a repository policy
+type RepoPolicyRule string
+
+const (
+	// RepoPolicyRuleNamePattern the name of a new repository must match the pattern
+	RepoPolicyRuleNamePattern RepoPolicyRule = "name_pattern"
+	// RepoPolicyRuleTopic a new repository must have at least one topic
+	RepoPolicyRuleTopic RepoPolicyRule = "topic"
+	// RepoPolicyRuleProperty a new repository must have a value for the custom property
+	RepoPolicyRuleProperty RepoPolicyRule = "property"
+)
+
+// ErrRepoPolicyNotExist represents a "RepoPolicyNotExist" kind of error.
+type ErrRepoPolicyNotExist struct {
+	OrgID int64
+}
+
+// IsErrRepoPolicyNotExist checks if an error is a ErrRepoPolicyNotExist.
+func IsErrRepoPolicyNotExist(err error) bool {
+	_, ok := err.(ErrRepoPolicyNotExist)
+	return ok
+}
+
+func (err ErrRepoPolicyNotExist) Error() string {
+	return fmt.Sprintf("repository policy does not exist [org_id: %d]", err.OrgID)
+}
+
+// ErrRepoPolicyViolation represents a "RepoPolicyViolation" kind of error.
+type ErrRepoPolicyViolation struct {
+	Rule RepoPolicyRule
+	// Value is the pattern of a name rule or the name of the property of a property rule
+	Value       string
+	Description string
+}
+
+// IsErrRepoPolicyViolation checks if an error is a ErrRepoPolicyViolation.
+func IsErrRepoPolicyViolation(err error) bool {
+	_, ok := err.(ErrRepoPolicyViolation)
+	return ok
+}
+
+func (err ErrRepoPolicyViolation) Error() string {
+	var msg string
+	switch err.Rule {
+	case RepoPolicyRuleNamePattern:
+		msg = fmt.Sprintf("the repository name must match the pattern %s", err.Value)
+	case RepoPolicyRuleTopic:
+		msg = "the repository must have at least one topic"
+	case RepoPolicyRuleProperty:
+		msg = fmt.Sprintf("the repository must have a value for the custom property %s", err.Value)
+	}
+	if err.Description != "" {
+		msg += ": " + err.Description
+	}
+	return "repository violates the policy of the organization: " + msg
+}
+
+// RepoPolicy represents the requirements an organization sets for the creation of its repositories
+type RepoPolicy struct {
+	ID    int64 `xorm:"pk autoincr"`
+	OrgID int64 `xorm:"UNIQUE NOT NULL"`
+	// NamePattern is a regular expression the whole name of a new repository must match, any name is allowed if it is empty
+	NamePattern string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	// NamePatternDescription explains the name pattern to the users
+	NamePatternDescription string `xorm:"TEXT"`
+	RequireTopic           bool   `xorm:"NOT NULL DEFAULT false"`
+	// RequiredProperties are the custom properties a new repository must have a value for
+	RequiredProperties []string           `xorm:"TEXT JSON"`
+	CreatedUnix        timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix        timeutil.TimeStamp `xorm:"updated"`
+}
+
+// CompileRepoPolicyNamePattern compiles the name pattern of a repository policy, the pattern must match the whole name
+func CompileRepoPolicyNamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// Check returns an ErrRepoPolicyViolation if a new repository with the name, topics and properties violates the policy
+func (p *RepoPolicy) Check(name string, topics []string, properties map[string]string) error {
+	if p.NamePattern != "" {
+		re, err := CompileRepoPolicyNamePattern(p.NamePattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(name) {
+			return ErrRepoPolicyViolation{Rule: RepoPolicyRuleNamePattern, Value: p.NamePattern, Description: p.NamePatternDescription}
+		}
+	}
+	if p.RequireTopic && len(topics) == 0 {
+		return ErrRepoPolicyViolation{Rule: RepoPolicyRuleTopic}
+	}
+	for _, name := range p.RequiredProperties {
+		if properties[name] == "" {
+			return ErrRepoPolicyViolation{Rule: RepoPolicyRuleProperty, Value: name}
+		}
+	}
+	return nil
+}
+
+// GetRepoPolicy returns the repository policy of an organization
+func GetRepoPolicy(ctx context.Context, orgID int64) (*RepoPolicy, error) {
+	p := &RepoPolicy{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPolicyNotExist{orgID}
+	}
+	return p, nil
+}
+
+// SetRepoPolicy creates or replaces the repository policy of an organization
+func SetRepoPolicy(ctx context.Context, p *RepoPolicy) error {
+	return db.WithTx(func(ctx context.Context) error {
+		existing, err := GetRepoPolicy(ctx, p.OrgID)
+		if err != nil && !IsErrRepoPolicyNotExist(err) {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		p.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(p.ID).AllCols().Update(p)
+		return err
+	}, ctx)
+}
+
+// DeleteRepoPolicy deletes the repository policy of an organization
+func DeleteRepoPolicy(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(&RepoPolicy{})
+	return err
+}
+
+// CheckRepoPolicy checks a new repository of an owner against the repository policy of the owner, if there is one.
+// Required properties which the owner no longer defines are ignored.
+func CheckRepoPolicy(ctx context.Context, ownerID int64, name string, topics []string, properties map[string]string) error {
+	p, err := GetRepoPolicy(ctx, ownerID)
+	if err != nil {
+		if IsErrRepoPolicyNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if len(p.RequiredProperties) > 0 {
+		defs, err := repo_model.GetPropertyDefinitions(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		defined := make(map[string]bool, len(defs))
+		for _, def := range defs {
+			defined[def.Name] = true
+		}
+		required := make([]string, 0, len(p.RequiredProperties))
+		for _, name := range p.RequiredProperties {
+			if defined[name] {
+				required = append(required, name)
+			}
+		}
+		p.RequiredProperties = required
+	}
+	return p.Check(name, topics, properties)
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoPolicyCheck(t *testing.T) {
+	p := &organization.RepoPolicy{
+		NamePattern:            "team-[a-z]+",
+		NamePatternDescription: "names start with team-",
+		RequireTopic:           true,
+		RequiredProperties:     []string{"cost-center"},
+	}
+
+	assert.NoError(t, p.Check("team-infra", []string{"go"}, map[string]string{"cost-center": "42"}))
+
+	err := p.Check("my-team-infra", []string{"go"}, map[string]string{"cost-center": "42"})
+	assert.Equal(t, organization.ErrRepoPolicyViolation{Rule: organization.RepoPolicyRuleNamePattern, Value: "team-[a-z]+", Description: "names start with team-"}, err)
+	assert.Contains(t, err.Error(), "names start with team-")
+
+	err = p.Check("team-infra", nil, map[string]string{"cost-center": "42"})
+	assert.Equal(t, organization.ErrRepoPolicyViolation{Rule: organization.RepoPolicyRuleTopic}, err)
+
+	err = p.Check("team-infra", []string{"go"}, map[string]string{"owner": "ops"})
+	assert.Equal(t, organization.ErrRepoPolicyViolation{Rule: organization.RepoPolicyRuleProperty, Value: "cost-center"}, err)
+}
+
+func TestRepoPolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, organization.CheckRepoPolicy(db.DefaultContext, 3, "anything", nil, nil))
+
+	_, err := organization.GetRepoPolicy(db.DefaultContext, 3)
+	assert.True(t, organization.IsErrRepoPolicyNotExist(err))
+
+	assert.NoError(t, organization.SetRepoPolicy(db.DefaultContext, &organization.RepoPolicy{
+		OrgID:              3,
+		NamePattern:        "svc-.*",
+		RequiredProperties: []string{"cost-center"},
+	}))
+	assert.True(t, organization.IsErrRepoPolicyViolation(organization.CheckRepoPolicy(db.DefaultContext, 3, "anything", nil, nil)))
+	// the property is not defined by the organization
+	assert.NoError(t, organization.CheckRepoPolicy(db.DefaultContext, 3, "svc-api", nil, nil))
+
+	assert.NoError(t, repo_model.CreatePropertyDefinition(db.DefaultContext, &repo_model.PropertyDefinition{OwnerID: 3, Name: "cost-center"}))
+	assert.True(t, organization.IsErrRepoPolicyViolation(organization.CheckRepoPolicy(db.DefaultContext, 3, "svc-api", nil, nil)))
+	assert.NoError(t, organization.CheckRepoPolicy(db.DefaultContext, 3, "svc-api", nil, map[string]string{"cost-center": "42"}))
+
+	assert.NoError(t, organization.SetRepoPolicy(db.DefaultContext, &organization.RepoPolicy{OrgID: 3, RequireTopic: true}))
+	p, err := organization.GetRepoPolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, p.NamePattern)
+	assert.True(t, p.RequireTopic)
+	unittest.AssertCount(t, &organization.RepoPolicy{}, 1)
+
+	assert.NoError(t, organization.DeleteRepoPolicy(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &organization.RepoPolicy{OrgID: 3})
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package organization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(ServiceAccount))
+}
+
+// ServiceAccount links a service account to the organization which owns it
+type ServiceAccount struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	UserID      int64              `xorm:"UNIQUE NOT NULL"`
+	CreatorID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+// ErrServiceAccountNotExist represents a "ServiceAccountNotExist" kind of error.
+type ErrServiceAccountNotExist struct {
+	OrgID int64
+	Name  string
+}
+
+// IsErrServiceAccountNotExist checks if an error is a ErrServiceAccountNotExist.
+func IsErrServiceAccountNotExist(err error) bool {
+	_, ok := err.(ErrServiceAccountNotExist)
+	return ok
+}
+
+func (err ErrServiceAccountNotExist) Error() string {
+	return fmt.Sprintf("service account does not exist [org_id: %d, name: %s]", err.OrgID, err.Name)
+}
+
+// AddServiceAccount records the organization as the owner of the service account
+func AddServiceAccount(ctx context.Context, sa *ServiceAccount) error {
+	return db.Insert(ctx, sa)
+}
+
+// GetServiceAccounts returns the service accounts owned by the organization ordered by name
+func GetServiceAccounts(ctx context.Context, orgID int64, listOptions db.ListOptions) ([]*user_model.User, int64, error) {
+	sess := db.GetEngine(ctx).
+		Join("INNER", "service_account", "service_account.user_id = `user`.id").
+		Where("service_account.org_id = ?", orgID).
+		OrderBy("`user`.lower_name")
+	if listOptions.Page > 0 {
+		sess = db.SetSessionPagination(sess, &listOptions)
+	}
+	users := make([]*user_model.User, 0, 10)
+	count, err := sess.FindAndCount(&users)
+	return users, count, err
+}
+
+// GetServiceAccount returns the service account of the organization with the name
+func GetServiceAccount(ctx context.Context, orgID int64, name string) (*user_model.User, error) {
+	u := new(user_model.User)
+	has, err := db.GetEngine(ctx).
+		Join("INNER", "service_account", "service_account.user_id = `user`.id").
+		Where("service_account.org_id = ? AND `user`.lower_name = ?", orgID, strings.ToLower(name)).
+		Get(u)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrServiceAccountNotExist{OrgID: orgID, Name: name}
+	}
+	return u, nil
+}
+
+// disableServiceAccounts prohibits the login of the service accounts of a deleted organization,
+// they are kept for the site administrators to review and delete.
+func disableServiceAccounts(ctx context.Context, orgID int64) error {
+	if _, err := db.GetEngine(ctx).
+		Where(builder.In("id", builder.Select("user_id").From("service_account").Where(builder.Eq{"org_id": orgID}))).
+		Cols("prohibit_login").
+		Update(&user_model.User{ProhibitLogin: true}); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Delete(&ServiceAccount{OrgID: orgID})
+	return err
+}
@@ -26,11 +26,12 @@ func buildCondition(name, value string) builder.Cond {
 }
 
 type RecipeSearchOptions struct {
-	OwnerID int64
-	Name    string
-	Version string
-	User    string
-	Channel string
+	OwnerID     int64
+	Name        string
+	Version     string
+	User        string
+	Channel     string
+	HidePrivate bool
 }
 
 // SearchRecipes gets all recipes matching the search options
@@ -42,6 +43,10 @@ func SearchRecipes(ctx context.Context, opts *RecipeSearchOptions) ([]string, er
 		"package_version.is_internal": false,
 	}
 
+	if opts.HidePrivate {
+		cond = cond.And(builder.Neq{"package.visibility": packages.VisibilityPrivate})
+	}
+
 	if opts.Name != "" {
 		cond = cond.And(buildCondition("package.lower_name", strings.ToLower(opts.Name)))
 	}
@@ -14,7 +14,9 @@ import (
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/packages/composer"
 	"code.gitea.io/gitea/modules/packages/conan"
+	"code.gitea.io/gitea/modules/packages/conda"
 	"code.gitea.io/gitea/modules/packages/container"
+	"code.gitea.io/gitea/modules/packages/cran"
 	"code.gitea.io/gitea/modules/packages/helm"
 	"code.gitea.io/gitea/modules/packages/maven"
 	"code.gitea.io/gitea/modules/packages/npm"
@@ -22,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/packages/pub"
 	"code.gitea.io/gitea/modules/packages/pypi"
 	"code.gitea.io/gitea/modules/packages/rubygems"
+	"code.gitea.io/gitea/modules/packages/swift"
 	"code.gitea.io/gitea/modules/packages/vagrant"
 
 	"github.com/hashicorp/go-version"
@@ -40,6 +43,17 @@ func (l PackagePropertyList) GetByName(name string) string {
 	return ""
 }
 
+// GetValuesByName gets all property values with the specific name
+func (l PackagePropertyList) GetValuesByName(name string) []string {
+	values := make([]string, 0, len(l))
+	for _, pp := range l {
+		if pp.Name == name {
+			values = append(values, pp.Value)
+		}
+	}
+	return values
+}
+
 // PackageDescriptor describes a package
 type PackageDescriptor struct {
 	Package           *Package
@@ -127,14 +141,39 @@ func GetPackageDescriptor(ctx context.Context, pv *PackageVersion) (*PackageDesc
 		pfds = append(pfds, pfd)
 	}
 
+	metadata, err := DecodeMetadata(p.Type, pv.MetadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageDescriptor{
+		Package:           p,
+		Owner:             o,
+		Repository:        repository,
+		Version:           pv,
+		SemVer:            semVer,
+		Creator:           creator,
+		PackageProperties: PackagePropertyList(pps),
+		VersionProperties: PackagePropertyList(pvps),
+		Metadata:          metadata,
+		Files:             pfds,
+	}, nil
+}
+
+// DecodeMetadata decodes the metadata of a package version into the metadata type of the package type
+func DecodeMetadata(packageType Type, metadataJSON string) (interface{}, error) {
 	var metadata interface{}
-	switch p.Type {
+	switch packageType {
 	case TypeComposer:
 		metadata = &composer.Metadata{}
 	case TypeConan:
 		metadata = &conan.Metadata{}
+	case TypeConda:
+		metadata = &conda.VersionMetadata{}
 	case TypeContainer:
 		metadata = &container.Metadata{}
+	case TypeCran:
+		metadata = &cran.Metadata{}
 	case TypeGeneric:
 		// generic packages have no metadata
 	case TypeHelm:
@@ -151,29 +190,19 @@ func GetPackageDescriptor(ctx context.Context, pv *PackageVersion) (*PackageDesc
 		metadata = &pypi.Metadata{}
 	case TypeRubyGems:
 		metadata = &rubygems.Metadata{}
+	case TypeSwift:
+		metadata = &swift.Metadata{}
 	case TypeVagrant:
 		metadata = &vagrant.Metadata{}
 	default:
-		panic(fmt.Sprintf("unknown package type: %s", string(p.Type)))
+		panic(fmt.Sprintf("unknown package type: %s", string(packageType)))
 	}
 	if metadata != nil {
-		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+		if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
 			return nil, err
 		}
 	}
-
-	return &PackageDescriptor{
-		Package:           p,
-		Owner:             o,
-		Repository:        repository,
-		Version:           pv,
-		SemVer:            semVer,
-		Creator:           creator,
-		PackageProperties: PackagePropertyList(pps),
-		VersionProperties: PackagePropertyList(pvps),
-		Metadata:          metadata,
-		Files:             pfds,
-	}, nil
+	return metadata, nil
 }
 
 // GetPackageFileDescriptor gets a package file descriptor for a package file
@@ -33,7 +33,9 @@ type Type string
 const (
 	TypeComposer  Type = "composer"
 	TypeConan     Type = "conan"
+	TypeConda     Type = "conda"
 	TypeContainer Type = "container"
+	TypeCran      Type = "cran"
 	TypeGeneric   Type = "generic"
 	TypeHelm      Type = "helm"
 	TypeMaven     Type = "maven"
@@ -42,9 +44,29 @@ const (
 	TypePub       Type = "pub"
 	TypePyPI      Type = "pypi"
 	TypeRubyGems  Type = "rubygems"
+	TypeSwift     Type = "swift"
 	TypeVagrant   Type = "vagrant"
 )
 
+// TypeList contains all supported package types
+var TypeList = []Type{
+	TypeComposer,
+	TypeConan,
+	TypeConda,
+	TypeContainer,
+	TypeCran,
+	TypeGeneric,
+	TypeHelm,
+	TypeMaven,
+	TypeNpm,
+	TypeNuGet,
+	TypePub,
+	TypePyPI,
+	TypeRubyGems,
+	TypeSwift,
+	TypeVagrant,
+}
+
 // Name gets the name of the package type
 func (pt Type) Name() string {
 	switch pt {
@@ -52,8 +74,12 @@ func (pt Type) Name() string {
 		return "Composer"
 	case TypeConan:
 		return "Conan"
+	case TypeConda:
+		return "Conda"
 	case TypeContainer:
 		return "Container"
+	case TypeCran:
+		return "CRAN"
 	case TypeGeneric:
 		return "Generic"
 	case TypeHelm:
@@ -70,6 +96,8 @@ func (pt Type) Name() string {
 		return "PyPI"
 	case TypeRubyGems:
 		return "RubyGems"
+	case TypeSwift:
+		return "Swift"
 	case TypeVagrant:
 		return "Vagrant"
 	}
@@ -83,8 +111,12 @@ func (pt Type) SVGName() string {
 		return "gitea-composer"
 	case TypeConan:
 		return "gitea-conan"
+	case TypeConda:
+		return "gitea-conda"
 	case TypeContainer:
 		return "octicon-container"
+	case TypeCran:
+		return "gitea-cran"
 	case TypeGeneric:
 		return "octicon-package"
 	case TypeHelm:
@@ -101,6 +133,8 @@ func (pt Type) SVGName() string {
 		return "gitea-python"
 	case TypeRubyGems:
 		return "gitea-rubygems"
+	case TypeSwift:
+		return "gitea-swift"
 	case TypeVagrant:
 		return "gitea-vagrant"
 	}
@@ -109,13 +143,14 @@ func (pt Type) SVGName() string {
 
 // Package represents a package
 type Package struct {
-	ID               int64  `xorm:"pk autoincr"`
-	OwnerID          int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	RepoID           int64  `xorm:"INDEX"`
-	Type             Type   `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	Name             string `xorm:"NOT NULL"`
-	LowerName        string `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	SemverCompatible bool   `xorm:"NOT NULL DEFAULT false"`
+	ID               int64      `xorm:"pk autoincr"`
+	OwnerID          int64      `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RepoID           int64      `xorm:"INDEX"`
+	Type             Type       `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name             string     `xorm:"NOT NULL"`
+	LowerName        string     `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	SemverCompatible bool       `xorm:"NOT NULL DEFAULT false"`
+	Visibility       Visibility `xorm:"NOT NULL DEFAULT 0"`
 }
 
 // TryInsertPackage inserts a package. If a package exists already, ErrDuplicatePackage is returned
@@ -153,6 +188,12 @@ func SetRepositoryLink(ctx context.Context, packageID, repoID int64) error {
 	return err
 }
 
+// SetPackageVisibility sets the visibility of a package
+func SetPackageVisibility(ctx context.Context, packageID int64, visibility Visibility) error {
+	_, err := db.GetEngine(ctx).ID(packageID).Cols("visibility").Update(&Package{Visibility: visibility})
+	return err
+}
+
 // UnlinkRepositoryFromAllPackages unlinks every package from the repository
 func UnlinkRepositoryFromAllPackages(ctx context.Context, repoID int64) error {
 	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Cols("repo_id").Update(&Package{})
@@ -233,3 +274,23 @@ func HasOwnerPackages(ctx context.Context, ownerID int64) (bool, error) {
 func HasRepositoryPackages(ctx context.Context, repositoryID int64) (bool, error) {
 	return db.GetEngine(ctx).Where("repo_id = ?", repositoryID).Exist(&Package{})
 }
+
+// TypeStatistic contains the number of packages, versions and downloads of a package type
+type TypeStatistic struct {
+	Type          Type
+	PackageCount  int64
+	VersionCount  int64
+	DownloadCount int64
+}
+
+// GetTypeStatistics returns the statistics of all package types which have packages.
+// Internal versions are not counted.
+func GetTypeStatistics(ctx context.Context) ([]*TypeStatistic, error) {
+	stats := make([]*TypeStatistic, 0, len(TypeList))
+	return stats, db.GetEngine(ctx).
+		Table("package").
+		Select("package.type AS type, COUNT(DISTINCT package.id) AS package_count, COUNT(package_version.id) AS version_count, COALESCE(SUM(package_version.download_count), 0) AS download_count").
+		Join("LEFT", "package_version", "package_version.package_id = package.id AND package_version.is_internal = ?", false).
+		GroupBy("package.type").
+		Find(&stats)
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+)
+
+func init() {
+	db.RegisterModel(new(PackageTeam))
+}
+
+// ErrPackageTeamNotExist indicates a package team access not exist error
+var ErrPackageTeamNotExist = errors.New("Package team access does not exist")
+
+// Visibility defines who can read a package
+type Visibility int
+
+const (
+	// VisibilityInherit uses the visibility of the package owner
+	VisibilityInherit Visibility = iota
+	// VisibilityPublic lets everyone read the package, even if the owner is not visible
+	VisibilityPublic
+	// VisibilityPrivate restricts the package to users with write access to the owner's packages and to granted teams
+	VisibilityPrivate
+)
+
+var visibilityNames = map[Visibility]string{
+	VisibilityInherit: "inherit",
+	VisibilityPublic:  "public",
+	VisibilityPrivate: "private",
+}
+
+// String returns the name of the visibility
+func (v Visibility) String() string {
+	return visibilityNames[v]
+}
+
+// VisibilityFromString returns the visibility with the given name
+func VisibilityFromString(name string) (Visibility, bool) {
+	for v, n := range visibilityNames {
+		if n == name {
+			return v, true
+		}
+	}
+	return VisibilityInherit, false
+}
+
+// PackageTeam grants a team access to a single package
+type PackageTeam struct {
+	ID         int64           `xorm:"pk autoincr"`
+	PackageID  int64           `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	TeamID     int64           `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	AccessMode perm.AccessMode `xorm:"NOT NULL"`
+}
+
+// SetPackageTeam grants a team access to a package or updates the existing grant
+func SetPackageTeam(ctx context.Context, packageID, teamID int64, mode perm.AccessMode) error {
+	e := db.GetEngine(ctx)
+
+	pt := &PackageTeam{}
+	has, err := e.Where("package_id = ? AND team_id = ?", packageID, teamID).Get(pt)
+	if err != nil {
+		return err
+	}
+	if has {
+		pt.AccessMode = mode
+		_, err = e.ID(pt.ID).Cols("access_mode").Update(pt)
+		return err
+	}
+
+	_, err = e.Insert(&PackageTeam{
+		PackageID:  packageID,
+		TeamID:     teamID,
+		AccessMode: mode,
+	})
+	return err
+}
+
+// RemovePackageTeam revokes the access of a team to a package
+func RemovePackageTeam(ctx context.Context, packageID, teamID int64) error {
+	n, err := db.GetEngine(ctx).Where("package_id = ? AND team_id = ?", packageID, teamID).Delete(&PackageTeam{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageTeamNotExist
+	}
+	return nil
+}
+
+// GetPackageTeams gets all team grants of a package
+func GetPackageTeams(ctx context.Context, packageID int64) ([]*PackageTeam, error) {
+	pts := make([]*PackageTeam, 0, 5)
+	return pts, db.GetEngine(ctx).Where("package_id = ?", packageID).OrderBy("team_id").Find(&pts)
+}
+
+// DeletePackageTeamsByPackageID deletes all team grants of a package
+func DeletePackageTeamsByPackageID(ctx context.Context, packageID int64) error {
+	_, err := db.GetEngine(ctx).Where("package_id = ?", packageID).Delete(&PackageTeam{})
+	return err
+}
+
+// DeletePackageTeamsByTeamID deletes all package grants of a team
+func DeletePackageTeamsByTeamID(ctx context.Context, teamID int64) error {
+	_, err := db.GetEngine(ctx).Where("team_id = ?", teamID).Delete(&PackageTeam{})
+	return err
+}
+
+// GetUserPackageAccessMode returns the highest access mode the teams of the user are granted for the package
+func GetUserPackageAccessMode(ctx context.Context, packageID, userID int64) (perm.AccessMode, error) {
+	var mode perm.AccessMode
+	_, err := db.GetEngine(ctx).
+		Select("max(package_team.access_mode)").
+		Table("package_team").
+		Join("INNER", "team_user", "team_user.team_id = package_team.team_id").
+		Where("team_user.uid = ?", userID).
+		And("package_team.package_id = ?", packageID).
+		Get(&mode)
+	return mode, err
+}
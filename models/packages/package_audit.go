@@ -0,0 +1,193 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageAuditLog))
+}
+
+// AuditScope separates the operations recorded in the audit log
+type AuditScope string
+
+// List of audit log scopes
+const (
+	// AuditScopePackage records operations on the packages of an owner
+	AuditScopePackage AuditScope = "package"
+	// AuditScopeAdmin records operations of administrators on behalf of a user, the user is the owner of the entry
+	AuditScopeAdmin AuditScope = "admin"
+)
+
+// IsValid checks if the audit scope is known
+func (s AuditScope) IsValid() bool {
+	return s == AuditScopePackage || s == AuditScopeAdmin
+}
+
+// AuditAction is the kind of an audited operation
+type AuditAction string
+
+// List of audited package operations
+const (
+	AuditActionPublish    AuditAction = "publish"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionDownload   AuditAction = "download"
+	AuditActionPermission AuditAction = "permission"
+)
+
+// List of audited administrative operations
+const (
+	AuditActionProvisionSSHKey AuditAction = "provision_ssh_key"
+	AuditActionProvisionGPGKey AuditAction = "provision_gpg_key"
+	AuditActionProvisionToken  AuditAction = "provision_token"
+	// AuditActionIssueTwoFactorRecovery records a recovery code for a user locked out of two-factor authentication
+	AuditActionIssueTwoFactorRecovery AuditAction = "issue_2fa_recovery"
+	// AuditActionUseTwoFactorRecovery records the sign in of a user with a recovery code, the user is the actor
+	AuditActionUseTwoFactorRecovery AuditAction = "use_2fa_recovery"
+)
+
+// Scope returns the scope of the audit action
+func (a AuditAction) Scope() AuditScope {
+	switch a {
+	case AuditActionProvisionSSHKey, AuditActionProvisionGPGKey, AuditActionProvisionToken,
+		AuditActionIssueTwoFactorRecovery, AuditActionUseTwoFactorRecovery:
+		return AuditScopeAdmin
+	}
+	return AuditScopePackage
+}
+
+// IsValid checks if the audit action is known
+func (a AuditAction) IsValid() bool {
+	switch a {
+	case AuditActionPublish, AuditActionDelete, AuditActionDownload, AuditActionPermission,
+		AuditActionProvisionSSHKey, AuditActionProvisionGPGKey, AuditActionProvisionToken,
+		AuditActionIssueTwoFactorRecovery, AuditActionUseTwoFactorRecovery:
+		return true
+	}
+	return false
+}
+
+// PackageAuditLog records an operation on the packages of an owner or, in the admin scope, an operation of an administrator on behalf of a user.
+// The names are stored instead of references because the entries must outlive the packages and the actors.
+type PackageAuditLog struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Scope       AuditScope         `xorm:"INDEX NOT NULL DEFAULT 'package'"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL"`
+	Action      AuditAction        `xorm:"INDEX NOT NULL"`
+	ActorID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // 0 for anonymous requests
+	ActorName   string             `xorm:"NOT NULL DEFAULT ''"`
+	PackageType Type               `xorm:"NOT NULL DEFAULT ''"` // empty for changes of owner-wide settings
+	PackageName string             `xorm:"NOT NULL DEFAULT ''"`
+	Version     string             `xorm:"NOT NULL DEFAULT ''"`
+	Filename    string             `xorm:"NOT NULL DEFAULT ''"`
+	Detail      string             `xorm:"TEXT"`
+	IP          string             `xorm:"NOT NULL DEFAULT ''"`
+	UserAgent   string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+
+	Owner *user_model.User `xorm:"-"`
+}
+
+// AuditLogList is a list of audit log entries
+type AuditLogList []*PackageAuditLog
+
+// LoadOwners loads the owners of the entries, deleted owners are replaced by the ghost user
+func (logs AuditLogList) LoadOwners(ctx context.Context) error {
+	ownerIDs := make(map[int64]struct{}, len(logs))
+	for _, l := range logs {
+		ownerIDs[l.OwnerID] = struct{}{}
+	}
+	if len(ownerIDs) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(ownerIDs))
+	for id := range ownerIDs {
+		ids = append(ids, id)
+	}
+
+	owners := make(map[int64]*user_model.User, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Find(&owners); err != nil {
+		return err
+	}
+	for _, l := range logs {
+		l.Owner = owners[l.OwnerID]
+		if l.Owner == nil {
+			l.Owner = user_model.NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// InsertAuditLog stores an audit log entry, the scope is derived from the action
+func InsertAuditLog(ctx context.Context, l *PackageAuditLog) error {
+	l.Scope = l.Action.Scope()
+	_, err := db.GetEngine(ctx).Insert(l)
+	return err
+}
+
+// AuditLogSearchOptions filters the audit log
+type AuditLogSearchOptions struct {
+	Scope       AuditScope
+	OwnerID     int64
+	ActorID     int64
+	Action      AuditAction
+	PackageType Type
+	PackageName string
+	Since       timeutil.TimeStamp // inclusive, 0 for no limit
+	Before      timeutil.TimeStamp // exclusive, 0 for no limit
+	db.Paginator
+}
+
+func (opts *AuditLogSearchOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Scope != "" {
+		cond = cond.And(builder.Eq{"scope": opts.Scope})
+	}
+	if opts.OwnerID != 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.ActorID != 0 {
+		cond = cond.And(builder.Eq{"actor_id": opts.ActorID})
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	if opts.PackageType != "" {
+		cond = cond.And(builder.Eq{"package_type": opts.PackageType})
+	}
+	if opts.PackageName != "" {
+		cond = cond.And(builder.Eq{"package_name": opts.PackageName})
+	}
+	if opts.Since != 0 {
+		cond = cond.And(builder.Gte{"created_unix": opts.Since})
+	}
+	if opts.Before != 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.Before})
+	}
+	return cond
+}
+
+// SearchAuditLogs gets the audit log entries matching the search options, newest first
+func SearchAuditLogs(ctx context.Context, opts *AuditLogSearchOptions) (AuditLogList, int64, error) {
+	sess := db.GetEngine(ctx).
+		Where(opts.toConds()).
+		OrderBy("created_unix DESC, id DESC")
+
+	if opts.Paginator != nil {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	logs := make(AuditLogList, 0, 10)
+	count, err := sess.FindAndCount(&logs)
+	return logs, count, err
+}
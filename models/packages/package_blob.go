@@ -7,10 +7,13 @@ package packages
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // ErrPackageBlobNotExist indicates a package blob not exist error
@@ -62,6 +65,30 @@ func GetBlobByID(ctx context.Context, blobID int64) (*PackageBlob, error) {
 	return pb, nil
 }
 
+// GetBlobBySHA256ForOwner gets a blob with the SHA256 hash which is referenced by a package file of the owner.
+// Blobs of other owners are not considered so the existence of their content is not disclosed.
+func GetBlobBySHA256ForOwner(ctx context.Context, ownerID int64, hashSHA256 string) (*PackageBlob, error) {
+	pb := &PackageBlob{}
+
+	has, err := db.GetEngine(ctx).
+		Table("package_blob").
+		Join("INNER", "package_file", "package_file.blob_id = package_blob.id").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Where(builder.Eq{
+			"package.owner_id":         ownerID,
+			"package_blob.hash_sha256": strings.ToLower(hashSHA256),
+		}).
+		Get(pb)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageBlobNotExist
+	}
+	return pb, nil
+}
+
 // FindExpiredUnreferencedBlobs gets all blobs without associated files older than the specific duration
 func FindExpiredUnreferencedBlobs(ctx context.Context, olderThan time.Duration) ([]*PackageBlob, error) {
 	pbs := make([]*PackageBlob, 0, 10)
@@ -83,3 +110,8 @@ func GetTotalBlobSize() (int64, error) {
 	return db.GetEngine(db.DefaultContext).
 		SumInt(&PackageBlob{}, "size")
 }
+
+// CountBlobs returns the number of stored blobs
+func CountBlobs(ctx context.Context) (int64, error) {
+	return db.GetEngine(ctx).Count(&PackageBlob{})
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(PackageContainerDomain))
+}
+
+var (
+	// ErrContainerDomainNotExist indicates a container domain not exist error
+	ErrContainerDomainNotExist = errors.New("Container domain does not exist")
+	// ErrContainerDomainInUse indicates the domain is mapped to the container registry of another owner
+	ErrContainerDomainInUse = errors.New("Container domain is already in use")
+)
+
+// PackageContainerDomain maps a hostname to the container registry of an owner.
+// The images of the owner are available on the hostname without the owner in their path.
+type PackageContainerDomain struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE NOT NULL"`
+	Domain      string             `xorm:"UNIQUE NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func containerDomainCacheKey(domain string) string {
+	return "ContainerDomain:" + strings.ToLower(domain)
+}
+
+// SetContainerDomain maps the domain to the container registry of the owner, replacing the previous domain of the owner
+func SetContainerDomain(ctx context.Context, ownerID int64, domain string) (*PackageContainerDomain, error) {
+	domain = strings.ToLower(domain)
+
+	ctx, committer, err := db.TxContext()
+	if err != nil {
+		return nil, err
+	}
+	defer committer.Close()
+
+	existing, err := GetContainerDomainByDomain(ctx, domain)
+	if err != nil && err != ErrContainerDomainNotExist {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.OwnerID != ownerID {
+			return nil, ErrContainerDomainInUse
+		}
+		return existing, nil
+	}
+
+	if err := DeleteContainerDomainByOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+	d := &PackageContainerDomain{
+		OwnerID: ownerID,
+		Domain:  domain,
+	}
+	if err := db.Insert(ctx, d); err != nil {
+		return nil, err
+	}
+	if err := committer.Commit(); err != nil {
+		return nil, err
+	}
+	cache.Remove(containerDomainCacheKey(domain))
+	return d, nil
+}
+
+// GetContainerDomainByOwner gets the container domain of an owner
+func GetContainerDomainByOwner(ctx context.Context, ownerID int64) (*PackageContainerDomain, error) {
+	d := &PackageContainerDomain{}
+	has, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Get(d)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrContainerDomainNotExist
+	}
+	return d, nil
+}
+
+// GetContainerDomainByDomain gets the container domain mapping of a hostname
+func GetContainerDomainByDomain(ctx context.Context, domain string) (*PackageContainerDomain, error) {
+	d := &PackageContainerDomain{}
+	has, err := db.GetEngine(ctx).Where("domain = ?", strings.ToLower(domain)).Get(d)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrContainerDomainNotExist
+	}
+	return d, nil
+}
+
+// GetContainerDomainOwnerID gets the id of the owner a hostname is mapped to.
+// The result is cached, 0 is returned if the hostname is not mapped to an owner.
+func GetContainerDomainOwnerID(ctx context.Context, domain string) (int64, error) {
+	return cache.GetInt64(containerDomainCacheKey(domain), func() (int64, error) {
+		d, err := GetContainerDomainByDomain(ctx, domain)
+		if err != nil {
+			if err == ErrContainerDomainNotExist {
+				return 0, nil
+			}
+			return 0, err
+		}
+		return d.OwnerID, nil
+	})
+}
+
+// DeleteContainerDomainByOwner removes the container domain of an owner
+func DeleteContainerDomainByOwner(ctx context.Context, ownerID int64) error {
+	d, err := GetContainerDomainByOwner(ctx, ownerID)
+	if err != nil {
+		if err == ErrContainerDomainNotExist {
+			return nil
+		}
+		return err
+	}
+	if _, err := db.GetEngine(ctx).ID(d.ID).Delete(&PackageContainerDomain{}); err != nil {
+		return err
+	}
+	cache.Remove(containerDomainCacheKey(d.Domain))
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/packages/composer"
+	"code.gitea.io/gitea/modules/packages/cran"
+	"code.gitea.io/gitea/modules/packages/helm"
+	"code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/packages/nuget"
+	"code.gitea.io/gitea/modules/packages/pub"
+	"code.gitea.io/gitea/modules/packages/rubygems"
+	"code.gitea.io/gitea/modules/structs"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageDependency))
+}
+
+// DependencyKind describes when a dependency is needed
+type DependencyKind string
+
+// List of dependency kinds
+const (
+	DependencyKindRuntime     DependencyKind = "runtime"
+	DependencyKindDevelopment DependencyKind = "development"
+	DependencyKindPeer        DependencyKind = "peer"
+	DependencyKindOptional    DependencyKind = "optional"
+)
+
+// PackageDependency is a dependency declared by a package version.
+// The dependency is a package of the same type as the declaring package.
+type PackageDependency struct {
+	ID           int64          `xorm:"pk autoincr"`
+	VersionID    int64          `xorm:"INDEX NOT NULL"`
+	Kind         DependencyKind `xorm:"NOT NULL"`
+	Name         string         `xorm:"NOT NULL"`
+	LowerName    string         `xorm:"INDEX NOT NULL"`
+	VersionRange string
+}
+
+// InsertDependencies stores the dependencies of a package version
+func InsertDependencies(ctx context.Context, versionID int64, deps []*PackageDependency) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	for _, dep := range deps {
+		dep.VersionID = versionID
+		dep.LowerName = strings.ToLower(dep.Name)
+	}
+	_, err := db.GetEngine(ctx).Insert(&deps)
+	return err
+}
+
+// GetDependenciesByVersionID gets the dependencies of a package version
+func GetDependenciesByVersionID(ctx context.Context, versionID int64) ([]*PackageDependency, error) {
+	deps := make([]*PackageDependency, 0, 10)
+	return deps, db.GetEngine(ctx).Where("version_id = ?", versionID).OrderBy("id").Find(&deps)
+}
+
+// HasDependencies checks if dependencies are stored for the package version
+func HasDependencies(ctx context.Context, versionID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("version_id = ?", versionID).Exist(&PackageDependency{})
+}
+
+// DeleteDependenciesByVersionID deletes the dependencies of a package version
+func DeleteDependenciesByVersionID(ctx context.Context, versionID int64) error {
+	_, err := db.GetEngine(ctx).Where("version_id = ?", versionID).Delete(&PackageDependency{})
+	return err
+}
+
+// visiblePackagesCond returns the condition for packages the doer can read without being granted access explicitly.
+// Private packages are only visible to their owner. Like repositories, the packages of limited owners are only visible
+// to restricted users if they are members of the owning organization.
+func visiblePackagesCond(doer *user_model.User) builder.Cond {
+	if doer != nil && doer.IsAdmin {
+		return builder.NewCond()
+	}
+
+	visibleOwners := []structs.VisibleType{structs.VisibleTypePublic}
+	if doer != nil && !doer.IsRestricted {
+		visibleOwners = append(visibleOwners, structs.VisibleTypeLimited)
+	}
+	var ownerCond builder.Cond = builder.In("package.owner_id", builder.Select("id").From("`user`").Where(builder.In("visibility", visibleOwners)))
+	if doer != nil {
+		ownerCond = ownerCond.Or(
+			builder.Eq{"package.owner_id": doer.ID},
+			builder.In("package.owner_id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": doer.ID})),
+		)
+	}
+
+	cond := builder.Eq{"package.visibility": VisibilityPublic}.
+		Or(builder.Eq{"package.visibility": VisibilityInherit}.And(ownerCond))
+	if doer != nil {
+		cond = cond.Or(builder.Eq{"package.visibility": VisibilityPrivate, "package.owner_id": doer.ID})
+	}
+	return cond
+}
+
+// PackageDependent is a package version which depends on a package
+type PackageDependent struct {
+	Version    *PackageVersion
+	Dependency *PackageDependency
+}
+
+// SearchDependents gets the package versions of all owners visible to the doer which depend on the package
+func SearchDependents(ctx context.Context, doer *user_model.User, p *Package, paginator db.Paginator) ([]*PackageDependent, int64, error) {
+	cond := builder.Eq{
+		"package_dependency.lower_name": p.LowerName,
+		"package.type":                  p.Type,
+		"package_version.is_internal":   false,
+	}.
+		And(builder.Neq{"package.id": p.ID}).
+		And(visiblePackagesCond(doer))
+
+	sess := db.GetEngine(ctx).
+		Table("package_dependency").
+		Join("INNER", "package_version", "package_version.id = package_dependency.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Where(cond).
+		OrderBy("package.lower_name, package_version.created_unix DESC, package_dependency.id")
+	if paginator != nil {
+		sess = db.SetSessionPagination(sess, paginator)
+	}
+
+	deps := make([]*PackageDependency, 0, 10)
+	count, err := sess.Select("package_dependency.*").FindAndCount(&deps)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(deps) == 0 {
+		return []*PackageDependent{}, count, nil
+	}
+
+	versionIDs := make([]int64, 0, len(deps))
+	for _, dep := range deps {
+		versionIDs = append(versionIDs, dep.VersionID)
+	}
+	versions := make(map[int64]*PackageVersion, len(versionIDs))
+	if err := db.GetEngine(ctx).In("id", versionIDs).Find(&versions); err != nil {
+		return nil, 0, err
+	}
+
+	dependents := make([]*PackageDependent, 0, len(deps))
+	for _, dep := range deps {
+		if pv, ok := versions[dep.VersionID]; ok {
+			dependents = append(dependents, &PackageDependent{
+				Version:    pv,
+				Dependency: dep,
+			})
+		}
+	}
+	return dependents, count, nil
+}
+
+// DependenciesFromMetadata extracts the declared dependencies from the metadata of a package version.
+// Package types without dependency information return no dependencies.
+func DependenciesFromMetadata(metadata interface{}) []*PackageDependency {
+	var deps []*PackageDependency
+
+	add := func(kind DependencyKind, name, versionRange string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		for _, dep := range deps {
+			if dep.Kind == kind && strings.EqualFold(dep.Name, name) {
+				return
+			}
+		}
+		deps = append(deps, &PackageDependency{
+			Kind:         kind,
+			Name:         name,
+			VersionRange: strings.TrimSpace(versionRange),
+		})
+	}
+	addMap := func(kind DependencyKind, m map[string]string) {
+		for _, name := range sortedKeys(m) {
+			add(kind, name, m[name])
+		}
+	}
+
+	switch m := metadata.(type) {
+	case *composer.Metadata:
+		// platform requirements like php or ext-json are no packages
+		for _, reqs := range []struct {
+			kind DependencyKind
+			m    map[string]string
+		}{{DependencyKindRuntime, m.Require}, {DependencyKindDevelopment, m.RequireDev}} {
+			for _, name := range sortedKeys(reqs.m) {
+				if strings.Contains(name, "/") {
+					add(reqs.kind, name, reqs.m[name])
+				}
+			}
+		}
+	case *cran.Metadata:
+		for _, kind := range []struct {
+			kind DependencyKind
+			deps []string
+		}{{DependencyKindRuntime, m.Depends}, {DependencyKindRuntime, m.Imports}, {DependencyKindRuntime, m.LinkingTo}, {DependencyKindOptional, m.Suggests}} {
+			for _, d := range kind.deps {
+				// the R version requirement is listed like a dependency
+				if name, versionRange := splitCranDependency(d); name != "R" {
+					add(kind.kind, name, versionRange)
+				}
+			}
+		}
+	case *helm.Metadata:
+		for _, d := range m.Dependencies {
+			add(DependencyKindRuntime, d.Name, d.Version)
+		}
+	case *maven.Metadata:
+		for _, d := range m.Dependencies {
+			if d.GroupID != "" && d.ArtifactID != "" {
+				add(DependencyKindRuntime, d.GroupID+"-"+d.ArtifactID, d.Version)
+			}
+		}
+	case *npm.Metadata:
+		addMap(DependencyKindRuntime, m.Dependencies)
+		addMap(DependencyKindDevelopment, m.DevelopmentDependencies)
+		addMap(DependencyKindPeer, m.PeerDependencies)
+		addMap(DependencyKindOptional, m.OptionalDependencies)
+	case *nuget.Metadata:
+		frameworks := make([]string, 0, len(m.Dependencies))
+		for framework := range m.Dependencies {
+			frameworks = append(frameworks, framework)
+		}
+		sort.Strings(frameworks)
+		for _, framework := range frameworks {
+			for _, d := range m.Dependencies[framework] {
+				add(DependencyKindRuntime, d.ID, d.Version)
+			}
+		}
+	case *pub.Metadata:
+		if pubspec, ok := m.Pubspec.(map[string]interface{}); ok {
+			addMap(DependencyKindRuntime, pubDependencies(pubspec["dependencies"]))
+			addMap(DependencyKindDevelopment, pubDependencies(pubspec["dev_dependencies"]))
+		}
+	case *rubygems.Metadata:
+		for _, kind := range []struct {
+			kind DependencyKind
+			deps []rubygems.Dependency
+		}{{DependencyKindRuntime, m.RuntimeDependencies}, {DependencyKindDevelopment, m.DevelopmentDependencies}} {
+			for _, d := range kind.deps {
+				requirements := make([]string, 0, len(d.Version))
+				for _, r := range d.Version {
+					requirements = append(requirements, r.Restriction+" "+r.Version)
+				}
+				add(kind.kind, d.Name, strings.Join(requirements, ", "))
+			}
+		}
+	}
+	return deps
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitCranDependency splits "name (>= 1.0)" into the name and the version range
+func splitCranDependency(s string) (string, string) {
+	name, versionRange, found := strings.Cut(s, "(")
+	if !found {
+		return strings.TrimSpace(s), ""
+	}
+	return strings.TrimSpace(name), strings.TrimSuffix(strings.TrimSpace(versionRange), ")")
+}
+
+// pubDependencies converts the dependencies section of a pubspec into name => version constraint.
+// Dependencies can be a version constraint or a map describing the source (hosted, git, path or sdk).
+func pubDependencies(section interface{}) map[string]string {
+	m, ok := section.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	deps := make(map[string]string, len(m))
+	for name, value := range m {
+		switch v := value.(type) {
+		case string:
+			deps[name] = v
+		case map[string]interface{}:
+			if _, isSDK := v["sdk"]; isSDK {
+				continue
+			}
+			version, _ := v["version"].(string)
+			deps[name] = version
+		default:
+			deps[name] = ""
+		}
+	}
+	return deps
+}
+
+// UpdateDependencies replaces the stored dependencies of the package version with the ones declared in its metadata
+func UpdateDependencies(ctx context.Context, packageType Type, pv *PackageVersion) error {
+	metadata, err := DecodeMetadata(packageType, pv.MetadataJSON)
+	if err != nil {
+		return err
+	}
+	if err := DeleteDependenciesByVersionID(ctx, pv.ID); err != nil {
+		return err
+	}
+	return InsertDependencies(ctx, pv.ID, DependenciesFromMetadata(metadata))
+}
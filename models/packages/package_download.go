@@ -0,0 +1,169 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageDownload))
+}
+
+// PackageDownload counts the downloads of a package file per day and client class
+type PackageDownload struct {
+	ID        int64              `xorm:"pk autoincr"`
+	VersionID int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	FileID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Day       timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"` // start of the day (UTC)
+	Client    string             `xorm:"UNIQUE(s) NOT NULL"`
+	Count     int64              `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// DayStart returns the start of the day (UTC) the time belongs to
+func DayStart(t time.Time) timeutil.TimeStamp {
+	t = t.UTC()
+	return timeutil.TimeStamp(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix())
+}
+
+// RecordDownload increments the download counter of the file for the day and client class
+func RecordDownload(ctx context.Context, versionID, fileID int64, client string, t time.Time) error {
+	e := db.GetEngine(ctx)
+
+	key := &PackageDownload{
+		VersionID: versionID,
+		FileID:    fileID,
+		Day:       DayStart(t),
+		Client:    client,
+	}
+
+	increment := func() (int64, error) {
+		return e.Where(builder.Eq{
+			"version_id": key.VersionID,
+			"file_id":    key.FileID,
+			"day":        key.Day,
+			"client":     key.Client,
+		}).Incr("count").Update(&PackageDownload{})
+	}
+
+	n, err := increment()
+	if err != nil || n != 0 {
+		return err
+	}
+
+	key.Count = 1
+	if _, err := e.Insert(key); err != nil {
+		// a concurrent download may have inserted the row in the meantime
+		_, err = increment()
+		return err
+	}
+	return nil
+}
+
+// DeleteDownloadsByVersionID deletes the download statistics of a version
+func DeleteDownloadsByVersionID(ctx context.Context, versionID int64) error {
+	_, err := db.GetEngine(ctx).Where("version_id = ?", versionID).Delete(&PackageDownload{})
+	return err
+}
+
+// DownloadStatsOptions selects the downloads to aggregate
+type DownloadStatsOptions struct {
+	PackageID int64
+	VersionID int64
+	Since     timeutil.TimeStamp // inclusive, 0 for no limit
+	Until     timeutil.TimeStamp // exclusive, 0 for no limit
+}
+
+func (opts *DownloadStatsOptions) toConds() builder.Cond {
+	var cond builder.Cond = builder.Eq{"version_id": opts.VersionID}
+	if opts.VersionID == 0 {
+		cond = builder.In("version_id", builder.Select("id").From("package_version").Where(builder.Eq{"package_id": opts.PackageID}))
+	}
+	if opts.Since != 0 {
+		cond = cond.And(builder.Gte{"day": opts.Since})
+	}
+	if opts.Until != 0 {
+		cond = cond.And(builder.Lt{"day": opts.Until})
+	}
+	return cond
+}
+
+// DownloadsPerDay is the number of downloads of a day
+type DownloadsPerDay struct {
+	Day   timeutil.TimeStamp
+	Count int64
+}
+
+// DownloadsPerFile is the number of downloads of a file
+type DownloadsPerFile struct {
+	FileID int64
+	Count  int64
+}
+
+// DownloadsPerClient is the number of downloads by a client class
+type DownloadsPerClient struct {
+	Client string
+	Count  int64
+}
+
+// DownloadsPerVersion is the number of downloads of a version
+type DownloadsPerVersion struct {
+	VersionID int64
+	Count     int64
+}
+
+// GetDownloadsPerDay aggregates the downloads per day in ascending order
+func GetDownloadsPerDay(ctx context.Context, opts *DownloadStatsOptions) ([]*DownloadsPerDay, error) {
+	stats := make([]*DownloadsPerDay, 0, 30)
+	return stats, db.GetEngine(ctx).
+		Table("package_download").
+		Select("day, SUM(count) AS count").
+		Where(opts.toConds()).
+		GroupBy("day").
+		OrderBy("day ASC").
+		Find(&stats)
+}
+
+// GetDownloadsPerFile aggregates the downloads per file, most downloaded first
+func GetDownloadsPerFile(ctx context.Context, opts *DownloadStatsOptions) ([]*DownloadsPerFile, error) {
+	stats := make([]*DownloadsPerFile, 0, 10)
+	return stats, db.GetEngine(ctx).
+		Table("package_download").
+		Select("file_id, SUM(count) AS count").
+		Where(opts.toConds()).
+		GroupBy("file_id").
+		OrderBy("count DESC, file_id ASC").
+		Find(&stats)
+}
+
+// GetDownloadsPerClient aggregates the downloads per client class, most downloads first
+func GetDownloadsPerClient(ctx context.Context, opts *DownloadStatsOptions) ([]*DownloadsPerClient, error) {
+	stats := make([]*DownloadsPerClient, 0, 10)
+	return stats, db.GetEngine(ctx).
+		Table("package_download").
+		Select("client, SUM(count) AS count").
+		Where(opts.toConds()).
+		GroupBy("client").
+		OrderBy("count DESC, client ASC").
+		Find(&stats)
+}
+
+// GetDownloadsPerVersion aggregates the downloads per version, most downloaded first
+func GetDownloadsPerVersion(ctx context.Context, opts *DownloadStatsOptions) ([]*DownloadsPerVersion, error) {
+	stats := make([]*DownloadsPerVersion, 0, 10)
+	return stats, db.GetEngine(ctx).
+		Table("package_download").
+		Select("version_id, SUM(count) AS count").
+		Where(opts.toConds()).
+		GroupBy("version_id").
+		OrderBy("count DESC, version_id ASC").
+		Find(&stats)
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(PackageMirror))
+}
+
+// ErrPackageMirrorNotExist indicates a package mirror not exist error
+var ErrPackageMirrorNotExist = errors.New("Package mirror does not exist")
+
+// PackageMirrorRule selects the packages versions of the upstream registry which are mirrored
+type PackageMirrorRule struct {
+	Name string `json:"name"`
+	// VersionRange is a version constraint like ">= 1.2, < 2", empty matches all versions
+	VersionRange string `json:"version_range,omitempty"`
+}
+
+// PackageMirror periodically syncs selected packages of an upstream registry into the packages of an owner
+type PackageMirror struct {
+	ID           int64                `xorm:"pk autoincr"`
+	OwnerID      int64                `xorm:"INDEX NOT NULL"`
+	Type         Type                 `xorm:"NOT NULL"`
+	UpstreamURL  string               `xorm:"TEXT NOT NULL"`
+	Rules        []*PackageMirrorRule `xorm:"JSON TEXT"`
+	CreatorID    int64                `xorm:"NOT NULL DEFAULT 0"`
+	Interval     time.Duration
+	NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+	LastSyncUnix timeutil.TimeStamp
+	LastError    string             `xorm:"TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ScheduleNextSync sets the time of the next sync relative to now
+func (m *PackageMirror) ScheduleNextSync() {
+	m.NextSyncUnix = timeutil.TimeStampNow().AddDuration(m.Interval)
+}
+
+// CreateMirror inserts a package mirror
+func CreateMirror(ctx context.Context, m *PackageMirror) error {
+	_, err := db.GetEngine(ctx).Insert(m)
+	return err
+}
+
+// UpdateMirrorCols updates the columns of a package mirror
+func UpdateMirrorCols(ctx context.Context, m *PackageMirror, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(m.ID).Cols(cols...).Update(m)
+	return err
+}
+
+// GetMirrorByID gets a package mirror of the owner
+func GetMirrorByID(ctx context.Context, ownerID, mirrorID int64) (*PackageMirror, error) {
+	m := &PackageMirror{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", mirrorID, ownerID).Get(m)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageMirrorNotExist
+	}
+	return m, nil
+}
+
+// GetMirrorsByOwner gets all package mirrors of the owner
+func GetMirrorsByOwner(ctx context.Context, ownerID int64) ([]*PackageMirror, error) {
+	mirrors := make([]*PackageMirror, 0, 5)
+	return mirrors, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("id").Find(&mirrors)
+}
+
+// GetMirrorsToSync gets the package mirrors whose next sync is due, the longest overdue first
+func GetMirrorsToSync(ctx context.Context, limit int) ([]*PackageMirror, error) {
+	mirrors := make([]*PackageMirror, 0, 10)
+	sess := db.GetEngine(ctx).
+		Where("next_sync_unix <= ?", timeutil.TimeStampNow()).
+		OrderBy("next_sync_unix, id")
+	if limit > 0 {
+		sess = sess.Limit(limit)
+	}
+	return mirrors, sess.Find(&mirrors)
+}
+
+// DeleteMirrorByID deletes a package mirror of the owner
+func DeleteMirrorByID(ctx context.Context, ownerID, mirrorID int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", mirrorID, ownerID).Delete(&PackageMirror{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageMirrorNotExist
+	}
+	return nil
+}
+
+// DeleteMirrorsByOwner deletes all package mirrors of the owner
+func DeleteMirrorsByOwner(ctx context.Context, ownerID int64) error {
+	_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(&PackageMirror{})
+	return err
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageShare))
+}
+
+// ErrPackageShareNotExist indicates a package share not exist error
+var ErrPackageShareNotExist = errors.New("Package share does not exist")
+
+// PackageShare adds a package of a user to the aggregated registries of an organization.
+// A share only applies while the package owner is a member of the organization.
+type PackageShare struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PackageID   int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	OrgID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// SharePackage shares a package with an organization
+func SharePackage(ctx context.Context, packageID, orgID int64) error {
+	e := db.GetEngine(ctx)
+
+	has, err := e.Where("package_id = ? AND org_id = ?", packageID, orgID).Exist(&PackageShare{})
+	if err != nil || has {
+		return err
+	}
+
+	_, err = e.Insert(&PackageShare{
+		PackageID: packageID,
+		OrgID:     orgID,
+	})
+	return err
+}
+
+// UnsharePackage removes the share of a package with an organization
+func UnsharePackage(ctx context.Context, packageID, orgID int64) error {
+	n, err := db.GetEngine(ctx).Where("package_id = ? AND org_id = ?", packageID, orgID).Delete(&PackageShare{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageShareNotExist
+	}
+	return nil
+}
+
+// GetPackageShares gets all shares of a package
+func GetPackageShares(ctx context.Context, packageID int64) ([]*PackageShare, error) {
+	pss := make([]*PackageShare, 0, 5)
+	return pss, db.GetEngine(ctx).Where("package_id = ?", packageID).OrderBy("org_id").Find(&pss)
+}
+
+// DeletePackageSharesByPackageID deletes all shares of a package
+func DeletePackageSharesByPackageID(ctx context.Context, packageID int64) error {
+	_, err := db.GetEngine(ctx).Where("package_id = ?", packageID).Delete(&PackageShare{})
+	return err
+}
+
+// DeletePackageSharesByOrgID deletes all shares with an organization
+func DeletePackageSharesByOrgID(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(&PackageShare{})
+	return err
+}
+
+// aggregatedPackagesCond matches the packages owned by the organization
+// and the packages shared with it by the current members of the organization
+func aggregatedPackagesCond(orgID int64) builder.Cond {
+	shared := builder.Select("package_share.package_id").
+		From("package_share").
+		Join("INNER", "package shared_package", "shared_package.id = package_share.package_id").
+		Join("INNER", "org_user", "org_user.uid = shared_package.owner_id AND org_user.org_id = package_share.org_id").
+		Where(builder.Eq{"package_share.org_id": orgID})
+
+	return builder.Eq{"package.owner_id": orgID}.Or(builder.In("package.id", shared))
+}
+
+// GetAggregatedPackagesByType gets all packages of a specific type owned by the organization or shared with it
+func GetAggregatedPackagesByType(ctx context.Context, orgID int64, packageType Type) ([]*Package, error) {
+	cond := builder.Eq{"package.type": packageType}.And(aggregatedPackagesCond(orgID))
+
+	ps := make([]*Package, 0, 10)
+	return ps, db.GetEngine(ctx).
+		Where(cond).
+		Find(&ps)
+}
+
+// GetAggregatedPackageByName gets a package by name from the packages owned by the organization or shared with it.
+// A package owned by the organization takes precedence over shared packages with the same name.
+func GetAggregatedPackageByName(ctx context.Context, orgID int64, packageType Type, name string) (*Package, error) {
+	p, err := GetPackageByName(ctx, orgID, packageType, name)
+	if err != ErrPackageNotExist {
+		return p, err
+	}
+
+	cond := builder.Eq{
+		"package.type":       packageType,
+		"package.lower_name": strings.ToLower(name),
+	}.And(aggregatedPackagesCond(orgID))
+
+	p = &Package{}
+	has, err := db.GetEngine(ctx).
+		Where(cond).
+		Asc("package.id").
+		Get(p)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageNotExist
+	}
+	return p, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(PackageVendorGrant))
+}
+
+// ErrPackageVendorGrantNotExist indicates a package vendor grant not exist error
+var ErrPackageVendorGrantNotExist = errors.New("Package vendor grant does not exist")
+
+// PackageVendorGrant reserves a vendor namespace of the composer registry of an organization for a team.
+// If a vendor has grants, only the members of the granted teams may publish packages of the vendor.
+type PackageVendorGrant struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Vendor      string             `xorm:"UNIQUE(s) NOT NULL"`
+	TeamID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// AddVendorGrant grants a team the publishing of the packages of a vendor
+func AddVendorGrant(ctx context.Context, ownerID int64, vendor string, teamID int64) error {
+	vendor = strings.ToLower(vendor)
+
+	e := db.GetEngine(ctx)
+	has, err := e.Where("owner_id = ? AND vendor = ? AND team_id = ?", ownerID, vendor, teamID).Exist(&PackageVendorGrant{})
+	if err != nil || has {
+		return err
+	}
+
+	_, err = e.Insert(&PackageVendorGrant{
+		OwnerID: ownerID,
+		Vendor:  vendor,
+		TeamID:  teamID,
+	})
+	return err
+}
+
+// RemoveVendorGrant revokes the grant of a team for the packages of a vendor
+func RemoveVendorGrant(ctx context.Context, ownerID int64, vendor string, teamID int64) error {
+	n, err := db.GetEngine(ctx).Where("owner_id = ? AND vendor = ? AND team_id = ?", ownerID, strings.ToLower(vendor), teamID).Delete(&PackageVendorGrant{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageVendorGrantNotExist
+	}
+	return nil
+}
+
+// GetVendorGrants gets all vendor grants of an owner
+func GetVendorGrants(ctx context.Context, ownerID int64) ([]*PackageVendorGrant, error) {
+	grants := make([]*PackageVendorGrant, 0, 10)
+	return grants, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("vendor, team_id").Find(&grants)
+}
+
+// CanPublishVendor checks if the user may publish the packages of a vendor.
+// Vendors without grants can be published by everyone with write access to the packages of the owner.
+func CanPublishVendor(ctx context.Context, ownerID int64, vendor string, userID int64) (bool, error) {
+	vendor = strings.ToLower(vendor)
+
+	e := db.GetEngine(ctx)
+	reserved, err := e.Where("owner_id = ? AND vendor = ?", ownerID, vendor).Exist(&PackageVendorGrant{})
+	if err != nil || !reserved {
+		return !reserved, err
+	}
+
+	return e.Table("package_vendor_grant").
+		Join("INNER", "team_user", "team_user.team_id = package_vendor_grant.team_id").
+		Where("team_user.uid = ?", userID).
+		And("package_vendor_grant.owner_id = ? AND package_vendor_grant.vendor = ?", ownerID, vendor).
+		Exist()
+}
+
+// DeleteVendorGrantsByTeamID deletes all vendor grants of a team
+func DeleteVendorGrantsByTeamID(ctx context.Context, teamID int64) error {
+	_, err := db.GetEngine(ctx).Where("team_id = ?", teamID).Delete(&PackageVendorGrant{})
+	return err
+}
+
+// DeleteVendorGrantsByOwner deletes all vendor grants of an owner
+func DeleteVendorGrantsByOwner(ctx context.Context, ownerID int64) error {
+	_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(&PackageVendorGrant{})
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -35,6 +36,7 @@ type PackageVersion struct {
 	IsInternal    bool               `xorm:"INDEX NOT NULL DEFAULT false"`
 	MetadataJSON  string             `xorm:"metadata_json TEXT"`
 	DownloadCount int64              `xorm:"NOT NULL DEFAULT 0"`
+	ReleaseNotes  string             `xorm:"TEXT"` // markdown
 }
 
 // GetOrInsertVersion inserts a version. If the same version exist already ErrDuplicatePackageVersion is returned
@@ -65,6 +67,12 @@ func UpdateVersion(ctx context.Context, pv *PackageVersion) error {
 	return err
 }
 
+// UpdateVersionReleaseNotes updates the release notes of a version
+func UpdateVersionReleaseNotes(ctx context.Context, pv *PackageVersion) error {
+	_, err := db.GetEngine(ctx).ID(pv.ID).Cols("release_notes").Update(pv)
+	return err
+}
+
 // IncrementDownloadCounter increments the download counter of a version
 func IncrementDownloadCounter(ctx context.Context, versionID int64) error {
 	_, err := db.GetEngine(ctx).Exec("UPDATE `package_version` SET `download_count` = `download_count` + 1 WHERE `id` = ?", versionID)
@@ -143,8 +151,11 @@ func GetVersionsByPackageName(ctx context.Context, ownerID int64, packageType Ty
 	return pvs, err
 }
 
-// DeleteVersionByID deletes a version by id
+// DeleteVersionByID deletes a version and its dependencies by id
 func DeleteVersionByID(ctx context.Context, versionID int64) error {
+	if err := DeleteDependenciesByVersionID(ctx, versionID); err != nil {
+		return err
+	}
 	_, err := db.GetEngine(ctx).ID(versionID).Delete(&PackageVersion{})
 	return err
 }
@@ -176,6 +187,10 @@ type PackageSearchOptions struct {
 	IsInternal      util.OptionalBool
 	HasFileWithName string            // only results are found which are associated with a file with the specific name
 	HasFiles        util.OptionalBool // only results are found which have associated files
+	HidePrivate     bool              // packages with a private visibility are excluded
+	AggregateOrgID  int64             // only results owned by the organization or shared with it are found
+	AllOwners       bool              // packages of all owners are searched, only results visible to the actor are found
+	Actor           *user_model.User  // the user searching the packages of all owners, nil for anonymous users
 	Sort            string
 	db.Paginator
 }
@@ -198,6 +213,15 @@ func (opts *PackageSearchOptions) toConds() builder.Cond {
 	if opts.PackageID != 0 {
 		cond = cond.And(builder.Eq{"package.id": opts.PackageID})
 	}
+	if opts.HidePrivate {
+		cond = cond.And(builder.Neq{"package.visibility": VisibilityPrivate})
+	}
+	if opts.AggregateOrgID != 0 {
+		cond = cond.And(aggregatedPackagesCond(opts.AggregateOrgID))
+	}
+	if opts.AllOwners {
+		cond = cond.And(visiblePackagesCond(opts.Actor))
+	}
 	if opts.Name.Value != "" {
 		if opts.Name.ExactMatch {
 			cond = cond.And(builder.Eq{"package.lower_name": strings.ToLower(opts.Name.Value)})
@@ -264,6 +288,9 @@ func (opts *PackageSearchOptions) configureOrderBy(e db.Engine) {
 		e.Asc("package_version.version")
 	case "oldest":
 		e.Asc("package_version.created_unix")
+	case "mostdownloads":
+		e.Desc("package_version.download_count")
+		e.Desc("package_version.created_unix")
 	default:
 		e.Desc("package_version.created_unix")
 	}
@@ -56,9 +56,37 @@ func accessLevel(ctx context.Context, user *user_model.User, repo *repo_model.Re
 	if has, err := db.GetByBean(ctx, a); !has || err != nil {
 		return mode, err
 	}
+
+	// the access may have been granted by a collaboration which expired but was not revoked yet
+	expired, err := repo_model.HasExpiredCollaboration(ctx, repo.ID, userID)
+	if err != nil {
+		return mode, err
+	}
+	if expired {
+		return teamAccessLevel(ctx, userID, repo, mode)
+	}
 	return a.Mode, nil
 }
 
+// teamAccessLevel returns the access level the user gets from the teams of the organization owning the repository
+func teamAccessLevel(ctx context.Context, userID int64, repo *repo_model.Repository, mode perm.AccessMode) (perm.AccessMode, error) {
+	if err := repo.GetOwner(ctx); err != nil {
+		return mode, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return mode, nil
+	}
+
+	teams, err := organization.GetUserRepoTeams(ctx, repo.OwnerID, userID, repo.ID)
+	if err != nil {
+		return mode, err
+	}
+	for _, t := range teams {
+		mode = maxAccessMode(mode, t.AccessMode)
+	}
+	return mode, nil
+}
+
 func maxAccessMode(modes ...perm.AccessMode) perm.AccessMode {
 	max := perm.AccessModeNone
 	for _, mode := range modes {
@@ -130,7 +158,7 @@ func refreshCollaboratorAccesses(ctx context.Context, repoID int64, accessMap ma
 		return fmt.Errorf("getCollaborations: %v", err)
 	}
 	for _, c := range collaborators {
-		if c.User.IsGhost() {
+		if c.User.IsGhost() || c.Collaboration.IsExpired() {
 			continue
 		}
 		updateUserAccess(accessMap, c.User, c.Collaboration.Mode)
@@ -196,7 +224,7 @@ func RecalculateUserAccess(ctx context.Context, repo *repo_model.Repository, uid
 	collaborator, err := repo_model.GetCollaboration(ctx, repo.ID, uid)
 	if err != nil {
 		return err
-	} else if collaborator != nil {
+	} else if collaborator != nil && !collaborator.IsExpired() {
 		accessMode = collaborator.Mode
 	}
 
@@ -11,8 +11,10 @@ import (
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -68,6 +70,31 @@ func TestAccessLevel(t *testing.T) {
 	assert.Equal(t, perm_model.AccessModeRead, level)
 }
 
+func TestAccessLevelExpiredCollaboration(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// restricted user which is a collaborator of the public repository
+	user29 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 29})
+	repo4 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, repo4.ID, user29.ID, timeutil.TimeStampNow().Add(3600)))
+
+	level, err := access_model.AccessLevel(user29, repo4)
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeWrite, level)
+
+	// the collaboration expired but the cron task did not revoke it yet
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, repo4.ID, user29.ID, timeutil.TimeStampNow().Add(-3600)))
+
+	level, err = access_model.AccessLevel(user29, repo4)
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeNone, level)
+
+	perm, err := access_model.GetUserRepoPermission(db.DefaultContext, repo4, user29)
+	assert.NoError(t, err)
+	assert.False(t, perm.CanWrite(unit.TypeCode))
+}
+
 func TestHasAccess(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 
@@ -170,7 +170,7 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 
 	var is bool
 	if user != nil {
-		is, err = repo_model.IsCollaborator(ctx, repo.ID, user.ID)
+		is, err = repo_model.IsActiveCollaborator(ctx, repo.ID, user.ID)
 		if err != nil {
 			return perm, err
 		}
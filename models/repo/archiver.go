@@ -23,6 +23,7 @@ type ArchiverStatus int
 const (
 	ArchiverGenerating = iota // the archiver is generating
 	ArchiverReady             // it's ready
+	ArchiverFailed            // the last generation failed
 )
 
 // RepoArchiver represents all archivers
@@ -44,6 +45,19 @@ func (archiver *RepoArchiver) RelativePath() string {
 	return fmt.Sprintf("%d/%s/%s.%s", archiver.RepoID, archiver.CommitID[:2], archiver.CommitID, archiver.Type.String())
 }
 
+// ArchiveStoredSuffixes are the suffixes of the files stored next to an archive: its checksums and its signature
+var ArchiveStoredSuffixes = []string{".sha256", ".sha512", ".asc"}
+
+// StoredPaths returns the paths of the archive and of the files stored next to it relative to the archive storage root.
+func (archiver *RepoArchiver) StoredPaths() []string {
+	p := archiver.RelativePath()
+	paths := []string{p}
+	for _, suffix := range ArchiveStoredSuffixes {
+		paths = append(paths, p+suffix)
+	}
+	return paths
+}
+
 var delRepoArchiver = new(RepoArchiver)
 
 // DeleteRepoArchiver delete archiver
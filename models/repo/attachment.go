@@ -14,6 +14,8 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // Attachment represent a attachment of issue/comment/release.
@@ -28,6 +30,7 @@ type Attachment struct {
 	Name          string
 	DownloadCount int64              `xorm:"DEFAULT 0"`
 	Size          int64              `xorm:"DEFAULT 0"`
+	HashSHA256    string             `xorm:"hash_sha256 VARCHAR(64)"` // empty for attachments uploaded before this column was added
 	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
 }
 
@@ -238,3 +241,32 @@ func DeleteOrphanedAttachments() error {
 		Delete(new(Attachment))
 	return err
 }
+
+// OrphanedIssueAttachmentsCond returns the condition of the attachments whose issue or comment was deleted
+func OrphanedIssueAttachmentsCond() builder.Cond {
+	return builder.Or(
+		builder.Gt{"issue_id": 0}.And(builder.NotIn("issue_id", builder.Select("id").From("issue"))),
+		builder.Gt{"comment_id": 0}.And(builder.NotIn("comment_id", builder.Select("id").From("comment"))),
+	)
+}
+
+// ClosedIssueAttachmentsCond returns the condition of the attachments of closed issues and their comments uploaded before the time
+func ClosedIssueAttachmentsCond(before timeutil.TimeStamp) builder.Cond {
+	return builder.Lt{"created_unix": before}.
+		And(builder.In("issue_id", builder.Select("id").From("issue").Where(builder.Eq{"is_closed": true})))
+}
+
+// CountAttachmentsByCond returns the number and the total size of the attachments matching the condition
+func CountAttachmentsByCond(ctx context.Context, cond builder.Cond) (count, size int64, err error) {
+	if count, err = db.GetEngine(ctx).Where(cond).Count(new(Attachment)); err != nil || count == 0 {
+		return 0, 0, err
+	}
+	size, err = db.GetEngine(ctx).Where(cond).SumInt(new(Attachment), "size")
+	return count, size, err
+}
+
+// FindAttachmentsByCond returns at most limit attachments matching the condition, oldest first
+func FindAttachmentsByCond(ctx context.Context, cond builder.Cond, limit int) ([]*Attachment, error) {
+	attachments := make([]*Attachment, 0, limit)
+	return attachments, db.GetEngine(ctx).Where(cond).OrderBy("id").Limit(limit).Find(&attachments)
+}
@@ -14,6 +14,8 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // Collaboration represent the relation between an individual and a repository.
@@ -24,12 +26,31 @@ type Collaboration struct {
 	Mode        perm.AccessMode    `xorm:"DEFAULT 2 NOT NULL"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	// the access gets revoked after this time, 0 if it never expires
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// time the reminder about the expiry was sent, 0 if no reminder was sent
+	RemindedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 }
 
 func init() {
 	db.RegisterModel(new(Collaboration))
 }
 
+// HasExpiry returns true if the collaboration expires
+func (c *Collaboration) HasExpiry() bool {
+	return c.ExpiresUnix != 0
+}
+
+// IsExpired returns true if the collaboration has expired but was not revoked yet
+func (c *Collaboration) IsExpired() bool {
+	return c.ExpiresUnix != 0 && c.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// expiredCollaborationCond matches the collaborations which have expired but were not revoked yet
+func expiredCollaborationCond() builder.Cond {
+	return builder.Neq{"expires_unix": 0}.And(builder.Lte{"expires_unix": timeutil.TimeStampNow()})
+}
+
 // Collaborator represents a user with collaboration details.
 type Collaborator struct {
 	*user_model.User
@@ -85,6 +106,22 @@ func IsCollaborator(ctx context.Context, repoID, userID int64) (bool, error) {
 	return db.GetEngine(ctx).Get(&Collaboration{RepoID: repoID, UserID: userID})
 }
 
+// IsActiveCollaborator check if a user is a collaborator of a repository whose collaboration has not expired
+func IsActiveCollaborator(ctx context.Context, repoID, userID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID, "user_id": userID}).
+		And(builder.Not{expiredCollaborationCond()}).
+		Exist(new(Collaboration))
+}
+
+// HasExpiredCollaboration check if a user has a collaboration on a repository which has expired but was not revoked yet
+func HasExpiredCollaboration(ctx context.Context, repoID, userID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID, "user_id": userID}).
+		And(expiredCollaborationCond()).
+		Exist(new(Collaboration))
+}
+
 func getCollaborations(ctx context.Context, repoID int64, listOptions db.ListOptions) ([]*Collaboration, error) {
 	if listOptions.Page == 0 {
 		collaborations := make([]*Collaboration, 0, 8)
@@ -99,6 +136,63 @@ func getCollaborations(ctx context.Context, repoID int64, listOptions db.ListOpt
 	return collaborations, e.Find(&collaborations, &Collaboration{RepoID: repoID})
 }
 
+// SetCollaborationExpiry sets the time after which the collaboration gets revoked, 0 to never expire.
+// A reminder will be sent again for the new expiry.
+func SetCollaborationExpiry(ctx context.Context, repoID, uid int64, expires timeutil.TimeStamp) error {
+	_, err := db.GetEngine(ctx).
+		Where("repo_id = ? AND user_id = ?", repoID, uid).
+		Cols("expires_unix", "reminded_unix").
+		Update(&Collaboration{ExpiresUnix: expires})
+	return err
+}
+
+// MarkCollaborationReminded stores that the reminder about the expiry was sent
+func MarkCollaborationReminded(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Cols("reminded_unix").Update(&Collaboration{RemindedUnix: timeutil.TimeStampNow()})
+	return err
+}
+
+// FindCollaborationsOptions represents the options to search collaborations
+type FindCollaborationsOptions struct {
+	db.ListOptions
+	RepoID        int64
+	ExpiresBefore timeutil.TimeStamp // only expiring collaborations which expire before this time
+	OnlyExpiring  bool
+	NotReminded   bool
+}
+
+func (opts *FindCollaborationsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.OnlyExpiring || opts.ExpiresBefore != 0 {
+		cond = cond.And(builder.Gt{"expires_unix": 0})
+	}
+	if opts.ExpiresBefore != 0 {
+		cond = cond.And(builder.Lt{"expires_unix": opts.ExpiresBefore})
+	}
+	if opts.NotReminded {
+		cond = cond.And(builder.Eq{"reminded_unix": 0})
+	}
+	return cond
+}
+
+// FindCollaborations returns the collaborations matching the options,
+// the first to expire first and collaborations without expiry last
+func FindCollaborations(ctx context.Context, opts *FindCollaborationsOptions) ([]*Collaboration, int64, error) {
+	sess := db.GetEngine(ctx).
+		Where(opts.toConds()).
+		OrderBy("CASE WHEN expires_unix = 0 THEN 1 ELSE 0 END, expires_unix ASC, id ASC")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	collaborations := make([]*Collaboration, 0, 10)
+	count, err := sess.FindAndCount(&collaborations)
+	return collaborations, count, err
+}
+
 // ChangeCollaborationAccessMode sets new access mode for the collaboration.
 func ChangeCollaborationAccessModeCtx(ctx context.Context, repo *Repository, uid int64, mode perm.AccessMode) error {
 	// Discard invalid input
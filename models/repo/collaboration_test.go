@@ -12,6 +12,7 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,3 +70,45 @@ func TestRepository_ChangeCollaborationAccessMode(t *testing.T) {
 
 	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: repo.ID})
 }
+
+func TestFindCollaborations(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 29, 2000))
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 4, 1000))
+
+	collaborations, count, err := repo_model.FindCollaborations(db.DefaultContext, &repo_model.FindCollaborationsOptions{RepoID: 4})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, collaborations, 2) {
+		assert.EqualValues(t, 4, collaborations[0].UserID)
+		assert.EqualValues(t, 29, collaborations[1].UserID)
+	}
+
+	// collaborations without expiry are listed last
+	collaborations, count, err = repo_model.FindCollaborations(db.DefaultContext, &repo_model.FindCollaborationsOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1000, collaborations[0].ExpiresUnix)
+	assert.EqualValues(t, 2000, collaborations[1].ExpiresUnix)
+	assert.False(t, collaborations[count-1].HasExpiry())
+
+	collaborations, _, err = repo_model.FindCollaborations(db.DefaultContext, &repo_model.FindCollaborationsOptions{ExpiresBefore: 1500})
+	assert.NoError(t, err)
+	if assert.Len(t, collaborations, 1) {
+		assert.EqualValues(t, 4, collaborations[0].UserID)
+
+		assert.NoError(t, repo_model.MarkCollaborationReminded(db.DefaultContext, collaborations[0].ID))
+	}
+
+	collaborations, _, err = repo_model.FindCollaborations(db.DefaultContext, &repo_model.FindCollaborationsOptions{OnlyExpiring: true, NotReminded: true})
+	assert.NoError(t, err)
+	if assert.Len(t, collaborations, 1) {
+		assert.EqualValues(t, 29, collaborations[0].UserID)
+	}
+
+	// a new expiry resets the reminder
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 4, timeutil.TimeStamp(0)))
+	collaboration := unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: 4, UserID: 4})
+	assert.False(t, collaboration.HasExpiry())
+	assert.EqualValues(t, 0, collaboration.RemindedUnix)
+}
@@ -21,6 +21,19 @@ func GetRepositoriesByForkID(ctx context.Context, forkID int64) ([]*Repository,
 		Find(&repos)
 }
 
+// GetForksOfRepositories returns at most limit direct forks of all the given repositories, all forks if limit is 0
+func GetForksOfRepositories(ctx context.Context, repoIDs []int64, limit int) ([]*Repository, error) {
+	sess := db.GetEngine(ctx).
+		In("fork_id", repoIDs).
+		OrderBy("id")
+	if limit > 0 {
+		sess = sess.Limit(limit)
+	}
+
+	forks := make([]*Repository, 0, len(repoIDs))
+	return forks, sess.Find(&forks)
+}
+
 // GetForkedRepo checks if given user has already forked a repository with given ID.
 func GetForkedRepo(ownerID, repoID int64) *Repository {
 	repo := new(Repository)
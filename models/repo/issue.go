@@ -70,3 +70,21 @@ func (repo *Repository) IsDependenciesEnabledCtx(ctx context.Context) bool {
 	}
 	return u.IssuesConfig().EnableDependencies
 }
+
+// IssueKeywords returns the keywords closing and reopening issues of the repository,
+// falling back to the globally configured ones.
+func (repo *Repository) IssueKeywords(ctx context.Context) (close, reopen []string) {
+	close, reopen = setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords
+	u, err := repo.GetUnitCtx(ctx, unit.TypeIssues)
+	if err != nil {
+		return close, reopen
+	}
+	cfg := u.IssuesConfig()
+	if len(cfg.CloseKeywords) > 0 {
+		close = cfg.CloseKeywords
+	}
+	if len(cfg.ReopenKeywords) > 0 {
+		reopen = cfg.ReopenKeywords
+	}
+	return close, reopen
+}
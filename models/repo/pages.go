@@ -0,0 +1,77 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// PagesDeploymentStatus represents the result of the last deployment of the pages
+type PagesDeploymentStatus int
+
+// enumerate all pages deployment statuses
+const (
+	PagesDeploymentDeployed PagesDeploymentStatus = iota // the last deployment succeeded
+	PagesDeploymentFailed                                // the last deployment failed, the previous commit is still served
+)
+
+// PagesDeployment describes which commit of the pages branch of a repository is served
+type PagesDeployment struct {
+	ID       int64  `xorm:"pk autoincr"`
+	RepoID   int64  `xorm:"UNIQUE NOT NULL"`
+	CommitID string `xorm:"VARCHAR(40)"` // empty if no deployment succeeded yet
+	// the directory of the commit the pages are served from
+	Directory    string
+	Status       PagesDeploymentStatus `xorm:"NOT NULL DEFAULT 0"`
+	Error        string                `xorm:"TEXT"`
+	DeployedUnix timeutil.TimeStamp
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(PagesDeployment))
+}
+
+// IsDeployed returns true if a commit is served
+func (d *PagesDeployment) IsDeployed() bool {
+	return d.CommitID != ""
+}
+
+// IsFailed returns true if the last deployment failed
+func (d *PagesDeployment) IsFailed() bool {
+	return d.Status == PagesDeploymentFailed
+}
+
+// GetPagesDeployment returns the pages deployment of the repository or nil if the pages were never deployed
+func GetPagesDeployment(ctx context.Context, repoID int64) (*PagesDeployment, error) {
+	d := &PagesDeployment{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(d)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	return d, nil
+}
+
+// SavePagesDeployment inserts or updates the pages deployment of the repository
+func SavePagesDeployment(ctx context.Context, d *PagesDeployment) error {
+	if d.ID == 0 {
+		_, err := db.GetEngine(ctx).Insert(d)
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(d.ID).AllCols().Update(d)
+	return err
+}
+
+// DeletePagesDeployment deletes the pages deployment of the repository
+func DeletePagesDeployment(ctx context.Context, repoID int64) error {
+	_, err := db.DeleteByBean(ctx, &PagesDeployment{RepoID: repoID})
+	return err
+}
@@ -0,0 +1,248 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PropertyDefinition))
+	db.RegisterModel(new(RepoProperty))
+}
+
+var propertyNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ValidatePropertyName checks a property name by length and match pattern rules
+func ValidatePropertyName(name string) bool {
+	return len(name) <= 50 && propertyNamePattern.MatchString(name)
+}
+
+// MaxPropertyValueLength is the maximum length of the value of a repository property
+const MaxPropertyValueLength = 255
+
+// ErrPropertyDefinitionNotExist represents a "PropertyDefinitionNotExist" kind of error.
+type ErrPropertyDefinitionNotExist struct {
+	OwnerID int64
+	Name    string
+}
+
+// IsErrPropertyDefinitionNotExist checks if an error is a ErrPropertyDefinitionNotExist.
+func IsErrPropertyDefinitionNotExist(err error) bool {
+	_, ok := err.(ErrPropertyDefinitionNotExist)
+	return ok
+}
+
+func (err ErrPropertyDefinitionNotExist) Error() string {
+	return fmt.Sprintf("property definition does not exist [owner_id: %d, name: %s]", err.OwnerID, err.Name)
+}
+
+// ErrPropertyDefinitionAlreadyExist represents a "PropertyDefinitionAlreadyExist" kind of error.
+type ErrPropertyDefinitionAlreadyExist struct {
+	OwnerID int64
+	Name    string
+}
+
+// IsErrPropertyDefinitionAlreadyExist checks if an error is a ErrPropertyDefinitionAlreadyExist.
+func IsErrPropertyDefinitionAlreadyExist(err error) bool {
+	_, ok := err.(ErrPropertyDefinitionAlreadyExist)
+	return ok
+}
+
+func (err ErrPropertyDefinitionAlreadyExist) Error() string {
+	return fmt.Sprintf("property definition already exists [owner_id: %d, name: %s]", err.OwnerID, err.Name)
+}
+
+// ErrInvalidPropertyValue represents a "InvalidPropertyValue" kind of error.
+type ErrInvalidPropertyValue struct {
+	Name  string
+	Value string
+}
+
+// IsErrInvalidPropertyValue checks if an error is a ErrInvalidPropertyValue.
+func IsErrInvalidPropertyValue(err error) bool {
+	_, ok := err.(ErrInvalidPropertyValue)
+	return ok
+}
+
+func (err ErrInvalidPropertyValue) Error() string {
+	return fmt.Sprintf("invalid property value [name: %s, value: %s]", err.Name, err.Value)
+}
+
+// PropertyDefinition represents a custom property which an organization defines for its repositories
+type PropertyDefinition struct {
+	ID          int64  `xorm:"pk autoincr"`
+	OwnerID     int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	Description string `xorm:"TEXT"`
+	// AllowedValues restricts the values of the property, any value is allowed if it is empty
+	AllowedValues []string           `xorm:"TEXT JSON"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsAllowedValue returns true if value may be set for the property
+func (d *PropertyDefinition) IsAllowedValue(value string) bool {
+	if len(value) > MaxPropertyValueLength {
+		return false
+	}
+	return len(d.AllowedValues) == 0 || util.IsStringInSlice(value, d.AllowedValues)
+}
+
+// RepoProperty represents the value of a custom property of a repository
+type RepoProperty struct { //revive:disable-line:exported
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name   string `xorm:"UNIQUE(s) INDEX VARCHAR(50) NOT NULL"`
+	Value  string `xorm:"INDEX VARCHAR(255) NOT NULL"`
+}
+
+// CreatePropertyDefinition creates a new property definition
+func CreatePropertyDefinition(ctx context.Context, def *PropertyDefinition) error {
+	has, err := db.GetEngine(ctx).Exist(&PropertyDefinition{OwnerID: def.OwnerID, Name: def.Name})
+	if err != nil {
+		return err
+	} else if has {
+		return ErrPropertyDefinitionAlreadyExist{def.OwnerID, def.Name}
+	}
+	return db.Insert(ctx, def)
+}
+
+// GetPropertyDefinition returns the property definition of an owner by name
+func GetPropertyDefinition(ctx context.Context, ownerID int64, name string) (*PropertyDefinition, error) {
+	def := &PropertyDefinition{OwnerID: ownerID, Name: name}
+	has, err := db.GetEngine(ctx).Get(def)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPropertyDefinitionNotExist{ownerID, name}
+	}
+	return def, nil
+}
+
+// GetPropertyDefinitions returns all property definitions of an owner ordered by name
+func GetPropertyDefinitions(ctx context.Context, ownerID int64) ([]*PropertyDefinition, error) {
+	defs := make([]*PropertyDefinition, 0, 10)
+	return defs, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Asc("name").Find(&defs)
+}
+
+// UpdatePropertyDefinition updates the description and the allowed values of a property definition.
+// Values of repositories which are no longer allowed are removed.
+func UpdatePropertyDefinition(ctx context.Context, def *PropertyDefinition) error {
+	return db.WithTx(func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(def.ID).Cols("description", "allowed_values").Update(def); err != nil {
+			return err
+		}
+		if len(def.AllowedValues) == 0 {
+			return nil
+		}
+		_, err := db.GetEngine(ctx).
+			Where(ownerRepoPropertiesCond(def.OwnerID, def.Name)).
+			And(builder.NotIn("value", def.AllowedValues)).
+			Delete(&RepoProperty{})
+		return err
+	}, ctx)
+}
+
+// DeletePropertyDefinition deletes a property definition and the values of the repositories of its owner
+func DeletePropertyDefinition(ctx context.Context, def *PropertyDefinition) error {
+	return db.WithTx(func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(def.ID).Delete(&PropertyDefinition{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where(ownerRepoPropertiesCond(def.OwnerID, def.Name)).Delete(&RepoProperty{})
+		return err
+	}, ctx)
+}
+
+func ownerRepoPropertiesCond(ownerID int64, name string) builder.Cond {
+	return builder.Eq{"name": name}.And(
+		builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": ownerID})),
+	)
+}
+
+// GetRepoProperties returns the custom properties of a repository
+func GetRepoProperties(ctx context.Context, repoID int64) (map[string]string, error) {
+	props := make([]*RepoProperty, 0, 10)
+	if err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Find(&props); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(props))
+	for _, prop := range props {
+		result[prop.Name] = prop.Value
+	}
+	return result, nil
+}
+
+// CheckRepoProperties checks that all properties are defined by the owner and that the values are allowed
+func CheckRepoProperties(ctx context.Context, ownerID int64, props map[string]string) error {
+	for name, value := range props {
+		def, err := GetPropertyDefinition(ctx, ownerID, name)
+		if err != nil {
+			return err
+		}
+		if value != "" && !def.IsAllowedValue(value) {
+			return ErrInvalidPropertyValue{name, value}
+		}
+	}
+	return nil
+}
+
+// SetRepoProperties sets the custom properties of a repository, an empty value removes a property.
+// All properties must be defined by the owner of the repository and the values must be allowed.
+func SetRepoProperties(ctx context.Context, repo *Repository, props map[string]string) error {
+	return db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		for name, value := range props {
+			def, err := GetPropertyDefinition(ctx, repo.OwnerID, name)
+			if err != nil {
+				return err
+			}
+			if value == "" {
+				if _, err := e.Delete(&RepoProperty{RepoID: repo.ID, Name: name}); err != nil {
+					return err
+				}
+				continue
+			}
+			if !def.IsAllowedValue(value) {
+				return ErrInvalidPropertyValue{name, value}
+			}
+
+			prop := &RepoProperty{RepoID: repo.ID, Name: name}
+			has, err := e.Get(prop)
+			if err != nil {
+				return err
+			}
+			prop.Value = value
+			if has {
+				_, err = e.ID(prop.ID).Cols("value").Update(prop)
+			} else {
+				_, err = e.Insert(prop)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ctx)
+}
+
+// RemoveUndefinedRepoProperties removes the properties of a repository which are not defined by
+// the owner, e.g. after a transfer
+func RemoveUndefinedRepoProperties(ctx context.Context, repoID, ownerID int64) error {
+	_, err := db.GetEngine(ctx).
+		Where("repo_id = ?", repoID).
+		And(builder.NotIn("name", builder.Select("name").From("property_definition").Where(builder.Eq{"owner_id": ownerID}))).
+		Delete(&RepoProperty{})
+	return err
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoProperties(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	tier := &repo_model.PropertyDefinition{OwnerID: repo.OwnerID, Name: "service-tier", AllowedValues: []string{"gold", "silver"}}
+	assert.NoError(t, repo_model.CreatePropertyDefinition(db.DefaultContext, tier))
+	assert.True(t, repo_model.IsErrPropertyDefinitionAlreadyExist(repo_model.CreatePropertyDefinition(db.DefaultContext, &repo_model.PropertyDefinition{OwnerID: repo.OwnerID, Name: "service-tier"})))
+	assert.NoError(t, repo_model.CreatePropertyDefinition(db.DefaultContext, &repo_model.PropertyDefinition{OwnerID: repo.OwnerID, Name: "cost-center"}))
+
+	assert.NoError(t, repo_model.SetRepoProperties(db.DefaultContext, repo, map[string]string{"service-tier": "gold", "cost-center": "4711"}))
+	assert.True(t, repo_model.IsErrInvalidPropertyValue(repo_model.SetRepoProperties(db.DefaultContext, repo, map[string]string{"service-tier": "bronze"})))
+	assert.True(t, repo_model.IsErrPropertyDefinitionNotExist(repo_model.SetRepoProperties(db.DefaultContext, repo, map[string]string{"owner-team": "core"})))
+
+	props, err := repo_model.GetRepoProperties(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"service-tier": "gold", "cost-center": "4711"}, props)
+
+	repos, count, err := repo_model.SearchRepository(&repo_model.SearchRepoOptions{
+		Private:    true,
+		Properties: map[string]string{"service-tier": "gold"},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.Equal(t, repo.ID, repos[0].ID)
+	}
+
+	// values which are no longer allowed are removed
+	tier.AllowedValues = []string{"silver"}
+	assert.NoError(t, repo_model.UpdatePropertyDefinition(db.DefaultContext, tier))
+	props, err = repo_model.GetRepoProperties(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cost-center": "4711"}, props)
+
+	// an empty value removes the property
+	assert.NoError(t, repo_model.SetRepoProperties(db.DefaultContext, repo, map[string]string{"cost-center": ""}))
+	unittest.AssertNotExistsBean(t, &repo_model.RepoProperty{RepoID: repo.ID})
+
+	assert.NoError(t, repo_model.SetRepoProperties(db.DefaultContext, repo, map[string]string{"service-tier": "silver"}))
+	assert.NoError(t, repo_model.DeletePropertyDefinition(db.DefaultContext, tier))
+	unittest.AssertNotExistsBean(t, &repo_model.PropertyDefinition{ID: tier.ID})
+	unittest.AssertNotExistsBean(t, &repo_model.RepoProperty{RepoID: repo.ID})
+}
@@ -265,6 +265,33 @@ func GetLatestReleaseByRepoID(repoID int64) (*Release, error) {
 	return rel, nil
 }
 
+// GetPreviousRelease returns the latest published release which was created before the given release.
+// Prereleases are only considered if the given release is a prerelease too.
+func GetPreviousRelease(ctx context.Context, rel *Release) (*Release, error) {
+	cond := builder.NewCond().
+		And(builder.Eq{"repo_id": rel.RepoID}).
+		And(builder.Eq{"is_draft": false}).
+		And(builder.Eq{"is_tag": false}).
+		And(builder.Neq{"id": rel.ID}).
+		And(builder.Lte{"created_unix": rel.CreatedUnix})
+	if !rel.IsPrerelease {
+		cond = cond.And(builder.Eq{"is_prerelease": false})
+	}
+
+	prev := new(Release)
+	has, err := db.GetEngine(ctx).
+		Desc("created_unix", "id").
+		Where(cond).
+		Get(prev)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReleaseNotExist{0, "previous"}
+	}
+
+	return prev, nil
+}
+
 // GetReleasesByRepoIDAndNames returns a list of releases of repository according repoID and tagNames.
 func GetReleasesByRepoIDAndNames(ctx context.Context, repoID int64, tagNames []string) (rels []*Release, err error) {
 	err = db.GetEngine(ctx).
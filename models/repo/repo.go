@@ -364,6 +364,16 @@ func (repo *Repository) MustGetUnit(tp unit.Type) *RepoUnit {
 			Type:   tp,
 			Config: new(IssuesConfig),
 		}
+	} else if tp == unit.TypeCode {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(CodeConfig),
+		}
+	} else if tp == unit.TypeReleases {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(ReleasesConfig),
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -506,6 +516,11 @@ func (repo *Repository) ComposeCompareURL(oldCommitID, newCommitID string) strin
 	return fmt.Sprintf("%s/%s/compare/%s...%s", url.PathEscape(repo.OwnerName), url.PathEscape(repo.Name), util.PathEscapeSegments(oldCommitID), util.PathEscapeSegments(newCommitID))
 }
 
+// ComposeCommitsURL returns the URL of the paginated commit history of a commit
+func (repo *Repository) ComposeCommitsURL(commitID string) string {
+	return fmt.Sprintf("%s/%s/commits/commit/%s", url.PathEscape(repo.OwnerName), url.PathEscape(repo.Name), util.PathEscapeSegments(commitID))
+}
+
 // IsOwnedBy returns true when user owns this repository
 func (repo *Repository) IsOwnedBy(userID int64) bool {
 	return repo.OwnerID == userID
@@ -157,6 +157,8 @@ type SearchRepoOptions struct {
 	Language string
 	// include description in keyword search
 	IncludeDescription bool
+	// only search repositories with all of the specified custom property values
+	Properties map[string]string
 	// None -> include has milestones AND has no milestone
 	// True -> include just has milestones
 	// False -> include just has no milestone
@@ -467,6 +469,13 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 			Where(builder.Eq{"language": opts.Language}).And(builder.Eq{"is_primary": true})))
 	}
 
+	for name, value := range opts.Properties {
+		cond = cond.And(builder.In("id", builder.
+			Select("repo_id").
+			From("repo_property").
+			Where(builder.Eq{"name": name, "value": value})))
+	}
+
 	if opts.Fork != util.OptionalBoolNone || opts.OnlyShowRelevant {
 		if opts.OnlyShowRelevant && opts.Fork == util.OptionalBoolNone {
 			cond = cond.And(builder.Eq{"is_fork": false})
@@ -673,6 +682,24 @@ func SearchRepositoryIDs(opts *SearchRepoOptions) ([]int64, int64, error) {
 	return ids, count, err
 }
 
+// FindAccessibleOrgRepoIDsByUnit returns the ids of the repositories of an organization
+// which have the unit enabled and in which the user can access the unit. user is nil for anonymous access.
+func FindAccessibleOrgRepoIDsByUnit(ctx context.Context, user *user_model.User, orgID int64, unitType unit.Type) ([]int64, error) {
+	cond := builder.Eq{"`repository`.owner_id": orgID}.And(
+		builder.In("`repository`.id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": unitType})),
+	)
+	if user == nil || !user.IsAdmin {
+		cond = cond.And(AccessibleRepositoryCondition(user, unitType))
+	}
+
+	ids := make([]int64, 0, 10)
+	return ids, db.GetEngine(ctx).
+		Table("repository").
+		Cols("`repository`.id").
+		Where(cond).
+		Find(&ids)
+}
+
 // AccessibleRepoIDsQuery queries accessible repository ids. Usable as a subquery wherever repo ids need to be filtered.
 func AccessibleRepoIDsQuery(user *user_model.User) *builder.Builder {
 	// NB: Please note this code needs to still work if user is nil
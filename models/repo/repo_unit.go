@@ -7,6 +7,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"path"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unit"
@@ -59,6 +60,79 @@ func (cfg *UnitConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// CodeConfig describes code config
+type CodeConfig struct {
+	RawCORSAllowDomain []string `json:"raw_cors_allow_domain,omitempty"`
+	RawCacheControl    string   `json:"raw_cache_control,omitempty"`
+}
+
+// FromDB fills up a CodeConfig from serialized format.
+func (cfg *CodeConfig) FromDB(bs []byte) error {
+	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
+}
+
+// ToDB exports a CodeConfig to a serialized format.
+func (cfg *CodeConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
+// ReleasePackageRule maps release assets with a name matching the pattern to a package type
+type ReleasePackageRule struct {
+	Pattern     string `json:"pattern"`
+	PackageType string `json:"package_type"`
+}
+
+// ReleasesConfig describes releases config
+type ReleasesConfig struct {
+	PackageRules []*ReleasePackageRule `json:"package_rules,omitempty"`
+}
+
+// FromDB fills up a ReleasesConfig from serialized format.
+func (cfg *ReleasesConfig) FromDB(bs []byte) error {
+	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
+}
+
+// ToDB exports a ReleasesConfig to a serialized format.
+func (cfg *ReleasesConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
+// MatchPackageRule returns the first package rule matching the asset name or nil
+func (cfg *ReleasesConfig) MatchPackageRule(assetName string) *ReleasePackageRule {
+	for _, rule := range cfg.PackageRules {
+		if matched, _ := path.Match(rule.Pattern, assetName); matched {
+			return rule
+		}
+	}
+	return nil
+}
+
+// PagesConfig describes pages config
+type PagesConfig struct {
+	// Branch the pages are served from, the default branch if empty
+	Branch string `json:"branch,omitempty"`
+	// Directory inside the branch containing the pages, the root directory if empty
+	Directory string `json:"directory,omitempty"`
+}
+
+// FromDB fills up a PagesConfig from serialized format.
+func (cfg *PagesConfig) FromDB(bs []byte) error {
+	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
+}
+
+// ToDB exports a PagesConfig to a serialized format.
+func (cfg *PagesConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
+// BranchName returns the branch the pages of the repository are served from
+func (cfg *PagesConfig) BranchName(repo *Repository) string {
+	if cfg.Branch != "" {
+		return cfg.Branch
+	}
+	return repo.DefaultBranch
+}
+
 // ExternalWikiConfig describes external wiki config
 type ExternalWikiConfig struct {
 	ExternalWikiURL string
@@ -97,6 +171,9 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	// CloseKeywords and ReopenKeywords replace the globally configured keywords of commit messages if set
+	CloseKeywords  []string `json:",omitempty"`
+	ReopenKeywords []string `json:",omitempty"`
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -170,7 +247,13 @@ func (r *RepoUnit) BeforeSet(colName string, val xorm.Cell) {
 			r.Config = new(PullRequestsConfig)
 		case unit.TypeIssues:
 			r.Config = new(IssuesConfig)
-		case unit.TypeCode, unit.TypeReleases, unit.TypeWiki, unit.TypeProjects, unit.TypePackages:
+		case unit.TypeCode:
+			r.Config = new(CodeConfig)
+		case unit.TypeReleases:
+			r.Config = new(ReleasesConfig)
+		case unit.TypePages:
+			r.Config = new(PagesConfig)
+		case unit.TypeWiki, unit.TypeProjects, unit.TypePackages:
 			fallthrough
 		default:
 			r.Config = new(UnitConfig)
@@ -184,8 +267,11 @@ func (r *RepoUnit) Unit() unit.Unit {
 }
 
 // CodeConfig returns config for unit.TypeCode
-func (r *RepoUnit) CodeConfig() *UnitConfig {
-	return r.Config.(*UnitConfig)
+func (r *RepoUnit) CodeConfig() *CodeConfig {
+	if cfg, ok := r.Config.(*CodeConfig); ok {
+		return cfg
+	}
+	return new(CodeConfig)
 }
 
 // PullRequestsConfig returns config for unit.TypePullRequests
@@ -194,8 +280,19 @@ func (r *RepoUnit) PullRequestsConfig() *PullRequestsConfig {
 }
 
 // ReleasesConfig returns config for unit.TypeReleases
-func (r *RepoUnit) ReleasesConfig() *UnitConfig {
-	return r.Config.(*UnitConfig)
+func (r *RepoUnit) ReleasesConfig() *ReleasesConfig {
+	if cfg, ok := r.Config.(*ReleasesConfig); ok {
+		return cfg
+	}
+	return new(ReleasesConfig)
+}
+
+// PagesConfig returns config for unit.TypePages
+func (r *RepoUnit) PagesConfig() *PagesConfig {
+	if cfg, ok := r.Config.(*PagesConfig); ok {
+		return cfg
+	}
+	return new(PagesConfig)
 }
 
 // ExternalWikiConfig returns config for unit.TypeExternalWiki
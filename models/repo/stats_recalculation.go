@@ -0,0 +1,241 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// StatsRecalculationKind is a kind of statistics of a repository which can be recalculated
+type StatsRecalculationKind string
+
+// enumerate all kinds of recalculated statistics
+const (
+	StatsRecalculationSize      StatsRecalculationKind = "size"      // the size of the git repository and its LFS objects
+	StatsRecalculationLanguages StatsRecalculationKind = "languages" // the language statistics of the default branch
+	StatsRecalculationCommits   StatsRecalculationKind = "commits"   // the cached commit count of the default branch
+	StatsRecalculationCounters  StatsRecalculationKind = "counters"  // the numbers of issues, pulls, stars, watches and the label and milestone counters
+)
+
+// StatsRecalculationKinds are all kinds of recalculated statistics
+var StatsRecalculationKinds = []StatsRecalculationKind{
+	StatsRecalculationSize,
+	StatsRecalculationLanguages,
+	StatsRecalculationCommits,
+	StatsRecalculationCounters,
+}
+
+// IsValid returns true if the kind is known
+func (k StatsRecalculationKind) IsValid() bool {
+	for _, kind := range StatsRecalculationKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// StatsRecalculationStatus represents the status of a recalculation
+type StatsRecalculationStatus int
+
+// enumerate all statuses of a recalculation
+const (
+	StatsRecalculationRunning   StatsRecalculationStatus = iota // repositories are queued or processed
+	StatsRecalculationFinished                                  // all repositories were processed
+	StatsRecalculationCancelled                                 // the recalculation was cancelled by an admin
+)
+
+func (s StatsRecalculationStatus) String() string {
+	switch s {
+	case StatsRecalculationRunning:
+		return "running"
+	case StatsRecalculationFinished:
+		return "finished"
+	case StatsRecalculationCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// ErrStatsRecalculationRunning represents a "StatsRecalculationRunning" kind of error.
+type ErrStatsRecalculationRunning struct {
+	ID int64
+}
+
+// IsErrStatsRecalculationRunning checks if an error is a ErrStatsRecalculationRunning.
+func IsErrStatsRecalculationRunning(err error) bool {
+	_, ok := err.(ErrStatsRecalculationRunning)
+	return ok
+}
+
+func (err ErrStatsRecalculationRunning) Error() string {
+	return fmt.Sprintf("a recalculation of the repository statistics is already running [id: %d]", err.ID)
+}
+
+// StatsRecalculation is a recalculation of the statistics of all repositories of the instance.
+// The repositories are queued in the order of their IDs, LastRepoID allows to resume queueing after a restart.
+type StatsRecalculation struct {
+	ID     int64                    `xorm:"pk autoincr"`
+	DoerID int64                    `xorm:"NOT NULL DEFAULT 0"` // 0 if started from the command line
+	Kinds  []string                 `xorm:"TEXT JSON"`
+	Status StatsRecalculationStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// repositories created after the start are not recalculated
+	MaxRepoID  int64 `xorm:"NOT NULL DEFAULT 0"`
+	LastRepoID int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Total is the number of repositories at the start, Queued the number of repositories pushed to the queue
+	Total     int64 `xorm:"NOT NULL DEFAULT 0"`
+	Queued    int64 `xorm:"NOT NULL DEFAULT 0"`
+	Processed int64 `xorm:"NOT NULL DEFAULT 0"`
+	Failed    int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	FinishedUnix timeutil.TimeStamp
+}
+
+func init() {
+	db.RegisterModel(new(StatsRecalculation))
+}
+
+// HasKind returns true if the kind of statistics is recalculated
+func (r *StatsRecalculation) HasKind(kind StatsRecalculationKind) bool {
+	for _, k := range r.Kinds {
+		if k == string(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRunning returns true if the recalculation is neither finished nor cancelled
+func (r *StatsRecalculation) IsRunning() bool {
+	return r.Status == StatsRecalculationRunning
+}
+
+// IsQueued returns true if all repositories of the recalculation were pushed to the queue
+func (r *StatsRecalculation) IsQueued() bool {
+	return r.LastRepoID >= r.MaxRepoID
+}
+
+// GetStatsRecalculationByID returns the recalculation with the id or nil if it does not exist
+func GetStatsRecalculationByID(ctx context.Context, id int64) (*StatsRecalculation, error) {
+	r := &StatsRecalculation{}
+	has, err := db.GetEngine(ctx).ID(id).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// GetLatestStatsRecalculation returns the most recently started recalculation or nil if none was ever started
+func GetLatestStatsRecalculation(ctx context.Context) (*StatsRecalculation, error) {
+	r := &StatsRecalculation{}
+	has, err := db.GetEngine(ctx).Desc("id").Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// GetRunningStatsRecalculation returns the running recalculation or nil if none is running
+func GetRunningStatsRecalculation(ctx context.Context) (*StatsRecalculation, error) {
+	r := &StatsRecalculation{}
+	has, err := db.GetEngine(ctx).Where("status = ?", StatsRecalculationRunning).Desc("id").Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// CreateStatsRecalculation starts a recalculation of all existing repositories, only one recalculation can run at a time
+func CreateStatsRecalculation(ctx context.Context, doerID int64, kinds []StatsRecalculationKind) (*StatsRecalculation, error) {
+	r := &StatsRecalculation{
+		DoerID: doerID,
+		Kinds:  make([]string, 0, len(kinds)),
+		Status: StatsRecalculationRunning,
+	}
+	for _, kind := range kinds {
+		if !kind.IsValid() {
+			return nil, fmt.Errorf("unknown kind of repository statistics: %s", kind)
+		}
+		if !r.HasKind(kind) {
+			r.Kinds = append(r.Kinds, string(kind))
+		}
+	}
+
+	return r, db.WithTx(func(ctx context.Context) error {
+		running, err := GetRunningStatsRecalculation(ctx)
+		if err != nil {
+			return err
+		} else if running != nil {
+			return ErrStatsRecalculationRunning{running.ID}
+		}
+
+		e := db.GetEngine(ctx)
+		if _, err := e.Table("repository").Select("MAX(id)").Get(&r.MaxRepoID); err != nil {
+			return err
+		}
+		if r.Total, err = e.Where("id <= ?", r.MaxRepoID).Count(new(Repository)); err != nil {
+			return err
+		}
+		return db.Insert(ctx, r)
+	}, ctx)
+}
+
+// UpdateStatsRecalculationCursor records that the repositories up to lastRepoID were queued
+func UpdateStatsRecalculationCursor(ctx context.Context, id, lastRepoID, queued int64) error {
+	_, err := db.GetEngine(ctx).Exec("UPDATE `stats_recalculation` SET last_repo_id = ?, queued = queued + ?, updated_unix = ? WHERE id = ?",
+		lastRepoID, queued, timeutil.TimeStampNow(), id)
+	return err
+}
+
+// IncreaseStatsRecalculationProgress records that a repository was processed
+func IncreaseStatsRecalculationProgress(ctx context.Context, id int64, failed bool) error {
+	failedIncr := 0
+	if failed {
+		failedIncr = 1
+	}
+	_, err := db.GetEngine(ctx).Exec("UPDATE `stats_recalculation` SET processed = processed + 1, failed = failed + ?, updated_unix = ? WHERE id = ?",
+		failedIncr, timeutil.TimeStampNow(), id)
+	return err
+}
+
+// FinishStatsRecalculation marks a running recalculation as finished if all repositories were queued and processed.
+// It returns true if the recalculation was finished by this call.
+func FinishStatsRecalculation(ctx context.Context, id int64) (bool, error) {
+	now := timeutil.TimeStampNow()
+	res, err := db.GetEngine(ctx).Exec("UPDATE `stats_recalculation` SET status = ?, finished_unix = ?, updated_unix = ? WHERE id = ? AND status = ? AND last_repo_id >= max_repo_id AND processed >= queued",
+		StatsRecalculationFinished, now, now, id, StatsRecalculationRunning)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CancelStatsRecalculation cancels the running recalculation, it returns nil if none is running
+func CancelStatsRecalculation(ctx context.Context) (*StatsRecalculation, error) {
+	r, err := GetRunningStatsRecalculation(ctx)
+	if err != nil || r == nil {
+		return nil, err
+	}
+
+	r.Status = StatsRecalculationCancelled
+	r.FinishedUnix = timeutil.TimeStampNow()
+	if _, err := db.GetEngine(ctx).ID(r.ID).Where("status = ?", StatsRecalculationRunning).Cols("status", "finished_unix").Update(r); err != nil {
+		return nil, err
+	}
+	return GetStatsRecalculationByID(ctx, r.ID)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsRecalculation(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := repo_model.CreateStatsRecalculation(db.DefaultContext, 1, []repo_model.StatsRecalculationKind{"unknown"})
+	assert.Error(t, err)
+
+	r, err := repo_model.CreateStatsRecalculation(db.DefaultContext, 1, []repo_model.StatsRecalculationKind{repo_model.StatsRecalculationSize, repo_model.StatsRecalculationCounters, repo_model.StatsRecalculationSize})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"size", "counters"}, r.Kinds)
+	assert.True(t, r.IsRunning())
+	assert.False(t, r.IsQueued())
+	assert.EqualValues(t, unittest.GetCount(t, &repo_model.Repository{}), r.Total)
+
+	_, err = repo_model.CreateStatsRecalculation(db.DefaultContext, 1, repo_model.StatsRecalculationKinds)
+	assert.True(t, repo_model.IsErrStatsRecalculationRunning(err))
+
+	assert.NoError(t, repo_model.UpdateStatsRecalculationCursor(db.DefaultContext, r.ID, r.MaxRepoID, r.Total))
+	assert.NoError(t, repo_model.IncreaseStatsRecalculationProgress(db.DefaultContext, r.ID, true))
+	finished, err := repo_model.FinishStatsRecalculation(db.DefaultContext, r.ID)
+	assert.NoError(t, err)
+	assert.False(t, finished)
+
+	for i := int64(1); i < r.Total; i++ {
+		assert.NoError(t, repo_model.IncreaseStatsRecalculationProgress(db.DefaultContext, r.ID, false))
+	}
+	finished, err = repo_model.FinishStatsRecalculation(db.DefaultContext, r.ID)
+	assert.NoError(t, err)
+	assert.True(t, finished)
+
+	r, err = repo_model.GetLatestStatsRecalculation(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Equal(t, repo_model.StatsRecalculationFinished, r.Status)
+	assert.True(t, r.IsQueued())
+	assert.Equal(t, r.Total, r.Processed)
+	assert.EqualValues(t, 1, r.Failed)
+	assert.NotZero(t, r.FinishedUnix)
+
+	cancelled, err := repo_model.CancelStatsRecalculation(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Nil(t, cancelled)
+
+	r, err = repo_model.CreateStatsRecalculation(db.DefaultContext, 0, repo_model.StatsRecalculationKinds)
+	assert.NoError(t, err)
+	cancelled, err = repo_model.CancelStatsRecalculation(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Equal(t, r.ID, cancelled.ID)
+	assert.Equal(t, repo_model.StatsRecalculationCancelled, cancelled.Status)
+
+	running, err := repo_model.GetRunningStatsRecalculation(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Nil(t, running)
+}
@@ -31,6 +31,12 @@ type RepoTransfer struct {
 	TeamIDs     []int64
 	Teams       []*organization.Team `xorm:"-"`
 
+	// AcceptorID and AcceptedUnix are set if a scheduled transfer was accepted and waits for its execution
+	AcceptorID   int64
+	AcceptedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// ScheduledUnix is the earliest time the transfer gets executed, 0 means immediately after acceptance
+	ScheduledUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL updated"`
 }
@@ -77,6 +83,16 @@ func (r *RepoTransfer) LoadAttributes() error {
 	return nil
 }
 
+// IsAccepted returns true if the transfer was accepted and waits for its scheduled execution
+func (r *RepoTransfer) IsAccepted() bool {
+	return r.AcceptedUnix > 0
+}
+
+// IsDue returns true if the scheduled time of the transfer has been reached
+func (r *RepoTransfer) IsDue() bool {
+	return r.ScheduledUnix <= timeutil.TimeStampNow()
+}
+
 // CanUserAcceptTransfer checks if the user has the rights to accept/decline a repo transfer.
 // For user, it checks if it's himself
 // For organizations, it checks if the user is able to create repos
@@ -116,6 +132,53 @@ func GetPendingRepositoryTransfer(repo *repo_model.Repository) (*RepoTransfer, e
 	return transfer, nil
 }
 
+// GetPendingRepositoryTransfersForUser returns the pending transfers the user can accept or reject.
+// These are the transfers to the user itself and to the organizations the user can create repositories in.
+func GetPendingRepositoryTransfersForUser(ctx context.Context, u *user_model.User) ([]*RepoTransfer, error) {
+	orgs, err := organization.GetOrgsCanCreateRepoByUserID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipientIDs := make([]int64, 0, len(orgs)+1)
+	recipientIDs = append(recipientIDs, u.ID)
+	for _, org := range orgs {
+		recipientIDs = append(recipientIDs, org.ID)
+	}
+
+	transfers := make([]*RepoTransfer, 0, 5)
+	return transfers, db.GetEngine(ctx).
+		In("recipient_id", recipientIDs).
+		OrderBy("created_unix ASC, id ASC").
+		Find(&transfers)
+}
+
+// AcceptRepositoryTransfer marks the transfer as accepted by the user.
+// It is used for scheduled transfers which get executed later.
+func AcceptRepositoryTransfer(ctx context.Context, transfer *RepoTransfer, acceptor *user_model.User) error {
+	transfer.AcceptorID = acceptor.ID
+	transfer.AcceptedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(transfer.ID).Cols("acceptor_id", "accepted_unix").Update(transfer)
+	return err
+}
+
+// SetRepositoryTransferSchedule sets the earliest time the transfer gets executed, 0 removes the schedule
+func SetRepositoryTransferSchedule(ctx context.Context, transfer *RepoTransfer, scheduled timeutil.TimeStamp) error {
+	transfer.ScheduledUnix = scheduled
+	_, err := db.GetEngine(ctx).ID(transfer.ID).Cols("scheduled_unix").Update(transfer)
+	return err
+}
+
+// FindDueRepositoryTransfers returns the accepted transfers whose scheduled time has been reached
+func FindDueRepositoryTransfers(ctx context.Context) ([]*RepoTransfer, error) {
+	transfers := make([]*RepoTransfer, 0, 5)
+	return transfers, db.GetEngine(ctx).
+		Where("accepted_unix > 0").
+		And("scheduled_unix <= ?", timeutil.TimeStampNow()).
+		OrderBy("scheduled_unix ASC, id ASC").
+		Find(&transfers)
+}
+
 func deleteRepositoryTransfer(ctx context.Context, repoID int64) error {
 	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(&RepoTransfer{})
 	return err
@@ -355,6 +418,11 @@ func TransferOwnership(doer *user_model.User, newOwnerName string, repo *repo_mo
 		}
 	}
 
+	// Remove custom properties which are not defined by the new owner
+	if err := repo_model.RemoveUndefinedRepoProperties(ctx, repo.ID, newOwner.ID); err != nil {
+		return fmt.Errorf("RemoveUndefinedRepoProperties: %v", err)
+	}
+
 	// Delete labels that belong to the old organization and comments that added these labels
 	if oldOwner.IsOrganization() {
 		if _, err := sess.Exec(`DELETE FROM issue_label WHERE issue_label.id IN (
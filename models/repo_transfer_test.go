@@ -7,9 +7,11 @@ package models
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -55,3 +57,42 @@ func TestRepositoryTransfer(t *testing.T) {
 	// Cancel transfer
 	assert.NoError(t, CancelRepositoryTransfer(repo))
 }
+
+func TestRepositoryTransferSchedule(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	recipient := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+
+	transfers, err := GetPendingRepositoryTransfersForUser(db.DefaultContext, recipient)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+	assert.EqualValues(t, repo.ID, transfers[0].RepoID)
+
+	transfer, err := GetPendingRepositoryTransfer(repo)
+	assert.NoError(t, err)
+	assert.False(t, transfer.IsAccepted())
+	assert.True(t, transfer.IsDue())
+
+	assert.NoError(t, SetRepositoryTransferSchedule(db.DefaultContext, transfer, timeutil.TimeStampNow().Add(3600)))
+	assert.False(t, transfer.IsDue())
+
+	assert.NoError(t, AcceptRepositoryTransfer(db.DefaultContext, transfer, recipient))
+
+	transfer, err = GetPendingRepositoryTransfer(repo)
+	assert.NoError(t, err)
+	assert.True(t, transfer.IsAccepted())
+	assert.EqualValues(t, recipient.ID, transfer.AcceptorID)
+
+	// Accepted but not due yet
+	transfers, err = FindDueRepositoryTransfers(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, transfers)
+
+	assert.NoError(t, SetRepositoryTransferSchedule(db.DefaultContext, transfer, timeutil.TimeStampNow().Add(-60)))
+
+	transfers, err = FindDueRepositoryTransfers(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+	assert.EqualValues(t, transfer.ID, transfers[0].ID)
+}
@@ -28,6 +28,7 @@ const (
 	TypeExternalTracker             // 7 ExternalTracker
 	TypeProjects                    // 8 Kanban board
 	TypePackages                    // 9 Packages
+	TypePages                       // 10 Pages
 )
 
 // Value returns integer value for unit type
@@ -55,6 +56,8 @@ func (u Type) String() string {
 		return "TypeProjects"
 	case TypePackages:
 		return "TypePackages"
+	case TypePages:
+		return "TypePages"
 	}
 	return fmt.Sprintf("Unknown Type %d", u)
 }
@@ -78,6 +81,7 @@ var (
 		TypeExternalTracker,
 		TypeProjects,
 		TypePackages,
+		TypePages,
 	}
 
 	// DefaultRepoUnits contains the default unit types
@@ -95,6 +99,7 @@ var (
 	NotAllowedDefaultRepoUnits = []Type{
 		TypeExternalWiki,
 		TypeExternalTracker,
+		TypePages,
 	}
 
 	// MustRepoUnits contains the units could not be disabled currently
@@ -128,6 +133,9 @@ func LoadUnitConfig() {
 	}
 
 	DisabledRepoUnits = FindUnitTypes(setting.Repository.DisabledRepoUnits...)
+	if !setting.Pages.Enabled {
+		DisabledRepoUnits = append(DisabledRepoUnits, TypePages)
+	}
 	// Check that must units are not disabled
 	for i, disabledU := range DisabledRepoUnits {
 		if !disabledU.CanDisable() {
@@ -289,6 +297,15 @@ var (
 		perm.AccessModeRead,
 	}
 
+	UnitPages = Unit{
+		TypePages,
+		"repo.pages",
+		"/pages",
+		"repo.pages.desc",
+		7,
+		perm.AccessModeRead,
+	}
+
 	// Units contains all the units
 	Units = map[Type]Unit{
 		TypeCode:            UnitCode,
@@ -300,6 +317,7 @@ var (
 		TypeExternalWiki:    UnitExternalWiki,
 		TypeProjects:        UnitProjects,
 		TypePackages:        UnitPackages,
+		TypePages:           UnitPages,
 	}
 )
 
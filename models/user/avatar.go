@@ -70,7 +70,9 @@ func (u *User) AvatarLinkWithSize(size int) string {
 	switch {
 	case u.UseCustomAvatar:
 		useLocalAvatar = true
-	case setting.DisableGravatar, setting.OfflineMode:
+	case setting.Avatar.DefaultAvatar == setting.DefaultAvatarImage:
+		return avatars.DefaultAvatarLink()
+	case setting.DisableGravatar, setting.OfflineMode, setting.Avatar.DefaultAvatar == setting.DefaultAvatarGenerated:
 		useLocalAvatar = true
 		autoGenerateAvatar = true
 	}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/avatars"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvatarLinkWithSizeDefaultAvatar(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldDefaultAvatar := setting.Avatar.DefaultAvatar
+	defer func() {
+		setting.Avatar.DefaultAvatar = oldDefaultAvatar
+	}()
+
+	u := unittest.AssertExistsAndLoadBean(t, &User{ID: 2})
+	assert.False(t, u.UseCustomAvatar)
+
+	setting.Avatar.DefaultAvatar = setting.DefaultAvatarImage
+	assert.Equal(t, avatars.DefaultAvatarLink(), u.AvatarLinkWithSize(100))
+
+	u.UseCustomAvatar = true
+	u.Avatar = "avatar2"
+	assert.Equal(t, avatars.GenerateUserAvatarImageLink("avatar2", 100), u.AvatarLinkWithSize(100))
+}
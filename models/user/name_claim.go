@@ -0,0 +1,139 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(NameClaim))
+}
+
+// NameClaimStatus is the status of a name claim
+type NameClaimStatus string
+
+// List of name claim statuses
+const (
+	NameClaimStatusPending   NameClaimStatus = "pending"
+	NameClaimStatusCompleted NameClaimStatus = "completed"
+	NameClaimStatusCancelled NameClaimStatus = "cancelled"
+)
+
+// NameClaim is the reclaim of the name of a dormant user by an administrator.
+// The user is notified and the name is released after the grace period unless the user signs in.
+type NameClaim struct {
+	ID     int64  `xorm:"pk autoincr"`
+	UserID int64  `xorm:"INDEX NOT NULL"`
+	Name   string `xorm:"INDEX NOT NULL"` // lower name of the user when the claim was made
+	// ClaimantID is the user who gets the released name, 0 to only release it
+	ClaimantID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	DoerID       int64              `xorm:"NOT NULL"`
+	Status       NameClaimStatus    `xorm:"INDEX NOT NULL"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created NOT NULL"`
+	DeadlineUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrNameClaimNotExist represents a "NameClaimNotExist" kind of error.
+type ErrNameClaimNotExist struct {
+	ID int64
+}
+
+// IsErrNameClaimNotExist checks if an error is a ErrNameClaimNotExist.
+func IsErrNameClaimNotExist(err error) bool {
+	_, ok := err.(ErrNameClaimNotExist)
+	return ok
+}
+
+func (err ErrNameClaimNotExist) Error() string {
+	return fmt.Sprintf("name claim does not exist [id: %d]", err.ID)
+}
+
+// ErrNameClaimAlreadyPending represents a "NameClaimAlreadyPending" kind of error.
+type ErrNameClaimAlreadyPending struct {
+	Name string
+}
+
+// IsErrNameClaimAlreadyPending checks if an error is a ErrNameClaimAlreadyPending.
+func IsErrNameClaimAlreadyPending(err error) bool {
+	_, ok := err.(ErrNameClaimAlreadyPending)
+	return ok
+}
+
+func (err ErrNameClaimAlreadyPending) Error() string {
+	return fmt.Sprintf("a claim of the name is already pending [name: %s]", err.Name)
+}
+
+// CreateNameClaim stores a new pending name claim
+func CreateNameClaim(ctx context.Context, c *NameClaim) error {
+	has, err := db.GetEngine(ctx).Exist(&NameClaim{UserID: c.UserID, Status: NameClaimStatusPending})
+	if err != nil {
+		return err
+	} else if has {
+		return ErrNameClaimAlreadyPending{c.Name}
+	}
+	c.Status = NameClaimStatusPending
+	return db.Insert(ctx, c)
+}
+
+// GetNameClaimByID returns the name claim with the id
+func GetNameClaimByID(ctx context.Context, id int64) (*NameClaim, error) {
+	c := new(NameClaim)
+	has, err := db.GetEngine(ctx).ID(id).Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrNameClaimNotExist{id}
+	}
+	return c, nil
+}
+
+// FindNameClaimsOptions filters name claims
+type FindNameClaimsOptions struct {
+	db.ListOptions
+	Status NameClaimStatus
+}
+
+func (opts *FindNameClaimsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Status != "" {
+		cond = cond.And(builder.Eq{"status": opts.Status})
+	}
+	return cond
+}
+
+// FindNameClaims returns the name claims matching the options, newest first
+func FindNameClaims(ctx context.Context, opts *FindNameClaimsOptions) ([]*NameClaim, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.toConds()).OrderBy("id DESC")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+	claims := make([]*NameClaim, 0, 10)
+	count, err := sess.FindAndCount(&claims)
+	return claims, count, err
+}
+
+// GetDueNameClaims returns the pending name claims whose grace period is over
+func GetDueNameClaims(ctx context.Context) ([]*NameClaim, error) {
+	claims := make([]*NameClaim, 0, 10)
+	return claims, db.GetEngine(ctx).
+		Where("status = ? AND deadline_unix <= ?", NameClaimStatusPending, timeutil.TimeStampNow()).
+		OrderBy("deadline_unix").
+		Find(&claims)
+}
+
+// UpdateNameClaimStatus changes the status of a name claim
+func UpdateNameClaimStatus(ctx context.Context, c *NameClaim, status NameClaimStatus) error {
+	c.Status = status
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("status").Update(c)
+	return err
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+func init() {
+	db.RegisterModel(new(NamePolicy))
+}
+
+// NamePolicyKind is the kind of a name policy
+type NamePolicyKind string
+
+// List of name policy kinds
+const (
+	// NamePolicyKindReserved reserves all user and organization names matching a glob pattern
+	NamePolicyKindReserved NamePolicyKind = "reserved"
+	// NamePolicyKindServicePrefix requires service accounts to use the prefix, other accounts must not use it
+	NamePolicyKindServicePrefix NamePolicyKind = "service_prefix"
+)
+
+// IsValid checks if the name policy kind is known
+func (k NamePolicyKind) IsValid() bool {
+	return k == NamePolicyKindReserved || k == NamePolicyKindServicePrefix
+}
+
+// NamePolicy is an administrator defined rule for the names of new users and organizations
+type NamePolicy struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Kind        NamePolicyKind     `xorm:"INDEX NOT NULL"`
+	Pattern     string             `xorm:"NOT NULL"`
+	Description string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+// ErrNamePolicyNotExist represents a "NamePolicyNotExist" kind of error.
+type ErrNamePolicyNotExist struct {
+	ID int64
+}
+
+// IsErrNamePolicyNotExist checks if an error is a ErrNamePolicyNotExist.
+func IsErrNamePolicyNotExist(err error) bool {
+	_, ok := err.(ErrNamePolicyNotExist)
+	return ok
+}
+
+func (err ErrNamePolicyNotExist) Error() string {
+	return fmt.Sprintf("name policy does not exist [id: %d]", err.ID)
+}
+
+// ErrServiceAccountPrefixRequired represents a "ServiceAccountPrefixRequired" kind of error.
+type ErrServiceAccountPrefixRequired struct {
+	Name     string
+	Prefixes []string
+}
+
+// IsErrServiceAccountPrefixRequired checks if an error is a ErrServiceAccountPrefixRequired.
+func IsErrServiceAccountPrefixRequired(err error) bool {
+	_, ok := err.(ErrServiceAccountPrefixRequired)
+	return ok
+}
+
+func (err ErrServiceAccountPrefixRequired) Error() string {
+	return fmt.Sprintf("name of a service account must start with one of the prefixes [name: %s, prefixes: %s]", err.Name, strings.Join(err.Prefixes, ", "))
+}
+
+// ValidateNamePolicy checks the pattern of a name policy
+func ValidateNamePolicy(p *NamePolicy) error {
+	if !p.Kind.IsValid() {
+		return fmt.Errorf("unknown name policy kind: %s", p.Kind)
+	}
+	if p.Pattern == "" {
+		return fmt.Errorf("empty name policy pattern")
+	}
+	if p.Kind == NamePolicyKindReserved {
+		if _, err := path.Match(p.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid name policy pattern %q: %w", p.Pattern, err)
+		}
+	} else if db.AlphaDashDotPattern.MatchString(p.Pattern) {
+		return fmt.Errorf("invalid service account prefix %q", p.Pattern)
+	}
+	return nil
+}
+
+// CreateNamePolicy stores a new name policy, the pattern is stored lower case
+func CreateNamePolicy(ctx context.Context, p *NamePolicy) error {
+	p.Pattern = strings.ToLower(p.Pattern)
+	if err := ValidateNamePolicy(p); err != nil {
+		return err
+	}
+	return db.Insert(ctx, p)
+}
+
+// GetNamePolicyByID returns the name policy with the id
+func GetNamePolicyByID(ctx context.Context, id int64) (*NamePolicy, error) {
+	p := new(NamePolicy)
+	has, err := db.GetEngine(ctx).ID(id).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrNamePolicyNotExist{id}
+	}
+	return p, nil
+}
+
+// GetNamePolicies returns all name policies
+func GetNamePolicies(ctx context.Context) ([]*NamePolicy, error) {
+	policies := make([]*NamePolicy, 0, 10)
+	return policies, db.GetEngine(ctx).OrderBy("id").Find(&policies)
+}
+
+// DeleteNamePolicy deletes a name policy
+func DeleteNamePolicy(ctx context.Context, id int64) error {
+	n, err := db.GetEngine(ctx).ID(id).Delete(&NamePolicy{})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNamePolicyNotExist{id}
+	}
+	return nil
+}
+
+// CheckNamePolicies checks the name of a new user or organization against the name policies.
+// Names matching a reserved pattern are rejected. If service account prefixes are defined,
+// the name of a service account must start with one of them and other names must not.
+func CheckNamePolicies(ctx context.Context, name string, isServiceAccount bool) error {
+	policies, err := GetNamePolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	name = strings.ToLower(name)
+	prefixes := make([]string, 0, len(policies))
+	hasPrefix := false
+	for _, p := range policies {
+		switch p.Kind {
+		case NamePolicyKindReserved:
+			if matched, _ := path.Match(p.Pattern, name); matched {
+				return db.ErrNamePatternNotAllowed{Pattern: p.Pattern}
+			}
+		case NamePolicyKindServicePrefix:
+			prefixes = append(prefixes, p.Pattern)
+			if strings.HasPrefix(name, p.Pattern) {
+				hasPrefix = true
+				if !isServiceAccount {
+					return db.ErrNamePatternNotAllowed{Pattern: p.Pattern + "*"}
+				}
+			}
+		}
+	}
+	if isServiceAccount && len(prefixes) > 0 && !hasPrefix {
+		return ErrServiceAccountPrefixRequired{Name: name, Prefixes: prefixes}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNamePolicies(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, user_model.CheckNamePolicies(db.DefaultContext, "anything", false))
+	assert.NoError(t, user_model.CheckNamePolicies(db.DefaultContext, "anything", true))
+
+	assert.NoError(t, user_model.CreateNamePolicy(db.DefaultContext, &user_model.NamePolicy{Kind: user_model.NamePolicyKindReserved, Pattern: "Admin*"}))
+	assert.NoError(t, user_model.CreateNamePolicy(db.DefaultContext, &user_model.NamePolicy{Kind: user_model.NamePolicyKindServicePrefix, Pattern: "bot-"}))
+	assert.Error(t, user_model.CreateNamePolicy(db.DefaultContext, &user_model.NamePolicy{Kind: user_model.NamePolicyKindReserved, Pattern: "[a"}))
+	assert.Error(t, user_model.CreateNamePolicy(db.DefaultContext, &user_model.NamePolicy{Kind: user_model.NamePolicyKindServicePrefix, Pattern: "bot/"}))
+
+	err := user_model.CheckNamePolicies(db.DefaultContext, "administrator", false)
+	assert.True(t, db.IsErrNamePatternNotAllowed(err))
+	err = user_model.CheckNamePolicies(db.DefaultContext, "bot-deploy", false)
+	assert.True(t, db.IsErrNamePatternNotAllowed(err))
+	err = user_model.CheckNamePolicies(db.DefaultContext, "deploy", true)
+	assert.True(t, user_model.IsErrServiceAccountPrefixRequired(err))
+
+	assert.NoError(t, user_model.CheckNamePolicies(db.DefaultContext, "deploy", false))
+	assert.NoError(t, user_model.CheckNamePolicies(db.DefaultContext, "Bot-Deploy", true))
+}
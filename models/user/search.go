@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"strings"
 
+	"code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/builder"
@@ -27,18 +29,26 @@ type SearchUserOptions struct {
 	Visible       []structs.VisibleType
 	Actor         *User // The user doing the search
 	SearchByEmail bool  // Search by email as well as username/full name
+	IncludeBots   bool  // Search service accounts as well as individual users
 
 	IsActive           util.OptionalBool
 	IsAdmin            util.OptionalBool
 	IsRestricted       util.OptionalBool
 	IsTwoFactorEnabled util.OptionalBool
 	IsProhibitLogin    util.OptionalBool
+	IsNeverLoggedIn    util.OptionalBool
+
+	LastLoginBefore timeutil.TimeStamp // only users which have logged in at least once
+	LoginTypes      []auth.Type
 
 	ExtraParamStrings map[string]string
 }
 
 func (opts *SearchUserOptions) toSearchQueryBase() *xorm.Session {
 	var cond builder.Cond = builder.Eq{"type": opts.Type}
+	if opts.IncludeBots && opts.Type == UserTypeIndividual {
+		cond = builder.In("type", UserTypeIndividual, UserTypeBot)
+	}
 	if len(opts.Keyword) > 0 {
 		lowerKeyword := strings.ToLower(opts.Keyword)
 		keywordCond := builder.Or(
@@ -79,6 +89,22 @@ func (opts *SearchUserOptions) toSearchQueryBase() *xorm.Session {
 		cond = cond.And(builder.Eq{"prohibit_login": opts.IsProhibitLogin.IsTrue()})
 	}
 
+	if !opts.IsNeverLoggedIn.IsNone() {
+		if opts.IsNeverLoggedIn.IsTrue() {
+			cond = cond.And(builder.Eq{"last_login_unix": 0})
+		} else {
+			cond = cond.And(builder.Gt{"last_login_unix": 0})
+		}
+	}
+
+	if opts.LastLoginBefore > 0 {
+		cond = cond.And(builder.Gt{"last_login_unix": 0}, builder.Lt{"last_login_unix": opts.LastLoginBefore})
+	}
+
+	if len(opts.LoginTypes) > 0 {
+		cond = cond.And(builder.In("login_type", opts.LoginTypes))
+	}
+
 	e := db.GetEngine(db.DefaultContext)
 	if opts.IsTwoFactorEnabled.IsNone() {
 		return e.Where(cond)
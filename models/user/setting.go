@@ -108,6 +108,16 @@ func SetUserSetting(userID int64, key, value string) error {
 	return upsertUserSettingValue(userID, key, value)
 }
 
+// IsSecurityKeysOnly returns true if the user only allows security keys as second factor,
+// disabling TOTP passcodes and scratch codes
+func IsSecurityKeysOnly(userID int64) (bool, error) {
+	val, err := GetUserSetting(userID, SettingsKeySecurityKeysOnly)
+	if err != nil {
+		return false, err
+	}
+	return val == "true", nil
+}
+
 func upsertUserSettingValue(userID int64, key, value string) error {
 	return db.WithTx(func(ctx context.Context) error {
 		e := db.GetEngine(ctx)
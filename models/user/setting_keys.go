@@ -9,6 +9,12 @@ const (
 	SettingsKeyHiddenCommentTypes = "issue.hidden_comment_types"
 	// SettingsKeyDiffWhitespaceBehavior is the setting key for whitespace behavior of diff
 	SettingsKeyDiffWhitespaceBehavior = "diff.whitespace_behaviour"
+	// SettingsKeyPackagesAnonymousRead is the setting key which allows anonymous users to read the packages of an owner
+	SettingsKeyPackagesAnonymousRead = "packages.anonymous_read"
+	// SettingsKeyPackagesImmutableVersions is the setting key which prevents changing or deleting the published package versions of an owner
+	SettingsKeyPackagesImmutableVersions = "packages.immutable_versions"
+	// SettingsKeySecurityKeysOnly is the setting key which only allows security keys as second factor of a user
+	SettingsKeySecurityKeysOnly = "security.keys_only"
 	// UserActivityPubPrivPem is user's private key
 	UserActivityPubPrivPem = "activitypub.priv_pem"
 	// UserActivityPubPubPem is user's public key
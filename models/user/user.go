@@ -46,6 +46,9 @@ const (
 
 	// UserTypeOrganization defines an organization
 	UserTypeOrganization
+
+	// UserTypeBot defines a service account, it can only authenticate with access tokens
+	UserTypeBot
 )
 
 const (
@@ -451,6 +454,11 @@ func (u *User) IsOrganization() bool {
 	return u.Type == UserTypeOrganization
 }
 
+// IsBot returns true if user is a service account.
+func (u *User) IsBot() bool {
+	return u.Type == UserTypeBot
+}
+
 // DisplayName returns full name if it's not empty,
 // returns username otherwise.
 func (u *User) DisplayName() string {
@@ -685,6 +693,17 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 		}
 	}
 
+	if u.IsBot() {
+		// service accounts don't use passwords, don't receive mails and can't create organizations
+		u.MustChangePassword = false
+		u.AllowCreateOrganization = false
+		u.EmailNotificationsPreference = EmailNotificationsDisabled
+	}
+
+	if err := CheckNamePolicies(db.DefaultContext, u.Name, u.IsBot()); err != nil {
+		return err
+	}
+
 	// validate data
 	if err := validateUser(u); err != nil {
 		return err
@@ -38,18 +38,26 @@ const (
 	HookEventIssueLabel                HookEventType = "issue_label"
 	HookEventIssueMilestone            HookEventType = "issue_milestone"
 	HookEventIssueComment              HookEventType = "issue_comment"
+	HookEventIssueCommentEdited        HookEventType = "issue_comment_edited"
+	HookEventIssueCommentDeleted       HookEventType = "issue_comment_deleted"
 	HookEventPullRequest               HookEventType = "pull_request"
 	HookEventPullRequestAssign         HookEventType = "pull_request_assign"
 	HookEventPullRequestLabel          HookEventType = "pull_request_label"
 	HookEventPullRequestMilestone      HookEventType = "pull_request_milestone"
 	HookEventPullRequestComment        HookEventType = "pull_request_comment"
+	HookEventPullRequestCommentEdited  HookEventType = "pull_request_comment_edited"
+	HookEventPullRequestCommentDeleted HookEventType = "pull_request_comment_deleted"
 	HookEventPullRequestReviewApproved HookEventType = "pull_request_review_approved"
 	HookEventPullRequestReviewRejected HookEventType = "pull_request_review_rejected"
 	HookEventPullRequestReviewComment  HookEventType = "pull_request_review_comment"
 	HookEventPullRequestSync           HookEventType = "pull_request_sync"
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
+	HookEventReleasePublished          HookEventType = "release_published"
+	HookEventReleaseEdited             HookEventType = "release_edited"
 	HookEventPackage                   HookEventType = "package"
+	HookEventReaction                  HookEventType = "reaction"
+	HookEventDigest                    HookEventType = "digest"
 )
 
 // Event returns the HookEventType as an event string
@@ -68,7 +76,8 @@ func (h HookEventType) Event() string {
 	case HookEventPullRequest, HookEventPullRequestAssign, HookEventPullRequestLabel, HookEventPullRequestMilestone,
 		HookEventPullRequestSync:
 		return "pull_request"
-	case HookEventIssueComment, HookEventPullRequestComment:
+	case HookEventIssueComment, HookEventIssueCommentEdited, HookEventIssueCommentDeleted,
+		HookEventPullRequestComment, HookEventPullRequestCommentEdited, HookEventPullRequestCommentDeleted:
 		return "issue_comment"
 	case HookEventPullRequestReviewApproved:
 		return "pull_request_approved"
@@ -78,8 +87,12 @@ func (h HookEventType) Event() string {
 		return "pull_request_comment"
 	case HookEventRepository:
 		return "repository"
-	case HookEventRelease:
+	case HookEventRelease, HookEventReleasePublished, HookEventReleaseEdited:
 		return "release"
+	case HookEventReaction:
+		return "reaction"
+	case HookEventDigest:
+		return "digest"
 	}
 	return ""
 }
@@ -135,6 +135,8 @@ type HookEvents struct {
 	Repository           bool `json:"repository"`
 	Release              bool `json:"release"`
 	Package              bool `json:"package"`
+	Reaction             bool `json:"reaction"`
+	Digest               bool `json:"digest"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -192,6 +194,12 @@ type Webhook struct {
 	Meta            string     `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus      HookStatus // Last delivery status
 
+	// IsDefaultWebhook marks an organization webhook which is copied onto new repositories of the organization
+	// instead of being delivered for all of them
+	IsDefaultWebhook bool `xorm:"NOT NULL DEFAULT false"`
+	// DefaultWebhookID is the default webhook this repository webhook was copied from
+	DefaultWebhookID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -346,6 +354,18 @@ func (w *Webhook) HasPackageEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Package)
 }
 
+// HasReactionEvent returns true if hook enabled reaction event.
+func (w *Webhook) HasReactionEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Reaction)
+}
+
+// HasDigestEvent returns true if hook enabled the weekly digest event.
+// The digest summarizes the other events, so it is not included in SendEverything.
+func (w *Webhook) HasDigestEvent() bool {
+	return w.ChooseEvents && w.HookEvents.Digest
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -364,18 +384,26 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasIssuesLabelEvent, HookEventIssueLabel},
 		{w.HasIssuesMilestoneEvent, HookEventIssueMilestone},
 		{w.HasIssueCommentEvent, HookEventIssueComment},
+		{w.HasIssueCommentEvent, HookEventIssueCommentEdited},
+		{w.HasIssueCommentEvent, HookEventIssueCommentDeleted},
 		{w.HasPullRequestEvent, HookEventPullRequest},
 		{w.HasPullRequestAssignEvent, HookEventPullRequestAssign},
 		{w.HasPullRequestLabelEvent, HookEventPullRequestLabel},
 		{w.HasPullRequestMilestoneEvent, HookEventPullRequestMilestone},
 		{w.HasPullRequestCommentEvent, HookEventPullRequestComment},
+		{w.HasPullRequestCommentEvent, HookEventPullRequestCommentEdited},
+		{w.HasPullRequestCommentEvent, HookEventPullRequestCommentDeleted},
 		{w.HasPullRequestApprovedEvent, HookEventPullRequestReviewApproved},
 		{w.HasPullRequestRejectedEvent, HookEventPullRequestReviewRejected},
 		{w.HasPullRequestCommentEvent, HookEventPullRequestReviewComment},
 		{w.HasPullRequestSyncEvent, HookEventPullRequestSync},
 		{w.HasRepositoryEvent, HookEventRepository},
 		{w.HasReleaseEvent, HookEventRelease},
+		{w.HasReleaseEvent, HookEventReleasePublished},
+		{w.HasReleaseEvent, HookEventReleaseEdited},
 		{w.HasPackageEvent, HookEventPackage},
+		{w.HasReactionEvent, HookEventReaction},
+		{w.HasDigestEvent, HookEventDigest},
 	}
 }
 
@@ -447,9 +475,10 @@ func GetWebhookByOrgID(orgID, id int64) (*Webhook, error) {
 // ListWebhookOptions are options to filter webhooks on ListWebhooksByOpts
 type ListWebhookOptions struct {
 	db.ListOptions
-	RepoID   int64
-	OrgID    int64
-	IsActive util.OptionalBool
+	RepoID    int64
+	OrgID     int64
+	IsActive  util.OptionalBool
+	IsDefault util.OptionalBool
 }
 
 func (opts *ListWebhookOptions) toCond() builder.Cond {
@@ -463,9 +492,31 @@ func (opts *ListWebhookOptions) toCond() builder.Cond {
 	if !opts.IsActive.IsNone() {
 		cond = cond.And(builder.Eq{"webhook.is_active": opts.IsActive.IsTrue()})
 	}
+	if !opts.IsDefault.IsNone() {
+		cond = cond.And(builder.Eq{"webhook.is_default_webhook": opts.IsDefault.IsTrue()})
+	}
 	return cond
 }
 
+// FindDigestWebhooks returns the active repository and organization webhooks which enabled the digest event
+func FindDigestWebhooks(ctx context.Context) ([]*Webhook, error) {
+	ws := make([]*Webhook, 0, 10)
+	if err := db.GetEngine(ctx).
+		Where(builder.Eq{"is_active": true, "is_default_webhook": false, "is_system_webhook": false}).
+		And(builder.Like{"events", `"digest":true`}).
+		Find(&ws); err != nil {
+		return nil, err
+	}
+
+	hooks := make([]*Webhook, 0, len(ws))
+	for _, w := range ws {
+		if w.HasDigestEvent() {
+			hooks = append(hooks, w)
+		}
+	}
+	return hooks, nil
+}
+
 // ListWebhooksByOpts return webhooks based on options
 func ListWebhooksByOpts(ctx context.Context, opts *ListWebhookOptions) ([]*Webhook, error) {
 	sess := db.GetEngine(ctx).Where(opts.toCond())
@@ -495,6 +546,14 @@ func GetDefaultWebhooks(ctx context.Context) ([]*Webhook, error) {
 		Find(&webhooks)
 }
 
+// GetOrgDefaultWebhooks returns the default webhooks of an organization.
+func GetOrgDefaultWebhooks(ctx context.Context, orgID int64) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 5)
+	return webhooks, db.GetEngine(ctx).
+		Where("repo_id=? AND org_id=? AND is_default_webhook=?", 0, orgID, true).
+		Find(&webhooks)
+}
+
 // GetSystemOrDefaultWebhook returns admin system or default webhook by given ID.
 func GetSystemOrDefaultWebhook(id int64) (*Webhook, error) {
 	webhook := &Webhook{ID: id}
@@ -594,19 +653,42 @@ func DeleteDefaultSystemWebhook(id int64) error {
 	return committer.Commit()
 }
 
-// CopyDefaultWebhooksToRepo creates copies of the default webhooks in a new repo
-func CopyDefaultWebhooksToRepo(ctx context.Context, repoID int64) error {
+// CopyDefaultWebhooksToRepo creates copies of the admin-default webhooks and the default webhooks
+// of the owner organization in a new repo
+func CopyDefaultWebhooksToRepo(ctx context.Context, ownerID, repoID int64) error {
 	ws, err := GetDefaultWebhooks(ctx)
 	if err != nil {
 		return fmt.Errorf("GetDefaultWebhooks: %v", err)
 	}
+	orgWebhooks, err := GetOrgDefaultWebhooks(ctx, ownerID)
+	if err != nil {
+		return fmt.Errorf("GetOrgDefaultWebhooks: %v", err)
+	}
+	ws = append(ws, orgWebhooks...)
 
 	for _, w := range ws {
-		w.ID = 0
-		w.RepoID = repoID
-		if err := CreateWebhook(ctx, w); err != nil {
+		if err := CopyDefaultWebhookToRepo(ctx, w, repoID); err != nil {
 			return fmt.Errorf("CreateWebhook: %v", err)
 		}
 	}
 	return nil
 }
+
+// CopyDefaultWebhookToRepo creates a copy of the default webhook in the repo
+func CopyDefaultWebhookToRepo(ctx context.Context, w *Webhook, repoID int64) error {
+	hook := *w
+	hook.ID = 0
+	hook.RepoID = repoID
+	hook.OrgID = 0
+	hook.IsDefaultWebhook = false
+	hook.DefaultWebhookID = w.ID
+	hook.LastStatus = HookStatusNone
+	return CreateWebhook(ctx, &hook)
+}
+
+// HasWebhookCopy returns true if the repo has a copy of the default webhook
+func HasWebhookCopy(ctx context.Context, defaultWebhookID, repoID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("repo_id=? AND default_webhook_id=?", repoID, defaultWebhookID).
+		Exist(&Webhook{})
+}
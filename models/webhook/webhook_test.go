@@ -69,10 +69,12 @@ func TestWebhook_EventsArray(t *testing.T) {
 	assert.Equal(t, []string{
 		"create", "delete", "fork", "push",
 		"issues", "issue_assign", "issue_label", "issue_milestone", "issue_comment",
+		"issue_comment_edited", "issue_comment_deleted",
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
-		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
+		"pull_request_comment", "pull_request_comment_edited", "pull_request_comment_deleted",
+		"pull_request_review_approved", "pull_request_review_rejected",
 		"pull_request_review_comment", "pull_request_sync", "repository", "release",
-		"package",
+		"release_published", "release_edited", "package", "reaction",
 	},
 		(&Webhook{
 			HookEvent: &HookEvent{SendEverything: true},
@@ -84,6 +86,42 @@ func TestWebhook_EventsArray(t *testing.T) {
 			HookEvent: &HookEvent{PushOnly: true},
 		}).EventsArray(),
 	)
+
+	assert.Equal(t, []string{"release", "digest"},
+		(&Webhook{
+			HookEvent: &HookEvent{ChooseEvents: true, HookEvents: HookEvents{Release: true, Digest: true}},
+		}).EventsArray(),
+	)
+}
+
+func TestFindDigestWebhooks(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	hooks, err := FindDigestWebhooks(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	hook := &Webhook{
+		RepoID:      1,
+		URL:         "www.example.com/digest",
+		ContentType: ContentTypeJSON,
+		IsActive:    true,
+		Events:      `{"push_only":false,"send_everything":false,"choose_events":true,"events":{"digest":true}}`,
+	}
+	assert.NoError(t, CreateWebhook(db.DefaultContext, hook))
+	assert.NoError(t, CreateWebhook(db.DefaultContext, &Webhook{
+		RepoID:      1,
+		URL:         "www.example.com/everything",
+		ContentType: ContentTypeJSON,
+		IsActive:    true,
+		Events:      `{"push_only":false,"send_everything":true,"choose_events":false,"events":{"digest":true}}`,
+	}))
+
+	hooks, err = FindDigestWebhooks(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, hook.ID, hooks[0].ID)
+	}
 }
 
 func TestCreateWebhook(t *testing.T) {
@@ -160,6 +198,41 @@ func TestGetWebhooksByOrgID(t *testing.T) {
 	}
 }
 
+func TestCopyDefaultWebhooksToRepo(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	adminDefault := &Webhook{URL: "www.example.com/admin-default", Events: `{"push_only":true}`, IsActive: true}
+	orgDefault := &Webhook{OrgID: 3, URL: "www.example.com/org-default", Events: `{"push_only":true}`, IsActive: true, IsDefaultWebhook: true}
+	otherOrgDefault := &Webhook{OrgID: 7, URL: "www.example.com/other-default", Events: `{"push_only":true}`, IsActive: true, IsDefaultWebhook: true}
+	for _, w := range []*Webhook{adminDefault, orgDefault, otherOrgDefault} {
+		assert.NoError(t, CreateWebhook(db.DefaultContext, w))
+	}
+
+	hooks, err := GetOrgDefaultWebhooks(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, orgDefault.ID, hooks[0].ID)
+	}
+	hooks, err = ListWebhooksByOpts(db.DefaultContext, &ListWebhookOptions{OrgID: 3, IsDefault: util.OptionalBoolFalse})
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.Equal(t, int64(3), hooks[0].ID)
+	}
+
+	assert.NoError(t, CopyDefaultWebhooksToRepo(db.DefaultContext, 3, 3))
+	unittest.AssertExistsAndLoadBean(t, &Webhook{RepoID: 3, DefaultWebhookID: adminDefault.ID, URL: adminDefault.URL})
+	copied := unittest.AssertExistsAndLoadBean(t, &Webhook{RepoID: 3, DefaultWebhookID: orgDefault.ID, URL: orgDefault.URL})
+	assert.EqualValues(t, 0, copied.OrgID)
+	assert.False(t, copied.IsDefaultWebhook)
+	unittest.AssertNotExistsBean(t, &Webhook{RepoID: 3, DefaultWebhookID: otherOrgDefault.ID})
+
+	has, err := HasWebhookCopy(db.DefaultContext, orgDefault.ID, 3)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = HasWebhookCopy(db.DefaultContext, orgDefault.ID, 5)
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
 func TestUpdateWebhook(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	hook := unittest.AssertExistsAndLoadBean(t, &Webhook{ID: 2})
@@ -14,6 +14,8 @@ import (
 	"strings"
 	"time"
 
+	"code.gitea.io/gitea/models/db"
+	federation_model "code.gitea.io/gitea/models/federation"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/proxy"
 	"code.gitea.io/gitea/modules/setting"
@@ -119,6 +121,14 @@ func (c *Client) Post(b []byte, to string) (resp *http.Response, err error) {
 	if req, err = c.NewRequest(b, to); err != nil {
 		return
 	}
+	allowed, err := federation_model.IsInstanceAllowed(db.DefaultContext, req.URL.Host)
+	if err != nil {
+		return
+	}
+	if !allowed {
+		err = federation_model.ErrInstanceNotAllowed{Host: req.URL.Host}
+		return
+	}
 	resp, err = c.client.Do(req)
 	return resp, err
 }
@@ -12,6 +12,8 @@ import (
 	"regexp"
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
+	federation_model "code.gitea.io/gitea/models/federation"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
@@ -47,3 +49,21 @@ func TestActivityPubSignedPost(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, string(body))
 }
+
+func TestActivityPubSignedPostBlocked(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	c, err := NewClient(user, "https://example.com/pubID")
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "request to a blocked instance")
+	}))
+	defer srv.Close()
+
+	_, _, err = federation_model.SetInstancePolicy(db.DefaultContext, "127.0.0.1", federation_model.InstancePolicyBlock, "")
+	assert.NoError(t, err)
+
+	_, err = c.Post([]byte("BODY"), srv.URL)
+	assert.True(t, federation_model.IsErrInstanceNotAllowed(err))
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// proxyCachePrefix is the directory of the avatar storage holding the cached external avatars
+const proxyCachePrefix = "proxy/"
+
+var (
+	proxyClient     *http.Client
+	proxyClientOnce sync.Once
+)
+
+// getProxyClient returns a client which only connects to the allowed hosts, the avatar url can be
+// discovered via the DNS records of the domain of an email address and is therefore not trusted
+func getProxyClient() *http.Client {
+	proxyClientOnce.Do(func() {
+		allowList := setting.Avatar.ProxyAllowedHosts
+		if allowList == "" {
+			allowList = hostmatcher.MatchBuiltinExternal
+		}
+
+		proxyClient = &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				Proxy:       proxy.Proxy(),
+				DialContext: hostmatcher.NewDialContext("avatar", hostmatcher.ParseHostMatchList("picture.AVATAR_PROXY_ALLOWED_HOST_LIST", allowList), nil),
+			},
+		}
+	})
+	return proxyClient
+}
+
+// OpenProxied returns the cached copy of an external avatar like a Gravatar, so the browsers of the users don't contact the external service.
+// The avatar is fetched again if the copy is older than the cache TTL, a stale copy is returned if the external service fails.
+func OpenProxied(ctx context.Context, avatarURL string) (storage.Object, error) {
+	cachePath := fmt.Sprintf("%s%x", proxyCachePrefix, sha256.Sum256([]byte(avatarURL)))
+
+	if fi, err := storage.Avatars.Stat(cachePath); err == nil && time.Since(fi.ModTime()) < setting.Avatar.ProxyCacheTTL {
+		return storage.Avatars.Open(cachePath)
+	}
+
+	if err := fetchProxied(ctx, avatarURL, cachePath); err != nil {
+		obj, openErr := storage.Avatars.Open(cachePath)
+		if openErr != nil {
+			return nil, err
+		}
+		log.Warn("Unable to refresh the proxied avatar %s, serving the cached copy: %v", avatarURL, err)
+		return obj, nil
+	}
+	return storage.Avatars.Open(cachePath)
+}
+
+func fetchProxied(ctx context.Context, avatarURL, cachePath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, avatarURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := getProxyClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", resp.StatusCode, avatarURL)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, setting.Avatar.MaxFileSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > setting.Avatar.MaxFileSize {
+		return fmt.Errorf("avatar %s is larger than %d bytes", avatarURL, setting.Avatar.MaxFileSize)
+	}
+	// only images are served from the instance
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("avatar %s is no image: %w", avatarURL, err)
+	}
+
+	_, err = storage.Avatars.Save(cachePath, bytes.NewReader(data), int64(len(data)))
+	return err
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package avatar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenProxied(t *testing.T) {
+	png, err := os.ReadFile("testdata/avatar.png")
+	assert.NoError(t, err)
+
+	requests := 0
+	failing := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case failing:
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/text":
+			_, _ = w.Write([]byte("no image"))
+		default:
+			_, _ = w.Write(png)
+		}
+	}))
+	defer srv.Close()
+
+	oldAvatars, oldTTL, oldMaxFileSize, oldAllowedHosts := storage.Avatars, setting.Avatar.ProxyCacheTTL, setting.Avatar.MaxFileSize, setting.Avatar.ProxyAllowedHosts
+	defer func() {
+		storage.Avatars, setting.Avatar.ProxyCacheTTL, setting.Avatar.MaxFileSize, setting.Avatar.ProxyAllowedHosts = oldAvatars, oldTTL, oldMaxFileSize, oldAllowedHosts
+	}()
+	storage.Avatars, err = storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+	setting.Avatar.ProxyCacheTTL = time.Hour
+	setting.Avatar.MaxFileSize = 1048576
+	setting.Avatar.ProxyAllowedHosts = "loopback"
+	// the client is built once with the allowed hosts
+	proxyClientOnce = sync.Once{}
+
+	read := func(avatarURL string) ([]byte, error) {
+		obj, err := OpenProxied(context.Background(), avatarURL)
+		if err != nil {
+			return nil, err
+		}
+		defer obj.Close()
+		return io.ReadAll(obj)
+	}
+
+	data, err := read(srv.URL + "/avatar")
+	assert.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, 1, requests)
+
+	// the cached copy is served
+	data, err = read(srv.URL + "/avatar")
+	assert.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, 1, requests)
+
+	// a stale copy is served if the external service fails
+	setting.Avatar.ProxyCacheTTL = 0
+	failing = true
+	data, err = read(srv.URL + "/avatar")
+	assert.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, 2, requests)
+
+	_, err = read(srv.URL + "/other")
+	assert.Error(t, err)
+
+	failing = false
+	_, err = read(srv.URL + "/text")
+	assert.Error(t, err)
+}
+
+func TestOpenProxiedLoopbackDenied(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	oldAvatars, oldAllowedHosts := storage.Avatars, setting.Avatar.ProxyAllowedHosts
+	defer func() {
+		storage.Avatars, setting.Avatar.ProxyAllowedHosts = oldAvatars, oldAllowedHosts
+	}()
+	var err error
+	storage.Avatars, err = storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+	setting.Avatar.ProxyAllowedHosts = ""
+	proxyClientOnce = sync.Once{}
+
+	// only external hosts are allowed by default
+	_, err = OpenProxied(context.Background(), srv.URL+"/avatar")
+	assert.Error(t, err)
+	assert.Zero(t, requests)
+}
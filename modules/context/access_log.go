@@ -11,8 +11,11 @@ import (
 	"net/http"
 	"time"
 
+	"code.gitea.io/gitea/modules/diagnostics"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type routerLoggerOptions struct {
@@ -60,3 +63,43 @@ func AccessLogger() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequestRecorder returns a middleware to keep the served requests for the diagnostics if the request log is enabled
+func RequestRecorder() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if !setting.Diagnostics.EnableRequestLog {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			start := time.Now()
+			// share the identity with the access logger, the innermost one is filled by the contexts
+			identity, _ := req.Context().Value(signedUserNameStringPointerKey).(*string)
+			if identity == nil {
+				identity = new(string)
+				req = req.WithContext(context.WithValue(req.Context(), signedUserNameStringPointerKey, identity))
+			}
+
+			next.ServeHTTP(w, req)
+
+			r := &diagnostics.Request{
+				Time:     start,
+				Method:   req.Method,
+				Path:     req.URL.Path,
+				Duration: time.Since(start),
+			}
+			if rctx := chi.RouteContext(req.Context()); rctx != nil {
+				r.Route = rctx.RoutePattern()
+			}
+			if *identity != "-" {
+				r.User = *identity
+			}
+			if rw, ok := w.(ResponseWriter); ok {
+				r.Status = rw.Status()
+				r.Size = int64(rw.Size())
+			}
+			diagnostics.RecordRequest(r)
+		})
+	}
+}
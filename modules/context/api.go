@@ -14,6 +14,7 @@ import (
 
 	"code.gitea.io/gitea/models/auth"
 	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/httpcache"
@@ -205,6 +206,16 @@ func (ctx *APIContext) CheckForOTP() {
 		ctx.Context.Error(http.StatusInternalServerError)
 		return
 	}
+	// TOTP passcodes are disabled if only security keys are allowed
+	keysOnly, err := user_model.IsSecurityKeysOnly(ctx.Context.Doer.ID)
+	if err != nil {
+		ctx.Context.Error(http.StatusInternalServerError)
+		return
+	}
+	if keysOnly {
+		ctx.Context.Error(http.StatusUnauthorized)
+		return
+	}
 	ok, err := twofa.ValidateTOTP(otpHeader)
 	if err != nil {
 		ctx.Context.Error(http.StatusInternalServerError)
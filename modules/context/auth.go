@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web/middleware"
@@ -167,13 +168,19 @@ func ToggleAPI(options *ToggleOptions) func(ctx *APIContext) {
 					ctx.InternalServerError(err)
 					return
 				}
+				// TOTP passcodes are disabled if only security keys are allowed
+				keysOnly, err := user_model.IsSecurityKeysOnly(ctx.Doer.ID)
+				if err != nil {
+					ctx.InternalServerError(err)
+					return
+				}
 				otpHeader := ctx.Req.Header.Get("X-Gitea-OTP")
 				ok, err := twofa.ValidateTOTP(otpHeader)
 				if err != nil {
 					ctx.InternalServerError(err)
 					return
 				}
-				if !ok {
+				if !ok || keysOnly {
 					ctx.JSON(http.StatusForbidden, map[string]string{
 						"message": "Only signed in user is allowed to call APIs.",
 					})
@@ -9,11 +9,14 @@ import (
 	"fmt"
 	"net/http"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/templates"
 )
@@ -23,6 +26,10 @@ type Package struct {
 	Owner      *user_model.User
 	AccessMode perm.AccessMode
 	Descriptor *packages_model.PackageDescriptor
+
+	doer            *user_model.User
+	ownerAccessMode perm.AccessMode // access granted by the owner, before the settings of a single package are applied
+	maxAccessMode   perm.AccessMode // limit of a package scoped access token
 }
 
 // PackageAssignment returns a middleware to handle Context.Package assignment
@@ -54,20 +61,26 @@ func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
 		Owner: ctx.ContextUser,
 	}
 
+	// The container registry uses the ghost user for anonymous access, treat it like an anonymous user
+	doer := ctx.Doer
+	if doer != nil && doer.IsGhost() {
+		doer = nil
+	}
+
 	if ctx.Package.Owner.IsOrganization() {
 		org := organization.OrgFromUser(ctx.Package.Owner)
 
 		// 1. Get user max authorize level for the org (may be none, if user is not member of the org)
-		if ctx.Doer != nil {
+		if doer != nil {
 			var err error
-			ctx.Package.AccessMode, err = org.GetOrgUserMaxAuthorizeLevel(ctx.Doer.ID)
+			ctx.Package.AccessMode, err = org.GetOrgUserMaxAuthorizeLevel(doer.ID)
 			if err != nil {
 				errCb(http.StatusInternalServerError, "GetOrgUserMaxAuthorizeLevel", err)
 				return
 			}
 			// If access mode is less than write check every team for more permissions
 			if ctx.Package.AccessMode < perm.AccessModeWrite {
-				teams, err := organization.GetUserOrgTeams(ctx, org.ID, ctx.Doer.ID)
+				teams, err := organization.GetUserOrgTeams(ctx, org.ID, doer.ID)
 				if err != nil {
 					errCb(http.StatusInternalServerError, "GetUserOrgTeams", err)
 					return
@@ -81,13 +94,13 @@ func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
 			}
 		}
 		// 2. If authorize level is none, check if org is visible to user
-		if ctx.Package.AccessMode == perm.AccessModeNone && organization.HasOrgOrUserVisible(ctx, ctx.Package.Owner, ctx.Doer) {
+		if ctx.Package.AccessMode == perm.AccessModeNone && organization.HasOrgOrUserVisible(ctx, ctx.Package.Owner, doer) {
 			ctx.Package.AccessMode = perm.AccessModeRead
 		}
 	} else {
-		if ctx.Doer != nil && !ctx.Doer.IsGhost() {
+		if doer != nil {
 			// 1. Check if user is package owner
-			if ctx.Doer.ID == ctx.Package.Owner.ID {
+			if doer.ID == ctx.Package.Owner.ID {
 				ctx.Package.AccessMode = perm.AccessModeOwner
 			} else if ctx.Package.Owner.Visibility == structs.VisibleTypePublic || ctx.Package.Owner.Visibility == structs.VisibleTypeLimited { // 2. Check if package owner is public or limited
 				ctx.Package.AccessMode = perm.AccessModeRead
@@ -97,6 +110,43 @@ func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
 		}
 	}
 
+	// Limit the access if the request was authenticated with a package scoped access token
+	ctx.Package.maxAccessMode = perm.AccessModeOwner
+	if scope, ownerID := ctx.AccessTokenScope(); scope.IsPackageScope() {
+		// the site admin bypass of the access checks doesn't apply to scoped tokens
+		if ctx.Doer != nil && ctx.Doer.IsAdmin {
+			ctx.Package.AccessMode = perm.AccessModeOwner
+		}
+
+		ctx.Package.maxAccessMode = perm.AccessModeWrite
+		if scope == auth_model.AccessTokenScopePackageRead {
+			ctx.Package.maxAccessMode = perm.AccessModeRead
+		}
+		if ownerID != 0 && ownerID != ctx.Package.Owner.ID {
+			ctx.Package.maxAccessMode = perm.AccessModeNone
+			if ctx.Package.Owner.Visibility == structs.VisibleTypePublic {
+				ctx.Package.maxAccessMode = perm.AccessModeRead
+			}
+		}
+	}
+	// If signing in is required to view anything, anonymous users can only read the packages of public owners which allow it
+	if doer == nil && setting.Service.RequireSignInView {
+		allowed, err := IsPackageAnonymousReadAllowed(ctx.Package.Owner)
+		if err != nil {
+			errCb(http.StatusInternalServerError, "IsPackageAnonymousReadAllowed", err)
+			return
+		}
+		if !allowed {
+			ctx.Package.AccessMode = perm.AccessModeNone
+			ctx.Package.maxAccessMode = perm.AccessModeNone
+		}
+	}
+	ctx.Package.doer = doer
+	ctx.Package.ownerAccessMode = ctx.Package.AccessMode
+	if ctx.Package.AccessMode > ctx.Package.maxAccessMode {
+		ctx.Package.AccessMode = ctx.Package.maxAccessMode
+	}
+
 	packageType := ctx.Params("type")
 	name := ctx.Params("name")
 	version := ctx.Params("version")
@@ -116,7 +166,88 @@ func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
 			errCb(http.StatusInternalServerError, "GetPackageDescriptor", err)
 			return
 		}
+
+		if err := ctx.Package.AssignPackageAccess(ctx, ctx.Package.Descriptor.Package); err != nil {
+			errCb(http.StatusInternalServerError, "AssignPackageAccess", err)
+			return
+		}
+	} else if packageType != "" && name != "" {
+		p, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.Type(packageType), name)
+		if err != nil {
+			if err != packages_model.ErrPackageNotExist {
+				errCb(http.StatusInternalServerError, "GetPackageByName", err)
+			}
+			return
+		}
+
+		if err := ctx.Package.AssignPackageAccess(ctx, p); err != nil {
+			errCb(http.StatusInternalServerError, "AssignPackageAccess", err)
+			return
+		}
+	}
+}
+
+// IsPackageAnonymousReadAllowed returns true if anonymous users can read the packages of the owner
+// although signing in is required to view anything. Only public owners can allow it.
+func IsPackageAnonymousReadAllowed(owner *user_model.User) (bool, error) {
+	if !owner.Visibility.IsPublic() {
+		return false, nil
+	}
+	allowed, err := user_model.GetUserSetting(owner.ID, user_model.SettingsKeyPackagesAnonymousRead)
+	if err != nil {
+		return false, err
+	}
+	return allowed == "true", nil
+}
+
+// CanSeePrivatePackages returns true if the doer can see the packages of the owner which have a private visibility
+func (p *Package) CanSeePrivatePackages() bool {
+	return p.ownerAccessMode >= perm.AccessModeWrite && p.maxAccessMode >= perm.AccessModeRead
+}
+
+// AssignPackageAccess replaces the access mode granted by the owner with the access mode
+// resulting from the visibility and the team access settings of the package.
+func (p *Package) AssignPackageAccess(ctx gocontext.Context, pkg *packages_model.Package) error {
+	mode := p.ownerAccessMode
+	switch pkg.Visibility {
+	case packages_model.VisibilityPublic:
+		if mode < perm.AccessModeRead {
+			mode = perm.AccessModeRead
+		}
+	case packages_model.VisibilityPrivate:
+		if mode < perm.AccessModeWrite {
+			mode = perm.AccessModeNone
+		}
+	}
+
+	if p.doer != nil {
+		teamMode, err := packages_model.GetUserPackageAccessMode(ctx, pkg.ID, p.doer.ID)
+		if err != nil {
+			return err
+		}
+		if mode < teamMode {
+			mode = teamMode
+		}
 	}
+
+	if mode > p.maxAccessMode {
+		mode = p.maxAccessMode
+	}
+	p.AccessMode = mode
+	return nil
+}
+
+// AccessTokenScope returns the scope and the owner restriction of the access token used to authenticate the request
+func (ctx *Context) AccessTokenScope() (auth_model.AccessTokenScope, int64) {
+	scope, _ := ctx.Data["ApiTokenScope"].(auth_model.AccessTokenScope)
+	ownerID, _ := ctx.Data["ApiTokenOwnerID"].(int64)
+	return scope, ownerID
+}
+
+// IsAccessTokenAuthenticated returns true if the request was authenticated with an access token instead of a password or a session
+func (ctx *Context) IsAccessTokenAuthenticated() bool {
+	_, ok := ctx.Data["ApiTokenScope"]
+	return ok
 }
 
 // PackageContexter initializes a package context for a request.
@@ -131,6 +262,9 @@ func PackageContexter(ctx gocontext.Context) func(next http.Handler) http.Handle
 			}
 			defer ctx.Close()
 
+			// the user agent is used to classify the client in the download statistics,
+			// both the user agent and the client address are recorded in the audit log
+			req = req.WithContext(packages_module.WithRequest(req.Context(), req))
 			ctx.Req = WithContext(req, &ctx)
 
 			next.ServeHTTP(ctx.Resp, ctx.Req)
@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	repo_module "code.gitea.io/gitea/modules/repository"
@@ -49,6 +50,16 @@ var IssueTemplateDirCandidates = []string{
 	".gitlab/issue_template",
 }
 
+// PullRequestTemplateDirCandidates pull request templates directory
+var PullRequestTemplateDirCandidates = []string{
+	"PULL_REQUEST_TEMPLATE",
+	"pull_request_template",
+	".gitea/PULL_REQUEST_TEMPLATE",
+	".gitea/pull_request_template",
+	".github/PULL_REQUEST_TEMPLATE",
+	".github/pull_request_template",
+}
+
 // PullRequest contains information to make a pull request
 type PullRequest struct {
 	BaseRepo       *repo_model.Repository
@@ -1003,7 +1014,7 @@ func RepoRefByType(refType RepoRefType, ignoreNotExistErr ...bool) func(*Context
 			return
 		}
 		ctx.Data["CommitsCount"] = ctx.Repo.CommitsCount
-		ctx.Repo.GitRepo.LastCommitCache = git.NewLastCommitCache(ctx.Repo.CommitsCount, ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, cache.GetCache())
+		ctx.Repo.GitRepo.LastCommitCache = git.NewLastCommitCache(ctx.Repo.CommitsCount, ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, git_model.GetLastCommitStore(ctx.Repo.Repository.ID))
 
 		return cancel
 	}
@@ -1031,27 +1042,139 @@ func UnitTypes() func(ctx *Context) {
 		ctx.Data["UnitTypeExternalTracker"] = unit_model.TypeExternalTracker
 		ctx.Data["UnitTypeProjects"] = unit_model.TypeProjects
 		ctx.Data["UnitTypePackages"] = unit_model.TypePackages
+		ctx.Data["UnitTypePages"] = unit_model.TypePages
 	}
 }
 
 // IssueTemplatesFromDefaultBranch checks for issue templates in the repo's default branch
+// and sorts them according to the issue config
 func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
+	issueTemplates, config := ctx.issueTemplatesAndConfig()
+	issue_template.SortTemplates(issueTemplates, config)
+	return issueTemplates
+}
+
+// IssueConfigFromDefaultBranch returns the issue template chooser configuration of the repo's default branch
+func (ctx *Context) IssueConfigFromDefaultBranch() api.IssueConfig {
+	_, config := ctx.issueTemplatesAndConfig()
+	return config
+}
+
+// issueTemplatesAndConfig returns the issue templates and the issue config of the repo's default branch.
+// If the repo has neither, they are inherited from the meta repository of the owner.
+func (ctx *Context) issueTemplatesAndConfig() ([]api.IssueTemplate, api.IssueConfig) {
+	if ctx.loadDefaultBranchCommit() {
+		issueTemplates := issueTemplatesFromCommit(ctx.Repo.Commit)
+		config, has := issueConfigFromCommit(ctx.Repo.Commit)
+		if len(issueTemplates) > 0 || has {
+			return issueTemplates, config
+		}
+	}
+
 	var issueTemplates []api.IssueTemplate
+	config := issue_template.DefaultConfig()
+	ctx.withMetaRepoCommit(func(_ *repo_model.Repository, commit *git.Commit) {
+		issueTemplates = issueTemplatesFromCommit(commit)
+		config, _ = issueConfigFromCommit(commit)
+	})
+	return issueTemplates, config
+}
+
+// issueConfigFromCommit returns the issue template chooser configuration of the commit and if a config file exists
+func issueConfigFromCommit(commit *git.Commit) (api.IssueConfig, bool) {
+	for _, dirName := range IssueTemplateDirCandidates {
+		for _, fileName := range issue_template.ConfigFileCandidates {
+			entry, err := commit.GetTreeEntryByPath(path.Join(dirName, fileName))
+			if err != nil {
+				continue
+			}
+			if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+				log.Debug("Issue config is too large: %s", entry.Name())
+				return issue_template.DefaultConfig(), true
+			}
+			r, err := entry.Blob().DataAsync()
+			if err != nil {
+				log.Debug("DataAsync: %v", err)
+				return issue_template.DefaultConfig(), true
+			}
+			data, err := io.ReadAll(r)
+			_ = r.Close()
+			if err != nil {
+				log.Debug("ReadAll: %v", err)
+				return issue_template.DefaultConfig(), true
+			}
+			config, err := issue_template.ParseConfig(data)
+			if err != nil {
+				log.Debug("ParseConfig: %v", err)
+			}
+			return config, true
+		}
+	}
+	return issue_template.DefaultConfig(), false
+}
+
+// PullRequestTemplatesFromDefaultBranch returns the pull request templates of the first existing template directory
+// of the repo's default branch. The content isn't loaded, the name of a template defaults to its file name.
+func (ctx *Context) PullRequestTemplatesFromDefaultBranch() []api.IssueTemplate {
+	if !ctx.loadDefaultBranchCommit() {
+		return nil
+	}
 
+	for _, dirName := range PullRequestTemplateDirCandidates {
+		tree, err := ctx.Repo.Commit.SubTree(dirName)
+		if err != nil {
+			continue
+		}
+		entries, err := tree.ListEntries()
+		if err != nil {
+			return nil
+		}
+		templates := make([]api.IssueTemplate, 0, len(entries))
+		for _, entry := range entries {
+			if !entry.IsRegular() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			it := api.IssueTemplate{FileName: entry.Name()}
+			// the metadata is optional and at the beginning of the file
+			if header, err := entry.Blob().GetBlobContent(); err == nil {
+				if _, err := markdown.ExtractMetadata(header, &it); err != nil {
+					it = api.IssueTemplate{FileName: entry.Name()}
+				}
+			}
+			if strings.TrimSpace(it.Name) == "" {
+				it.Name = strings.TrimSuffix(entry.Name(), ".md")
+			}
+			templates = append(templates, it)
+		}
+		if len(templates) > 0 {
+			return templates
+		}
+	}
+	return nil
+}
+
+// loadDefaultBranchCommit loads the commit of the default branch if no commit is loaded
+func (ctx *Context) loadDefaultBranchCommit() bool {
 	if ctx.Repo.Repository.IsEmpty {
-		return issueTemplates
+		return false
 	}
 
 	if ctx.Repo.Commit == nil {
 		var err error
 		ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
 		if err != nil {
-			return issueTemplates
+			return false
 		}
 	}
+	return true
+}
+
+// issueTemplatesFromCommit returns the issue templates of the first existing template directory of the commit
+func issueTemplatesFromCommit(commit *git.Commit) []api.IssueTemplate {
+	var issueTemplates []api.IssueTemplate
 
 	for _, dirName := range IssueTemplateDirCandidates {
-		tree, err := ctx.Repo.Commit.SubTree(dirName)
+		tree, err := commit.SubTree(dirName)
 		if err != nil {
 			continue
 		}
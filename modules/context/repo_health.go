@@ -0,0 +1,165 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"io"
+	"path"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/funding"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MetaRepoName is the name of the repository which provides the default community health files
+// for all repositories of its owner
+const MetaRepoName = ".gitea"
+
+// healthFileDirCandidates are the directories which are searched for community health files
+var healthFileDirCandidates = []string{
+	"",
+	"docs",
+	".gitea",
+	".github",
+}
+
+// HealthFile represents a community health file of a repository
+type HealthFile struct {
+	TreePath string
+	Link     string
+	// IsInherited is true if the file is provided by the meta repository of the owner
+	IsInherited bool
+}
+
+// HealthFiles contains the community health files of a repository
+type HealthFiles struct {
+	Security     *HealthFile
+	Contributing *HealthFile
+	Funding      []*funding.Link
+}
+
+// HealthFilesFromDefaultBranch returns the community health files of the repo's default branch.
+// Files which don't exist in the repo are inherited from the meta repository of the owner.
+func (ctx *Context) HealthFilesFromDefaultBranch() *HealthFiles {
+	files := &HealthFiles{}
+
+	resolve := func(repo *repo_model.Repository, commit *git.Commit, isInherited bool) {
+		if files.Security == nil {
+			files.Security = healthFileFromCommit(repo, commit, "SECURITY.md", isInherited)
+		}
+		if files.Contributing == nil {
+			files.Contributing = healthFileFromCommit(repo, commit, "CONTRIBUTING.md", isInherited)
+		}
+		if files.Funding == nil {
+			files.Funding = fundingFromCommit(commit)
+		}
+	}
+
+	if !ctx.Repo.Repository.IsEmpty && ctx.Repo.GitRepo != nil {
+		commit, err := ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
+		if err == nil {
+			resolve(ctx.Repo.Repository, commit, false)
+		}
+	}
+
+	if files.Security == nil || files.Contributing == nil || files.Funding == nil {
+		ctx.withMetaRepoCommit(func(metaRepo *repo_model.Repository, commit *git.Commit) {
+			resolve(metaRepo, commit, true)
+		})
+	}
+
+	return files
+}
+
+// withMetaRepoCommit calls f with the default branch commit of the meta repository of the owner.
+// f is not called if the meta repository doesn't exist or its content is not public.
+func (ctx *Context) withMetaRepoCommit(f func(*repo_model.Repository, *git.Commit)) {
+	repo := ctx.Repo.Repository
+	if repo == nil || repo.LowerName == MetaRepoName {
+		return
+	}
+
+	metaRepo, err := repo_model.GetRepositoryByName(repo.OwnerID, MetaRepoName)
+	if err != nil {
+		if !repo_model.IsErrRepoNotExist(err) {
+			log.Error("GetRepositoryByName: %v", err)
+		}
+		return
+	}
+	if metaRepo.IsPrivate || metaRepo.IsEmpty {
+		return
+	}
+	if _, err := metaRepo.GetUnitCtx(ctx, unit_model.TypeCode); err != nil {
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, metaRepo.RepoPath())
+	if err != nil {
+		log.Error("OpenRepository[%s]: %v", metaRepo.RepoPath(), err)
+		return
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(metaRepo.DefaultBranch)
+	if err != nil {
+		log.Debug("GetBranchCommit[%s]: %v", metaRepo.DefaultBranch, err)
+		return
+	}
+
+	f(metaRepo, commit)
+}
+
+// healthFileFromCommit searches the health file in the candidate directories of the commit
+func healthFileFromCommit(repo *repo_model.Repository, commit *git.Commit, name string, isInherited bool) *HealthFile {
+	for _, dirName := range healthFileDirCandidates {
+		treePath := path.Join(dirName, name)
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		return &HealthFile{
+			TreePath:    treePath,
+			Link:        repo.Link() + "/src/branch/" + util.PathEscapeSegments(repo.DefaultBranch) + "/" + util.PathEscapeSegments(treePath),
+			IsInherited: isInherited,
+		}
+	}
+	return nil
+}
+
+// fundingFromCommit returns the funding links of the commit or nil if no funding file exists
+func fundingFromCommit(commit *git.Commit) []*funding.Link {
+	for _, treePath := range funding.FileCandidates {
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			continue
+		}
+		if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+			log.Debug("Funding file is too large: %s", treePath)
+			return []*funding.Link{}
+		}
+		r, err := entry.Blob().DataAsync()
+		if err != nil {
+			log.Debug("DataAsync: %v", err)
+			return []*funding.Link{}
+		}
+		data, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			log.Debug("ReadAll: %v", err)
+			return []*funding.Link{}
+		}
+		links, err := funding.Parse(data)
+		if err != nil {
+			log.Debug("Parse funding file: %v", err)
+			return []*funding.Link{}
+		}
+		return links
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"context"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToActivity converts an Action to an api.Activity
+func ToActivity(ctx context.Context, ac *activities_model.Action, doer *user_model.User) *api.Activity {
+	result := &api.Activity{
+		ID:        ac.ID,
+		UserID:    ac.UserID,
+		OpType:    ac.OpType.String(),
+		ActUserID: ac.ActUserID,
+		ActUser:   ToUser(ac.ActUser, doer),
+		RepoID:    ac.RepoID,
+		CommentID: ac.CommentID,
+		RefName:   ac.RefName,
+		IsPrivate: ac.IsPrivate,
+		Content:   ac.Content,
+		Created:   ac.CreatedUnix.AsTime(),
+	}
+
+	if ac.Repo != nil {
+		p, err := access_model.GetUserRepoPermission(ctx, ac.Repo, doer)
+		if err != nil {
+			log.Error("GetUserRepoPermission[%d]: %v", ac.RepoID, err)
+		}
+		result.Repo = ToRepo(ac.Repo, p.AccessMode)
+	}
+
+	return result
+}
+
+// ToActivities converts an ActionList to a list of api.Activity
+func ToActivities(ctx context.Context, al activities_model.ActionList, doer *user_model.User) []*api.Activity {
+	result := make([]*api.Activity, 0, len(al))
+	for _, ac := range al {
+		result = append(result, ToActivity(ctx, ac, doer))
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+var authSourceTypeNames = map[auth.Type]string{
+	auth.LDAP:   "ldap",
+	auth.DLDAP:  "dldap",
+	auth.SMTP:   "smtp",
+	auth.PAM:    "pam",
+	auth.OAuth2: "oauth2",
+	auth.SSPI:   "sspi",
+}
+
+// ToAuthSource converts an auth.Source and its health to api.AuthSource
+func ToAuthSource(source *auth.Source, health *auth.SourceHealth) *api.AuthSource {
+	return &api.AuthSource{
+		ID:               source.ID,
+		Name:             source.Name,
+		Type:             authSourceTypeNames[source.Type],
+		IsActive:         source.IsActive,
+		IsSyncEnabled:    source.IsSyncEnabled,
+		FallbackSourceID: source.FallbackSourceID,
+		Health:           ToAuthSourceHealth(source, health),
+		Created:          source.CreatedUnix.AsTime(),
+		Updated:          source.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToAuthSourceHealth converts an auth.SourceHealth to api.AuthSourceHealth, health may be nil if the source has not been checked yet
+func ToAuthSourceHealth(source *auth.Source, health *auth.SourceHealth) *api.AuthSourceHealth {
+	if !source.IsHealthCheckable() || health == nil || !health.IsChecked() {
+		return &api.AuthSourceHealth{Status: "unknown"}
+	}
+
+	apiHealth := &api.AuthSourceHealth{
+		Status:    "healthy",
+		CheckedAt: health.CheckedUnix.AsTimePtr(),
+	}
+	if !health.IsHealthy {
+		apiHealth.Status = "unhealthy"
+		apiHealth.Error = health.Error
+	}
+	if health.LastHealthyUnix != 0 {
+		apiHealth.LastHealthyAt = health.LastHealthyUnix.AsTimePtr()
+	}
+	return apiHealth
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToCollaboration converts a collaboration to its API format, the repository is only included if withRepo is set
+func ToCollaboration(ctx context.Context, c *repo_model.Collaboration, withRepo bool, doer *user_model.User) (*api.Collaboration, error) {
+	u, err := user_model.GetUserByIDCtx(ctx, c.UserID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		u = user_model.NewGhostUser()
+	}
+
+	result := &api.Collaboration{
+		User:       ToUser(u, doer),
+		Permission: c.Mode.String(),
+		Reminded:   c.RemindedUnix != 0,
+		Created:    c.CreatedUnix.AsTime(),
+	}
+	if c.HasExpiry() {
+		result.ExpiresAt = c.ExpiresUnix.AsTimePtr()
+	}
+
+	if withRepo {
+		repo, err := repo_model.GetRepositoryByIDCtx(ctx, c.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		result.Repository = ToRepo(repo, perm.AccessModeAdmin)
+	}
+
+	return result, nil
+}
@@ -184,7 +184,7 @@ func ToVerification(c *git.Commit) *api.PayloadCommitVerification {
 
 // ToPublicKey convert asymkey_model.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *asymkey_model.PublicKey) *api.PublicKey {
-	return &api.PublicKey{
+	apiKey := &api.PublicKey{
 		ID:          key.ID,
 		Key:         key.Content,
 		URL:         fmt.Sprintf("%s%d", apiLink, key.ID),
@@ -192,6 +192,13 @@ func ToPublicKey(apiLink string, key *asymkey_model.PublicKey) *api.PublicKey {
 		Fingerprint: key.Fingerprint,
 		Created:     key.CreatedUnix.AsTime(),
 	}
+	if key.LastUsedUnix > 0 {
+		apiKey.LastUsed = key.LastUsedUnix.AsTimePtr()
+	}
+	if key.ExpiresUnix > 0 {
+		apiKey.ExpiresAt = key.ExpiresUnix.AsTimePtr()
+	}
+	return apiKey
 }
 
 // ToGPGKey converts models.GPGKey to api.GPGKey
@@ -278,7 +285,7 @@ func ToGitHook(h *git.Hook) *api.GitHook {
 
 // ToDeployKey convert asymkey_model.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *asymkey_model.DeployKey) *api.DeployKey {
-	return &api.DeployKey{
+	apiKey := &api.DeployKey{
 		ID:          key.ID,
 		KeyID:       key.KeyID,
 		Key:         key.Content,
@@ -288,6 +295,13 @@ func ToDeployKey(apiLink string, key *asymkey_model.DeployKey) *api.DeployKey {
 		Created:     key.CreatedUnix.AsTime(),
 		ReadOnly:    key.Mode == perm.AccessModeRead, // All deploy keys are read-only.
 	}
+	if key.LastUsedUnix > 0 {
+		apiKey.LastUsed = key.LastUsedUnix.AsTimePtr()
+	}
+	if key.ExpiresUnix > 0 {
+		apiKey.ExpiresAt = key.ExpiresUnix.AsTimePtr()
+	}
+	return apiKey
 }
 
 // ToOrganization convert user_model.User to api.Organization
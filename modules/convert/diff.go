@@ -0,0 +1,129 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// ToChangedFile converts a git.DiffFileStat to an api.ChangedFile
+func ToChangedFile(stat *git.DiffFileStat) *api.ChangedFile {
+	return &api.ChangedFile{
+		Filename:         stat.Name,
+		PreviousFilename: stat.OldName,
+		Status:           stat.Status,
+		Additions:        stat.Additions,
+		Deletions:        stat.Deletions,
+		Changes:          stat.Additions + stat.Deletions,
+		IsBinary:         stat.IsBinary,
+	}
+}
+
+// ToChangedFileHunks converts the sections of a gitdiff.DiffFile to an api.ChangedFileHunks
+func ToChangedFileHunks(file *gitdiff.DiffFile) *api.ChangedFileHunks {
+	result := &api.ChangedFileHunks{
+		Filename:     file.Name,
+		IsBinary:     file.IsBin,
+		Hunks:        make([]*api.DiffHunk, 0, len(file.Sections)),
+		IsIncomplete: file.IsIncomplete,
+	}
+	if file.IsRenamed {
+		result.PreviousFilename = file.OldName
+	}
+
+	for _, section := range file.Sections {
+		hunk := &api.DiffHunk{
+			Lines: make([]*api.DiffHunkLine, 0, len(section.Lines)),
+		}
+		for _, line := range section.Lines {
+			content := line.Content
+			if len(content) > 0 {
+				// strip the marker of the line type
+				content = content[1:]
+			}
+			switch line.Type {
+			case gitdiff.DiffLineSection:
+				if !strings.HasPrefix(line.Content, "@@") || line.SectionInfo == nil {
+					// the expandable remainder of the file after the last hunk
+					continue
+				}
+				hunk.Header = line.Content
+				hunk.OldStart = line.SectionInfo.LeftIdx
+				hunk.OldLines = line.SectionInfo.LeftHunkSize
+				hunk.NewStart = line.SectionInfo.RightIdx
+				hunk.NewLines = line.SectionInfo.RightHunkSize
+			case gitdiff.DiffLineAdd:
+				hunk.Lines = append(hunk.Lines, &api.DiffHunkLine{Type: "add", Content: content, NewNumber: line.RightIdx})
+			case gitdiff.DiffLineDel:
+				hunk.Lines = append(hunk.Lines, &api.DiffHunkLine{Type: "delete", Content: content, OldNumber: line.LeftIdx})
+			default:
+				hunk.Lines = append(hunk.Lines, &api.DiffHunkLine{Type: "context", Content: content, OldNumber: line.LeftIdx, NewNumber: line.RightIdx})
+			}
+		}
+		if hunk.Header == "" && len(hunk.Lines) == 0 {
+			continue
+		}
+		result.Hunks = append(result.Hunks, hunk)
+	}
+	return result
+}
+
+// ToBlobDiff converts the parsed diff of two blobs to an api.BlobDiff
+func ToBlobDiff(baseRepoName string, base *gitdiff.BlobDiffSide, headRepoName string, head *gitdiff.BlobDiffSide, diff *gitdiff.Diff) *api.BlobDiff {
+	result := &api.BlobDiff{
+		Base:      toBlobDiffSide(baseRepoName, base),
+		Head:      toBlobDiffSide(headRepoName, head),
+		Identical: len(diff.Files) == 0,
+		Hunks:     []*api.DiffHunk{},
+	}
+	if len(diff.Files) > 0 {
+		file := diff.Files[0]
+		hunks := ToChangedFileHunks(file)
+		result.IsBinary = hunks.IsBinary
+		result.Additions = file.Addition
+		result.Deletions = file.Deletion
+		result.Hunks = hunks.Hunks
+		result.IsIncomplete = hunks.IsIncomplete || diff.IsIncomplete
+	}
+	return result
+}
+
+func toBlobDiffSide(repoName string, side *gitdiff.BlobDiffSide) *api.BlobDiffSide {
+	return &api.BlobDiffSide{
+		Repository: repoName,
+		Ref:        side.Ref,
+		CommitID:   side.CommitID,
+		Path:       side.TreePath,
+		SHA:        side.Blob.ID.String(),
+		Size:       side.Blob.Size(),
+	}
+}
+
+// ToBinaryDiff converts a gitdiff.BinaryDiff to an api.BinaryDiff
+func ToBinaryDiff(diff *gitdiff.BinaryDiff) *api.BinaryDiff {
+	return &api.BinaryDiff{
+		Before:     toBinaryDiffBlob(diff.Before),
+		After:      toBinaryDiffBlob(diff.After),
+		SizeChange: diff.SizeChange(),
+	}
+}
+
+func toBinaryDiffBlob(blob *gitdiff.BinaryDiffBlob) *api.BinaryDiffBlob {
+	if blob == nil {
+		return nil
+	}
+	return &api.BinaryDiffBlob{
+		SHA:      blob.ID,
+		Size:     blob.Size,
+		MimeType: blob.MimeType,
+		IsImage:  blob.IsImage,
+		Width:    blob.Width,
+		Height:   blob.Height,
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/gitdiff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToChangedFileHunks(t *testing.T) {
+	patch := `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -1,3 +1,3 @@ intro
+ first
+-second
++2nd
+ third
+@@ -10,2 +10,3 @@
+ tenth
++new
+ eleventh
+`
+	diff, err := gitdiff.ParsePatch(-1, 5000, -1, strings.NewReader(patch), "")
+	assert.NoError(t, err)
+	if !assert.Len(t, diff.Files, 1) {
+		return
+	}
+
+	hunks := ToChangedFileHunks(diff.Files[0])
+	assert.Equal(t, "README.md", hunks.Filename)
+	assert.False(t, hunks.IsIncomplete)
+	if !assert.Len(t, hunks.Hunks, 2) {
+		return
+	}
+	assert.Equal(t, &api.DiffHunk{
+		Header:   "@@ -1,3 +1,3 @@ intro",
+		OldStart: 1,
+		OldLines: 3,
+		NewStart: 1,
+		NewLines: 3,
+		Lines: []*api.DiffHunkLine{
+			{Type: "context", Content: "first", OldNumber: 1, NewNumber: 1},
+			{Type: "delete", Content: "second", OldNumber: 2},
+			{Type: "add", Content: "2nd", NewNumber: 2},
+			{Type: "context", Content: "third", OldNumber: 3, NewNumber: 3},
+		},
+	}, hunks.Hunks[0])
+	assert.Equal(t, 10, hunks.Hunks[1].NewStart)
+	assert.Equal(t, &api.DiffHunkLine{Type: "add", Content: "new", NewNumber: 11}, hunks.Hunks[1].Lines[1])
+}
@@ -0,0 +1,22 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	federation_model "code.gitea.io/gitea/models/federation"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToFederatedInstance converts federation.Instance to api.FederatedInstance
+func ToFederatedInstance(instance *federation_model.Instance) *api.FederatedInstance {
+	return &api.FederatedInstance{
+		ID:      instance.ID,
+		Host:    instance.Host,
+		Policy:  string(instance.Policy),
+		Reason:  instance.Reason,
+		Created: instance.CreatedUnix.AsTime(),
+		Updated: instance.UpdatedUnix.AsTime(),
+	}
+}
@@ -14,6 +14,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 )
@@ -53,6 +54,12 @@ func ToAPIIssue(issue *issues_model.Issue) *api.Issue {
 		Updated:  issue.UpdatedUnix.AsTime(),
 	}
 
+	total, completed := markdown.CountTasks(issue.Content)
+	apiIssue.TaskProgress = &api.IssueTaskProgress{
+		Total:     total,
+		Completed: completed,
+	}
+
 	apiIssue.Repo = &api.RepositoryMeta{
 		ID:       issue.Repo.ID,
 		Name:     issue.Repo.Name,
@@ -184,6 +191,7 @@ func ToLabel(label *issues_model.Label, repo *repo_model.Repository, org *user_m
 		Name:        label.Name,
 		Color:       strings.TrimLeft(label.Color, "#"),
 		Description: label.Description,
+		Scope:       label.Scope(),
 	}
 
 	// calculate URL
@@ -233,3 +241,21 @@ func ToAPIMilestone(m *issues_model.Milestone) *api.Milestone {
 	}
 	return apiMilestone
 }
+
+// ToIssueTaskList converts the tasks parsed from the body of an issue to API format
+func ToIssueTaskList(tasks []*markdown.Task) []*api.IssueTask {
+	result := make([]*api.IssueTask, 0, len(tasks))
+	for _, task := range tasks {
+		result = append(result, ToIssueTask(task))
+	}
+	return result
+}
+
+// ToIssueTask converts a task parsed from the body of an issue to API format
+func ToIssueTask(task *markdown.Task) *api.IssueTask {
+	return &api.IssueTask{
+		Index:   task.Index,
+		Text:    task.Text,
+		Checked: task.Checked,
+	}
+}
@@ -24,7 +24,38 @@ func ToComment(c *issues_model.Comment) *api.Comment {
 		Body:     c.Content,
 		Created:  c.CreatedUnix.AsTime(),
 		Updated:  c.UpdatedUnix.AsTime(),
+
+		Hidden:       c.IsHidden(),
+		HiddenReason: c.HiddenReason,
+	}
+}
+
+// ToCommentRevision converts a content history revision to the api.CommentRevision format
+func ToCommentRevision(h *issues_model.ContentHistory, editor *user_model.User) *api.CommentRevision {
+	return &api.CommentRevision{
+		ID:        h.ID,
+		Editor:    ToUser(editor, nil),
+		Body:      h.ContentText,
+		IsFirst:   h.IsFirstCreated,
+		IsDeleted: h.IsDeleted,
+		Edited:    h.EditedUnix.AsTime(),
+	}
+}
+
+// ToModerationLog converts a moderation log entry to the api.ModerationLog format
+func ToModerationLog(l *issues_model.ModerationLog) *api.ModerationLog {
+	result := &api.ModerationLog{
+		ID:        l.ID,
+		Action:    string(l.Action),
+		Doer:      ToUser(l.Doer, nil),
+		CommentID: l.CommentID,
+		Reason:    l.Reason,
+		Created:   l.CreatedUnix.AsTime(),
+	}
+	if l.Issue != nil {
+		result.IssueIndex = l.Issue.Index
 	}
+	return result
 }
 
 // ToTimelineComment converts a issues_model.Comment to the api.TimelineComment format
@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToNamePolicy converts user_model.NamePolicy to api.NamePolicy
+func ToNamePolicy(p *user_model.NamePolicy) *api.NamePolicy {
+	return &api.NamePolicy{
+		ID:          p.ID,
+		Kind:        string(p.Kind),
+		Pattern:     p.Pattern,
+		Description: p.Description,
+		Created:     p.CreatedUnix.AsTime(),
+	}
+}
+
+// ToNameClaim converts user_model.NameClaim to api.NameClaim
+func ToNameClaim(c *user_model.NameClaim, claimant, doer *user_model.User) *api.NameClaim {
+	apiClaim := &api.NameClaim{
+		ID:       c.ID,
+		UserID:   c.UserID,
+		Name:     c.Name,
+		Status:   string(c.Status),
+		Created:  c.CreatedUnix.AsTime(),
+		Deadline: c.DeadlineUnix.AsTime(),
+	}
+	if claimant != nil {
+		apiClaim.Claimant = ToUser(claimant, doer)
+	}
+	return apiClaim
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToOrgJoinRequest converts a join request to its API format
+func ToOrgJoinRequest(ctx context.Context, jr *organization.JoinRequest, org *organization.Organization, doer *user_model.User) (*api.OrgJoinRequest, error) {
+	requester, err := user_model.GetUserByIDCtx(ctx, jr.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.OrgJoinRequest{
+		ID:           jr.ID,
+		User:         ToUser(requester, doer),
+		Organization: ToOrganization(org),
+		Message:      jr.Message,
+		Status:       jr.Status.String(),
+		Created:      jr.CreatedUnix.AsTime(),
+		Updated:      jr.UpdatedUnix.AsTime(),
+	}
+
+	if jr.TeamID != 0 {
+		team, err := organization.GetTeamByID(ctx, jr.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if result.Team, err = ToTeam(team); err != nil {
+			return nil, err
+		}
+	}
+
+	if jr.ReviewerID != 0 {
+		reviewer, err := user_model.GetUserByIDCtx(ctx, jr.ReviewerID)
+		if err != nil {
+			if !user_model.IsErrUserNotExist(err) {
+				return nil, err
+			}
+			reviewer = user_model.NewGhostUser()
+		}
+		result.Reviewer = ToUser(reviewer, doer)
+	}
+
+	return result, nil
+}
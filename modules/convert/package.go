@@ -10,7 +10,9 @@ import (
 	"code.gitea.io/gitea/models/packages"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // ToPackage convert a packages.PackageDescriptor to api.Package
@@ -28,14 +30,15 @@ func ToPackage(ctx context.Context, pd *packages.PackageDescriptor, doer *user_m
 	}
 
 	return &api.Package{
-		ID:         pd.Version.ID,
-		Owner:      ToUser(pd.Owner, doer),
-		Repository: repo,
-		Creator:    ToUser(pd.Creator, doer),
-		Type:       string(pd.Package.Type),
-		Name:       pd.Package.Name,
-		Version:    pd.Version.Version,
-		CreatedAt:  pd.Version.CreatedUnix.AsTime(),
+		ID:           pd.Version.ID,
+		Owner:        ToUser(pd.Owner, doer),
+		Repository:   repo,
+		Creator:      ToUser(pd.Creator, doer),
+		Type:         string(pd.Package.Type),
+		Name:         pd.Package.Name,
+		Version:      pd.Version.Version,
+		ReleaseNotes: pd.Version.ReleaseNotes,
+		CreatedAt:    pd.Version.CreatedUnix.AsTime(),
 	}, nil
 }
 
@@ -51,3 +54,70 @@ func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 		HashSHA512: pfd.Blob.HashSHA512,
 	}
 }
+
+// ToPackageFileDiff converts packages_module.ContentDiffEntry to api.PackageFileDiff
+func ToPackageFileDiff(e *packages_module.ContentDiffEntry) *api.PackageFileDiff {
+	return &api.PackageFileDiff{
+		Path:    e.Path,
+		Status:  string(e.Status),
+		OldSize: e.OldSize,
+		NewSize: e.NewSize,
+		Patch:   e.Patch,
+	}
+}
+
+// ToPackageDependency converts packages.PackageDependency to api.PackageDependency
+func ToPackageDependency(dep *packages.PackageDependency) *api.PackageDependency {
+	return &api.PackageDependency{
+		Kind:         string(dep.Kind),
+		Name:         dep.Name,
+		VersionRange: dep.VersionRange,
+	}
+}
+
+// ToPackageAuditLog converts packages.PackageAuditLog to api.PackageAuditLog, the owner must be loaded
+func ToPackageAuditLog(l *packages.PackageAuditLog) *api.PackageAuditLog {
+	return &api.PackageAuditLog{
+		ID:          l.ID,
+		Scope:       string(l.Scope),
+		Owner:       l.Owner.Name,
+		Action:      string(l.Action),
+		ActorID:     l.ActorID,
+		Actor:       l.ActorName,
+		PackageType: string(l.PackageType),
+		PackageName: l.PackageName,
+		Version:     l.Version,
+		Filename:    l.Filename,
+		Detail:      l.Detail,
+		IP:          l.IP,
+		UserAgent:   l.UserAgent,
+		CreatedAt:   l.CreatedUnix.AsTime(),
+	}
+}
+
+// ToPackageMirror converts packages.PackageMirror to api.PackageMirror
+func ToPackageMirror(m *packages.PackageMirror) *api.PackageMirror {
+	rules := make([]*api.PackageMirrorRule, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		rules = append(rules, &api.PackageMirrorRule{
+			Name:         rule.Name,
+			VersionRange: rule.VersionRange,
+		})
+	}
+
+	apiMirror := &api.PackageMirror{
+		ID:          m.ID,
+		Type:        string(m.Type),
+		UpstreamURL: util.SanitizeCredentialURLs(m.UpstreamURL),
+		Rules:       rules,
+		Interval:    m.Interval.String(),
+		NextSync:    m.NextSyncUnix.AsTime(),
+		LastError:   m.LastError,
+		CreatedAt:   m.CreatedUnix.AsTime(),
+	}
+	if m.LastSyncUnix != 0 {
+		lastSync := m.LastSyncUnix.AsTime()
+		apiMirror.LastSync = &lastSync
+	}
+	return apiMirror
+}
@@ -51,28 +51,29 @@ func ToAPIPullRequest(ctx context.Context, pr *issues_model.PullRequest, doer *u
 	}
 
 	apiPullRequest := &api.PullRequest{
-		ID:        pr.ID,
-		URL:       pr.Issue.HTMLURL(),
-		Index:     pr.Index,
-		Poster:    apiIssue.Poster,
-		Title:     apiIssue.Title,
-		Body:      apiIssue.Body,
-		Labels:    apiIssue.Labels,
-		Milestone: apiIssue.Milestone,
-		Assignee:  apiIssue.Assignee,
-		Assignees: apiIssue.Assignees,
-		State:     apiIssue.State,
-		IsLocked:  apiIssue.IsLocked,
-		Comments:  apiIssue.Comments,
-		HTMLURL:   pr.Issue.HTMLURL(),
-		DiffURL:   pr.Issue.DiffURL(),
-		PatchURL:  pr.Issue.PatchURL(),
-		HasMerged: pr.HasMerged,
-		MergeBase: pr.MergeBase,
-		Mergeable: pr.Mergeable(),
-		Deadline:  apiIssue.Deadline,
-		Created:   pr.Issue.CreatedUnix.AsTimePtr(),
-		Updated:   pr.Issue.UpdatedUnix.AsTimePtr(),
+		ID:           pr.ID,
+		URL:          pr.Issue.HTMLURL(),
+		Index:        pr.Index,
+		Poster:       apiIssue.Poster,
+		Title:        apiIssue.Title,
+		Body:         apiIssue.Body,
+		Labels:       apiIssue.Labels,
+		Milestone:    apiIssue.Milestone,
+		Assignee:     apiIssue.Assignee,
+		Assignees:    apiIssue.Assignees,
+		State:        apiIssue.State,
+		TaskProgress: apiIssue.TaskProgress,
+		IsLocked:     apiIssue.IsLocked,
+		Comments:     apiIssue.Comments,
+		HTMLURL:      pr.Issue.HTMLURL(),
+		DiffURL:      pr.Issue.DiffURL(),
+		PatchURL:     pr.Issue.PatchURL(),
+		HasMerged:    pr.HasMerged,
+		MergeBase:    pr.MergeBase,
+		Mergeable:    pr.Mergeable(),
+		Deadline:     apiIssue.Deadline,
+		Created:      pr.Issue.CreatedUnix.AsTimePtr(),
+		Updated:      pr.Issue.UpdatedUnix.AsTimePtr(),
 
 		AllowMaintainerEdit: pr.AllowMaintainerEdit,
 
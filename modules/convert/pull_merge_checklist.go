@@ -0,0 +1,47 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToMergeChecklistItem converts issues_model.MergeChecklistItem to api.MergeChecklistItem
+func ToMergeChecklistItem(item *issues_model.MergeChecklistItem) *api.MergeChecklistItem {
+	return &api.MergeChecklistItem{
+		ID:      item.ID,
+		Name:    item.Name,
+		Created: item.CreatedUnix.AsTime(),
+	}
+}
+
+// ToPullMergeChecklist converts the merge checklist state of a pull request to api.PullMergeChecklist,
+// users contains the users who checked the items
+func ToPullMergeChecklist(states []*issues_model.MergeChecklistItemState, users map[int64]*user_model.User, doer *user_model.User) *api.PullMergeChecklist {
+	checklist := &api.PullMergeChecklist{
+		Complete: true,
+		Items:    make([]*api.PullMergeChecklistItem, 0, len(states)),
+	}
+	for _, s := range states {
+		item := &api.PullMergeChecklistItem{
+			ID:      s.ID,
+			Name:    s.Name,
+			Checked: s.IsChecked(),
+		}
+		if s.IsChecked() {
+			checkedAt := s.Check.CreatedUnix.AsTime()
+			item.CheckedAt = &checkedAt
+			if u, ok := users[s.Check.DoerID]; ok {
+				item.CheckedBy = ToUser(u, doer)
+			}
+		} else {
+			checklist.Complete = false
+		}
+		checklist.Items = append(checklist.Items, item)
+	}
+	return checklist
+}
@@ -13,7 +13,7 @@ import (
 func ToRelease(r *repo_model.Release) *api.Release {
 	assets := make([]*api.Attachment, 0)
 	for _, att := range r.Attachments {
-		assets = append(assets, ToReleaseAttachment(att))
+		assets = append(assets, ToAttachment(att))
 	}
 	return &api.Release{
 		ID:           r.ID,
@@ -34,8 +34,8 @@ func ToRelease(r *repo_model.Release) *api.Release {
 	}
 }
 
-// ToReleaseAttachment converts models.Attachment to api.Attachment
-func ToReleaseAttachment(a *repo_model.Attachment) *api.Attachment {
+// ToAttachment converts models.Attachment to api.Attachment
+func ToAttachment(a *repo_model.Attachment) *api.Attachment {
 	return &api.Attachment{
 		ID:            a.ID,
 		Name:          a.Name,
@@ -44,5 +44,6 @@ func ToReleaseAttachment(a *repo_model.Attachment) *api.Attachment {
 		Size:          a.Size,
 		UUID:          a.UUID,
 		DownloadURL:   a.DownloadURL(),
+		SHA256:        a.HashSHA256,
 	}
 }
@@ -0,0 +1,42 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPropertyDefinition converts a repo_model.PropertyDefinition to an api.PropertyDefinition
+func ToPropertyDefinition(def *repo_model.PropertyDefinition) *api.PropertyDefinition {
+	allowedValues := def.AllowedValues
+	if allowedValues == nil {
+		allowedValues = []string{}
+	}
+	return &api.PropertyDefinition{
+		Name:          def.Name,
+		Description:   def.Description,
+		AllowedValues: allowedValues,
+		Created:       def.CreatedUnix.AsTime(),
+		Updated:       def.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToRepoPolicy converts an organization.RepoPolicy to an api.RepoPolicy
+func ToRepoPolicy(p *organization.RepoPolicy) *api.RepoPolicy {
+	requiredProperties := p.RequiredProperties
+	if requiredProperties == nil {
+		requiredProperties = []string{}
+	}
+	return &api.RepoPolicy{
+		NamePattern:            p.NamePattern,
+		NamePatternDescription: p.NamePatternDescription,
+		RequireTopic:           p.RequireTopic,
+		RequiredProperties:     requiredProperties,
+		Created:                p.CreatedUnix.AsTime(),
+		Updated:                p.UpdatedUnix.AsTime(),
+	}
+}
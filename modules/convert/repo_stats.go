@@ -0,0 +1,30 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoStatsRecalculation converts repo_model.StatsRecalculation to api.RepoStatsRecalculation
+func ToRepoStatsRecalculation(r *repo_model.StatsRecalculation) *api.RepoStatsRecalculation {
+	apiRecalculation := &api.RepoStatsRecalculation{
+		ID:        r.ID,
+		Kinds:     r.Kinds,
+		Status:    r.Status.String(),
+		Total:     r.Total,
+		Queued:    r.Queued,
+		Processed: r.Processed,
+		Failed:    r.Failed,
+		Created:   r.CreatedUnix.AsTime(),
+		Updated:   r.UpdatedUnix.AsTime(),
+	}
+	if r.FinishedUnix > 0 {
+		finished := r.FinishedUnix.AsTime()
+		apiRecalculation.Finished = &finished
+	}
+	return apiRecalculation
+}
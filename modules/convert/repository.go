@@ -51,6 +51,8 @@ func innerToRepo(repo *repo_model.Repository, mode perm.AccessMode, isParent boo
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			CloseKeywords:                    config.CloseKeywords,
+			ReopenKeywords:                   config.ReopenKeywords,
 		}
 	} else if unit, err := repo.GetUnit(unit_model.TypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -129,6 +131,11 @@ func innerToRepo(repo *repo_model.Repository, mode perm.AccessMode, isParent boo
 		}
 	}
 
+	properties, err := repo_model.GetRepoProperties(db.DefaultContext, repo.ID)
+	if err != nil {
+		log.Warn("GetRepoProperties: %v", err)
+	}
+
 	var language string
 	if repo.PrimaryLanguage != nil {
 		language = repo.PrimaryLanguage.Language
@@ -187,6 +194,7 @@ func innerToRepo(repo *repo_model.Repository, mode perm.AccessMode, isParent boo
 		MirrorInterval:                mirrorInterval,
 		MirrorUpdated:                 mirrorUpdated,
 		RepoTransfer:                  transfer,
+		Properties:                    properties,
 	}
 }
 
@@ -194,9 +202,17 @@ func innerToRepo(repo *repo_model.Repository, mode perm.AccessMode, isParent boo
 func ToRepoTransfer(t *models.RepoTransfer) *api.RepoTransfer {
 	teams, _ := ToTeams(t.Teams, false)
 
-	return &api.RepoTransfer{
+	transfer := &api.RepoTransfer{
 		Doer:      ToUser(t.Doer, nil),
 		Recipient: ToUser(t.Recipient, nil),
 		Teams:     teams,
+		Created:   t.CreatedUnix.AsTime(),
+	}
+	if t.ScheduledUnix > 0 {
+		transfer.ScheduledAt = t.ScheduledUnix.AsTimePtr()
+	}
+	if t.AcceptedUnix > 0 {
+		transfer.AcceptedAt = t.AcceptedUnix.AsTimePtr()
 	}
+	return transfer
 }
@@ -0,0 +1,92 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"context"
+
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	search_service "code.gitea.io/gitea/services/search"
+)
+
+// ToGlobalSearchResults converts search_service.Results to api.GlobalSearchResults
+func ToGlobalSearchResults(ctx context.Context, results *search_service.Results, doer *user_model.User) (*api.GlobalSearchResults, error) {
+	apiResults := &api.GlobalSearchResults{
+		Counts: make(map[string]int64, len(results.Counts)),
+	}
+	for t, count := range results.Counts {
+		apiResults.Counts[string(t)] = count
+	}
+
+	toRepo := func(repo *repo_model.Repository) (*api.Repository, error) {
+		if err := repo.GetOwner(ctx); err != nil {
+			return nil, err
+		}
+		accessMode, err := access_model.AccessLevel(doer, repo)
+		if err != nil {
+			return nil, err
+		}
+		return ToRepo(repo, accessMode), nil
+	}
+
+	if _, ok := results.Counts[search_service.TypeRepository]; ok {
+		apiResults.Repositories = make([]*api.Repository, 0, len(results.Repositories))
+		for _, repo := range results.Repositories {
+			apiRepo, err := toRepo(repo)
+			if err != nil {
+				return nil, err
+			}
+			apiResults.Repositories = append(apiResults.Repositories, apiRepo)
+		}
+	}
+
+	if _, ok := results.Counts[search_service.TypeCode]; ok {
+		apiResults.Code = make([]*api.CodeSearchResult, 0, len(results.Code))
+		for _, result := range results.Code {
+			repo, ok := results.CodeRepos[result.RepoID]
+			if !ok {
+				continue
+			}
+			apiRepo, err := toRepo(repo)
+			if err != nil {
+				return nil, err
+			}
+			apiResults.Code = append(apiResults.Code, &api.CodeSearchResult{
+				Repository:  apiRepo,
+				Path:        result.Filename,
+				CommitID:    result.CommitID,
+				Language:    result.Language,
+				LineNumbers: result.LineNumbers,
+				HTMLURL:     repo.HTMLURL() + "/src/commit/" + result.CommitID + "/" + util.PathEscapeSegments(result.Filename),
+				Updated:     result.UpdatedUnix.AsTime(),
+			})
+		}
+	}
+
+	if _, ok := results.Counts[search_service.TypeIssue]; ok {
+		apiResults.Issues = ToAPIIssueList(results.Issues)
+	}
+
+	if _, ok := results.Counts[search_service.TypeUser]; ok {
+		apiResults.Users = ToUsers(doer, results.Users)
+	}
+
+	if _, ok := results.Counts[search_service.TypePackage]; ok {
+		apiResults.Packages = make([]*api.Package, 0, len(results.Packages))
+		for _, pd := range results.Packages {
+			apiPackage, err := ToPackage(ctx, pd, doer)
+			if err != nil {
+				return nil, err
+			}
+			apiResults.Packages = append(apiResults.Packages, apiPackage)
+		}
+	}
+
+	return apiResults, nil
+}
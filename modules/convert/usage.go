@@ -0,0 +1,59 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	activities_model "code.gitea.io/gitea/models/activities"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStorageUsage converts the storage use of activities_model.UsageReport to api.StorageUsage
+func ToStorageUsage(r *activities_model.UsageReport) *api.StorageUsage {
+	return &api.StorageUsage{
+		Git:         r.GitSize,
+		LFS:         r.LFSSize,
+		Packages:    r.PackageSize,
+		Attachments: r.AttachmentSize,
+		Total:       r.TotalSize(),
+	}
+}
+
+// ToUsageSnapshot converts activities_model.UsageReport to api.UsageSnapshot
+func ToUsageSnapshot(r *activities_model.UsageReport) *api.UsageSnapshot {
+	return &api.UsageSnapshot{
+		Generated:     r.CreatedUnix.AsTime(),
+		Storage:       ToStorageUsage(r),
+		Repositories:  r.NumRepos,
+		Users:         r.NumUsers,
+		Organizations: r.NumOrgs,
+	}
+}
+
+// ToUsageReport converts activities_model.UsageReport and the reports of a time range to api.UsageReport
+func ToUsageReport(r *activities_model.UsageReport, growth []*activities_model.UsageReport) *api.UsageReport {
+	consumers := make([]*api.UsageConsumer, 0, len(r.TopConsumers))
+	for _, c := range r.TopConsumers {
+		consumers = append(consumers, &api.UsageConsumer{
+			Owner:        c.OwnerName,
+			Repositories: c.RepoSize,
+			Packages:     c.PackageSize,
+			Total:        c.TotalSize(),
+		})
+	}
+	snapshots := make([]*api.UsageSnapshot, 0, len(growth))
+	for _, g := range growth {
+		snapshots = append(snapshots, ToUsageSnapshot(g))
+	}
+
+	return &api.UsageReport{
+		Generated:     r.CreatedUnix.AsTime(),
+		Storage:       ToStorageUsage(r),
+		Repositories:  r.NumRepos,
+		Users:         r.NumUsers,
+		Organizations: r.NumOrgs,
+		TopConsumers:  consumers,
+		Growth:        snapshots,
+	}
+}
@@ -57,6 +57,8 @@ func toUser(user *user_model.User, signed, authed bool) *api.User {
 		Location:    user.Location,
 		Website:     user.Website,
 		Description: user.Description,
+		// service accounts are labeled for everyone
+		IsServiceAccount: user.IsBot(),
 		// counter's
 		Followers:    user.NumFollowers,
 		Following:    user.NumFollowing,
@@ -105,3 +107,16 @@ func ToUserAndPermission(user, doer *user_model.User, accessMode perm.AccessMode
 		RoleName:   accessMode.String(),
 	}
 }
+
+// ToServiceAccount converts a service account to api.ServiceAccount
+func ToServiceAccount(u *user_model.User) *api.ServiceAccount {
+	return &api.ServiceAccount{
+		ID:          u.ID,
+		UserName:    u.Name,
+		FullName:    u.FullName,
+		Description: u.Description,
+		AvatarURL:   u.AvatarLink(),
+		Disabled:    u.ProhibitLogin,
+		Created:     u.CreatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package diagnostics keeps the latest requests and slow database queries in memory,
+// so the administrators can find performance issues without an external monitoring service.
+package diagnostics
+
+import (
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Request is a served HTTP request
+type Request struct {
+	Time     time.Time
+	Method   string
+	Route    string
+	Path     string
+	User     string
+	Status   int
+	Duration time.Duration
+	Size     int64
+}
+
+// SlowQuery is a database query which took longer than the configured threshold
+type SlowQuery struct {
+	Time     time.Time
+	SQL      string
+	Duration time.Duration
+	Error    string
+}
+
+var (
+	requests    ring
+	slowQueries ring
+)
+
+// RecordRequest keeps the request if the request log is enabled
+func RecordRequest(r *Request) {
+	if !setting.Diagnostics.EnableRequestLog {
+		return
+	}
+	requests.add(r, setting.Diagnostics.MaxRequests)
+}
+
+// RecordSlowQuery keeps the query
+func RecordSlowQuery(q *SlowQuery) {
+	slowQueries.add(q, setting.Diagnostics.MaxSlowQueries)
+}
+
+// FindRequestsOptions represents the options to filter the kept requests
+type FindRequestsOptions struct {
+	// Route matches the requests whose route pattern or path contain the value
+	Route       string
+	User        string
+	MinStatus   int
+	MinDuration time.Duration
+	Since       time.Time
+	Limit       int
+}
+
+func (opts *FindRequestsOptions) match(r *Request) bool {
+	return (opts.Route == "" || strings.Contains(r.Route, opts.Route) || strings.Contains(r.Path, opts.Route)) &&
+		(opts.User == "" || strings.EqualFold(r.User, opts.User)) &&
+		r.Status >= opts.MinStatus &&
+		r.Duration >= opts.MinDuration &&
+		!r.Time.Before(opts.Since)
+}
+
+// FindRequests returns the kept requests matching the options, the newest first
+func FindRequests(opts FindRequestsOptions) []*Request {
+	result := make([]*Request, 0, 10)
+	requests.each(func(entry interface{}) bool {
+		if r := entry.(*Request); opts.match(r) {
+			result = append(result, r)
+		}
+		return opts.Limit <= 0 || len(result) < opts.Limit
+	})
+	return result
+}
+
+// FindSlowQueriesOptions represents the options to filter the kept slow queries
+type FindSlowQueriesOptions struct {
+	MinDuration time.Duration
+	Since       time.Time
+	Limit       int
+}
+
+// FindSlowQueries returns the kept slow queries matching the options, the newest first
+func FindSlowQueries(opts FindSlowQueriesOptions) []*SlowQuery {
+	result := make([]*SlowQuery, 0, 10)
+	slowQueries.each(func(entry interface{}) bool {
+		if q := entry.(*SlowQuery); q.Duration >= opts.MinDuration && !q.Time.Before(opts.Since) {
+			result = append(result, q)
+		}
+		return opts.Limit <= 0 || len(result) < opts.Limit
+	})
+	return result
+}
+
+// Reset removes all kept requests and slow queries
+func Reset() {
+	requests.reset()
+	slowQueries.reset()
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing(t *testing.T) {
+	var r ring
+	values := func() []int {
+		result := []int{}
+		r.each(func(entry interface{}) bool {
+			result = append(result, entry.(int))
+			return true
+		})
+		return result
+	}
+
+	assert.Empty(t, values())
+	r.add(1, 3)
+	r.add(2, 3)
+	assert.Equal(t, []int{2, 1}, values())
+	r.add(3, 3)
+	assert.Equal(t, []int{3, 2, 1}, values())
+	r.add(4, 3)
+	r.add(5, 3)
+	assert.Equal(t, []int{5, 4, 3}, values())
+	r.reset()
+	assert.Empty(t, values())
+}
+
+func TestFindRequests(t *testing.T) {
+	defer func(enabled bool, max int) {
+		setting.Diagnostics.EnableRequestLog = enabled
+		setting.Diagnostics.MaxRequests = max
+		Reset()
+	}(setting.Diagnostics.EnableRequestLog, setting.Diagnostics.MaxRequests)
+
+	setting.Diagnostics.EnableRequestLog = false
+	RecordRequest(&Request{Route: "/ignored"})
+	assert.Empty(t, FindRequests(FindRequestsOptions{}))
+
+	setting.Diagnostics.EnableRequestLog = true
+	setting.Diagnostics.MaxRequests = 3
+	now := time.Now()
+	RecordRequest(&Request{Time: now.Add(-time.Hour), Route: "/{username}", Path: "/user1", User: "user1", Status: 200, Duration: time.Millisecond})
+	RecordRequest(&Request{Time: now, Route: "/api/v1/repos/{username}/{reponame}", Path: "/api/v1/repos/user2/repo1", User: "user2", Status: 200, Duration: 2 * time.Second})
+	RecordRequest(&Request{Time: now, Route: "/{username}/{reponame}", Path: "/user2/repo1", Status: 404, Duration: 10 * time.Millisecond})
+
+	assert.Len(t, FindRequests(FindRequestsOptions{}), 3)
+	assert.Len(t, FindRequests(FindRequestsOptions{Limit: 2}), 2)
+	assert.Equal(t, "/user2/repo1", FindRequests(FindRequestsOptions{})[0].Path)
+
+	found := FindRequests(FindRequestsOptions{Route: "/api/"})
+	assert.Len(t, found, 1)
+	assert.Equal(t, "user2", found[0].User)
+	assert.Len(t, FindRequests(FindRequestsOptions{Route: "user2"}), 2)
+	assert.Len(t, FindRequests(FindRequestsOptions{User: "USER1"}), 1)
+	assert.Len(t, FindRequests(FindRequestsOptions{MinStatus: 400}), 1)
+	assert.Len(t, FindRequests(FindRequestsOptions{MinDuration: time.Second}), 1)
+	assert.Len(t, FindRequests(FindRequestsOptions{Since: now.Add(-time.Minute)}), 2)
+
+	RecordRequest(&Request{Time: now, Route: "/", Path: "/", Status: 200})
+	found = FindRequests(FindRequestsOptions{})
+	assert.Len(t, found, 3)
+	assert.Equal(t, "/", found[0].Path)
+	assert.Len(t, FindRequests(FindRequestsOptions{User: "user1"}), 0)
+}
+
+func TestFindSlowQueries(t *testing.T) {
+	defer Reset()
+
+	now := time.Now()
+	RecordSlowQuery(&SlowQuery{Time: now.Add(-time.Hour), SQL: "SELECT 1", Duration: time.Second})
+	RecordSlowQuery(&SlowQuery{Time: now, SQL: "SELECT 2", Duration: 5 * time.Second})
+
+	found := FindSlowQueries(FindSlowQueriesOptions{})
+	assert.Len(t, found, 2)
+	assert.Equal(t, "SELECT 2", found[0].SQL)
+	assert.Len(t, FindSlowQueries(FindSlowQueriesOptions{MinDuration: 2 * time.Second}), 1)
+	assert.Len(t, FindSlowQueries(FindSlowQueriesOptions{Since: now.Add(-time.Minute)}), 1)
+	assert.Len(t, FindSlowQueries(FindSlowQueriesOptions{Limit: 1}), 1)
+}
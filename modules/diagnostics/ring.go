@@ -0,0 +1,49 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"sync"
+)
+
+// ring keeps the latest entries up to a maximum number, older entries are overwritten
+type ring struct {
+	mu      sync.RWMutex
+	entries []interface{}
+	next    int
+}
+
+func (r *ring) add(entry interface{}, max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) < max {
+		r.entries = append(r.entries, entry)
+		r.next = len(r.entries) % max
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+}
+
+// each calls fn for the entries from the newest to the oldest until fn returns false
+func (r *ring) each(fn func(entry interface{}) bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := 1; i <= len(r.entries); i++ {
+		if !fn(r.entries[(r.next-i+len(r.entries))%len(r.entries)]) {
+			return
+		}
+	}
+}
+
+func (r *ring) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+	r.next = 0
+}
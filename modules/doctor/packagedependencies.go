@@ -0,0 +1,71 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// checkPackageDependencies finds package versions uploaded before dependencies were recorded
+func checkPackageDependencies(ctx context.Context, logger log.Logger, autofix bool) error {
+	var count, fixed int
+	if err := db.IterateObjects(ctx, func(p *packages_model.Package) error {
+		pvs := make([]*packages_model.PackageVersion, 0, 10)
+		if err := db.GetEngine(ctx).Where("package_id = ?", p.ID).Find(&pvs); err != nil {
+			return err
+		}
+		for _, pv := range pvs {
+			has, err := packages_model.HasDependencies(ctx, pv.ID)
+			if err != nil {
+				return err
+			}
+			if has {
+				continue
+			}
+			metadata, err := packages_model.DecodeMetadata(p.Type, pv.MetadataJSON)
+			if err != nil {
+				logger.Warn("Unable to decode the metadata of %s package %s version %s: %v", p.Type, p.Name, pv.Version, err)
+				continue
+			}
+			if len(packages_model.DependenciesFromMetadata(metadata)) == 0 {
+				continue
+			}
+			count++
+			if autofix {
+				if err := packages_model.UpdateDependencies(ctx, p.Type, pv); err != nil {
+					return err
+				}
+				fixed++
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Critical("Error: %v whilst checking package dependencies", err)
+		return err
+	}
+
+	if count > 0 {
+		if autofix {
+			logger.Info("Recorded the dependencies of %d package versions", fixed)
+		} else {
+			logger.Warn("%d package versions without recorded dependencies exist", count)
+		}
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Check if the dependencies of all package versions are recorded",
+		Name:      "check-package-dependencies",
+		IsDefault: false,
+		Run:       checkPackageDependencies,
+		Priority:  7,
+	})
+}
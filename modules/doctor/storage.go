@@ -6,55 +6,295 @@ package doctor
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
 
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/storage"
+
+	"xorm.io/builder"
 )
 
-func checkAttachmentStorageFiles(logger log.Logger, autofix bool) error {
-	var total, garbageNum int
-	var deletePaths []string
-	if err := storage.Attachments.IterateObjects(func(p string, obj storage.Object) error {
+// StorageIssueType is the kind of inconsistency between the database and the storage
+type StorageIssueType string
+
+// List of storage inconsistencies
+const (
+	// StorageIssueMissing is a database record without an object in the storage
+	StorageIssueMissing StorageIssueType = "missing"
+	// StorageIssueOrphaned is an object in the storage without a database record
+	StorageIssueOrphaned StorageIssueType = "orphaned"
+	// StorageIssueSizeMismatch is an object whose size differs from the size stored in the database
+	StorageIssueSizeMismatch StorageIssueType = "size_mismatch"
+)
+
+// StorageIssue is an inconsistency found for a storage object
+type StorageIssue struct {
+	Type         StorageIssueType `json:"type"`
+	Path         string           `json:"path"`
+	ExpectedSize int64            `json:"expected_size,omitempty"`
+	ActualSize   int64            `json:"actual_size,omitempty"`
+	Fixed        bool             `json:"fixed"`
+	FixError     string           `json:"fix_error,omitempty"`
+}
+
+// StorageCategoryReport is the result of checking one storage category
+type StorageCategoryReport struct {
+	Category string          `json:"category"`
+	Records  int             `json:"records"`
+	Objects  int             `json:"objects"`
+	Issues   []*StorageIssue `json:"issues"`
+}
+
+// StorageReport is the result of a storage consistency check
+type StorageReport struct {
+	Categories []*StorageCategoryReport `json:"categories"`
+}
+
+// IssueCount returns the number of issues found in all categories
+func (r *StorageReport) IssueCount() int {
+	count := 0
+	for _, c := range r.Categories {
+		count += len(c.Issues)
+	}
+	return count
+}
+
+// storageRecord is the database side of a stored object
+type storageRecord struct {
+	// Size is the expected size of the object, -1 if the database does not know it
+	Size int64
+	// Repair fixes the database record if the object is missing or broken, nil if it can't be repaired
+	Repair func(ctx context.Context) error
+}
+
+type storageCategory struct {
+	Name    string
+	Storage func() storage.ObjectStorage
+	Records func(ctx context.Context) (map[string]*storageRecord, error)
+}
+
+// StorageCategories are the names of the storage categories which can be checked
+var StorageCategories = []string{"attachments", "avatars", "repo-avatars", "lfs", "repo-archivers", "packages"}
+
+var storageCategories = map[string]*storageCategory{
+	"attachments": {
+		Storage: func() storage.ObjectStorage { return storage.Attachments },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			return records, db.IterateObjects(ctx, func(attach *repo_model.Attachment) error {
+				records[attach.RelativePath()] = &storageRecord{Size: attach.Size}
+				return nil
+			})
+		},
+	},
+	"avatars": {
+		Storage: func() storage.ObjectStorage { return storage.Avatars },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			return records, db.Iterate(ctx, new(user_model.User), builder.Neq{"avatar": ""}, func(idx int, bean interface{}) error {
+				u := bean.(*user_model.User)
+				records[u.CustomAvatarRelativePath()] = &storageRecord{
+					Size: -1,
+					Repair: func(ctx context.Context) error {
+						u.Avatar = ""
+						u.UseCustomAvatar = false
+						return user_model.UpdateUserCols(ctx, u, "avatar", "use_custom_avatar")
+					},
+				}
+				return nil
+			})
+		},
+	},
+	"repo-avatars": {
+		Storage: func() storage.ObjectStorage { return storage.RepoAvatars },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			return records, db.Iterate(ctx, new(repo_model.Repository), builder.Neq{"avatar": ""}, func(idx int, bean interface{}) error {
+				repo := bean.(*repo_model.Repository)
+				records[repo.CustomAvatarRelativePath()] = &storageRecord{
+					Size: -1,
+					Repair: func(ctx context.Context) error {
+						repo.Avatar = ""
+						return repo_model.UpdateRepositoryCols(ctx, repo, "avatar")
+					},
+				}
+				return nil
+			})
+		},
+	},
+	"lfs": {
+		Storage: func() storage.ObjectStorage { return storage.LFS },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			return records, db.IterateObjects(ctx, func(mo *git_model.LFSMetaObject) error {
+				// the same object can be referenced by several repositories
+				records[mo.RelativePath()] = &storageRecord{Size: mo.Size}
+				return nil
+			})
+		},
+	},
+	"repo-archivers": {
+		Storage: func() storage.ObjectStorage { return storage.RepoArchives },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			// archives which are still generating or have failed are not expected to be stored
+			return records, db.Iterate(ctx, new(repo_model.RepoArchiver), builder.Eq{"status": repo_model.ArchiverReady}, func(idx int, bean interface{}) error {
+				archiver := bean.(*repo_model.RepoArchiver)
+				records[archiver.RelativePath()] = &storageRecord{
+					Size: -1,
+					Repair: func(ctx context.Context) error {
+						// the archive is generated again on the next request
+						return repo_model.DeleteRepoArchiver(ctx, archiver)
+					},
+				}
+				return nil
+			})
+		},
+	},
+	"packages": {
+		Storage: func() storage.ObjectStorage { return storage.Packages },
+		Records: func(ctx context.Context) (map[string]*storageRecord, error) {
+			records := make(map[string]*storageRecord)
+			return records, db.IterateObjects(ctx, func(pb *packages_model.PackageBlob) error {
+				records[packages_module.KeyToRelativePath(packages_module.BlobHash256Key(pb.HashSHA256))] = &storageRecord{Size: pb.Size}
+				return nil
+			})
+		},
+	},
+}
+
+// checkStorageObjects compares the records with the objects of the storage.
+// If autofix is set, orphaned objects are deleted and the records of missing objects are repaired if possible.
+func checkStorageObjects(ctx context.Context, store storage.ObjectStorage, records map[string]*storageRecord, autofix bool) (*StorageCategoryReport, error) {
+	report := &StorageCategoryReport{
+		Records: len(records),
+		Issues:  []*StorageIssue{},
+	}
+
+	seen := make(map[string]bool, len(records))
+	var orphaned []*StorageIssue
+	if err := store.IterateObjects(func(p string, obj storage.Object) error {
 		defer obj.Close()
 
-		total++
-		stat, err := obj.Stat()
-		if err != nil {
-			return err
+		report.Objects++
+		p = filepath.ToSlash(p)
+
+		record, has := records[p]
+		if !has {
+			orphaned = append(orphaned, &StorageIssue{Type: StorageIssueOrphaned, Path: p})
+			return nil
+		}
+		seen[p] = true
+
+		if record.Size < 0 {
+			return nil
 		}
-		exist, err := repo_model.ExistAttachmentsByUUID(stat.Name())
+		stat, err := obj.Stat()
 		if err != nil {
 			return err
 		}
-		if !exist {
-			garbageNum++
-			if autofix {
-				deletePaths = append(deletePaths, p)
-			}
+		if stat.Size() != record.Size {
+			report.Issues = append(report.Issues, &StorageIssue{
+				Type:         StorageIssueSizeMismatch,
+				Path:         p,
+				ExpectedSize: record.Size,
+				ActualSize:   stat.Size(),
+			})
 		}
 		return nil
 	}); err != nil {
-		logger.Error("storage.Attachments.IterateObjects failed: %v", err)
-		return err
+		return nil, err
 	}
 
-	if garbageNum > 0 {
-		if autofix {
-			var deletedNum int
-			for _, p := range deletePaths {
-				if err := storage.Attachments.Delete(p); err != nil {
-					log.Error("Delete attachment %s failed: %v", p, err)
-				} else {
-					deletedNum++
-				}
+	for p, record := range records {
+		if !seen[p] {
+			report.Issues = append(report.Issues, &StorageIssue{
+				Type:         StorageIssueMissing,
+				Path:         p,
+				ExpectedSize: record.Size,
+			})
+		}
+	}
+	report.Issues = append(report.Issues, orphaned...)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Type != report.Issues[j].Type {
+			return report.Issues[i].Type < report.Issues[j].Type
+		}
+		return report.Issues[i].Path < report.Issues[j].Path
+	})
+
+	if !autofix {
+		return report, nil
+	}
+
+	for _, issue := range report.Issues {
+		var err error
+		switch issue.Type {
+		case StorageIssueOrphaned:
+			err = store.Delete(issue.Path)
+		case StorageIssueSizeMismatch:
+			// only records which can be recreated are repaired, the broken object is removed together with them
+			if records[issue.Path].Repair == nil {
+				continue
 			}
-			logger.Info("%d missed information attachment detected, %d deleted.", garbageNum, deletedNum)
-		} else {
-			logger.Warn("Checked %d attachment, %d missed information.", total, garbageNum)
+			if err = store.Delete(issue.Path); err == nil {
+				err = records[issue.Path].Repair(ctx)
+			}
+		case StorageIssueMissing:
+			if records[issue.Path].Repair == nil {
+				continue
+			}
+			err = records[issue.Path].Repair(ctx)
 		}
+		if err != nil {
+			issue.FixError = err.Error()
+			continue
+		}
+		issue.Fixed = true
 	}
-	return nil
+	return report, nil
+}
+
+// CheckStorageConsistency cross-checks the database records against the objects of the given storage categories
+// (all if none are given) and reports missing, orphaned and size mismatched objects.
+// If autofix is set, orphaned objects are deleted. Records of missing avatars are reset and records of missing or broken
+// repository archives are removed so the archives are generated again. Missing attachments, LFS objects and package
+// blobs can't be repaired and are only reported.
+func CheckStorageConsistency(ctx context.Context, categories []string, autofix bool) (*StorageReport, error) {
+	if len(categories) == 0 {
+		categories = StorageCategories
+	}
+
+	report := &StorageReport{
+		Categories: make([]*StorageCategoryReport, 0, len(categories)),
+	}
+	for _, name := range categories {
+		category, ok := storageCategories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown storage category: %s", name)
+		}
+
+		records, err := category.Records(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the %s records: %w", name, err)
+		}
+		categoryReport, err := checkStorageObjects(ctx, category.Storage(), records, autofix)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check the %s storage: %w", name, err)
+		}
+		categoryReport.Category = name
+		report.Categories = append(report.Categories, categoryReport)
+	}
+	return report, nil
 }
 
 func checkStorageFiles(ctx context.Context, logger log.Logger, autofix bool) error {
@@ -62,12 +302,43 @@ func checkStorageFiles(ctx context.Context, logger log.Logger, autofix bool) err
 		logger.Error("storage.Init failed: %v", err)
 		return err
 	}
-	return checkAttachmentStorageFiles(logger, autofix)
+
+	report, err := CheckStorageConsistency(ctx, nil, autofix)
+	if err != nil {
+		logger.Error("Storage consistency check failed: %v", err)
+		return err
+	}
+
+	for _, category := range report.Categories {
+		counts := make(map[StorageIssueType]int)
+		fixed := 0
+		for _, issue := range category.Issues {
+			counts[issue.Type]++
+			if issue.Fixed {
+				fixed++
+			} else if issue.FixError != "" {
+				logger.Error("Unable to fix %s %s object %s: %s", issue.Type, category.Category, issue.Path, issue.FixError)
+			}
+		}
+		if len(category.Issues) == 0 {
+			continue
+		}
+		if autofix {
+			logger.Info("Checked %d %s records and %d objects: %d missing, %d orphaned, %d size mismatches, %d fixed.",
+				category.Records, category.Category, category.Objects,
+				counts[StorageIssueMissing], counts[StorageIssueOrphaned], counts[StorageIssueSizeMismatch], fixed)
+		} else {
+			logger.Warn("Checked %d %s records and %d objects: %d missing, %d orphaned, %d size mismatches.",
+				category.Records, category.Category, category.Objects,
+				counts[StorageIssueMissing], counts[StorageIssueOrphaned], counts[StorageIssueSizeMismatch])
+		}
+	}
+	return nil
 }
 
 func init() {
 	Register(&Check{
-		Title:                      "Check if there is garbage storage files",
+		Title:                      "Check if database records and storage objects are consistent",
 		Name:                       "storages",
 		IsDefault:                  false,
 		Run:                        checkStorageFiles,
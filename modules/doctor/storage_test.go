@@ -0,0 +1,84 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStorageObjects(t *testing.T) {
+	store, err := storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+
+	for p, content := range map[string]string{
+		"aa/bb/ok":       "12345",
+		"aa/bb/unknown":  "1234",
+		"aa/cc/mismatch": "123",
+		"aa/cc/broken":   "12",
+	} {
+		_, err := store.Save(p, strings.NewReader(content), int64(len(content)))
+		assert.NoError(t, err)
+	}
+
+	repaired := make(map[string]bool)
+	repair := func(p string) func(context.Context) error {
+		return func(context.Context) error {
+			repaired[p] = true
+			return nil
+		}
+	}
+	records := map[string]*storageRecord{
+		"aa/bb/ok":          {Size: 5},
+		"aa/cc/mismatch":    {Size: 5},
+		"aa/cc/broken":      {Size: 5, Repair: repair("aa/cc/broken")},
+		"aa/dd/missing":     {Size: 1},
+		"aa/dd/repairable":  {Size: -1, Repair: repair("aa/dd/repairable")},
+		"aa/dd/unknownsize": {Size: -1},
+	}
+	_, err = store.Save("aa/dd/unknownsize", strings.NewReader("1"), 1)
+	assert.NoError(t, err)
+
+	report, err := checkStorageObjects(context.Background(), store, records, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.Records)
+	assert.Equal(t, 5, report.Objects)
+	assert.Equal(t, []*StorageIssue{
+		{Type: StorageIssueMissing, Path: "aa/dd/missing", ExpectedSize: 1},
+		{Type: StorageIssueMissing, Path: "aa/dd/repairable", ExpectedSize: -1},
+		{Type: StorageIssueOrphaned, Path: "aa/bb/unknown"},
+		{Type: StorageIssueSizeMismatch, Path: "aa/cc/broken", ExpectedSize: 5, ActualSize: 2},
+		{Type: StorageIssueSizeMismatch, Path: "aa/cc/mismatch", ExpectedSize: 5, ActualSize: 3},
+	}, report.Issues)
+	assert.Empty(t, repaired)
+
+	report, err = checkStorageObjects(context.Background(), store, records, true)
+	assert.NoError(t, err)
+	fixed := make(map[string]bool)
+	for _, issue := range report.Issues {
+		assert.Empty(t, issue.FixError)
+		fixed[issue.Path] = issue.Fixed
+	}
+	assert.Equal(t, map[string]bool{
+		"aa/dd/missing":    false,
+		"aa/dd/repairable": true,
+		"aa/bb/unknown":    true,
+		"aa/cc/broken":     true,
+		"aa/cc/mismatch":   false,
+	}, fixed)
+	assert.Equal(t, map[string]bool{"aa/cc/broken": true, "aa/dd/repairable": true}, repaired)
+
+	_, err = store.Stat("aa/bb/unknown")
+	assert.Error(t, err)
+	_, err = store.Stat("aa/cc/broken")
+	assert.Error(t, err)
+	_, err = store.Stat("aa/cc/mismatch")
+	assert.NoError(t, err)
+}
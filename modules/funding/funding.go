@@ -0,0 +1,102 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package funding
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/validation"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FileCandidates are the paths of the funding file relative to the repository root
+var FileCandidates = []string{
+	".gitea/FUNDING.yml",
+	".gitea/FUNDING.yaml",
+	".github/FUNDING.yml",
+	".github/FUNDING.yaml",
+}
+
+// platform represents a funding platform which links to an account name
+type platform struct {
+	Key  string
+	Name string
+	URL  string
+}
+
+var platforms = []platform{
+	{Key: "github", Name: "GitHub Sponsors", URL: "https://github.com/sponsors/%s"},
+	{Key: "liberapay", Name: "Liberapay", URL: "https://liberapay.com/%s"},
+	{Key: "ko_fi", Name: "Ko-fi", URL: "https://ko-fi.com/%s"},
+	{Key: "open_collective", Name: "Open Collective", URL: "https://opencollective.com/%s"},
+	{Key: "patreon", Name: "Patreon", URL: "https://www.patreon.com/%s"},
+}
+
+// Link represents a funding link
+type Link struct {
+	Name string
+	URL  string
+}
+
+// Parse parses a FUNDING.yml file. Every entry maps a platform to one or more account names,
+// the "custom" entry contains arbitrary URLs.
+func Parse(data []byte) ([]*Link, error) {
+	var entries map[string]interface{}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	links := make([]*Link, 0, len(entries))
+	for _, p := range platforms {
+		for _, name := range toStrings(entries[p.Key]) {
+			links = append(links, &Link{
+				Name: p.Name,
+				URL:  fmt.Sprintf(p.URL, url.PathEscape(name)),
+			})
+		}
+	}
+	for _, u := range toStrings(entries["custom"]) {
+		if !validation.IsValidURL(u) {
+			continue
+		}
+		name := u
+		if parsed, err := url.Parse(u); err == nil {
+			name = parsed.Host
+		}
+		links = append(links, &Link{
+			Name: name,
+			URL:  u,
+		})
+	}
+	return links, nil
+}
+
+// toStrings converts a single value or a list of values to a list of non-empty strings
+func toStrings(v interface{}) []string {
+	var values []interface{}
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values = t
+	default:
+		values = []interface{}{t}
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
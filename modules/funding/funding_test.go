@@ -0,0 +1,35 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package funding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	links, err := Parse([]byte(`github: [octocat, "sur name"]
+patreon: gitea
+custom:
+  - https://example.com/donate
+  - not a url
+unknown: value
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*Link{
+		{Name: "GitHub Sponsors", URL: "https://github.com/sponsors/octocat"},
+		{Name: "GitHub Sponsors", URL: "https://github.com/sponsors/sur%20name"},
+		{Name: "Patreon", URL: "https://www.patreon.com/gitea"},
+		{Name: "example.com", URL: "https://example.com/donate"},
+	}, links)
+
+	links, err = Parse([]byte(""))
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = Parse([]byte("github: [unclosed"))
+	assert.Error(t, err)
+}
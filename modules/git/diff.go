@@ -317,3 +317,84 @@ func GetAffectedFiles(repo *Repository, oldCommitID, newCommitID string, env []s
 
 	return affectedFiles, err
 }
+
+// ChangedBlob represents a regular file added or modified between two commits
+type ChangedBlob struct {
+	Path string
+	ID   string
+	Size int64
+}
+
+// GetChangedBlobs returns the regular files added or modified between two commits.
+// Deleted files, symlinks and submodules are omitted. oldCommitID may be EmptyTreeSHA to list all files.
+func GetChangedBlobs(repo *Repository, oldCommitID, newCommitID string, env []string) ([]*ChangedBlob, error) {
+	stdout, _, err := NewCommand(repo.Ctx, "diff-tree", "-r", "-z", "--no-renames", oldCommitID, newCommitID).RunStdBytes(&RunOpts{
+		Env: env,
+		Dir: repo.Path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the output consists of pairs of ":<old mode> <new mode> <old sha> <new sha> <status>" and <path>
+	fields := bytes.Split(bytes.TrimSuffix(stdout, []byte{0}), []byte{0})
+	blobs := make([]*ChangedBlob, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		info := strings.Fields(string(fields[i]))
+		if len(info) != 5 {
+			return nil, fmt.Errorf("unexpected diff-tree output: %s", fields[i])
+		}
+		newMode, newID, status := info[1], info[3], info[4]
+		if status == "D" || (newMode != "100644" && newMode != "100755") {
+			continue
+		}
+		blobs = append(blobs, &ChangedBlob{
+			Path: string(fields[i+1]),
+			ID:   newID,
+		})
+	}
+	if len(blobs) == 0 {
+		return blobs, nil
+	}
+
+	// query the sizes of all blobs at once
+	var input strings.Builder
+	for _, blob := range blobs {
+		input.WriteString(blob.ID)
+		input.WriteByte('\n')
+	}
+	stdout, _, err = NewCommand(repo.Ctx, "cat-file", "--batch-check").RunStdBytes(&RunOpts{
+		Env:   env,
+		Dir:   repo.Path,
+		Stdin: strings.NewReader(input.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(stdout)), "\n")
+	if len(lines) != len(blobs) {
+		return nil, fmt.Errorf("unexpected cat-file output for %d blobs: %d lines", len(blobs), len(lines))
+	}
+	for i, line := range lines {
+		// <sha> blob <size>
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected cat-file output: %s", line)
+		}
+		size, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		blobs[i].Size = size
+	}
+	return blobs, nil
+}
+
+// ReadChangedBlob reads the content of a blob returned by GetChangedBlobs
+func ReadChangedBlob(repo *Repository, blob *ChangedBlob, env []string) ([]byte, error) {
+	stdout, _, err := NewCommand(repo.Ctx, "cat-file", "blob", blob.ID).RunStdBytes(&RunOpts{
+		Env: env,
+		Dir: repo.Path,
+	})
+	return stdout, err
+}
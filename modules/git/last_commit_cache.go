@@ -7,6 +7,7 @@ package git
 import (
 	"crypto/sha256"
 	"fmt"
+	"path"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -60,14 +61,20 @@ func (c *LastCommitCache) Put(ref, entryPath, commitID string) error {
 	return c.cache.Put(getCacheKey(c.repoPath, ref, entryPath), commitID, c.ttl())
 }
 
-// Get gets the last commit information by commit id and entry path
-func (c *LastCommitCache) Get(ref, entryPath string) (*Commit, error) {
+// getID gets the cached id of the last commit by commit id and entry path
+func (c *LastCommitCache) getID(ref, entryPath string) string {
 	if c == nil || c.cache == nil {
-		return nil, nil
+		return ""
 	}
 
-	commitID, ok := c.cache.Get(getCacheKey(c.repoPath, ref, entryPath)).(string)
-	if !ok || commitID == "" {
+	commitID, _ := c.cache.Get(getCacheKey(c.repoPath, ref, entryPath)).(string)
+	return commitID
+}
+
+// Get gets the last commit information by commit id and entry path
+func (c *LastCommitCache) Get(ref, entryPath string) (*Commit, error) {
+	commitID := c.getID(ref, entryPath)
+	if commitID == "" {
 		return nil, nil
 	}
 
@@ -113,3 +120,63 @@ func (c *LastCommitCache) GetCommitByPath(commitID, entryPath string) (*Commit,
 
 	return lastCommit, nil
 }
+
+// inheritFromParent caches the last commits of the entries which can be derived from the first parent of the commit
+// without walking the history: entries unchanged from the parent inherit its cached last commit and entries changed
+// by a non-merge commit have the commit itself as last commit. The names of the unresolved entries are returned.
+func (c *LastCommitCache) inheritFromParent(commit *Commit, treePath string, entries Entries) ([]string, error) {
+	unresolved := make([]string, 0, len(entries))
+	if c == nil || c.cache == nil || commit.ParentCount() == 0 {
+		for _, entry := range entries {
+			unresolved = append(unresolved, entry.Name())
+		}
+		return unresolved, nil
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, err
+	}
+
+	parentIDs := make(map[string]SHA1)
+	parentTree, err := parent.SubTree(treePath)
+	if err != nil && !IsErrNotExist(err) {
+		return nil, err
+	}
+	if parentTree != nil {
+		parentEntries, err := parentTree.ListEntries()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range parentEntries {
+			parentIDs[entry.Name()] = entry.ID
+		}
+	}
+
+	commitID := commit.ID.String()
+	parentID := parent.ID.String()
+
+	for _, entry := range entries {
+		entryPath := path.Join(treePath, entry.Name())
+
+		lastCommitID := ""
+		if id, ok := parentIDs[entry.Name()]; ok && id == entry.ID {
+			lastCommitID = c.getID(parentID, entryPath)
+		} else if commit.ParentCount() == 1 {
+			lastCommitID = commitID
+		}
+
+		if lastCommitID == "" {
+			unresolved = append(unresolved, entry.Name())
+			continue
+		}
+
+		if err := c.Put(commitID, entryPath, lastCommitID); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debug("LastCommitCache inherited %d of %d entries of %q from parent %s", len(entries)-len(unresolved), len(entries), treePath, parentID)
+
+	return unresolved, nil
+}
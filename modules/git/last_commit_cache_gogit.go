@@ -37,25 +37,24 @@ func (c *Commit) recursiveCache(ctx context.Context, index cgobject.CommitNode,
 		return err
 	}
 
-	entryPaths := make([]string, len(entries))
-	entryMap := make(map[string]*TreeEntry)
-	for i, entry := range entries {
-		entryPaths[i] = entry.Name()
-		entryMap[entry.Name()] = entry
-	}
-
-	commits, err := GetLastCommitForPaths(ctx, c.repo.LastCommitCache, index, treePath, entryPaths)
+	entryPaths, err := c.repo.LastCommitCache.inheritFromParent(c, treePath, entries)
 	if err != nil {
 		return err
 	}
 
-	for entry := range commits {
-		if entryMap[entry].IsDir() {
-			subTree, err := tree.SubTree(entry)
+	if len(entryPaths) > 0 {
+		if _, err := GetLastCommitForPaths(ctx, c.repo.LastCommitCache, index, treePath, entryPaths); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subTree, err := tree.SubTree(entry.Name())
 			if err != nil {
 				return err
 			}
-			if err := c.recursiveCache(ctx, index, subTree, entry, level-1); err != nil {
+			if err := c.recursiveCache(ctx, index, subTree, entry.Name(), level-1); err != nil {
 				return err
 			}
 		}
@@ -28,16 +28,17 @@ func (c *Commit) recursiveCache(ctx context.Context, tree *Tree, treePath string
 		return err
 	}
 
-	entryPaths := make([]string, len(entries))
-	for i, entry := range entries {
-		entryPaths[i] = entry.Name()
-	}
-
-	_, err = WalkGitLog(ctx, c.repo, c, treePath, entryPaths...)
+	entryPaths, err := c.repo.LastCommitCache.inheritFromParent(c, treePath, entries)
 	if err != nil {
 		return err
 	}
 
+	if len(entryPaths) > 0 {
+		if _, err := WalkGitLog(ctx, c.repo, c, treePath, entryPaths...); err != nil {
+			return err
+		}
+	}
+
 	for _, treeEntry := range entries {
 		// entryMap won't contain "" therefore skip this.
 		if treeEntry.IsDir() {
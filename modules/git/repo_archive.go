@@ -11,6 +11,9 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 // ArchiveType archive types
@@ -23,6 +26,10 @@ const (
 	TARGZ
 	// BUNDLE bundle archive type
 	BUNDLE
+	// TARZST tar zst archive type
+	TARZST
+	// TARXZ tar xz archive type
+	TARXZ
 )
 
 // String converts an ArchiveType to string
@@ -34,6 +41,10 @@ func (a ArchiveType) String() string {
 		return "tar.gz"
 	case BUNDLE:
 		return "bundle"
+	case TARZST:
+		return "tar.zst"
+	case TARXZ:
+		return "tar.xz"
 	}
 	return "unknown"
 }
@@ -51,17 +62,47 @@ func (repo *Repository) CreateArchive(ctx context.Context, format ArchiveType, t
 		args = append(args, "--prefix="+filepath.Base(strings.TrimSuffix(repo.Path, ".git"))+"/")
 	}
 
+	// git archive has no built-in support for zstd and xz, the tarball gets compressed while it is streamed
+	gitFormat := format.String()
+	var compressor io.WriteCloser
+	switch format {
+	case TARZST:
+		gitFormat = "tar"
+		zw, err := zstd.NewWriter(target)
+		if err != nil {
+			return err
+		}
+		compressor = zw
+	case TARXZ:
+		gitFormat = "tar"
+		xw, err := xz.NewWriter(target)
+		if err != nil {
+			return err
+		}
+		compressor = xw
+	}
+
 	args = append(args,
-		"--format="+format.String(),
+		"--format="+gitFormat,
 		commitID,
 	)
 
+	stdout := target
+	if compressor != nil {
+		stdout = compressor
+	}
+
 	var stderr strings.Builder
 	err := NewCommand(ctx, args...).Run(&RunOpts{
 		Dir:    repo.Path,
-		Stdout: target,
+		Stdout: stdout,
 		Stderr: &stderr,
 	})
+	if compressor != nil {
+		if closeErr := compressor.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	if err != nil {
 		return ConcatenateError(err, stderr.String())
 	}
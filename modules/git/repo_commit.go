@@ -436,7 +436,8 @@ func (repo *Repository) IsCommitInBranch(commitID, branch string) (r bool, err e
 	return len(stdout) > 0, err
 }
 
-func (repo *Repository) AddLastCommitCache(cacheKey, fullName, sha string) error {
+// AddLastCommitCache adds a last commit cache backed by the store to the repository if it has enough commits
+func (repo *Repository) AddLastCommitCache(cacheKey, fullName, sha string, store Cache) error {
 	if repo.LastCommitCache == nil {
 		commitsCount, err := cache.GetInt64(cacheKey, func() (int64, error) {
 			commit, err := repo.GetCommit(sha)
@@ -448,7 +449,7 @@ func (repo *Repository) AddLastCommitCache(cacheKey, fullName, sha string) error
 		if err != nil {
 			return err
 		}
-		repo.LastCommitCache = NewLastCommitCache(commitsCount, fullName, repo, cache.GetCache())
+		repo.LastCommitCache = NewLastCommitCache(commitsCount, fullName, repo, store)
 	}
 	return nil
 }
@@ -199,6 +199,105 @@ func GetDiffShortStat(ctx context.Context, repoPath string, args ...string) (num
 	return parseDiffStat(stdout)
 }
 
+// DiffFileStat represents the changes of a single file of a diff
+type DiffFileStat struct {
+	Name      string
+	OldName   string
+	Status    string // added, deleted, modified, renamed, copied or changed
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// GetDiffFileStats returns the changed files with their number of additions and deletions.
+// The patch itself is not generated, so this stays cheap for diffs with many files.
+func GetDiffFileStats(ctx context.Context, repoPath string, args ...string) ([]*DiffFileStat, error) {
+	args = append([]string{
+		"diff",
+		"--raw",
+		"--numstat",
+		"-z",
+		"-M",
+	}, args...)
+
+	stdout, _, err := NewCommand(ctx, args...).RunStdBytes(&RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDiffFileStats(stdout)
+}
+
+// parseDiffFileStats parses the output of `git diff --raw --numstat -z`: all the raw
+// records come first, followed by the numstat records in the same order
+func parseDiffFileStats(stdout []byte) ([]*DiffFileStat, error) {
+	fields := bytes.Split(bytes.TrimSuffix(stdout, []byte{0}), []byte{0})
+	stats := make([]*DiffFileStat, 0, len(fields)/4)
+	numStats := 0
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) == 0 {
+			continue
+		}
+
+		if field[0] == ':' {
+			// ":<old mode> <new mode> <old sha> <new sha> <status>" followed by the path, or by the old and the new path
+			parts := bytes.Fields(field)
+			if len(parts) != 5 || i+1 >= len(fields) {
+				return nil, fmt.Errorf("unable to parse raw diff record: %q", field)
+			}
+			i++
+			stat := &DiffFileStat{Name: string(fields[i])}
+			switch parts[4][0] {
+			case 'A':
+				stat.Status = "added"
+			case 'D':
+				stat.Status = "deleted"
+			case 'R', 'C':
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("unable to parse raw diff record: %q", field)
+				}
+				i++
+				stat.OldName = stat.Name
+				stat.Name = string(fields[i])
+				stat.Status = "renamed"
+				if parts[4][0] == 'C' {
+					stat.Status = "copied"
+				}
+			case 'T':
+				stat.Status = "changed"
+			default:
+				stat.Status = "modified"
+			}
+			stats = append(stats, stat)
+			continue
+		}
+
+		// "<additions>\t<deletions>\t<path>", the path is empty for renames and copies and followed by the old and the new path
+		parts := bytes.SplitN(field, []byte{'\t'}, 3)
+		if len(parts) != 3 || numStats >= len(stats) {
+			return nil, fmt.Errorf("unable to parse numstat record: %q", field)
+		}
+		stat := stats[numStats]
+		numStats++
+		if string(parts[0]) == "-" {
+			stat.IsBinary = true
+		} else {
+			var err error
+			if stat.Additions, err = strconv.Atoi(string(parts[0])); err != nil {
+				return nil, fmt.Errorf("unable to parse numstat record: %q", field)
+			}
+			if stat.Deletions, err = strconv.Atoi(string(parts[1])); err != nil {
+				return nil, fmt.Errorf("unable to parse numstat record: %q", field)
+			}
+		}
+		if len(parts[2]) == 0 {
+			i += 2
+		}
+	}
+	return stats, nil
+}
+
 var shortStatFormat = regexp.MustCompile(
 	`\s*(\d+) files? changed(?:, (\d+) insertions?\(\+\))?(?:, (\d+) deletions?\(-\))?`)
 
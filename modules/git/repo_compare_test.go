@@ -124,3 +124,30 @@ func TestReadWritePullHead(t *testing.T) {
 	err = repo.RemoveReference(PullPrefix + "1/head")
 	assert.NoError(t, err)
 }
+
+func TestParseDiffFileStats(t *testing.T) {
+	stdout := ":100644 100644 1111111 2222222 M\x00README.md\x00" +
+		":000000 100644 0000000 3333333 A\x00docs/new.md\x00" +
+		":100644 100644 4444444 5555555 R090\x00old.go\x00new.go\x00" +
+		":100644 000000 6666666 0000000 D\x00image.png\x00" +
+		"3\t1\tREADME.md\x00" +
+		"10\t0\tdocs/new.md\x00" +
+		"2\t2\t\x00old.go\x00new.go\x00" +
+		"-\t-\timage.png\x00"
+
+	stats, err := parseDiffFileStats([]byte(stdout))
+	assert.NoError(t, err)
+	assert.Equal(t, []*DiffFileStat{
+		{Name: "README.md", Status: "modified", Additions: 3, Deletions: 1},
+		{Name: "docs/new.md", Status: "added", Additions: 10},
+		{Name: "new.go", OldName: "old.go", Status: "renamed", Additions: 2, Deletions: 2},
+		{Name: "image.png", Status: "deleted", IsBinary: true},
+	}, stats)
+
+	stats, err = parseDiffFileStats(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	_, err = parseDiffFileStats([]byte("3\t1\tREADME.md\x00"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"sort"
+	"strings"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/validation"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFileCandidates are the names of the issue template chooser configuration inside of an issue template directory
+var ConfigFileCandidates = []string{
+	"config.yml",
+	"config.yaml",
+}
+
+// DefaultConfig returns the configuration used if a repository has no config file
+func DefaultConfig() api.IssueConfig {
+	return api.IssueConfig{
+		BlankIssuesEnabled: true,
+		ContactLinks:       []api.IssueConfigContactLink{},
+		Order:              []string{},
+	}
+}
+
+// ParseConfig parses the issue template chooser configuration, invalid contact links are ignored
+func ParseConfig(content []byte) (api.IssueConfig, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return DefaultConfig(), err
+	}
+
+	links := make([]api.IssueConfigContactLink, 0, len(config.ContactLinks))
+	for _, link := range config.ContactLinks {
+		link.Name = strings.TrimSpace(link.Name)
+		link.URL = strings.TrimSpace(link.URL)
+		link.About = strings.TrimSpace(link.About)
+		if link.Name == "" || !validation.IsValidURL(link.URL) {
+			continue
+		}
+		links = append(links, link)
+	}
+	config.ContactLinks = links
+
+	if config.Order == nil {
+		config.Order = []string{}
+	}
+
+	return config, nil
+}
+
+// SortTemplates sorts the templates by the order of the configuration,
+// templates which are not listed keep their order and follow the listed ones
+func SortTemplates(templates []api.IssueTemplate, config api.IssueConfig) {
+	if len(config.Order) == 0 {
+		return
+	}
+
+	position := make(map[string]int, len(config.Order))
+	for i, fileName := range config.Order {
+		if _, has := position[fileName]; !has {
+			position[fileName] = i
+		}
+	}
+
+	index := func(it api.IssueTemplate) int {
+		if i, has := position[it.FileName]; has {
+			return i
+		}
+		return len(config.Order)
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		return index(templates[i]) < index(templates[j])
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(""))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), config)
+
+	config, err = ParseConfig([]byte(`blank_issues_enabled: false
+contact_links:
+  - name: Security issues
+    url: https://example.com/security
+    about: Please report security issues here
+  - name: Invalid URL
+    url: not a url
+  - url: https://example.com/missing-name
+order:
+  - feature.md
+  - bug.md
+`))
+	assert.NoError(t, err)
+	assert.False(t, config.BlankIssuesEnabled)
+	assert.Equal(t, []api.IssueConfigContactLink{
+		{Name: "Security issues", URL: "https://example.com/security", About: "Please report security issues here"},
+	}, config.ContactLinks)
+	assert.Equal(t, []string{"feature.md", "bug.md"}, config.Order)
+
+	config, err = ParseConfig([]byte("blank_issues_enabled: [invalid"))
+	assert.Error(t, err)
+	assert.Equal(t, DefaultConfig(), config)
+}
+
+func TestSortTemplates(t *testing.T) {
+	templates := []api.IssueTemplate{
+		{FileName: "a.md"},
+		{FileName: "b.md"},
+		{FileName: "c.md"},
+		{FileName: "d.md"},
+	}
+
+	SortTemplates(templates, api.IssueConfig{Order: []string{"c.md", "missing.md", "a.md"}})
+
+	names := make([]string, 0, len(templates))
+	for _, it := range templates {
+		names = append(names, it.FileName)
+	}
+	assert.Equal(t, []string{"c.md", "a.md", "b.md", "d.md"}, names)
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markdown
+
+import (
+	"bytes"
+	"sync"
+
+	giteautil "code.gitea.io/gitea/modules/util"
+
+	"github.com/yuin/goldmark"
+	meta "github.com/yuin/goldmark-meta"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	taskParser     parser.Parser
+	taskParserOnce sync.Once
+)
+
+// Task is an item of a task list
+type Task struct {
+	// Index is the position of the task in the document, starting at 0
+	Index int
+	// Offset is the byte offset of the checkbox ("[ ]" or "[x]") in the normalized content
+	Offset  int
+	Checked bool
+	// Text is the markdown source of the first line of the item without the checkbox
+	Text string
+}
+
+// ParseTasks returns the task list items of the markdown content.
+// Items in code blocks or in the front matter are ignored, the same as when rendering the content.
+func ParseTasks(content string) []*Task {
+	taskParserOnce.Do(func() {
+		taskParser = goldmark.New(
+			goldmark.WithExtensions(extension.TaskList, meta.Meta),
+		).Parser()
+	})
+
+	source := giteautil.NormalizeEOL([]byte(content))
+	doc := taskParser.Parse(text.NewReader(source))
+
+	tasks := make([]*Task, 0, 5)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		checkBox, ok := n.(*east.TaskCheckBox)
+		// the checkbox is only rendered if it is the start of the list item
+		if !ok || n.Parent().FirstChild() != n || n.Parent().Lines().Len() == 0 {
+			return ast.WalkContinue, nil
+		}
+		segment := n.Parent().Lines().At(0)
+		line := source[segment.Start:segment.Stop]
+		tasks = append(tasks, &Task{
+			Index:   len(tasks),
+			Offset:  segment.Start,
+			Checked: checkBox.IsChecked,
+			Text:    string(bytes.TrimSpace(line[3:])),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return tasks
+}
+
+// CountTasks returns the number of task list items and of the completed ones
+func CountTasks(content string) (total, completed int) {
+	tasks := ParseTasks(content)
+	for _, task := range tasks {
+		if task.Checked {
+			completed++
+		}
+	}
+	return len(tasks), completed
+}
+
+// SetTaskChecked changes the checkbox of the task parsed from the content.
+// The returned content uses normalized line endings.
+func SetTaskChecked(content string, task *Task, checked bool) string {
+	source := giteautil.NormalizeEOL([]byte(content))
+	mark := byte(' ')
+	if checked {
+		mark = 'x'
+	}
+	source[task.Offset+1] = mark
+	return string(source)
+}
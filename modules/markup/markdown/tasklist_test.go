@@ -0,0 +1,48 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTasks(t *testing.T) {
+	content := "---\n- [ ] front matter\n---\nintro\r\n\r\n- [ ] one\n- [x] two `x`\n  * [X] nested\n\n```\n- [ ] code\n```\n> - [ ] quoted\n- no [x] task\n"
+
+	tasks := ParseTasks(content)
+	assert.Len(t, tasks, 4)
+
+	expected := []struct {
+		Checked bool
+		Text    string
+	}{
+		{false, "one"},
+		{true, "two `x`"},
+		{true, "nested"},
+		{false, "quoted"},
+	}
+	for i, e := range expected {
+		assert.Equal(t, i, tasks[i].Index)
+		assert.Equal(t, e.Checked, tasks[i].Checked)
+		assert.Equal(t, e.Text, tasks[i].Text)
+	}
+
+	total, completed := CountTasks(content)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, 2, completed)
+
+	changed := SetTaskChecked(content, tasks[0], true)
+	assert.Equal(t, "---\n- [ ] front matter\n---\nintro\n\n- [x] one\n- [x] two `x`\n  * [X] nested\n\n```\n- [ ] code\n```\n> - [ ] quoted\n- no [x] task\n", changed)
+
+	changed = SetTaskChecked(changed, ParseTasks(changed)[2], false)
+	total, completed = CountTasks(changed)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, 2, completed)
+	assert.False(t, ParseTasks(changed)[2].Checked)
+
+	assert.Empty(t, ParseTasks("- item\n- [link](https://gitea.io)"))
+}
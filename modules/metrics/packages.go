@@ -0,0 +1,181 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PackageRequestsThrottled counts the package registry requests rejected by the rate limit.
+	// The "auth" label is "anonymous" for requests limited per IP and "authenticated" for requests limited per token or user.
+	PackageRequestsThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "package_requests_throttled_total",
+			Help: "Number of package registry requests rejected by the rate limit",
+		},
+		[]string{"auth"},
+	)
+
+	// PackageUploads counts the uploaded package files by package type
+	PackageUploads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "package_uploads_total",
+			Help: "Number of uploaded package files",
+		},
+		[]string{"type"},
+	)
+
+	// PackageBlobDedupHits counts the uploaded package files whose content was stored already
+	PackageBlobDedupHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: namespace + "package_blob_dedup_hits_total",
+			Help: "Number of uploaded package files which reused an existing blob",
+		},
+	)
+
+	// PackageRequestDuration observes the duration of package registry requests by package type
+	PackageRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    namespace + "package_request_duration_seconds",
+			Help:    "Duration of package registry requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"type"},
+	)
+
+	// PackageCleanupRuns counts the runs of the package cleanup by result ("success" or "failure")
+	PackageCleanupRuns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "package_cleanup_runs_total",
+			Help: "Number of package cleanup runs",
+		},
+		[]string{"result"},
+	)
+
+	// PackageCleanupRemovedBlobs counts the blobs removed by the package cleanup
+	PackageCleanupRemovedBlobs = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: namespace + "package_cleanup_removed_blobs_total",
+			Help: "Number of package blobs removed by the package cleanup",
+		},
+	)
+)
+
+// PackageCollector implements the prometheus.Collector interface and
+// exposes the package registry metrics for prometheus
+type PackageCollector struct {
+	Packages        *prometheus.Desc
+	PackageVersions *prometheus.Desc
+	Downloads       *prometheus.Desc
+	Blobs           *prometheus.Desc
+	BlobsSize       *prometheus.Desc
+}
+
+// NewPackageCollector returns a new PackageCollector with all prometheus.Desc initialized
+func NewPackageCollector() PackageCollector {
+	return PackageCollector{
+		Packages: prometheus.NewDesc(
+			namespace+"packages",
+			"Number of packages",
+			[]string{"type"}, nil,
+		),
+		PackageVersions: prometheus.NewDesc(
+			namespace+"package_versions",
+			"Number of package versions",
+			[]string{"type"}, nil,
+		),
+		Downloads: prometheus.NewDesc(
+			namespace+"package_downloads",
+			"Number of package downloads",
+			[]string{"type"}, nil,
+		),
+		Blobs: prometheus.NewDesc(
+			namespace+"package_blobs",
+			"Number of stored package blobs",
+			nil, nil,
+		),
+		BlobsSize: prometheus.NewDesc(
+			namespace+"package_blobs_size_bytes",
+			"Total size of the stored package blobs",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c PackageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Packages
+	ch <- c.PackageVersions
+	ch <- c.Downloads
+	ch <- c.Blobs
+	ch <- c.BlobsSize
+
+	PackageRequestsThrottled.Describe(ch)
+	PackageUploads.Describe(ch)
+	PackageBlobDedupHits.Describe(ch)
+	PackageRequestDuration.Describe(ch)
+	PackageCleanupRuns.Describe(ch)
+	PackageCleanupRemovedBlobs.Describe(ch)
+}
+
+// Collect returns the metrics with values
+func (c PackageCollector) Collect(ch chan<- prometheus.Metric) {
+	typeStats, err := packages_model.GetTypeStatistics(db.DefaultContext)
+	if err != nil {
+		log.Error("Unable to get package type statistics: %v", err)
+	}
+	for _, ts := range typeStats {
+		ch <- prometheus.MustNewConstMetric(
+			c.Packages,
+			prometheus.GaugeValue,
+			float64(ts.PackageCount),
+			string(ts.Type),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.PackageVersions,
+			prometheus.GaugeValue,
+			float64(ts.VersionCount),
+			string(ts.Type),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Downloads,
+			prometheus.GaugeValue,
+			float64(ts.DownloadCount),
+			string(ts.Type),
+		)
+	}
+
+	blobCount, err := packages_model.CountBlobs(db.DefaultContext)
+	if err != nil {
+		log.Error("Unable to count package blobs: %v", err)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.Blobs,
+		prometheus.GaugeValue,
+		float64(blobCount),
+	)
+
+	blobsSize, err := packages_model.GetTotalBlobSize()
+	if err != nil {
+		log.Error("Unable to get total package blob size: %v", err)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BlobsSize,
+		prometheus.GaugeValue,
+		float64(blobsSize),
+	)
+
+	PackageRequestsThrottled.Collect(ch)
+	PackageUploads.Collect(ch)
+	PackageBlobDedupHits.Collect(ch)
+	PackageRequestDuration.Collect(ch)
+	PackageCleanupRuns.Collect(ch)
+	PackageCleanupRemovedBlobs.Collect(ch)
+}
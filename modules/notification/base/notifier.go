@@ -45,6 +45,8 @@ type Notifier interface {
 		issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User)
 	NotifyUpdateComment(*user_model.User, *issues_model.Comment, string)
 	NotifyDeleteComment(*user_model.User, *issues_model.Comment)
+	NotifyCreateReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction)
+	NotifyDeleteReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction)
 	NotifyNewRelease(rel *repo_model.Release)
 	NotifyUpdateRelease(doer *user_model.User, rel *repo_model.Release)
 	NotifyDeleteRelease(doer *user_model.User, rel *repo_model.Release)
@@ -55,6 +57,10 @@ type Notifier interface {
 	NotifySyncCreateRef(doer *user_model.User, repo *repo_model.Repository, refType, refFullName, refID string)
 	NotifySyncDeleteRef(doer *user_model.User, repo *repo_model.Repository, refType, refFullName string)
 	NotifyRepoPendingTransfer(doer, newOwner *user_model.User, repo *repo_model.Repository)
+	NotifyRepoTransferAccepted(doer *user_model.User, repo *repo_model.Repository)
+	NotifyRepoTransferScheduled(doer *user_model.User, repo *repo_model.Repository)
+	NotifyRepoTransferRejected(doer *user_model.User, repo *repo_model.Repository)
+	NotifyRepoTransferCanceled(doer *user_model.User, repo *repo_model.Repository)
 	NotifyPackageCreate(doer *user_model.User, pd *packages_model.PackageDescriptor)
 	NotifyPackageDelete(doer *user_model.User, pd *packages_model.PackageDescriptor)
 }
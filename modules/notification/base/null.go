@@ -78,6 +78,14 @@ func (*NullNotifier) NotifyUpdateComment(doer *user_model.User, c *issues_model.
 func (*NullNotifier) NotifyDeleteComment(doer *user_model.User, c *issues_model.Comment) {
 }
 
+// NotifyCreateReaction places a place holder function
+func (*NullNotifier) NotifyCreateReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+}
+
+// NotifyDeleteReaction places a place holder function
+func (*NullNotifier) NotifyDeleteReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+}
+
 // NotifyNewRelease places a place holder function
 func (*NullNotifier) NotifyNewRelease(rel *repo_model.Release) {
 }
@@ -175,6 +183,22 @@ func (*NullNotifier) NotifySyncDeleteRef(doer *user_model.User, repo *repo_model
 func (*NullNotifier) NotifyRepoPendingTransfer(doer, newOwner *user_model.User, repo *repo_model.Repository) {
 }
 
+// NotifyRepoTransferAccepted places a place holder function
+func (*NullNotifier) NotifyRepoTransferAccepted(doer *user_model.User, repo *repo_model.Repository) {
+}
+
+// NotifyRepoTransferScheduled places a place holder function
+func (*NullNotifier) NotifyRepoTransferScheduled(doer *user_model.User, repo *repo_model.Repository) {
+}
+
+// NotifyRepoTransferRejected places a place holder function
+func (*NullNotifier) NotifyRepoTransferRejected(doer *user_model.User, repo *repo_model.Repository) {
+}
+
+// NotifyRepoTransferCanceled places a place holder function
+func (*NullNotifier) NotifyRepoTransferCanceled(doer *user_model.User, repo *repo_model.Repository) {
+}
+
 // NotifyPackageCreate places a place holder function
 func (*NullNotifier) NotifyPackageCreate(doer *user_model.User, pd *packages_model.PackageDescriptor) {
 }
@@ -140,6 +140,20 @@ func NotifyDeleteComment(doer *user_model.User, c *issues_model.Comment) {
 	}
 }
 
+// NotifyCreateReaction notifies a new reaction to an issue or a comment to notifiers
+func NotifyCreateReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateReaction(doer, issue, comment, reaction)
+	}
+}
+
+// NotifyDeleteReaction notifies a removed reaction of an issue or a comment to notifiers
+func NotifyDeleteReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteReaction(doer, issue, comment, reaction)
+	}
+}
+
 // NotifyNewRelease notifies new release to notifiers
 func NotifyNewRelease(rel *repo_model.Release) {
 	for _, notifier := range notifiers {
@@ -310,6 +324,34 @@ func NotifyRepoPendingTransfer(doer, newOwner *user_model.User, repo *repo_model
 	}
 }
 
+// NotifyRepoTransferAccepted notifies the acceptance of a scheduled repository transfer to notifiers
+func NotifyRepoTransferAccepted(doer *user_model.User, repo *repo_model.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoTransferAccepted(doer, repo)
+	}
+}
+
+// NotifyRepoTransferScheduled notifies a changed schedule of a pending repository transfer to notifiers
+func NotifyRepoTransferScheduled(doer *user_model.User, repo *repo_model.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoTransferScheduled(doer, repo)
+	}
+}
+
+// NotifyRepoTransferRejected notifies the rejection of a pending repository transfer to notifiers
+func NotifyRepoTransferRejected(doer *user_model.User, repo *repo_model.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoTransferRejected(doer, repo)
+	}
+}
+
+// NotifyRepoTransferCanceled notifies the cancellation of a pending repository transfer to notifiers
+func NotifyRepoTransferCanceled(doer *user_model.User, repo *repo_model.Repository) {
+	for _, notifier := range notifiers {
+		notifier.NotifyRepoTransferCanceled(doer, repo)
+	}
+}
+
 // NotifyPackageCreate notifies creation of a package to notifiers
 func NotifyPackageCreate(doer *user_model.User, pd *packages_model.PackageDescriptor) {
 	for _, notifier := range notifiers {
@@ -6,6 +6,7 @@ package webhook
 
 import (
 	"fmt"
+	"net/url"
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -147,6 +148,43 @@ func (m *webhookNotifier) NotifyMigrateRepository(doer, u *user_model.User, repo
 	}
 }
 
+func (m *webhookNotifier) NotifyTransferRepository(doer *user_model.User, repo *repo_model.Repository, oldOwnerName string) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferred)
+}
+
+func (m *webhookNotifier) NotifyRepoPendingTransfer(doer, newOwner *user_model.User, repo *repo_model.Repository) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferRequested)
+}
+
+func (m *webhookNotifier) NotifyRepoTransferAccepted(doer *user_model.User, repo *repo_model.Repository) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferAccepted)
+}
+
+func (m *webhookNotifier) NotifyRepoTransferScheduled(doer *user_model.User, repo *repo_model.Repository) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferScheduled)
+}
+
+func (m *webhookNotifier) NotifyRepoTransferRejected(doer *user_model.User, repo *repo_model.Repository) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferRejected)
+}
+
+func (m *webhookNotifier) NotifyRepoTransferCanceled(doer *user_model.User, repo *repo_model.Repository) {
+	sendRepoTransferHook(doer, repo, api.HookRepoTransferCanceled)
+}
+
+func sendRepoTransferHook(doer *user_model.User, repo *repo_model.Repository, action api.HookRepoAction) {
+	u := repo.MustOwner()
+
+	if err := webhook_services.PrepareWebhooks(repo, webhook.HookEventRepository, &api.RepositoryPayload{
+		Action:       action,
+		Repository:   convert.ToRepo(repo, perm.AccessModeOwner),
+		Organization: convert.ToUser(u, nil),
+		Sender:       convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
 func (m *webhookNotifier) NotifyIssueChangeAssignee(doer *user_model.User, issue *issues_model.Issue, assignee *user_model.User, removed bool, comment *issues_model.Comment) {
 	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(), fmt.Sprintf("webhook.NotifyIssueChangeAssignee User: %s[%d] Issue[%d] #%d in [%d] Assignee %s[%d] removed: %t", doer.Name, doer.ID, issue.ID, issue.Index, issue.RepoID, assignee.Name, assignee.ID, removed))
 	defer finished()
@@ -392,7 +430,7 @@ func (m *webhookNotifier) NotifyUpdateComment(doer *user_model.User, c *issues_m
 
 	mode, _ := access_model.AccessLevel(doer, c.Issue.Repo)
 	if c.Issue.IsPull {
-		err = webhook_services.PrepareWebhooks(c.Issue.Repo, webhook.HookEventPullRequestComment, &api.IssueCommentPayload{
+		err = webhook_services.PrepareWebhooks(c.Issue.Repo, webhook.HookEventPullRequestCommentEdited, &api.IssueCommentPayload{
 			Action:  api.HookIssueCommentEdited,
 			Issue:   convert.ToAPIIssue(c.Issue),
 			Comment: convert.ToComment(c),
@@ -406,7 +444,7 @@ func (m *webhookNotifier) NotifyUpdateComment(doer *user_model.User, c *issues_m
 			IsPull:     true,
 		})
 	} else {
-		err = webhook_services.PrepareWebhooks(c.Issue.Repo, webhook.HookEventIssueComment, &api.IssueCommentPayload{
+		err = webhook_services.PrepareWebhooks(c.Issue.Repo, webhook.HookEventIssueCommentEdited, &api.IssueCommentPayload{
 			Action:  api.HookIssueCommentEdited,
 			Issue:   convert.ToAPIIssue(c.Issue),
 			Comment: convert.ToComment(c),
@@ -477,7 +515,7 @@ func (m *webhookNotifier) NotifyDeleteComment(doer *user_model.User, comment *is
 	mode, _ := access_model.AccessLevel(doer, comment.Issue.Repo)
 
 	if comment.Issue.IsPull {
-		err = webhook_services.PrepareWebhooks(comment.Issue.Repo, webhook.HookEventPullRequestComment, &api.IssueCommentPayload{
+		err = webhook_services.PrepareWebhooks(comment.Issue.Repo, webhook.HookEventPullRequestCommentDeleted, &api.IssueCommentPayload{
 			Action:     api.HookIssueCommentDeleted,
 			Issue:      convert.ToAPIIssue(comment.Issue),
 			Comment:    convert.ToComment(comment),
@@ -486,7 +524,7 @@ func (m *webhookNotifier) NotifyDeleteComment(doer *user_model.User, comment *is
 			IsPull:     true,
 		})
 	} else {
-		err = webhook_services.PrepareWebhooks(comment.Issue.Repo, webhook.HookEventIssueComment, &api.IssueCommentPayload{
+		err = webhook_services.PrepareWebhooks(comment.Issue.Repo, webhook.HookEventIssueCommentDeleted, &api.IssueCommentPayload{
 			Action:     api.HookIssueCommentDeleted,
 			Issue:      convert.ToAPIIssue(comment.Issue),
 			Comment:    convert.ToComment(comment),
@@ -501,6 +539,46 @@ func (m *webhookNotifier) NotifyDeleteComment(doer *user_model.User, comment *is
 	}
 }
 
+func (m *webhookNotifier) NotifyCreateReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+	m.notifyReaction(doer, issue, comment, reaction, api.HookReactionCreated)
+}
+
+func (m *webhookNotifier) NotifyDeleteReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction) {
+	m.notifyReaction(doer, issue, comment, reaction, api.HookReactionDeleted)
+}
+
+func (m *webhookNotifier) notifyReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, reaction *issues_model.Reaction, action api.HookReactionAction) {
+	if err := issue.LoadAttributes(db.DefaultContext); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	mode, _ := access_model.AccessLevel(doer, issue.Repo)
+	payload := &api.ReactionPayload{
+		Action: action,
+		Reaction: &api.Reaction{
+			User:     convert.ToUser(doer, nil),
+			Reaction: reaction.Type,
+			Created:  reaction.CreatedUnix.AsTime(),
+		},
+		Issue:      convert.ToAPIIssue(issue),
+		Repository: convert.ToRepo(issue.Repo, mode),
+		Sender:     convert.ToUser(doer, nil),
+		IsPull:     issue.IsPull,
+	}
+	if comment != nil {
+		if err := comment.LoadPoster(); err != nil {
+			log.Error("LoadPoster: %v", err)
+			return
+		}
+		payload.Comment = convert.ToComment(comment)
+	}
+
+	if err := webhook_services.PrepareWebhooks(issue.Repo, webhook.HookEventReaction, payload); err != nil {
+		log.Error("PrepareWebhooks [issue_id: %d, reaction_id: %d]: %v", issue.ID, reaction.ID, err)
+	}
+}
+
 func (m *webhookNotifier) NotifyIssueChangeLabels(doer *user_model.User, issue *issues_model.Issue,
 	addedLabels, removedLabels []*issues_model.Label,
 ) {
@@ -595,6 +673,15 @@ func (m *webhookNotifier) NotifyIssueChangeMilestone(doer *user_model.User, issu
 	}
 }
 
+// pushCommitsURL returns the API URL listing all commits of the push
+func pushCommitsURL(repo *repo_model.Repository, opts *repository.PushUpdateOptions) string {
+	query := url.Values{"sha": []string{opts.NewCommitID}}
+	if !opts.IsNewRef() {
+		query.Set("not", opts.OldCommitID)
+	}
+	return repo.APIURL() + "/commits?" + query.Encode()
+}
+
 func (m *webhookNotifier) NotifyPushCommits(pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
 	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(), fmt.Sprintf("webhook.NotifyPushCommits User: %s[%d] in %s[%d]", pusher.Name, pusher.ID, repo.FullName(), repo.ID))
 	defer finished()
@@ -607,15 +694,17 @@ func (m *webhookNotifier) NotifyPushCommits(pusher *user_model.User, repo *repo_
 	}
 
 	if err := webhook_services.PrepareWebhooks(repo, webhook.HookEventPush, &api.PushPayload{
-		Ref:        opts.RefFullName,
-		Before:     opts.OldCommitID,
-		After:      opts.NewCommitID,
-		CompareURL: setting.AppURL + commits.CompareURL,
-		Commits:    apiCommits,
-		HeadCommit: apiHeadCommit,
-		Repo:       convert.ToRepo(repo, perm.AccessModeOwner),
-		Pusher:     apiPusher,
-		Sender:     apiPusher,
+		Ref:          opts.RefFullName,
+		Before:       opts.OldCommitID,
+		After:        opts.NewCommitID,
+		CompareURL:   setting.AppURL + commits.CompareURL,
+		Commits:      apiCommits,
+		TotalCommits: commits.Len,
+		CommitsURL:   pushCommitsURL(repo, opts),
+		HeadCommit:   apiHeadCommit,
+		Repo:         convert.ToRepo(repo, perm.AccessModeOwner),
+		Pusher:       apiPusher,
+		Sender:       apiPusher,
 	}); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 	}
@@ -796,33 +885,71 @@ func (m *webhookNotifier) NotifyDeleteRef(pusher *user_model.User, repo *repo_mo
 	}
 }
 
-func sendReleaseHook(doer *user_model.User, rel *repo_model.Release, action api.HookReleaseAction) {
+func sendReleaseHook(doer *user_model.User, rel *repo_model.Release, event webhook.HookEventType, action api.HookReleaseAction) {
 	if err := rel.LoadAttributes(); err != nil {
 		log.Error("LoadAttributes: %v", err)
 		return
 	}
 
+	payload := &api.ReleasePayload{
+		Action:  action,
+		Release: convert.ToRelease(rel),
+		Channel: releaseChannel(rel),
+		Sender:  convert.ToUser(doer, nil),
+	}
+
+	// the tag doesn't exist anymore if the release has been deleted and drafts don't have a tag
+	if action != api.HookReleaseDeleted && !rel.IsDraft {
+		payload.Changelog = generateReleaseChangelog(rel)
+	}
+
 	mode, _ := access_model.AccessLevel(doer, rel.Repo)
-	if err := webhook_services.PrepareWebhooks(rel.Repo, webhook.HookEventRelease, &api.ReleasePayload{
-		Action:     action,
-		Release:    convert.ToRelease(rel),
-		Repository: convert.ToRepo(rel.Repo, mode),
-		Sender:     convert.ToUser(doer, nil),
-	}); err != nil {
+	payload.Repository = convert.ToRepo(rel.Repo, mode)
+	if err := webhook_services.PrepareWebhooks(rel.Repo, event, payload); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
 
+func releaseChannel(rel *repo_model.Release) api.ReleaseChannel {
+	switch {
+	case rel.IsDraft:
+		return api.ReleaseChannelDraft
+	case rel.IsPrerelease:
+		return api.ReleaseChannelPrerelease
+	default:
+		return api.ReleaseChannelStable
+	}
+}
+
+func generateReleaseChangelog(rel *repo_model.Release) *api.ReleaseChangelog {
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(), fmt.Sprintf("webhook.generateReleaseChangelog Release: %s in %s[%d]", rel.TagName, rel.Repo.FullName(), rel.Repo.ID))
+	defer finished()
+
+	gitRepo, err := git.OpenRepository(ctx, rel.Repo.RepoPath())
+	if err != nil {
+		log.Error("OpenRepository[%s]: %v", rel.Repo.RepoPath(), err)
+		return nil
+	}
+	defer gitRepo.Close()
+
+	changelog, err := repository.GenerateReleaseChangelog(ctx, gitRepo, rel)
+	if err != nil {
+		log.Error("GenerateReleaseChangelog[%d]: %v", rel.ID, err)
+		return nil
+	}
+	return changelog
+}
+
 func (m *webhookNotifier) NotifyNewRelease(rel *repo_model.Release) {
-	sendReleaseHook(rel.Publisher, rel, api.HookReleasePublished)
+	sendReleaseHook(rel.Publisher, rel, webhook.HookEventReleasePublished, api.HookReleasePublished)
 }
 
 func (m *webhookNotifier) NotifyUpdateRelease(doer *user_model.User, rel *repo_model.Release) {
-	sendReleaseHook(doer, rel, api.HookReleaseUpdated)
+	sendReleaseHook(doer, rel, webhook.HookEventReleaseEdited, api.HookReleaseUpdated)
 }
 
 func (m *webhookNotifier) NotifyDeleteRelease(doer *user_model.User, rel *repo_model.Release) {
-	sendReleaseHook(doer, rel, api.HookReleaseDeleted)
+	sendReleaseHook(doer, rel, webhook.HookEventRelease, api.HookReleaseDeleted)
 }
 
 func (m *webhookNotifier) NotifySyncPushCommits(pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
@@ -837,15 +964,17 @@ func (m *webhookNotifier) NotifySyncPushCommits(pusher *user_model.User, repo *r
 	}
 
 	if err := webhook_services.PrepareWebhooks(repo, webhook.HookEventPush, &api.PushPayload{
-		Ref:        opts.RefFullName,
-		Before:     opts.OldCommitID,
-		After:      opts.NewCommitID,
-		CompareURL: setting.AppURL + commits.CompareURL,
-		Commits:    apiCommits,
-		HeadCommit: apiHeadCommit,
-		Repo:       convert.ToRepo(repo, perm.AccessModeOwner),
-		Pusher:     apiPusher,
-		Sender:     apiPusher,
+		Ref:          opts.RefFullName,
+		Before:       opts.OldCommitID,
+		After:        opts.NewCommitID,
+		CompareURL:   setting.AppURL + commits.CompareURL,
+		Commits:      apiCommits,
+		TotalCommits: commits.Len,
+		CommitsURL:   pushCommitsURL(repo, opts),
+		HeadCommit:   apiHeadCommit,
+		Repo:         convert.ToRepo(repo, perm.AccessModeOwner),
+		Pusher:       apiPusher,
+		Sender:       apiPusher,
 	}); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 	}
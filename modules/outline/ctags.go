@@ -0,0 +1,119 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ctagsTag is a tag of the JSON output of Universal Ctags
+type ctagsTag struct {
+	Type      string `json:"_type"`
+	Name      string `json:"name"`
+	Line      int    `json:"line"`
+	End       int    `json:"end"`
+	Kind      string `json:"kind"`
+	Scope     string `json:"scope"`
+	ScopeKind string `json:"scopeKind"`
+}
+
+// generateCtags generates the outline with Universal Ctags.
+// The content is written to a temporary file with the same name so ctags can detect the language.
+func generateCtags(ctx context.Context, filename string, content []byte) ([]*Symbol, bool, error) {
+	tmpDir, err := os.MkdirTemp("", "gitea-outline")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		if err := util.RemoveAll(tmpDir); err != nil {
+			log.Warn("Unable to remove temporary directory: %s: Error: %v", tmpDir, err)
+		}
+	}()
+
+	tmpFile := filepath.Join(tmpDir, filepath.Base(filename))
+	if err := os.WriteFile(tmpFile, content, 0o600); err != nil {
+		return nil, false, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	description := fmt.Sprintf("Outline [ctags] for %s", filename)
+	var processCtx context.Context
+	var finished process.FinishedFunc
+	if setting.Repository.Outline.Timeout > 0 {
+		processCtx, _, finished = process.GetManager().AddContextTimeout(ctx, setting.Repository.Outline.Timeout, description)
+	} else {
+		processCtx, _, finished = process.GetManager().AddContext(ctx, description)
+	}
+	defer finished()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(processCtx, setting.Repository.Outline.CtagsPath,
+		"--output-format=json", "--fields=+nKse", "--sort=no", "-o", "-", tmpFile)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	process.SetSysProcAttribute(cmd)
+	if err := cmd.Run(); err != nil {
+		return nil, false, fmt.Errorf("ctags failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	tags := make([]*ctagsTag, 0, 10)
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		tag := &ctagsTag{}
+		if err := json.Unmarshal(scanner.Bytes(), tag); err != nil {
+			return nil, false, fmt.Errorf("invalid ctags output: %w", err)
+		}
+		if tag.Type == "tag" {
+			tags = append(tags, tag)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+
+	// no tags means ctags does not know the language
+	return buildCtagsSymbols(tags), len(tags) > 0, nil
+}
+
+// buildCtagsSymbols nests the tags below the tags of their scope.
+// Scopes are qualified names like "Class.method" or "ns::Class".
+func buildCtagsSymbols(tags []*ctagsTag) []*Symbol {
+	symbols := make([]*Symbol, 0, len(tags))
+	scopes := make(map[string]*Symbol, len(tags))
+	for _, tag := range tags {
+		symbol := &Symbol{
+			Name:    tag.Name,
+			Kind:    Kind(tag.Kind),
+			Line:    tag.Line,
+			EndLine: tag.End,
+		}
+
+		if parent, has := scopes[tag.Scope]; has && tag.Scope != "" {
+			parent.Children = append(parent.Children, symbol)
+		} else {
+			symbols = append(symbols, symbol)
+		}
+
+		if tag.Scope == "" {
+			scopes[tag.Name] = symbol
+		} else {
+			scopes[tag.Scope+"."+tag.Name] = symbol
+			scopes[tag.Scope+"::"+tag.Name] = symbol
+		}
+	}
+	return symbols
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outline
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// generateGo generates the outline of Go source code.
+// Methods and fields are listed as children of their types if the type is declared in the same file.
+func generateGo(_ context.Context, filename string, content []byte) ([]*Symbol, bool, error) {
+	fset := token.NewFileSet()
+	// parse as much as possible of files with syntax errors
+	file, _ := parser.ParseFile(fset, filename, content, parser.SkipObjectResolution)
+	if file == nil {
+		return nil, false, nil
+	}
+
+	line := func(pos token.Pos) int {
+		return fset.Position(pos).Line
+	}
+
+	symbols := make([]*Symbol, 0, len(file.Decls))
+	types := make(map[string]*Symbol)
+	var methods []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil {
+				methods = append(methods, d)
+				continue
+			}
+			symbols = append(symbols, &Symbol{
+				Name:    d.Name.Name,
+				Kind:    KindFunction,
+				Line:    line(d.Pos()),
+				EndLine: line(d.End()),
+			})
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					symbol := &Symbol{
+						Name:    s.Name.Name,
+						Kind:    KindType,
+						Line:    line(s.Pos()),
+						EndLine: line(s.End()),
+					}
+					switch t := s.Type.(type) {
+					case *ast.StructType:
+						symbol.Kind = KindStruct
+						for _, field := range t.Fields.List {
+							for _, name := range field.Names {
+								symbol.Children = append(symbol.Children, &Symbol{
+									Name: name.Name,
+									Kind: KindField,
+									Line: line(name.Pos()),
+								})
+							}
+						}
+					case *ast.InterfaceType:
+						symbol.Kind = KindInterface
+						for _, method := range t.Methods.List {
+							for _, name := range method.Names {
+								symbol.Children = append(symbol.Children, &Symbol{
+									Name: name.Name,
+									Kind: KindMethod,
+									Line: line(name.Pos()),
+								})
+							}
+						}
+					}
+					types[s.Name.Name] = symbol
+					symbols = append(symbols, symbol)
+				case *ast.ValueSpec:
+					kind := KindVariable
+					if d.Tok == token.CONST {
+						kind = KindConstant
+					}
+					for _, name := range s.Names {
+						if name.Name == "_" {
+							continue
+						}
+						symbols = append(symbols, &Symbol{
+							Name: name.Name,
+							Kind: kind,
+							Line: line(name.Pos()),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	for _, m := range methods {
+		symbol := &Symbol{
+			Name:    m.Name.Name,
+			Kind:    KindMethod,
+			Line:    line(m.Pos()),
+			EndLine: line(m.End()),
+		}
+		recv := receiverTypeName(m.Recv)
+		if t, has := types[recv]; has {
+			t.Children = append(t.Children, symbol)
+			continue
+		}
+		if recv != "" {
+			symbol.Name = "(" + recv + ") " + symbol.Name
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, true, nil
+}
+
+// receiverTypeName returns the name of the receiver type without pointer and type parameters
+func receiverTypeName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outline
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/yuin/goldmark"
+	meta "github.com/yuin/goldmark-meta"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// generateMarkdown generates the outline of the headings of markdown content.
+// Headings are nested below the previous heading of a higher level.
+func generateMarkdown(_ context.Context, _ string, content []byte) ([]*Symbol, bool, error) {
+	source := util.NormalizeEOL(content)
+	doc := goldmark.New(goldmark.WithExtensions(meta.Meta)).Parser().Parse(text.NewReader(source))
+
+	type level struct {
+		level  int
+		symbol *Symbol
+	}
+	var stack []level
+	symbols := make([]*Symbol, 0, 10)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		heading, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		name := strings.TrimSpace(string(heading.Text(source)))
+		if name == "" || heading.Lines().Len() == 0 {
+			return ast.WalkSkipChildren, nil
+		}
+		symbol := &Symbol{
+			Name: name,
+			Kind: KindHeading,
+			Line: bytes.Count(source[:heading.Lines().At(0).Start], []byte{'\n'}) + 1,
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			symbols = append(symbols, symbol)
+		} else {
+			parent := stack[len(stack)-1].symbol
+			parent.Children = append(parent.Children, symbol)
+		}
+		stack = append(stack, level{level: heading.Level, symbol: symbol})
+		return ast.WalkSkipChildren, nil
+	})
+	return symbols, true, nil
+}
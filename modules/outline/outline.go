@@ -0,0 +1,134 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrFileTooLarge is returned if the file is larger than the configured maximum size
+var ErrFileTooLarge = errors.New("file is too large to generate an outline")
+
+// Kind is the kind of a symbol
+type Kind string
+
+// List of the kinds of symbols generated by the built-in parsers.
+// Symbols generated by ctags use the kind names of ctags.
+const (
+	KindHeading   Kind = "heading"
+	KindFunction  Kind = "function"
+	KindMethod    Kind = "method"
+	KindStruct    Kind = "struct"
+	KindInterface Kind = "interface"
+	KindType      Kind = "type"
+	KindField     Kind = "field"
+	KindConstant  Kind = "constant"
+	KindVariable  Kind = "variable"
+)
+
+// Symbol is an entry of the outline of a file
+type Symbol struct {
+	Name     string    `json:"name"`
+	Kind     Kind      `json:"kind"`
+	Line     int       `json:"line"`
+	EndLine  int       `json:"end_line,omitempty"`
+	Children []*Symbol `json:"children,omitempty"`
+}
+
+// Outline is the structural outline of a file
+type Outline struct {
+	Language string    `json:"language"`
+	Symbols  []*Symbol `json:"symbols"`
+}
+
+// generator generates the symbols of a file, it returns false if it does not support the file
+type generator func(ctx context.Context, filename string, content []byte) ([]*Symbol, bool, error)
+
+// generators are the built-in parsers by language, ctags is used for the other languages if configured
+var generators = map[string]generator{
+	"Go":       generateGo,
+	"Markdown": generateMarkdown,
+}
+
+// Generate generates the outline of the file content.
+// Files of an unsupported language get an empty outline.
+func Generate(ctx context.Context, filename string, content []byte) (*Outline, error) {
+	o := &Outline{
+		Language: analyze.GetCodeLanguage(filename, content),
+		Symbols:  []*Symbol{},
+	}
+
+	var symbols []*Symbol
+	var ok bool
+	var err error
+	if gen, has := generators[o.Language]; has {
+		symbols, ok, err = gen(ctx, filename, content)
+	}
+	if !ok && err == nil && setting.Repository.Outline.CtagsPath != "" {
+		symbols, ok, err = generateCtags(ctx, filename, content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		sortSymbols(symbols)
+		o.Symbols = symbols
+	}
+	return o, nil
+}
+
+// GetBlobOutline returns the outline of the blob.
+// The outline is cached by the blob hash and the file name, the latter determines the language.
+func GetBlobOutline(ctx context.Context, blob *git.Blob, filename string) (*Outline, error) {
+	if setting.Repository.Outline.MaxFileSize > 0 && blob.Size() > setting.Repository.Outline.MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	key := fmt.Sprintf("file_outline_%s_%s", blob.ID.String(), path.Base(filename))
+	s, err := cache.GetString(key, func() (string, error) {
+		rd, err := blob.DataAsync()
+		if err != nil {
+			return "", err
+		}
+		defer rd.Close()
+		content, err := io.ReadAll(rd)
+		if err != nil {
+			return "", err
+		}
+
+		o, err := Generate(ctx, filename, content)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(o)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Outline{}
+	return o, json.Unmarshal([]byte(s), o)
+}
+
+func sortSymbols(symbols []*Symbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Line < symbols[j].Line
+	})
+	for _, s := range symbols {
+		sortSymbols(s.Children)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package outline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGo(t *testing.T) {
+	content := `package test
+
+const Answer = 42
+
+var (
+	_       = Answer
+	enabled bool
+)
+
+type Handler interface {
+	Handle() error
+}
+
+type server struct {
+	name, addr string
+}
+
+func (s *server) Handle() error {
+	return nil
+}
+
+func (l List[T]) Len() int {
+	return len(l)
+}
+
+func main() {
+}
+`
+	o, err := Generate(context.Background(), "main.go", []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "Go", o.Language)
+	assert.Equal(t, []*Symbol{
+		{Name: "Answer", Kind: KindConstant, Line: 3},
+		{Name: "enabled", Kind: KindVariable, Line: 7},
+		{Name: "Handler", Kind: KindInterface, Line: 10, EndLine: 12, Children: []*Symbol{
+			{Name: "Handle", Kind: KindMethod, Line: 11},
+		}},
+		{Name: "server", Kind: KindStruct, Line: 14, EndLine: 16, Children: []*Symbol{
+			{Name: "name", Kind: KindField, Line: 15},
+			{Name: "addr", Kind: KindField, Line: 15},
+			{Name: "Handle", Kind: KindMethod, Line: 18, EndLine: 20},
+		}},
+		{Name: "(List) Len", Kind: KindMethod, Line: 22, EndLine: 24},
+		{Name: "main", Kind: KindFunction, Line: 26, EndLine: 27},
+	}, o.Symbols)
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	content := `---
+title: front matter
+---
+# Title
+
+## Install
+` + "```" + `
+# not a heading
+` + "```" + `
+### From source
+
+Usage
+-----
+
+# Appendix
+`
+	o, err := Generate(context.Background(), "README.md", []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "Markdown", o.Language)
+	assert.Equal(t, []*Symbol{
+		{Name: "Title", Kind: KindHeading, Line: 4, Children: []*Symbol{
+			{Name: "Install", Kind: KindHeading, Line: 6, Children: []*Symbol{
+				{Name: "From source", Kind: KindHeading, Line: 10},
+			}},
+			{Name: "Usage", Kind: KindHeading, Line: 12},
+		}},
+		{Name: "Appendix", Kind: KindHeading, Line: 15},
+	}, o.Symbols)
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	o, err := Generate(context.Background(), "data.bin", []byte{0, 1, 2})
+	assert.NoError(t, err)
+	assert.Empty(t, o.Symbols)
+}
+
+func TestBuildCtagsSymbols(t *testing.T) {
+	tags := []*ctagsTag{
+		{Type: "tag", Name: "Greeter", Line: 1, End: 6, Kind: "class"},
+		{Type: "tag", Name: "greet", Line: 2, End: 3, Kind: "member", Scope: "Greeter", ScopeKind: "class"},
+		{Type: "tag", Name: "inner", Line: 3, Kind: "function", Scope: "Greeter.greet", ScopeKind: "member"},
+		{Type: "tag", Name: "main", Line: 8, Kind: "function"},
+		{Type: "tag", Name: "orphan", Line: 9, Kind: "variable", Scope: "unknown"},
+	}
+	assert.Equal(t, []*Symbol{
+		{Name: "Greeter", Kind: "class", Line: 1, EndLine: 6, Children: []*Symbol{
+			{Name: "greet", Kind: "member", Line: 2, EndLine: 3, Children: []*Symbol{
+				{Name: "inner", Kind: "function", Line: 3},
+			}},
+		}},
+		{Name: "main", Kind: "function", Line: 8},
+		{Name: "orphan", Kind: "variable", Line: 9},
+	}, buildCtagsSymbols(tags))
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// List of client classes used to group package downloads
+const (
+	ClientBrowser   = "browser"
+	ClientCLI       = "cli"
+	ClientComposer  = "composer"
+	ClientConan     = "conan"
+	ClientConda     = "conda"
+	ClientContainer = "container"
+	ClientGradle    = "gradle"
+	ClientHelm      = "helm"
+	ClientMaven     = "maven"
+	ClientNpm       = "npm"
+	ClientNuGet     = "nuget"
+	ClientPub       = "pub"
+	ClientPyPI      = "pypi"
+	ClientRubyGems  = "rubygems"
+	ClientSwift     = "swift"
+	ClientVagrant   = "vagrant"
+	ClientOther     = "other"
+	ClientUnknown   = "unknown"
+)
+
+// clientPatterns maps lower case user agent substrings to client classes, the first match wins
+var clientPatterns = []struct {
+	pattern string
+	client  string
+}{
+	{"composer/", ClientComposer},
+	{"conan/", ClientConan},
+	{"conda/", ClientConda},
+	{"docker/", ClientContainer},
+	{"containerd/", ClientContainer},
+	{"podman/", ClientContainer},
+	{"skopeo/", ClientContainer},
+	{"buildkit/", ClientContainer},
+	{"gradle/", ClientGradle},
+	{"helm/", ClientHelm},
+	{"apache-maven/", ClientMaven},
+	{"npm/", ClientNpm},
+	{"yarn/", ClientNpm},
+	{"pnpm/", ClientNpm},
+	{"nuget", ClientNuGet},
+	{"dart pub", ClientPub},
+	{"dart/", ClientPub},
+	{"pip/", ClientPyPI},
+	{"poetry/", ClientPyPI},
+	{"twine/", ClientPyPI},
+	{"rubygems/", ClientRubyGems},
+	{"bundler/", ClientRubyGems},
+	{"swiftpackagemanager/", ClientSwift},
+	{"vagrant/", ClientVagrant},
+	{"curl/", ClientCLI},
+	{"wget/", ClientCLI},
+	{"mozilla/", ClientBrowser},
+}
+
+// ClassifyUserAgent returns the client class of a user agent
+func ClassifyUserAgent(userAgent string) string {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return ClientUnknown
+	}
+	for _, p := range clientPatterns {
+		if strings.Contains(userAgent, p.pattern) {
+			return p.client
+		}
+	}
+	return ClientOther
+}
+
+type userAgentContextKey struct{}
+
+// WithUserAgent returns a context which carries the user agent of the client requesting package files
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentContextKey{}, userAgent)
+}
+
+// UserAgentFromContext returns the user agent stored by WithUserAgent
+func UserAgentFromContext(ctx context.Context) string {
+	userAgent, _ := ctx.Value(userAgentContextKey{}).(string)
+	return userAgent
+}
+
+type remoteAddrContextKey struct{}
+
+// WithRequest returns a context which carries the user agent and the address of the client requesting packages
+func WithRequest(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(WithUserAgent(ctx, req.UserAgent()), remoteAddrContextKey{}, req.RemoteAddr)
+}
+
+// RemoteAddrFromContext returns the client address stored by WithRequest
+func RemoteAddrFromContext(ctx context.Context) string {
+	remoteAddr, _ := ctx.Value(remoteAddrContextKey{}).(string)
+	return remoteAddr
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyUserAgent(t *testing.T) {
+	cases := map[string]string{
+		"": ClientUnknown,
+		"npm/8.19.2 node/v18.12.0 linux x64 workspaces/false":                              ClientNpm,
+		"yarn/1.22.19 npm/? node/v16.17.0 darwin arm64":                                    ClientNpm,
+		"pip/22.3 {\"ci\":null,\"cpu\":\"x86_64\"}":                                        ClientPyPI,
+		"Apache-Maven/3.8.6 (Java 17.0.4; Linux 5.15.0)":                                   ClientMaven,
+		"Gradle/7.5.1 (Linux;5.15.0;amd64) (Eclipse Adoptium;17.0.4;17.0.4+8)":             ClientGradle,
+		"docker/20.10.21 go/go1.18.7 git-commit/3056208 kernel/5.15.0 os/linux arch/amd64": ClientContainer,
+		"NuGet Command Line/6.3.1 (Microsoft Windows NT 10.0.19044.0)":                     ClientNuGet,
+		"Composer/2.4.4 (Linux; 5.15.0; PHP 8.1.12; cURL 7.81.0)":                          ClientComposer,
+		"SwiftPackageManager/5.7.1 (arm64; macOS 13.0.1)":                                  ClientSwift,
+		"curl/7.81.0": ClientCLI,
+		"Mozilla/5.0 (X11; Linux x86_64; rv:106.0) Gecko/20100101 Firefox/106.0": ClientBrowser,
+		"Go-http-client/1.1": ClientOther,
+	}
+
+	for userAgent, expected := range cases {
+		assert.Equal(t, expected, ClassifyUserAgent(userAgent), "user agent: %s", userAgent)
+	}
+}
+
+func TestUserAgentContext(t *testing.T) {
+	assert.Empty(t, UserAgentFromContext(context.Background()))
+	assert.Equal(t, "npm/8.19.2", UserAgentFromContext(WithUserAgent(context.Background(), "npm/8.19.2")))
+}
+
+func TestRequestContext(t *testing.T) {
+	assert.Empty(t, RemoteAddrFromContext(context.Background()))
+
+	req := httptest.NewRequest("GET", "/api/packages/user2/npm/test", nil)
+	req.Header.Set("User-Agent", "npm/8.19.2")
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	ctx := WithRequest(context.Background(), req)
+	assert.Equal(t, "npm/8.19.2", UserAgentFromContext(ctx))
+	assert.Equal(t, "192.0.2.1:1234", RemoteAddrFromContext(ctx))
+}
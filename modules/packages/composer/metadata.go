@@ -51,6 +51,33 @@ type Metadata struct {
 	RequireDev  map[string]string      `json:"require-dev,omitempty"`
 	Suggest     map[string]string      `json:"suggest,omitempty"`
 	Provide     map[string]string      `json:"provide,omitempty"`
+	Abandoned   *Abandoned             `json:"abandoned,omitempty"`
+}
+
+// Abandoned represents the abandoned state of a Composer package
+type Abandoned struct {
+	IsAbandoned bool
+	Replacement string // name of the package which should be used instead
+}
+
+// UnmarshalJSON reads from a bool or the name of the replacement package
+func (a *Abandoned) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &a.Replacement); err != nil {
+			return err
+		}
+		a.IsAbandoned = true
+		return nil
+	}
+	return json.Unmarshal(data, &a.IsAbandoned)
+}
+
+// MarshalJSON writes the name of the replacement package or a bool if there is none
+func (a Abandoned) MarshalJSON() ([]byte, error) {
+	if a.IsAbandoned && a.Replacement != "" {
+		return json.Marshal(a.Replacement)
+	}
+	return json.Marshal(a.IsAbandoned)
 }
 
 // Licenses represents the licenses of a Composer package
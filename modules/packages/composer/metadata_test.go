@@ -55,6 +55,27 @@ func TestLicenseUnmarshal(t *testing.T) {
 	assert.Equal(t, "MIT", l[0])
 }
 
+func TestAbandonedJSON(t *testing.T) {
+	cases := []struct {
+		JSON     string
+		Expected Abandoned
+	}{
+		{`true`, Abandoned{IsAbandoned: true}},
+		{`false`, Abandoned{}},
+		{`"gitea/replacement"`, Abandoned{IsAbandoned: true, Replacement: "gitea/replacement"}},
+	}
+
+	for _, c := range cases {
+		var a Abandoned
+		assert.NoError(t, json.Unmarshal([]byte(c.JSON), &a))
+		assert.Equal(t, c.Expected, a)
+
+		data, err := json.Marshal(a)
+		assert.NoError(t, err)
+		assert.JSONEq(t, c.JSON, string(data))
+	}
+}
+
 func TestParsePackage(t *testing.T) {
 	createArchive := func(name, content string) []byte {
 		var buf bytes.Buffer
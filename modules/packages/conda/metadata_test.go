@@ -0,0 +1,158 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package conda
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	packageName      = "gitea"
+	packageVersion   = "1.0.1"
+	description      = "Package Description"
+	projectURL       = "https://gitea.io"
+	repositoryURL    = "https://gitea.io/gitea/gitea"
+	documentationURL = "https://docs.gitea.io"
+)
+
+func TestParsePackage(t *testing.T) {
+	createArchive := func(files map[string][]byte) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for filename, content := range files {
+			hdr := &tar.Header{
+				Name: filename,
+				Mode: 0o600,
+				Size: int64(len(content)),
+			}
+			tw.WriteHeader(hdr)
+			tw.Write(content)
+		}
+		tw.Close()
+		return &buf
+	}
+
+	t.Run("MissingIndexFile", func(t *testing.T) {
+		buf := createArchive(map[string][]byte{"dummy.txt": {}})
+
+		p, err := parsePackageTar(buf)
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidStructure)
+	})
+
+	t.Run("MissingAboutFile", func(t *testing.T) {
+		buf := createArchive(map[string][]byte{"info/index.json": []byte(`{"name":"name","version":"1.0"}`)})
+
+		p, err := parsePackageTar(buf)
+		assert.NotNil(t, p)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "name", p.Name)
+		assert.Equal(t, "1.0", p.Version)
+		assert.Equal(t, "noarch", p.Subdir)
+		assert.Empty(t, p.VersionMetadata.ProjectURL)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{"", "name!", "nAMe"} {
+			buf := createArchive(map[string][]byte{"info/index.json": []byte(`{"name":"` + name + `","version":"1.0"}`)})
+
+			p, err := parsePackageTar(buf)
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidName)
+		}
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		for _, version := range []string{"", "1.0-2"} {
+			buf := createArchive(map[string][]byte{"info/index.json": []byte(`{"name":"name","version":"` + version + `"}`)})
+
+			p, err := parsePackageTar(buf)
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidVersion)
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		indexJSON, _ := json.Marshal(map[string]interface{}{
+			"name":           packageName,
+			"version":        packageVersion,
+			"subdir":         "linux-64",
+			"build":          "py_0",
+			"build_number":   3,
+			"depends":        []string{"python >=3.7"},
+			"license":        "MIT",
+			"license_family": "MIT",
+			"noarch":         true,
+		})
+		aboutJSON, _ := json.Marshal(map[string]string{
+			"description": description,
+			"home":        projectURL,
+			"dev_url":     repositoryURL,
+			"doc_url":     documentationURL,
+		})
+
+		buf := createArchive(map[string][]byte{
+			"info/index.json": indexJSON,
+			"info/about.json": aboutJSON,
+		})
+
+		t.Run(".tar.bz2", func(t *testing.T) {
+			var data bytes.Buffer
+			bw, _ := bzip2.NewWriter(&data, nil)
+			io.Copy(bw, bytes.NewReader(buf.Bytes()))
+			bw.Close()
+
+			p, err := ParsePackageBZ2(&data)
+			assert.NotNil(t, p)
+			assert.NoError(t, err)
+
+			assert.Equal(t, packageName, p.Name)
+			assert.Equal(t, packageVersion, p.Version)
+			assert.Equal(t, "linux-64", p.Subdir)
+			assert.Equal(t, description, p.VersionMetadata.Description)
+			assert.Equal(t, projectURL, p.VersionMetadata.ProjectURL)
+			assert.Equal(t, repositoryURL, p.VersionMetadata.RepositoryURL)
+			assert.Equal(t, documentationURL, p.VersionMetadata.DocumentationURL)
+			assert.Equal(t, "MIT", p.VersionMetadata.License)
+			assert.False(t, p.FileMetadata.IsCondaPackage)
+			assert.Equal(t, "generic", p.FileMetadata.NoArch)
+			assert.Equal(t, "py_0", p.FileMetadata.Build)
+			assert.EqualValues(t, 3, p.FileMetadata.BuildNumber)
+			assert.Equal(t, []string{"python >=3.7"}, p.FileMetadata.Dependencies)
+		})
+
+		t.Run(".conda", func(t *testing.T) {
+			var infoBuf bytes.Buffer
+			zw, _ := zstd.NewWriter(&infoBuf)
+			io.Copy(zw, bytes.NewReader(buf.Bytes()))
+			zw.Close()
+
+			var data bytes.Buffer
+			archive := zip.NewWriter(&data)
+			w, _ := archive.Create("info-x.tar.zst")
+			w.Write(infoBuf.Bytes())
+			archive.Close()
+
+			p, err := ParsePackageConda(bytes.NewReader(data.Bytes()), int64(data.Len()))
+			assert.NotNil(t, p)
+			assert.NoError(t, err)
+
+			assert.Equal(t, packageName, p.Name)
+			assert.Equal(t, packageVersion, p.Version)
+			assert.True(t, p.FileMetadata.IsCondaPackage)
+		})
+	})
+}
@@ -0,0 +1,350 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/typesniffer"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// ContentEntry is a file contained in a package
+type ContentEntry struct {
+	Path       string
+	Size       int64
+	HashSHA256 string
+	// Content is only set for text files which are not larger than the requested maximum size
+	Content []byte
+}
+
+// readContentEntry hashes the content of a file and keeps it if it is text and not larger than maxContentSize
+func readContentEntry(name string, r io.Reader, maxContentSize int64) (*ContentEntry, error) {
+	h := sha256.New()
+	var buf bytes.Buffer
+	w := io.Writer(h)
+	if maxContentSize > 0 {
+		w = io.MultiWriter(h, &limitedBuffer{&buf, maxContentSize + 1})
+	}
+	size, err := io.Copy(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &ContentEntry{
+		Path:       name,
+		Size:       size,
+		HashSHA256: hex.EncodeToString(h.Sum(nil)),
+	}
+	if maxContentSize > 0 && size <= maxContentSize && typesniffer.DetectContentType(buf.Bytes()).IsText() {
+		entry.Content = buf.Bytes()
+	}
+	return entry, nil
+}
+
+// limitedBuffer discards everything written after the first n bytes
+type limitedBuffer struct {
+	buf *bytes.Buffer
+	n   int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.n - int64(b.buf.Len()); remaining > 0 {
+		if int64(len(p)) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// ReadTarGzEntries reads the files of a gzipped tar archive.
+// If stripRoot is set, the first path component is removed like the "package/" directory of npm packages.
+// The content of text files is kept up to maxContentSize, 0 to not keep any content.
+func ReadTarGzEntries(r io.Reader, stripRoot bool, maxContentSize int64) ([]*ContentEntry, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	entries := make([]*ContentEntry, 0, 10)
+	tr := tar.NewReader(zr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(hd.Name, "./")
+		if stripRoot {
+			if pos := strings.IndexByte(name, '/'); pos != -1 {
+				name = name[pos+1:]
+			}
+		}
+
+		entry, err := readContentEntry(name, tr, maxContentSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadZipEntries reads the files of a zip archive.
+// The content of text files is kept up to maxContentSize, 0 to not keep any content.
+func ReadZipEntries(r io.ReaderAt, size, maxContentSize int64) ([]*ContentEntry, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*ContentEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		entry, err := readContentEntry(f.Name, rc, maxContentSize)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadContentEntry reads a single file, e.g. a file of a generic package.
+// The content of a text file is kept up to maxContentSize, 0 to not keep any content.
+func ReadContentEntry(name string, r io.Reader, maxContentSize int64) (*ContentEntry, error) {
+	return readContentEntry(name, r, maxContentSize)
+}
+
+// ContentDiffStatus describes how a file differs between two package versions
+type ContentDiffStatus string
+
+// List of content diff statuses
+const (
+	ContentDiffStatusAdded    ContentDiffStatus = "added"
+	ContentDiffStatusRemoved  ContentDiffStatus = "removed"
+	ContentDiffStatusModified ContentDiffStatus = "modified"
+)
+
+// ContentDiffEntry is a file which differs between two package versions
+type ContentDiffEntry struct {
+	Path    string
+	Status  ContentDiffStatus
+	OldSize int64
+	NewSize int64
+	// Patch is a unified diff if the content of both sides is available
+	Patch string
+}
+
+// DiffContentEntries compares the files of two package versions, ordered by path.
+// Unchanged files are omitted.
+func DiffContentEntries(oldEntries, newEntries []*ContentEntry) []*ContentDiffEntry {
+	oldByPath := make(map[string]*ContentEntry, len(oldEntries))
+	for _, e := range oldEntries {
+		oldByPath[e.Path] = e
+	}
+	newByPath := make(map[string]*ContentEntry, len(newEntries))
+	for _, e := range newEntries {
+		newByPath[e.Path] = e
+	}
+
+	result := make([]*ContentDiffEntry, 0, 10)
+	for _, o := range oldEntries {
+		n, ok := newByPath[o.Path]
+		if !ok {
+			diff := &ContentDiffEntry{Path: o.Path, Status: ContentDiffStatusRemoved, OldSize: o.Size}
+			if o.Content != nil {
+				diff.Patch = UnifiedDiff(o.Path, string(o.Content), "")
+			}
+			result = append(result, diff)
+			continue
+		}
+		if o.HashSHA256 == n.HashSHA256 {
+			continue
+		}
+		diff := &ContentDiffEntry{Path: o.Path, Status: ContentDiffStatusModified, OldSize: o.Size, NewSize: n.Size}
+		if o.Content != nil && n.Content != nil {
+			diff.Patch = UnifiedDiff(o.Path, string(o.Content), string(n.Content))
+		}
+		result = append(result, diff)
+	}
+	for _, n := range newEntries {
+		if _, ok := oldByPath[n.Path]; ok {
+			continue
+		}
+		diff := &ContentDiffEntry{Path: n.Path, Status: ContentDiffStatusAdded, NewSize: n.Size}
+		if n.Content != nil {
+			diff.Patch = UnifiedDiff(n.Path, "", string(n.Content))
+		}
+		result = append(result, diff)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+const unifiedDiffContext = 3
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff creates a unified diff of two texts with three lines of context
+func UnifiedDiff(path, oldText, newText string) string {
+	// every distinct line is mapped to a rune to diff by lines
+	var lineTexts []string
+	runeOfLine := make(map[string]rune)
+	toRunes := func(text string) []rune {
+		result := make([]rune, 0, 50)
+		for _, line := range strings.SplitAfter(text, "\n") {
+			if line == "" {
+				continue
+			}
+			r, ok := runeOfLine[line]
+			if !ok {
+				r = rune(len(lineTexts))
+				if r >= 0xD800 {
+					r += 0x800 // skip the surrogates which are no valid runes
+				}
+				runeOfLine[line] = r
+				lineTexts = append(lineTexts, line)
+			}
+			result = append(result, r)
+		}
+		return result
+	}
+	lineOfRune := func(r rune) string {
+		if r >= 0xD800 {
+			r -= 0x800
+		}
+		return lineTexts[r]
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(toRunes(oldText), toRunes(newText), false)
+
+	lines := make([]diffLine, 0, 50)
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		}
+		for _, r := range d.Text {
+			lines = append(lines, diffLine{op, lineOfRune(r)})
+		}
+	}
+
+	var sb strings.Builder
+	oldName, newName := "a/"+path, "b/"+path
+	if oldText == "" {
+		oldName = "/dev/null"
+	}
+	if newText == "" {
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	oldLine, newLine := 0, 0 // lines before the current position
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// collect the changes which are close enough to share a hunk
+		start := i - unifiedDiffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*unifiedDiffContext {
+				break
+			}
+		}
+		end += unifiedDiffContext
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		oldStart, newStart := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != '+' {
+				oldCount++
+			}
+			if l.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		for _, l := range lines[i:end] {
+			if l.op != '+' {
+				oldLine++
+			}
+			if l.op != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats the start line and the number of lines of a hunk side
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTarGzEntries(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, f := range []struct{ name, content string }{
+		{"package/package.json", `{"name":"test"}`},
+		{"package/lib/index.js", "module.exports = {}\n"},
+		{"package/logo.png", "\x89PNG\r\n\x1a\n\x00\x00\x00"},
+	} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, zw.Close())
+
+	entries, err := ReadTarGzEntries(bytes.NewReader(buf.Bytes()), true, 1024)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "package.json", entries[0].Path)
+		assert.Equal(t, `{"name":"test"}`, string(entries[0].Content))
+		assert.Equal(t, "lib/index.js", entries[1].Path)
+		assert.EqualValues(t, 20, entries[1].Size)
+		assert.Equal(t, "logo.png", entries[2].Path)
+		assert.Nil(t, entries[2].Content)
+	}
+
+	entries, err = ReadTarGzEntries(bytes.NewReader(buf.Bytes()), false, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "package/package.json", entries[0].Path)
+		assert.Nil(t, entries[0].Content)
+	}
+}
+
+func TestReadZipEntries(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("src/")
+	assert.NoError(t, err)
+	w, err := zw.Create("src/Test.php")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("<?php\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	entries, err := ReadZipEntries(bytes.NewReader(buf.Bytes()), int64(buf.Len()), 3)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "src/Test.php", entries[0].Path)
+		assert.EqualValues(t, 6, entries[0].Size)
+		assert.Equal(t, "00aba3928cd622ad67c0f81ecd5aa8699e07f8f4908c045fe34f6a2dc0067b37", entries[0].HashSHA256)
+		assert.Nil(t, entries[0].Content, "content is larger than the maximum size")
+	}
+}
+
+func TestDiffContentEntries(t *testing.T) {
+	oldEntries := []*ContentEntry{
+		{Path: "a.txt", Size: 2, HashSHA256: "1", Content: []byte("a\n")},
+		{Path: "b.txt", Size: 2, HashSHA256: "2", Content: []byte("b\n")},
+		{Path: "c.bin", Size: 10, HashSHA256: "3"},
+	}
+	newEntries := []*ContentEntry{
+		{Path: "a.txt", Size: 2, HashSHA256: "1", Content: []byte("a\n")},
+		{Path: "b.txt", Size: 3, HashSHA256: "4", Content: []byte("bb\n")},
+		{Path: "0.txt", Size: 2, HashSHA256: "5", Content: []byte("0\n")},
+	}
+
+	diff := DiffContentEntries(oldEntries, newEntries)
+	assert.Equal(t, []*ContentDiffEntry{
+		{Path: "0.txt", Status: ContentDiffStatusAdded, NewSize: 2, Patch: "--- /dev/null\n+++ b/0.txt\n@@ -0,0 +1 @@\n+0\n"},
+		{Path: "b.txt", Status: ContentDiffStatusModified, OldSize: 2, NewSize: 3, Patch: "--- a/b.txt\n+++ b/b.txt\n@@ -1 +1 @@\n-b\n+bb\n"},
+		{Path: "c.bin", Status: ContentDiffStatusRemoved, OldSize: 10},
+	}, diff)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20"
+	newText := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20\n"
+
+	assert.Equal(t, `--- a/f
++++ b/f
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -17,4 +17,4 @@
+ 17
+ 18
+ 19
+-20
+\ No newline at end of file
++20
+`, UnifiedDiff("f", oldText, newText))
+
+	// changes separated by at most six lines share a hunk
+	assert.Equal(t, `--- a/f
++++ b/f
+@@ -1,11 +1,11 @@
+-1
++one
+ 2
+ 3
+ 4
+ 5
+ 6
+ 7
+-8
++eight
+ 9
+ 10
+ 11
+`, UnifiedDiff("f", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "one\n2\n3\n4\n5\n6\n7\neight\n9\n10\n11\n12\n"))
+}
@@ -6,8 +6,10 @@ package packages
 
 import (
 	"io"
+	"net/url"
 	"path"
 
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 )
 
@@ -30,6 +32,17 @@ func (s *ContentStore) Get(key BlobHash256Key) (storage.Object, error) {
 	return s.store.Open(KeyToRelativePath(key))
 }
 
+// ShouldServeDirect returns true if package blobs should be served directly by the storage
+func (s *ContentStore) ShouldServeDirect() bool {
+	return setting.Packages.Storage.ServeDirect
+}
+
+// GetServeDirectURL returns a presigned url to the package blob which is served as filename.
+// storage.ErrURLNotSupported is returned if the storage does not support it.
+func (s *ContentStore) GetServeDirectURL(key BlobHash256Key, filename string) (*url.URL, error) {
+	return s.store.URL(KeyToRelativePath(key), filename)
+}
+
 // Save stores a package blob
 func (s *ContentStore) Save(key BlobHash256Key, r io.Reader, size int64) error {
 	_, err := s.store.Save(KeyToRelativePath(key), r, size)
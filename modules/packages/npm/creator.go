@@ -84,6 +84,8 @@ type PackageMetadataVersion struct {
 	Readme               string              `json:"readme,omitempty"`
 	Dist                 PackageDistribution `json:"dist"`
 	Maintainers          []User              `json:"maintainers,omitempty"`
+	// Downloads is the number of downloads of the version, only set in the package metadata of the registry
+	Downloads int64 `json:"downloads,omitempty"`
 }
 
 // PackageDistribution https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#version
@@ -155,94 +157,101 @@ func ParsePackage(r io.Reader) (*Package, error) {
 	}
 
 	for _, meta := range upload.Versions {
-		if !validateName(meta.Name) {
-			return nil, ErrInvalidPackageName
+		// missing or invalid data is reported by NewPackage after the metadata is validated
+		var data []byte
+		for _, attachment := range upload.Attachments {
+			data, _ = base64.StdEncoding.DecodeString(attachment.Data)
+			break
 		}
 
-		v, err := version.NewSemver(meta.Version)
+		p, err := NewPackage(meta, data)
 		if err != nil {
-			return nil, ErrInvalidPackageVersion
+			return nil, err
 		}
 
-		scope := ""
-		name := meta.Name
-		nameParts := strings.SplitN(meta.Name, "/", 2)
-		if len(nameParts) == 2 {
-			scope = nameParts[0]
-			name = nameParts[1]
+		for tag := range upload.DistTags {
+			p.DistTags = append(p.DistTags, tag)
 		}
 
-		if !validation.IsValidURL(meta.Homepage) {
-			meta.Homepage = ""
-		}
+		return p, nil
+	}
 
-		p := &Package{
-			Name:     meta.Name,
-			Version:  v.String(),
-			DistTags: make([]string, 0, 1),
-			Metadata: Metadata{
-				Scope:                   scope,
-				Name:                    name,
-				Description:             meta.Description,
-				Author:                  meta.Author.Name,
-				License:                 meta.License,
-				ProjectURL:              meta.Homepage,
-				Keywords:                meta.Keywords,
-				Dependencies:            meta.Dependencies,
-				DevelopmentDependencies: meta.DevDependencies,
-				PeerDependencies:        meta.PeerDependencies,
-				OptionalDependencies:    meta.OptionalDependencies,
-				Readme:                  meta.Readme,
-			},
-		}
+	return nil, ErrInvalidPackage
+}
 
-		for tag := range upload.DistTags {
-			p.DistTags = append(p.DistTags, tag)
-		}
+// NewPackage creates a npm package from the metadata of a version and its tarball.
+// The integrity of the tarball is verified against the metadata.
+func NewPackage(meta *PackageMetadataVersion, data []byte) (*Package, error) {
+	if !validateName(meta.Name) {
+		return nil, ErrInvalidPackageName
+	}
 
-		p.Filename = strings.ToLower(fmt.Sprintf("%s-%s.tgz", name, p.Version))
+	v, err := version.NewSemver(meta.Version)
+	if err != nil {
+		return nil, ErrInvalidPackageVersion
+	}
 
-		attachment := func() *PackageAttachment {
-			for _, a := range upload.Attachments {
-				return a
-			}
-			return nil
-		}()
-		if attachment == nil || len(attachment.Data) == 0 {
-			return nil, ErrInvalidAttachment
-		}
+	scope := ""
+	name := meta.Name
+	nameParts := strings.SplitN(meta.Name, "/", 2)
+	if len(nameParts) == 2 {
+		scope = nameParts[0]
+		name = nameParts[1]
+	}
 
-		data, err := base64.StdEncoding.DecodeString(attachment.Data)
-		if err != nil {
-			return nil, ErrInvalidAttachment
-		}
-		p.Data = data
+	if !validation.IsValidURL(meta.Homepage) {
+		meta.Homepage = ""
+	}
 
-		integrity := strings.SplitN(meta.Dist.Integrity, "-", 2)
-		if len(integrity) != 2 {
-			return nil, ErrInvalidIntegrity
-		}
-		integrityHash, err := base64.StdEncoding.DecodeString(integrity[1])
-		if err != nil {
-			return nil, ErrInvalidIntegrity
-		}
-		var hash []byte
-		switch integrity[0] {
-		case "sha1":
-			tmp := sha1.Sum(data)
-			hash = tmp[:]
-		case "sha512":
-			tmp := sha512.Sum512(data)
-			hash = tmp[:]
-		}
-		if !bytes.Equal(integrityHash, hash) {
-			return nil, ErrInvalidIntegrity
-		}
+	p := &Package{
+		Name:     meta.Name,
+		Version:  v.String(),
+		DistTags: make([]string, 0, 1),
+		Metadata: Metadata{
+			Scope:                   scope,
+			Name:                    name,
+			Description:             meta.Description,
+			Author:                  meta.Author.Name,
+			License:                 meta.License,
+			ProjectURL:              meta.Homepage,
+			Keywords:                meta.Keywords,
+			Dependencies:            meta.Dependencies,
+			DevelopmentDependencies: meta.DevDependencies,
+			PeerDependencies:        meta.PeerDependencies,
+			OptionalDependencies:    meta.OptionalDependencies,
+			Readme:                  meta.Readme,
+		},
+	}
 
-		return p, nil
+	p.Filename = strings.ToLower(fmt.Sprintf("%s-%s.tgz", name, p.Version))
+
+	if len(data) == 0 {
+		return nil, ErrInvalidAttachment
 	}
+	p.Data = data
 
-	return nil, ErrInvalidPackage
+	integrity := strings.SplitN(meta.Dist.Integrity, "-", 2)
+	if len(integrity) != 2 {
+		return nil, ErrInvalidIntegrity
+	}
+	integrityHash, err := base64.StdEncoding.DecodeString(integrity[1])
+	if err != nil {
+		return nil, ErrInvalidIntegrity
+	}
+	var hash []byte
+	switch integrity[0] {
+	case "sha1":
+		tmp := sha1.Sum(data)
+		hash = tmp[:]
+	case "sha512":
+		tmp := sha512.Sum512(data)
+		hash = tmp[:]
+	}
+	if !bytes.Equal(integrityHash, hash) {
+		return nil, ErrInvalidIntegrity
+	}
+
+	return p, nil
 }
 
 func validateName(name string) bool {
@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"strings"
 
+	"code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/validation"
 
 	"github.com/hashicorp/go-version"
@@ -35,6 +36,8 @@ type Package struct {
 	Name     string
 	Version  string
 	Metadata *Metadata
+	// ReleaseNotes is the section of the changelog which describes the version
+	ReleaseNotes string
 }
 
 // Metadata represents the metadata of a Pub package
@@ -65,7 +68,7 @@ func ParsePackage(r io.Reader) (*Package, error) {
 	defer gzr.Close()
 
 	var p *Package
-	var readme string
+	var readme, changelog string
 
 	tr := tar.NewReader(gzr)
 	for {
@@ -95,6 +98,12 @@ func ParsePackage(r io.Reader) (*Package, error) {
 				return nil, err
 			}
 			readme = string(data)
+		} else if strings.ToLower(hd.Name) == "changelog.md" {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			changelog = string(data)
 		}
 	}
 
@@ -103,6 +112,7 @@ func ParsePackage(r io.Reader) (*Package, error) {
 	}
 
 	p.Metadata.Readme = readme
+	p.ReleaseNotes = packages.ExtractChangelogSection(changelog, p.Version)
 
 	return p, nil
 }
@@ -102,6 +102,16 @@ func TestParsePackage(t *testing.T) {
 		assert.NotNil(t, pp)
 		assert.Equal(t, "readme", pp.Metadata.Readme)
 	})
+
+	t.Run("ValidWithChangelog", func(t *testing.T) {
+		changelog := "## 1.1.0\n\n- New feature\n\n## " + packageVersion + "\n\n- Bugfix\n"
+		data := createArchive(map[string][]byte{"pubspec.yaml": []byte(pubspecContent), "CHANGELOG.md": []byte(changelog)})
+
+		pp, err := ParsePackage(data)
+		assert.NoError(t, err)
+		assert.NotNil(t, pp)
+		assert.Equal(t, "- Bugfix", pp.ReleaseNotes)
+	})
 }
 
 func TestParsePubspecMetadata(t *testing.T) {
@@ -4,6 +4,43 @@
 
 package pypi
 
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"net/mail"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/validation"
+)
+
+var (
+	// ErrInvalidStructure indicates an invalid package structure
+	ErrInvalidStructure = errors.New("package structure is invalid")
+	// ErrInvalidName indicates an invalid package name
+	ErrInvalidName = errors.New("package name is invalid")
+	// ErrInvalidVersion indicates an invalid package version
+	ErrInvalidVersion = errors.New("package version is invalid")
+)
+
+var (
+	// https://www.python.org/dev/peps/pep-0503/#normalized-names
+	normalizer  = strings.NewReplacer(".", "-", "_", "-")
+	nameMatcher = regexp.MustCompile(`\A[a-z0-9\.\-_]+\z`)
+
+	// https://www.python.org/dev/peps/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+	versionMatcher = regexp.MustCompile(`^([1-9][0-9]*!)?(0|[1-9][0-9]*)(\.(0|[1-9][0-9]*))*((a|b|rc)(0|[1-9][0-9]*))?(\.post(0|[1-9][0-9]*))?(\.dev(0|[1-9][0-9]*))?$`)
+)
+
+// Package represents a PyPI package
+type Package struct {
+	Name     string
+	Version  string
+	Metadata *Metadata
+}
+
 // Metadata represents the metadata of a PyPI package
 type Metadata struct {
 	Author          string `json:"author,omitempty"`
@@ -14,3 +51,74 @@ type Metadata struct {
 	License         string `json:"license,omitempty"`
 	RequiresPython  string `json:"requires_python,omitempty"`
 }
+
+// ParseWheel parses the metadata of a wheel (.whl) package
+// https://peps.python.org/pep-0427/#the-dist-info-directory
+func ParseWheel(r io.ReaderAt, size int64) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range zr.File {
+		dir, name := path.Split(file.Name)
+		if name == "METADATA" && strings.Count(dir, "/") == 1 && strings.HasSuffix(dir, ".dist-info/") {
+			f, err := zr.Open(file.Name)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			return parseMetadataFile(f)
+		}
+	}
+
+	return nil, ErrInvalidStructure
+}
+
+// parseMetadataFile parses the METADATA file which uses the email header format
+// https://packaging.python.org/en/latest/specifications/core-metadata/
+func parseMetadataFile(r io.Reader) (*Package, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	name := normalizer.Replace(strings.ToLower(msg.Header.Get("Name")))
+	if !nameMatcher.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	version := msg.Header.Get("Version")
+	if !versionMatcher.MatchString(version) {
+		return nil, ErrInvalidVersion
+	}
+
+	// since metadata version 2.1 the long description is stored in the body
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	longDescription := strings.TrimSpace(string(body))
+	if longDescription == "" {
+		longDescription = msg.Header.Get("Description")
+	}
+
+	projectURL := msg.Header.Get("Home-page")
+	if !validation.IsValidURL(projectURL) {
+		projectURL = ""
+	}
+
+	return &Package{
+		Name:    name,
+		Version: version,
+		Metadata: &Metadata{
+			Author:          msg.Header.Get("Author"),
+			LongDescription: longDescription,
+			Summary:         msg.Header.Get("Summary"),
+			ProjectURL:      projectURL,
+			License:         msg.Header.Get("License"),
+			RequiresPython:  msg.Header.Get("Requires-Python"),
+		},
+	}, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	packageName     = "Test_Package"
+	packageVersion  = "1.0.1"
+	author          = "gitea"
+	summary         = "Package Summary"
+	longDescription = "Package Description"
+	projectURL      = "https://gitea.io"
+	license         = "MIT"
+	requiresPython  = ">=3.7"
+)
+
+func TestParseWheel(t *testing.T) {
+	createWheel := func(filename, content string) *bytes.Reader {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		w, _ := archive.Create(filename)
+		w.Write([]byte(content))
+		archive.Close()
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	createMetadata := func(name, version string) string {
+		return strings.Join([]string{
+			"Metadata-Version: 2.1",
+			"Name: " + name,
+			"Version: " + version,
+			"Summary: " + summary,
+			"Home-page: " + projectURL,
+			"Author: " + author,
+			"License: " + license,
+			"Requires-Python: " + requiresPython,
+			"",
+			longDescription,
+		}, "\n")
+	}
+
+	t.Run("MissingMetadataFile", func(t *testing.T) {
+		r := createWheel("dummy.txt", "")
+
+		p, err := ParseWheel(r, r.Size())
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidStructure)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		r := createWheel("test_package-1.0.1.dist-info/METADATA", createMetadata("", packageVersion))
+
+		p, err := ParseWheel(r, r.Size())
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidName)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		r := createWheel("test_package-1.0.1.dist-info/METADATA", createMetadata(packageName, "1.0-invalid"))
+
+		p, err := ParseWheel(r, r.Size())
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidVersion)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		r := createWheel("test_package-1.0.1.dist-info/METADATA", createMetadata(packageName, packageVersion))
+
+		p, err := ParseWheel(r, r.Size())
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+
+		assert.Equal(t, "test-package", p.Name)
+		assert.Equal(t, packageVersion, p.Version)
+		assert.Equal(t, author, p.Metadata.Author)
+		assert.Equal(t, summary, p.Metadata.Summary)
+		assert.Equal(t, longDescription, p.Metadata.LongDescription)
+		assert.Equal(t, projectURL, p.Metadata.ProjectURL)
+		assert.Equal(t, license, p.Metadata.License)
+		assert.Equal(t, requiresPython, p.Metadata.RequiresPython)
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxReleaseNotesSize is the maximum size of the release notes of a package version in bytes
+const MaxReleaseNotesSize = 65535
+
+// TruncateReleaseNotes shortens release notes which are larger than MaxReleaseNotesSize without splitting a character
+func TruncateReleaseNotes(notes string) string {
+	if len(notes) <= MaxReleaseNotesSize {
+		return notes
+	}
+	cut := MaxReleaseNotesSize
+	for cut > 0 && !utf8.RuneStart(notes[cut]) {
+		cut--
+	}
+	return notes[:cut]
+}
+
+// ExtractChangelogSection returns the section of a markdown changelog which describes the version.
+// The section starts with a heading which contains the version, for example "## 1.2.0", "## [1.2.0] - 2022-10-01"
+// or "# v1.2.0", and ends before the next heading of the same or a higher level.
+// An empty string is returned if the changelog has no section for the version.
+func ExtractChangelogSection(changelog, version string) string {
+	var section []string
+	level := 0
+	inCodeBlock := false
+	for _, line := range strings.Split(strings.ReplaceAll(changelog, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		headingLevel, title := 0, ""
+		if !inCodeBlock {
+			headingLevel, title = parseHeading(line)
+		}
+
+		if level == 0 {
+			if headingLevel > 0 && headingContainsVersion(title, version) {
+				level = headingLevel
+			}
+			continue
+		}
+		if headingLevel > 0 && headingLevel <= level {
+			break
+		}
+		section = append(section, line)
+	}
+	return strings.TrimSpace(strings.Join(section, "\n"))
+}
+
+// parseHeading returns the level and the title of an ATX heading or 0 if the line is no heading
+func parseHeading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
+
+func headingContainsVersion(title, version string) bool {
+	version = strings.ToLower(version)
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r == '.' || r == '-' || r == '+' || r == '_')
+	})
+	for _, field := range fields {
+		if field == version || field == "v"+version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractChangelogSection(t *testing.T) {
+	changelog := `# Changelog
+
+## [1.2.0] - 2022-10-01
+
+### Added
+
+- Support for streams
+
+` + "```" + `
+# not a heading
+` + "```" + `
+
+## v1.1.0
+
+- Fix a crash
+## 1.0.0
+Initial release
+`
+
+	assert.Equal(t, "### Added\n\n- Support for streams\n\n```\n# not a heading\n```", ExtractChangelogSection(changelog, "1.2.0"))
+	assert.Equal(t, "- Fix a crash", ExtractChangelogSection(changelog, "1.1.0"))
+	assert.Equal(t, "Initial release", ExtractChangelogSection(changelog, "1.0.0"))
+	assert.Empty(t, ExtractChangelogSection(changelog, "1.0"))
+	assert.Empty(t, ExtractChangelogSection(changelog, "2.0.0"))
+	assert.Empty(t, ExtractChangelogSection("#1.0.0\ntext", "1.0.0"))
+}
+
+func TestTruncateReleaseNotes(t *testing.T) {
+	assert.Equal(t, "notes", TruncateReleaseNotes("notes"))
+
+	notes := TruncateReleaseNotes(strings.Repeat("a", MaxReleaseNotesSize-1) + "äb")
+	assert.Len(t, notes, MaxReleaseNotesSize-1)
+}
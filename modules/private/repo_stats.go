@@ -0,0 +1,66 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// RecalculateRepoStatsOptions represents the options for the recalculate-repo-stats call
+type RecalculateRepoStatsOptions struct {
+	Kinds []string
+}
+
+// RecalculateRepoStats starts the recalculation of the statistics of all repositories
+func RecalculateRepoStats(ctx context.Context, kinds []string) (*api.RepoStatsRecalculation, int, string) {
+	reqURL := setting.LocalURL + "api/internal/manager/repo-stats/recalculation"
+
+	req := newInternalRequest(ctx, reqURL, "POST")
+	req = req.Header("Content-Type", "application/json")
+	jsonBytes, _ := json.Marshal(RecalculateRepoStatsOptions{
+		Kinds: kinds,
+	})
+	req.Body(jsonBytes)
+	return doRepoStatsRecalculationRequest(req.Response())
+}
+
+// GetRepoStatsRecalculation returns the progress of the latest recalculation of the repository statistics
+func GetRepoStatsRecalculation(ctx context.Context) (*api.RepoStatsRecalculation, int, string) {
+	reqURL := setting.LocalURL + "api/internal/manager/repo-stats/recalculation"
+
+	req := newInternalRequest(ctx, reqURL, "GET")
+	return doRepoStatsRecalculationRequest(req.Response())
+}
+
+// CancelRepoStatsRecalculation cancels the running recalculation of the repository statistics
+func CancelRepoStatsRecalculation(ctx context.Context) (*api.RepoStatsRecalculation, int, string) {
+	reqURL := setting.LocalURL + "api/internal/manager/repo-stats/recalculation/cancel"
+
+	req := newInternalRequest(ctx, reqURL, "POST")
+	return doRepoStatsRecalculationRequest(req.Response())
+}
+
+func doRepoStatsRecalculationRequest(resp *http.Response, err error) (*api.RepoStatsRecalculation, int, string) {
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Unable to contact gitea: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, decodeJSONError(resp).Err
+	}
+
+	r := &api.RepoStatsRecalculation{}
+	if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Response body Unmarshal error: %v", err.Error())
+	}
+	return r, http.StatusOK, ""
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pushvalidation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/gobwas/glob"
+)
+
+// File represents a file added or modified by a push
+type File struct {
+	Path string
+	Size int64
+
+	readContent func() ([]byte, error)
+	content     []byte
+	contentErr  error
+	once        sync.Once
+}
+
+// NewFile creates a file, the content is only read if a validator needs it
+func NewFile(path string, size int64, readContent func() ([]byte, error)) *File {
+	return &File{
+		Path:        path,
+		Size:        size,
+		readContent: readContent,
+	}
+}
+
+// Content returns the content of the file
+func (f *File) Content() ([]byte, error) {
+	f.once.Do(func() {
+		f.content, f.contentErr = f.readContent()
+	})
+	return f.content, f.contentErr
+}
+
+// Validator checks a pushed file
+type Validator interface {
+	// Validate returns a non-empty reason if the file is rejected
+	Validate(f *File) (string, error)
+}
+
+// Factory creates a validator from its configuration
+type Factory func(cfg *setting.PushValidator) (Validator, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a validator type available for the [push_validation.*] sections.
+// It must be called before Init.
+func Register(typ string, factory Factory) {
+	factories[typ] = factory
+}
+
+type rule struct {
+	cfg       *setting.PushValidator
+	validator Validator
+}
+
+var rules []*rule
+
+// Init creates the validators configured in the settings
+func Init() error {
+	rules = make([]*rule, 0, len(setting.PushValidators))
+	for _, cfg := range setting.PushValidators {
+		factory, ok := factories[cfg.Type]
+		if !ok {
+			return fmt.Errorf("push validator %s has unknown type %q", cfg.Name, cfg.Type)
+		}
+		validator, err := factory(cfg)
+		if err != nil {
+			return fmt.Errorf("push validator %s: %w", cfg.Name, err)
+		}
+		rules = append(rules, &rule{
+			cfg:       cfg,
+			validator: validator,
+		})
+	}
+	return nil
+}
+
+func matchAny(globs []glob.Glob, s string, matchEmpty bool) bool {
+	if len(globs) == 0 {
+		return matchEmpty
+	}
+	s = strings.ToLower(s)
+	for _, g := range globs {
+		if g.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func rulesForRepository(repoFullName string) []*rule {
+	matched := make([]*rule, 0, len(rules))
+	for _, r := range rules {
+		if matchAny(r.cfg.Repositories, repoFullName, true) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// HasValidators returns true if validators apply to the repository
+func HasValidators(repoFullName string) bool {
+	return len(rulesForRepository(repoFullName)) > 0
+}
+
+// Violation describes a file rejected by a validator
+type Violation struct {
+	Validator string
+	Path      string
+	Reason    string
+	Message   string
+}
+
+// Validate runs all validators applying to the repository on the files and returns the violations
+func Validate(repoFullName string, files []*File) ([]*Violation, error) {
+	matched := rulesForRepository(repoFullName)
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	violations := make([]*Violation, 0, 5)
+	for _, f := range files {
+		for _, r := range matched {
+			if !matchAny(r.cfg.Files, f.Path, true) {
+				continue
+			}
+			reason, err := r.validator.Validate(f)
+			if err != nil {
+				return nil, fmt.Errorf("push validator %s failed for %s: %w", r.cfg.Name, f.Path, err)
+			}
+			if reason != "" {
+				violations = append(violations, &Violation{
+					Validator: r.cfg.Name,
+					Path:      f.Path,
+					Reason:    reason,
+					Message:   r.cfg.Message,
+				})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		log.Trace("Push to %s rejected by %d push validation violations", repoFullName, len(violations))
+	}
+	return violations, nil
+}
+
+// FormatViolations creates the rejection message shown to the pusher
+func FormatViolations(refName string, violations []*Violation) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "push to %s rejected by %d push validation violation(s):", refName, len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(&sb, "\n- %s [%s]: %s", v.Path, v.Validator, v.Reason)
+		if v.Message != "" {
+			fmt.Fprintf(&sb, "\n  %s", v.Message)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pushvalidation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/gobwas/glob"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFile(path, content string) *File {
+	return NewFile(path, int64(len(content)), func() ([]byte, error) {
+		return []byte(content), nil
+	})
+}
+
+func TestValidate(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	assert.NoError(t, os.WriteFile(schemaPath, []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"}
+		}
+	}`), 0o644))
+
+	oldValidators := setting.PushValidators
+	defer func() {
+		setting.PushValidators = oldValidators
+	}()
+
+	setting.PushValidators = []*setting.PushValidator{
+		{
+			Name:  "secrets",
+			Type:  "forbidden_path",
+			Files: []glob.Glob{glob.MustCompile("**.pem", '/')},
+		},
+		{
+			Name:         "size",
+			Type:         "max_size",
+			Repositories: []glob.Glob{glob.MustCompile("user2/*", '/')},
+			MaxSize:      10,
+		},
+		{
+			Name:    "config",
+			Type:    "json_schema",
+			Files:   []glob.Glob{glob.MustCompile("config/*", '/')},
+			Schema:  schemaPath,
+			Message: "See the documentation of the configuration.",
+		},
+	}
+	assert.NoError(t, Init())
+
+	assert.True(t, HasValidators("user2/repo1"))
+
+	t.Run("Valid", func(t *testing.T) {
+		violations, err := Validate("user5/repo4", []*File{
+			newTestFile("README.md", "readme content"),
+			newTestFile("config/app.json", `{"name": "gitea"}`),
+			newTestFile("config/app.yaml", "name: gitea\n"),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		violations, err := Validate("user2/repo1", []*File{
+			newTestFile("keys/server.PEM", "x"),
+			newTestFile("README.md", "readme content"),
+			newTestFile("config/app.json", `{"name": `),
+			newTestFile("config/app.yml", "name: 1\n"),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, violations, 4)
+
+		assert.Equal(t, "secrets", violations[0].Validator)
+		assert.Equal(t, "keys/server.PEM", violations[0].Path)
+		assert.Equal(t, "size", violations[1].Validator)
+		assert.Equal(t, "README.md", violations[1].Path)
+		assert.Equal(t, "config", violations[2].Validator)
+		assert.Contains(t, violations[2].Reason, "invalid JSON")
+		assert.Equal(t, "See the documentation of the configuration.", violations[2].Message)
+		assert.Equal(t, "config", violations[3].Validator)
+		assert.Contains(t, violations[3].Reason, "schema validation failed")
+
+		msg := FormatViolations("main", violations)
+		assert.Contains(t, msg, "push to main rejected by 4 push validation violation(s):")
+		assert.Contains(t, msg, "- keys/server.PEM [secrets]: path is forbidden")
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		setting.PushValidators = []*setting.PushValidator{{Name: "unknown", Type: "unknown"}}
+		assert.Error(t, Init())
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pushvalidation
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v2"
+)
+
+// maxContentSize is the maximum size of files which get parsed by a validator
+const maxContentSize = 10 * 1024 * 1024
+
+func init() {
+	Register("forbidden_path", newForbiddenPathValidator)
+	Register("max_size", newMaxSizeValidator)
+	Register("json", newJSONValidator)
+	Register("yaml", newYAMLValidator)
+	Register("json_schema", newJSONSchemaValidator)
+}
+
+type forbiddenPathValidator struct{}
+
+func newForbiddenPathValidator(cfg *setting.PushValidator) (Validator, error) {
+	if len(cfg.Files) == 0 {
+		return nil, errors.New("FILES must be set")
+	}
+	return &forbiddenPathValidator{}, nil
+}
+
+func (v *forbiddenPathValidator) Validate(f *File) (string, error) {
+	return "path is forbidden", nil
+}
+
+type maxSizeValidator struct {
+	maxSize int64
+}
+
+func newMaxSizeValidator(cfg *setting.PushValidator) (Validator, error) {
+	if cfg.MaxSize <= 0 {
+		return nil, errors.New("MAX_SIZE must be set")
+	}
+	return &maxSizeValidator{maxSize: cfg.MaxSize}, nil
+}
+
+func (v *maxSizeValidator) Validate(f *File) (string, error) {
+	if f.Size > v.maxSize {
+		return fmt.Sprintf("file size %s exceeds the maximum of %s", base.FileSize(f.Size), base.FileSize(v.maxSize)), nil
+	}
+	return "", nil
+}
+
+// parseContent decodes JSON or YAML files into a value usable for schema validation
+func parseContent(f *File, isYAML bool) (interface{}, string, error) {
+	if f.Size > maxContentSize {
+		return nil, fmt.Sprintf("file size %s exceeds the maximum of %s for content validation", base.FileSize(f.Size), base.FileSize(maxContentSize)), nil
+	}
+
+	content, err := f.Content()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var v interface{}
+	if isYAML {
+		if err := yaml.Unmarshal(content, &v); err != nil {
+			return nil, fmt.Sprintf("invalid YAML: %v", err), nil
+		}
+		if v, err = toStringKeys(v); err != nil {
+			return nil, fmt.Sprintf("invalid YAML: %v", err), nil
+		}
+		return v, "", nil
+	}
+	if err := json.Unmarshal(content, &v); err != nil {
+		return nil, fmt.Sprintf("invalid JSON: %v", err), nil
+	}
+	return v, "", nil
+}
+
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+type syntaxValidator struct {
+	isYAML bool
+}
+
+func newJSONValidator(cfg *setting.PushValidator) (Validator, error) {
+	return &syntaxValidator{isYAML: false}, nil
+}
+
+func newYAMLValidator(cfg *setting.PushValidator) (Validator, error) {
+	return &syntaxValidator{isYAML: true}, nil
+}
+
+func (v *syntaxValidator) Validate(f *File) (string, error) {
+	_, reason, err := parseContent(f, v.isYAML)
+	return reason, err
+}
+
+type jsonSchemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+func newJSONSchemaValidator(cfg *setting.PushValidator) (Validator, error) {
+	if cfg.Schema == "" {
+		return nil, errors.New("SCHEMA must be set")
+	}
+	schema, err := jsonschema.NewCompiler().Compile(cfg.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonSchemaValidator{schema: schema}, nil
+}
+
+// Validate checks JSON files and, determined by the file extension, YAML files against the schema
+func (v *jsonSchemaValidator) Validate(f *File) (string, error) {
+	value, reason, err := parseContent(f, isYAMLFile(f.Path))
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	if err := v.schema.Validate(value); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			return fmt.Sprintf("schema validation failed: %s", validationErr.Error()), nil
+		}
+		return "", err
+	}
+	return "", nil
+}
+
+func toStringKeys(val interface{}) (interface{}, error) {
+	var err error
+	switch val := val.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for k, v := range val {
+			k, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("found non-string key %T %s", k, k)
+			}
+			m[k], err = toStringKeys(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case []interface{}:
+		l := make([]interface{}, len(val))
+		for i, v := range val {
+			l[i], err = toStringKeys(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return l, nil
+	default:
+		return val, nil
+	}
+}
@@ -31,6 +31,7 @@ type PersistableChannelQueueConfiguration struct {
 	BlockTimeout time.Duration
 	BoostTimeout time.Duration
 	BoostWorkers int
+	AutoscaleConfiguration
 }
 
 // PersistableChannelQueue wraps a channel queue and level queue together
@@ -74,6 +75,8 @@ func NewPersistableChannelQueue(handle HandlerFunc, cfg, exemplar interface{}) (
 			BoostWorkers: config.BoostWorkers,
 			MaxWorkers:   config.MaxWorkers,
 			Name:         config.Name + "-channel",
+
+			AutoscaleConfiguration: config.AutoscaleConfiguration,
 		},
 		Workers: config.Workers,
 	}, exemplar)
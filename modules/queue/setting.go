@@ -67,7 +67,7 @@ func CreateQueue(name string, handle HandlerFunc, exemplar interface{}) Queue {
 	}
 
 	// Sanity check configuration
-	if q.Workers == 0 && (q.BoostTimeout == 0 || q.BoostWorkers == 0 || q.MaxWorkers == 0) {
+	if q.Workers == 0 && !q.Autoscale && (q.BoostTimeout == 0 || q.BoostWorkers == 0 || q.MaxWorkers == 0) {
 		log.Warn("Queue: %s is configured to be non-scaling and have no workers\n - this configuration is likely incorrect and could cause Gitea to block", q.Name)
 		if pausable, ok := returnable.(Pausable); ok {
 			log.Warn("Queue: %s is being paused to prevent data-loss, add workers manually and unpause.", q.Name)
@@ -115,7 +115,7 @@ func CreateUniqueQueue(name string, handle HandlerFunc, exemplar interface{}) Un
 	}
 
 	// Sanity check configuration
-	if q.Workers == 0 && (q.BoostTimeout == 0 || q.BoostWorkers == 0 || q.MaxWorkers == 0) {
+	if q.Workers == 0 && !q.Autoscale && (q.BoostTimeout == 0 || q.BoostWorkers == 0 || q.MaxWorkers == 0) {
 		log.Warn("Queue: %s is configured to be non-scaling and have no workers\n - this configuration is likely incorrect and could cause Gitea to block", q.Name)
 		if pausable, ok := returnable.(Pausable); ok {
 			log.Warn("Queue: %s is being paused to prevent data-loss, add workers manually and unpause.", q.Name)
@@ -29,6 +29,7 @@ type PersistableChannelUniqueQueueConfiguration struct {
 	BlockTimeout time.Duration
 	BoostTimeout time.Duration
 	BoostWorkers int
+	AutoscaleConfiguration
 }
 
 // PersistableChannelUniqueQueue wraps a channel queue and level queue together
@@ -74,6 +75,8 @@ func NewPersistableChannelUniqueQueue(handle HandlerFunc, cfg, exemplar interfac
 			BoostWorkers: config.BoostWorkers,
 			MaxWorkers:   config.MaxWorkers,
 			Name:         config.Name + "-channel",
+
+			AutoscaleConfiguration: config.AutoscaleConfiguration,
 		},
 		Workers: config.Workers,
 	}, exemplar)
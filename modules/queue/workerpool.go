@@ -26,6 +26,8 @@ type WorkerPool struct {
 	// This is to allow 64 bit atomic operations on 32-bit machines.
 	// See: https://pkg.go.dev/sync/atomic#pkg-note-BUG & Gitea issue 19518
 	numInQueue         int64
+	handledNumber      int64
+	handledDuration    int64
 	lock               sync.Mutex
 	baseCtx            context.Context
 	baseCtxCancel      context.CancelFunc
@@ -42,6 +44,7 @@ type WorkerPool struct {
 	blockTimeout       time.Duration
 	boostTimeout       time.Duration
 	boostWorkers       int
+	autoscale          AutoscaleConfiguration
 }
 
 var (
@@ -58,6 +61,7 @@ type WorkerPoolConfiguration struct {
 	BoostTimeout time.Duration
 	BoostWorkers int
 	MaxWorkers   int
+	AutoscaleConfiguration
 }
 
 // NewWorkerPool creates a new worker pool
@@ -78,6 +82,14 @@ func NewWorkerPool(handle HandlerFunc, config WorkerPoolConfiguration) *WorkerPo
 		boostTimeout:       config.BoostTimeout,
 		boostWorkers:       config.BoostWorkers,
 		maxNumberOfWorkers: config.MaxWorkers,
+		autoscale:          config.AutoscaleConfiguration,
+	}
+
+	if pool.autoscale.isEnabled(pool.maxNumberOfWorkers) {
+		pool.autoscale.normalize(config.Name, pool.maxNumberOfWorkers)
+		go pool.runAutoscaler()
+	} else {
+		pool.autoscale.Autoscale = false
 	}
 
 	return pool
@@ -121,7 +133,7 @@ func (p *WorkerPool) HasNoWorkerScaling() bool {
 }
 
 func (p *WorkerPool) hasNoWorkerScaling() bool {
-	return p.numberOfWorkers == 0 && (p.boostTimeout == 0 || p.boostWorkers == 0 || p.maxNumberOfWorkers == 0)
+	return p.numberOfWorkers == 0 && !p.autoscale.Autoscale && (p.boostTimeout == 0 || p.boostWorkers == 0 || p.maxNumberOfWorkers == 0)
 }
 
 // zeroBoost will add a temporary boost worker for a no worker queue
@@ -500,11 +512,7 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 		case <-paused:
 			log.Trace("Worker for Queue %d Pausing", p.qid)
 			if len(data) > 0 {
-				log.Trace("Handling: %d data, %v", len(data), data)
-				if unhandled := p.handle(data...); unhandled != nil {
-					log.Error("Unhandled Data in queue %d", p.qid)
-				}
-				atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+				p.handleBatch(data)
 			}
 			_, resumed := p.IsPausedIsResumed()
 			select {
@@ -523,11 +531,7 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 			// go back around
 		case <-ctx.Done():
 			if len(data) > 0 {
-				log.Trace("Handling: %d data, %v", len(data), data)
-				if unhandled := p.handle(data...); unhandled != nil {
-					log.Error("Unhandled Data in queue %d", p.qid)
-				}
-				atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+				p.handleBatch(data)
 			}
 			log.Trace("Worker shutting down")
 			return
@@ -535,11 +539,7 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 			if !ok {
 				// the dataChan has been closed - we should finish up:
 				if len(data) > 0 {
-					log.Trace("Handling: %d data, %v", len(data), data)
-					if unhandled := p.handle(data...); unhandled != nil {
-						log.Error("Unhandled Data in queue %d", p.qid)
-					}
-					atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+					p.handleBatch(data)
 				}
 				log.Trace("Worker shutting down")
 				return
@@ -548,11 +548,7 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 			util.StopTimer(timer)
 
 			if len(data) >= p.batchLength {
-				log.Trace("Handling: %d data, %v", len(data), data)
-				if unhandled := p.handle(data...); unhandled != nil {
-					log.Error("Unhandled Data in queue %d", p.qid)
-				}
-				atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+				p.handleBatch(data)
 				data = make([]Data, 0, p.batchLength)
 			} else {
 				timer.Reset(delay)
@@ -560,13 +556,20 @@ func (p *WorkerPool) doWork(ctx context.Context) {
 		case <-timer.C:
 			delay = time.Millisecond * 100
 			if len(data) > 0 {
-				log.Trace("Handling: %d data, %v", len(data), data)
-				if unhandled := p.handle(data...); unhandled != nil {
-					log.Error("Unhandled Data in queue %d", p.qid)
-				}
-				atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+				p.handleBatch(data)
 				data = make([]Data, 0, p.batchLength)
 			}
 		}
 	}
 }
+
+// handleBatch passes the data to the handler and records the time needed for the autoscaler
+func (p *WorkerPool) handleBatch(data []Data) {
+	log.Trace("Handling: %d data, %v", len(data), data)
+	start := time.Now()
+	if unhandled := p.handle(data...); unhandled != nil {
+		log.Error("Unhandled Data in queue %d", p.qid)
+	}
+	p.recordLatency(len(data), time.Since(start))
+	atomic.AddInt64(&p.numInQueue, -1*int64(len(data)))
+}
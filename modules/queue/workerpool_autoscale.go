@@ -0,0 +1,175 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// AutoscaleConfiguration configures the automatic scaling of the workers of a WorkerPool.
+// Every ScaleInterval the backlog and the average processing latency are checked:
+// workers are added if the backlog per worker exceeds ScaleUpBacklog or the latency exceeds ScaleUpLatency,
+// a worker is removed if the backlog per worker falls below ScaleDownBacklog and the latency is low.
+type AutoscaleConfiguration struct {
+	Autoscale        bool
+	MinWorkers       int
+	ScaleInterval    time.Duration
+	ScaleUpBacklog   int
+	ScaleDownBacklog int
+	ScaleUpLatency   time.Duration
+	ScaleCooldown    time.Duration
+}
+
+// normalize fixes inconsistent settings so that scaling up and down can't flap between each other
+func (c *AutoscaleConfiguration) normalize(name string, maxWorkers int) {
+	if c.ScaleUpBacklog < 1 {
+		c.ScaleUpBacklog = 1
+	}
+	if c.ScaleDownBacklog >= c.ScaleUpBacklog {
+		log.Warn("Queue: %s SCALE_DOWN_BACKLOG (%d) must be lower than SCALE_UP_BACKLOG (%d), using %d", name, c.ScaleDownBacklog, c.ScaleUpBacklog, c.ScaleUpBacklog/2)
+		c.ScaleDownBacklog = c.ScaleUpBacklog / 2
+	}
+	if c.ScaleDownBacklog < 0 {
+		c.ScaleDownBacklog = 0
+	}
+	if c.MinWorkers < 0 {
+		c.MinWorkers = 0
+	}
+	if maxWorkers >= 0 && c.MinWorkers > maxWorkers {
+		c.MinWorkers = maxWorkers
+	}
+}
+
+// isEnabled returns if the pool will be scaled automatically
+func (c *AutoscaleConfiguration) isEnabled(maxWorkers int) bool {
+	return c.Autoscale && c.ScaleInterval > 0 && maxWorkers != 0
+}
+
+// scaleDelta returns the number of workers to add (positive) or to remove (negative).
+// The distance between the thresholds for scaling up and down and the cooldown after each change
+// prevent the pool from oscillating around a threshold.
+func (c *AutoscaleConfiguration) scaleDelta(workers, maxWorkers int, backlog int64, latency, sinceLastScale time.Duration) int {
+	if workers < c.MinWorkers {
+		return c.MinWorkers - workers
+	}
+	if maxWorkers >= 0 && workers > maxWorkers {
+		return maxWorkers - workers
+	}
+	if sinceLastScale < c.ScaleCooldown {
+		return 0
+	}
+
+	if backlog > 0 && (workers == 0 ||
+		backlog > int64(workers)*int64(c.ScaleUpBacklog) ||
+		(c.ScaleUpLatency > 0 && latency > c.ScaleUpLatency)) {
+		// scale up to the number of workers needed for the backlog but at least by one
+		target := int((backlog + int64(c.ScaleUpBacklog) - 1) / int64(c.ScaleUpBacklog))
+		if target <= workers {
+			target = workers + 1
+		}
+		if maxWorkers >= 0 && target > maxWorkers {
+			target = maxWorkers
+		}
+		return target - workers
+	}
+
+	if workers > c.MinWorkers &&
+		backlog < int64(workers)*int64(c.ScaleDownBacklog) &&
+		(c.ScaleUpLatency <= 0 || latency < c.ScaleUpLatency/2) {
+		// scale down slowly, the next check will remove more workers if they are still not needed
+		return -1
+	}
+	return 0
+}
+
+// autoscaledWorker is a worker added by the autoscaler
+type autoscaledWorker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// recordLatency records the time needed to handle a batch of data
+func (p *WorkerPool) recordLatency(number int, duration time.Duration) {
+	if !p.autoscale.Autoscale {
+		return
+	}
+	atomic.AddInt64(&p.handledDuration, int64(duration))
+	atomic.AddInt64(&p.handledNumber, int64(number))
+}
+
+// averageLatency returns the average time needed to handle one datum since the last call
+func (p *WorkerPool) averageLatency() time.Duration {
+	number := atomic.SwapInt64(&p.handledNumber, 0)
+	duration := atomic.SwapInt64(&p.handledDuration, 0)
+	if number <= 0 {
+		return 0
+	}
+	return time.Duration(duration / number)
+}
+
+// runAutoscaler adds and removes workers until the pool is shut down.
+// Only workers added by the autoscaler itself are removed, the configured and boosted workers are left alone.
+func (p *WorkerPool) runAutoscaler() {
+	ticker := time.NewTicker(p.autoscale.ScaleInterval)
+	defer ticker.Stop()
+
+	var workers []autoscaledWorker
+	var lastScale time.Time
+	for {
+		select {
+		case <-p.baseCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		latency := p.averageLatency()
+
+		// the workers can be removed manually in the admin panel
+		running := workers[:0]
+		for _, w := range workers {
+			if w.ctx.Err() == nil {
+				running = append(running, w)
+			}
+		}
+		workers = running
+
+		p.lock.Lock()
+		select {
+		case <-p.paused:
+			p.lock.Unlock()
+			continue
+		default:
+		}
+		numberOfWorkers := p.numberOfWorkers
+		maxNumberOfWorkers := p.maxNumberOfWorkers
+		p.lock.Unlock()
+
+		backlog := p.NumberInQueue()
+		delta := p.autoscale.scaleDelta(numberOfWorkers, maxNumberOfWorkers, backlog, latency, time.Since(lastScale))
+		if delta < 0 && -delta > len(workers) {
+			delta = -len(workers)
+		}
+		if delta == 0 {
+			continue
+		}
+
+		log.Debug("WorkerPool: %d scaling from %d workers by %d (backlog: %d, latency: %v)", p.qid, numberOfWorkers, delta, backlog, latency)
+		for ; delta > 0; delta-- {
+			ctx, cancel := p.commonRegisterWorkers(1, 0, false)
+			p.addWorkers(ctx, cancel, 1)
+			workers = append(workers, autoscaledWorker{ctx: ctx, cancel: cancel})
+		}
+		for ; delta < 0; delta++ {
+			w := workers[len(workers)-1]
+			workers = workers[:len(workers)-1]
+			w.cancel()
+		}
+		lastScale = time.Now()
+	}
+}
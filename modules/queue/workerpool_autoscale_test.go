@@ -0,0 +1,69 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoscaleConfigurationScaleDelta(t *testing.T) {
+	config := AutoscaleConfiguration{
+		Autoscale:        true,
+		MinWorkers:       1,
+		ScaleInterval:    time.Second,
+		ScaleUpBacklog:   10,
+		ScaleDownBacklog: 2,
+		ScaleUpLatency:   time.Second,
+		ScaleCooldown:    time.Minute,
+	}
+
+	cases := []struct {
+		name      string
+		workers   int
+		max       int
+		backlog   int64
+		latency   time.Duration
+		sinceLast time.Duration
+		expected  int
+	}{
+		{"below minimum", 0, 10, 0, 0, 0, 1},
+		{"above maximum", 12, 10, 100, 0, time.Hour, -2},
+		{"idle at minimum", 1, 10, 0, 0, time.Hour, 0},
+		{"backlog needs more workers", 2, 10, 55, 0, time.Hour, 4},
+		{"limited by maximum", 2, 5, 500, 0, time.Hour, 3},
+		{"unlimited maximum", 2, -1, 500, 0, time.Hour, 48},
+		{"slow handler", 2, 10, 5, 2 * time.Second, time.Hour, 1},
+		{"slow handler without backlog", 2, 10, 0, 2 * time.Second, time.Hour, 0},
+		{"cooldown", 2, 10, 55, 0, 30 * time.Second, 0},
+		{"between thresholds", 4, 10, 20, 0, time.Hour, 0},
+		{"scale down", 4, 10, 7, 0, time.Hour, -1},
+		{"no scale down with latency", 4, 10, 7, 700 * time.Millisecond, time.Hour, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, config.scaleDelta(c.workers, c.max, c.backlog, c.latency, c.sinceLast))
+		})
+	}
+}
+
+func TestAutoscaleConfigurationNormalize(t *testing.T) {
+	config := AutoscaleConfiguration{
+		MinWorkers:       20,
+		ScaleUpBacklog:   4,
+		ScaleDownBacklog: 8,
+	}
+	config.normalize("test", 10)
+	assert.Equal(t, 10, config.MinWorkers)
+	assert.Equal(t, 4, config.ScaleUpBacklog)
+	assert.Equal(t, 2, config.ScaleDownBacklog)
+
+	config = AutoscaleConfiguration{}
+	config.normalize("test", -1)
+	assert.Equal(t, 1, config.ScaleUpBacklog)
+	assert.Equal(t, 0, config.ScaleDownBacklog)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import "sync"
+
+// ConcurrencyLimiter limits the number of concurrent operations per key
+type ConcurrencyLimiter struct {
+	mutex   sync.Mutex
+	max     int
+	running map[string]int
+}
+
+// NewConcurrencyLimiter creates a limiter which allows max concurrent operations per key, a value <= 0 means unlimited
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		max:     max,
+		running: make(map[string]int),
+	}
+}
+
+// Acquire tries to start an operation for the key and returns false if the limit is reached
+func (l *ConcurrencyLimiter) Acquire(key string) bool {
+	if l.max <= 0 {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.running[key] >= l.max {
+		return false
+	}
+	l.running[key]++
+	return true
+}
+
+// Release ends an operation for the key which was started by Acquire
+func (l *ConcurrencyLimiter) Release(key string) {
+	if l.max <= 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.running[key] <= 1 {
+		delete(l.running, key)
+	} else {
+		l.running[key]--
+	}
+}
+
+// Running returns the number of running operations for the key
+func (l *ConcurrencyLimiter) Running(key string) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.running[key]
+}
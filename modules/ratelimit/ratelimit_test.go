@@ -0,0 +1,93 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	l := NewConcurrencyLimiter(2)
+
+	assert.True(t, l.Acquire("a"))
+	assert.True(t, l.Acquire("a"))
+	assert.False(t, l.Acquire("a"))
+	assert.True(t, l.Acquire("b"))
+	assert.Equal(t, 2, l.Running("a"))
+
+	l.Release("a")
+	assert.Equal(t, 1, l.Running("a"))
+	assert.True(t, l.Acquire("a"))
+
+	l.Release("a")
+	l.Release("a")
+	l.Release("b")
+	assert.Equal(t, 0, l.Running("a"))
+	assert.Equal(t, 0, l.Running("b"))
+
+	unlimited := NewConcurrencyLimiter(0)
+	for i := 0; i < 10; i++ {
+		assert.True(t, unlimited.Acquire("a"))
+	}
+}
+
+func TestThrottledWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.Equal(t, &buf, NewThrottledWriter(context.Background(), &buf, 0))
+
+	w := NewThrottledWriter(context.Background(), &buf, 100)
+	start := time.Now()
+	n, err := w.Write(make([]byte, 150))
+	assert.NoError(t, err)
+	assert.Equal(t, 150, n)
+	assert.Equal(t, 150, buf.Len())
+	assert.GreaterOrEqual(t, time.Since(start), 1400*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = NewThrottledWriter(ctx, &buf, 10)
+	_, err = w.Write(make([]byte, 100))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRequestLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewRequestLimiter(60, 2)
+	l.now = func() time.Time { return now }
+
+	ok, _ := l.Allow("a")
+	assert.True(t, ok)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+	ok, retryAfter := l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	ok, _ = l.Allow("b")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+	ok, _ = l.Allow("a")
+	assert.False(t, ok)
+
+	now = now.Add(cleanupInterval + time.Second)
+	ok, _ = l.Allow("c")
+	assert.True(t, ok)
+	assert.Len(t, l.buckets, 1)
+
+	unlimited := NewRequestLimiter(0, 0)
+	for i := 0; i < 10; i++ {
+		ok, _ := unlimited.Allow("a")
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is the interval in which buckets which are full again get removed
+const cleanupInterval = 5 * time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RequestLimiter limits the number of requests per key with a token bucket per key
+type RequestLimiter struct {
+	mutex       sync.Mutex
+	rate        float64 // tokens per second
+	burst       float64
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// NewRequestLimiter creates a limiter which allows requestsPerMinute requests per key with bursts of up to burst requests.
+// A requestsPerMinute value <= 0 means unlimited, a burst value <= 0 defaults to requestsPerMinute.
+func NewRequestLimiter(requestsPerMinute, burst int) *RequestLimiter {
+	if burst <= 0 {
+		burst = requestsPerMinute
+	}
+	return &RequestLimiter{
+		rate:        float64(requestsPerMinute) / 60,
+		burst:       float64(burst),
+		buckets:     make(map[string]*bucket),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// Allow consumes a request for the key. If the limit is reached it returns false and the duration until the next request is allowed.
+func (l *RequestLimiter) Allow(key string) (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastCleanup) > cleanupInterval {
+		l.cleanup(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	} else {
+		b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// cleanup removes the buckets which are full again, they behave like new buckets
+func (l *RequestLimiter) cleanup(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
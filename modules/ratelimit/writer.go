@@ -0,0 +1,65 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// throttledWriter is a writer which limits the throughput to a number of bytes per second
+type throttledWriter struct {
+	ctx            context.Context
+	w              io.Writer
+	bytesPerSecond int64
+	start          time.Time
+	written        int64
+}
+
+// NewThrottledWriter creates a writer which writes at most bytesPerSecond bytes per second to w.
+// A value <= 0 disables the throttling.
+func NewThrottledWriter(ctx context.Context, w io.Writer, bytesPerSecond int64) io.Writer {
+	if bytesPerSecond <= 0 {
+		return w
+	}
+	return &throttledWriter{
+		ctx:            ctx,
+		w:              w,
+		bytesPerSecond: bytesPerSecond,
+		start:          time.Now(),
+	}
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		chunk := p
+		if int64(len(chunk)) > t.bytesPerSecond {
+			chunk = chunk[:t.bytesPerSecond]
+		}
+
+		n, err := t.w.Write(chunk)
+		total += n
+		t.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+
+		// wait until the written bytes are within the limit
+		expected := time.Duration(float64(t.written) / float64(t.bytesPerSecond) * float64(time.Second))
+		if wait := expected - time.Since(t.start); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-t.ctx.Done():
+				timer.Stop()
+				return total, t.ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	return total, nil
+}
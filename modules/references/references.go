@@ -140,13 +140,19 @@ func makeKeywordsPat(words []string) *regexp.Regexp {
 	return regexp.MustCompile(`(?i)(?:\s|^|\(|\[)(` + strings.Join(acceptedWords, `|`) + `):? $`)
 }
 
+// keywordPattern accepts Unicode letter class runes (a-z, á, à, ä, )
+var keywordPattern = regexp.MustCompile(`^[\pL]+$`)
+
+// IsValidKeyword returns true if the word can be used as closing or reopening keyword
+func IsValidKeyword(word string) bool {
+	return keywordPattern.MatchString(strings.TrimSpace(word))
+}
+
 func parseKeywords(words []string) []string {
 	acceptedWords := make([]string, 0, 5)
-	wordPat := regexp.MustCompile(`^[\pL]+$`)
 	for _, word := range words {
 		word = strings.ToLower(strings.TrimSpace(word))
-		// Accept Unicode letter class runes (a-z, á, à, ä, )
-		if wordPat.MatchString(word) {
+		if keywordPattern.MatchString(word) {
 			acceptedWords = append(acceptedWords, word)
 		} else {
 			log.Info("Invalid keyword: %s", word)
@@ -167,6 +173,20 @@ func doNewKeywords(close, reopen []string) {
 	issueReopenKeywordsPat = makeKeywordsPat(reopen)
 }
 
+// Keywords holds a custom set of keywords closing and reopening the referenced issues,
+// e.g. the localized keywords of a repository
+type Keywords struct {
+	closePat, reopenPat *regexp.Regexp
+}
+
+// NewKeywords returns the keywords matching the given words. Only words consisting of letters are accepted.
+func NewKeywords(close, reopen []string) *Keywords {
+	return &Keywords{
+		closePat:  makeKeywordsPat(close),
+		reopenPat: makeKeywordsPat(reopen),
+	}
+}
+
 // getGiteaHostName returns a normalized string with the local host name, with no scheme or port information
 func getGiteaHostName() string {
 	giteaHostInit.Do(func() {
@@ -246,7 +266,7 @@ func FindAllIssueReferencesMarkdown(content string) []IssueReference {
 
 func findAllIssueReferencesMarkdown(content string) []*rawReference {
 	bcontent, links := mdstripper.StripMarkdownBytes([]byte(content))
-	return findAllIssueReferencesBytes(bcontent, links)
+	return findAllIssueReferencesBytes(bcontent, links, nil)
 }
 
 func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byte) {
@@ -317,6 +337,12 @@ func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byt
 
 // FindAllIssueReferences returns a list of unvalidated references found in a string.
 func FindAllIssueReferences(content string) []IssueReference {
+	return FindAllIssueReferencesWithKeywords(content, nil)
+}
+
+// FindAllIssueReferencesWithKeywords returns a list of unvalidated references found in a string,
+// using the given closing and reopening keywords instead of the configured ones if they are not nil.
+func FindAllIssueReferencesWithKeywords(content string, keywords *Keywords) []IssueReference {
 	// Need to convert fully qualified html references to local system to #/! short codes
 	contentBytes := []byte(content)
 	if re := getGiteaIssuePullPattern(); re != nil {
@@ -324,7 +350,7 @@ func FindAllIssueReferences(content string) []IssueReference {
 	} else {
 		log.Debug("No GiteaIssuePullPattern pattern")
 	}
-	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}))
+	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}, keywords))
 }
 
 // FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string.
@@ -335,7 +361,7 @@ func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *Rende
 			return false, nil
 		}
 	}
-	r := getCrossReference(util.StringToReadOnlyBytes(content), match[2], match[3], false, prOnly)
+	r := getCrossReference(util.StringToReadOnlyBytes(content), match[2], match[3], false, prOnly, nil)
 	if r == nil {
 		return false, nil
 	}
@@ -388,7 +414,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 }
 
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
-func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
+func findAllIssueReferencesBytes(content []byte, links []string, keywords *Keywords) []*rawReference {
 	ret := make([]*rawReference, 0, 10)
 	pos := 0
 
@@ -401,7 +427,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -419,7 +445,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -453,7 +479,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 			}
 			// Note: closing/reopening keywords not supported with URLs
 			bytes := []byte(parts[1] + "/" + parts[2] + sep + parts[4])
-			if ref := getCrossReference(bytes, 0, len(bytes), true, false); ref != nil {
+			if ref := getCrossReference(bytes, 0, len(bytes), true, false, keywords); ref != nil {
 				ref.refLocation = nil
 				ret = append(ret, ref)
 			}
@@ -495,7 +521,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 	return ret
 }
 
-func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *rawReference {
+func getCrossReference(content []byte, start, end int, fromLink, prOnly bool, keywords *Keywords) *rawReference {
 	sep := bytes.IndexAny(content[start:end], "#!")
 	if sep < 0 {
 		return nil
@@ -515,7 +541,7 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 			// Markdown links must specify owner/repo
 			return nil
 		}
-		action, location := findActionKeywords(content, start)
+		action, location := findActionKeywordsWith(keywords, content, start)
 		return &rawReference{
 			index:          index,
 			action:         action,
@@ -533,7 +559,7 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 	if !validNamePattern.MatchString(owner) || !validNamePattern.MatchString(name) {
 		return nil
 	}
-	action, location := findActionKeywords(content, start)
+	action, location := findActionKeywordsWith(keywords, content, start)
 	return &rawReference{
 		index:          index,
 		owner:          owner,
@@ -547,16 +573,24 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 }
 
 func findActionKeywords(content []byte, start int) (XRefAction, *RefSpan) {
-	newKeywords()
+	return findActionKeywordsWith(nil, content, start)
+}
+
+func findActionKeywordsWith(keywords *Keywords, content []byte, start int) (XRefAction, *RefSpan) {
+	if keywords == nil {
+		newKeywords()
+		keywords = &Keywords{closePat: issueCloseKeywordsPat, reopenPat: issueReopenKeywordsPat}
+	}
+	closePat, reopenPat := keywords.closePat, keywords.reopenPat
 	var m []int
-	if issueCloseKeywordsPat != nil {
-		m = issueCloseKeywordsPat.FindSubmatchIndex(content[:start])
+	if closePat != nil {
+		m = closePat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionCloses, &RefSpan{Start: m[2], End: m[3]}
 		}
 	}
-	if issueReopenKeywordsPat != nil {
-		m = issueReopenKeywordsPat.FindSubmatchIndex(content[:start])
+	if reopenPat != nil {
+		m = reopenPat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionReopens, &RefSpan{Start: m[2], End: m[3]}
 		}
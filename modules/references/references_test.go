@@ -460,6 +460,24 @@ func TestCustomizeCloseKeywords(t *testing.T) {
 	doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
 }
 
+func TestFindAllIssueReferencesWithKeywords(t *testing.T) {
+	keywords := NewKeywords([]string{"cierra", "cerró"}, []string{"reabre"})
+
+	refs := FindAllIssueReferencesWithKeywords("Cierra #12, reabre user3/repo4#200 and fixes #13", keywords)
+	if assert.Len(t, refs, 3) {
+		assert.Equal(t, IssueReference{Index: 12, Action: XRefActionCloses}, refs[0])
+		assert.Equal(t, IssueReference{Index: 13, Action: XRefActionNone}, refs[1])
+		assert.Equal(t, IssueReference{Index: 200, Owner: "user3", Name: "repo4", Action: XRefActionReopens}, refs[2])
+	}
+
+	// the configured keywords are used without custom keywords
+	refs = FindAllIssueReferencesWithKeywords("Cierra #12 and fixes #13", nil)
+	if assert.Len(t, refs, 2) {
+		assert.Equal(t, XRefActionNone, refs[0].Action)
+		assert.Equal(t, XRefActionCloses, refs[1].Action)
+	}
+}
+
 func TestParseCloseKeywords(t *testing.T) {
 	// Test parsing of CloseKeywords and ReopenKeywords
 	assert.Len(t, parseKeywords([]string{""}), 0)
@@ -0,0 +1,66 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package refevents
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/nosql"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func init() {
+	Register("redis", newRedisPublisher)
+}
+
+// redisPublisher adds the events to a Redis stream, consumers read them with XREAD or a consumer group
+type redisPublisher struct {
+	client redis.UniversalClient
+	stream string
+}
+
+func newRedisPublisher() (Publisher, error) {
+	p := &redisPublisher{
+		client: nosql.GetManager().GetRedisClient(setting.RefEvents.ConnStr),
+		stream: setting.RefEvents.Stream,
+	}
+	if err := p.client.Ping(graceful.GetManager().ShutdownContext()).Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Publish adds the events in a transaction, so consumers never see a partial push
+func (p *redisPublisher) Publish(ctx context.Context, events []*Event) error {
+	values := make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		values = append(values, map[string]interface{}{
+			"repository": event.Repository,
+			"ref":        event.Ref,
+			"event":      payload,
+		})
+	}
+
+	_, err := p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, v := range values {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: p.stream,
+				MaxLen: setting.RefEvents.MaxLength,
+				Approx: true,
+				Values: v,
+			})
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package refevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Event is the update of a git reference, e.g. by a push.
+// A created reference has an empty OldSHA, a deleted one an empty NewSHA.
+type Event struct {
+	RepoID     int64  `json:"repo_id"`
+	Repository string `json:"repository"`
+	IsWiki     bool   `json:"is_wiki"`
+	Ref        string `json:"ref"`
+	OldSHA     string `json:"old_sha"`
+	NewSHA     string `json:"new_sha"`
+	PusherID   int64  `json:"pusher_id"`
+	PusherName string `json:"pusher_name"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// NewEvent creates the event of a reference update, git.EmptySHA is used by the hooks for missing commits
+func NewEvent(repoID int64, repoFullName string, isWiki bool, ref, oldSHA, newSHA string, pusherID int64, pusherName string) *Event {
+	if oldSHA == git.EmptySHA {
+		oldSHA = ""
+	}
+	if newSHA == git.EmptySHA {
+		newSHA = ""
+	}
+	return &Event{
+		RepoID:     repoID,
+		Repository: repoFullName,
+		IsWiki:     isWiki,
+		Ref:        ref,
+		OldSHA:     oldSHA,
+		NewSHA:     newSHA,
+		PusherID:   pusherID,
+		PusherName: pusherName,
+		Timestamp:  time.Now().Unix(),
+	}
+}
+
+// Publisher sends the events to a message broker
+type Publisher interface {
+	// Publish sends the events of a push, either all or none of them should be published
+	Publish(ctx context.Context, events []*Event) error
+}
+
+// Factory creates the publisher of a broker type
+type Factory func() (Publisher, error)
+
+var (
+	factories  = map[string]Factory{}
+	publisher  Publisher
+	eventQueue queue.Queue
+)
+
+// Register makes a broker type available for the [ref_events] section.
+// It must be called before Init.
+func Register(typ string, factory Factory) {
+	factories[typ] = factory
+}
+
+// Init creates the publisher configured in the settings and the queue the events are published from
+func Init() error {
+	publisher = nil
+	if !setting.RefEvents.Enabled {
+		return nil
+	}
+	p, err := newPublisher()
+	if err != nil {
+		return err
+	}
+
+	eventQueue = queue.CreateQueue("ref_events", handle, []*Event{})
+	if eventQueue == nil {
+		return errors.New("unable to create ref_events Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(eventQueue.Run)
+
+	publisher = p
+	return nil
+}
+
+func newPublisher() (Publisher, error) {
+	factory, ok := factories[setting.RefEvents.Type]
+	if !ok {
+		return nil, fmt.Errorf("ref events have unknown broker type %q", setting.RefEvents.Type)
+	}
+	p, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("ref events broker %s: %w", setting.RefEvents.Type, err)
+	}
+	return p, nil
+}
+
+// handle publishes the queued events of the pushes. Events which could not be published after all retries are dropped,
+// except if Gitea is shutting down, then they are kept in the queue.
+func handle(data ...queue.Data) []queue.Data {
+	ctx := graceful.GetManager().ShutdownContext()
+	var unhandled []queue.Data
+	for _, datum := range data {
+		events, ok := datum.([]*Event)
+		if !ok {
+			log.Error("Unable to process provided datum: %v - not possible to cast to []*Event", datum)
+			continue
+		}
+		if err := Publish(ctx, events); err != nil {
+			if ctx.Err() != nil {
+				unhandled = append(unhandled, datum)
+				continue
+			}
+			log.Error("Unable to publish %d ref events of %s: %v", len(events), events[0].Repository, err)
+		}
+	}
+	return unhandled
+}
+
+// IsEnabled returns true if the ref events are published
+func IsEnabled() bool {
+	return publisher != nil
+}
+
+// ShouldPublish returns true if the updates of the reference are published.
+// Only branches and tags are published unless all references are configured.
+func ShouldPublish(refFullName string) bool {
+	return setting.RefEvents.AllRefs || strings.HasPrefix(refFullName, git.BranchPrefix) || strings.HasPrefix(refFullName, git.TagPrefix)
+}
+
+// Enqueue adds the events of a push to the queue they are published from, so the push isn't delayed by the broker
+func Enqueue(events []*Event) error {
+	if publisher == nil || len(events) == 0 {
+		return nil
+	}
+	return eventQueue.Push(events)
+}
+
+// Publish sends the events to the configured broker, failed attempts are retried
+func Publish(ctx context.Context, events []*Event) error {
+	if publisher == nil || len(events) == 0 {
+		return nil
+	}
+
+	var err error
+	for attempt := 0; attempt <= setting.RefEvents.Retries; attempt++ {
+		if attempt > 0 {
+			log.Warn("Publishing %d ref events failed, retrying: %v", len(events), err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+			}
+		}
+		if err = publish(ctx, events); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func publish(ctx context.Context, events []*Event) error {
+	ctx, cancel := context.WithTimeout(ctx, setting.RefEvents.Timeout)
+	defer cancel()
+	return publisher.Publish(ctx, events)
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package refevents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPublisher struct {
+	failures int
+	events   []*Event
+}
+
+func (p *testPublisher) Publish(ctx context.Context, events []*Event) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	defer func(enabled bool, typ string, retries int) {
+		setting.RefEvents.Enabled = enabled
+		setting.RefEvents.Type = typ
+		setting.RefEvents.Retries = retries
+		publisher = nil
+	}(setting.RefEvents.Enabled, setting.RefEvents.Type, setting.RefEvents.Retries)
+
+	p := &testPublisher{}
+	Register("test", func() (Publisher, error) {
+		return p, nil
+	})
+
+	setting.RefEvents.Enabled = true
+	setting.RefEvents.Type = "unknown"
+	assert.Error(t, Init())
+	assert.False(t, IsEnabled())
+
+	setting.RefEvents.Type = "test"
+	setting.RefEvents.Retries = 1
+	var err error
+	publisher, err = newPublisher()
+	assert.NoError(t, err)
+	assert.True(t, IsEnabled())
+
+	events := []*Event{
+		NewEvent(1, "user2/repo1", false, "refs/heads/master", git.EmptySHA, "65f1bf27bc3bf70f64657658635e66094edbcb4d", 2, "user2"),
+	}
+	assert.Empty(t, events[0].OldSHA)
+
+	p.failures = 1
+	assert.NoError(t, Publish(context.Background(), events))
+	assert.Len(t, p.events, 1)
+
+	p.failures = 2
+	assert.Error(t, Publish(context.Background(), events))
+	assert.Len(t, p.events, 1)
+
+	// the queue handler drops the events after the retries
+	p.failures = 2
+	assert.Empty(t, handle(events))
+	assert.Len(t, p.events, 1)
+
+	assert.Empty(t, handle(events))
+	assert.Len(t, p.events, 2)
+}
+
+func TestShouldPublish(t *testing.T) {
+	defer func(allRefs bool) {
+		setting.RefEvents.AllRefs = allRefs
+	}(setting.RefEvents.AllRefs)
+
+	setting.RefEvents.AllRefs = false
+	assert.True(t, ShouldPublish("refs/heads/master"))
+	assert.True(t, ShouldPublish("refs/tags/v1.0"))
+	assert.False(t, ShouldPublish("refs/pull/1/head"))
+
+	setting.RefEvents.AllRefs = true
+	assert.True(t, ShouldPublish("refs/pull/1/head"))
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ReleaseChangelogMaxCommits is the maximum number of commits listed in a release changelog
+const ReleaseChangelogMaxCommits = 100
+
+// GenerateReleaseChangelog lists the commits between the previous release and the given release
+func GenerateReleaseChangelog(ctx context.Context, gitRepo *git.Repository, rel *repo_model.Release) (*api.ReleaseChangelog, error) {
+	if err := rel.LoadAttributes(); err != nil {
+		return nil, err
+	}
+
+	commit, err := gitRepo.GetTagCommit(rel.TagName)
+	if err != nil {
+		return nil, fmt.Errorf("GetTagCommit[%s]: %w", rel.TagName, err)
+	}
+
+	changelog := &api.ReleaseChangelog{}
+
+	var before *git.Commit
+	prev, err := repo_model.GetPreviousRelease(ctx, rel)
+	if err != nil && !repo_model.IsErrReleaseNotExist(err) {
+		return nil, err
+	}
+	if prev != nil {
+		// the tag of the previous release may have been deleted
+		if before, err = gitRepo.GetTagCommit(prev.TagName); err == nil {
+			changelog.PreviousTag = prev.TagName
+			changelog.CompareURL = setting.AppURL + rel.Repo.ComposeCompareURL(prev.TagName, rel.TagName)
+		} else if !git.IsErrNotExist(err) {
+			return nil, err
+		}
+	}
+
+	commits, err := gitRepo.CommitsBetweenLimit(commit, before, ReleaseChangelogMaxCommits+1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) > ReleaseChangelogMaxCommits {
+		commits = commits[:ReleaseChangelogMaxCommits]
+		changelog.Truncated = true
+	}
+
+	var body strings.Builder
+	changelog.Commits = make([]*api.ReleaseChangelogCommit, 0, len(commits))
+	for _, c := range commits {
+		cc := &api.ReleaseChangelogCommit{
+			ID:      c.ID.String(),
+			Message: c.Summary(),
+			URL:     rel.Repo.HTMLURL() + "/commit/" + c.ID.String(),
+		}
+		if c.Author != nil {
+			cc.Author = c.Author.Name
+		}
+		changelog.Commits = append(changelog.Commits, cc)
+
+		fmt.Fprintf(&body, "* %s (%s)\n", cc.Message, base.ShortSha(cc.ID))
+	}
+	changelog.Body = body.String()
+
+	return changelog, nil
+}
@@ -34,12 +34,19 @@ type PushCommits struct {
 	Commits    []*PushCommit
 	HeadCommit *PushCommit
 	CompareURL string
+	// CommitsURL lists the commits of a large push page by page, comparing them at once would be too expensive
+	CommitsURL string
 	Len        int
 
 	avatars    map[string]string
 	emailUsers map[string]*user_model.User
 }
 
+// IsLargePush returns true if the push has more commits than the large push threshold, e.g. a history import
+func (pc *PushCommits) IsLargePush() bool {
+	return setting.Repository.LargePushThreshold > 0 && pc.Len > setting.Repository.LargePushThreshold
+}
+
 // NewPushCommits creates a new PushCommits object.
 func NewPushCommits() *PushCommits {
 	return &PushCommits{
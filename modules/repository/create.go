@@ -145,7 +145,7 @@ func CreateRepositoryByExample(ctx context.Context, doer, u *user_model.User, re
 		}
 	}
 
-	if err = webhook.CopyDefaultWebhooksToRepo(ctx, repo.ID); err != nil {
+	if err = webhook.CopyDefaultWebhooksToRepo(ctx, repo.OwnerID, repo.ID); err != nil {
 		return fmt.Errorf("CopyDefaultWebhooksToRepo: %v", err)
 	}
 
@@ -170,6 +170,14 @@ type CreateRepoOptions struct {
 	Status         repo_model.RepositoryStatus
 	TrustModel     repo_model.TrustModelType
 	MirrorInterval string
+	// content overrides of the init files and additional files of the initial commit
+	ReadmeContent    string
+	GitignoreContent string
+	LicenseContent   string
+	InitialFiles     map[string][]byte
+	// Topics and Properties are saved by the repository service after the creation
+	Topics     []string
+	Properties map[string]string
 }
 
 // CreateRepository creates a repository for the user/organization.
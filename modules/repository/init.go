@@ -236,31 +236,41 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 	}
 
 	// README
-	data, err := GetRepoInitFile("readme", opts.Readme)
-	if err != nil {
-		return fmt.Errorf("GetRepoInitFile[%s]: %v", opts.Readme, err)
-	}
+	res := opts.ReadmeContent
+	if len(res) == 0 {
+		data, err := GetRepoInitFile("readme", opts.Readme)
+		if err != nil {
+			return fmt.Errorf("GetRepoInitFile[%s]: %v", opts.Readme, err)
+		}
 
-	cloneLink := repo.CloneLink()
-	match := map[string]string{
-		"Name":           repo.Name,
-		"Description":    repo.Description,
-		"CloneURL.SSH":   cloneLink.SSH,
-		"CloneURL.HTTPS": cloneLink.HTTPS,
-		"OwnerName":      repo.OwnerName,
-	}
-	res, err := vars.Expand(string(data), match)
-	if err != nil {
-		// here we could just log the error and continue the rendering
-		log.Error("unable to expand template vars for repo README: %s, err: %v", opts.Readme, err)
+		cloneLink := repo.CloneLink()
+		match := map[string]string{
+			"Name":           repo.Name,
+			"Description":    repo.Description,
+			"CloneURL.SSH":   cloneLink.SSH,
+			"CloneURL.HTTPS": cloneLink.HTTPS,
+			"OwnerName":      repo.OwnerName,
+		}
+		res, err = vars.Expand(string(data), match)
+		if err != nil {
+			// here we could just log the error and continue the rendering
+			log.Error("unable to expand template vars for repo README: %s, err: %v", opts.Readme, err)
+		}
 	}
-	if err = os.WriteFile(filepath.Join(tmpDir, "README.md"),
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"),
 		[]byte(res), 0o644); err != nil {
 		return fmt.Errorf("write README.md: %v", err)
 	}
 
+	var data []byte
+	var err error
+
 	// .gitignore
-	if len(opts.Gitignores) > 0 {
+	if len(opts.GitignoreContent) > 0 {
+		if err = os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(opts.GitignoreContent), 0o644); err != nil {
+			return fmt.Errorf("write .gitignore: %v", err)
+		}
+	} else if len(opts.Gitignores) > 0 {
 		var buf bytes.Buffer
 		names := strings.Split(opts.Gitignores, ",")
 		for _, name := range names {
@@ -281,7 +291,11 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 	}
 
 	// LICENSE
-	if len(opts.License) > 0 {
+	if len(opts.LicenseContent) > 0 {
+		if err = os.WriteFile(filepath.Join(tmpDir, "LICENSE"), []byte(opts.LicenseContent), 0o644); err != nil {
+			return fmt.Errorf("write LICENSE: %v", err)
+		}
+	} else if len(opts.License) > 0 {
 		data, err = GetRepoInitFile("license", opts.License)
 		if err != nil {
 			return fmt.Errorf("GetRepoInitFile[%s]: %v", opts.License, err)
@@ -292,6 +306,17 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 		}
 	}
 
+	// Additional files, the paths are expected to be cleaned by the caller
+	for treePath, content := range opts.InitialFiles {
+		filePath := filepath.Join(tmpDir, filepath.FromSlash(treePath))
+		if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			return fmt.Errorf("create directory for %s: %v", treePath, err)
+		}
+		if err = os.WriteFile(filePath, content, 0o644); err != nil {
+			return fmt.Errorf("write %s: %v", treePath, err)
+		}
+	}
+
 	return nil
 }
 
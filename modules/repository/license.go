@@ -0,0 +1,109 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// detectableLicenses are the license templates the content of license files is compared with
+var detectableLicenses = []string{
+	"0BSD", "AGPL-3.0-only", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "EPL-2.0", "GPL-2.0-only",
+	"GPL-3.0-only", "ISC", "LGPL-2.1-only", "LGPL-3.0-only", "MIT", "MPL-2.0", "Unlicense",
+}
+
+// licenseMinSimilarity is the share of words a license file must have in common with a template
+const licenseMinSimilarity = 0.9
+
+var (
+	licenseWordPattern    = regexp.MustCompile(`[a-z0-9]+`)
+	spdxIdentifierPattern = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([A-Za-z0-9.+\-() ]+)`)
+
+	licenseTemplatesOnce sync.Once
+	licenseTemplates     map[string]map[string]int
+)
+
+// licenseFileNames are the names of the files in the repository root which contain the license, in order of preference
+var licenseFileNames = []string{"license", "license.md", "license.txt", "licence", "licence.md", "licence.txt", "copying", "copying.md", "copying.txt"}
+
+// IsLicenseFile returns the preference of a file of the repository root as license file, -1 if it is none
+func IsLicenseFile(name string) int {
+	name = strings.ToLower(name)
+	for i, n := range licenseFileNames {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func licenseWords(content []byte) map[string]int {
+	words := make(map[string]int)
+	for _, w := range licenseWordPattern.FindAll(bytes.ToLower(content), -1) {
+		words[string(w)]++
+	}
+	return words
+}
+
+func loadLicenseTemplates() {
+	licenseTemplates = make(map[string]map[string]int, len(detectableLicenses))
+	for _, name := range detectableLicenses {
+		data, err := GetRepoInitFile("license", name)
+		if err != nil {
+			log.Error("Unable to load license template %s: %v", name, err)
+			continue
+		}
+		licenseTemplates[name] = licenseWords(data)
+	}
+}
+
+// DetectLicense returns the SPDX identifier of the license in the content of a license file or an empty string if it is unknown.
+// An explicit SPDX-License-Identifier is preferred, otherwise the words of the content are compared with common license templates.
+func DetectLicense(content []byte) string {
+	if m := spdxIdentifierPattern.FindSubmatch(content); m != nil {
+		return strings.TrimSpace(string(m[1]))
+	}
+
+	licenseTemplatesOnce.Do(loadLicenseTemplates)
+
+	words := licenseWords(content)
+	total := 0
+	for _, c := range words {
+		total += c
+	}
+
+	best, bestSimilarity := "", 0.0
+	for name, template := range licenseTemplates {
+		templateTotal, common := 0, 0
+		for w, c := range template {
+			templateTotal += c
+			if c2 := words[w]; c2 < c {
+				common += c2
+			} else {
+				common += c
+			}
+		}
+		max := templateTotal
+		if total > max {
+			max = total
+		}
+		if max == 0 {
+			continue
+		}
+		if similarity := float64(common) / float64(max); similarity > bestSimilarity {
+			best, bestSimilarity = name, similarity
+		}
+	}
+	if bestSimilarity < licenseMinSimilarity {
+		return ""
+	}
+	// the texts of the -only and -or-later variants are identical
+	return strings.TrimSuffix(best, "-only")
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLicense(t *testing.T) {
+	for _, name := range []string{"MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "GPL-3.0-only"} {
+		data, err := GetRepoInitFile("license", name)
+		assert.NoError(t, err)
+		content := strings.NewReplacer("<year>", "2022", "<copyright holders>", "The Gitea Authors").Replace(string(data))
+		assert.Equal(t, strings.TrimSuffix(name, "-only"), DetectLicense([]byte(content)), name)
+	}
+
+	assert.Equal(t, "MIT OR Apache-2.0", DetectLicense([]byte("// SPDX-License-Identifier: MIT OR Apache-2.0")))
+	assert.Equal(t, "", DetectLicense([]byte("All rights reserved.")))
+
+	assert.Equal(t, 0, IsLicenseFile("LICENSE"))
+	assert.Equal(t, 6, IsLicenseFile("COPYING"))
+	assert.Equal(t, -1, IsLicenseFile("README.md"))
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ImmutableRawLink returns the link to the content of a blob which does not change as long as the blob exists.
+// Links of private repositories are signed if signed links are enabled.
+func ImmutableRawLink(repo *repo_model.Repository, blobSHA, treePath string) string {
+	link := fmt.Sprintf("%s/raw/blob/%s/%s", repo.HTMLURL(), blobSHA, util.PathEscapeSegments(treePath))
+	if !repo.IsPrivate || setting.Repository.Raw.SignedURLExpiry <= 0 {
+		return link
+	}
+
+	expires := time.Now().Add(setting.Repository.Raw.SignedURLExpiry).Unix()
+
+	params := url.Values{}
+	params.Set("expires", strconv.FormatInt(expires, 10))
+	params.Set("signature", rawBlobSignature(repo.ID, blobSHA, expires))
+	return link + "?" + params.Encode()
+}
+
+// VerifyRawBlobSignature checks if the signature of an immutable raw link is valid and not expired
+func VerifyRawBlobSignature(repoID int64, blobSHA, expires, signature string) bool {
+	if setting.Repository.Raw.SignedURLExpiry <= 0 {
+		return false
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(rawBlobSignature(repoID, blobSHA, expiresUnix)))
+}
+
+func rawBlobSignature(repoID int64, blobSHA string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "raw-blob:%d:%s:%d", repoID, blobSHA, expires) // hmac does not return errors
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImmutableRawLink(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	defer func(expiry time.Duration) {
+		setting.Repository.Raw.SignedURLExpiry = expiry
+	}(setting.Repository.Raw.SignedURLExpiry)
+
+	const sha = "4b4851ad51df6a7d9f25c979345979eaeb5b349f"
+
+	public := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	private := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+
+	setting.Repository.Raw.SignedURLExpiry = 0
+	assert.Equal(t, public.HTMLURL()+"/raw/blob/"+sha+"/docs/read%20me.md", ImmutableRawLink(public, sha, "docs/read me.md"))
+	assert.Equal(t, private.HTMLURL()+"/raw/blob/"+sha+"/README.md", ImmutableRawLink(private, sha, "README.md"))
+
+	setting.Repository.Raw.SignedURLExpiry = time.Hour
+	assert.Equal(t, public.HTMLURL()+"/raw/blob/"+sha+"/README.md", ImmutableRawLink(public, sha, "README.md"))
+
+	link := ImmutableRawLink(private, sha, "README.md")
+	assert.True(t, strings.HasPrefix(link, private.HTMLURL()+"/raw/blob/"+sha+"/README.md?"))
+
+	u, err := url.Parse(link)
+	assert.NoError(t, err)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	assert.True(t, VerifyRawBlobSignature(private.ID, sha, expires, signature))
+	assert.False(t, VerifyRawBlobSignature(public.ID, sha, expires, signature))
+	assert.False(t, VerifyRawBlobSignature(private.ID, "6395b68e1feebb1e4c657b4f9f6ba2676a283c0b", expires, signature))
+	assert.False(t, VerifyRawBlobSignature(private.ID, sha, expires+"0", signature))
+
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	assert.False(t, VerifyRawBlobSignature(private.ID, sha, expired, rawBlobSignature(private.ID, sha, time.Now().Add(-time.Minute).Unix())))
+
+	setting.Repository.Raw.SignedURLExpiry = 0
+	assert.False(t, VerifyRawBlobSignature(private.ID, sha, expires, signature))
+}
@@ -4,6 +4,8 @@
 
 package setting
 
+import "time"
+
 // Attachment settings
 var Attachment = struct {
 	Storage
@@ -11,6 +13,9 @@ var Attachment = struct {
 	MaxSize      int64
 	MaxFiles     int
 	Enabled      bool
+
+	FetchAllowedHostList string
+	FetchTimeout         time.Duration
 }{
 	Storage: Storage{
 		ServeDirect: false,
@@ -19,6 +24,9 @@ var Attachment = struct {
 	MaxSize:      4,
 	MaxFiles:     5,
 	Enabled:      true,
+
+	FetchAllowedHostList: "",
+	FetchTimeout:         time.Minute,
 }
 
 func newAttachmentService() {
@@ -31,4 +39,6 @@ func newAttachmentService() {
 	Attachment.MaxSize = sec.Key("MAX_SIZE").MustInt64(4)
 	Attachment.MaxFiles = sec.Key("MAX_FILES").MustInt(5)
 	Attachment.Enabled = sec.Key("ENABLED").MustBool(true)
+	Attachment.FetchAllowedHostList = sec.Key("FETCH_ALLOWED_HOST_LIST").MustString("")
+	Attachment.FetchTimeout = sec.Key("FETCH_TIMEOUT").MustDuration(time.Minute)
 }
@@ -28,6 +28,7 @@ var CacheService = struct {
 		Enabled      bool
 		TTL          time.Duration `ini:"ITEM_TTL"`
 		CommitsCount int64
+		Store        string
 	} `ini:"cache.last_commit"`
 }{
 	Cache: Cache{
@@ -40,13 +41,21 @@ var CacheService = struct {
 		Enabled      bool
 		TTL          time.Duration `ini:"ITEM_TTL"`
 		CommitsCount int64
+		Store        string
 	}{
 		Enabled:      true,
 		TTL:          8760 * time.Hour,
 		CommitsCount: 1000,
+		Store:        LastCommitStoreCache,
 	},
 }
 
+// enumerates all the stores of the last commit cache
+const (
+	LastCommitStoreCache    = "cache"
+	LastCommitStoreDatabase = "database"
+)
+
 // MemcacheMaxTTL represents the maximum memcache TTL
 const MemcacheMaxTTL = 30 * 24 * time.Hour
 
@@ -81,8 +90,14 @@ func newCacheService() {
 	}
 
 	sec = Cfg.Section("cache.last_commit")
-	if !CacheService.Enabled {
-		CacheService.LastCommit.Enabled = false
+	switch CacheService.LastCommit.Store {
+	case LastCommitStoreCache:
+		if !CacheService.Enabled {
+			CacheService.LastCommit.Enabled = false
+		}
+	case LastCommitStoreDatabase:
+	default:
+		log.Fatal("Unknown last commit cache store: %s", CacheService.LastCommit.Store)
 	}
 
 	CacheService.LastCommit.CommitsCount = sec.Key("COMMITS_COUNT").MustInt64(1000)
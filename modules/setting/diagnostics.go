@@ -0,0 +1,34 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+)
+
+// Diagnostics settings of the requests and slow database queries kept in memory for the admin API
+var Diagnostics = struct {
+	EnableRequestLog   bool
+	MaxRequests        int
+	SlowQueryThreshold time.Duration
+	MaxSlowQueries     int
+}{
+	MaxRequests:    1000,
+	MaxSlowQueries: 500,
+}
+
+func newDiagnostics() {
+	sec := Cfg.Section("diagnostics")
+	Diagnostics.EnableRequestLog = sec.Key("ENABLE_REQUEST_LOG").MustBool(false)
+	Diagnostics.MaxRequests = sec.Key("MAX_REQUESTS").MustInt(Diagnostics.MaxRequests)
+	Diagnostics.SlowQueryThreshold = sec.Key("SLOW_QUERY_THRESHOLD").MustDuration(0)
+	Diagnostics.MaxSlowQueries = sec.Key("MAX_SLOW_QUERIES").MustInt(Diagnostics.MaxSlowQueries)
+	if Diagnostics.MaxRequests <= 0 {
+		Diagnostics.MaxRequests = 1000
+	}
+	if Diagnostics.MaxSlowQueries <= 0 {
+		Diagnostics.MaxSlowQueries = 500
+	}
+}
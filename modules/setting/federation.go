@@ -5,6 +5,8 @@
 package setting
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/modules/log"
 
 	"github.com/go-fed/httpsig"
@@ -13,13 +15,17 @@ import (
 // Federation settings
 var (
 	Federation = struct {
-		Enabled             bool
-		ShareUserStatistics bool
-		MaxSize             int64
-		Algorithms          []string
-		DigestAlgorithm     string
-		GetHeaders          []string
-		PostHeaders         []string
+		Enabled               bool
+		ShareUserStatistics   bool
+		MaxSize               int64
+		Algorithms            []string
+		DigestAlgorithm       string
+		GetHeaders            []string
+		PostHeaders           []string
+		RequireAllowlist      bool
+		DisabledActivityTypes []string
+		InboxRateLimit        int
+		InboxRateLimitBurst   int
 	}{
 		Enabled:             false,
 		ShareUserStatistics: true,
@@ -28,6 +34,9 @@ var (
 		DigestAlgorithm:     "SHA-256",
 		GetHeaders:          []string{"(request-target)", "Date"},
 		PostHeaders:         []string{"(request-target)", "Date", "Digest"},
+		RequireAllowlist:    false,
+		InboxRateLimit:      0,
+		InboxRateLimitBurst: 0,
 	}
 )
 
@@ -50,3 +59,13 @@ func newFederationService() {
 		HttpsigAlgs[i] = httpsig.Algorithm(alg)
 	}
 }
+
+// IsFederationActivityTypeAllowed checks if the inbox accepts activities of the type
+func IsFederationActivityTypeAllowed(activityType string) bool {
+	for _, t := range Federation.DisabledActivityTypes {
+		if strings.EqualFold(t, activityType) {
+			return false
+		}
+	}
+	return true
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+
+	"github.com/dustin/go-humanize"
 )
 
 // Git settings
@@ -37,6 +39,11 @@ var Git = struct {
 		Pull    int
 		GC      int `ini:"GC"`
 	} `ini:"git.timeout"`
+	HTTP struct {
+		MaxConcurrentUploadPackPerRepo int
+		MaxConcurrentUploadPackPerUser int
+		AnonymousBandwidthLimit        int64 `ini:"-"`
+	} `ini:"git.http"`
 }{
 	DisableDiffHighlight:      false,
 	MaxGitDiffLines:           1000,
@@ -81,4 +88,12 @@ func newGit() {
 	} else {
 		Git.HomePath = filepath.Clean(Git.HomePath)
 	}
+
+	if limit := Cfg.Section("git.http").Key("ANONYMOUS_BANDWIDTH_LIMIT").MustString(""); limit != "" {
+		bytesPerSecond, err := humanize.ParseBytes(limit)
+		if err != nil {
+			log.Fatal("Failed to parse git.http ANONYMOUS_BANDWIDTH_LIMIT %q: %v", limit, err)
+		}
+		Git.HTTP.AnonymousBandwidthLimit = int64(bytesPerSecond)
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -19,8 +20,19 @@ var (
 		Enabled           bool
 		ChunkedUploadPath string
 		RegistryHost      string
+
+		RateLimitAnonymous     int
+		RateLimitAuthenticated int
+		RateLimitBurst         int
+
+		MirrorAllowedHostList string
+		MirrorMinInterval     time.Duration
+
+		ContainerDomainAllowedHostList string
+		ContainerDomainCertPath        string
 	}{
-		Enabled: true,
+		Enabled:           true,
+		MirrorMinInterval: time.Hour,
 	}
 )
 
@@ -40,6 +52,10 @@ func newPackages() {
 		Packages.ChunkedUploadPath = filepath.ToSlash(filepath.Join(AppDataPath, Packages.ChunkedUploadPath))
 	}
 
+	if Packages.ContainerDomainCertPath != "" && !filepath.IsAbs(Packages.ContainerDomainCertPath) {
+		Packages.ContainerDomainCertPath = filepath.Join(CustomPath, Packages.ContainerDomainCertPath)
+	}
+
 	if err := os.MkdirAll(Packages.ChunkedUploadPath, os.ModePerm); err != nil {
 		log.Error("Unable to create chunked upload directory: %s (%v)", Packages.ChunkedUploadPath, err)
 	}
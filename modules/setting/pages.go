@@ -0,0 +1,32 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Pages settings
+var (
+	Pages = struct {
+		Enabled bool
+		Domain  string
+		MaxSize int64
+	}{
+		Enabled: false,
+		Domain:  "",
+		MaxSize: 100,
+	}
+)
+
+func newPages() {
+	if err := Cfg.Section("pages").MapTo(&Pages); err != nil {
+		log.Fatal("Failed to map Pages settings: %v", err)
+	}
+
+	Pages.Domain = strings.ToLower(strings.Trim(Pages.Domain, "."))
+}
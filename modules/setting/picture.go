@@ -6,6 +6,7 @@ package setting
 
 import (
 	"net/url"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 
@@ -22,11 +23,17 @@ var (
 		MaxHeight          int
 		MaxFileSize        int64
 		RenderedSizeFactor int
+		EnableProxy        bool
+		ProxyCacheTTL      time.Duration
+		ProxyAllowedHosts  string
+		DefaultAvatar      string
 	}{
 		MaxWidth:           4096,
 		MaxHeight:          3072,
 		MaxFileSize:        1048576,
 		RenderedSizeFactor: 3,
+		ProxyCacheTTL:      24 * time.Hour,
+		DefaultAvatar:      DefaultAvatarExternal,
 	}
 
 	GravatarSource        string
@@ -43,6 +50,13 @@ var (
 	}{}
 )
 
+// The avatars of users who didn't upload one
+const (
+	DefaultAvatarExternal  = "external"  // Gravatar or the federated avatar of the email
+	DefaultAvatarGenerated = "generated" // an avatar generated from the email
+	DefaultAvatarImage     = "image"     // the default avatar image
+)
+
 func newPictureService() {
 	sec := Cfg.Section("picture")
 
@@ -87,6 +101,20 @@ func newPictureService() {
 		}
 	}
 
+	Avatar.EnableProxy = sec.Key("ENABLE_AVATAR_PROXY").MustBool(false) && !DisableGravatar
+	Avatar.ProxyCacheTTL = sec.Key("AVATAR_PROXY_CACHE_TTL").MustDuration(24 * time.Hour)
+	Avatar.ProxyAllowedHosts = sec.Key("AVATAR_PROXY_ALLOWED_HOST_LIST").MustString("")
+
+	switch Avatar.DefaultAvatar = sec.Key("DEFAULT_AVATAR").MustString(DefaultAvatarExternal); Avatar.DefaultAvatar {
+	case DefaultAvatarExternal:
+		if DisableGravatar {
+			Avatar.DefaultAvatar = DefaultAvatarGenerated
+		}
+	case DefaultAvatarGenerated, DefaultAvatarImage:
+	default:
+		log.Fatal("Invalid [picture] DEFAULT_AVATAR: %s", Avatar.DefaultAvatar)
+	}
+
 	if EnableFederatedAvatar {
 		LibravatarService = libravatar.New()
 		if GravatarSourceURL.Scheme == "https" {
@@ -0,0 +1,96 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gobwas/glob"
+	"gopkg.in/ini.v1"
+)
+
+// PushValidators represents the validators run on the files of pushes to repositories
+var PushValidators []*PushValidator
+
+// PushValidator defines a validator configured in a [push_validation.*] section
+type PushValidator struct {
+	Name         string
+	Type         string
+	Repositories []glob.Glob
+	Files        []glob.Glob
+	Schema       string
+	MaxSize      int64
+	Message      string
+}
+
+func newPushValidation() {
+	PushValidators = make([]*PushValidator, 0, 5)
+
+	for _, sec := range Cfg.Section("push_validation").ChildSections() {
+		name := strings.TrimPrefix(sec.Name(), "push_validation.")
+		if name == "" {
+			log.Warn("name is empty, push validator " + sec.Name() + " ignored")
+			continue
+		}
+		newPushValidator(name, sec)
+	}
+}
+
+func newPushValidator(name string, sec *ini.Section) {
+	if !sec.Key("ENABLED").MustBool(true) {
+		return
+	}
+
+	validator := &PushValidator{
+		Name:         name,
+		Type:         sec.Key("TYPE").MustString(""),
+		Repositories: pushValidationGlobFromString(sec.Key("REPOSITORIES").MustString("")),
+		Files:        pushValidationGlobFromString(sec.Key("FILES").MustString("")),
+		Message:      sec.Key("MESSAGE").MustString(""),
+	}
+	if validator.Type == "" {
+		log.Warn(sec.Name() + " TYPE is empty, push validator " + name + " ignored")
+		return
+	}
+
+	if schema := sec.Key("SCHEMA").MustString(""); schema != "" {
+		if !filepath.IsAbs(schema) {
+			schema = filepath.Join(CustomPath, schema)
+		}
+		validator.Schema = schema
+	}
+
+	if maxSize := sec.Key("MAX_SIZE").MustString(""); maxSize != "" {
+		size, err := humanize.ParseBytes(maxSize)
+		if err != nil {
+			log.Fatal("Failed to parse %s MAX_SIZE %q: %v", sec.Name(), maxSize, err)
+		}
+		validator.MaxSize = int64(size)
+	}
+
+	PushValidators = append(PushValidators, validator)
+}
+
+// pushValidationGlobFromString parses a comma separated list of patterns.
+// Patterns are matched case-insensitive and "*" does not cross directory boundaries, use "**" instead.
+func pushValidationGlobFromString(globstr string) []glob.Glob {
+	globs := make([]glob.Glob, 0, 5)
+	for _, expr := range strings.Split(strings.ToLower(globstr), ",") {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+		g, err := glob.Compile(expr, '/')
+		if err != nil {
+			log.Fatal("Invalid push validation glob expression %q: %v", expr, err)
+		}
+		globs = append(globs, g)
+	}
+	return globs
+}
@@ -32,6 +32,13 @@ type QueueSettings struct {
 	BlockTimeout     time.Duration
 	BoostTimeout     time.Duration
 	BoostWorkers     int
+	Autoscale        bool
+	MinWorkers       int
+	ScaleInterval    time.Duration
+	ScaleUpBacklog   int
+	ScaleDownBacklog int
+	ScaleUpLatency   time.Duration
+	ScaleCooldown    time.Duration
 }
 
 // Queue settings
@@ -78,6 +85,13 @@ func GetQueueSettings(name string) QueueSettings {
 	q.BlockTimeout = sec.Key("BLOCK_TIMEOUT").MustDuration(Queue.BlockTimeout)
 	q.BoostTimeout = sec.Key("BOOST_TIMEOUT").MustDuration(Queue.BoostTimeout)
 	q.BoostWorkers = sec.Key("BOOST_WORKERS").MustInt(Queue.BoostWorkers)
+	q.Autoscale = sec.Key("AUTOSCALE").MustBool(Queue.Autoscale)
+	q.MinWorkers = sec.Key("MIN_WORKERS").MustInt(Queue.MinWorkers)
+	q.ScaleInterval = sec.Key("SCALE_INTERVAL").MustDuration(Queue.ScaleInterval)
+	q.ScaleUpBacklog = sec.Key("SCALE_UP_BACKLOG").MustInt(Queue.ScaleUpBacklog)
+	q.ScaleDownBacklog = sec.Key("SCALE_DOWN_BACKLOG").MustInt(Queue.ScaleDownBacklog)
+	q.ScaleUpLatency = sec.Key("SCALE_UP_LATENCY").MustDuration(Queue.ScaleUpLatency)
+	q.ScaleCooldown = sec.Key("SCALE_COOLDOWN").MustDuration(Queue.ScaleCooldown)
 
 	return q
 }
@@ -103,6 +117,13 @@ func NewQueueService() {
 	Queue.BlockTimeout = sec.Key("BLOCK_TIMEOUT").MustDuration(1 * time.Second)
 	Queue.BoostTimeout = sec.Key("BOOST_TIMEOUT").MustDuration(5 * time.Minute)
 	Queue.BoostWorkers = sec.Key("BOOST_WORKERS").MustInt(1)
+	Queue.Autoscale = sec.Key("AUTOSCALE").MustBool(false)
+	Queue.MinWorkers = sec.Key("MIN_WORKERS").MustInt(0)
+	Queue.ScaleInterval = sec.Key("SCALE_INTERVAL").MustDuration(10 * time.Second)
+	Queue.ScaleUpBacklog = sec.Key("SCALE_UP_BACKLOG").MustInt(10)
+	Queue.ScaleDownBacklog = sec.Key("SCALE_DOWN_BACKLOG").MustInt(2)
+	Queue.ScaleUpLatency = sec.Key("SCALE_UP_LATENCY").MustDuration(5 * time.Second)
+	Queue.ScaleCooldown = sec.Key("SCALE_COOLDOWN").MustDuration(1 * time.Minute)
 	Queue.QueueName = sec.Key("QUEUE_NAME").MustString("_queue")
 	Queue.SetName = sec.Key("SET_NAME").MustString("")
 
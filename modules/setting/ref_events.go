@@ -0,0 +1,39 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+)
+
+// RefEvents settings of the events published to a message broker when git references are updated
+var RefEvents = struct {
+	Enabled   bool
+	Type      string
+	ConnStr   string
+	Stream    string
+	MaxLength int64
+	AllRefs   bool
+	Timeout   time.Duration
+	Retries   int
+}{
+	Type:      "redis",
+	Stream:    "gitea:ref-events",
+	MaxLength: 100000,
+	Timeout:   5 * time.Second,
+	Retries:   3,
+}
+
+func newRefEvents() {
+	sec := Cfg.Section("ref_events")
+	RefEvents.Enabled = sec.Key("ENABLED").MustBool(false)
+	RefEvents.Type = sec.Key("TYPE").MustString(RefEvents.Type)
+	RefEvents.ConnStr = sec.Key("CONN_STR").MustString("redis://127.0.0.1:6379/0")
+	RefEvents.Stream = sec.Key("STREAM").MustString(RefEvents.Stream)
+	RefEvents.MaxLength = sec.Key("MAX_LENGTH").MustInt64(RefEvents.MaxLength)
+	RefEvents.AllRefs = sec.Key("ALL_REFS").MustBool(false)
+	RefEvents.Timeout = sec.Key("TIMEOUT").MustDuration(RefEvents.Timeout)
+	RefEvents.Retries = sec.Key("RETRIES").MustInt(RefEvents.Retries)
+}
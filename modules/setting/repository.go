@@ -9,6 +9,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -49,6 +50,7 @@ var (
 		AllowAdoptionOfUnadoptedRepositories    bool
 		AllowDeleteOfUnadoptedRepositories      bool
 		DisableDownloadSourceArchives           bool
+		LargePushThreshold                      int
 
 		// Repository editor settings
 		Editor struct {
@@ -58,11 +60,12 @@ var (
 
 		// Repository upload settings
 		Upload struct {
-			Enabled      bool
-			TempPath     string
-			AllowedTypes string
-			FileMaxSize  int64
-			MaxFiles     int
+			Enabled       bool
+			TempPath      string
+			AllowedTypes  string
+			FileMaxSize   int64
+			MaxFiles      int
+			BundleMaxSize int64
 		} `ini:"-"`
 
 		// Repository local settings
@@ -87,7 +90,8 @@ var (
 
 		// Issue Setting
 		Issue struct {
-			LockReasons []string
+			LockReasons         []string
+			MaxContentRevisions int
 		} `ini:"repository.issue"`
 
 		Release struct {
@@ -95,6 +99,18 @@ var (
 			DefaultPagingNum int
 		} `ini:"repository.release"`
 
+		// Raw file settings
+		Raw struct {
+			CORSAllowDomain      []string      `ini:"CORS_ALLOW_DOMAIN"`
+			CORSAllowCredentials bool          `ini:"CORS_ALLOW_CREDENTIALS"`
+			CORSMaxAge           time.Duration `ini:"CORS_MAX_AGE"`
+			CacheControl         string
+			AllowRepoOverride    bool
+			ImmutableMaxAge      time.Duration
+			SignedURLExpiry      time.Duration     `ini:"SIGNED_URL_EXPIRY"`
+			Headers              map[string]string `ini:"-"`
+		} `ini:"repository.raw"`
+
 		Signing struct {
 			SigningKey        string
 			SigningName       string
@@ -104,7 +120,16 @@ var (
 			Merges            []string
 			Wiki              []string
 			DefaultTrustModel string
+			Archives          bool
 		} `ini:"repository.signing"`
+
+		// Outline settings
+		Outline struct {
+			Enabled     bool
+			CtagsPath   string
+			MaxFileSize int64
+			Timeout     time.Duration
+		} `ini:"repository.outline"`
 	}{
 		DetectedCharsetsOrder: []string{
 			"UTF-8",
@@ -160,6 +185,7 @@ var (
 		DisableMigrations:                       false,
 		DisableStars:                            false,
 		DefaultBranch:                           "main",
+		LargePushThreshold:                      1000,
 
 		// Repository editor settings
 		Editor: struct {
@@ -172,17 +198,19 @@ var (
 
 		// Repository upload settings
 		Upload: struct {
-			Enabled      bool
-			TempPath     string
-			AllowedTypes string
-			FileMaxSize  int64
-			MaxFiles     int
+			Enabled       bool
+			TempPath      string
+			AllowedTypes  string
+			FileMaxSize   int64
+			MaxFiles      int
+			BundleMaxSize int64
 		}{
-			Enabled:      true,
-			TempPath:     "data/tmp/uploads",
-			AllowedTypes: "",
-			FileMaxSize:  3,
-			MaxFiles:     5,
+			Enabled:       true,
+			TempPath:      "data/tmp/uploads",
+			AllowedTypes:  "",
+			FileMaxSize:   3,
+			MaxFiles:      5,
+			BundleMaxSize: 1024,
 		},
 
 		// Repository local settings
@@ -223,9 +251,11 @@ var (
 
 		// Issue settings
 		Issue: struct {
-			LockReasons []string
+			LockReasons         []string
+			MaxContentRevisions int
 		}{
-			LockReasons: strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			LockReasons:         strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			MaxContentRevisions: 20,
 		},
 
 		Release: struct {
@@ -236,6 +266,27 @@ var (
 			DefaultPagingNum: 10,
 		},
 
+		// Raw file settings
+		Raw: struct {
+			CORSAllowDomain      []string      `ini:"CORS_ALLOW_DOMAIN"`
+			CORSAllowCredentials bool          `ini:"CORS_ALLOW_CREDENTIALS"`
+			CORSMaxAge           time.Duration `ini:"CORS_MAX_AGE"`
+			CacheControl         string
+			AllowRepoOverride    bool
+			ImmutableMaxAge      time.Duration
+			SignedURLExpiry      time.Duration     `ini:"SIGNED_URL_EXPIRY"`
+			Headers              map[string]string `ini:"-"`
+		}{
+			CORSAllowDomain:      []string{},
+			CORSAllowCredentials: false,
+			CORSMaxAge:           10 * time.Minute,
+			CacheControl:         "",
+			AllowRepoOverride:    false,
+			ImmutableMaxAge:      365 * 24 * time.Hour,
+			SignedURLExpiry:      0,
+			Headers:              map[string]string{},
+		},
+
 		// Signing settings
 		Signing: struct {
 			SigningKey        string
@@ -246,6 +297,7 @@ var (
 			Merges            []string
 			Wiki              []string
 			DefaultTrustModel string
+			Archives          bool
 		}{
 			SigningKey:        "default",
 			SigningName:       "",
@@ -256,6 +308,19 @@ var (
 			Wiki:              []string{"never"},
 			DefaultTrustModel: "collaborator",
 		},
+
+		// Outline settings
+		Outline: struct {
+			Enabled     bool
+			CtagsPath   string
+			MaxFileSize int64
+			Timeout     time.Duration
+		}{
+			Enabled:     true,
+			CtagsPath:   "",
+			MaxFileSize: 1024 * 1024,
+			Timeout:     10 * time.Second,
+		},
 	}
 	RepoRootPath string
 	ScriptType   = "bash"
@@ -300,7 +365,10 @@ func newRepository() {
 		log.Fatal("Failed to map Repository.Local settings: %v", err)
 	} else if err = Cfg.Section("repository.pull-request").MapTo(&Repository.PullRequest); err != nil {
 		log.Fatal("Failed to map Repository.PullRequest settings: %v", err)
+	} else if err = Cfg.Section("repository.raw").MapTo(&Repository.Raw); err != nil {
+		log.Fatal("Failed to map Repository.Raw settings: %v", err)
 	}
+	Repository.Raw.Headers = Cfg.Section("repository.raw.headers").KeysHash()
 
 	if !Cfg.Section("packages").Key("ENABLED").MustBool(true) {
 		Repository.DisabledRepoUnits = append(Repository.DisabledRepoUnits, "repo.packages")
@@ -351,9 +351,14 @@ var (
 	}
 
 	// Admin settings
-	Admin struct {
+	Admin = struct {
 		DisableRegularOrgCreation bool
 		DefaultEmailNotification  string
+		NameClaimDormantPeriod    time.Duration
+		NameClaimGracePeriod      time.Duration
+	}{
+		NameClaimDormantPeriod: 365 * 24 * time.Hour,
+		NameClaimGracePeriod:   30 * 24 * time.Hour,
 	}
 
 	// Log settings
@@ -1043,6 +1048,8 @@ func loadFromConf(allowEmpty bool, extraConfig string) {
 
 	newPackages()
 
+	newPages()
+
 	if err = Cfg.Section("ui").MapTo(&UI); err != nil {
 		log.Fatal("Failed to map UI settings: %v", err)
 	} else if err = Cfg.Section("markdown").MapTo(&Markdown); err != nil {
@@ -1096,6 +1103,9 @@ func loadFromConf(allowEmpty bool, extraConfig string) {
 	}
 
 	newMarkup()
+	newPushValidation()
+	newRefEvents()
+	newDiagnostics()
 
 	UI.ReactionsMap = make(map[string]bool)
 	for _, reaction := range UI.Reactions {
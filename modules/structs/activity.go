@@ -0,0 +1,25 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// Activity represents an activity feed entry
+type Activity struct {
+	ID int64 `json:"id"`
+	// the user or organization which receives the activity
+	UserID int64 `json:"user_id"`
+	// the type of the activity, e.g. create_repo or commit_repo
+	OpType    string      `json:"op_type"`
+	ActUserID int64       `json:"act_user_id"`
+	ActUser   *User       `json:"act_user"`
+	RepoID    int64       `json:"repo_id"`
+	Repo      *Repository `json:"repo"`
+	CommentID int64       `json:"comment_id"`
+	RefName   string      `json:"ref_name"`
+	IsPrivate bool        `json:"is_private"`
+	Content   string      `json:"content"`
+	Created   time.Time   `json:"created"`
+}
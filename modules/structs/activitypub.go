@@ -4,7 +4,32 @@
 
 package structs
 
+import (
+	"time"
+)
+
 // ActivityPub type
 type ActivityPub struct {
 	Context string `json:"@context"`
 }
+
+// FederatedInstance represents an entry of the federation allowlist or blocklist
+type FederatedInstance struct {
+	ID   int64  `json:"id"`
+	Host string `json:"host"`
+	// enum: allow,block
+	Policy string `json:"policy"`
+	Reason string `json:"reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SetFederatedInstanceOption options for adding an instance to the federation allowlist or blocklist
+type SetFederatedInstanceOption struct {
+	// required: true
+	// enum: allow,block
+	Policy string `json:"policy" binding:"Required;In(allow,block)"`
+	Reason string `json:"reason"`
+}
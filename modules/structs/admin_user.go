@@ -5,6 +5,8 @@
 
 package structs
 
+import "time"
+
 // CreateUserOption create user options
 type CreateUserOption struct {
 	SourceID  int64  `json:"source_id"`
@@ -21,6 +23,8 @@ type CreateUserOption struct {
 	SendNotify         bool   `json:"send_notify"`
 	Restricted         *bool  `json:"restricted"`
 	Visibility         string `json:"visibility" binding:"In(,public,limited,private)"`
+	// create a service account, which can only authenticate with access tokens
+	ServiceAccount bool `json:"service_account"`
 }
 
 // EditUserOption edit user options
@@ -47,3 +51,32 @@ type EditUserOption struct {
 	Restricted              *bool   `json:"restricted"`
 	Visibility              string  `json:"visibility" binding:"In(,public,limited,private)"`
 }
+
+// AdminBulkUsersOption options for running an action on several users at once
+type AdminBulkUsersOption struct {
+	// required: true
+	Usernames []string `json:"usernames" binding:"Required"`
+	// action to run for every listed user
+	// required: true
+	// enum: disable,force_password_reset,delete
+	Action string `json:"action" binding:"Required;In(disable,force_password_reset,delete)"`
+	// only used by the delete action: owner the repositories of the deleted users are transferred to
+	TransferReposTo string `json:"transfer_repos_to"`
+	// only used by the delete action: purge the users' content as well
+	Purge bool `json:"purge"`
+}
+
+// AdminBulkUserResult represents the result of a bulk action for a single user
+type AdminBulkUserResult struct {
+	Username string `json:"username"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// TwoFactorRecovery represents a one-time two-factor recovery code issued by an administrator
+type TwoFactorRecovery struct {
+	// the code is only shown once
+	Code string `json:"code"`
+	// swagger:strfmt date-time
+	ExpiresAt time.Time `json:"expires_at"`
+}
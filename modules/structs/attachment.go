@@ -19,6 +19,8 @@ type Attachment struct {
 	Created     time.Time `json:"created_at"`
 	UUID        string    `json:"uuid"`
 	DownloadURL string    `json:"browser_download_url"`
+	// SHA256 checksum of the file, empty for files uploaded before checksums were recorded
+	SHA256 string `json:"sha256,omitempty"`
 }
 
 // EditAttachmentOptions options for editing attachments
@@ -26,3 +28,20 @@ type Attachment struct {
 type EditAttachmentOptions struct {
 	Name string `json:"name"`
 }
+
+// CreateAttachmentFromURLOptions options for creating an attachment from a file downloaded by the server
+// swagger:model
+type CreateAttachmentFromURLOptions struct {
+	// http or https url of the file
+	// required: true
+	URL string `json:"url" binding:"Required"`
+	// name of the attachment, defaults to the last element of the url path
+	Name string `json:"name"`
+}
+
+// PastedAttachment is an uploaded attachment with a markdown reference to it
+type PastedAttachment struct {
+	Attachment *Attachment `json:"attachment"`
+	// markdown to reference the attachment in an issue or a comment
+	Markdown string `json:"markdown"`
+}
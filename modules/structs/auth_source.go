@@ -0,0 +1,36 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// AuthSource represents an authentication source
+type AuthSource struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: ldap,dldap,smtp,pam,oauth2,sspi
+	Type          string `json:"type"`
+	IsActive      bool   `json:"is_active"`
+	IsSyncEnabled bool   `json:"is_sync_enabled"`
+	// id of the source used to sign in while this source is unreachable, 0 if there is none
+	FallbackSourceID int64             `json:"fallback_source_id"`
+	Health           *AuthSourceHealth `json:"health"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated"`
+}
+
+// AuthSourceHealth represents the result of the last health check of an authentication source
+type AuthSourceHealth struct {
+	// unknown if the source has not been checked yet or does not support health checks
+	// enum: unknown,healthy,unhealthy
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	// swagger:strfmt date-time
+	LastHealthyAt *time.Time `json:"last_healthy_at,omitempty"`
+}
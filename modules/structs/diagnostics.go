@@ -0,0 +1,34 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// DiagnosticRequest represents a served request kept by the request log
+type DiagnosticRequest struct {
+	// swagger:strfmt date-time
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// the route pattern which handled the request
+	Route string `json:"route"`
+	Path  string `json:"path"`
+	// the name of the signed in user, empty for anonymous requests
+	User   string `json:"user"`
+	Status int    `json:"status"`
+	// duration in milliseconds
+	Duration float64 `json:"duration_ms"`
+	// size of the response body in bytes
+	Size int64 `json:"size"`
+}
+
+// DiagnosticSlowQuery represents a database query which took longer than the slow query threshold
+type DiagnosticSlowQuery struct {
+	// swagger:strfmt date-time
+	Time time.Time `json:"time"`
+	SQL  string    `json:"sql"`
+	// duration in milliseconds
+	Duration float64 `json:"duration_ms"`
+	Error    string  `json:"error,omitempty"`
+}
@@ -11,3 +11,21 @@ type CreateForkOption struct {
 	// name of the forked repository
 	Name *string `json:"name"`
 }
+
+// ForkNetwork represents a repository of a fork network together with its forks, including the forks of forks
+type ForkNetwork struct {
+	Repository *Repository    `json:"repository"`
+	Forks      []*ForkNetwork `json:"forks"`
+}
+
+// ForkComparison represents the difference between a branch of a fork and a branch of the root repository of its fork network
+type ForkComparison struct {
+	Root       *Repository `json:"root"`
+	RootBranch string      `json:"root_branch"`
+	Branch     string      `json:"branch"`
+	MergeBase  string      `json:"merge_base"`
+	// number of commits of the branch which aren't in the root branch
+	AheadBy int `json:"ahead_by"`
+	// number of commits of the root branch which aren't in the branch
+	BehindBy int `json:"behind_by"`
+}
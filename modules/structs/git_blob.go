@@ -12,3 +12,31 @@ type GitBlobResponse struct {
 	SHA      string `json:"sha"`
 	Size     int64  `json:"size"`
 }
+
+// BlobDiffSide represents one of the two blobs of a blob diff
+type BlobDiffSide struct {
+	// full name of the repository of the blob
+	Repository string `json:"repository"`
+	// empty if the blob was addressed by its sha
+	Ref string `json:"ref,omitempty"`
+	// empty if the blob was addressed by its sha
+	CommitID string `json:"commit_id,omitempty"`
+	// empty if the blob was addressed by its sha
+	Path string `json:"path,omitempty"`
+	SHA  string `json:"sha"`
+	Size int64  `json:"size"`
+}
+
+// BlobDiff represents the diff of two blobs
+type BlobDiff struct {
+	Base *BlobDiffSide `json:"base"`
+	Head *BlobDiffSide `json:"head"`
+	// whether the contents of both blobs are equal
+	Identical bool        `json:"identical"`
+	IsBinary  bool        `json:"is_binary"`
+	Additions int         `json:"additions"`
+	Deletions int         `json:"deletions"`
+	Hunks     []*DiffHunk `json:"hunks"`
+	// whether the diff was too large to be loaded completely
+	IsIncomplete bool `json:"is_incomplete"`
+}
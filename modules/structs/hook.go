@@ -58,6 +58,19 @@ type EditHookOption struct {
 	Active       *bool             `json:"active"`
 }
 
+// ApplyDefaultHookOption options when applying a default hook to existing repositories
+type ApplyDefaultHookOption struct {
+	// glob pattern matched against the repository name for default hooks of an organization
+	// and against "owner/name" for admin-default hooks, empty matches all repositories
+	Pattern string `json:"pattern" binding:"GlobPattern"`
+}
+
+// ApplyDefaultHookResult is the result of applying a default hook to existing repositories
+type ApplyDefaultHookResult struct {
+	// number of repositories the hook was copied to
+	Applied int `json:"applied"`
+}
+
 // Payloader payload is some part of one hook
 type Payloader interface {
 	JSONPayload() ([]byte, error)
@@ -228,6 +241,32 @@ func (p *IssueCommentPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
 
+// HookReactionAction defines hook reaction action
+type HookReactionAction string
+
+// all reaction actions
+const (
+	HookReactionCreated HookReactionAction = "created"
+	HookReactionDeleted HookReactionAction = "deleted"
+)
+
+// ReactionPayload represents a payload information of reaction event.
+// Comment is only set for reactions to comments.
+type ReactionPayload struct {
+	Action     HookReactionAction `json:"action"`
+	Reaction   *Reaction          `json:"reaction"`
+	Issue      *Issue             `json:"issue"`
+	Comment    *Comment           `json:"comment,omitempty"`
+	Repository *Repository        `json:"repository"`
+	Sender     *User              `json:"sender"`
+	IsPull     bool               `json:"is_pull"`
+}
+
+// JSONPayload implements Payload
+func (p *ReactionPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
 // __________       .__
 // \______   \ ____ |  |   ____ _____    ______ ____
 //  |       _// __ \|  | _/ __ \\__  \  /  ___// __ \
@@ -245,10 +284,42 @@ const (
 	HookReleaseDeleted   HookReleaseAction = "deleted"
 )
 
+// ReleaseChannel defines the channel a release is published in
+type ReleaseChannel string
+
+// all release channels
+const (
+	ReleaseChannelStable     ReleaseChannel = "stable"
+	ReleaseChannelPrerelease ReleaseChannel = "prerelease"
+	ReleaseChannelDraft      ReleaseChannel = "draft"
+)
+
+// ReleaseChangelogCommit represents a commit of a release changelog
+type ReleaseChangelogCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  string `json:"author"`
+	URL     string `json:"url"`
+}
+
+// ReleaseChangelog represents the changes since the previous release
+type ReleaseChangelog struct {
+	// PreviousTag is empty for the first release of a repository
+	PreviousTag string                    `json:"previous_tag"`
+	CompareURL  string                    `json:"compare_url"`
+	Commits     []*ReleaseChangelogCommit `json:"commits"`
+	// Truncated is true if the release contains more commits than listed
+	Truncated bool `json:"truncated"`
+	// Body is the changelog rendered as markdown list
+	Body string `json:"body"`
+}
+
 // ReleasePayload represents a payload information of release event.
 type ReleasePayload struct {
 	Action     HookReleaseAction `json:"action"`
 	Release    *Release          `json:"release"`
+	Channel    ReleaseChannel    `json:"channel"`
+	Changelog  *ReleaseChangelog `json:"changelog,omitempty"`
 	Repository *Repository       `json:"repository"`
 	Sender     *User             `json:"sender"`
 }
@@ -272,10 +343,22 @@ type PushPayload struct {
 	After      string           `json:"after"`
 	CompareURL string           `json:"compare_url"`
 	Commits    []*PayloadCommit `json:"commits"`
-	HeadCommit *PayloadCommit   `json:"head_commit"`
-	Repo       *Repository      `json:"repository"`
-	Pusher     *User            `json:"pusher"`
-	Sender     *User            `json:"sender"`
+	// TotalCommits is the number of commits of the push, Commits only contains the latest of them
+	TotalCommits int `json:"total_commits"`
+	// CommitsURL lists all commits of the push page by page
+	CommitsURL string         `json:"commits_url"`
+	HeadCommit *PayloadCommit `json:"head_commit"`
+	Repo       *Repository    `json:"repository"`
+	Pusher     *User          `json:"pusher"`
+	Sender     *User          `json:"sender"`
+}
+
+// CommitCount returns the number of commits of the push
+func (p *PushPayload) CommitCount() int {
+	if p.TotalCommits > len(p.Commits) {
+		return p.TotalCommits
+	}
+	return len(p.Commits)
 }
 
 // JSONPayload FIXME
@@ -412,6 +495,18 @@ const (
 	HookRepoCreated HookRepoAction = "created"
 	// HookRepoDeleted deleted
 	HookRepoDeleted HookRepoAction = "deleted"
+	// HookRepoTransferRequested a transfer to a new owner was requested
+	HookRepoTransferRequested HookRepoAction = "transfer_requested"
+	// HookRepoTransferAccepted a scheduled transfer was accepted by the new owner
+	HookRepoTransferAccepted HookRepoAction = "transfer_accepted"
+	// HookRepoTransferScheduled the schedule of a pending transfer was changed
+	HookRepoTransferScheduled HookRepoAction = "transfer_scheduled"
+	// HookRepoTransferRejected a pending transfer was rejected by the new owner
+	HookRepoTransferRejected HookRepoAction = "transfer_rejected"
+	// HookRepoTransferCanceled a pending transfer was canceled
+	HookRepoTransferCanceled HookRepoAction = "transfer_canceled"
+	// HookRepoTransferred transferred to a new owner
+	HookRepoTransferred HookRepoAction = "transferred"
 )
 
 // RepositoryPayload payload for repository webhooks
@@ -450,3 +545,35 @@ type PackagePayload struct {
 func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// DigestEntry is an issue, a pull request or a release listed in a digest
+type DigestEntry struct {
+	// Number is the index of an issue or a pull request and zero for releases
+	Number  int64  `json:"number,omitempty"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+// DigestRepository summarizes the activity of a repository during the period of a digest
+type DigestRepository struct {
+	Repository         *Repository    `json:"repository"`
+	Commits            int64          `json:"commits"`
+	CommitAuthors      int64          `json:"commit_authors"`
+	MergedPullRequests []*DigestEntry `json:"merged_pull_requests"`
+	NewIssues          []*DigestEntry `json:"new_issues"`
+	Releases           []*DigestEntry `json:"releases"`
+}
+
+// DigestPayload represents the weekly summary of the activity of a repository or of the repositories of an organization.
+// Organization is only set for organization webhooks, which receive a single digest for all their repositories.
+type DigestPayload struct {
+	Since        time.Time           `json:"since"`
+	Until        time.Time           `json:"until"`
+	Organization *Organization       `json:"organization,omitempty"`
+	Repositories []*DigestRepository `json:"repositories"`
+}
+
+// JSONPayload implements Payload
+func (p *DigestPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
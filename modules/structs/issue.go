@@ -50,6 +50,8 @@ type Issue struct {
 	Ref              string     `json:"ref"`
 	Labels           []*Label   `json:"labels"`
 	Milestone        *Milestone `json:"milestone"`
+	// progress of the task list in the body
+	TaskProgress *IssueTaskProgress `json:"task_progress"`
 	// deprecated
 	Assignee  *User   `json:"assignee"`
 	Assignees []*User `json:"assignees"`
@@ -73,6 +75,29 @@ type Issue struct {
 	Repo        *RepositoryMeta  `json:"repository"`
 }
 
+// IssueTaskProgress represents the progress of the task list in the body of an issue or pull request
+type IssueTaskProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// IssueTask represents an item of the task list in the body of an issue or pull request
+type IssueTask struct {
+	// position of the task in the body, starting at 0
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// EditIssueTaskOption options for checking or unchecking a task in the body of an issue or pull request
+type EditIssueTaskOption struct {
+	// required: true
+	Checked *bool `json:"checked" binding:"Required"`
+	// if set, the request is rejected if the text of the task differs,
+	// e.g. because the body was edited in the meantime
+	Text *string `json:"text"`
+}
+
 // CreateIssueOption options to create one issue
 type CreateIssueOption struct {
 	// required:true
@@ -136,3 +161,19 @@ type IssueTemplate struct {
 func (it IssueTemplate) Valid() bool {
 	return strings.TrimSpace(it.Name) != "" && strings.TrimSpace(it.About) != ""
 }
+
+// IssueConfigContactLink represents an external link shown in the issue template chooser
+type IssueConfigContactLink struct {
+	Name  string `json:"name" yaml:"name"`
+	URL   string `json:"url" yaml:"url"`
+	About string `json:"about" yaml:"about"`
+}
+
+// IssueConfig represents the configuration of the issue template chooser
+// swagger:model
+type IssueConfig struct {
+	BlankIssuesEnabled bool                     `json:"blank_issues_enabled" yaml:"blank_issues_enabled"`
+	ContactLinks       []IssueConfigContactLink `json:"contact_links" yaml:"contact_links"`
+	// file names of the issue templates in the order they are shown in
+	Order []string `json:"order" yaml:"order"`
+}
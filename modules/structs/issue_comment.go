@@ -22,6 +22,9 @@ type Comment struct {
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
+	// whether the comment was hidden by a moderator
+	Hidden       bool   `json:"hidden"`
+	HiddenReason string `json:"hidden_reason"`
 }
 
 // CreateIssueCommentOption options for creating a comment on an issue
@@ -36,6 +39,19 @@ type EditIssueCommentOption struct {
 	Body string `json:"body" binding:"Required"`
 }
 
+// CommentRevision represents a revision of the content of a comment
+type CommentRevision struct {
+	ID     int64  `json:"id"`
+	Editor *User  `json:"editor"`
+	Body   string `json:"body"`
+	// whether this is the content the comment was created with
+	IsFirst bool `json:"is_first"`
+	// whether the content of the revision was deleted
+	IsDeleted bool `json:"is_deleted"`
+	// swagger:strfmt date-time
+	Edited time.Time `json:"edited_at"`
+}
+
 // TimelineComment represents a timeline comment (comment of any type) on a commit or issue
 type TimelineComment struct {
 	ID   int64  `json:"id"`
@@ -14,6 +14,9 @@ type Label struct {
 	Color       string `json:"color"`
 	Description string `json:"description"`
 	URL         string `json:"url"`
+	// scope of a scoped label like `priority::high`, an issue can only have one label of a scope
+	// example: priority
+	Scope string `json:"scope"`
 }
 
 // CreateLabelOption options for creating a label
@@ -0,0 +1,38 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// HideIssueCommentOption options for hiding a comment
+type HideIssueCommentOption struct {
+	// required: true
+	// enum: spam,abuse,off-topic,outdated,duplicate,resolved
+	Reason string `json:"reason" binding:"Required"`
+}
+
+// LockIssueOption options for locking the conversation of an issue or pull request
+type LockIssueOption struct {
+	// one of the lock reasons of the instance, empty for no reason
+	Reason string `json:"reason"`
+	// duration of the lock like `24h`, the conversation is unlocked automatically afterwards. Empty for a lock without expiry
+	Duration string `json:"duration"`
+}
+
+// ModerationLog represents a moderation of an issue or a comment
+type ModerationLog struct {
+	ID int64 `json:"id"`
+	// enum: hide_comment,unhide_comment,edit_comment,delete_comment,lock,unlock
+	Action     string `json:"action"`
+	Doer       *User  `json:"doer"`
+	IssueIndex int64  `json:"issue_index"`
+	// 0 for moderations of the issue itself
+	CommentID int64  `json:"comment_id"`
+	Reason    string `json:"reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
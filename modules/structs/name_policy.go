@@ -0,0 +1,54 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// NamePolicy represents a rule for the names of new users and organizations
+type NamePolicy struct {
+	ID int64 `json:"id"`
+	// enum: reserved,service_prefix
+	Kind        string `json:"kind"`
+	Pattern     string `json:"pattern"`
+	Description string `json:"description"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateNamePolicyOption options for creating a name policy
+type CreateNamePolicyOption struct {
+	// reserved names match the glob pattern, service account names start with the prefix
+	// required: true
+	// enum: reserved,service_prefix
+	Kind string `json:"kind" binding:"Required;In(reserved,service_prefix)"`
+	// required: true
+	Pattern     string `json:"pattern" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+}
+
+// NameClaim represents the reclaim of the name of a dormant user
+type NameClaim struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	Claimant *User  `json:"claimant"`
+	// enum: pending,completed,cancelled
+	Status string `json:"status"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Deadline time.Time `json:"deadline"`
+}
+
+// CreateNameClaimOption options for reclaiming the name of a dormant user
+type CreateNameClaimOption struct {
+	// name of the dormant user
+	// required: true
+	Username string `json:"username" binding:"Required"`
+	// name of the user who gets the released name
+	Claimant string `json:"claimant"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// OrgJoinRequest represents the request of a user to join an organization or team
+type OrgJoinRequest struct {
+	ID           int64         `json:"id"`
+	User         *User         `json:"user"`
+	Organization *Organization `json:"organization"`
+	// the requested team, empty if the reviewer chooses the team
+	Team    *Team  `json:"team,omitempty"`
+	Message string `json:"message"`
+	// enum: pending,approved,rejected
+	Status   string `json:"status"`
+	Reviewer *User  `json:"reviewer,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateOrgJoinRequestOption options for requesting to join an organization
+type CreateOrgJoinRequestOption struct {
+	// name of the team to join, the owners choose the team if empty
+	Team    string `json:"team"`
+	Message string `json:"message" binding:"MaxSize(1000)"`
+}
+
+// ApproveOrgJoinRequestOption options for approving a join request
+type ApproveOrgJoinRequestOption struct {
+	// name of the team to add the user to, required if the request is not for a specific team
+	Team string `json:"team"`
+}
@@ -17,10 +17,18 @@ type Package struct {
 	Type       string      `json:"type"`
 	Name       string      `json:"name"`
 	Version    string      `json:"version"`
+	// the changes of the version in markdown
+	ReleaseNotes string `json:"release_notes,omitempty"`
 	// swagger:strfmt date-time
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// EditPackageOption options for editing a package version
+type EditPackageOption struct {
+	// the changes of the version in markdown, an empty string removes the release notes
+	ReleaseNotes *string `json:"release_notes"`
+}
+
 // PackageFile represents a package file
 type PackageFile struct {
 	ID         int64 `json:"id"`
@@ -31,3 +39,213 @@ type PackageFile struct {
 	HashSHA256 string `json:"sha256"`
 	HashSHA512 string `json:"sha512"`
 }
+
+// PackageDependency represents a dependency declared by a package version
+type PackageDependency struct {
+	// enum: runtime,development,peer,optional
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	VersionRange string `json:"version_range"`
+}
+
+// PackageDependent represents a package version which depends on a package
+type PackageDependent struct {
+	Package    *Package           `json:"package"`
+	Dependency *PackageDependency `json:"dependency"`
+}
+
+// PackageAccess represents the access settings of a package
+type PackageAccess struct {
+	// enum: inherit,public,private
+	Visibility string               `json:"visibility"`
+	Teams      []*PackageTeamAccess `json:"teams"`
+}
+
+// PackageTeamAccess represents the access of a team to a package
+type PackageTeamAccess struct {
+	Team *Team `json:"team"`
+	// enum: read,write
+	Permission string `json:"permission"`
+}
+
+// EditPackageAccessOption options for editing the access settings of a package
+type EditPackageAccessOption struct {
+	// inherit uses the visibility of the owner
+	// required: true
+	// enum: inherit,public,private
+	Visibility string `json:"visibility" binding:"Required;In(inherit,public,private)"`
+}
+
+// PackageTeamAccessOption options for granting a team access to a package
+type PackageTeamAccessOption struct {
+	// required: true
+	// enum: read,write
+	Permission string `json:"permission" binding:"Required;In(read,write)"`
+}
+
+// PackageDownloadStats represents the download statistics of a package or a package version
+type PackageDownloadStats struct {
+	// first day of the statistics (YYYY-MM-DD, UTC)
+	Since string `json:"since"`
+	// last day of the statistics (YYYY-MM-DD, UTC)
+	Until   string                    `json:"until"`
+	Total   int64                     `json:"total"`
+	Days    []*PackageDownloadsDay    `json:"days"`
+	Clients []*PackageDownloadsClient `json:"clients"`
+	// downloads per file, only present for a package version
+	Files []*PackageDownloadsFile `json:"files,omitempty"`
+	// downloads per version, only present for a package
+	Versions []*PackageDownloadsVersion `json:"versions,omitempty"`
+}
+
+// PackageDownloadsDay represents the downloads of a day
+type PackageDownloadsDay struct {
+	// day (YYYY-MM-DD, UTC)
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// PackageDownloadsClient represents the downloads by a client class
+type PackageDownloadsClient struct {
+	// client class derived from the user agent, e.g. npm, pypi, maven, container, browser, cli, other or unknown
+	Client string `json:"client"`
+	Count  int64  `json:"count"`
+}
+
+// PackageDownloadsFile represents the downloads of a package file
+type PackageDownloadsFile struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// PackageDownloadsVersion represents the downloads of a package version
+type PackageDownloadsVersion struct {
+	Version string `json:"version"`
+	Count   int64  `json:"count"`
+}
+
+// PackageBlob represents content stored for packages of an owner
+type PackageBlob struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// PackageAuditLog represents an entry of the package audit log
+type PackageAuditLog struct {
+	ID int64 `json:"id"`
+	// package for operations on packages, admin for operations of administrators on behalf of the owner
+	// enum: package,admin
+	Scope string `json:"scope"`
+	Owner string `json:"owner"`
+	// enum: publish,delete,download,permission,provision_ssh_key,provision_gpg_key,provision_token,issue_2fa_recovery,use_2fa_recovery
+	Action string `json:"action"`
+	// 0 for anonymous requests
+	ActorID int64  `json:"actor_id"`
+	Actor   string `json:"actor"`
+	// empty for changes of owner-wide settings
+	PackageType string `json:"package_type"`
+	PackageName string `json:"package_name"`
+	Version     string `json:"version"`
+	Filename    string `json:"filename"`
+	// in the admin scope the name of the provisioned credential or the expiry of an issued recovery code
+	Detail    string `json:"detail"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PackageMirrorRule selects the versions of an upstream package which are mirrored
+type PackageMirrorRule struct {
+	Name string `json:"name"`
+	// version constraint like ">= 1.2, < 2" or "~> 1.2", empty to mirror all versions
+	VersionRange string `json:"version_range"`
+}
+
+// PackageMirror represents a scheduled sync of selected packages from an upstream registry
+type PackageMirror struct {
+	ID int64 `json:"id"`
+	// enum: composer,npm
+	Type        string               `json:"type"`
+	UpstreamURL string               `json:"upstream_url"`
+	Rules       []*PackageMirrorRule `json:"rules"`
+	Interval    string               `json:"interval"`
+	// swagger:strfmt date-time
+	LastSync *time.Time `json:"last_sync"`
+	// swagger:strfmt date-time
+	NextSync  time.Time `json:"next_sync"`
+	LastError string    `json:"last_error"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatePackageMirrorOption options for creating a package mirror
+type CreatePackageMirrorOption struct {
+	// required: true
+	// enum: composer,npm
+	Type string `json:"type" binding:"Required"`
+	// url of the upstream registry, e.g. https://registry.npmjs.org or https://repo.packagist.org
+	// required: true
+	UpstreamURL string `json:"upstream_url" binding:"Required"`
+	// required: true
+	Rules []*PackageMirrorRule `json:"rules" binding:"Required"`
+	// sync interval like 12h, defaults to 24h
+	Interval string `json:"interval"`
+}
+
+// EditPackageMirrorOption options for editing a package mirror
+type EditPackageMirrorOption struct {
+	UpstreamURL *string `json:"upstream_url"`
+	// replaces all rules if set
+	Rules    []*PackageMirrorRule `json:"rules"`
+	Interval *string              `json:"interval"`
+}
+
+// PackageMirrorSyncResult represents the result of a package mirror sync
+type PackageMirrorSyncResult struct {
+	// package versions added by the sync as name@version
+	Synced []string `json:"synced"`
+	Errors []string `json:"errors"`
+}
+
+// PackageVendorGrant represents a vendor namespace of a composer registry reserved for a team
+type PackageVendorGrant struct {
+	Vendor string `json:"vendor"`
+	Team   *Team  `json:"team"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// PackageContainerDomain represents the custom domain of the container registry of an owner
+type PackageContainerDomain struct {
+	Domain string `json:"domain"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// SetPackageContainerDomainOption options for mapping a custom domain to the container registry of an owner
+type SetPackageContainerDomainOption struct {
+	// hostname the images of the owner are served on, without the owner in their path
+	// required: true
+	Domain string `json:"domain" binding:"Required"`
+}
+
+// PackageVersionDiff represents the files which differ between two versions of a package
+type PackageVersionDiff struct {
+	From  string             `json:"from"`
+	To    string             `json:"to"`
+	Files []*PackageFileDiff `json:"files"`
+}
+
+// PackageFileDiff represents a file which differs between two versions of a package
+type PackageFileDiff struct {
+	// path of the file inside the package
+	Path string `json:"path"`
+	// enum: added,removed,modified
+	Status  string `json:"status"`
+	OldSize int64  `json:"old_size"`
+	NewSize int64  `json:"new_size"`
+	// unified diff of a text file, only included if requested
+	Patch string `json:"patch,omitempty"`
+}
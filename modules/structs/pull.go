@@ -19,10 +19,12 @@ type PullRequest struct {
 	Labels    []*Label   `json:"labels"`
 	Milestone *Milestone `json:"milestone"`
 	Assignee  *User      `json:"assignee"`
-	Assignees []*User    `json:"assignees"`
-	State     StateType  `json:"state"`
-	IsLocked  bool       `json:"is_locked"`
-	Comments  int        `json:"comments"`
+	// progress of the task list in the body
+	TaskProgress *IssueTaskProgress `json:"task_progress"`
+	Assignees    []*User            `json:"assignees"`
+	State        StateType          `json:"state"`
+	IsLocked     bool               `json:"is_locked"`
+	Comments     int                `json:"comments"`
 
 	HTMLURL  string `json:"html_url"`
 	DiffURL  string `json:"diff_url"`
@@ -95,3 +97,69 @@ type EditPullRequestOption struct {
 	RemoveDeadline      *bool      `json:"unset_due_date"`
 	AllowMaintainerEdit *bool      `json:"allow_maintainer_edit"`
 }
+
+// ChangedFile a file changed by a pull request
+type ChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	// enum: added,deleted,modified,renamed,copied,changed
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Changes   int    `json:"changes"`
+	IsBinary  bool   `json:"is_binary"`
+}
+
+// ChangedFileHunks the hunks of a file changed by a pull request
+type ChangedFileHunks struct {
+	Filename         string      `json:"filename"`
+	PreviousFilename string      `json:"previous_filename,omitempty"`
+	IsBinary         bool        `json:"is_binary"`
+	Hunks            []*DiffHunk `json:"hunks"`
+	// whether the diff of the file was too large to be loaded completely
+	IsIncomplete bool `json:"is_incomplete"`
+	// metadata of both versions of a binary file, only set if requested
+	Binary *BinaryDiff `json:"binary,omitempty"`
+}
+
+// BinaryDiff the change of a binary file
+type BinaryDiff struct {
+	// nil if the file was added
+	Before *BinaryDiffBlob `json:"before"`
+	// nil if the file was deleted
+	After      *BinaryDiffBlob `json:"after"`
+	SizeChange int64           `json:"size_change"`
+}
+
+// BinaryDiffBlob one version of a changed binary file
+type BinaryDiffBlob struct {
+	SHA      string `json:"sha"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+	IsImage  bool   `json:"is_image"`
+	// width of a raster image, 0 if unknown
+	Width int `json:"width"`
+	// height of a raster image, 0 if unknown
+	Height int `json:"height"`
+}
+
+// DiffHunk a hunk of the diff of a file
+type DiffHunk struct {
+	Header   string          `json:"header"`
+	OldStart int             `json:"old_start"`
+	OldLines int             `json:"old_lines"`
+	NewStart int             `json:"new_start"`
+	NewLines int             `json:"new_lines"`
+	Lines    []*DiffHunkLine `json:"lines"`
+}
+
+// DiffHunkLine a line of a diff hunk
+type DiffHunkLine struct {
+	// enum: context,add,delete
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	// line number in the old file, 0 for added lines
+	OldNumber int `json:"old_number"`
+	// line number in the new file, 0 for deleted lines
+	NewNumber int `json:"new_number"`
+}
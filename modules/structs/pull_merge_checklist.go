@@ -0,0 +1,40 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// MergeChecklistItem represents an item of the merge checklist of a repository
+type MergeChecklistItem struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateMergeChecklistItemOption options for adding an item to the merge checklist of a repository
+type CreateMergeChecklistItemOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+}
+
+// PullMergeChecklistItem represents the state of an item of the merge checklist on a pull request
+type PullMergeChecklistItem struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Checked   bool   `json:"checked"`
+	CheckedBy *User  `json:"checked_by"`
+	// swagger:strfmt date-time
+	CheckedAt *time.Time `json:"checked_at"`
+}
+
+// PullMergeChecklist represents the merge checklist of a pull request
+type PullMergeChecklist struct {
+	// true if all items are checked, a pull request can't be merged before
+	Complete bool                      `json:"complete"`
+	Items    []*PullMergeChecklistItem `json:"items"`
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// PullReviewStats represents the review throughput of the pull requests of a repository or an organization
+type PullReviewStats struct {
+	// first day of the statistics (YYYY-MM-DD, UTC)
+	Since string `json:"since"`
+	// last day of the statistics (YYYY-MM-DD, UTC)
+	Until string `json:"until"`
+	// number of pull requests opened in the range
+	Opened int64 `json:"opened"`
+	// number of pull requests merged in the range
+	Merged int64 `json:"merged"`
+	// time from opening to the first review of the pull requests first reviewed in the range
+	TimeToFirstReview *PullReviewDurations `json:"time_to_first_review"`
+	// time from opening to merging of the pull requests merged in the range
+	TimeToMerge *PullReviewDurations `json:"time_to_merge"`
+	// size distribution of the pull requests merged in the range
+	Sizes []*PullSizeCount `json:"sizes"`
+	// reviews submitted in the range per reviewer, most reviews first
+	Reviewers []*PullReviewerStats `json:"reviewers"`
+}
+
+// PullReviewDurations summarizes the durations of pull requests in seconds
+type PullReviewDurations struct {
+	Count          int64 `json:"count"`
+	AverageSeconds int64 `json:"average_seconds"`
+	MedianSeconds  int64 `json:"median_seconds"`
+	P90Seconds     int64 `json:"p90_seconds"`
+}
+
+// PullSizeCount represents the number of pull requests of a size class
+type PullSizeCount struct {
+	// size class by changed lines: xs (< 10), s (< 50), m (< 250), l (< 1000) or xl
+	// enum: xs,s,m,l,xl
+	Size  string `json:"size"`
+	Count int64  `json:"count"`
+}
+
+// PullReviewerStats represents the reviews submitted by a user
+type PullReviewerStats struct {
+	Reviewer   *User `json:"reviewer"`
+	Reviews    int64 `json:"reviews"`
+	Approvals  int64 `json:"approvals"`
+	Rejections int64 `json:"rejections"`
+	Comments   int64 `json:"comments"`
+}
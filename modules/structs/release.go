@@ -49,3 +49,28 @@ type EditReleaseOption struct {
 	IsDraft      *bool  `json:"draft"`
 	IsPrerelease *bool  `json:"prerelease"`
 }
+
+// ReleaseComparison represents the changes between two releases or tags
+type ReleaseComparison struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	FromCommit string `json:"from_commit"`
+	ToCommit   string `json:"to_commit"`
+	// TotalCommits is the number of commits reachable from `to` but not from `from`
+	TotalCommits int `json:"total_commits"`
+	// Commits is the requested page of the commits, newest first
+	Commits []*Commit `json:"commits"`
+	// PullRequests are the pull requests merged by the commits, in the order they were merged
+	PullRequests []*PullRequest `json:"pull_requests"`
+	// Contributors are the authors of the commits, most commits first
+	Contributors []*ReleaseContributor `json:"contributors"`
+}
+
+// ReleaseContributor represents an author of the commits between two releases
+type ReleaseContributor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// User is the user account of the author if the email is known
+	User    *User `json:"user"`
+	Commits int   `json:"commits"`
+}
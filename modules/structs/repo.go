@@ -25,6 +25,10 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Keywords of commit messages closing the referenced issues, the instance-wide keywords are used if empty (Built-in issue tracker)
+	CloseKeywords []string `json:"close_keywords"`
+	// Keywords of commit messages reopening the referenced issues, the instance-wide keywords are used if empty (Built-in issue tracker)
+	ReopenKeywords []string `json:"reopen_keywords"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -100,6 +104,8 @@ type Repository struct {
 	// swagger:strfmt date-time
 	MirrorUpdated time.Time     `json:"mirror_updated,omitempty"`
 	RepoTransfer  *RepoTransfer `json:"repo_transfer"`
+	// custom properties defined by the owning organization
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // CreateRepoOption options when creating repository
@@ -131,6 +137,34 @@ type CreateRepoOption struct {
 	// TrustModel of the repository
 	// enum: default,collaborator,committer,collaboratorcommitter
 	TrustModel string `json:"trust_model"`
+	// Content of the README, overrides the readme template
+	ReadmeContent string `json:"readme_content"`
+	// Content of the .gitignore file, overrides the gitignore templates
+	GitignoreContent string `json:"gitignore_content"`
+	// Content of the LICENSE file, overrides the license template
+	LicenseContent string `json:"license_content"`
+	// Additional files of the initial commit
+	Files []*CreateRepoFileOption `json:"files"`
+	// Labels to create in addition to the label set
+	Labels []*CreateLabelOption `json:"labels"`
+	// Branch protections to create
+	BranchProtections []*CreateBranchProtectionOption `json:"branch_protections"`
+	// Webhooks to create
+	Webhooks []*CreateHookOption `json:"webhooks"`
+	// Topics of the repository
+	Topics []string `json:"topics"`
+	// Values of the custom properties defined by the owner
+	Properties map[string]string `json:"properties"`
+}
+
+// CreateRepoFileOption a file of the initial commit of a new repository
+type CreateRepoFileOption struct {
+	// path of the file
+	//
+	// required: true
+	Path string `json:"path" binding:"Required"`
+	// content of the file, must be base64 encoded
+	Content string `json:"content"`
 }
 
 // EditRepoOption options when editing a repository's properties
@@ -247,6 +281,17 @@ type TransferRepoOption struct {
 	NewOwner string `json:"new_owner"`
 	// ID of the team or teams to add to the repository. Teams can only be added to organization-owned repositories.
 	TeamIDs *[]int64 `json:"team_ids"`
+	// Earliest time the transfer gets executed after it was accepted, for example a maintenance window.
+	// If set, the transfer is not executed immediately even if the new owner doesn't need to accept it.
+	// swagger:strfmt date-time
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+// EditRepoTransferOption options when changing the schedule of a pending repository transfer
+type EditRepoTransferOption struct {
+	// Earliest time the transfer gets executed after it was accepted, null executes it immediately after the acceptance
+	// swagger:strfmt date-time
+	ScheduledAt *time.Time `json:"scheduled_at"`
 }
 
 // GitServiceType represents a git service
@@ -352,4 +397,13 @@ type RepoTransfer struct {
 	Doer      *User   `json:"doer"`
 	Recipient *User   `json:"recipient"`
 	Teams     []*Team `json:"teams"`
+	// Repository is only set if the transfer is listed without its repository
+	Repository *Repository `json:"repository,omitempty"`
+	// swagger:strfmt date-time
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	// Set if a scheduled transfer was accepted and waits for its execution
+	// swagger:strfmt date-time
+	AcceptedAt *time.Time `json:"accepted_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
 }
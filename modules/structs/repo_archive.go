@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepoArchiveStatus represents the generation state of a repository archive
+type RepoArchiveStatus struct {
+	// enum: pending,ready,failed
+	Status   string `json:"status"`
+	CommitID string `json:"commit_id"`
+	// URL to poll for the status of the archive
+	StatusURL string `json:"status_url"`
+	// URL to download the archive from once it is ready
+	DownloadURL string `json:"download_url"`
+}
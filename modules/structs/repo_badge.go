@@ -0,0 +1,15 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Badge represents the data of a badge in the shields.io endpoint schema (https://shields.io/endpoint)
+type Badge struct {
+	// always 1
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color,omitempty"`
+	IsError       bool   `json:"isError,omitempty"`
+}
@@ -0,0 +1,13 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// BundleRefUpdate represents a reference updated by an uploaded git bundle
+type BundleRefUpdate struct {
+	Ref string `json:"ref"`
+	// empty if the reference was created
+	OldSHA string `json:"old_sha"`
+	NewSHA string `json:"new_sha"`
+}
@@ -4,9 +4,17 @@
 
 package structs
 
+import (
+	"time"
+)
+
 // AddCollaboratorOption options when adding a user as a collaborator of a repository
 type AddCollaboratorOption struct {
 	Permission *string `json:"permission"`
+	// time after which the access gets revoked automatically,
+	// the current expiry is kept if omitted and a zero time removes the expiry
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
 }
 
 // RepoCollaboratorPermission to get repository permission for a collaborator
@@ -15,3 +23,19 @@ type RepoCollaboratorPermission struct {
 	RoleName   string `json:"role_name"`
 	User       *User  `json:"user"`
 }
+
+// Collaboration represents the access of a collaborator to a repository
+type Collaboration struct {
+	User *User `json:"user"`
+	// the repository is only included in listings across repositories
+	Repository *Repository `json:"repository,omitempty"`
+	// enum: read,write,admin
+	Permission string `json:"permission"`
+	// time after which the access gets revoked, null if it never expires
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
+	// whether the collaborator was reminded about the expiry
+	Reminded bool `json:"reminded"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepoConfig is the declarative configuration of a repository. A section which
+// is omitted is left untouched when the configuration is applied, a section which
+// is present describes the complete state: missing items are created, existing
+// items are updated and items which are not listed are removed.
+type RepoConfig struct {
+	// the name, the archived state and the mirror settings are not managed
+	Settings          *EditRepoOption                 `json:"settings,omitempty"`
+	BranchProtections []*CreateBranchProtectionOption `json:"branch_protections"`
+	// webhooks are identified by their type and url, secrets are never exported
+	Webhooks []*CreateHookOption `json:"webhooks"`
+	// labels are identified by their name
+	Labels        []*CreateLabelOption      `json:"labels"`
+	Collaborators []*RepoConfigCollaborator `json:"collaborators"`
+}
+
+// RepoConfigCollaborator a collaborator of a repository
+type RepoConfigCollaborator struct {
+	Username string `json:"username"`
+	// enum: read,write,admin
+	Permission string `json:"permission"`
+}
@@ -133,3 +133,22 @@ type FileDeleteResponse struct {
 	Commit       *FileCommitResponse        `json:"commit"`
 	Verification *PayloadCommitVerification `json:"verification"`
 }
+
+// OutlineSymbol is an entry of the outline of a file
+type OutlineSymbol struct {
+	Name string `json:"name"`
+	// `kind` is e.g. `function`, `method`, `struct`, `class` or `heading`
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+	// `end_line` is 0 if the end of the symbol is unknown
+	EndLine  int              `json:"end_line"`
+	Children []*OutlineSymbol `json:"children"`
+}
+
+// FileOutline contains the structural outline of a repo's file
+type FileOutline struct {
+	Path     string           `json:"path"`
+	SHA      string           `json:"sha"`
+	Language string           `json:"language"`
+	Symbols  []*OutlineSymbol `json:"symbols"`
+}
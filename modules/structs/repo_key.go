@@ -17,7 +17,13 @@ type DeployKey struct {
 	Title       string `json:"title"`
 	Fingerprint string `json:"fingerprint"`
 	// swagger:strfmt date-time
-	Created    time.Time   `json:"created_at"`
+	Created time.Time `json:"created_at"`
+	// time the key was last used to access the repository, null if it was never used
+	// swagger:strfmt date-time
+	LastUsed *time.Time `json:"last_used_at"`
+	// time after which the key can't be used anymore, null if it never expires
+	// swagger:strfmt date-time
+	ExpiresAt  *time.Time  `json:"expires_at"`
 	ReadOnly   bool        `json:"read_only"`
 	Repository *Repository `json:"repository,omitempty"`
 }
@@ -38,4 +44,7 @@ type CreateKeyOption struct {
 	//
 	// required: false
 	ReadOnly bool `json:"read_only"`
+	// time after which the key can't be used anymore, the key never expires if omitted
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
 }
@@ -0,0 +1,70 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PropertyDefinition represents a custom property an organization defines for its repositories
+type PropertyDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the values the property may have, any value is allowed if empty
+	AllowedValues []string `json:"allowed_values"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreatePropertyDefinitionOption options for defining a custom repository property
+type CreatePropertyDefinitionOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description"`
+	// the values the property may have, any value is allowed if empty
+	AllowedValues []string `json:"allowed_values"`
+}
+
+// EditPropertyDefinitionOption options for editing a custom repository property definition
+type EditPropertyDefinitionOption struct {
+	Description *string `json:"description"`
+	// the values the property may have, an empty list allows any value. Values of repositories
+	// which are no longer allowed are removed
+	AllowedValues []string `json:"allowed_values"`
+}
+
+// EditRepoPropertiesOption options for setting the custom properties of a repository
+type EditRepoPropertiesOption struct {
+	// the values of the properties to change, an empty value removes the property
+	Properties map[string]string `json:"properties"`
+}
+
+// RepoPolicy represents the requirements an organization sets for the creation of its repositories
+type RepoPolicy struct {
+	// regular expression the whole name of a new repository must match, any name is allowed if empty
+	NamePattern string `json:"name_pattern"`
+	// explanation of the name pattern shown in the error message
+	NamePatternDescription string `json:"name_pattern_description"`
+	// whether a new repository must have at least one topic
+	RequireTopic bool `json:"require_topic"`
+	// custom properties a new repository must have a value for
+	RequiredProperties []string `json:"required_properties"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SetRepoPolicyOption options for setting the repository policy of an organization
+type SetRepoPolicyOption struct {
+	// regular expression the whole name of a new repository must match, any name is allowed if empty
+	NamePattern            string `json:"name_pattern" binding:"MaxSize(255)"`
+	NamePatternDescription string `json:"name_pattern_description"`
+	RequireTopic           bool   `json:"require_topic"`
+	// custom properties of the organization a new repository must have a value for
+	RequiredProperties []string `json:"required_properties"`
+}
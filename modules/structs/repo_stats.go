@@ -0,0 +1,35 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoStatsRecalculation represents the recalculation of the statistics of all repositories of the instance
+type RepoStatsRecalculation struct {
+	ID int64 `json:"id"`
+	// the recalculated kinds of statistics: size, languages, commits or counters
+	Kinds []string `json:"kinds"`
+	// enum: running,finished,cancelled
+	Status string `json:"status"`
+	// the number of repositories at the start of the recalculation
+	Total     int64 `json:"total"`
+	Queued    int64 `json:"queued"`
+	Processed int64 `json:"processed"`
+	Failed    int64 `json:"failed"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
+
+// RecalculateRepoStatsOption options for recalculating the statistics of all repositories
+type RecalculateRepoStatsOption struct {
+	// the kinds of statistics to recalculate: size, languages, commits or counters, all kinds if empty
+	Kinds []string `json:"kinds"`
+}
@@ -10,8 +10,17 @@ type GitEntry struct {
 	Mode string `json:"mode"`
 	Type string `json:"type"`
 	Size int64  `json:"size"`
-	SHA  string `json:"sha"`
-	URL  string `json:"url"`
+	// the commit of the submodule for submodule entries
+	SHA string `json:"sha"`
+	URL string `json:"url"`
+	// `target` is populated when `type` is `blob` and the entry is a symlink
+	Target *string `json:"target,omitempty"`
+	// `target_in_repo` tells whether the target of a symlink is an existing entry of the repository
+	TargetInRepo *bool `json:"target_in_repo,omitempty"`
+	// `submodule_git_url` is populated when `type` is `commit` and the submodule is configured in .gitmodules
+	SubmoduleGitURL *string `json:"submodule_git_url,omitempty"`
+	// `submodule_url` is the resolved web URL of the submodule, relative URLs are resolved against this instance
+	SubmoduleURL *string `json:"submodule_url,omitempty"`
 }
 
 // GitTreeResponse returns a git tree
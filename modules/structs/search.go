@@ -0,0 +1,33 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// GlobalSearchResults represents the results of a search across repositories, code, issues, users and packages.
+// Only the searched types are included.
+type GlobalSearchResults struct {
+	// total number of results of every searched type
+	Counts       map[string]int64    `json:"counts"`
+	Repositories []*Repository       `json:"repositories"`
+	Code         []*CodeSearchResult `json:"code"`
+	Issues       []*Issue            `json:"issues"`
+	Users        []*User             `json:"users"`
+	Packages     []*Package          `json:"packages"`
+}
+
+// CodeSearchResult represents a file matching a code search
+type CodeSearchResult struct {
+	Repository  *Repository `json:"repository"`
+	Path        string      `json:"path"`
+	CommitID    string      `json:"commit_id"`
+	Language    string      `json:"language"`
+	LineNumbers []int       `json:"line_numbers"`
+	HTMLURL     string      `json:"html_url"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ServiceAccount represents a service account owned by an organization
+type ServiceAccount struct {
+	ID          int64  `json:"id"`
+	UserName    string `json:"login"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	AvatarURL   string `json:"avatar_url"`
+	// a disabled service account can't authenticate with its access tokens
+	Disabled bool `json:"disabled"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateServiceAccountOption options for creating a service account
+type CreateServiceAccountOption struct {
+	// required: true
+	Username    string `json:"username" binding:"Required;AlphaDashDot;MaxSize(40)"`
+	FullName    string `json:"full_name" binding:"MaxSize(100)"`
+	Description string `json:"description" binding:"MaxSize(255)"`
+}
+
+// EditServiceAccountOption options for editing a service account
+type EditServiceAccountOption struct {
+	FullName    *string `json:"full_name" binding:"MaxSize(100)"`
+	Description *string `json:"description" binding:"MaxSize(255)"`
+	Disabled    *bool   `json:"disabled"`
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// UsageReport represents the storage use and the number of repositories, users and organizations of the instance
+type UsageReport struct {
+	// swagger:strfmt date-time
+	Generated     time.Time     `json:"generated_at"`
+	Storage       *StorageUsage `json:"storage"`
+	Repositories  int64         `json:"repositories"`
+	Users         int64         `json:"users"`
+	Organizations int64         `json:"organizations"`
+	// owners with the highest storage use, highest first
+	TopConsumers []*UsageConsumer `json:"top_consumers"`
+	// the reports created in the requested time range, oldest first
+	Growth []*UsageSnapshot `json:"growth"`
+}
+
+// StorageUsage represents the storage use by category in bytes
+type StorageUsage struct {
+	Git         int64 `json:"git"`
+	LFS         int64 `json:"lfs"`
+	Packages    int64 `json:"packages"`
+	Attachments int64 `json:"attachments"`
+	Total       int64 `json:"total"`
+}
+
+// UsageConsumer represents the storage use of an owner in bytes
+type UsageConsumer struct {
+	Owner string `json:"owner"`
+	// size of the repositories including LFS objects
+	Repositories int64 `json:"repositories"`
+	Packages     int64 `json:"packages"`
+	Total        int64 `json:"total"`
+}
+
+// UsageSnapshot represents the usage of the instance at a point in time
+type UsageSnapshot struct {
+	// swagger:strfmt date-time
+	Generated     time.Time     `json:"generated_at"`
+	Storage       *StorageUsage `json:"storage"`
+	Repositories  int64         `json:"repositories"`
+	Users         int64         `json:"users"`
+	Organizations int64         `json:"organizations"`
+}
@@ -48,6 +48,8 @@ type User struct {
 	Description string `json:"description"`
 	// User visibility level option: public, limited, private
 	Visibility string `json:"visibility"`
+	// Is the user a service account, which can only authenticate with access tokens
+	IsServiceAccount bool `json:"is_service_account"`
 
 	// user counts
 	Followers    int `json:"followers_count"`
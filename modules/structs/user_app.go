@@ -16,6 +16,8 @@ type AccessToken struct {
 	Name           string `json:"name"`
 	Token          string `json:"sha1"`
 	TokenLastEight string `json:"token_last_eight"`
+	// scope of the token, empty for full access
+	Scope string `json:"scope"`
 }
 
 // AccessTokenList represents a list of API access token.
@@ -26,6 +28,10 @@ type AccessTokenList []*AccessToken
 // swagger:parameters userCreateToken
 type CreateAccessTokenOption struct {
 	Name string `json:"name" binding:"Required"`
+	// restricts the token to the package registry (package:read or package:write), an empty scope grants full access
+	Scope string `json:"scope"`
+	// restricts a package scoped token to the packages of this user or organization
+	PackageOwner string `json:"package_owner"`
 }
 
 // CreateOAuth2ApplicationOptions holds options to create an oauth2 application
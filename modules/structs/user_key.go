@@ -16,8 +16,14 @@ type PublicKey struct {
 	Title       string `json:"title,omitempty"`
 	Fingerprint string `json:"fingerprint,omitempty"`
 	// swagger:strfmt date-time
-	Created  time.Time `json:"created_at,omitempty"`
-	Owner    *User     `json:"user,omitempty"`
-	ReadOnly bool      `json:"read_only,omitempty"`
-	KeyType  string    `json:"key_type,omitempty"`
+	Created time.Time `json:"created_at,omitempty"`
+	// time the key was last used to access a repository, null if it was never used
+	// swagger:strfmt date-time
+	LastUsed *time.Time `json:"last_used_at"`
+	// time after which the key can't be used anymore, null if it never expires
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
+	Owner     *User      `json:"user,omitempty"`
+	ReadOnly  bool       `json:"read_only,omitempty"`
+	KeyType   string     `json:"key_type,omitempty"`
 }
@@ -163,6 +163,7 @@ func NewFuncMap() []template.FuncMap {
 		"RenderCommitBody":               RenderCommitBody,
 		"RenderIssueTitle":               RenderIssueTitle,
 		"RenderEmoji":                    RenderEmoji,
+		"RenderLabelName":                RenderLabelName,
 		"RenderEmojiPlain":               emoji.ReplaceAliases,
 		"ReactionToEmoji":                ReactionToEmoji,
 		"RenderNote":                     RenderNote,
@@ -384,7 +385,7 @@ func NewFuncMap() []template.FuncMap {
 					continue
 				}
 				html += fmt.Sprintf("<div class='ui label' style='color: %s; background-color: %s'>%s</div> ",
-					label.ForegroundColor(), label.Color, RenderEmoji(label.Name))
+					label.ForegroundColor(), label.Color, RenderLabelName(label))
 			}
 			html += "</span>"
 			return template.HTML(html)
@@ -819,6 +820,15 @@ func RenderEmoji(text string) template.HTML {
 	return template.HTML(renderedText)
 }
 
+// RenderLabelName renders the name of a label, scoped labels get the scope and the value rendered separately
+func RenderLabelName(label *issues_model.Label) template.HTML {
+	if !label.IsScoped() {
+		return RenderEmoji(label.Name)
+	}
+	return template.HTML(fmt.Sprintf(`<span class="scope-name">%s</span><span class="scope-value">%s</span>`,
+		RenderEmoji(label.Scope()), RenderEmoji(label.ScopedName())))
+}
+
 // ReactionToEmoji renders emoji for use in reactions
 func ReactionToEmoji(reaction string) template.HTML {
 	val := emoji.FromCode(reaction)
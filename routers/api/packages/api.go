@@ -10,21 +10,28 @@ import (
 	"regexp"
 	"strings"
 
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/packages/composer"
 	"code.gitea.io/gitea/routers/api/packages/conan"
+	"code.gitea.io/gitea/routers/api/packages/conda"
 	"code.gitea.io/gitea/routers/api/packages/container"
+	"code.gitea.io/gitea/routers/api/packages/cran"
 	"code.gitea.io/gitea/routers/api/packages/generic"
 	"code.gitea.io/gitea/routers/api/packages/helm"
+	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/routers/api/packages/maven"
 	"code.gitea.io/gitea/routers/api/packages/npm"
 	"code.gitea.io/gitea/routers/api/packages/nuget"
 	"code.gitea.io/gitea/routers/api/packages/pub"
 	"code.gitea.io/gitea/routers/api/packages/pypi"
 	"code.gitea.io/gitea/routers/api/packages/rubygems"
+	"code.gitea.io/gitea/routers/api/packages/swift"
 	"code.gitea.io/gitea/routers/api/packages/vagrant"
 	"code.gitea.io/gitea/services/auth"
 	context_service "code.gitea.io/gitea/services/context"
@@ -32,7 +39,8 @@ import (
 
 func reqPackageAccess(accessMode perm.AccessMode) func(ctx *context.Context) {
 	return func(ctx *context.Context) {
-		if ctx.Package.AccessMode < accessMode && !ctx.IsUserSiteAdmin() {
+		scope, _ := ctx.AccessTokenScope()
+		if ctx.Package.AccessMode < accessMode && (!ctx.IsUserSiteAdmin() || scope.IsPackageScope()) {
 			ctx.Resp.Header().Set("WWW-Authenticate", `Basic realm="Gitea Package API"`)
 			ctx.Error(http.StatusUnauthorized, "reqPackageAccess", "user should have specific permission or be a site admin")
 			return
@@ -40,9 +48,168 @@ func reqPackageAccess(accessMode perm.AccessMode) func(ctx *context.Context) {
 	}
 }
 
+var (
+	condaDownloadPattern = regexp.MustCompile(`\A(.+/)?(.+)/((?:[^/]+(?:\.tar\.bz2|\.conda))|(?:current_)?repodata\.json(?:\.bz2)?)\z`)
+	condaUploadPattern   = regexp.MustCompile(`\A(.+/)?([^/]+(?:\.tar\.bz2|\.conda))\z`)
+
+	containerBlobsUploadsPattern = regexp.MustCompile(`\A(.+)/blobs/uploads/([a-zA-Z0-9-_.=]+)\z`)
+	containerBlobsPattern        = regexp.MustCompile(`\A(.+)/blobs/([^/]+)\z`)
+	containerManifestsPattern    = regexp.MustCompile(`\A(.+)/manifests/([^/]+)\z`)
+)
+
+// packageResolvers find the existing package a request to the registry of the package type is addressed to.
+// Requests which don't address a single package or address a package which doesn't exist yet resolve to nil.
+var packageResolvers = map[packages_model.Type]func(ctx *context.Context) (*packages_model.Package, error){
+	packages_model.TypeComposer: packageByName(packages_model.TypeComposer, composerPackageNameFromParams),
+	packages_model.TypeConan: packageByName(packages_model.TypeConan, func(ctx *context.Context) string {
+		return ctx.Params("name")
+	}),
+	packages_model.TypeConda: packageByFilename(packages_model.TypeConda, func(ctx *context.Context) string {
+		if m := condaDownloadPattern.FindStringSubmatch(ctx.Params("*")); len(m) != 0 {
+			return m[3]
+		}
+		return ""
+	}),
+	packages_model.TypeContainer: packageByName(packages_model.TypeContainer, func(ctx *context.Context) string {
+		if image := ctx.Params("image"); image != "" {
+			return image
+		}
+		path := ctx.Params("*")
+		for _, suffix := range []string{"/blobs/uploads", "/tags/list"} {
+			if strings.HasSuffix(path, suffix) {
+				return path[:len(path)-len(suffix)]
+			}
+		}
+		for _, pattern := range []*regexp.Regexp{containerBlobsUploadsPattern, containerBlobsPattern, containerManifestsPattern} {
+			if m := pattern.FindStringSubmatch(path); len(m) == 3 {
+				return m[1]
+			}
+		}
+		return ""
+	}),
+	packages_model.TypeCran: packageByFilename(packages_model.TypeCran, func(ctx *context.Context) string {
+		return ctx.Params("filename")
+	}),
+	packages_model.TypeGeneric: packageByName(packages_model.TypeGeneric, func(ctx *context.Context) string {
+		return ctx.Params("packagename")
+	}),
+	packages_model.TypeHelm: packageByFilename(packages_model.TypeHelm, func(ctx *context.Context) string {
+		return ctx.Params("filename")
+	}),
+	packages_model.TypeMaven: packageByName(packages_model.TypeMaven, maven.PackageNameFromParams),
+	packages_model.TypeNpm:   packageByName(packages_model.TypeNpm, npm.PackageNameFromParams),
+	packages_model.TypeNuGet: packageByName(packages_model.TypeNuGet, func(ctx *context.Context) string {
+		return ctx.Params("id")
+	}),
+	packages_model.TypePub: packageByName(packages_model.TypePub, func(ctx *context.Context) string {
+		return ctx.Params("id")
+	}),
+	packages_model.TypePyPI: packageByName(packages_model.TypePyPI, pypi.PackageNameFromParams),
+	packages_model.TypeRubyGems: packageByFilename(packages_model.TypeRubyGems, func(ctx *context.Context) string {
+		filename := ctx.Params("filename")
+		if strings.HasSuffix(filename, ".gemspec.rz") {
+			return strings.TrimSuffix(filename, ".gemspec.rz") + ".gem"
+		}
+		return filename
+	}),
+	packages_model.TypeSwift: packageByName(packages_model.TypeSwift, swift.PackageNameFromParams),
+	packages_model.TypeVagrant: packageByName(packages_model.TypeVagrant, func(ctx *context.Context) string {
+		return ctx.Params("name")
+	}),
+}
+
+func composerPackageNameFromParams(ctx *context.Context) string {
+	if name := ctx.Params("package"); name != "" {
+		return name
+	}
+	if vendorName := ctx.Params("vendorname"); vendorName != "" {
+		return vendorName + "/" + ctx.Params("projectname")
+	}
+	return ""
+}
+
+func packageByName(packageType packages_model.Type, name func(ctx *context.Context) string) func(ctx *context.Context) (*packages_model.Package, error) {
+	return func(ctx *context.Context) (*packages_model.Package, error) {
+		n := name(ctx)
+		if n == "" {
+			return nil, nil
+		}
+		p, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packageType, n)
+		if err == packages_model.ErrPackageNotExist {
+			return nil, nil
+		}
+		return p, err
+	}
+}
+
+func packageByFilename(packageType packages_model.Type, filename func(ctx *context.Context) string) func(ctx *context.Context) (*packages_model.Package, error) {
+	return func(ctx *context.Context) (*packages_model.Package, error) {
+		n := filename(ctx)
+		if n == "" {
+			return nil, nil
+		}
+		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+			OwnerID:         ctx.Package.Owner.ID,
+			Type:            packageType,
+			HasFileWithName: n,
+			IsInternal:      util.OptionalBoolFalse,
+			Paginator:       db.NewAbsoluteListOptions(0, 1),
+		})
+		if err != nil || len(pvs) == 0 {
+			return nil, err
+		}
+		return packages_model.GetPackageByID(ctx, pvs[0].PackageID)
+	}
+}
+
+// reqPackageReadAccess applies the access settings of the package a request is addressed to
+// and checks if the package can be read
+func reqPackageReadAccess(packageType packages_model.Type) func(ctx *context.Context) {
+	resolve := packageResolvers[packageType]
+	return func(ctx *context.Context) {
+		p, err := resolve(ctx)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "reqPackageReadAccess", err.Error())
+			return
+		}
+		if p != nil {
+			if err := ctx.Package.AssignPackageAccess(ctx, p); err != nil {
+				ctx.Error(http.StatusInternalServerError, "AssignPackageAccess", err.Error())
+				return
+			}
+		}
+
+		reqPackageAccess(perm.AccessModeRead)(ctx)
+	}
+}
+
+// reqAggregatedPackageReadAccess applies the access settings of the package a request to the aggregated registry
+// of an organization is addressed to, which may be a package shared with the organization by a member,
+// and checks if the package can be read
+func reqAggregatedPackageReadAccess(packageType packages_model.Type, name func(ctx *context.Context) string) func(ctx *context.Context) {
+	return func(ctx *context.Context) {
+		if n := name(ctx); n != "" {
+			p, err := packages_model.GetAggregatedPackageByName(ctx, ctx.Package.Owner.ID, packageType, n)
+			if err != nil && err != packages_model.ErrPackageNotExist {
+				ctx.Error(http.StatusInternalServerError, "GetAggregatedPackageByName", err.Error())
+				return
+			}
+			if p != nil {
+				if err := ctx.Package.AssignPackageAccess(ctx, p); err != nil {
+					ctx.Error(http.StatusInternalServerError, "AssignPackageAccess", err.Error())
+					return
+				}
+			}
+		}
+
+		reqPackageAccess(perm.AccessModeRead)(ctx)
+	}
+}
+
 func Routes(ctx gocontext.Context) *web.Route {
 	r := web.NewRoute()
 
+	r.Use(helper.RequestMetrics)
 	r.Use(context.PackageContexter(ctx))
 
 	authMethods := []auth.Method{
@@ -59,8 +226,32 @@ func Routes(ctx gocontext.Context) *web.Route {
 	r.Use(func(ctx *context.Context) {
 		ctx.Doer = authGroup.Verify(ctx.Req, ctx.Resp, ctx, ctx.Session)
 	})
+	r.Use(helper.RateLimit)
 
 	r.Group("/{username}", func() {
+		// read-only registries of an organization which also serve the packages shared with it by its members
+		r.Group("/aggregate", func() {
+			r.Group("/composer", func() {
+				r.Get("/packages.json", composer.ServiceIndex)
+				r.Get("/search.json", composer.SearchPackages)
+				r.Get("/list.json", composer.EnumeratePackages)
+				r.Get("/p2/{vendorname}/{projectname}~dev.json", composer.PackageMetadata)
+				r.Get("/p2/{vendorname}/{projectname}.json", composer.PackageMetadata)
+				r.Get("/files/{package}/{version}/{filename}", composer.DownloadPackageFile)
+			}, reqAggregatedPackageReadAccess(packages_model.TypeComposer, composerPackageNameFromParams))
+			r.Group("/npm", func() {
+				r.Group("/@{scope}/{id}", func() {
+					r.Get("", npm.PackageMetadata)
+					r.Get("/-/{version}/{filename}", npm.DownloadPackageFile)
+				})
+				r.Group("/{id}", func() {
+					r.Get("", npm.PackageMetadata)
+					r.Get("/-/{version}/{filename}", npm.DownloadPackageFile)
+				})
+				r.Get("/-/downloads/@{scope}/{id}", npm.PackageDownloads)
+				r.Get("/-/downloads/{id}", npm.PackageDownloads)
+			}, reqAggregatedPackageReadAccess(packages_model.TypeNpm, npm.PackageNameFromParams))
+		}, helper.AggregatedRegistry)
 		r.Group("/composer", func() {
 			r.Get("/packages.json", composer.ServiceIndex)
 			r.Get("/search.json", composer.SearchPackages)
@@ -69,7 +260,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 			r.Get("/p2/{vendorname}/{projectname}.json", composer.PackageMetadata)
 			r.Get("/files/{package}/{version}/{filename}", composer.DownloadPackageFile)
 			r.Put("", reqPackageAccess(perm.AccessModeWrite), composer.UploadPackage)
-		})
+		}, reqPackageReadAccess(packages_model.TypeComposer))
 		r.Group("/conan", func() {
 			r.Group("/v1", func() {
 				r.Get("/ping", conan.Ping)
@@ -157,7 +348,58 @@ func Routes(ctx gocontext.Context) *web.Route {
 					}, conan.ExtractPathParameters)
 				})
 			})
-		})
+		}, reqPackageReadAccess(packages_model.TypeConan))
+		r.Group("/conda", func() {
+			// Manual mapping of routes because the channel can contain slashes which chi does not support
+			r.Get("/*", func(ctx *context.Context) {
+				m := condaDownloadPattern.FindStringSubmatch(ctx.Params("*"))
+				if len(m) == 0 {
+					ctx.Status(http.StatusNotFound)
+					return
+				}
+
+				ctx.SetParams("channel", strings.TrimSuffix(m[1], "/"))
+				ctx.SetParams("architecture", m[2])
+				ctx.SetParams("filename", m[3])
+
+				switch m[3] {
+				case "repodata.json", "repodata.json.bz2", "current_repodata.json", "current_repodata.json.bz2":
+					conda.EnumeratePackages(ctx)
+				default:
+					conda.DownloadPackageFile(ctx)
+				}
+			})
+			r.Put("/*", reqPackageAccess(perm.AccessModeWrite), func(ctx *context.Context) {
+				m := condaUploadPattern.FindStringSubmatch(ctx.Params("*"))
+				if len(m) == 0 {
+					ctx.Status(http.StatusNotFound)
+					return
+				}
+
+				ctx.SetParams("channel", strings.TrimSuffix(m[1], "/"))
+				ctx.SetParams("filename", m[2])
+
+				conda.UploadPackageFile(ctx)
+			})
+		}, reqPackageReadAccess(packages_model.TypeConda))
+		r.Group("/cran", func() {
+			r.Group("/src", func() {
+				r.Group("/contrib", func() {
+					r.Get("/PACKAGES", cran.EnumerateSourcePackages)
+					r.Get("/PACKAGES{format}", cran.EnumerateSourcePackages)
+					r.Get("/{filename}", cran.DownloadSourcePackageFile)
+				})
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), cran.UploadSourcePackageFile)
+			})
+			r.Group("/bin", func() {
+				r.Group("/{platform}/contrib/{rversion}", func() {
+					r.Get("/PACKAGES", cran.EnumerateBinaryPackages)
+					r.Get("/PACKAGES{format}", cran.EnumerateBinaryPackages)
+					r.Get("/{filename}", cran.DownloadBinaryPackageFile)
+				})
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), cran.UploadBinaryPackageFile)
+			})
+		}, reqPackageReadAccess(packages_model.TypeCran))
 		r.Group("/generic", func() {
 			r.Group("/{packagename}/{packageversion}", func() {
 				r.Delete("", reqPackageAccess(perm.AccessModeWrite), generic.DeletePackage)
@@ -169,16 +411,16 @@ func Routes(ctx gocontext.Context) *web.Route {
 					}, reqPackageAccess(perm.AccessModeWrite))
 				})
 			})
-		})
+		}, reqPackageReadAccess(packages_model.TypeGeneric))
 		r.Group("/helm", func() {
 			r.Get("/index.yaml", helm.Index)
 			r.Get("/{filename}", helm.DownloadPackageFile)
 			r.Post("/api/charts", reqPackageAccess(perm.AccessModeWrite), helm.UploadPackage)
-		})
+		}, reqPackageReadAccess(packages_model.TypeHelm))
 		r.Group("/maven", func() {
 			r.Put("/*", reqPackageAccess(perm.AccessModeWrite), maven.UploadPackageFile)
 			r.Get("/*", maven.DownloadPackageFile)
-		})
+		}, reqPackageReadAccess(packages_model.TypeMaven))
 		r.Group("/nuget", func() {
 			r.Get("/index.json", nuget.ServiceIndex)
 			r.Get("/query", nuget.SearchService)
@@ -196,7 +438,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 				r.Delete("/{id}/{version}", nuget.DeletePackage)
 			}, reqPackageAccess(perm.AccessModeWrite))
 			r.Get("/symbols/{filename}/{guid:[0-9a-f]{32}}FFFFFFFF/{filename2}", nuget.DownloadSymbolFile)
-		})
+		}, reqPackageReadAccess(packages_model.TypeNuGet))
 		r.Group("/npm", func() {
 			r.Group("/@{scope}/{id}", func() {
 				r.Get("", npm.PackageMetadata)
@@ -236,7 +478,9 @@ func Routes(ctx gocontext.Context) *web.Route {
 					r.Delete("", npm.DeletePackageTag)
 				}, reqPackageAccess(perm.AccessModeWrite))
 			})
-		})
+			r.Get("/-/downloads/@{scope}/{id}", npm.PackageDownloads)
+			r.Get("/-/downloads/{id}", npm.PackageDownloads)
+		}, reqPackageReadAccess(packages_model.TypeNpm))
 		r.Group("/pub", func() {
 			r.Group("/api/packages", func() {
 				r.Group("/versions/new", func() {
@@ -250,12 +494,12 @@ func Routes(ctx gocontext.Context) *web.Route {
 					r.Get("/{version}", pub.PackageVersionMetadata)
 				})
 			})
-		})
+		}, reqPackageReadAccess(packages_model.TypePub))
 		r.Group("/pypi", func() {
 			r.Post("/", reqPackageAccess(perm.AccessModeWrite), pypi.UploadPackageFile)
 			r.Get("/files/{id}/{version}/{filename}", pypi.DownloadPackageFile)
 			r.Get("/simple/{id}", pypi.PackageMetadata)
-		})
+		}, reqPackageReadAccess(packages_model.TypePyPI))
 		r.Group("/rubygems", func() {
 			r.Get("/specs.4.8.gz", rubygems.EnumeratePackages)
 			r.Get("/latest_specs.4.8.gz", rubygems.EnumeratePackagesLatest)
@@ -266,7 +510,36 @@ func Routes(ctx gocontext.Context) *web.Route {
 				r.Post("/", rubygems.UploadPackageFile)
 				r.Delete("/yank", rubygems.DeletePackage)
 			}, reqPackageAccess(perm.AccessModeWrite))
-		})
+		}, reqPackageReadAccess(packages_model.TypeRubyGems))
+		r.Group("/swift", func() {
+			r.Group("/{scope}/{name}", func() {
+				r.Get("", swift.CheckAcceptMediaType(swift.AcceptJSON), swift.EnumeratePackageVersions)
+				r.Group("/{version}", func() {
+					r.Get("/Package.swift", swift.CheckAcceptMediaType(swift.AcceptSwift), swift.DownloadManifest)
+					r.Put("", reqPackageAccess(perm.AccessModeWrite), swift.CheckAcceptMediaType(swift.AcceptJSON), swift.UploadPackageFile)
+					r.Get("", func(ctx *context.Context) {
+						// The version parameter can have a .zip or .json suffix which chi can't route separately
+						version := ctx.Params("version")
+						if strings.HasSuffix(version, ".zip") {
+							swift.CheckAcceptMediaType(swift.AcceptZip)(ctx)
+							if ctx.Written() {
+								return
+							}
+							ctx.SetParams("version", strings.TrimSuffix(version, ".zip"))
+							swift.DownloadPackageFile(ctx)
+						} else {
+							swift.CheckAcceptMediaType(swift.AcceptJSON)(ctx)
+							if ctx.Written() {
+								return
+							}
+							ctx.SetParams("version", strings.TrimSuffix(version, ".json"))
+							swift.PackageVersionMetadata(ctx)
+						}
+					})
+				})
+			})
+			r.Get("/identifiers", swift.CheckAcceptMediaType(swift.AcceptJSON), swift.LookupPackageIdentifiers)
+		}, reqPackageReadAccess(packages_model.TypeSwift))
 		r.Group("/vagrant", func() {
 			r.Group("/authenticate", func() {
 				r.Get("", vagrant.CheckAuthenticate)
@@ -279,8 +552,8 @@ func Routes(ctx gocontext.Context) *web.Route {
 					r.Put("", reqPackageAccess(perm.AccessModeWrite), vagrant.UploadPackageFile)
 				})
 			})
-		})
-	}, context_service.UserAssignmentWeb(), context.PackageAssignment(), reqPackageAccess(perm.AccessModeRead))
+		}, reqPackageReadAccess(packages_model.TypeVagrant))
+	}, context_service.UserAssignmentWeb(), context.PackageAssignment())
 
 	return r
 }
@@ -288,6 +561,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 func ContainerRoutes(ctx gocontext.Context) *web.Route {
 	r := web.NewRoute()
 
+	r.Use(helper.ContainerRequestMetrics)
 	r.Use(context.PackageContexter(ctx))
 
 	authMethods := []auth.Method{
@@ -302,6 +576,7 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 	r.Use(func(ctx *context.Context) {
 		ctx.Doer = authGroup.Verify(ctx.Req, ctx.Resp, ctx, ctx.Session)
 	})
+	r.Use(helper.RateLimit)
 
 	r.Get("", container.ReqContainerAccess, container.DetermineSupport)
 	r.Get("/token", container.Authenticate)
@@ -329,12 +604,6 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 			r.Get("/tags/list", container.GetTagList)
 		}, container.VerifyImageName)
 
-		var (
-			blobsUploadsPattern = regexp.MustCompile(`\A(.+)/blobs/uploads/([a-zA-Z0-9-_.=]+)\z`)
-			blobsPattern        = regexp.MustCompile(`\A(.+)/blobs/([^/]+)\z`)
-			manifestsPattern    = regexp.MustCompile(`\A(.+)/manifests/([^/]+)\z`)
-		)
-
 		// Manual mapping of routes because {image} can contain slashes which chi does not support
 		r.Route("/*", "HEAD,GET,POST,PUT,PATCH,DELETE", func(ctx *context.Context) {
 			path := ctx.Params("*")
@@ -371,7 +640,7 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 				return
 			}
 
-			m := blobsUploadsPattern.FindStringSubmatch(path)
+			m := containerBlobsUploadsPattern.FindStringSubmatch(path)
 			if len(m) == 3 && (isPut || isPatch) {
 				reqPackageAccess(perm.AccessModeWrite)(ctx)
 				if ctx.Written() {
@@ -393,7 +662,7 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 				}
 				return
 			}
-			m = blobsPattern.FindStringSubmatch(path)
+			m = containerBlobsPattern.FindStringSubmatch(path)
 			if len(m) == 3 && (isHead || isGet || isDelete) {
 				ctx.SetParams("image", m[1])
 				container.VerifyImageName(ctx)
@@ -416,7 +685,7 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 				}
 				return
 			}
-			m = manifestsPattern.FindStringSubmatch(path)
+			m = containerManifestsPattern.FindStringSubmatch(path)
 			if len(m) == 3 && (isHead || isGet || isPut || isDelete) {
 				ctx.SetParams("image", m[1])
 				container.VerifyImageName(ctx)
@@ -446,7 +715,7 @@ func ContainerRoutes(ctx gocontext.Context) *web.Route {
 
 			ctx.Status(http.StatusNotFound)
 		})
-	}, container.ReqContainerAccess, context_service.UserAssignmentWeb(), context.PackageAssignment(), reqPackageAccess(perm.AccessModeRead))
+	}, container.ReqContainerAccess, context_service.UserAssignmentWeb(), context.PackageAssignment(), reqPackageReadAccess(packages_model.TypeContainer))
 
 	return r
 }
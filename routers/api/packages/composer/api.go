@@ -36,21 +36,67 @@ type SearchResultResponse struct {
 }
 
 // SearchResult contains a search result
+// Only the name is always present, the other fields depend on the requested fields.
 type SearchResult struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Downloads   int64  `json:"downloads"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description,omitempty"`
+	URL         string      `json:"url,omitempty"`
+	Repository  *string     `json:"repository,omitempty"`
+	Downloads   *int64      `json:"downloads,omitempty"`
+	Abandoned   interface{} `json:"abandoned,omitempty"` // true or the name of the replacement package
 }
 
-func createSearchResultResponse(total int64, pds []*packages_model.PackageDescriptor, nextLink string) *SearchResultResponse {
+// Names of the optional search result fields
+const (
+	SearchFieldDescription = "description"
+	SearchFieldURL         = "url"
+	SearchFieldRepository  = "repository"
+	SearchFieldDownloads   = "downloads"
+	SearchFieldAbandoned   = "abandoned"
+)
+
+// SearchFields contains all optional search result fields
+var SearchFields = []string{
+	SearchFieldDescription,
+	SearchFieldURL,
+	SearchFieldRepository,
+	SearchFieldDownloads,
+	SearchFieldAbandoned,
+}
+
+func createSearchResultResponse(total int64, pds []*packages_model.PackageDescriptor, fields map[string]bool, nextLink string) *SearchResultResponse {
 	results := make([]*SearchResult, 0, len(pds))
 
 	for _, pd := range pds {
-		results = append(results, &SearchResult{
-			Name:        pd.Package.Name,
-			Description: pd.Metadata.(*composer_module.Metadata).Description,
-			Downloads:   pd.Version.DownloadCount,
-		})
+		metadata := pd.Metadata.(*composer_module.Metadata)
+
+		result := &SearchResult{
+			Name: pd.Package.Name,
+		}
+		if fields[SearchFieldDescription] {
+			result.Description = &metadata.Description
+		}
+		if fields[SearchFieldURL] {
+			result.URL = pd.FullWebLink()
+		}
+		if fields[SearchFieldRepository] {
+			repository := ""
+			if pd.Repository != nil {
+				repository = pd.Repository.HTMLURL()
+			}
+			result.Repository = &repository
+		}
+		if fields[SearchFieldDownloads] {
+			result.Downloads = &pd.Version.DownloadCount
+		}
+		if fields[SearchFieldAbandoned] && metadata.Abandoned != nil && metadata.Abandoned.IsAbandoned {
+			if metadata.Abandoned.Replacement != "" {
+				result.Abandoned = metadata.Abandoned.Replacement
+			} else {
+				result.Abandoned = true
+			}
+		}
+		results = append(results, result)
 	}
 
 	return &SearchResultResponse{
@@ -83,6 +129,23 @@ type Dist struct {
 	Checksum string `json:"shasum"`
 }
 
+// DownloadsExtraKey is the key of the extra field which contains the download count of a version
+const DownloadsExtraKey = "gitea"
+
+// withDownloadCount returns a copy of the metadata with the download count added to the extra fields.
+// Composer keeps unknown extra fields, so tools can read them from the installed package too.
+func withDownloadCount(metadata *composer_module.Metadata, downloads int64) *composer_module.Metadata {
+	m := *metadata
+	m.Extra = make(map[string]interface{}, len(metadata.Extra)+1)
+	for k, v := range metadata.Extra {
+		m.Extra[k] = v
+	}
+	m.Extra[DownloadsExtraKey] = map[string]interface{}{
+		"downloads": downloads,
+	}
+	return &m
+}
+
 func createPackageMetadataResponse(registryURL string, pds []*packages_model.PackageDescriptor) *PackageMetadataResponse {
 	versions := make([]*PackageVersionMetadata, 0, len(pds))
 
@@ -100,7 +163,7 @@ func createPackageMetadataResponse(registryURL string, pds []*packages_model.Pac
 			Version:  pd.Version.Version,
 			Type:     packageType,
 			Created:  pd.Version.CreatedUnix.AsLocalTime(),
-			Metadata: pd.Metadata.(*composer_module.Metadata),
+			Metadata: withDownloadCount(pd.Metadata.(*composer_module.Metadata), pd.Version.DownloadCount),
 			Dist: Dist{
 				Type:     "zip",
 				URL:      fmt.Sprintf("%s/files/%s/%s/%s", registryURL, url.PathEscape(pd.Package.LowerName), url.PathEscape(pd.Version.LowerVersion), url.PathEscape(pd.Files[0].File.LowerName)),
@@ -18,10 +18,10 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	composer_module "code.gitea.io/gitea/modules/packages/composer"
-	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	packages_service "code.gitea.io/gitea/services/packages"
+	composer_service "code.gitea.io/gitea/services/packages/composer"
 
 	"github.com/hashicorp/go-version"
 )
@@ -44,14 +44,59 @@ func apiError(ctx *context.Context, status int, obj interface{}) {
 
 // ServiceIndex displays registry endpoints
 func ServiceIndex(ctx *context.Context) {
-	resp := createServiceIndexResponse(setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/composer")
+	resp := createServiceIndexResponse(helper.RegistryURL(ctx, packages_model.TypeComposer))
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
-// SearchPackages searches packages, only "q" is supported
+// searchOrderBy maps the supported "order_by" values to package search sort options
+var searchOrderBy = map[string]string{
+	"downloads": "mostdownloads",
+	"updated":   "newest",
+	"name":      "alphabetically",
+}
+
+// parseSearchFields parses the requested search result fields from "fields" (comma separated) or "fields[]".
+// All fields are selected if none are requested.
+func parseSearchFields(ctx *context.Context) (map[string]bool, []string, error) {
+	requested := ctx.FormStrings("fields[]")
+	for _, field := range strings.Split(ctx.FormTrim("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			requested = append(requested, field)
+		}
+	}
+
+	selected := requested
+	if len(selected) == 0 {
+		selected = SearchFields
+	}
+
+	fields := make(map[string]bool, len(selected))
+	for _, field := range selected {
+		if !util.IsStringInSlice(field, SearchFields) {
+			return nil, nil, fmt.Errorf("unknown field: %s", field)
+		}
+		fields[field] = true
+	}
+	return fields, requested, nil
+}
+
+// SearchPackages searches packages, "q", "type", "order_by" and "fields" are supported
 // https://packagist.org/apidoc#search-packages
 func SearchPackages(ctx *context.Context) {
+	orderBy := ctx.FormTrim("order_by")
+	sort, ok := searchOrderBy[orderBy]
+	if orderBy != "" && !ok {
+		apiError(ctx, http.StatusBadRequest, fmt.Errorf("unknown order_by: %s", orderBy))
+		return
+	}
+
+	fields, requestedFields, err := parseSearchFields(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
 	page := ctx.FormInt("page")
 	if page < 1 {
 		page = 1
@@ -63,11 +108,17 @@ func SearchPackages(ctx *context.Context) {
 	}
 
 	opts := &packages_model.PackageSearchOptions{
-		OwnerID:    ctx.Package.Owner.ID,
-		Type:       packages_model.TypeComposer,
-		Name:       packages_model.SearchValue{Value: ctx.FormTrim("q")},
-		IsInternal: util.OptionalBoolFalse,
-		Paginator:  &paginator,
+		Type:        packages_model.TypeComposer,
+		Name:        packages_model.SearchValue{Value: ctx.FormTrim("q")},
+		IsInternal:  util.OptionalBoolFalse,
+		HidePrivate: !ctx.Package.CanSeePrivatePackages(),
+		Sort:        sort,
+		Paginator:   &paginator,
+	}
+	if helper.IsAggregatedRegistry(ctx) {
+		opts.AggregateOrgID = ctx.Package.Owner.ID
+	} else {
+		opts.OwnerID = ctx.Package.Owner.ID
 	}
 	if ctx.FormTrim("type") != "" {
 		opts.Properties = map[string]string{
@@ -83,7 +134,7 @@ func SearchPackages(ctx *context.Context) {
 
 	nextLink := ""
 	if len(pvs) == paginator.PageSize {
-		u, err := url.Parse(setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/composer/search.json")
+		u, err := url.Parse(helper.RegistryURL(ctx, packages_model.TypeComposer) + "/search.json")
 		if err != nil {
 			apiError(ctx, http.StatusInternalServerError, err)
 			return
@@ -95,6 +146,12 @@ func SearchPackages(ctx *context.Context) {
 		if perPage != 0 {
 			q.Set("per_page", strconv.Itoa(perPage))
 		}
+		if orderBy != "" {
+			q.Set("order_by", orderBy)
+		}
+		if len(requestedFields) != 0 {
+			q.Set("fields", strings.Join(requestedFields, ","))
+		}
 		u.RawQuery = q.Encode()
 
 		nextLink = u.String()
@@ -106,7 +163,7 @@ func SearchPackages(ctx *context.Context) {
 		return
 	}
 
-	resp := createSearchResultResponse(total, pds, nextLink)
+	resp := createSearchResultResponse(total, pds, fields, nextLink)
 
 	ctx.JSON(http.StatusOK, resp)
 }
@@ -114,14 +171,29 @@ func SearchPackages(ctx *context.Context) {
 // EnumeratePackages lists all package names
 // https://packagist.org/apidoc#list-packages
 func EnumeratePackages(ctx *context.Context) {
-	ps, err := packages_model.GetPackagesByType(db.DefaultContext, ctx.Package.Owner.ID, packages_model.TypeComposer)
+	var ps []*packages_model.Package
+	var err error
+	if helper.IsAggregatedRegistry(ctx) {
+		ps, err = packages_model.GetAggregatedPackagesByType(ctx, ctx.Package.Owner.ID, packages_model.TypeComposer)
+	} else {
+		ps, err = packages_model.GetPackagesByType(db.DefaultContext, ctx.Package.Owner.ID, packages_model.TypeComposer)
+	}
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	names := make([]string, 0, len(ps))
+	seen := make(map[string]bool, len(ps))
 	for _, p := range ps {
+		if p.Visibility == packages_model.VisibilityPrivate && !ctx.Package.CanSeePrivatePackages() {
+			continue
+		}
+		// packages of different owners in an aggregated registry may have the same name
+		if seen[p.LowerName] {
+			continue
+		}
+		seen[p.LowerName] = true
 		names = append(names, p.Name)
 	}
 
@@ -136,7 +208,17 @@ func PackageMetadata(ctx *context.Context) {
 	vendorName := ctx.Params("vendorname")
 	projectName := ctx.Params("projectname")
 
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeComposer, vendorName+"/"+projectName)
+	owner, err := helper.ResolvePackageOwner(ctx, packages_model.TypeComposer, vendorName+"/"+projectName)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, owner.ID, packages_model.TypeComposer, vendorName+"/"+projectName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -153,7 +235,7 @@ func PackageMetadata(ctx *context.Context) {
 	}
 
 	resp := createPackageMetadataResponse(
-		setting.AppURL+"api/packages/"+ctx.Package.Owner.Name+"/composer",
+		helper.RegistryURL(ctx, packages_model.TypeComposer),
 		pds,
 	)
 
@@ -162,10 +244,20 @@ func PackageMetadata(ctx *context.Context) {
 
 // DownloadPackageFile serves the content of a package
 func DownloadPackageFile(ctx *context.Context) {
-	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+	owner, err := helper.ResolvePackageOwner(ctx, packages_model.TypeComposer, ctx.Params("package"))
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
 		ctx,
 		&packages_service.PackageInfo{
-			Owner:       ctx.Package.Owner,
+			Owner:       owner,
 			PackageType: packages_model.TypeComposer,
 			Name:        ctx.Params("package"),
 			Version:     ctx.Params("version"),
@@ -182,9 +274,7 @@ func DownloadPackageFile(ctx *context.Context) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	defer s.Close()
-
-	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
+	helper.ServePackageFile(ctx, s, u, pf)
 }
 
 // UploadPackage creates a new package
@@ -202,6 +292,16 @@ func UploadPackage(ctx *context.Context) {
 		return
 	}
 
+	canPublish, err := composer_service.CanPublish(ctx, ctx.Package.Owner, ctx.Doer, cp.Name)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !canPublish {
+		apiError(ctx, http.StatusForbidden, fmt.Errorf("the vendor %s is reserved for other teams", composer_service.Vendor(cp.Name)))
+		return
+	}
+
 	if _, err := buf.Seek(0, io.SeekStart); err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -217,6 +317,7 @@ func UploadPackage(ctx *context.Context) {
 	}
 
 	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
 		&packages_service.PackageCreationInfo{
 			PackageInfo: packages_service.PackageInfo{
 				Owner:       ctx.Package.Owner,
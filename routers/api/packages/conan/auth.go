@@ -21,21 +21,23 @@ func (a *Auth) Name() string {
 
 // Verify extracts the user from the Bearer token
 func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataStore, sess auth.SessionStore) *user_model.User {
-	uid, err := packages.ParseAuthorizationToken(req)
+	claims, err := packages.ParseAuthorizationToken(req)
 	if err != nil {
 		log.Trace("ParseAuthorizationToken: %v", err)
 		return nil
 	}
 
-	if uid == 0 {
+	if claims.UserID == 0 {
 		return nil
 	}
 
-	u, err := user_model.GetUserByID(uid)
+	u, err := user_model.GetUserByID(claims.UserID)
 	if err != nil {
 		log.Error("GetUserByID:  %v", err)
 		return nil
 	}
 
+	auth.StoreAccessTokenScope(store, claims.Scope, claims.OwnerID)
+
 	return u
 }
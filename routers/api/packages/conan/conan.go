@@ -118,7 +118,8 @@ func Authenticate(ctx *context.Context) {
 		return
 	}
 
-	token, err := packages_service.CreateAuthorizationToken(ctx.Doer)
+	scope, ownerID := ctx.AccessTokenScope()
+	token, err := packages_service.CreateAuthorizationToken(ctx.Doer, scope, ownerID)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -414,6 +415,7 @@ func uploadFile(ctx *context.Context, fileFilter stringSet, fileKey string) {
 	}
 
 	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		ctx,
 		pci,
 		pfci,
 	)
@@ -422,6 +424,10 @@ func uploadFile(ctx *context.Context, fileFilter stringSet, fileKey string) {
 			apiError(ctx, http.StatusBadRequest, err)
 			return
 		}
+		if err == packages_service.ErrVersionImmutable {
+			apiError(ctx, http.StatusConflict, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -452,7 +458,7 @@ func downloadFile(ctx *context.Context, fileFilter stringSet, fileKey string) {
 		return
 	}
 
-	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
 		ctx,
 		&packages_service.PackageInfo{
 			Owner:       ctx.Package.Owner,
@@ -473,9 +479,7 @@ func downloadFile(ctx *context.Context, fileFilter stringSet, fileKey string) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	defer s.Close()
-
-	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
+	helper.ServePackageFile(ctx, s, u, pf)
 }
 
 // DeleteRecipeV1 deletes the requested recipe(s)
@@ -673,6 +677,12 @@ func deleteRecipeOrPackage(apictx *context.Context, rref *conan_module.RecipeRef
 		return err
 	}
 
+	detail := rref.String()
+	if pref != nil {
+		detail += ":" + pref.Reference
+	}
+	packages_service.RecordVersionAudit(apictx, apictx.Doer, packages_model.AuditActionDelete, pd, "", detail)
+
 	if versionDeleted {
 		notification.NotifyPackageDelete(apictx.Doer, pd)
 	}
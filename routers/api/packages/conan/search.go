@@ -25,6 +25,7 @@ func SearchRecipes(ctx *context.Context) {
 	q := ctx.FormTrim("q")
 
 	opts := parseQuery(ctx.Package.Owner, q)
+	opts.HidePrivate = !ctx.Package.CanSeePrivatePackages()
 
 	results, err := conan_model.SearchRecipes(ctx, opts)
 	if err != nil {
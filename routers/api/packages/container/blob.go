@@ -117,6 +117,11 @@ func saveAsPackageBlob(hsr packages_module.HashedSizeReader, pi *packages_servic
 }
 
 func deleteBlob(ownerID int64, image, digest string) error {
+	immutable, err := packages_service.IsVersionImmutable(ownerID)
+	if err != nil {
+		return err
+	}
+
 	return db.WithTx(func(ctx context.Context) error {
 		pfds, err := container_model.GetContainerBlobs(ctx, &container_model.BlobSearchOptions{
 			OwnerID: ownerID,
@@ -128,6 +133,17 @@ func deleteBlob(ownerID int64, image, digest string) error {
 		}
 
 		for _, file := range pfds {
+			// Only blobs which are not referenced by a published manifest can be deleted if the versions are immutable
+			if immutable {
+				pv, err := packages_model.GetVersionByID(ctx, file.File.VersionID)
+				if err != nil {
+					return err
+				}
+				if !pv.IsInternal {
+					return packages_service.ErrVersionImmutable
+				}
+			}
+
 			if err := packages_service.DeletePackageFile(ctx, file.File); err != nil {
 				return err
 			}
@@ -23,7 +23,6 @@ import (
 	packages_module "code.gitea.io/gitea/modules/packages"
 	container_module "code.gitea.io/gitea/modules/packages/container"
 	"code.gitea.io/gitea/modules/packages/container/oci"
-	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	packages_service "code.gitea.io/gitea/services/packages"
 	container_service "code.gitea.io/gitea/services/packages/container"
@@ -112,7 +111,7 @@ func apiErrorDefined(ctx *context.Context, err *namedError) {
 // ReqContainerAccess is a middleware which checks the current user valid (real user or ghost for anonymous access)
 func ReqContainerAccess(ctx *context.Context) {
 	if ctx.Doer == nil {
-		ctx.Resp.Header().Add("WWW-Authenticate", `Bearer realm="`+setting.AppURL+`v2/token",service="container_registry",scope="*"`)
+		ctx.Resp.Header().Add("WWW-Authenticate", `Bearer realm="`+tokenRealm(ctx)+`",service="container_registry",scope="*"`)
 		apiErrorDefined(ctx, errUnauthorized)
 	}
 }
@@ -140,7 +139,8 @@ func Authenticate(ctx *context.Context) {
 		u = user_model.NewGhostUser()
 	}
 
-	token, err := packages_service.CreateAuthorizationToken(u)
+	scope, ownerID := ctx.AccessTokenScope()
+	token, err := packages_service.CreateAuthorizationToken(u, scope, ownerID)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -199,7 +199,7 @@ func InitiateUploadBlob(ctx *context.Context) {
 		})
 		if blob != nil {
 			setResponseHeaders(ctx.Resp, &containerHeaders{
-				Location:      fmt.Sprintf("/v2/%s/%s/blobs/%s", ctx.Package.Owner.LowerName, image, mount),
+				Location:      fmt.Sprintf("%s/blobs/%s", imagePath(ctx, image), mount),
 				ContentDigest: mount,
 				Status:        http.StatusCreated,
 			})
@@ -227,7 +227,7 @@ func InitiateUploadBlob(ctx *context.Context) {
 		}
 
 		setResponseHeaders(ctx.Resp, &containerHeaders{
-			Location:      fmt.Sprintf("/v2/%s/%s/blobs/%s", ctx.Package.Owner.LowerName, image, digest),
+			Location:      fmt.Sprintf("%s/blobs/%s", imagePath(ctx, image), digest),
 			ContentDigest: digest,
 			Status:        http.StatusCreated,
 		})
@@ -241,7 +241,7 @@ func InitiateUploadBlob(ctx *context.Context) {
 	}
 
 	setResponseHeaders(ctx.Resp, &containerHeaders{
-		Location:   fmt.Sprintf("/v2/%s/%s/blobs/uploads/%s", ctx.Package.Owner.LowerName, image, upload.ID),
+		Location:   fmt.Sprintf("%s/blobs/uploads/%s", imagePath(ctx, image), upload.ID),
 		Range:      "0-0",
 		UploadUUID: upload.ID,
 		Status:     http.StatusAccepted,
@@ -286,7 +286,7 @@ func UploadBlob(ctx *context.Context) {
 	}
 
 	setResponseHeaders(ctx.Resp, &containerHeaders{
-		Location:   fmt.Sprintf("/v2/%s/%s/blobs/uploads/%s", ctx.Package.Owner.LowerName, image, uploader.ID),
+		Location:   fmt.Sprintf("%s/blobs/uploads/%s", imagePath(ctx, image), uploader.ID),
 		Range:      fmt.Sprintf("0-%d", uploader.Size()-1),
 		UploadUUID: uploader.ID,
 		Status:     http.StatusAccepted,
@@ -348,7 +348,7 @@ func EndUploadBlob(ctx *context.Context) {
 	}
 
 	setResponseHeaders(ctx.Resp, &containerHeaders{
-		Location:      fmt.Sprintf("/v2/%s/%s/blobs/%s", ctx.Package.Owner.LowerName, image, digest),
+		Location:      fmt.Sprintf("%s/blobs/%s", imagePath(ctx, image), digest),
 		ContentDigest: digest,
 		Status:        http.StatusCreated,
 	})
@@ -399,17 +399,51 @@ func GetBlob(ctx *context.Context) {
 		return
 	}
 
-	s, _, err := packages_service.GetPackageFileStream(ctx, blob.File)
+	serveBlob(ctx, blob)
+}
+
+// serveBlob writes the content of the blob or redirects to it if the storage serves it directly
+func serveBlob(ctx *context.Context, pfd *packages_model.PackageFileDescriptor) {
+	s, u, _, err := packages_service.GetPackageFileStream(ctx, pfd.File)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	helper.RecordDownloadAudit(ctx, pfd.File)
+
+	if u != nil {
+		setResponseHeaders(ctx.Resp, &containerHeaders{
+			ContentDigest: pfd.Properties.GetByName(container_module.PropertyDigest),
+			ContentType:   pfd.Properties.GetByName(container_module.PropertyMediaType),
+			Location:      u.String(),
+			Status:        http.StatusTemporaryRedirect,
+		})
+		return
+	}
 	defer s.Close()
 
+	streamBlob(ctx, pfd, s)
+}
+
+// serveManifest writes the content of the manifest. Clients need the content type and the digest
+// of the manifest, so it is never redirected to the storage.
+func serveManifest(ctx *context.Context, pfd *packages_model.PackageFileDescriptor) {
+	s, _, err := packages_service.OpenPackageFileStream(ctx, pfd.File)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.Close()
+	helper.RecordDownloadAudit(ctx, pfd.File)
+
+	streamBlob(ctx, pfd, s)
+}
+
+func streamBlob(ctx *context.Context, pfd *packages_model.PackageFileDescriptor, s io.Reader) {
 	setResponseHeaders(ctx.Resp, &containerHeaders{
-		ContentDigest: blob.Properties.GetByName(container_module.PropertyDigest),
-		ContentType:   blob.Properties.GetByName(container_module.PropertyMediaType),
-		ContentLength: blob.Blob.Size,
+		ContentDigest: pfd.Properties.GetByName(container_module.PropertyDigest),
+		ContentType:   pfd.Properties.GetByName(container_module.PropertyMediaType),
+		ContentLength: pfd.Blob.Size,
 		Status:        http.StatusOK,
 	})
 	if _, err := io.Copy(ctx.Resp, s); err != nil {
@@ -427,6 +461,10 @@ func DeleteBlob(ctx *context.Context) {
 	}
 
 	if err := deleteBlob(ctx.Package.Owner.ID, ctx.Params("image"), digest); err != nil {
+		if err == packages_service.ErrVersionImmutable {
+			apiErrorDefined(ctx, errDenied.WithMessage("The blob is referenced by an immutable manifest").WithStatusCode(http.StatusConflict))
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -480,8 +518,17 @@ func UploadManifest(ctx *context.Context) {
 		return
 	}
 
+	packages_service.RecordAudit(ctx, ctx.Doer, &packages_model.PackageAuditLog{
+		OwnerID:     ctx.Package.Owner.ID,
+		Action:      packages_model.AuditActionPublish,
+		PackageType: packages_model.TypeContainer,
+		PackageName: mci.Image,
+		Version:     reference,
+		Detail:      digest,
+	})
+
 	setResponseHeaders(ctx.Resp, &containerHeaders{
-		Location:      fmt.Sprintf("/v2/%s/%s/manifests/%s", ctx.Package.Owner.LowerName, mci.Image, reference),
+		Location:      fmt.Sprintf("%s/manifests/%s", imagePath(ctx, mci.Image), reference),
 		ContentDigest: digest,
 		Status:        http.StatusCreated,
 	})
@@ -538,22 +585,7 @@ func GetManifest(ctx *context.Context) {
 		return
 	}
 
-	s, _, err := packages_service.GetPackageFileStream(ctx, manifest.File)
-	if err != nil {
-		apiError(ctx, http.StatusInternalServerError, err)
-		return
-	}
-	defer s.Close()
-
-	setResponseHeaders(ctx.Resp, &containerHeaders{
-		ContentDigest: manifest.Properties.GetByName(container_module.PropertyDigest),
-		ContentType:   manifest.Properties.GetByName(container_module.PropertyMediaType),
-		ContentLength: manifest.Blob.Size,
-		Status:        http.StatusOK,
-	})
-	if _, err := io.Copy(ctx.Resp, s); err != nil {
-		log.Error("Error whilst copying content to response: %v", err)
-	}
+	serveManifest(ctx, manifest)
 }
 
 // https://github.com/opencontainers/distribution-spec/blob/main/spec.md#deleting-tags
@@ -587,7 +619,11 @@ func DeleteManifest(ctx *context.Context) {
 	}
 
 	for _, pv := range pvs {
-		if err := packages_service.RemovePackageVersion(ctx.Doer, pv); err != nil {
+		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
+			if err == packages_service.ErrVersionImmutable {
+				apiErrorDefined(ctx, errDenied.WithMessage("The manifest is immutable and can't be deleted").WithStatusCode(http.StatusConflict))
+				return
+			}
 			apiError(ctx, http.StatusInternalServerError, err)
 			return
 		}
@@ -0,0 +1,101 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	gocontext "context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var domainOwnerKey interface{} = "containerDomainOwner"
+
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// DomainRouting serves the container registry of an owner on the custom domain mapped to it.
+// The image paths of requests to the domain are prefixed with the owner, all paths outside of the registry are not found.
+func DomainRouting(next http.Handler) http.Handler {
+	appURL, _ := url.Parse(setting.AppURL)
+	appHostname := hostname(appURL.Host)
+
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		host := hostname(req.Host)
+		if host == "" || host == appHostname {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		ownerID, err := packages_model.GetContainerDomainOwnerID(req.Context(), host)
+		if err != nil {
+			log.Error("GetContainerDomainOwnerID: %v", err)
+			http.Error(resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if ownerID == 0 {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		owner, err := user_model.GetUserByIDCtx(req.Context(), ownerID)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				// the cached mapping may outlive a deleted owner
+				next.ServeHTTP(resp, req)
+				return
+			}
+			log.Error("GetUserByIDCtx: %v", err)
+			http.Error(resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		path := req.URL.Path
+		switch {
+		case path == "/v2" || path == "/v2/" || path == "/v2/token" || path == "/v2/_catalog":
+		case strings.HasPrefix(path, "/v2/"):
+			req.URL.Path = "/v2/" + owner.LowerName + path[3:]
+			req.URL.RawPath = ""
+		default:
+			http.NotFound(resp, req)
+			return
+		}
+
+		next.ServeHTTP(resp, req.WithContext(gocontext.WithValue(req.Context(), domainOwnerKey, owner)))
+	})
+}
+
+// isDomainRequest checks if the request was made to the custom domain of the owner of the packages
+func isDomainRequest(ctx *context.Context) bool {
+	owner, ok := ctx.Req.Context().Value(domainOwnerKey).(*user_model.User)
+	return ok && (ctx.Package == nil || ctx.Package.Owner.ID == owner.ID)
+}
+
+// imagePath returns the path of an image of the registry as seen by the client
+func imagePath(ctx *context.Context, image string) string {
+	if isDomainRequest(ctx) {
+		return "/v2/" + image
+	}
+	return "/v2/" + ctx.Package.Owner.LowerName + "/" + image
+}
+
+// tokenRealm returns the URL of the token endpoint on the host used by the client
+func tokenRealm(ctx *context.Context) string {
+	if isDomainRequest(ctx) {
+		appURL, _ := url.Parse(setting.AppURL)
+		return appURL.Scheme + "://" + ctx.Req.Host + "/v2/token"
+	}
+	return setting.AppURL + "v2/token"
+}
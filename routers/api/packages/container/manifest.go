@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
@@ -31,6 +32,9 @@ type manifestCreationInfo struct {
 	Reference  string
 	IsTagged   bool
 	Properties map[string]string
+	// IsImmutable is set if an existing tag must not point to a different manifest
+	IsImmutable bool
+	Digest      string
 }
 
 func processManifest(mci *manifestCreationInfo, buf *packages_module.HashedBuffer) (string, error) {
@@ -47,6 +51,15 @@ func processManifest(mci *manifestCreationInfo, buf *packages_module.HashedBuffe
 		return "", err
 	}
 
+	if mci.IsTagged {
+		immutable, err := packages_service.IsVersionImmutable(mci.Owner.ID)
+		if err != nil {
+			return "", err
+		}
+		mci.IsImmutable = immutable
+		mci.Digest = digestFromHashSummer(buf)
+	}
+
 	if !mci.MediaType.IsValid() {
 		mci.MediaType = schema.MediaType
 		if !mci.MediaType.IsValid() {
@@ -308,6 +321,12 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 	var pv *packages_model.PackageVersion
 	if pv, err = packages_model.GetOrInsertVersion(ctx, _pv); err != nil {
 		if err == packages_model.ErrDuplicatePackageVersion {
+			if mci.IsImmutable {
+				if err := checkManifestUnchanged(ctx, pv, mci.Digest); err != nil {
+					return nil, err
+				}
+			}
+
 			if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
 				return nil, err
 			}
@@ -341,6 +360,27 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 	return pv, nil
 }
 
+// checkManifestUnchanged returns an error if the existing version references a different manifest
+func checkManifestUnchanged(ctx context.Context, pv *packages_model.PackageVersion, digest string) error {
+	pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, container_model.ManifestFilename, packages_model.EmptyFileKey)
+	if err != nil {
+		if err == packages_model.ErrPackageFileNotExist {
+			return nil
+		}
+		return err
+	}
+
+	pb, err := packages_model.GetBlobByID(ctx, pf.BlobID)
+	if err != nil {
+		return err
+	}
+
+	if digestFromPackageBlob(pb) != digest {
+		return errDenied.WithMessage("The tag is immutable and can't be changed").WithStatusCode(http.StatusConflict)
+	}
+	return nil
+}
+
 type blobReference struct {
 	Digest       oci.Digest
 	MediaType    oci.MediaType
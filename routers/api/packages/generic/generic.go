@@ -31,7 +31,7 @@ func apiError(ctx *context.Context, status int, obj interface{}) {
 
 // DownloadPackageFile serves the specific generic package.
 func DownloadPackageFile(ctx *context.Context) {
-	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
 		ctx,
 		&packages_service.PackageInfo{
 			Owner:       ctx.Package.Owner,
@@ -51,9 +51,7 @@ func DownloadPackageFile(ctx *context.Context) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	defer s.Close()
-
-	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
+	helper.ServePackageFile(ctx, s, u, pf)
 }
 
 // UploadPackage uploads the specific generic package.
@@ -73,24 +71,48 @@ func UploadPackage(ctx *context.Context) {
 		return
 	}
 
-	upload, close, err := ctx.UploadStream()
-	if err != nil {
-		apiError(ctx, http.StatusInternalServerError, err)
-		return
-	}
-	if close {
-		defer upload.Close()
+	pfci := &packages_service.PackageFileCreationInfo{
+		PackageFileInfo: packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		IsLead: true,
 	}
 
-	buf, err := packages_module.CreateHashedBufferFromReader(upload, 32*1024*1024)
-	if err != nil {
-		log.Error("Error creating hashed buffer: %v", err)
-		apiError(ctx, http.StatusInternalServerError, err)
-		return
+	// clients can reference content already stored for the owner instead of uploading it again
+	if hash := ctx.FormTrim("sha256"); hash != "" {
+		pb, err := packages_model.GetBlobBySHA256ForOwner(ctx, ctx.Package.Owner.ID, hash)
+		if err != nil {
+			if err == packages_model.ErrPackageBlobNotExist {
+				apiError(ctx, http.StatusNotFound, err)
+				return
+			}
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		pfci.Blob = pb
+	} else {
+		upload, close, err := ctx.UploadStream()
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if close {
+			defer upload.Close()
+		}
+
+		buf, err := packages_module.CreateHashedBufferFromReader(upload, 32*1024*1024)
+		if err != nil {
+			log.Error("Error creating hashed buffer: %v", err)
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		defer buf.Close()
+
+		pfci.Data = buf
 	}
-	defer buf.Close()
 
-	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+	_, _, err := packages_service.CreatePackageOrAddFileToExisting(
+		ctx,
 		&packages_service.PackageCreationInfo{
 			PackageInfo: packages_service.PackageInfo{
 				Owner:       ctx.Package.Owner,
@@ -100,13 +122,7 @@ func UploadPackage(ctx *context.Context) {
 			},
 			Creator: ctx.Doer,
 		},
-		&packages_service.PackageFileCreationInfo{
-			PackageFileInfo: packages_service.PackageFileInfo{
-				Filename: filename,
-			},
-			Data:   buf,
-			IsLead: true,
-		},
+		pfci,
 	)
 	if err != nil {
 		if err == packages_model.ErrDuplicatePackageFile {
@@ -123,6 +139,7 @@ func UploadPackage(ctx *context.Context) {
 // DeletePackage deletes the specific generic package.
 func DeletePackage(ctx *context.Context) {
 	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
 		ctx.Doer,
 		&packages_service.PackageInfo{
 			Owner:       ctx.Package.Owner,
@@ -136,6 +153,10 @@ func DeletePackage(ctx *context.Context) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if err == packages_service.ErrVersionImmutable {
+			apiError(ctx, http.StatusConflict, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -167,6 +188,16 @@ func DeletePackageFile(ctx *context.Context) {
 		return
 	}
 
+	immutable, err := packages_service.IsVersionImmutable(ctx.Package.Owner.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if immutable {
+		apiError(ctx, http.StatusConflict, packages_service.ErrVersionImmutable)
+		return
+	}
+
 	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
@@ -174,7 +205,7 @@ func DeletePackageFile(ctx *context.Context) {
 	}
 
 	if len(pfs) == 1 {
-		if err := packages_service.RemovePackageVersion(ctx.Doer, pv); err != nil {
+		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
 			apiError(ctx, http.StatusInternalServerError, err)
 			return
 		}
@@ -183,6 +214,15 @@ func DeletePackageFile(ctx *context.Context) {
 			apiError(ctx, http.StatusInternalServerError, err)
 			return
 		}
+
+		packages_service.RecordAudit(ctx, ctx.Doer, &packages_model.PackageAuditLog{
+			OwnerID:     ctx.Package.Owner.ID,
+			Action:      packages_model.AuditActionDelete,
+			PackageType: packages_model.TypeGeneric,
+			PackageName: ctx.Params("packagename"),
+			Version:     pv.Version,
+			Filename:    pf.Name,
+		})
 	}
 
 	ctx.Status(http.StatusNoContent)
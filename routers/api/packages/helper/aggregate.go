@@ -0,0 +1,60 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package helper
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const aggregatedRegistryKey = "IsAggregatedRegistry"
+
+// AggregatedRegistry marks a request as addressed to the aggregated registry of an organization.
+// The aggregated registry serves the packages owned by the organization and the packages shared with it by its members.
+func AggregatedRegistry(ctx *context.Context) {
+	if !ctx.Package.Owner.IsOrganization() {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.Data[aggregatedRegistryKey] = true
+}
+
+// IsAggregatedRegistry returns true if the request is addressed to the aggregated registry of an organization
+func IsAggregatedRegistry(ctx *context.Context) bool {
+	aggregated, _ := ctx.Data[aggregatedRegistryKey].(bool)
+	return aggregated
+}
+
+// RegistryURL returns the url of the registry of the package type the request is addressed to
+func RegistryURL(ctx *context.Context, packageType packages_model.Type) string {
+	if IsAggregatedRegistry(ctx) {
+		return setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/aggregate/" + string(packageType)
+	}
+	return setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/" + string(packageType)
+}
+
+// ResolvePackageOwner returns the owner of the package the request is addressed to.
+// Requests to an aggregated registry may address packages of members of the organization.
+func ResolvePackageOwner(ctx *context.Context, packageType packages_model.Type, name string) (*user_model.User, error) {
+	if !IsAggregatedRegistry(ctx) {
+		return ctx.Package.Owner, nil
+	}
+
+	p, err := packages_model.GetAggregatedPackageByName(ctx, ctx.Package.Owner.ID, packageType, name)
+	if err != nil {
+		return nil, err
+	}
+	if p.Visibility == packages_model.VisibilityPrivate && !ctx.Package.CanSeePrivatePackages() {
+		return nil, packages_model.ErrPackageNotExist
+	}
+	if p.OwnerID == ctx.Package.Owner.ID {
+		return ctx.Package.Owner, nil
+	}
+	return user_model.GetUserByIDCtx(ctx, p.OwnerID)
+}
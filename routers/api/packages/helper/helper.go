@@ -6,11 +6,15 @@ package helper
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 
+	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
 )
 
 // LogAndProcessError logs an error and calls a custom callback with the processed error message.
@@ -36,3 +40,23 @@ func LogAndProcessError(ctx *context.Context, status int, obj interface{}, cb fu
 		cb(message)
 	}
 }
+
+// RecordDownloadAudit adds the download of the package file to the audit log if the request was authenticated with an access token
+func RecordDownloadAudit(ctx *context.Context, pf *packages_model.PackageFile) {
+	if ctx.Doer != nil && ctx.IsAccessTokenAuthenticated() {
+		packages_service.RecordDownloadAudit(ctx, ctx.Doer, pf)
+	}
+}
+
+// ServePackageFile serves the content of the package file or redirects to its presigned url if the storage serves it directly
+func ServePackageFile(ctx *context.Context, s io.ReadSeekCloser, u *url.URL, pf *packages_model.PackageFile) {
+	RecordDownloadAudit(ctx, pf)
+
+	if u != nil {
+		ctx.Redirect(u.String(), http.StatusFound)
+		return
+	}
+	defer s.Close()
+
+	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package helper
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/metrics"
+)
+
+// RequestMetrics observes the duration of the package registry requests.
+// The package type is read from the request path /api/packages/{owner}/{type}/...
+func RequestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, req)
+		metrics.PackageRequestDuration.WithLabelValues(packageTypeFromPath(req.URL.Path)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ContainerRequestMetrics observes the duration of the container registry requests
+func ContainerRequestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, req)
+		metrics.PackageRequestDuration.WithLabelValues(string(packages_model.TypeContainer)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// packageTypeFromPath returns the package type of a request path or "other" to keep the label cardinality bounded
+func packageTypeFromPath(p string) string {
+	idx := strings.Index(p, "/api/packages/")
+	if idx == -1 {
+		return "other"
+	}
+	parts := strings.SplitN(p[idx+len("/api/packages/"):], "/", 4)
+	if len(parts) < 2 {
+		return "other"
+	}
+	typ := parts[1]
+	if typ == "aggregate" && len(parts) > 2 {
+		typ = parts[2]
+	}
+	for _, t := range packages_model.TypeList {
+		if string(t) == typ {
+			return typ
+		}
+	}
+	return "other"
+}
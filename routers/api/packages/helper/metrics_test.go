@@ -0,0 +1,26 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageTypeFromPath(t *testing.T) {
+	cases := map[string]string{
+		"/api/packages/user/npm/package":                     "npm",
+		"/api/packages/user/pypi/simple/package":             "pypi",
+		"/gitea/api/packages/user/maven/com/example/lib/1.0": "maven",
+		"/api/packages/org/aggregate/composer/packages.json": "composer",
+		"/api/packages/user/unknown/package":                 "other",
+		"/api/packages/user":                                 "other",
+		"/api/v1/packages/user":                              "other",
+	}
+	for p, expected := range cases {
+		assert.Equal(t, expected, packageTypeFromPath(p), p)
+	}
+}
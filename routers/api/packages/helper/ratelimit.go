@@ -0,0 +1,65 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package helper
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
+	"code.gitea.io/gitea/modules/ratelimit"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	rateLimitersOnce         sync.Once
+	anonymousRateLimiter     *ratelimit.RequestLimiter
+	authenticatedRateLimiter *ratelimit.RequestLimiter
+)
+
+func initRateLimiters() {
+	anonymousRateLimiter = ratelimit.NewRequestLimiter(setting.Packages.RateLimitAnonymous, setting.Packages.RateLimitBurst)
+	authenticatedRateLimiter = ratelimit.NewRequestLimiter(setting.Packages.RateLimitAuthenticated, setting.Packages.RateLimitBurst)
+}
+
+// RateLimit rejects requests of clients which exceed the configured request rate.
+// Requests authenticated with an access token are counted per token, other authenticated requests per user and anonymous requests per IP.
+// It must be used after the authentication middleware.
+func RateLimit(ctx *context.Context) {
+	rateLimitersOnce.Do(initRateLimiters)
+
+	limiter := authenticatedRateLimiter
+	label := "authenticated"
+	var key string
+	if tokenID, ok := ctx.Data["ApiTokenID"].(int64); ok {
+		key = "token:" + strconv.FormatInt(tokenID, 10)
+	} else if ctx.Doer != nil {
+		key = "user:" + strconv.FormatInt(ctx.Doer.ID, 10)
+	} else {
+		limiter = anonymousRateLimiter
+		label = "anonymous"
+		host, _, err := net.SplitHostPort(ctx.RemoteAddr())
+		if err != nil {
+			host = ctx.RemoteAddr()
+		}
+		key = "ip:" + host
+	}
+
+	allowed, retryAfter := limiter.Allow(key)
+	if allowed {
+		return
+	}
+
+	log.Debug("Package registry request of %s rejected by the rate limit", key)
+	metrics.PackageRequestsThrottled.WithLabelValues(label).Inc()
+
+	ctx.Resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	ctx.PlainText(http.StatusTooManyRequests, "rate limit exceeded, retry later")
+}
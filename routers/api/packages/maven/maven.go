@@ -176,6 +176,8 @@ func servePackageFile(ctx *context.Context, params parameters) {
 			log.Error("Error incrementing download counter: %v", err)
 		}
 	}
+	packages_service.RecordDownload(ctx, pf)
+	helper.RecordDownloadAudit(ctx, pf)
 
 	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
 }
@@ -298,6 +300,10 @@ func UploadPackageFile(ctx *context.Context) {
 					apiError(ctx, http.StatusInternalServerError, err)
 					return
 				}
+				if err := packages_model.UpdateDependencies(ctx, pvci.PackageType, pv); err != nil {
+					apiError(ctx, http.StatusInternalServerError, err)
+					return
+				}
 			}
 		}
 
@@ -308,6 +314,7 @@ func UploadPackageFile(ctx *context.Context) {
 	}
 
 	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		ctx,
 		pvci,
 		pfci,
 	)
@@ -316,6 +323,10 @@ func UploadPackageFile(ctx *context.Context) {
 			apiError(ctx, http.StatusBadRequest, err)
 			return
 		}
+		if err == packages_service.ErrVersionImmutable {
+			apiError(ctx, http.StatusConflict, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -335,6 +346,16 @@ type parameters struct {
 	IsMeta     bool
 }
 
+// PackageNameFromParams returns the name of the package addressed by the request path
+// or an empty string if the path is invalid
+func PackageNameFromParams(ctx *context.Context) string {
+	params, err := extractPathParameters(ctx)
+	if err != nil {
+		return ""
+	}
+	return params.GroupID + "-" + params.ArtifactID
+}
+
 func extractPathParameters(ctx *context.Context) (parameters, error) {
 	parts := strings.Split(ctx.Params("*"), "/")
 
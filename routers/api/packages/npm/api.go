@@ -64,6 +64,7 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 		License:      metadata.License,
 		Dependencies: metadata.Dependencies,
 		Readme:       metadata.Readme,
+		Downloads:    pd.Version.DownloadCount,
 		Dist: npm_module.PackageDistribution{
 			Shasum:    pd.Files[0].Blob.HashSHA1,
 			Integrity: "sha512-" + base64.StdEncoding.EncodeToString(hashBytes),
@@ -71,3 +72,22 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 		},
 	}
 }
+
+// PackageDownloadsResponse contains the download counts of a package
+type PackageDownloadsResponse struct {
+	Package   string           `json:"package"`
+	Downloads int64            `json:"downloads"`
+	Versions  map[string]int64 `json:"versions"`
+}
+
+func createPackageDownloadsResponse(packageName string, pvs []*packages_model.PackageVersion) *PackageDownloadsResponse {
+	resp := &PackageDownloadsResponse{
+		Package:  packageName,
+		Versions: make(map[string]int64, len(pvs)),
+	}
+	for _, pv := range pvs {
+		resp.Downloads += pv.DownloadCount
+		resp.Versions[pv.Version] = pv.DownloadCount
+	}
+	return resp
+}
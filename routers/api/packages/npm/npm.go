@@ -6,28 +6,20 @@ package npm
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
-	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/context"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
-	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	packages_service "code.gitea.io/gitea/services/packages"
-
-	"github.com/hashicorp/go-version"
+	npm_service "code.gitea.io/gitea/services/packages/npm"
 )
 
-// errInvalidTagName indicates an invalid tag name
-var errInvalidTagName = errors.New("The tag name is invalid")
-
 func apiError(ctx *context.Context, status int, obj interface{}) {
 	helper.LogAndProcessError(ctx, status, obj, func(message string) {
 		ctx.JSON(status, map[string]string{
@@ -36,9 +28,9 @@ func apiError(ctx *context.Context, status int, obj interface{}) {
 	})
 }
 
-// packageNameFromParams gets the package name from the url parameters
+// PackageNameFromParams gets the package name from the url parameters
 // Variations: /name/, /@scope/name/, /@scope%2Fname/
-func packageNameFromParams(ctx *context.Context) string {
+func PackageNameFromParams(ctx *context.Context) string {
 	scope := ctx.Params("scope")
 	id := ctx.Params("id")
 	if scope != "" {
@@ -49,9 +41,19 @@ func packageNameFromParams(ctx *context.Context) string {
 
 // PackageMetadata returns the metadata for a single package
 func PackageMetadata(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
+	owner, err := helper.ResolvePackageOwner(ctx, packages_model.TypeNpm, packageName)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -68,23 +70,60 @@ func PackageMetadata(ctx *context.Context) {
 	}
 
 	resp := createPackageMetadataResponse(
-		setting.AppURL+"api/packages/"+ctx.Package.Owner.Name+"/npm",
+		helper.RegistryURL(ctx, packages_model.TypeNpm),
 		pds,
 	)
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
+// PackageDownloads returns the total and the per-version download counts of a package
+func PackageDownloads(ctx *context.Context) {
+	packageName := PackageNameFromParams(ctx)
+
+	owner, err := helper.ResolvePackageOwner(ctx, packages_model.TypeNpm, packageName)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, owner.ID, packages_model.TypeNpm, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createPackageDownloadsResponse(packageName, pvs))
+}
+
 // DownloadPackageFile serves the content of a package
 func DownloadPackageFile(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 	packageVersion := ctx.Params("version")
 	filename := ctx.Params("filename")
 
-	s, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+	owner, err := helper.ResolvePackageOwner(ctx, packages_model.TypeNpm, packageName)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
 		ctx,
 		&packages_service.PackageInfo{
-			Owner:       ctx.Package.Owner,
+			Owner:       owner,
 			PackageType: packages_model.TypeNpm,
 			Name:        packageName,
 			Version:     packageVersion,
@@ -101,9 +140,7 @@ func DownloadPackageFile(ctx *context.Context) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	defer s.Close()
-
-	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
+	helper.ServePackageFile(ctx, s, u, pf)
 }
 
 // UploadPackage creates a new package
@@ -122,6 +159,7 @@ func UploadPackage(ctx *context.Context) {
 	defer buf.Close()
 
 	pv, _, err := packages_service.CreatePackageAndAddFile(
+		ctx,
 		&packages_service.PackageCreationInfo{
 			PackageInfo: packages_service.PackageInfo{
 				Owner:       ctx.Package.Owner,
@@ -151,8 +189,8 @@ func UploadPackage(ctx *context.Context) {
 	}
 
 	for _, tag := range npmPackage.DistTags {
-		if err := setPackageTag(tag, pv, false); err != nil {
-			if err == errInvalidTagName {
+		if err := npm_service.SetPackageTag(tag, pv, false); err != nil {
+			if err == npm_service.ErrInvalidTagName {
 				apiError(ctx, http.StatusBadRequest, err)
 				return
 			}
@@ -172,10 +210,11 @@ func DeletePreview(ctx *context.Context) {
 
 // DeletePackageVersion deletes the package version
 func DeletePackageVersion(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 	packageVersion := ctx.Params("version")
 
 	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
 		ctx.Doer,
 		&packages_service.PackageInfo{
 			Owner:       ctx.Package.Owner,
@@ -189,6 +228,10 @@ func DeletePackageVersion(ctx *context.Context) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if err == packages_service.ErrVersionImmutable {
+			apiError(ctx, http.StatusConflict, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -198,7 +241,7 @@ func DeletePackageVersion(ctx *context.Context) {
 
 // DeletePackage deletes the package and all versions
 func DeletePackage(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
@@ -212,7 +255,11 @@ func DeletePackage(ctx *context.Context) {
 	}
 
 	for _, pv := range pvs {
-		if err := packages_service.RemovePackageVersion(ctx.Doer, pv); err != nil {
+		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
+			if err == packages_service.ErrVersionImmutable {
+				apiError(ctx, http.StatusConflict, err)
+				return
+			}
 			apiError(ctx, http.StatusInternalServerError, err)
 			return
 		}
@@ -223,7 +270,7 @@ func DeletePackage(ctx *context.Context) {
 
 // ListPackageTags returns all tags for a package
 func ListPackageTags(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
@@ -248,7 +295,7 @@ func ListPackageTags(ctx *context.Context) {
 
 // AddPackageTag adds a tag to the package
 func AddPackageTag(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 
 	body, err := io.ReadAll(ctx.Req.Body)
 	if err != nil {
@@ -267,8 +314,8 @@ func AddPackageTag(ctx *context.Context) {
 		return
 	}
 
-	if err := setPackageTag(ctx.Params("tag"), pv, false); err != nil {
-		if err == errInvalidTagName {
+	if err := npm_service.SetPackageTag(ctx.Params("tag"), pv, false); err != nil {
+		if err == npm_service.ErrInvalidTagName {
 			apiError(ctx, http.StatusBadRequest, err)
 			return
 		}
@@ -279,7 +326,7 @@ func AddPackageTag(ctx *context.Context) {
 
 // DeletePackageTag deletes a package tag
 func DeletePackageTag(ctx *context.Context) {
-	packageName := packageNameFromParams(ctx)
+	packageName := PackageNameFromParams(ctx)
 
 	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
@@ -288,8 +335,8 @@ func DeletePackageTag(ctx *context.Context) {
 	}
 
 	if len(pvs) != 0 {
-		if err := setPackageTag(ctx.Params("tag"), pvs[0], true); err != nil {
-			if err == errInvalidTagName {
+		if err := npm_service.SetPackageTag(ctx.Params("tag"), pvs[0], true); err != nil {
+			if err == npm_service.ErrInvalidTagName {
 				apiError(ctx, http.StatusBadRequest, err)
 				return
 			}
@@ -298,55 +345,3 @@ func DeletePackageTag(ctx *context.Context) {
 		}
 	}
 }
-
-func setPackageTag(tag string, pv *packages_model.PackageVersion, deleteOnly bool) error {
-	if tag == "" {
-		return errInvalidTagName
-	}
-	_, err := version.NewVersion(tag)
-	if err == nil {
-		return errInvalidTagName
-	}
-
-	ctx, committer, err := db.TxContext()
-	if err != nil {
-		return err
-	}
-	defer committer.Close()
-
-	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-		PackageID: pv.PackageID,
-		Properties: map[string]string{
-			npm_module.TagProperty: tag,
-		},
-		IsInternal: util.OptionalBoolFalse,
-	})
-	if err != nil {
-		return err
-	}
-
-	if len(pvs) == 1 {
-		pvps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pvs[0].ID, npm_module.TagProperty)
-		if err != nil {
-			return err
-		}
-
-		for _, pvp := range pvps {
-			if pvp.Value == tag {
-				if err := packages_model.DeletePropertyByID(ctx, pvp.ID); err != nil {
-					return err
-				}
-				break
-			}
-		}
-	}
-
-	if !deleteOnly {
-		_, err = packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, npm_module.TagProperty, tag)
-		if err != nil {
-			return err
-		}
-	}
-
-	return committer.Commit()
-}
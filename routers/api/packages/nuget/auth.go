@@ -41,5 +41,8 @@ func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataS
 		log.Error("UpdateAccessToken:  %v", err)
 	}
 
+	auth.StoreAccessTokenScope(store, token.Scope, token.OwnerID)
+	auth.StoreAccessTokenID(store, token.ID)
+
 	return u
 }
@@ -5,11 +5,14 @@
 package activitypub
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
 	"code.gitea.io/gitea/modules/activitypub"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
@@ -100,6 +103,24 @@ func PersonInbox(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "429":
+	//     "$ref": "#/responses/error"
+
+	var activity struct {
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(io.LimitReader(ctx.Req.Body, setting.Federation.MaxSize)).Decode(&activity); err != nil && err != io.EOF {
+		ctx.Error(http.StatusBadRequest, "Decode", err)
+		return
+	}
+	if !setting.IsFederationActivityTypeAllowed(activity.Type) {
+		ctx.Error(http.StatusForbidden, "", fmt.Sprintf("activities of type %s are not accepted", activity.Type))
+		return
+	}
 
 	ctx.Status(http.StatusNoContent)
 }
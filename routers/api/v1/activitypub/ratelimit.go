@@ -0,0 +1,47 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package activitypub
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	inboxRateLimiterOnce sync.Once
+	inboxRateLimiter     *ratelimit.RequestLimiter
+)
+
+// RateLimitInbox rejects inbox deliveries of remote servers (per IP) which exceed the configured request rate
+func RateLimitInbox() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		inboxRateLimiterOnce.Do(func() {
+			inboxRateLimiter = ratelimit.NewRequestLimiter(setting.Federation.InboxRateLimit, setting.Federation.InboxRateLimitBurst)
+		})
+
+		host, _, err := net.SplitHostPort(ctx.RemoteAddr())
+		if err != nil {
+			host = ctx.RemoteAddr()
+		}
+
+		allowed, retryAfter := inboxRateLimiter.Allow(host)
+		if allowed {
+			return
+		}
+
+		log.Debug("Federation inbox request of %s rejected by the rate limit", host)
+
+		ctx.Resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		ctx.Error(http.StatusTooManyRequests, "RateLimitInbox", "rate limit exceeded, retry later")
+	}
+}
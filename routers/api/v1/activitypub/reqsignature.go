@@ -13,9 +13,11 @@ import (
 	"net/http"
 	"net/url"
 
+	federation_model "code.gitea.io/gitea/models/federation"
 	"code.gitea.io/gitea/modules/activitypub"
 	gitea_context "code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
 	ap "github.com/go-ap/activitypub"
@@ -75,7 +77,16 @@ func verifyHTTPSignatures(ctx *gitea_context.APIContext) (authenticated bool, er
 	if err != nil {
 		return
 	}
-	// 2. Fetch the public key of the other actor
+	// 2. Reject blocked instances before contacting them
+	allowed, err := federation_model.IsInstanceAllowed(ctx, idIRI.Host)
+	if err != nil {
+		return
+	}
+	if !allowed {
+		err = federation_model.ErrInstanceNotAllowed{Host: idIRI.Host}
+		return
+	}
+	// 3. Fetch the public key of the other actor
 	b, err := fetch(idIRI)
 	if err != nil {
 		return
@@ -84,7 +95,7 @@ func verifyHTTPSignatures(ctx *gitea_context.APIContext) (authenticated bool, er
 	if err != nil {
 		return
 	}
-	// 3. Verify the other actor's key
+	// 4. Verify the other actor's key
 	algo := httpsig.Algorithm(setting.Federation.Algorithms[0])
 	authenticated = v.Verify(pubKey, algo) == nil
 	return authenticated, err
@@ -94,6 +105,11 @@ func verifyHTTPSignatures(ctx *gitea_context.APIContext) (authenticated bool, er
 func ReqHTTPSignature() func(ctx *gitea_context.APIContext) {
 	return func(ctx *gitea_context.APIContext) {
 		if authenticated, err := verifyHTTPSignatures(ctx); err != nil {
+			if federation_model.IsErrInstanceNotAllowed(err) {
+				log.Debug("Rejected federation request: %v", err)
+				ctx.Error(http.StatusForbidden, "reqSignature", err.Error())
+				return
+			}
 			ctx.ServerError("verifyHttpSignatures", err)
 		} else if !authenticated {
 			ctx.Error(http.StatusForbidden, "reqSignature", "request signature verification failed")
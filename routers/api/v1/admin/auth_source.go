@@ -0,0 +1,156 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	auth_service "code.gitea.io/gitea/services/auth"
+)
+
+func getAuthSource(ctx *context.APIContext) *auth.Source {
+	source, err := auth.GetSourceByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if auth.IsErrSourceNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSourceByID", err)
+		}
+		return nil
+	}
+	return source
+}
+
+// ListAuthSources api for listing all authentication sources
+func ListAuthSources(ctx *context.APIContext) {
+	// swagger:operation GET /admin/auth_sources admin adminListAuthSources
+	// ---
+	// summary: List all authentication sources and their health
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuthSourceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	sources, err := auth.Sources()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Sources", err)
+		return
+	}
+	healths, err := auth.GetSourceHealthMap(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSourceHealthMap", err)
+		return
+	}
+
+	count := len(sources)
+	listOpts := utils.GetListOptions(ctx)
+	sources = util.PaginateSlice(sources, listOpts.Page, listOpts.PageSize).([]*auth.Source)
+
+	apiSources := make([]*api.AuthSource, 0, len(sources))
+	for _, source := range sources {
+		apiSources = append(apiSources, convert.ToAuthSource(source, healths[source.ID]))
+	}
+
+	ctx.SetTotalCountHeader(int64(count))
+	ctx.JSON(http.StatusOK, apiSources)
+}
+
+// GetAuthSource api for getting an authentication source
+func GetAuthSource(ctx *context.APIContext) {
+	// swagger:operation GET /admin/auth_sources/{id} admin adminGetAuthSource
+	// ---
+	// summary: Get an authentication source and its health
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the authentication source
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuthSource"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	source := getAuthSource(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	health, err := auth.GetSourceHealth(ctx, source.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSourceHealth", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAuthSource(source, health))
+}
+
+// CheckAuthSourceHealth api for checking the health of an authentication source
+func CheckAuthSourceHealth(ctx *context.APIContext) {
+	// swagger:operation POST /admin/auth_sources/{id}/check_health admin adminCheckAuthSourceHealth
+	// ---
+	// summary: Check if the server of an authentication source is reachable
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the authentication source
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuthSource"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	source := getAuthSource(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !source.IsHealthCheckable() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("authentication source %s does not support health checks", source.Name))
+		return
+	}
+
+	health, err := auth_service.CheckSourceHealth(ctx, source)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CheckSourceHealth", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAuthSource(source, health))
+}
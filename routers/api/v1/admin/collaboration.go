@@ -0,0 +1,46 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListAllCollaborations list the access of collaborators across all repositories
+func ListAllCollaborations(ctx *context.APIContext) {
+	// swagger:operation GET /admin/collaborations admin adminListCollaborations
+	// ---
+	// summary: List the access of collaborators across all repositories, the first to expire first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: expires_before
+	//   in: query
+	//   description: only list collaborations which expire before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: only_expiring
+	//   in: query
+	//   description: only list collaborations which expire
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CollaborationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListCollaborations(ctx, 0)
+}
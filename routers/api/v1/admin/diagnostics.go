@@ -0,0 +1,229 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/diagnostics"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+const defaultDiagnosticsLimit = 100
+
+// parseDiagnosticsOptions parses the query parameters shared by the diagnostics endpoints
+func parseDiagnosticsOptions(ctx *context.APIContext, maxLimit int) (minDuration time.Duration, since time.Time, limit int, err error) {
+	if value := ctx.FormTrim("min_duration"); value != "" {
+		if minDuration, err = time.ParseDuration(value); err != nil {
+			return minDuration, since, limit, fmt.Errorf("invalid min_duration parameter: %s", value)
+		}
+	}
+	if value := ctx.FormTrim("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			return minDuration, since, limit, fmt.Errorf("invalid since parameter: %s", value)
+		}
+	}
+	limit = ctx.FormInt("limit")
+	if limit <= 0 {
+		limit = defaultDiagnosticsLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if format := ctx.FormTrim("format"); format != "" && format != "json" && format != "csv" {
+		return minDuration, since, limit, fmt.Errorf("invalid format parameter: %s", format)
+	}
+	return minDuration, since, limit, nil
+}
+
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// serveCSV writes the records as a CSV file
+func serveCSV(ctx *context.APIContext, filename string, header []string, records [][]string) {
+	ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Resp)
+	_ = w.Write(header)
+	_ = w.WriteAll(records)
+}
+
+// ListDiagnosticRequests lists the latest requests kept by the request log
+func ListDiagnosticRequests(ctx *context.APIContext) {
+	// swagger:operation GET /admin/diagnostics/requests admin adminListDiagnosticRequests
+	// ---
+	// summary: List the latest served requests
+	// description: The requests are only kept in memory if the request log is enabled, the newest are returned first.
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: route
+	//   in: query
+	//   description: only requests whose route pattern or path contain the value
+	//   type: string
+	// - name: user
+	//   in: query
+	//   description: only requests of the user
+	//   type: string
+	// - name: min_status
+	//   in: query
+	//   description: only requests with a response status of at least the value
+	//   type: integer
+	// - name: min_duration
+	//   in: query
+	//   description: only requests taking at least the duration, e.g. 500ms
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only requests served after the time (RFC 3339)
+	//   type: string
+	//   format: date-time
+	// - name: limit
+	//   in: query
+	//   description: maximum number of requests, defaults to 100
+	//   type: integer
+	// - name: format
+	//   in: query
+	//   description: json (default) or csv to export the requests
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiagnosticRequestList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	minDuration, since, limit, err := parseDiagnosticsOptions(ctx, setting.Diagnostics.MaxRequests)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	requests := diagnostics.FindRequests(diagnostics.FindRequestsOptions{
+		Route:       ctx.FormTrim("route"),
+		User:        ctx.FormTrim("user"),
+		MinStatus:   ctx.FormInt("min_status"),
+		MinDuration: minDuration,
+		Since:       since,
+		Limit:       limit,
+	})
+
+	if ctx.FormTrim("format") == "csv" {
+		records := make([][]string, 0, len(requests))
+		for _, r := range requests {
+			records = append(records, []string{
+				r.Time.Format(time.RFC3339),
+				r.Method,
+				r.Route,
+				r.Path,
+				r.User,
+				strconv.Itoa(r.Status),
+				strconv.FormatFloat(durationMilliseconds(r.Duration), 'f', 3, 64),
+				strconv.FormatInt(r.Size, 10),
+			})
+		}
+		serveCSV(ctx, "requests.csv", []string{"time", "method", "route", "path", "user", "status", "duration_ms", "size"}, records)
+		return
+	}
+
+	apiRequests := make([]*api.DiagnosticRequest, 0, len(requests))
+	for _, r := range requests {
+		apiRequests = append(apiRequests, &api.DiagnosticRequest{
+			Time:     r.Time,
+			Method:   r.Method,
+			Route:    r.Route,
+			Path:     r.Path,
+			User:     r.User,
+			Status:   r.Status,
+			Duration: durationMilliseconds(r.Duration),
+			Size:     r.Size,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiRequests)
+}
+
+// ListDiagnosticSlowQueries lists the latest database queries which took longer than the slow query threshold
+func ListDiagnosticSlowQueries(ctx *context.APIContext) {
+	// swagger:operation GET /admin/diagnostics/slow_queries admin adminListDiagnosticSlowQueries
+	// ---
+	// summary: List the latest slow database queries
+	// description: The queries are only kept in memory if a slow query threshold is configured, the newest are returned first.
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: min_duration
+	//   in: query
+	//   description: only queries taking at least the duration, e.g. 2s
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only queries executed after the time (RFC 3339)
+	//   type: string
+	//   format: date-time
+	// - name: limit
+	//   in: query
+	//   description: maximum number of queries, defaults to 100
+	//   type: integer
+	// - name: format
+	//   in: query
+	//   description: json (default) or csv to export the queries
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiagnosticSlowQueryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	minDuration, since, limit, err := parseDiagnosticsOptions(ctx, setting.Diagnostics.MaxSlowQueries)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	queries := diagnostics.FindSlowQueries(diagnostics.FindSlowQueriesOptions{
+		MinDuration: minDuration,
+		Since:       since,
+		Limit:       limit,
+	})
+
+	if ctx.FormTrim("format") == "csv" {
+		records := make([][]string, 0, len(queries))
+		for _, q := range queries {
+			records = append(records, []string{
+				q.Time.Format(time.RFC3339),
+				strconv.FormatFloat(durationMilliseconds(q.Duration), 'f', 3, 64),
+				q.SQL,
+				q.Error,
+			})
+		}
+		serveCSV(ctx, "slow_queries.csv", []string{"time", "duration_ms", "sql", "error"}, records)
+		return
+	}
+
+	apiQueries := make([]*api.DiagnosticSlowQuery, 0, len(queries))
+	for _, q := range queries {
+		apiQueries = append(apiQueries, &api.DiagnosticSlowQuery{
+			Time:     q.Time,
+			SQL:      q.SQL,
+			Duration: durationMilliseconds(q.Duration),
+			Error:    q.Error,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiQueries)
+}
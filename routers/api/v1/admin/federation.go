@@ -0,0 +1,154 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	federation_model "code.gitea.io/gitea/models/federation"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListFederatedInstances lists the instances of the federation allowlist and blocklist
+func ListFederatedInstances(ctx *context.APIContext) {
+	// swagger:operation GET /admin/federation/instances admin adminListFederatedInstances
+	// ---
+	// summary: List the instances of the federation allowlist and blocklist
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: policy
+	//   in: query
+	//   description: only list instances with this policy
+	//   type: string
+	//   enum: [allow, block]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederatedInstanceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := &federation_model.FindInstancesOptions{
+		ListOptions: listOptions,
+		Policy:      federation_model.InstancePolicy(ctx.FormTrim("policy")),
+	}
+	if opts.Policy != "" && !opts.Policy.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown policy %q", opts.Policy))
+		return
+	}
+
+	instances, count, err := federation_model.FindInstances(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindInstances", err)
+		return
+	}
+
+	apiInstances := make([]*api.FederatedInstance, 0, len(instances))
+	for _, instance := range instances {
+		apiInstances = append(apiInstances, convert.ToFederatedInstance(instance))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiInstances)
+}
+
+// SetFederatedInstance adds an instance to the federation allowlist or blocklist
+func SetFederatedInstance(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/federation/instances/{host} admin adminSetFederatedInstance
+	// ---
+	// summary: Add an instance to the federation allowlist or blocklist or change its policy
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: host
+	//   in: path
+	//   description: host of the instance, a policy also applies to all subdomains
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetFederatedInstanceOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederatedInstance"
+	//   "201":
+	//     "$ref": "#/responses/FederatedInstance"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetFederatedInstanceOption)
+
+	host := federation_model.NormalizeHost(ctx.Params(":host"))
+	if host == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "host must not be empty")
+		return
+	}
+
+	instance, created, err := federation_model.SetInstancePolicy(ctx, host, federation_model.InstancePolicy(form.Policy), form.Reason)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetInstancePolicy", err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, convert.ToFederatedInstance(instance))
+}
+
+// DeleteFederatedInstance removes an instance from the federation allowlist or blocklist
+func DeleteFederatedInstance(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/federation/instances/{host} admin adminDeleteFederatedInstance
+	// ---
+	// summary: Remove an instance from the federation allowlist or blocklist
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: host
+	//   in: path
+	//   description: host of the instance
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := federation_model.DeleteInstance(ctx, ctx.Params(":host")); err != nil {
+		if federation_model.IsErrInstanceNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteInstance", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
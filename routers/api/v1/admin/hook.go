@@ -0,0 +1,216 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// ListDefaultHooks list the admin-default webhooks
+func ListDefaultHooks(ctx *context.APIContext) {
+	// swagger:operation GET /admin/hooks/defaults admin adminListDefaultHooks
+	// ---
+	// summary: List the default webhooks which are copied onto all new repositories
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	defaultHooks, err := webhook.GetDefaultWebhooks(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDefaultWebhooks", err)
+		return
+	}
+
+	hooks := make([]*api.Hook, len(defaultHooks))
+	for i, hook := range defaultHooks {
+		hooks[i] = convert.ToHook(setting.AppSubURL+"/admin", hook)
+	}
+
+	ctx.SetTotalCountHeader(int64(len(hooks)))
+	ctx.JSON(http.StatusOK, hooks)
+}
+
+// GetDefaultHook get an admin-default webhook by id
+func GetDefaultHook(ctx *context.APIContext) {
+	// swagger:operation GET /admin/hooks/defaults/{id} admin adminGetDefaultHook
+	// ---
+	// summary: Get a default hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := utils.GetAdminDefaultHook(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(setting.AppSubURL+"/admin", hook))
+}
+
+// CreateDefaultHook create an admin-default webhook
+func CreateDefaultHook(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hooks/defaults admin adminCreateDefaultHook
+	// ---
+	// summary: Create a default hook which is copied onto all new repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Hook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	form := web.GetForm(ctx).(*api.CreateHookOption)
+	if !utils.CheckCreateHookOption(ctx, form) {
+		return
+	}
+	utils.AddAdminDefaultHook(ctx, form)
+}
+
+// EditDefaultHook modify an admin-default webhook
+func EditDefaultHook(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/hooks/defaults/{id} admin adminEditDefaultHook
+	// ---
+	// summary: Update a default hook, existing copies of the hook are not changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to update
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.EditHookOption)
+	utils.EditAdminDefaultHook(ctx, form, ctx.ParamsInt64(":id"))
+}
+
+// DeleteDefaultHook delete an admin-default webhook
+func DeleteDefaultHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/hooks/defaults/{id} admin adminDeleteDefaultHook
+	// ---
+	// summary: Delete a default hook, existing copies of the hook are kept
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hookID := ctx.ParamsInt64(":id")
+	if _, err := utils.GetAdminDefaultHook(ctx, hookID); err != nil {
+		return
+	}
+	if err := webhook.DeleteDefaultSystemWebhook(hookID); err != nil {
+		if webhook.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteDefaultSystemWebhook", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ApplyDefaultHook copy an admin-default webhook onto existing repositories
+func ApplyDefaultHook(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hooks/defaults/{id}/apply admin adminApplyDefaultHook
+	// ---
+	// summary: Copy a default hook onto the existing repositories which don't have a copy yet
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to apply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ApplyDefaultHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ApplyDefaultHookResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ApplyDefaultHookOption)
+
+	hook, err := utils.GetAdminDefaultHook(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	applied, err := webhook_service.ApplyDefaultWebhook(ctx, hook, form.Pattern)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ApplyDefaultWebhook", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ApplyDefaultHookResult{Applied: applied})
+}
@@ -0,0 +1,309 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	user_service "code.gitea.io/gitea/services/user"
+)
+
+// ListNamePolicies lists the policies for the names of new users and organizations
+func ListNamePolicies(ctx *context.APIContext) {
+	// swagger:operation GET /admin/name_policies admin adminListNamePolicies
+	// ---
+	// summary: List the policies for the names of new users and organizations
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NamePolicyList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	policies, err := user_model.GetNamePolicies(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetNamePolicies", err)
+		return
+	}
+
+	apiPolicies := make([]*api.NamePolicy, 0, len(policies))
+	for _, p := range policies {
+		apiPolicies = append(apiPolicies, convert.ToNamePolicy(p))
+	}
+	ctx.JSON(http.StatusOK, apiPolicies)
+}
+
+// CreateNamePolicy adds a policy for the names of new users and organizations
+func CreateNamePolicy(ctx *context.APIContext) {
+	// swagger:operation POST /admin/name_policies admin adminCreateNamePolicy
+	// ---
+	// summary: Add a policy for the names of new users and organizations
+	// description: Reserved patterns are globs which reject matching names. If service account
+	//   prefixes exist, service accounts must use one of them and other accounts must not.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateNamePolicyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/NamePolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateNamePolicyOption)
+
+	p := &user_model.NamePolicy{
+		Kind:        user_model.NamePolicyKind(form.Kind),
+		Pattern:     form.Pattern,
+		Description: form.Description,
+	}
+	if err := user_model.ValidateNamePolicy(p); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	if err := user_model.CreateNamePolicy(ctx, p); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateNamePolicy", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToNamePolicy(p))
+}
+
+// DeleteNamePolicy removes a policy for the names of new users and organizations
+func DeleteNamePolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/name_policies/{id} admin adminDeleteNamePolicy
+	// ---
+	// summary: Remove a policy for the names of new users and organizations
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the name policy
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := user_model.DeleteNamePolicy(ctx, ctx.ParamsInt64(":id")); err != nil {
+		if user_model.IsErrNamePolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteNamePolicy", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListNameClaims lists the reclaims of the names of dormant users
+func ListNameClaims(ctx *context.APIContext) {
+	// swagger:operation GET /admin/name_claims admin adminListNameClaims
+	// ---
+	// summary: List the reclaims of the names of dormant users, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: only list claims with this status
+	//   type: string
+	//   enum: [pending, completed, cancelled]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NameClaimList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := &user_model.FindNameClaimsOptions{
+		ListOptions: listOptions,
+		Status:      user_model.NameClaimStatus(ctx.FormTrim("status")),
+	}
+	switch opts.Status {
+	case "", user_model.NameClaimStatusPending, user_model.NameClaimStatusCompleted, user_model.NameClaimStatusCancelled:
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown status %q", opts.Status))
+		return
+	}
+
+	claims, count, err := user_model.FindNameClaims(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindNameClaims", err)
+		return
+	}
+
+	apiClaims := make([]*api.NameClaim, 0, len(claims))
+	for _, c := range claims {
+		claimant := getNameClaimant(ctx, c)
+		if ctx.Written() {
+			return
+		}
+		apiClaims = append(apiClaims, convert.ToNameClaim(c, claimant, ctx.Doer))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiClaims)
+}
+
+// CreateNameClaim reclaims the name of a dormant user
+func CreateNameClaim(ctx *context.APIContext) {
+	// swagger:operation POST /admin/name_claims admin adminCreateNameClaim
+	// ---
+	// summary: Reclaim the name of a dormant user
+	// description: The user is notified and renamed at the end of the grace period unless the user
+	//   signs in before. The claimant, if given, is renamed to the released name.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateNameClaimOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/NameClaim"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateNameClaimOption)
+
+	u, err := user_model.GetUserByName(ctx, form.Username)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	var claimant *user_model.User
+	if form.Claimant != "" {
+		claimant, err = user_model.GetUserByName(ctx, form.Claimant)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+	}
+
+	claim, err := user_service.CreateNameClaim(ctx, ctx.Doer, u, claimant)
+	if err != nil {
+		switch {
+		case user_model.IsErrUserNotExist(err):
+			ctx.NotFound()
+		case user_service.IsErrUserNotDormant(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case user_model.IsErrNameClaimAlreadyPending(err):
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CreateNameClaim", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToNameClaim(claim, claimant, ctx.Doer))
+}
+
+// CancelNameClaim cancels a pending reclaim of the name of a dormant user
+func CancelNameClaim(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/name_claims/{id} admin adminCancelNameClaim
+	// ---
+	// summary: Cancel a pending reclaim of the name of a dormant user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the name claim
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	claim, err := user_model.GetNameClaimByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if user_model.IsErrNameClaimNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetNameClaimByID", err)
+		}
+		return
+	}
+	if claim.Status != user_model.NameClaimStatusPending {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("name claim is %s", claim.Status))
+		return
+	}
+
+	if err := user_model.UpdateNameClaimStatus(ctx, claim, user_model.NameClaimStatusCancelled); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateNameClaimStatus", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getNameClaimant(ctx *context.APIContext, c *user_model.NameClaim) *user_model.User {
+	if c.ClaimantID == 0 {
+		return nil
+	}
+	claimant, err := user_model.GetUserByIDCtx(ctx, c.ClaimantID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetUserByIDCtx", err)
+		}
+		return nil
+	}
+	return claimant
+}
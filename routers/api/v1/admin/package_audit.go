@@ -0,0 +1,162 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListPackageAuditLogs lists the package audit log
+func ListPackageAuditLogs(ctx *context.APIContext) {
+	// swagger:operation GET /admin/packages/audit admin adminListPackageAuditLogs
+	// ---
+	// summary: List the package audit log, newest first
+	// description: The admin scope lists the operations of administrators on behalf of users, e.g. provisioned credentials.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: scope
+	//   in: query
+	//   description: scope of the listed entries
+	//   type: string
+	//   enum: [package, admin]
+	//   default: package
+	// - name: owner
+	//   in: query
+	//   description: only list entries of packages of this owner or, in the admin scope, of operations on behalf of this user
+	//   type: string
+	// - name: actor
+	//   in: query
+	//   description: only list entries of operations by this user
+	//   type: string
+	// - name: action
+	//   in: query
+	//   description: only list entries of this action
+	//   type: string
+	//   enum: [publish, delete, download, permission, provision_ssh_key, provision_gpg_key, provision_token, issue_2fa_recovery, use_2fa_recovery]
+	// - name: type
+	//   in: query
+	//   description: only list entries of this package type
+	//   type: string
+	// - name: name
+	//   in: query
+	//   description: only list entries of packages with this name
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only list entries created at or after this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only list entries created before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAuditLogList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := &packages_model.AuditLogSearchOptions{
+		Scope:       packages_model.AuditScope(ctx.FormTrim("scope")),
+		Action:      packages_model.AuditAction(ctx.FormTrim("action")),
+		PackageType: packages_model.Type(ctx.FormTrim("type")),
+		PackageName: ctx.FormTrim("name"),
+		Paginator:   &listOptions,
+	}
+	if opts.Scope == "" {
+		opts.Scope = packages_model.AuditScopePackage
+	} else if !opts.Scope.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown scope %q", opts.Scope))
+		return
+	}
+	if opts.Action != "" && !opts.Action.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown action %q", opts.Action))
+		return
+	}
+
+	if owner := ctx.FormTrim("owner"); owner != "" {
+		if opts.OwnerID = getUserIDByName(ctx, owner); ctx.Written() {
+			return
+		}
+	}
+	if actor := ctx.FormTrim("actor"); actor != "" {
+		if opts.ActorID = getUserIDByName(ctx, actor); ctx.Written() {
+			return
+		}
+	}
+	if since := ctx.FormTrim("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		opts.Since = timeutil.TimeStamp(t.Unix())
+	}
+	if before := ctx.FormTrim("before"); before != "" {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		opts.Before = timeutil.TimeStamp(t.Unix())
+	}
+
+	logs, count, err := packages_model.SearchAuditLogs(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchAuditLogs", err)
+		return
+	}
+	if err := logs.LoadOwners(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadOwners", err)
+		return
+	}
+
+	apiLogs := make([]*api.PackageAuditLog, 0, len(logs))
+	for _, l := range logs {
+		apiLogs = append(apiLogs, convert.ToPackageAuditLog(l))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiLogs)
+}
+
+func getUserIDByName(ctx *context.APIContext, name string) int64 {
+	u, err := user_model.GetUserByName(ctx, name)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return 0
+	}
+	return u.ID
+}
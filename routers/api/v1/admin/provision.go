@@ -0,0 +1,128 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/user"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// CreateGPGKey adds a GPG key on behalf of a user
+func CreateGPGKey(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/{username}/gpg_keys admin adminCreateGPGKey
+	// ---
+	// summary: Add a GPG key on behalf of a user
+	// description: The key is added unverified, a signature is ignored. The user is notified and the operation is recorded in the audit log.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateGPGKeyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/GPGKey"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateGPGKeyOption)
+	// the signature would have to be made for a token of the administrator
+	form.Signature = ""
+
+	if key := user.CreateUserGPGKey(ctx, *form, ctx.ContextUser.ID); key != nil {
+		recordProvisioning(ctx, packages_model.AuditActionProvisionGPGKey, "gpg_key", key.KeyID)
+	}
+}
+
+// CreateAccessToken creates an access token on behalf of a user
+func CreateAccessToken(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/{username}/tokens admin adminCreateAccessToken
+	// ---
+	// summary: Create an access token on behalf of a user
+	// description: The user is notified and the operation is recorded in the audit log.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAccessTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/AccessToken"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.CreateAccessTokenOption)
+
+	if t := user.CreateUserAccessToken(ctx, *form, ctx.ContextUser.ID); t != nil {
+		name := t.Name
+		if t.Scope != "" {
+			name = fmt.Sprintf("%s (%s)", t.Name, t.Scope)
+		}
+		recordProvisioning(ctx, packages_model.AuditActionProvisionToken, "token", name)
+	}
+}
+
+// recordProvisioning adds the provisioning of a credential for the context user to the audit log
+// and notifies the user. Failures are logged only, the credential has already been created.
+func recordProvisioning(ctx *context.APIContext, action packages_model.AuditAction, kind, name string) {
+	recordAudit(ctx, action, name)
+
+	if err := mailer.SendCredentialProvisionedMail(ctx.ContextUser, ctx.Doer, kind, name); err != nil {
+		log.Error("SendCredentialProvisionedMail: %v", err)
+	}
+}
+
+// recordAudit adds an operation of the doer on behalf of the context user to the audit log.
+// Failures are logged only.
+func recordAudit(ctx *context.APIContext, action packages_model.AuditAction, detail string) {
+	ip := ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if err := packages_model.InsertAuditLog(ctx, &packages_model.PackageAuditLog{
+		OwnerID:   ctx.ContextUser.ID,
+		Action:    action,
+		ActorID:   ctx.Doer.ID,
+		ActorName: ctx.Doer.Name,
+		Detail:    detail,
+		IP:        ip,
+		UserAgent: ctx.Req.UserAgent(),
+	}); err != nil {
+		log.Error("Error recording %s for %s in the audit log: %v", action, ctx.ContextUser.Name, err)
+	}
+}
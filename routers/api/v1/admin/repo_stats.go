@@ -0,0 +1,123 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetRepoStatsRecalculation returns the progress of the latest recalculation of the repository statistics
+func GetRepoStatsRecalculation(ctx *context.APIContext) {
+	// swagger:operation GET /admin/repos/stats/recalculation admin adminGetRepoStatsRecalculation
+	// ---
+	// summary: Get the progress of the latest recalculation of the repository statistics
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoStatsRecalculation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	r, err := repo_model.GetLatestStatsRecalculation(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLatestStatsRecalculation", err)
+		return
+	} else if r == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoStatsRecalculation(r))
+}
+
+// RecalculateRepoStats starts to recalculate the statistics of all repositories
+func RecalculateRepoStats(ctx *context.APIContext) {
+	// swagger:operation POST /admin/repos/stats/recalculation admin adminRecalculateRepoStats
+	// ---
+	// summary: Recalculate the statistics of all repositories
+	// description: The repositories are recalculated in the background by the repo_stats_recalculation queue, a recalculation interrupted by a restart is resumed.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RecalculateRepoStatsOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoStatsRecalculation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RecalculateRepoStatsOption)
+
+	kinds := make([]repo_model.StatsRecalculationKind, 0, len(form.Kinds))
+	for _, k := range form.Kinds {
+		kind := repo_model.StatsRecalculationKind(strings.ToLower(strings.TrimSpace(k)))
+		if !kind.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown kind of repository statistics: %s", k))
+			return
+		}
+		kinds = append(kinds, kind)
+	}
+
+	r, err := repo_service.StartStatsRecalculation(ctx, ctx.Doer.ID, kinds)
+	if err != nil {
+		if repo_model.IsErrStatsRecalculationRunning(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "StartStatsRecalculation", err)
+		}
+		return
+	}
+	log.Trace("Recalculation of the repository statistics started by admin(%s)", ctx.Doer.Name)
+
+	ctx.JSON(http.StatusCreated, convert.ToRepoStatsRecalculation(r))
+}
+
+// CancelRepoStatsRecalculation cancels the running recalculation of the repository statistics
+func CancelRepoStatsRecalculation(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/repos/stats/recalculation admin adminCancelRepoStatsRecalculation
+	// ---
+	// summary: Cancel the running recalculation of the repository statistics
+	// description: Repositories which are already recalculated keep their new statistics.
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoStatsRecalculation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	r, err := repo_model.CancelStatsRecalculation(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CancelStatsRecalculation", err)
+		return
+	} else if r == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoStatsRecalculation(r))
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// twoFactorRecoveryExpiry is how long a recovery code issued by an administrator can be used
+const twoFactorRecoveryExpiry = 24 * time.Hour
+
+// CreateTwoFactorRecovery issues a one-time recovery code for a user who lost access to the second factor
+func CreateTwoFactorRecovery(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/{username}/2fa/recovery admin adminCreateTwoFactorRecovery
+	// ---
+	// summary: Issue a one-time two-factor recovery code for a user
+	// description: The user can sign in once with the code instead of the second factor within 24 hours, also if only security keys are allowed. A previously issued code becomes invalid. The user is notified and the operation is recorded in the audit log.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/TwoFactorRecovery"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	hasTOTP, err := auth_model.HasTwoFactorByUID(ctx.ContextUser.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "HasTwoFactorByUID", err)
+		return
+	}
+	hasWebAuthn, err := auth_model.HasWebAuthnRegistrationsByUID(ctx.ContextUser.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "HasWebAuthnRegistrationsByUID", err)
+		return
+	}
+	if !hasTOTP && !hasWebAuthn {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("user is not enrolled in two-factor authentication"))
+		return
+	}
+
+	code, recovery, err := auth_model.CreateTwoFactorRecovery(ctx, ctx.ContextUser.ID, ctx.Doer.ID, twoFactorRecoveryExpiry)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateTwoFactorRecovery", err)
+		return
+	}
+
+	recordAudit(ctx, packages_model.AuditActionIssueTwoFactorRecovery, "expires "+recovery.ExpiresUnix.AsTime().UTC().Format(time.RFC3339))
+	if err := mailer.SendTwoFactorRecoveryMail(ctx.ContextUser, ctx.Doer, recovery.ExpiresUnix); err != nil {
+		log.Error("SendTwoFactorRecoveryMail: %v", err)
+	}
+
+	ctx.JSON(http.StatusCreated, &api.TwoFactorRecovery{
+		Code:      code,
+		ExpiresAt: recovery.ExpiresUnix.AsTime(),
+	})
+}
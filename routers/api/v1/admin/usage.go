@@ -0,0 +1,74 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+const (
+	defaultUsageGrowthDays = 30
+	maxUsageGrowthDays     = 366
+)
+
+// GetUsage reports the storage use and the number of repositories, users and organizations of the instance
+func GetUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/usage admin adminGetUsage
+	// ---
+	// summary: Get the latest usage report of the instance
+	// description: The usage reports are created by the aggregate_usage cron task.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: since
+	//   in: query
+	//   description: first day of the growth over time (YYYY-MM-DD or RFC 3339), defaults to 30 days ago
+	//   type: string
+	// - name: until
+	//   in: query
+	//   description: last day of the growth over time (YYYY-MM-DD or RFC 3339), defaults to today
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UsageReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, until, err := utils.ParseDateRange(ctx, defaultUsageGrowthDays, maxUsageGrowthDays)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	report, err := activities_model.GetLatestUsageReport(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLatestUsageReport", err)
+		return
+	} else if report == nil {
+		ctx.NotFound("no usage report was created yet")
+		return
+	}
+
+	since = time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+	until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	growth, err := activities_model.FindUsageReports(ctx, timeutil.TimeStamp(since.Unix()), timeutil.TimeStamp(until.Unix()))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUsageReports", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToUsageReport(report, growth))
+}
@@ -10,11 +10,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
@@ -22,12 +24,14 @@ import (
 	"code.gitea.io/gitea/modules/password"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/mailer"
+	repo_service "code.gitea.io/gitea/services/repository"
 	user_service "code.gitea.io/gitea/services/user"
 )
 
@@ -51,6 +55,17 @@ func parseAuthSource(ctx *context.APIContext, u *user_model.User, sourceID int64
 	u.LoginName = loginName
 }
 
+// sourceTypes maps the source_type filter values to the login types of the users
+var sourceTypes = map[string][]auth.Type{
+	"local":  {auth.NoType, auth.Plain},
+	"ldap":   {auth.LDAP},
+	"dldap":  {auth.DLDAP},
+	"smtp":   {auth.SMTP},
+	"pam":    {auth.PAM},
+	"oauth2": {auth.OAuth2},
+	"sspi":   {auth.SSPI},
+}
+
 // CreateUser create a user
 func CreateUser(ctx *context.APIContext) {
 	// swagger:operation POST /admin/users admin adminCreateUser
@@ -88,6 +103,9 @@ func CreateUser(ctx *context.APIContext) {
 	if form.MustChangePassword != nil {
 		u.MustChangePassword = *form.MustChangePassword
 	}
+	if form.ServiceAccount {
+		u.Type = user_model.UserTypeBot
+	}
 
 	parseAuthSource(ctx, u, form.SourceID, form.LoginName)
 	if ctx.Written() {
@@ -128,7 +146,8 @@ func CreateUser(ctx *context.APIContext) {
 			db.IsErrNameCharsNotAllowed(err) ||
 			user_model.IsErrEmailCharIsNotSupported(err) ||
 			user_model.IsErrEmailInvalid(err) ||
-			db.IsErrNamePatternNotAllowed(err) {
+			db.IsErrNamePatternNotAllowed(err) ||
+			user_model.IsErrServiceAccountPrefixRequired(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateUser", err)
@@ -336,6 +355,7 @@ func CreatePublicKey(ctx *context.APIContext) {
 	// swagger:operation POST /admin/users/{username}/keys admin adminCreatePublicKey
 	// ---
 	// summary: Add a public key on behalf of a user
+	// description: The user is notified and the operation is recorded in the audit log.
 	// consumes:
 	// - application/json
 	// produces:
@@ -360,7 +380,9 @@ func CreatePublicKey(ctx *context.APIContext) {
 
 	form := web.GetForm(ctx).(*api.CreateKeyOption)
 
-	user.CreateUserPublicKey(ctx, *form, ctx.ContextUser.ID)
+	if key := user.CreateUserPublicKey(ctx, *form, ctx.ContextUser.ID); key != nil {
+		recordProvisioning(ctx, packages_model.AuditActionProvisionSSHKey, "ssh_key", fmt.Sprintf("%s (%s)", key.Name, key.Fingerprint))
+	}
 }
 
 // DeleteUserPublicKey api for deleting a user's public key
@@ -421,20 +443,63 @@ func GetAllUsers(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: last_login_before
+	//   in: query
+	//   description: only list users whose last login is before the given time (users who never logged in are excluded)
+	//   type: string
+	//   format: date-time
+	// - name: never_logged_in
+	//   in: query
+	//   description: filter users by whether they have ever logged in
+	//   type: boolean
+	// - name: source_type
+	//   in: query
+	//   description: only list users of the given authentication source type
+	//   type: string
+	//   enum: [local, ldap, dldap, smtp, pam, oauth2, sspi]
+	// - name: two_factor_enabled
+	//   in: query
+	//   description: filter users by whether they have two-factor authentication enabled
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/UserList"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	listOptions := utils.GetListOptions(ctx)
 
-	users, maxResults, err := user_model.SearchUsers(&user_model.SearchUserOptions{
-		Actor:       ctx.Doer,
-		Type:        user_model.UserTypeIndividual,
-		OrderBy:     db.SearchOrderByAlphabetically,
-		ListOptions: listOptions,
-	})
+	opts := &user_model.SearchUserOptions{
+		Actor:              ctx.Doer,
+		Type:               user_model.UserTypeIndividual,
+		IncludeBots:        true,
+		OrderBy:            db.SearchOrderByAlphabetically,
+		ListOptions:        listOptions,
+		IsNeverLoggedIn:    ctx.FormOptionalBool("never_logged_in"),
+		IsTwoFactorEnabled: ctx.FormOptionalBool("two_factor_enabled"),
+	}
+
+	if lastLoginBefore := ctx.FormTrim("last_login_before"); lastLoginBefore != "" {
+		t, err := time.Parse(time.RFC3339, lastLoginBefore)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "last_login_before", err)
+			return
+		}
+		opts.LastLoginBefore = timeutil.TimeStamp(t.Unix())
+	}
+
+	if sourceType := ctx.FormTrim("source_type"); sourceType != "" {
+		loginTypes, ok := sourceTypes[strings.ToLower(sourceType)]
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "source_type", fmt.Errorf("unknown source type: %s", sourceType))
+			return
+		}
+		opts.LoginTypes = loginTypes
+	}
+
+	users, maxResults, err := user_model.SearchUsers(opts)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetAllUsers", err)
 		return
@@ -449,3 +514,99 @@ func GetAllUsers(ctx *context.APIContext) {
 	ctx.SetTotalCountHeader(maxResults)
 	ctx.JSON(http.StatusOK, &results)
 }
+
+// BulkUsers runs an action on several users at once
+func BulkUsers(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/bulk admin adminBulkUsers
+	// ---
+	// summary: Disable, force a password reset for or delete several users at once
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/AdminBulkUsersOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AdminBulkUserResultList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.AdminBulkUsersOption)
+
+	var transferTo *user_model.User
+	if form.Action == "delete" && form.TransferReposTo != "" {
+		var err error
+		transferTo, err = user_model.GetUserByName(ctx, form.TransferReposTo)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+	}
+
+	results := make([]*api.AdminBulkUserResult, 0, len(form.Usernames))
+	for _, name := range form.Usernames {
+		result := &api.AdminBulkUserResult{Username: name}
+		if err := bulkUserAction(ctx, name, form.Action, transferTo, form.Purge); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
+
+func bulkUserAction(ctx *context.APIContext, name, action string, transferTo *user_model.User, purge bool) error {
+	u, err := user_model.GetUserByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if u.IsOrganization() {
+		return fmt.Errorf("%s is an organization not a user", u.Name)
+	}
+	if u.ID == ctx.Doer.ID {
+		return errors.New("you cannot run bulk actions on yourself")
+	}
+
+	switch action {
+	case "disable":
+		u.ProhibitLogin = true
+		if err := user_model.UpdateUserCols(ctx, u, "prohibit_login"); err != nil {
+			return err
+		}
+		log.Trace("Account disabled by admin (%s): %s", ctx.Doer.Name, u.Name)
+	case "force_password_reset":
+		if !u.IsLocal() {
+			return fmt.Errorf("%s is not a local user", u.Name)
+		}
+		u.MustChangePassword = true
+		if err := user_model.UpdateUserCols(ctx, u, "must_change_password"); err != nil {
+			return err
+		}
+		log.Trace("Password reset forced by admin (%s): %s", ctx.Doer.Name, u.Name)
+	case "delete":
+		if transferTo != nil {
+			if err := repo_service.TransferAllRepositories(ctx.Doer, u, transferTo); err != nil {
+				return err
+			}
+		}
+		if err := user_service.DeleteUser(ctx, u, purge); err != nil {
+			return err
+		}
+		log.Trace("Account deleted by admin (%s): %s", ctx.Doer.Name, u.Name)
+	default:
+		return fmt.Errorf("unknown action: %s", action)
+	}
+	return nil
+}
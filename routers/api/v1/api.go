@@ -200,7 +200,8 @@ func repoAssignment() func(ctx *context.APIContext) {
 
 func reqPackageAccess(accessMode perm.AccessMode) func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
-		if ctx.Package.AccessMode < accessMode && !ctx.IsUserSiteAdmin() {
+		scope, _ := ctx.AccessTokenScope()
+		if ctx.Package.AccessMode < accessMode && (!ctx.IsUserSiteAdmin() || scope.IsPackageScope()) {
 			ctx.Error(http.StatusForbidden, "reqPackageAccess", "user should have specific permission or be a site admin")
 			return
 		}
@@ -648,13 +649,14 @@ func Routes(ctx gocontext.Context) *web.Route {
 			m.Group("/activitypub", func() {
 				m.Group("/user/{username}", func() {
 					m.Get("", activitypub.Person)
-					m.Post("/inbox", activitypub.ReqHTTPSignature(), activitypub.PersonInbox)
+					m.Post("/inbox", activitypub.RateLimitInbox(), activitypub.ReqHTTPSignature(), activitypub.PersonInbox)
 				}, context_service.UserAssignmentAPI())
 			})
 		}
 		m.Get("/signing-key.gpg", misc.SigningKey)
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Get("/search", misc.Search)
 		m.Group("/settings", func() {
 			m.Get("/ui", settings.GetGeneralUISettings)
 			m.Get("/api", settings.GetGeneralAPISettings)
@@ -771,6 +773,8 @@ func Routes(ctx gocontext.Context) *web.Route {
 
 			m.Get("/stopwatches", repo.GetStopwatches)
 
+			m.Get("/transfers", repo.ListMyTransfers)
+
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
 
 			m.Get("/teams", org.ListUserTeams)
@@ -793,7 +797,10 @@ func Routes(ctx gocontext.Context) *web.Route {
 					Delete(reqToken(), reqOwner(), repo.Delete).
 					Patch(reqToken(), reqAdmin(), bind(api.EditRepoOption{}), repo.Edit)
 				m.Post("/generate", reqToken(), reqRepoReader(unit.TypeCode), bind(api.GenerateRepoOption{}), repo.Generate)
-				m.Post("/transfer", reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer)
+				m.Combo("/transfer").Get(reqToken(), repo.GetTransfer).
+					Post(reqOwner(), bind(api.TransferRepoOption{}), repo.Transfer).
+					Patch(reqToken(), bind(api.EditRepoTransferOption{}), repo.EditTransfer).
+					Delete(reqToken(), reqOwner(), repo.CancelTransfer)
 				m.Post("/transfer/accept", reqToken(), repo.AcceptTransfer)
 				m.Post("/transfer/reject", reqToken(), repo.RejectTransfer)
 				m.Combo("/notifications").
@@ -817,6 +824,9 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Post("/tests", context.ReferencesGitRepo(), context.RepoRefForAPI, repo.TestHook)
 					})
 				}, reqToken(), reqAdmin(), reqWebhooksEnabled())
+				m.Combo("/config", reqToken(), reqAdmin()).
+					Get(repo.GetConfig).
+					Put(repo.ApplyConfig)
 				m.Group("/collaborators", func() {
 					m.Get("", reqAnyRepoReader(), repo.ListCollaborators)
 					m.Group("/{collaborator}", func() {
@@ -826,6 +836,8 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Get("/permission", repo.GetRepoPermissions)
 					}, reqToken())
 				}, reqToken())
+				m.Get("/collaborations", reqToken(), reqAdmin(), repo.ListCollaborations)
+				m.Get("/moderation/log", reqToken(), reqAdmin(), repo.ListModerationLogs)
 				m.Get("/assignees", reqToken(), reqAnyRepoReader(), repo.GetAssignees)
 				m.Get("/reviewers", reqToken(), reqAnyRepoReader(), repo.GetReviewers)
 				m.Group("/teams", func() {
@@ -837,8 +849,19 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Get("/raw/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFile)
 				m.Get("/media/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFileOrLFS)
 				m.Get("/archive/*", reqRepoReader(unit.TypeCode), repo.GetArchive)
-				m.Combo("/forks").Get(repo.ListForks).
-					Post(reqToken(), reqRepoReader(unit.TypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Post("/archive/*", context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode), repo.CreateArchive)
+				m.Get("/archive_status/*", context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode), repo.GetArchiveStatus)
+				m.Post("/bundle", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeCode), repo.UploadBundle)
+				m.Get("/diff/blobs", context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode), repo.GetBlobDiff)
+				m.Group("/forks", func() {
+					m.Combo("").Get(repo.ListForks).
+						Post(reqToken(), reqRepoReader(unit.TypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+					m.Get("/network", repo.GetForkNetwork)
+				})
+				m.Group("/fork", func() {
+					m.Get("/compare", reqRepoReader(unit.TypeCode), repo.CompareForkWithRoot)
+					m.Post("/detach", reqToken(), reqOwner(), repo.DetachFork)
+				})
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
@@ -893,6 +916,18 @@ func Routes(ctx gocontext.Context) *web.Route {
 								Get(repo.GetIssueCommentReactions).
 								Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueCommentReaction).
 								Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueCommentReaction)
+							m.Get("/revisions", repo.ListIssueCommentRevisions)
+							m.Combo("/hide", reqToken(), mustNotBeArchived).
+								Put(bind(api.HideIssueCommentOption{}), repo.HideIssueComment).
+								Delete(repo.UnhideIssueComment)
+							m.Group("/assets", func() {
+								m.Combo("").Get(repo.ListIssueCommentAttachments).
+									Post(reqToken(), mustNotBeArchived, repo.CreateIssueCommentAttachment)
+								m.Post("/url", reqToken(), mustNotBeArchived, bind(api.CreateAttachmentFromURLOptions{}), repo.CreateIssueCommentAttachmentFromURL)
+								m.Post("/paste", reqToken(), mustNotBeArchived, repo.PasteIssueCommentAttachment)
+								m.Combo("/{attachment_id}").Get(repo.GetIssueCommentAttachment).
+									Delete(reqToken(), mustNotBeArchived, repo.DeleteIssueCommentAttachment)
+							})
 						})
 					})
 					m.Group("/{index}", func() {
@@ -906,6 +941,13 @@ func Routes(ctx gocontext.Context) *web.Route {
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
 						m.Get("/timeline", repo.ListIssueCommentsAndTimeline)
+						m.Combo("/lock", reqToken(), mustNotBeArchived).
+							Put(bind(api.LockIssueOption{}), repo.LockIssue).
+							Delete(repo.UnlockIssue)
+						m.Group("/tasks", func() {
+							m.Get("", repo.ListIssueTasks)
+							m.Patch("/{task}", reqToken(), mustNotBeArchived, bind(api.EditIssueTaskOption{}), repo.EditIssueTask)
+						})
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
 								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
@@ -929,13 +971,22 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Group("/subscriptions", func() {
 							m.Get("", repo.GetIssueSubscribers)
 							m.Get("/check", reqToken(), repo.CheckIssueSubscription)
-							m.Put("/{user}", reqToken(), repo.AddIssueSubscription)
-							m.Delete("/{user}", reqToken(), repo.DelIssueSubscription)
+							m.Combo("/{user}").Get(repo.GetIssueSubscription).
+								Put(reqToken(), repo.AddIssueSubscription).
+								Delete(reqToken(), repo.DelIssueSubscription)
 						})
 						m.Combo("/reactions").
 							Get(repo.GetIssueReactions).
 							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
 							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
+						m.Group("/assets", func() {
+							m.Combo("").Get(repo.ListIssueAttachments).
+								Post(reqToken(), mustNotBeArchived, repo.CreateIssueAttachment)
+							m.Post("/url", reqToken(), mustNotBeArchived, bind(api.CreateAttachmentFromURLOptions{}), repo.CreateIssueAttachmentFromURL)
+							m.Post("/paste", reqToken(), mustNotBeArchived, repo.PasteIssueAttachment)
+							m.Combo("/{attachment_id}").Get(repo.GetIssueAttachment).
+								Delete(reqToken(), mustNotBeArchived, repo.DeleteIssueAttachment)
+						})
 					})
 				}, mustEnableIssuesOrPulls)
 				m.Group("/labels", func() {
@@ -976,6 +1027,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 								Delete(reqToken(), reqRepoWriter(unit.TypeReleases), repo.DeleteReleaseAttachment)
 						})
 					})
+					m.Get("/compare/{from}/{to}", reqRepoReader(unit.TypeCode), context.ReferencesGitRepo(), repo.CompareReleases)
 					m.Group("/tags", func() {
 						m.Combo("/{tag}").
 							Get(repo.GetReleaseByTag).
@@ -993,15 +1045,23 @@ func Routes(ctx gocontext.Context) *web.Route {
 				}, reqAdmin())
 
 				m.Get("/editorconfig/{filename}", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetEditorconfig)
+				m.Group("/merge_checklist", func() {
+					m.Combo("").Get(repo.ListMergeChecklistItems).
+						Post(reqToken(), reqAdmin(), bind(api.CreateMergeChecklistItemOption{}), repo.CreateMergeChecklistItem)
+					m.Delete("/{id}", reqToken(), reqAdmin(), repo.DeleteMergeChecklistItem)
+				}, mustAllowPulls, reqRepoReader(unit.TypePullRequests))
 				m.Group("/pulls", func() {
 					m.Combo("").Get(repo.ListPullRequests).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+					m.Get("/stats", repo.GetPullReviewStats)
 					m.Group("/{index}", func() {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Get(".{diffType:diff|patch}", repo.DownloadPullDiffOrPatch)
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
+						m.Get("/files", repo.GetPullRequestFiles)
+						m.Get("/files/hunks", repo.GetPullRequestFileHunks)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest).
 							Delete(reqToken(), mustNotBeArchived, repo.CancelScheduledAutoMerge)
@@ -1020,6 +1080,12 @@ func Routes(ctx gocontext.Context) *web.Route {
 								m.Post("/undismissals", reqToken(), repo.UnDismissPullReview)
 							})
 						})
+						m.Group("/checklist", func() {
+							m.Get("", repo.GetPullMergeChecklist)
+							m.Combo("/{id}", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypePullRequests)).
+								Put(repo.CheckPullMergeChecklistItem).
+								Delete(repo.UncheckPullMergeChecklistItem)
+						})
 						m.Combo("/requested_reviewers").
 							Delete(reqToken(), bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(reqToken(), bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
@@ -1058,6 +1124,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Delete("", bind(api.DeleteFileOptions{}), reqRepoBranchWriter, repo.DeleteFile)
 					}, reqToken())
 				}, reqRepoReader(unit.TypeCode))
+				m.Get("/outline/*", context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode), repo.GetFileOutline)
 				m.Get("/signing-key.gpg", misc.SigningKey)
 				m.Group("/topics", func() {
 					m.Combo("").Get(repo.ListTopics).
@@ -1067,22 +1134,70 @@ func Routes(ctx gocontext.Context) *web.Route {
 							Delete(reqToken(), repo.DeleteTopic)
 					}, reqAdmin())
 				}, reqAnyRepoReader())
+				m.Combo("/properties").Get(reqAnyRepoReader(), repo.ListProperties).
+					Patch(reqToken(), reqAdmin(), bind(api.EditRepoPropertiesOption{}), repo.EditProperties)
 				m.Get("/issue_templates", context.ReferencesGitRepo(), repo.GetIssueTemplates)
+				m.Get("/issue_config", context.ReferencesGitRepo(), repo.GetIssueConfig)
 				m.Get("/languages", reqRepoReader(unit.TypeCode), repo.GetLanguages)
+				m.Group("/badges", func() {
+					m.Get("/release", reqRepoReader(unit.TypeReleases), repo.GetReleaseBadge)
+					m.Get("/issues", reqRepoReader(unit.TypeIssues), repo.GetIssuesBadge)
+					m.Get("/build", reqRepoReader(unit.TypeCode), context.ReferencesGitRepo(), repo.GetBuildBadge)
+					m.Get("/license", reqRepoReader(unit.TypeCode), context.ReferencesGitRepo(), repo.GetLicenseBadge)
+					m.Get("/packages/{type}/{name}", reqRepoReader(unit.TypePackages), repo.GetPackageBadge)
+				})
 			}, repoAssignment())
 		})
 
 		m.Group("/packages/{username}", func() {
+			m.Group("/{type}/{name}/access", func() {
+				m.Combo("").Get(packages.GetPackageAccess).
+					Patch(bind(api.EditPackageAccessOption{}), packages.EditPackageAccess)
+				m.Combo("/teams/{team}").Put(bind(api.PackageTeamAccessOption{}), packages.SetPackageTeamAccess).
+					Delete(packages.DeletePackageTeamAccess)
+			}, reqToken(), reqPackageAccess(perm.AccessModeAdmin))
+			m.Group("/{type}/{name}/shares", func() {
+				m.Get("", packages.ListPackageShares)
+				m.Combo("/{org}").Put(packages.SharePackage).
+					Delete(packages.UnsharePackage)
+			}, reqToken(), reqPackageAccess(perm.AccessModeAdmin))
+			m.Get("/blobs/sha256/{sha256:[0-9a-fA-F]{64}}", reqPackageAccess(perm.AccessModeWrite), packages.GetPackageBlob)
+			m.Combo("/container_domain", reqToken(), reqPackageAccess(perm.AccessModeAdmin)).
+				Get(packages.GetPackageContainerDomain).
+				Put(bind(api.SetPackageContainerDomainOption{}), packages.SetPackageContainerDomain).
+				Delete(packages.DeletePackageContainerDomain)
+			m.Group("/vendors", func() {
+				m.Get("", packages.ListPackageVendorGrants)
+				m.Combo("/{vendor}/teams/{team}").Put(packages.AddPackageVendorGrant).
+					Delete(packages.RemovePackageVendorGrant)
+			}, reqToken(), reqPackageAccess(perm.AccessModeAdmin))
+			m.Group("/mirrors", func() {
+				m.Combo("").Get(packages.ListPackageMirrors).
+					Post(bind(api.CreatePackageMirrorOption{}), packages.CreatePackageMirror)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(packages.GetPackageMirror).
+						Patch(bind(api.EditPackageMirrorOption{}), packages.EditPackageMirror).
+						Delete(packages.DeletePackageMirror)
+					m.Post("/sync", packages.SyncPackageMirror)
+				})
+			}, reqToken(), reqPackageAccess(perm.AccessModeAdmin))
+			m.Get("/{type}/{name}/downloads", packages.GetPackageDownloadStats)
+			m.Get("/{type}/{name}/dependents", packages.ListPackageDependents)
+			m.Get("/{type}/{name}/compare/{from}/{to}", packages.ComparePackageVersions)
 			m.Group("/{type}/{name}/{version}", func() {
 				m.Get("", packages.GetPackage)
+				m.Patch("", reqPackageAccess(perm.AccessModeWrite), bind(api.EditPackageOption{}), packages.EditPackage)
 				m.Delete("", reqPackageAccess(perm.AccessModeWrite), packages.DeletePackage)
 				m.Get("/files", packages.ListPackageFiles)
+				m.Get("/downloads", packages.GetPackageVersionDownloadStats)
+				m.Get("/dependencies", packages.ListPackageDependencies)
 			})
 			m.Get("/", packages.ListPackages)
 		}, context_service.UserAssignmentAPI(), context.PackageAssignmentAPI(), reqPackageAccess(perm.AccessModeRead))
 
 		// Organizations
 		m.Get("/user/orgs", reqToken(), org.ListMyOrgs)
+		m.Get("/user/join_requests", reqToken(), org.ListMyJoinRequests)
 		m.Group("/users/{username}/orgs", func() {
 			m.Get("", org.ListUserOrgs)
 			m.Get("/{org}/permissions", reqToken(), org.GetUserOrgsPermissions)
@@ -1095,11 +1210,43 @@ func Routes(ctx gocontext.Context) *web.Route {
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Get("/activities/feeds", org.ListActivityFeeds)
+			m.Get("/issues", repo.ListOrgIssues)
+			m.Get("/pulls", repo.ListOrgPullRequests)
+			m.Get("/pulls/stats", org.GetPullReviewStats)
+			m.Combo("/notifications").
+				Get(reqToken(), notify.ListOrgNotifications).
+				Put(reqToken(), notify.ReadOrgNotifications)
 			m.Group("/members", func() {
 				m.Get("", org.ListMembers)
 				m.Combo("/{username}").Get(org.IsMember).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteMember)
 			})
+			m.Group("/join_requests", func() {
+				m.Combo("").Get(reqOrgOwnership(), org.ListJoinRequests).
+					Post(bind(api.CreateOrgJoinRequestOption{}), org.CreateJoinRequest)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(org.GetJoinRequest).
+						Delete(org.DeleteJoinRequest)
+					m.Post("/approve", reqOrgOwnership(), bind(api.ApproveOrgJoinRequestOption{}), org.ApproveJoinRequest)
+					m.Post("/reject", reqOrgOwnership(), org.RejectJoinRequest)
+				})
+			}, reqToken())
+			m.Group("/service_accounts", func() {
+				m.Combo("").Get(org.ListServiceAccounts).
+					Post(bind(api.CreateServiceAccountOption{}), org.CreateServiceAccount)
+				m.Group("/{username}", func() {
+					m.Combo("").Get(org.GetServiceAccount).
+						Patch(bind(api.EditServiceAccountOption{}), org.EditServiceAccount).
+						Delete(org.DeleteServiceAccount)
+					m.Group("/tokens", func() {
+						m.Combo("").Get(org.ListServiceAccountTokens).
+							Post(bind(api.CreateAccessTokenOption{}), org.CreateServiceAccountToken)
+						m.Delete("/{id}", org.DeleteServiceAccountToken)
+						m.Post("/{id}/rotate", org.RotateServiceAccountToken)
+					})
+				})
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/{username}").Get(org.IsPublicMember).
@@ -1118,9 +1265,27 @@ func Routes(ctx gocontext.Context) *web.Route {
 					Patch(reqToken(), reqOrgOwnership(), bind(api.EditLabelOption{}), org.EditLabel).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteLabel)
 			})
+			m.Group("/properties", func() {
+				m.Combo("").Get(org.ListPropertyDefinitions).
+					Post(reqOrgOwnership(), bind(api.CreatePropertyDefinitionOption{}), org.CreatePropertyDefinition)
+				m.Combo("/{name}").Get(org.GetPropertyDefinition).
+					Patch(reqOrgOwnership(), bind(api.EditPropertyDefinitionOption{}), org.EditPropertyDefinition).
+					Delete(reqOrgOwnership(), org.DeletePropertyDefinition)
+			}, reqToken(), reqOrgMembership())
+			m.Combo("/repo_policy", reqToken(), reqOrgMembership()).Get(org.GetRepoPolicy).
+				Put(reqOrgOwnership(), bind(api.SetRepoPolicyOption{}), org.SetRepoPolicy).
+				Delete(reqOrgOwnership(), org.DeleteRepoPolicy)
 			m.Group("/hooks", func() {
 				m.Combo("").Get(org.ListHooks).
 					Post(bind(api.CreateHookOption{}), org.CreateHook)
+				m.Group("/defaults", func() {
+					m.Combo("").Get(org.ListDefaultHooks).
+						Post(bind(api.CreateHookOption{}), org.CreateDefaultHook)
+					m.Combo("/{id}").Get(org.GetDefaultHook).
+						Patch(bind(api.EditHookOption{}), org.EditDefaultHook).
+						Delete(org.DeleteDefaultHook)
+					m.Post("/{id}/apply", bind(api.ApplyDefaultHookOption{}), org.ApplyDefaultHook)
+				})
 				m.Combo("/{id}").Get(org.GetHook).
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
@@ -1147,14 +1312,50 @@ func Routes(ctx gocontext.Context) *web.Route {
 		}, orgAssignment(false, true), reqToken(), reqTeamMembership())
 
 		m.Group("/admin", func() {
+			m.Group("/auth_sources", func() {
+				m.Get("", admin.ListAuthSources)
+				m.Get("/{id}", admin.GetAuthSource)
+				m.Post("/{id}/check_health", admin.CheckAuthSourceHealth)
+			})
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/{task}", admin.PostCronTask)
 			})
+			m.Get("/collaborations", admin.ListAllCollaborations)
+			m.Get("/usage", admin.GetUsage)
+			m.Group("/diagnostics", func() {
+				m.Get("/requests", admin.ListDiagnosticRequests)
+				m.Get("/slow_queries", admin.ListDiagnosticSlowQueries)
+			})
+			m.Group("/federation/instances", func() {
+				m.Get("", admin.ListFederatedInstances)
+				m.Combo("/{host}").Put(bind(api.SetFederatedInstanceOption{}), admin.SetFederatedInstance).
+					Delete(admin.DeleteFederatedInstance)
+			})
+			m.Group("/hooks/defaults", func() {
+				m.Combo("").Get(admin.ListDefaultHooks).
+					Post(bind(api.CreateHookOption{}), admin.CreateDefaultHook)
+				m.Combo("/{id}").Get(admin.GetDefaultHook).
+					Patch(bind(api.EditHookOption{}), admin.EditDefaultHook).
+					Delete(admin.DeleteDefaultHook)
+				m.Post("/{id}/apply", bind(api.ApplyDefaultHookOption{}), admin.ApplyDefaultHook)
+			}, reqWebhooksEnabled())
 			m.Get("/orgs", admin.GetAllOrgs)
+			m.Group("/name_policies", func() {
+				m.Combo("").Get(admin.ListNamePolicies).
+					Post(bind(api.CreateNamePolicyOption{}), admin.CreateNamePolicy)
+				m.Delete("/{id}", admin.DeleteNamePolicy)
+			})
+			m.Group("/name_claims", func() {
+				m.Combo("").Get(admin.ListNameClaims).
+					Post(bind(api.CreateNameClaimOption{}), admin.CreateNameClaim)
+				m.Delete("/{id}", admin.CancelNameClaim)
+			})
+			m.Get("/packages/audit", admin.ListPackageAuditLogs)
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Post("/bulk", bind(api.AdminBulkUsersOption{}), admin.BulkUsers)
 				m.Group("/{username}", func() {
 					m.Combo("").Patch(bind(api.EditUserOption{}), admin.EditUser).
 						Delete(admin.DeleteUser)
@@ -1162,11 +1363,17 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Post("", bind(api.CreateKeyOption{}), admin.CreatePublicKey)
 						m.Delete("/{id}", admin.DeleteUserPublicKey)
 					})
+					m.Post("/gpg_keys", bind(api.CreateGPGKeyOption{}), admin.CreateGPGKey)
+					m.Post("/tokens", bind(api.CreateAccessTokenOption{}), admin.CreateAccessToken)
+					m.Post("/2fa/recovery", admin.CreateTwoFactorRecovery)
 					m.Get("/orgs", org.ListUserOrgs)
 					m.Post("/orgs", bind(api.CreateOrgOption{}), admin.CreateOrg)
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				}, context_service.UserAssignmentAPI())
 			})
+			m.Combo("/repos/stats/recalculation").Get(admin.GetRepoStatsRecalculation).
+				Post(bind(api.RecalculateRepoStatsOption{}), admin.RecalculateRepoStats).
+				Delete(admin.CancelRepoStatsRecalculation)
 			m.Group("/unadopted", func() {
 				m.Get("", admin.ListUnadoptedRepositories)
 				m.Post("/{username}/{reponame}", admin.AdoptRepository)
@@ -0,0 +1,90 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	search_service "code.gitea.io/gitea/services/search"
+)
+
+// Search searches repositories, code, issues, users and packages at once
+func Search(ctx *context.APIContext) {
+	// swagger:operation GET /search miscellaneous search
+	// ---
+	// summary: Search repositories, code, issues, users and packages
+	// description: The pagination applies to every searched type, the Link header pages through the type with the most results.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: types to search, all types if empty. Code and packages are only searched if enabled.
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [repo, code, issue, user, package]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GlobalSearchResults"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := ctx.FormTrim("q")
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "keyword is required")
+		return
+	}
+
+	types, err := search_service.ParseTypes(ctx.FormStrings("type"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	results, err := search_service.Search(ctx, &search_service.Options{
+		ListOptions: listOptions,
+		Doer:        ctx.Doer,
+		Keyword:     keyword,
+		Types:       types,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Search", err)
+		return
+	}
+
+	apiResults, err := convert.ToGlobalSearchResults(ctx, results, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToGlobalSearchResults", err)
+		return
+	}
+
+	var maxCount int64
+	for _, count := range results.Counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	ctx.SetLinkHeader(int(maxCount), listOptions.PageSize)
+	ctx.JSON(http.StatusOK, apiResults)
+}
@@ -11,8 +11,10 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	webhook_service "code.gitea.io/gitea/services/webhook"
 )
 
 // ListHooks list an organziation's webhooks
@@ -43,6 +45,7 @@ func ListHooks(ctx *context.APIContext) {
 	opts := &webhook.ListWebhookOptions{
 		ListOptions: utils.GetListOptions(ctx),
 		OrgID:       ctx.Org.Organization.ID,
+		IsDefault:   util.OptionalBoolFalse,
 	}
 
 	count, err := webhook.CountWebhooksByOpts(opts)
@@ -191,6 +194,201 @@ func DeleteHook(ctx *context.APIContext) {
 
 	org := ctx.Org.Organization
 	hookID := ctx.ParamsInt64(":id")
+	if _, err := utils.GetOrgHook(ctx, org.ID, hookID); err != nil {
+		return
+	}
+	if err := webhook.DeleteWebhookByOrgID(org.ID, hookID); err != nil {
+		if webhook.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteWebhookByOrgID", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDefaultHooks list an organization's default webhooks
+func ListDefaultHooks(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/hooks/defaults organization orgListDefaultHooks
+	// ---
+	// summary: List an organization's default webhooks which are copied onto its new repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookList"
+
+	opts := &webhook.ListWebhookOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OrgID:       ctx.Org.Organization.ID,
+		IsDefault:   util.OptionalBoolTrue,
+	}
+
+	count, err := webhook.CountWebhooksByOpts(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	orgHooks, err := webhook.ListWebhooksByOpts(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	hooks := make([]*api.Hook, len(orgHooks))
+	for i, hook := range orgHooks {
+		hooks[i] = convert.ToHook(ctx.Org.Organization.AsUser().HomeLink(), hook)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, hooks)
+}
+
+// GetDefaultHook get an organization's default hook by id
+func GetDefaultHook(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/hooks/defaults/{id} organization orgGetDefaultHook
+	// ---
+	// summary: Get a default hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	org := ctx.Org.Organization
+	hook, err := utils.GetOrgDefaultHook(ctx, org.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(org.AsUser().HomeLink(), hook))
+}
+
+// CreateDefaultHook create a default hook for an organization
+func CreateDefaultHook(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/defaults organization orgCreateDefaultHook
+	// ---
+	// summary: Create a default hook which is copied onto new repositories of the organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Hook"
+
+	form := web.GetForm(ctx).(*api.CreateHookOption)
+	if !utils.CheckCreateHookOption(ctx, form) {
+		return
+	}
+	utils.AddOrgDefaultHook(ctx, form)
+}
+
+// EditDefaultHook modify a default hook of an organization
+func EditDefaultHook(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/hooks/defaults/{id} organization orgEditDefaultHook
+	// ---
+	// summary: Update a default hook, existing copies of the hook are not changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook to update
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.EditHookOption)
+	utils.EditOrgDefaultHook(ctx, form, ctx.ParamsInt64(":id"))
+}
+
+// DeleteDefaultHook delete a default hook of an organization
+func DeleteDefaultHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/hooks/defaults/{id} organization orgDeleteDefaultHook
+	// ---
+	// summary: Delete a default hook, existing copies of the hook are kept
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	org := ctx.Org.Organization
+	hookID := ctx.ParamsInt64(":id")
+	if _, err := utils.GetOrgDefaultHook(ctx, org.ID, hookID); err != nil {
+		return
+	}
 	if err := webhook.DeleteWebhookByOrgID(org.ID, hookID); err != nil {
 		if webhook.IsErrWebhookNotExist(err) {
 			ctx.NotFound()
@@ -201,3 +399,50 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// ApplyDefaultHook copy a default hook onto the existing repositories of an organization
+func ApplyDefaultHook(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/defaults/{id}/apply organization orgApplyDefaultHook
+	// ---
+	// summary: Copy a default hook onto the existing repositories of the organization which don't have a copy yet
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook to apply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ApplyDefaultHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ApplyDefaultHookResult"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ApplyDefaultHookOption)
+
+	hook, err := utils.GetOrgDefaultHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	applied, err := webhook_service.ApplyDefaultWebhook(ctx, hook, form.Pattern)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ApplyDefaultWebhook", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ApplyDefaultHookResult{Applied: applied})
+}
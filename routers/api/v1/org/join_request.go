@@ -0,0 +1,462 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	org_service "code.gitea.io/gitea/services/org"
+)
+
+// parseJoinRequestStatuses parses the optional "status" filter
+func parseJoinRequestStatuses(ctx *context.APIContext) []organization.JoinRequestStatus {
+	switch ctx.FormTrim("status") {
+	case "":
+		return nil
+	case "pending":
+		return []organization.JoinRequestStatus{organization.JoinRequestStatusPending}
+	case "approved":
+		return []organization.JoinRequestStatus{organization.JoinRequestStatusApproved}
+	case "rejected":
+		return []organization.JoinRequestStatus{organization.JoinRequestStatusRejected}
+	}
+	ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+	return nil
+}
+
+func writeJoinRequests(ctx *context.APIContext, jrs []*organization.JoinRequest, count int64) {
+	orgs := make(map[int64]*organization.Organization)
+	if ctx.Org.Organization != nil {
+		orgs[ctx.Org.Organization.ID] = ctx.Org.Organization
+	}
+
+	apiJoinRequests := make([]*api.OrgJoinRequest, 0, len(jrs))
+	for _, jr := range jrs {
+		org, ok := orgs[jr.OrgID]
+		if !ok {
+			var err error
+			org, err = organization.GetOrgByID(ctx, jr.OrgID)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetOrgByID", err)
+				return
+			}
+			orgs[jr.OrgID] = org
+		}
+
+		apiJoinRequest, err := convert.ToOrgJoinRequest(ctx, jr, org, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToOrgJoinRequest", err)
+			return
+		}
+		apiJoinRequests = append(apiJoinRequests, apiJoinRequest)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiJoinRequests)
+}
+
+// ListJoinRequests lists the join requests of an organization
+func ListJoinRequests(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/join_requests organization orgListJoinRequests
+	// ---
+	// summary: List the join requests of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: status
+	//   in: query
+	//   description: only list requests with this status
+	//   type: string
+	//   enum: [pending, approved, rejected]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgJoinRequestList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	statuses := parseJoinRequestStatuses(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	jrs, count, err := organization.FindJoinRequests(ctx, &organization.FindJoinRequestsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OrgID:       ctx.Org.Organization.ID,
+		Statuses:    statuses,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindJoinRequests", err)
+		return
+	}
+
+	writeJoinRequests(ctx, jrs, count)
+}
+
+// ListMyJoinRequests lists the join requests of the authenticated user
+func ListMyJoinRequests(ctx *context.APIContext) {
+	// swagger:operation GET /user/join_requests organization orgListCurrentUserJoinRequests
+	// ---
+	// summary: List the organization join requests of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: only list requests with this status
+	//   type: string
+	//   enum: [pending, approved, rejected]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgJoinRequestList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	statuses := parseJoinRequestStatuses(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	jrs, count, err := organization.FindJoinRequests(ctx, &organization.FindJoinRequestsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		UserID:      ctx.Doer.ID,
+		Statuses:    statuses,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindJoinRequests", err)
+		return
+	}
+
+	writeJoinRequests(ctx, jrs, count)
+}
+
+// CreateJoinRequest requests to join an organization or one of its teams
+func CreateJoinRequest(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/join_requests organization orgCreateJoinRequest
+	// ---
+	// summary: Request to join an organization or one of its teams
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrgJoinRequestOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/OrgJoinRequest"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateOrgJoinRequestOption)
+
+	if !organization.HasOrgOrUserVisible(ctx, ctx.Org.Organization.AsUser(), ctx.Doer) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	var team *organization.Team
+	if form.Team != "" {
+		var err error
+		team, err = organization.GetTeam(ctx, ctx.Org.Organization.ID, form.Team)
+		if err != nil {
+			if organization.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+			}
+			return
+		}
+	}
+
+	jr, err := org_service.RequestToJoin(ctx, ctx.Doer, ctx.Org.Organization, team, form.Message)
+	if err != nil {
+		if errors.Is(err, org_service.ErrAlreadyMember) || organization.IsErrJoinRequestAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RequestToJoin", err)
+		}
+		return
+	}
+
+	apiJoinRequest, err := convert.ToOrgJoinRequest(ctx, jr, ctx.Org.Organization, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToOrgJoinRequest", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiJoinRequest)
+}
+
+// getJoinRequestByParams loads the join request which must be readable by the requester or an owner
+func getJoinRequestByParams(ctx *context.APIContext) *organization.JoinRequest {
+	jr, err := organization.GetJoinRequestByID(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if organization.IsErrJoinRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetJoinRequestByID", err)
+		}
+		return nil
+	}
+
+	if jr.UserID == ctx.Doer.ID || ctx.Doer.IsAdmin {
+		return jr
+	}
+
+	isOwner, err := organization.IsOrganizationOwner(ctx, ctx.Org.Organization.ID, ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsOrganizationOwner", err)
+		return nil
+	}
+	if !isOwner {
+		ctx.NotFound()
+		return nil
+	}
+	return jr
+}
+
+// GetJoinRequest gets a join request of an organization
+func GetJoinRequest(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/join_requests/{id} organization orgGetJoinRequest
+	// ---
+	// summary: Get a join request of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the join request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgJoinRequest"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	jr := getJoinRequestByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	apiJoinRequest, err := convert.ToOrgJoinRequest(ctx, jr, ctx.Org.Organization, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToOrgJoinRequest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiJoinRequest)
+}
+
+// DeleteJoinRequest withdraws a join request
+func DeleteJoinRequest(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/join_requests/{id} organization orgDeleteJoinRequest
+	// ---
+	// summary: Withdraw a pending join request or delete a reviewed one
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the join request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	jr := getJoinRequestByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := organization.DeleteJoinRequestByID(ctx, jr.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteJoinRequestByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ApproveJoinRequest approves a join request and adds the user to the team
+func ApproveJoinRequest(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/join_requests/{id}/approve organization orgApproveJoinRequest
+	// ---
+	// summary: Approve a join request and add the user to the team
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the join request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ApproveOrgJoinRequestOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgJoinRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ApproveOrgJoinRequestOption)
+
+	jr := getJoinRequestByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var team *organization.Team
+	if form.Team != "" {
+		var err error
+		team, err = organization.GetTeam(ctx, ctx.Org.Organization.ID, form.Team)
+		if err != nil {
+			if organization.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+			}
+			return
+		}
+	}
+
+	if err := org_service.ApproveJoinRequest(ctx, ctx.Doer, ctx.Org.Organization, jr, team); err != nil {
+		switch {
+		case errors.Is(err, org_service.ErrJoinRequestReviewed):
+			ctx.Error(http.StatusConflict, "", err)
+		case errors.Is(err, org_service.ErrJoinRequestTeamRequired):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "ApproveJoinRequest", err)
+		}
+		return
+	}
+
+	apiJoinRequest, err := convert.ToOrgJoinRequest(ctx, jr, ctx.Org.Organization, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToOrgJoinRequest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiJoinRequest)
+}
+
+// RejectJoinRequest rejects a join request
+func RejectJoinRequest(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/join_requests/{id}/reject organization orgRejectJoinRequest
+	// ---
+	// summary: Reject a join request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the join request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgJoinRequest"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	jr := getJoinRequestByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := org_service.RejectJoinRequest(ctx, ctx.Doer, ctx.Org.Organization, jr); err != nil {
+		if errors.Is(err, org_service.ErrJoinRequestReviewed) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RejectJoinRequest", err)
+		}
+		return
+	}
+
+	apiJoinRequest, err := convert.ToOrgJoinRequest(ctx, jr, ctx.Org.Organization, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToOrgJoinRequest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiJoinRequest)
+}
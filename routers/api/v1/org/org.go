@@ -6,8 +6,10 @@
 package org
 
 import (
+	"fmt"
 	"net/http"
 
+	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
@@ -371,3 +373,97 @@ func Delete(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListActivityFeeds lists the activities of the organization's repositories
+func ListActivityFeeds(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/activities/feeds organization orgListActivityFeeds
+	// ---
+	// summary: List the activities of all repositories of an organization which are visible to the user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the org
+	//   type: string
+	//   required: true
+	// - name: op_type
+	//   in: query
+	//   description: "filter activities by type, e.g. create_repo, commit_repo or create_issue"
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	// - name: since
+	//   in: query
+	//   description: Only show activities created after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only show activities created before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActivityFeedsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !organization.HasOrgOrUserVisible(ctx, ctx.Org.Organization.AsUser(), ctx.Doer) {
+		ctx.NotFound("HasOrgOrUserVisible", nil)
+		return
+	}
+
+	before, since, err := context.GetQueryBeforeSince(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	opTypes := make([]activities_model.ActionType, 0, 2)
+	for _, name := range ctx.FormStrings("op_type") {
+		opType, ok := activities_model.ActionTypeFromString(name)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "ActionTypeFromString", fmt.Errorf("unknown activity type: %s", name))
+			return
+		}
+		opTypes = append(opTypes, opType)
+	}
+
+	// repositories the user can't access are excluded by GetFeeds
+	opts := activities_model.GetFeedsOptions{
+		ListOptions:    utils.GetListOptions(ctx),
+		RequestedUser:  ctx.Org.Organization.AsUser(),
+		Actor:          ctx.Doer,
+		IncludePrivate: ctx.IsSigned,
+		OpTypes:        opTypes,
+		CreatedAfter:   since,
+		CreatedBefore:  before,
+	}
+
+	count, err := activities_model.CountFeeds(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountFeeds", err)
+		return
+	}
+
+	feeds, err := activities_model.GetFeeds(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFeeds", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, convert.ToActivities(ctx, feeds, ctx.Doer))
+}
@@ -0,0 +1,257 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListPropertyDefinitions list the custom repository properties defined by an organization
+func ListPropertyDefinitions(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/properties organization orgListPropertyDefinitions
+	// ---
+	// summary: List the custom repository properties defined by an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PropertyDefinitionList"
+
+	defs, err := repo_model.GetPropertyDefinitions(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPropertyDefinitions", err)
+		return
+	}
+
+	result := make([]*api.PropertyDefinition, 0, len(defs))
+	for _, def := range defs {
+		result = append(result, convert.ToPropertyDefinition(def))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreatePropertyDefinition define a custom repository property for an organization
+func CreatePropertyDefinition(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/properties organization orgCreatePropertyDefinition
+	// ---
+	// summary: Define a custom repository property for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePropertyDefinitionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PropertyDefinition"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePropertyDefinitionOption)
+	name := strings.ToLower(strings.TrimSpace(form.Name))
+	if !repo_model.ValidatePropertyName(name) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid property name: %s", form.Name))
+		return
+	}
+	allowedValues, ok := sanitizeAllowedValues(ctx, form.AllowedValues)
+	if !ok {
+		return
+	}
+
+	def := &repo_model.PropertyDefinition{
+		OwnerID:       ctx.Org.Organization.ID,
+		Name:          name,
+		Description:   form.Description,
+		AllowedValues: allowedValues,
+	}
+	if err := repo_model.CreatePropertyDefinition(ctx, def); err != nil {
+		if repo_model.IsErrPropertyDefinitionAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreatePropertyDefinition", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToPropertyDefinition(def))
+}
+
+// GetPropertyDefinition get a custom repository property defined by an organization
+func GetPropertyDefinition(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/properties/{name} organization orgGetPropertyDefinition
+	// ---
+	// summary: Get a custom repository property defined by an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the property
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PropertyDefinition"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	def := getPropertyDefinitionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPropertyDefinition(def))
+}
+
+// EditPropertyDefinition edit a custom repository property defined by an organization
+func EditPropertyDefinition(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/properties/{name} organization orgEditPropertyDefinition
+	// ---
+	// summary: Edit a custom repository property defined by an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the property
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPropertyDefinitionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PropertyDefinition"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPropertyDefinitionOption)
+	def := getPropertyDefinitionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Description != nil {
+		def.Description = *form.Description
+	}
+	if form.AllowedValues != nil {
+		allowedValues, ok := sanitizeAllowedValues(ctx, form.AllowedValues)
+		if !ok {
+			return
+		}
+		def.AllowedValues = allowedValues
+	}
+	if err := repo_model.UpdatePropertyDefinition(ctx, def); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdatePropertyDefinition", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPropertyDefinition(def))
+}
+
+// DeletePropertyDefinition delete a custom repository property defined by an organization
+func DeletePropertyDefinition(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/properties/{name} organization orgDeletePropertyDefinition
+	// ---
+	// summary: Delete a custom repository property and its values from all repositories of the organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the property
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	def := getPropertyDefinitionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_model.DeletePropertyDefinition(ctx, def); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeletePropertyDefinition", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getPropertyDefinitionByParams(ctx *context.APIContext) *repo_model.PropertyDefinition {
+	def, err := repo_model.GetPropertyDefinition(ctx, ctx.Org.Organization.ID, strings.ToLower(ctx.Params(":name")))
+	if err != nil {
+		if repo_model.IsErrPropertyDefinitionNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPropertyDefinition", err)
+		}
+		return nil
+	}
+	return def
+}
+
+// sanitizeAllowedValues trims and deduplicates the allowed values of a property definition.
+// If a value is invalid, write to `ctx` accordingly
+func sanitizeAllowedValues(ctx *context.APIContext, values []string) ([]string, bool) {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || len(value) > repo_model.MaxPropertyValueLength {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid allowed value: %q", value))
+			return nil, false
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result, true
+}
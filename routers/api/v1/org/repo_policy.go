@@ -0,0 +1,142 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// GetRepoPolicy get the repository policy of an organization
+func GetRepoPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repo_policy organization orgGetRepoPolicy
+	// ---
+	// summary: Get the requirements of an organization for the creation of its repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p, err := organization.GetRepoPolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		if organization.IsErrRepoPolicyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoPolicy", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPolicy(p))
+}
+
+// SetRepoPolicy set the repository policy of an organization
+func SetRepoPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/repo_policy organization orgSetRepoPolicy
+	// ---
+	// summary: Set the requirements of an organization for the creation of its repositories
+	// description: The policy is checked when a repository is created, existing repositories are not affected.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetRepoPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPolicy"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetRepoPolicyOption)
+
+	namePattern := strings.TrimSpace(form.NamePattern)
+	if namePattern != "" {
+		if _, err := organization.CompileRepoPolicyNamePattern(namePattern); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid name pattern: %w", err))
+			return
+		}
+	}
+
+	requiredProperties := make([]string, 0, len(form.RequiredProperties))
+	seen := make(map[string]struct{}, len(form.RequiredProperties))
+	for _, name := range form.RequiredProperties {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if _, err := repo_model.GetPropertyDefinition(ctx, ctx.Org.Organization.ID, name); err != nil {
+			if repo_model.IsErrPropertyDefinitionNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("the organization does not define the property %q", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetPropertyDefinition", err)
+			}
+			return
+		}
+		seen[name] = struct{}{}
+		requiredProperties = append(requiredProperties, name)
+	}
+
+	p := &organization.RepoPolicy{
+		OrgID:                  ctx.Org.Organization.ID,
+		NamePattern:            namePattern,
+		NamePatternDescription: strings.TrimSpace(form.NamePatternDescription),
+		RequireTopic:           form.RequireTopic,
+		RequiredProperties:     requiredProperties,
+	}
+	if err := organization.SetRepoPolicy(ctx, p); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetRepoPolicy", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToRepoPolicy(p))
+}
+
+// DeleteRepoPolicy delete the repository policy of an organization
+func DeleteRepoPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/repo_policy organization orgDeleteRepoPolicy
+	// ---
+	// summary: Delete the requirements of an organization for the creation of its repositories
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+
+	if err := organization.DeleteRepoPolicy(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoPolicy", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
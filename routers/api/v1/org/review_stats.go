@@ -0,0 +1,53 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+
+	"xorm.io/builder"
+)
+
+// GetPullReviewStats gets the review statistics of the pull requests of the organization's repositories
+func GetPullReviewStats(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/pulls/stats organization orgGetPullReviewStats
+	// ---
+	// summary: Get the review statistics of the pull requests of all repositories of an organization which are visible to the user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the org
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: first day of the statistics (date or RFC3339 timestamp, defaults to 29 days before until)
+	//   type: string
+	// - name: until
+	//   in: query
+	//   description: last day of the statistics (date or RFC3339 timestamp, defaults to today)
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ServeReviewStats(ctx, issues_model.ReviewStatsOptions{
+		RepoCond: builder.And(
+			builder.Eq{"`repository`.owner_id": ctx.Org.Organization.ID},
+			builder.In("`repository`.id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": unit.TypePullRequests})),
+			repo_model.AccessibleRepositoryCondition(ctx.Doer, unit.TypePullRequests),
+		),
+	})
+}
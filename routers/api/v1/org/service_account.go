@@ -0,0 +1,490 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/user"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	org_service "code.gitea.io/gitea/services/org"
+)
+
+// getServiceAccount loads the service account of the organization named in the path
+func getServiceAccount(ctx *context.APIContext) *user_model.User {
+	u, err := organization.GetServiceAccount(ctx, ctx.Org.Organization.ID, ctx.Params(":username"))
+	if err != nil {
+		if organization.IsErrServiceAccountNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetServiceAccount", err)
+		}
+		return nil
+	}
+	return u
+}
+
+// ListServiceAccounts lists the service accounts of an organization
+func ListServiceAccounts(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/service_accounts organization orgListServiceAccounts
+	// ---
+	// summary: List the service accounts of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ServiceAccountList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listOptions := utils.GetListOptions(ctx)
+	users, count, err := organization.GetServiceAccounts(ctx, ctx.Org.Organization.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetServiceAccounts", err)
+		return
+	}
+
+	apiAccounts := make([]*api.ServiceAccount, 0, len(users))
+	for _, u := range users {
+		apiAccounts = append(apiAccounts, convert.ToServiceAccount(u))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiAccounts)
+}
+
+// CreateServiceAccount creates a service account owned by an organization
+func CreateServiceAccount(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/service_accounts organization orgCreateServiceAccount
+	// ---
+	// summary: Create a service account owned by an organization
+	// description: A service account can't sign in interactively, it authenticates with access tokens only.
+	//   Add it to teams of the organization to grant access to repositories.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateServiceAccountOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ServiceAccount"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateServiceAccountOption)
+
+	u := &user_model.User{
+		Name:        form.Username,
+		FullName:    form.FullName,
+		Description: form.Description,
+	}
+	if err := org_service.CreateServiceAccount(ctx, ctx.Org.Organization, ctx.Doer, u); err != nil {
+		if user_model.IsErrUserAlreadyExist(err) ||
+			user_model.IsErrEmailAlreadyUsed(err) ||
+			db.IsErrNameReserved(err) ||
+			db.IsErrNameCharsNotAllowed(err) ||
+			user_model.IsErrEmailCharIsNotSupported(err) ||
+			user_model.IsErrEmailInvalid(err) ||
+			db.IsErrNamePatternNotAllowed(err) ||
+			user_model.IsErrServiceAccountPrefixRequired(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateServiceAccount", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToServiceAccount(u))
+}
+
+// GetServiceAccount gets a service account of an organization
+func GetServiceAccount(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/service_accounts/{username} organization orgGetServiceAccount
+	// ---
+	// summary: Get a service account of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ServiceAccount"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToServiceAccount(u))
+}
+
+// EditServiceAccount edits or disables a service account of an organization
+func EditServiceAccount(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/service_accounts/{username} organization orgEditServiceAccount
+	// ---
+	// summary: Edit or disable a service account of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditServiceAccountOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ServiceAccount"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditServiceAccountOption)
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.FullName != nil {
+		u.FullName = *form.FullName
+	}
+	if form.Description != nil {
+		u.Description = *form.Description
+	}
+	if form.Disabled != nil {
+		u.ProhibitLogin = *form.Disabled
+	}
+	if err := user_model.UpdateUserCols(ctx, u, "full_name", "description", "prohibit_login"); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateUserCols", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToServiceAccount(u))
+}
+
+// DeleteServiceAccount deletes a service account of an organization
+func DeleteServiceAccount(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/service_accounts/{username} organization orgDeleteServiceAccount
+	// ---
+	// summary: Delete a service account of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := org_service.DeleteServiceAccount(ctx, u); err != nil {
+		if models.IsErrUserOwnRepos(err) ||
+			organization.IsErrLastOrgOwner(err) ||
+			models.IsErrUserOwnPackages(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteServiceAccount", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListServiceAccountTokens lists the access tokens of a service account
+func ListServiceAccountTokens(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/service_accounts/{username}/tokens organization orgListServiceAccountTokens
+	// ---
+	// summary: List the access tokens of a service account
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AccessTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	opts := auth_model.ListAccessTokensOptions{UserID: u.ID, ListOptions: utils.GetListOptions(ctx)}
+	count, err := auth_model.CountAccessTokens(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	tokens, err := auth_model.ListAccessTokens(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiTokens := make([]*api.AccessToken, len(tokens))
+	for i := range tokens {
+		apiTokens[i] = &api.AccessToken{
+			ID:             tokens[i].ID,
+			Name:           tokens[i].Name,
+			TokenLastEight: tokens[i].TokenLastEight,
+			Scope:          string(tokens[i].Scope),
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiTokens)
+}
+
+// CreateServiceAccountToken creates an access token for a service account
+func CreateServiceAccountToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/service_accounts/{username}/tokens organization orgCreateServiceAccountToken
+	// ---
+	// summary: Create an access token for a service account
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAccessTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/AccessToken"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.CreateAccessTokenOption)
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+	user.CreateUserAccessToken(ctx, *form, u.ID)
+}
+
+// RotateServiceAccountToken replaces the secret of an access token of a service account
+func RotateServiceAccountToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/service_accounts/{username}/tokens/{id}/rotate organization orgRotateServiceAccountToken
+	// ---
+	// summary: Replace the secret of an access token of a service account
+	// description: The previous secret stops working immediately, name and scope of the token are kept.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the access token
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AccessToken"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	t, err := auth_model.GetAccessTokenByID(ctx.ParamsInt64(":id"), u.ID)
+	if err != nil {
+		if auth_model.IsErrAccessTokenNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAccessTokenByID", err)
+		}
+		return
+	}
+	if err := auth_model.RegenerateAccessToken(t); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RegenerateAccessToken", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.AccessToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		Token:          t.Token,
+		TokenLastEight: t.TokenLastEight,
+		Scope:          string(t.Scope),
+	})
+}
+
+// DeleteServiceAccountToken deletes an access token of a service account
+func DeleteServiceAccountToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/service_accounts/{username}/tokens/{id} organization orgDeleteServiceAccountToken
+	// ---
+	// summary: Delete an access token of a service account
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: name of the service account
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the access token
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := getServiceAccount(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := auth_model.DeleteAccessTokenByID(ctx.ParamsInt64(":id"), u.ID); err != nil {
+		if auth_model.IsErrAccessTokenNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteAccessTokenByID", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
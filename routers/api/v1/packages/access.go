@@ -0,0 +1,311 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// recordPermissionAudit adds a change of the access settings of the package to the audit log
+func recordPermissionAudit(ctx *context.APIContext, p *packages_model.Package, detail string) {
+	packages_service.RecordPackageAudit(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, packages_model.AuditActionPermission, p, detail)
+}
+
+func getPackage(ctx *context.APIContext) *packages_model.Package {
+	p, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.Type(ctx.Params("type")), ctx.Params("name"))
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return nil
+	}
+	return p
+}
+
+func getPackageTeam(ctx *context.APIContext) *organization.Team {
+	if !ctx.Package.Owner.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is not an organization", ctx.Package.Owner.Name))
+		return nil
+	}
+	team, err := organization.GetTeam(ctx, ctx.Package.Owner.ID, ctx.Params("team"))
+	if err != nil {
+		if organization.IsErrTeamNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+		}
+		return nil
+	}
+	return team
+}
+
+func toPackageAccess(ctx *context.APIContext, p *packages_model.Package) (*api.PackageAccess, error) {
+	pts, err := packages_model.GetPackageTeams(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := &api.PackageAccess{
+		Visibility: p.Visibility.String(),
+		Teams:      make([]*api.PackageTeamAccess, 0, len(pts)),
+	}
+	for _, pt := range pts {
+		team, err := organization.GetTeamByID(ctx, pt.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		apiTeam, err := convert.ToTeam(team)
+		if err != nil {
+			return nil, err
+		}
+		access.Teams = append(access.Teams, &api.PackageTeamAccess{
+			Team:       apiTeam,
+			Permission: pt.AccessMode.String(),
+		})
+	}
+	return access, nil
+}
+
+// GetPackageAccess gets the access settings of a package
+func GetPackageAccess(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/access package getPackageAccess
+	// ---
+	// summary: Gets the access settings of a package
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAccess"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	access, err := toPackageAccess(ctx, p)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toPackageAccess", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, access)
+}
+
+// EditPackageAccess changes the visibility of a package
+func EditPackageAccess(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/{type}/{name}/access package editPackageAccess
+	// ---
+	// summary: Changes the visibility of a package
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageAccessOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAccess"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPackageAccessOption)
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	visibility, ok := packages_model.VisibilityFromString(form.Visibility)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid visibility: %s", form.Visibility))
+		return
+	}
+
+	if err := packages_model.SetPackageVisibility(ctx, p.ID, visibility); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetPackageVisibility", err)
+		return
+	}
+	p.Visibility = visibility
+	recordPermissionAudit(ctx, p, "visibility: "+visibility.String())
+
+	access, err := toPackageAccess(ctx, p)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toPackageAccess", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, access)
+}
+
+// SetPackageTeamAccess grants a team access to a package
+func SetPackageTeamAccess(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/{type}/{name}/access/teams/{team} package setPackageTeamAccess
+	// ---
+	// summary: Grants a team of the owning organization access to a package
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: path
+	//   description: name of the team
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/PackageTeamAccessOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.PackageTeamAccessOption)
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+	team := getPackageTeam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	mode := perm.ParseAccessMode(form.Permission)
+	if mode != perm.AccessModeRead && mode != perm.AccessModeWrite {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid permission: %s", form.Permission))
+		return
+	}
+
+	if err := packages_model.SetPackageTeam(ctx, p.ID, team.ID, mode); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetPackageTeam", err)
+		return
+	}
+	recordPermissionAudit(ctx, p, fmt.Sprintf("team %s: %s", team.Name, mode))
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeletePackageTeamAccess revokes the access of a team to a package
+func DeletePackageTeamAccess(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name}/access/teams/{team} package deletePackageTeamAccess
+	// ---
+	// summary: Revokes the access of a team to a package
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: path
+	//   description: name of the team
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+	team := getPackageTeam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_model.RemovePackageTeam(ctx, p.ID, team.ID); err != nil {
+		if err == packages_model.ErrPackageTeamNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemovePackageTeam", err)
+		}
+		return
+	}
+	recordPermissionAudit(ctx, p, fmt.Sprintf("team %s: %s", team.Name, perm.AccessModeNone))
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetPackageBlob checks if content with the SHA256 hash is stored for packages of the owner
+func GetPackageBlob(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/blobs/sha256/{sha256} package getPackageBlob
+	// ---
+	// summary: Checks if content with the SHA256 hash is stored for packages of the owner
+	// description: Package types which support it can create a package file referencing the stored content without uploading it again.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: sha256
+	//   in: path
+	//   description: SHA256 hash of the content
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageBlob"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pb, err := packages_model.GetBlobBySHA256ForOwner(ctx, ctx.Package.Owner.ID, ctx.Params("sha256"))
+	if err != nil {
+		if err == packages_model.ErrPackageBlobNotExist {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetBlobBySHA256ForOwner", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.PackageBlob{
+		SHA256: pb.HashSHA256,
+		Size:   pb.Size,
+	})
+}
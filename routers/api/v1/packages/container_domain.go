@@ -0,0 +1,152 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+var hostnamePattern = regexp.MustCompile(`\A([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\z`)
+
+func toPackageContainerDomain(d *packages_model.PackageContainerDomain) *api.PackageContainerDomain {
+	return &api.PackageContainerDomain{
+		Domain:  d.Domain,
+		Created: d.CreatedUnix.AsTime(),
+	}
+}
+
+// GetPackageContainerDomain gets the custom domain of the container registry of an owner
+func GetPackageContainerDomain(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/container_domain package getPackageContainerDomain
+	// ---
+	// summary: Gets the custom domain of the container registry of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageContainerDomain"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d, err := packages_model.GetContainerDomainByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		if err == packages_model.ErrContainerDomainNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetContainerDomainByOwner", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toPackageContainerDomain(d))
+}
+
+// SetPackageContainerDomain maps a custom domain to the container registry of an owner
+func SetPackageContainerDomain(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/container_domain package setPackageContainerDomain
+	// ---
+	// summary: Maps a custom domain to the container registry of an owner
+	// description: The images of the owner are served on the domain without the owner in their path. Only site administrators may use domains which are not allowed by the instance settings. An existing domain of the owner is replaced.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPackageContainerDomainOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageContainerDomain"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: The domain is used by another owner.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPackageContainerDomainOption)
+	domain := strings.ToLower(strings.TrimSpace(form.Domain))
+	if !hostnamePattern.MatchString(domain) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid domain: %s", form.Domain))
+		return
+	}
+	appURL, _ := url.Parse(setting.AppURL)
+	if domain == strings.ToLower(appURL.Hostname()) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the domain of the instance can't be used")
+		return
+	}
+	if !ctx.Doer.IsAdmin && !hostmatcher.ParseSimpleMatchList("packages.CONTAINER_DOMAIN_ALLOWED_HOST_LIST", setting.Packages.ContainerDomainAllowedHostList).MatchHostName(domain) {
+		ctx.Error(http.StatusForbidden, "", fmt.Sprintf("the domain %s is not allowed", domain))
+		return
+	}
+
+	d, err := packages_model.SetContainerDomain(ctx, ctx.Package.Owner.ID, domain)
+	if err != nil {
+		if err == packages_model.ErrContainerDomainInUse {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetContainerDomain", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toPackageContainerDomain(d))
+}
+
+// DeletePackageContainerDomain removes the custom domain of the container registry of an owner
+func DeletePackageContainerDomain(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/container_domain package deletePackageContainerDomain
+	// ---
+	// summary: Removes the custom domain of the container registry of an owner
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if _, err := packages_model.GetContainerDomainByOwner(ctx, ctx.Package.Owner.ID); err != nil {
+		if err == packages_model.ErrContainerDomainNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetContainerDomainByOwner", err)
+		}
+		return
+	}
+	if err := packages_model.DeleteContainerDomainByOwner(ctx, ctx.Package.Owner.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteContainerDomainByOwner", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListPackageDependencies gets the dependencies of a package version
+func ListPackageDependencies(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/dependencies package listPackageDependencies
+	// ---
+	// summary: Gets the dependencies declared by a package version
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageDependencyList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deps, err := packages_model.GetDependenciesByVersionID(ctx, ctx.Package.Descriptor.Version.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDependenciesByVersionID", err)
+		return
+	}
+
+	apiDeps := make([]*api.PackageDependency, 0, len(deps))
+	for _, dep := range deps {
+		apiDeps = append(apiDeps, convert.ToPackageDependency(dep))
+	}
+
+	ctx.JSON(http.StatusOK, apiDeps)
+}
+
+// ListPackageDependents gets the package versions which depend on a package
+func ListPackageDependents(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/dependents package listPackageDependents
+	// ---
+	// summary: Gets the package versions of all owners which depend on a package
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageDependentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	dependents, count, err := packages_model.SearchDependents(ctx, ctx.Doer, p, &listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchDependents", err)
+		return
+	}
+
+	apiDependents := make([]*api.PackageDependent, 0, len(dependents))
+	for _, dependent := range dependents {
+		pd, err := packages_model.GetPackageDescriptor(ctx, dependent.Version)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetPackageDescriptor", err)
+			return
+		}
+		apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+			return
+		}
+		apiDependents = append(apiDependents, &api.PackageDependent{
+			Package:    apiPackage,
+			Dependency: convert.ToPackageDependency(dependent.Dependency),
+		})
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiDependents)
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// ComparePackageVersions gets the files which differ between two versions of a package
+func ComparePackageVersions(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/compare/{from}/{to} package comparePackageVersions
+	// ---
+	// summary: Gets the files which differ between two versions of a package
+	// description: Only composer, generic and npm packages are supported.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: from
+	//   in: path
+	//   description: version to compare from
+	//   type: string
+	//   required: true
+	// - name: to
+	//   in: path
+	//   description: version to compare to
+	//   type: string
+	//   required: true
+	// - name: content
+	//   in: query
+	//   description: include unified diffs of changed text files
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageVersionDiff"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+	from := getPackageDescriptorByVersion(ctx, p, ctx.Params("from"))
+	if ctx.Written() {
+		return
+	}
+	to := getPackageDescriptorByVersion(ctx, p, ctx.Params("to"))
+	if ctx.Written() {
+		return
+	}
+
+	entries, err := packages_service.DiffPackageVersions(from, to, ctx.FormBool("content"))
+	if err != nil {
+		if err == packages_service.ErrDiffNotSupported {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DiffPackageVersions", err)
+		}
+		return
+	}
+
+	files := make([]*api.PackageFileDiff, 0, len(entries))
+	for _, e := range entries {
+		files = append(files, convert.ToPackageFileDiff(e))
+	}
+
+	ctx.JSON(http.StatusOK, &api.PackageVersionDiff{
+		From:  from.Version.Version,
+		To:    to.Version.Version,
+		Files: files,
+	})
+}
+
+func getPackageDescriptorByVersion(ctx *context.APIContext, p *packages_model.Package, version string) *packages_model.PackageDescriptor {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, p.OwnerID, p.Type, p.Name, version)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetVersionByNameAndVersion", err)
+		}
+		return nil
+	}
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageDescriptor", err)
+		return nil
+	}
+	return pd
+}
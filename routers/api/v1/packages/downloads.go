@@ -0,0 +1,168 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+const (
+	defaultDownloadStatsDays = 30
+	maxDownloadStatsDays     = 366
+)
+
+func toPackageDownloadStats(stats *packages_service.DownloadStats) *api.PackageDownloadStats {
+	apiStats := &api.PackageDownloadStats{
+		Since:   stats.Since.FormatInLocation(utils.DateFormat, time.UTC),
+		Until:   stats.Until.FormatInLocation(utils.DateFormat, time.UTC),
+		Total:   stats.Total,
+		Days:    make([]*api.PackageDownloadsDay, 0, len(stats.Days)),
+		Clients: make([]*api.PackageDownloadsClient, 0, len(stats.Clients)),
+	}
+	for _, d := range stats.Days {
+		apiStats.Days = append(apiStats.Days, &api.PackageDownloadsDay{
+			Date:  d.Day.FormatInLocation(utils.DateFormat, time.UTC),
+			Count: d.Count,
+		})
+	}
+	for _, c := range stats.Clients {
+		apiStats.Clients = append(apiStats.Clients, &api.PackageDownloadsClient{
+			Client: c.Client,
+			Count:  c.Count,
+		})
+	}
+	for _, f := range stats.Files {
+		apiStats.Files = append(apiStats.Files, &api.PackageDownloadsFile{
+			ID:    f.File.ID,
+			Name:  f.File.Name,
+			Count: f.Count,
+		})
+	}
+	for _, v := range stats.Versions {
+		apiStats.Versions = append(apiStats.Versions, &api.PackageDownloadsVersion{
+			Version: v.Version.Version,
+			Count:   v.Count,
+		})
+	}
+	return apiStats
+}
+
+func serveDownloadStats(ctx *context.APIContext, p *packages_model.Package, pv *packages_model.PackageVersion) {
+	since, until, err := utils.ParseDateRange(ctx, defaultDownloadStatsDays, maxDownloadStatsDays)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	stats, err := packages_service.GetDownloadStats(ctx, p, pv, since, until)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDownloadStats", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPackageDownloadStats(stats))
+}
+
+// GetPackageDownloadStats gets the download statistics of all versions of a package
+func GetPackageDownloadStats(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/downloads package getPackageDownloadStats
+	// ---
+	// summary: Gets the download statistics of all versions of a package
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: first day of the statistics (date or RFC3339 timestamp, defaults to 29 days before until)
+	//   type: string
+	// - name: until
+	//   in: query
+	//   description: last day of the statistics (date or RFC3339 timestamp, defaults to today)
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageDownloadStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	serveDownloadStats(ctx, p, nil)
+}
+
+// GetPackageVersionDownloadStats gets the download statistics of a package version
+func GetPackageVersionDownloadStats(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/downloads package getPackageVersionDownloadStats
+	// ---
+	// summary: Gets the download statistics of a package version
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: first day of the statistics (date or RFC3339 timestamp, defaults to 29 days before until)
+	//   type: string
+	// - name: until
+	//   in: query
+	//   description: last day of the statistics (date or RFC3339 timestamp, defaults to today)
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageDownloadStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pd := ctx.Package.Descriptor
+	serveDownloadStats(ctx, pd.Package, pd.Version)
+}
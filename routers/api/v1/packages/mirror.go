@@ -0,0 +1,317 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	mirror_service "code.gitea.io/gitea/services/packages/mirror"
+)
+
+func getPackageMirror(ctx *context.APIContext) *packages_model.PackageMirror {
+	m, err := packages_model.GetMirrorByID(ctx, ctx.Package.Owner.ID, ctx.ParamsInt64("id"))
+	if err != nil {
+		if err == packages_model.ErrPackageMirrorNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMirrorByID", err)
+		}
+		return nil
+	}
+	return m
+}
+
+func toMirrorRules(rules []*api.PackageMirrorRule) []*packages_model.PackageMirrorRule {
+	mirrorRules := make([]*packages_model.PackageMirrorRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		mirrorRules = append(mirrorRules, &packages_model.PackageMirrorRule{
+			Name:         rule.Name,
+			VersionRange: rule.VersionRange,
+		})
+	}
+	return mirrorRules
+}
+
+// parseMirrorInterval parses the interval and writes an error response if it is invalid
+func parseMirrorInterval(ctx *context.APIContext, interval string) time.Duration {
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return 0
+	}
+	return d
+}
+
+// ListPackageMirrors lists the package mirrors of an owner
+func ListPackageMirrors(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/mirrors package listPackageMirrors
+	// ---
+	// summary: Lists the package mirrors of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirrors
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageMirrorList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	mirrors, err := packages_model.GetMirrorsByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMirrorsByOwner", err)
+		return
+	}
+
+	apiMirrors := make([]*api.PackageMirror, 0, len(mirrors))
+	for _, m := range mirrors {
+		apiMirrors = append(apiMirrors, convert.ToPackageMirror(m))
+	}
+	ctx.JSON(http.StatusOK, apiMirrors)
+}
+
+// CreatePackageMirror creates a package mirror
+func CreatePackageMirror(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/mirrors package createPackageMirror
+	// ---
+	// summary: Creates a mirror which periodically syncs selected packages from an upstream registry
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirror
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageMirrorOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageMirror"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageMirrorOption)
+
+	m := &packages_model.PackageMirror{
+		OwnerID:     ctx.Package.Owner.ID,
+		Type:        packages_model.Type(form.Type),
+		UpstreamURL: form.UpstreamURL,
+		Rules:       toMirrorRules(form.Rules),
+		CreatorID:   ctx.Doer.ID,
+		Interval:    mirror_service.DefaultInterval,
+	}
+	if form.Interval != "" {
+		if m.Interval = parseMirrorInterval(ctx, form.Interval); ctx.Written() {
+			return
+		}
+	}
+	if err := mirror_service.ValidateMirror(m); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	// the first sync is done by the next run of the cron task
+	if err := packages_model.CreateMirror(ctx, m); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateMirror", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToPackageMirror(m))
+}
+
+// GetPackageMirror gets a package mirror
+func GetPackageMirror(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/mirrors/{id} package getPackageMirror
+	// ---
+	// summary: Gets a package mirror
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirror
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the mirror
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageMirror"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	m := getPackageMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageMirror(m))
+}
+
+// EditPackageMirror edits a package mirror
+func EditPackageMirror(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/mirrors/{id} package editPackageMirror
+	// ---
+	// summary: Edits a package mirror
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirror
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the mirror
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageMirrorOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageMirror"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPackageMirrorOption)
+
+	m := getPackageMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.UpstreamURL != nil {
+		m.UpstreamURL = *form.UpstreamURL
+	}
+	if form.Rules != nil {
+		m.Rules = toMirrorRules(form.Rules)
+	}
+	if form.Interval != nil {
+		if m.Interval = parseMirrorInterval(ctx, *form.Interval); ctx.Written() {
+			return
+		}
+		m.ScheduleNextSync()
+	}
+	if err := mirror_service.ValidateMirror(m); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if err := packages_model.UpdateMirrorCols(ctx, m, "upstream_url", "rules", "interval", "next_sync_unix"); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateMirrorCols", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageMirror(m))
+}
+
+// DeletePackageMirror deletes a package mirror
+func DeletePackageMirror(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/mirrors/{id} package deletePackageMirror
+	// ---
+	// summary: Deletes a package mirror, the mirrored packages are kept
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirror
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the mirror
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := packages_model.DeleteMirrorByID(ctx, ctx.Package.Owner.ID, ctx.ParamsInt64("id")); err != nil {
+		if err == packages_model.ErrPackageMirrorNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteMirrorByID", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// SyncPackageMirror syncs a package mirror
+func SyncPackageMirror(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/mirrors/{id}/sync package syncPackageMirror
+	// ---
+	// summary: Syncs a package mirror now and returns when the sync is done
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the mirror
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the mirror
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageMirrorSyncResult"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	m := getPackageMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	result, err := mirror_service.SyncMirror(ctx, m)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SyncMirror", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.PackageMirrorSyncResult{
+		Synced: result.Synced,
+		Errors: result.Errors,
+	})
+}
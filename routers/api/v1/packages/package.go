@@ -5,13 +5,17 @@
 package packages
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	packages_service "code.gitea.io/gitea/services/packages"
 )
@@ -41,7 +45,7 @@ func ListPackages(ctx *context.APIContext) {
 	//   in: query
 	//   description: package type filter
 	//   type: string
-	//   enum: [composer, conan, container, generic, helm, maven, npm, nuget, pub, pypi, rubygems, vagrant]
+	//   enum: [composer, conan, conda, container, cran, generic, helm, maven, npm, nuget, pub, pypi, rubygems, swift, vagrant]
 	// - name: q
 	//   in: query
 	//   description: name filter
@@ -56,11 +60,12 @@ func ListPackages(ctx *context.APIContext) {
 	query := ctx.FormTrim("q")
 
 	pvs, count, err := packages.SearchVersions(ctx, &packages.PackageSearchOptions{
-		OwnerID:    ctx.Package.Owner.ID,
-		Type:       packages.Type(packageType),
-		Name:       packages.SearchValue{Value: query},
-		IsInternal: util.OptionalBoolFalse,
-		Paginator:  &listOptions,
+		OwnerID:     ctx.Package.Owner.ID,
+		Type:        packages.Type(packageType),
+		Name:        packages.SearchValue{Value: query},
+		IsInternal:  util.OptionalBoolFalse,
+		HidePrivate: !ctx.Package.CanSeePrivatePackages(),
+		Paginator:   &listOptions,
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "SearchVersions", err)
@@ -131,6 +136,75 @@ func GetPackage(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiPackage)
 }
 
+// EditPackage edits a package version
+func EditPackage(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/{type}/{name}/{version} package editPackage
+	// ---
+	// summary: Edit the release notes of a package version
+	// description: The release notes can be changed even if the versions of the owner are immutable.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Package"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPackageOption)
+
+	pv := ctx.Package.Descriptor.Version
+	if form.ReleaseNotes != nil {
+		if len(*form.ReleaseNotes) > packages_module.MaxReleaseNotesSize {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("the release notes must not be larger than %d bytes", packages_module.MaxReleaseNotesSize))
+			return
+		}
+		pv.ReleaseNotes = strings.TrimSpace(*form.ReleaseNotes)
+		if err := packages.UpdateVersionReleaseNotes(ctx, pv); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateVersionReleaseNotes", err)
+			return
+		}
+	}
+
+	apiPackage, err := convert.ToPackage(ctx, ctx.Package.Descriptor, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiPackage)
+}
+
 // DeletePackage deletes a package
 func DeletePackage(ctx *context.APIContext) {
 	// swagger:operation DELETE /packages/{owner}/{type}/{name}/{version} package deletePackage
@@ -157,14 +231,35 @@ func DeletePackage(ctx *context.APIContext) {
 	//   description: version of the package
 	//   type: string
 	//   required: true
+	// - name: force
+	//   in: query
+	//   description: delete the version even if the versions of the owner are immutable, only allowed for site administrators
+	//   type: boolean
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
 
-	err := packages_service.RemovePackageVersion(ctx.Doer, ctx.Package.Descriptor.Version)
+	var err error
+	if ctx.FormBool("force") {
+		if !ctx.Doer.IsAdmin {
+			ctx.Error(http.StatusForbidden, "", "only site administrators can force the deletion of a package version")
+			return
+		}
+		err = packages_service.ForceRemovePackageVersion(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, ctx.Package.Descriptor.Version)
+	} else {
+		err = packages_service.RemovePackageVersion(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, ctx.Package.Descriptor.Version)
+	}
 	if err != nil {
+		if err == packages_service.ErrVersionImmutable {
+			ctx.Error(http.StatusConflict, "RemovePackageVersion", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "RemovePackageVersion", err)
 		return
 	}
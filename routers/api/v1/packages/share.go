@@ -0,0 +1,200 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func getShareOrganization(ctx *context.APIContext) *organization.Organization {
+	org, err := organization.GetOrgByName(ctx.Params("org"))
+	if err != nil {
+		if organization.IsErrOrgNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetOrgByName", err)
+		}
+		return nil
+	}
+	return org
+}
+
+// ListPackageShares lists the organizations a package is shared with
+func ListPackageShares(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/shares package listPackageShares
+	// ---
+	// summary: Lists the organizations a package is shared with
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrganizationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pss, err := packages_model.GetPackageShares(ctx, p.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageShares", err)
+		return
+	}
+
+	apiOrgs := make([]*api.Organization, 0, len(pss))
+	for _, ps := range pss {
+		org, err := organization.GetOrgByID(ctx, ps.OrgID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetOrgByID", err)
+			return
+		}
+		apiOrgs = append(apiOrgs, convert.ToOrganization(org))
+	}
+	ctx.JSON(http.StatusOK, apiOrgs)
+}
+
+// SharePackage shares a package with an organization
+func SharePackage(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/{type}/{name}/shares/{org} package sharePackage
+	// ---
+	// summary: Shares a package with an organization the owner is a member of
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Package.Owner.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is an organization", ctx.Package.Owner.Name))
+		return
+	}
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+	org := getShareOrganization(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	isMember, err := organization.IsOrganizationMember(ctx, org.ID, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsOrganizationMember", err)
+		return
+	}
+	if !isMember {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is not a member of %s", ctx.Package.Owner.Name, org.Name))
+		return
+	}
+
+	if err := packages_model.SharePackage(ctx, p.ID, org.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SharePackage", err)
+		return
+	}
+	recordPermissionAudit(ctx, p, "shared with "+org.Name)
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnsharePackage removes the share of a package with an organization
+func UnsharePackage(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name}/shares/{org} package unsharePackage
+	// ---
+	// summary: Removes the share of a package with an organization
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getPackage(ctx)
+	if ctx.Written() {
+		return
+	}
+	org := getShareOrganization(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_model.UnsharePackage(ctx, p.ID, org.ID); err != nil {
+		if err == packages_model.ErrPackageShareNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UnsharePackage", err)
+		}
+		return
+	}
+	recordPermissionAudit(ctx, p, "unshared with "+org.Name)
+	ctx.Status(http.StatusNoContent)
+}
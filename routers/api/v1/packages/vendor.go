@@ -0,0 +1,169 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// vendorPattern matches the vendor part of composer package names
+var vendorPattern = regexp.MustCompile(`\A[a-z0-9]([_\.-]?[a-z0-9]+)*\z`)
+
+func getVendor(ctx *context.APIContext) string {
+	vendor := ctx.Params("vendor")
+	if !vendorPattern.MatchString(vendor) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid vendor: %s", vendor))
+		return ""
+	}
+	return vendor
+}
+
+// ListPackageVendorGrants lists the vendor namespaces of the composer registry reserved for teams
+func ListPackageVendorGrants(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/vendors package listPackageVendorGrants
+	// ---
+	// summary: Lists the vendor namespaces of the composer registry of an organization reserved for teams
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageVendorGrantList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	grants, err := packages_model.GetVendorGrants(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetVendorGrants", err)
+		return
+	}
+
+	apiGrants := make([]*api.PackageVendorGrant, 0, len(grants))
+	for _, grant := range grants {
+		team, err := organization.GetTeamByID(ctx, grant.TeamID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetTeamByID", err)
+			return
+		}
+		apiTeam, err := convert.ToTeam(team)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToTeam", err)
+			return
+		}
+		apiGrants = append(apiGrants, &api.PackageVendorGrant{
+			Vendor:  grant.Vendor,
+			Team:    apiTeam,
+			Created: grant.CreatedUnix.AsTime(),
+		})
+	}
+	ctx.JSON(http.StatusOK, apiGrants)
+}
+
+// AddPackageVendorGrant reserves a vendor namespace for a team
+func AddPackageVendorGrant(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/vendors/{vendor}/teams/{team} package addPackageVendorGrant
+	// ---
+	// summary: Grants a team the publishing of the composer packages of a vendor. Once a vendor has a grant, only members of granted teams may publish its packages.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: vendor
+	//   in: path
+	//   description: vendor of the composer packages
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: path
+	//   description: name of the team
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	vendor := getVendor(ctx)
+	if ctx.Written() {
+		return
+	}
+	team := getPackageTeam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_model.AddVendorGrant(ctx, ctx.Package.Owner.ID, vendor, team.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "AddVendorGrant", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemovePackageVendorGrant revokes the grant of a team for a vendor namespace
+func RemovePackageVendorGrant(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/vendors/{vendor}/teams/{team} package removePackageVendorGrant
+	// ---
+	// summary: Revokes the grant of a team for the composer packages of a vendor
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: vendor
+	//   in: path
+	//   description: vendor of the composer packages
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: path
+	//   description: name of the team
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	vendor := getVendor(ctx)
+	if ctx.Written() {
+		return
+	}
+	team := getPackageTeam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_model.RemoveVendorGrant(ctx, ctx.Package.Owner.ID, vendor, team.ID); err != nil {
+		if err == packages_model.ErrPackageVendorGrantNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveVendorGrant", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
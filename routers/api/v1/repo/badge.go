@@ -0,0 +1,191 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetReleaseBadge returns the badge data of the latest release
+func GetReleaseBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/release repository repoGetReleaseBadge
+	// ---
+	// summary: Get the badge data of the latest release in the shields.io endpoint schema
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Badge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	badge, err := repo_service.ReleaseBadge(ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReleaseBadge", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, badge)
+}
+
+// GetIssuesBadge returns the badge data of the open issues
+func GetIssuesBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/issues repository repoGetIssuesBadge
+	// ---
+	// summary: Get the badge data of the open issues in the shields.io endpoint schema
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Badge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ctx.JSON(http.StatusOK, repo_service.IssuesBadge(ctx.Repo.Repository))
+}
+
+// GetBuildBadge returns the badge data of the combined commit status of a branch
+func GetBuildBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/build repository repoGetBuildBadge
+	// ---
+	// summary: Get the badge data of the combined commit status of a branch in the shields.io endpoint schema
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch of the commit, defaults to the default branch
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Badge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branch := ctx.FormTrim("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+
+	badge, err := repo_service.BuildBadge(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, branch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "BuildBadge", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, badge)
+}
+
+// GetLicenseBadge returns the badge data of the license
+func GetLicenseBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/license repository repoGetLicenseBadge
+	// ---
+	// summary: Get the badge data of the license of the default branch in the shields.io endpoint schema
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Badge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	badge, err := repo_service.LicenseBadge(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "LicenseBadge", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, badge)
+}
+
+// GetPackageBadge returns the badge data of the latest version of a package linked to the repository
+func GetPackageBadge(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/packages/{type}/{name} repository repoGetPackageBadge
+	// ---
+	// summary: Get the badge data of the latest version of a package linked to the repository in the shields.io endpoint schema
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Badge"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	badge, err := repo_service.PackageBadge(ctx, ctx.Repo.Repository, packages_model.Type(ctx.Params("type")), ctx.Params("name"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "PackageBadge", err)
+		return
+	}
+	if badge == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, badge)
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/services/gitdiff"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetBlobDiff diffs two blobs of a repository or of two repositories of the same fork network
+func GetBlobDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/diff/blobs repository repoGetBlobDiff
+	// ---
+	// summary: Get the diff of two blobs
+	// description: The blobs are addressed by `<ref>:<path>`, where the ref is a branch, a tag or a commit sha, or by the full sha of a blob.
+	// produces:
+	// - application/json
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: base
+	//   in: query
+	//   description: the base blob in the repository
+	//   type: string
+	//   required: true
+	// - name: head
+	//   in: query
+	//   description: the head blob in the head repository
+	//   type: string
+	//   required: true
+	// - name: head_repo
+	//   in: query
+	//   description: full name of the repository of the head blob, which must belong to the same fork network, defaults to the repository
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: format of the diff, a structured diff or a unified diff as text
+	//   type: string
+	//   enum: [json, diff]
+	// - name: context
+	//   in: query
+	//   description: number of unchanged lines around the changes, defaults to 3
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BlobDiff"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	baseRev := ctx.FormTrim("base")
+	headRev := ctx.FormTrim("head")
+	if baseRev == "" || headRev == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "base and head are required")
+		return
+	}
+	for _, rev := range []string{baseRev, headRev} {
+		if !strings.Contains(rev, ":") && (len(rev) != 40 || !git.SHAPattern.MatchString(rev)) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "blobs must be addressed by <ref>:<path> or their sha")
+			return
+		}
+	}
+	format := ctx.FormString("format")
+	if format != "" && format != "json" && format != "diff" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "format must be json or diff")
+		return
+	}
+	contextLines := 3
+	if ctx.FormString("context") != "" {
+		contextLines = ctx.FormInt("context")
+		if contextLines < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "context must not be negative")
+			return
+		}
+	}
+
+	headRepo, headGitRepo, ok := getBlobDiffHeadRepository(ctx)
+	if !ok {
+		return
+	}
+	if headGitRepo != ctx.Repo.GitRepo {
+		defer headGitRepo.Close()
+	}
+
+	base, err := gitdiff.GetBlobDiffSide(ctx.Repo.GitRepo, baseRev)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("base blob does not exist")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlobDiffSide", err)
+		}
+		return
+	}
+	head, err := gitdiff.GetBlobDiffSide(headGitRepo, headRev)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("head blob does not exist")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlobDiffSide", err)
+		}
+		return
+	}
+
+	if format == "diff" {
+		patch, err := gitdiff.GetUnifiedBlobDiff(ctx, base, head, contextLines)
+		if err != nil {
+			handleBlobDiffError(ctx, err)
+			return
+		}
+		ctx.PlainTextBytes(http.StatusOK, patch)
+		return
+	}
+
+	diff, err := gitdiff.GetBlobDiff(ctx, base, head, contextLines)
+	if err != nil {
+		handleBlobDiffError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBlobDiff(ctx.Repo.Repository.FullName(), base, headRepo.FullName(), head, diff))
+}
+
+// getBlobDiffHeadRepository returns the repository of the head blob, which must be readable by the doer and belong to the same fork network
+func getBlobDiffHeadRepository(ctx *context.APIContext) (*repo_model.Repository, *git.Repository, bool) {
+	headRepoName := ctx.FormTrim("head_repo")
+	if headRepoName == "" || strings.EqualFold(headRepoName, ctx.Repo.Repository.FullName()) {
+		return ctx.Repo.Repository, ctx.Repo.GitRepo, true
+	}
+
+	ownerName, repoName, ok := strings.Cut(headRepoName, "/")
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "head_repo must be the full name of a repository")
+		return nil, nil, false
+	}
+	headRepo, err := repo_model.GetRepositoryByOwnerAndNameCtx(ctx, ownerName, repoName)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("head repository does not exist")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return nil, nil, false
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, headRepo, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil, nil, false
+	}
+	if !perm.CanRead(unit.TypeCode) {
+		ctx.NotFound("head repository does not exist")
+		return nil, nil, false
+	}
+
+	baseRoot, err := repo_service.GetRootRepository(ctx, ctx.Doer, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRootRepository", err)
+		return nil, nil, false
+	}
+	headRoot, err := repo_service.GetRootRepository(ctx, ctx.Doer, headRepo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRootRepository", err)
+		return nil, nil, false
+	}
+	if baseRoot.ID != headRoot.ID {
+		ctx.Error(http.StatusUnprocessableEntity, "", "head repository does not belong to the fork network of the repository")
+		return nil, nil, false
+	}
+
+	headGitRepo, err := git.OpenRepository(ctx, headRepo.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return nil, nil, false
+	}
+	return headRepo, headGitRepo, true
+}
+
+func handleBlobDiffError(ctx *context.APIContext, err error) {
+	if errors.Is(err, gitdiff.ErrBlobTooLarge) {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	ctx.Error(http.StatusInternalServerError, "GetBlobDiff", err)
+}
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models"
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
@@ -405,23 +406,40 @@ func CreateBranchProtection(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.CreateBranchProtectionOption)
-	repo := ctx.Repo.Repository
 
+	bp, ok := createBranchProtection(ctx, ctx.Repo.Repository, form)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToBranchProtection(bp))
+}
+
+// createBranchProtection creates the branch protection specified by `form` for
+// the repository. If there is an error, write to `ctx` accordingly. Return (protection, ok)
+func createBranchProtection(ctx *context.APIContext, repo *repo_model.Repository, form *api.CreateBranchProtectionOption) (*git_model.ProtectedBranch, bool) {
 	// Currently protection must match an actual branch
-	if !git.IsBranchExist(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(), form.BranchName) {
+	if !git.IsBranchExist(ctx.Req.Context(), repo.RepoPath(), form.BranchName) {
 		ctx.NotFound()
-		return
+		return nil, false
 	}
 
 	protectBranch, err := git_model.GetProtectedBranchBy(ctx, repo.ID, form.BranchName)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetProtectBranchOfRepoByName", err)
-		return
+		return nil, false
 	} else if protectBranch != nil {
 		ctx.Error(http.StatusForbidden, "Create branch protection", "Branch protection already exist")
-		return
+		return nil, false
 	}
 
+	return saveBranchProtection(ctx, repo, nil, form)
+}
+
+// saveBranchProtection creates the branch protection specified by `form`, or replaces
+// the settings of `existing` if it is not nil. If there is an error, write to `ctx`
+// accordingly. Return (protection, ok)
+func saveBranchProtection(ctx *context.APIContext, repo *repo_model.Repository, existing *git_model.ProtectedBranch, form *api.CreateBranchProtectionOption) (*git_model.ProtectedBranch, bool) {
 	var requiredApprovals int64
 	if form.RequiredApprovals > 0 {
 		requiredApprovals = form.RequiredApprovals
@@ -431,28 +449,28 @@ func CreateBranchProtection(ctx *context.APIContext) {
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return nil, false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return nil, false
 	}
 	mergeWhitelistUsers, err := user_model.GetUserIDsByNames(form.MergeWhitelistUsernames, false)
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return nil, false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return nil, false
 	}
 	approvalsWhitelistUsers, err := user_model.GetUserIDsByNames(form.ApprovalsWhitelistUsernames, false)
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return nil, false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return nil, false
 	}
 	var whitelistTeams, mergeWhitelistTeams, approvalsWhitelistTeams []int64
 	if repo.Owner.IsOrganization() {
@@ -460,33 +478,33 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return nil, false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return nil, false
 		}
 		mergeWhitelistTeams, err = organization.GetTeamIDsByNames(repo.OwnerID, form.MergeWhitelistTeams, false)
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return nil, false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return nil, false
 		}
 		approvalsWhitelistTeams, err = organization.GetTeamIDsByNames(repo.OwnerID, form.ApprovalsWhitelistTeams, false)
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return nil, false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return nil, false
 		}
 	}
 
-	protectBranch = &git_model.ProtectedBranch{
-		RepoID:                        ctx.Repo.Repository.ID,
+	protectBranch := &git_model.ProtectedBranch{
+		RepoID:                        repo.ID,
 		BranchName:                    form.BranchName,
 		CanPush:                       form.EnablePush,
 		EnableWhitelist:               form.EnablePush && form.EnablePushWhitelist,
@@ -504,8 +522,12 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		UnprotectedFilePatterns:       form.UnprotectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
 	}
+	if existing != nil {
+		protectBranch.ID = existing.ID
+		protectBranch.CreatedUnix = existing.CreatedUnix
+	}
 
-	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
+	err = git_model.UpdateProtectBranch(ctx, repo, protectBranch, git_model.WhitelistOptions{
 		UserIDs:          whitelistUsers,
 		TeamIDs:          whitelistTeams,
 		MergeUserIDs:     mergeWhitelistUsers,
@@ -515,26 +537,26 @@ func CreateBranchProtection(ctx *context.APIContext) {
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
-		return
+		return nil, false
 	}
 
-	if err = pull_service.CheckPrsForBaseBranch(ctx.Repo.Repository, protectBranch.BranchName); err != nil {
+	if err = pull_service.CheckPrsForBaseBranch(repo, protectBranch.BranchName); err != nil {
 		ctx.Error(http.StatusInternalServerError, "CheckPrsForBaseBranch", err)
-		return
+		return nil, false
 	}
 
 	// Reload from db to get all whitelists
-	bp, err := git_model.GetProtectedBranchBy(ctx, ctx.Repo.Repository.ID, form.BranchName)
+	bp, err := git_model.GetProtectedBranchBy(ctx, repo.ID, form.BranchName)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetProtectedBranchByID", err)
-		return
+		return nil, false
 	}
-	if bp == nil || bp.RepoID != ctx.Repo.Repository.ID {
+	if bp == nil || bp.RepoID != repo.ID {
 		ctx.Error(http.StatusInternalServerError, "New branch protection not found", err)
-		return
+		return nil, false
 	}
 
-	ctx.JSON(http.StatusCreated, convert.ToBranchProtection(bp))
+	return bp, true
 }
 
 // EditBranchProtection edits a branch protection for a repo
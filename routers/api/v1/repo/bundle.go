@@ -0,0 +1,105 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// UploadBundle updates the branches and tags of a repository from a git bundle
+func UploadBundle(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/bundle repository repoUploadBundle
+	// ---
+	// summary: Update the branches and tags of a repository from a git bundle
+	// description: An empty repository is initialized with the bundle. Existing branches can only be fast-forwarded, existing tags must not change and the branch protections apply.
+	// consumes:
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: bundle
+	//   in: formData
+	//   description: git bundle to upload
+	//   type: file
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BundleRefUpdateList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("repo is a mirror, cannot upload a bundle"))
+		return
+	}
+
+	file, header, err := ctx.Req.FormFile("bundle")
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "GetFile", err)
+		return
+	}
+	defer file.Close()
+
+	if maxSize := setting.Repository.Upload.BundleMaxSize << 20; header.Size > maxSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", repo_service.ErrBundleTooLarge{MaxSize: maxSize})
+		return
+	}
+
+	updates, err := repo_service.UploadBundle(ctx, ctx.Doer, ctx.Repo.Repository, file)
+	if err != nil {
+		if repo_service.IsErrInvalidBundle(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if repo_service.IsErrBundleTooLarge(err) {
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+		} else if repo_service.IsErrBundleNotFastForward(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if git.IsErrPushRejected(err) {
+			errPushRej := err.(*git.ErrPushRejected)
+			if len(errPushRej.Message) == 0 {
+				ctx.Error(http.StatusForbidden, "", "push rejected without remote error message")
+			} else {
+				ctx.Error(http.StatusForbidden, "", "push rejected with remote message: "+errPushRej.Message)
+			}
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UploadBundle", err)
+		}
+		return
+	}
+
+	apiUpdates := make([]*api.BundleRefUpdate, 0, len(updates))
+	for _, u := range updates {
+		apiUpdates = append(apiUpdates, &api.BundleRefUpdate{
+			Ref:    u.RefName,
+			OldSHA: u.OldCommitID,
+			NewSHA: u.NewCommitID,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiUpdates)
+}
@@ -8,6 +8,7 @@ package repo
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/perm"
@@ -18,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
@@ -73,6 +75,50 @@ func ListCollaborators(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, users)
 }
 
+// ListCollaborations list the access of a repository's collaborators including their expiry
+func ListCollaborations(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/collaborations repository repoListCollaborations
+	// ---
+	// summary: List the access of a repository's collaborators, the first to expire first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: expires_before
+	//   in: query
+	//   description: only list collaborations which expire before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: only_expiring
+	//   in: query
+	//   description: only list collaborations which expire
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CollaborationList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ListCollaborations(ctx, ctx.Repo.Repository.ID)
+}
+
 // IsCollaborator check if a user is a collaborator of a repository
 func IsCollaborator(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/collaborators/{collaborator} repository repoCheckCollaborator
@@ -160,6 +206,15 @@ func AddCollaborator(ctx *context.APIContext) {
 
 	form := web.GetForm(ctx).(*api.AddCollaboratorOption)
 
+	var expires timeutil.TimeStamp
+	if form.ExpiresAt != nil && !form.ExpiresAt.IsZero() {
+		if !form.ExpiresAt.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("expires_at must be in the future"))
+			return
+		}
+		expires = timeutil.TimeStamp(form.ExpiresAt.Unix())
+	}
+
 	collaborator, err := user_model.GetUserByName(ctx, ctx.Params(":collaborator"))
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
@@ -187,6 +242,13 @@ func AddCollaborator(ctx *context.APIContext) {
 		}
 	}
 
+	if form.ExpiresAt != nil {
+		if err := repo_model.SetCollaborationExpiry(ctx, ctx.Repo.Repository.ID, collaborator.ID, expires); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetCollaborationExpiry", err)
+			return
+		}
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -101,6 +101,10 @@ func GetAllCommits(ctx *context.APIContext) {
 	//   in: query
 	//   description: SHA or branch to start listing commits from (usually 'master')
 	//   type: string
+	// - name: not
+	//   in: query
+	//   description: SHA or branch whose commits are excluded (e.g. the commit before a push), ignored if used with 'path'
+	//   type: string
 	// - name: path
 	//   in: query
 	//   description: filepath of a file/dir
@@ -171,18 +175,42 @@ func GetAllCommits(ctx *context.APIContext) {
 			}
 		}
 
-		// Total commit count
-		commitsCountTotal, err = baseCommit.CommitsCount()
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "GetCommitsCount", err)
-			return
-		}
+		if not := ctx.FormString("not"); len(not) > 0 {
+			notCommit, err := ctx.Repo.GitRepo.GetCommit(not)
+			if err != nil {
+				if git.IsErrNotExist(err) {
+					ctx.NotFound("GetCommit", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+				}
+				return
+			}
 
-		// Query commits
-		commits, err = baseCommit.CommitsByRange(listOptions.Page, listOptions.PageSize)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "CommitsByRange", err)
-			return
+			commitsCountTotal, err = ctx.Repo.GitRepo.CommitsCountBetween(notCommit.ID.String(), baseCommit.ID.String())
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "CommitsCountBetween", err)
+				return
+			}
+
+			commits, err = ctx.Repo.GitRepo.CommitsBetweenLimit(baseCommit, notCommit, listOptions.PageSize, (listOptions.Page-1)*listOptions.PageSize)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "CommitsBetweenLimit", err)
+				return
+			}
+		} else {
+			// Total commit count
+			commitsCountTotal, err = baseCommit.CommitsCount()
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetCommitsCount", err)
+				return
+			}
+
+			// Query commits
+			commits, err = baseCommit.CommitsByRange(listOptions.Page, listOptions.PageSize)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "CommitsByRange", err)
+				return
+			}
 		}
 	} else {
 		if len(sha) == 0 {
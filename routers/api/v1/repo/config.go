@@ -0,0 +1,754 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/validation"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GetConfig exports the declarative configuration of a repository
+func GetConfig(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/config repository repoGetConfig
+	// ---
+	// summary: Export the settings, branch protections, webhooks, labels and collaborators of a repository
+	// produces:
+	// - application/json
+	// - application/x-yaml
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the document
+	//   type: string
+	//   enum: [json, yaml]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoConfig"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	config, ok := exportRepoConfig(ctx)
+	if !ok {
+		return
+	}
+	writeRepoConfig(ctx, config)
+}
+
+// ApplyConfig applies a declarative configuration to a repository
+func ApplyConfig(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/config repository repoApplyConfig
+	// ---
+	// summary: Apply a configuration to a repository, sections which are omitted are left untouched
+	// description: The labels, branch protections, webhooks and collaborators of a section which is present replace the existing ones, existing entries which are omitted from the section are deleted. The whole configuration is validated before anything is changed.
+	// consumes:
+	// - application/json
+	// - application/x-yaml
+	// produces:
+	// - application/json
+	// - application/x-yaml
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the returned document
+	//   type: string
+	//   enum: [json, yaml]
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RepoConfig"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoConfig"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	config, ok := readRepoConfig(ctx)
+	if !ok || !checkRepoConfig(ctx, config) {
+		return
+	}
+
+	// the sections are applied one after another, an internal error may leave the
+	// repository partially configured but the request can safely be repeated
+	if config.Settings != nil {
+		opts := *config.Settings
+		opts.Name = nil
+		opts.Archived = nil
+		opts.MirrorInterval = nil
+		opts.EnablePrune = nil
+		if err := updateBasicProperties(ctx, opts); err != nil {
+			return
+		}
+		if err := updateRepoUnits(ctx, opts); err != nil {
+			return
+		}
+	}
+	if config.Labels != nil && !applyConfigLabels(ctx, config.Labels) {
+		return
+	}
+	if config.BranchProtections != nil && !applyConfigBranchProtections(ctx, config.BranchProtections) {
+		return
+	}
+	if config.Webhooks != nil && !applyConfigWebhooks(ctx, config.Webhooks) {
+		return
+	}
+	if config.Collaborators != nil && !applyConfigCollaborators(ctx, config.Collaborators) {
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Repo.Repository = repo
+
+	config, ok = exportRepoConfig(ctx)
+	if !ok {
+		return
+	}
+	writeRepoConfig(ctx, config)
+}
+
+// readRepoConfig decodes the configuration in the request body, YAML is accepted
+// if the content type says so. If there is an error, write to `ctx` accordingly
+func readRepoConfig(ctx *context.APIContext) (*api.RepoConfig, bool) {
+	content, err := io.ReadAll(ctx.Req.Body)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return nil, false
+	}
+
+	if strings.Contains(ctx.Req.Header.Get("Content-Type"), "yaml") {
+		var doc interface{}
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid YAML document: %v", err))
+			return nil, false
+		}
+		if doc, err = yamlToJSONValue(doc); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid YAML document: %v", err))
+			return nil, false
+		}
+		if content, err = json.Marshal(doc); err != nil {
+			ctx.Error(http.StatusInternalServerError, "Marshal", err)
+			return nil, false
+		}
+	}
+
+	config := &api.RepoConfig{}
+	if err := json.Unmarshal(content, config); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid document: %v", err))
+		return nil, false
+	}
+	return config, true
+}
+
+// yamlToJSONValue converts the maps of a decoded YAML value to maps with string keys
+func yamlToJSONValue(val interface{}) (interface{}, error) {
+	var err error
+	switch val := val.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("found non-string key %v", k)
+			}
+			if m[key], err = yamlToJSONValue(v); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case []interface{}:
+		l := make([]interface{}, len(val))
+		for i, v := range val {
+			if l[i], err = yamlToJSONValue(v); err != nil {
+				return nil, err
+			}
+		}
+		return l, nil
+	default:
+		return val, nil
+	}
+}
+
+// writeRepoConfig writes the configuration in the format requested by the `format` query parameter
+func writeRepoConfig(ctx *context.APIContext, config *api.RepoConfig) {
+	if ctx.FormString("format") != "yaml" {
+		ctx.JSON(http.StatusOK, config)
+		return
+	}
+
+	// go through JSON to use the same field names and keep their order
+	content, err := json.Marshal(config)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Marshal", err)
+		return
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Unmarshal", err)
+		return
+	}
+	if content, err = yaml.Marshal(doc); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Marshal", err)
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "application/x-yaml; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(content); err != nil {
+		log.Error("Unable to write repository config: %v", err)
+	}
+}
+
+// exportRepoConfig collects the configuration of the current repository. If there is
+// an error, write to `ctx` accordingly
+func exportRepoConfig(ctx *context.APIContext) (*api.RepoConfig, bool) {
+	repo := ctx.Repo.Repository
+
+	apiRepo := convert.ToRepo(repo, ctx.Repo.AccessMode)
+	config := &api.RepoConfig{
+		Settings: &api.EditRepoOption{
+			Description:                   &apiRepo.Description,
+			Website:                       &apiRepo.Website,
+			Private:                       &apiRepo.Private,
+			Template:                      &apiRepo.Template,
+			HasIssues:                     &apiRepo.HasIssues,
+			InternalTracker:               apiRepo.InternalTracker,
+			ExternalTracker:               apiRepo.ExternalTracker,
+			HasWiki:                       &apiRepo.HasWiki,
+			ExternalWiki:                  apiRepo.ExternalWiki,
+			DefaultBranch:                 &apiRepo.DefaultBranch,
+			HasPullRequests:               &apiRepo.HasPullRequests,
+			HasProjects:                   &apiRepo.HasProjects,
+			IgnoreWhitespaceConflicts:     &apiRepo.IgnoreWhitespaceConflicts,
+			AllowMerge:                    &apiRepo.AllowMerge,
+			AllowRebase:                   &apiRepo.AllowRebase,
+			AllowRebaseMerge:              &apiRepo.AllowRebaseMerge,
+			AllowSquash:                   &apiRepo.AllowSquash,
+			AllowRebaseUpdate:             &apiRepo.AllowRebaseUpdate,
+			DefaultDeleteBranchAfterMerge: &apiRepo.DefaultDeleteBranchAfterMerge,
+		},
+		BranchProtections: []*api.CreateBranchProtectionOption{},
+		Webhooks:          []*api.CreateHookOption{},
+		Labels:            []*api.CreateLabelOption{},
+		Collaborators:     []*api.RepoConfigCollaborator{},
+	}
+	if apiRepo.DefaultMergeStyle != "" {
+		config.Settings.DefaultMergeStyle = &apiRepo.DefaultMergeStyle
+	}
+
+	protections, err := git_model.GetProtectedBranches(repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranches", err)
+		return nil, false
+	}
+	for _, protection := range protections {
+		bp := convert.ToBranchProtection(protection)
+		config.BranchProtections = append(config.BranchProtections, &api.CreateBranchProtectionOption{
+			BranchName:                    bp.BranchName,
+			EnablePush:                    bp.EnablePush,
+			EnablePushWhitelist:           bp.EnablePushWhitelist,
+			PushWhitelistUsernames:        bp.PushWhitelistUsernames,
+			PushWhitelistTeams:            bp.PushWhitelistTeams,
+			PushWhitelistDeployKeys:       bp.PushWhitelistDeployKeys,
+			EnableMergeWhitelist:          bp.EnableMergeWhitelist,
+			MergeWhitelistUsernames:       bp.MergeWhitelistUsernames,
+			MergeWhitelistTeams:           bp.MergeWhitelistTeams,
+			EnableStatusCheck:             bp.EnableStatusCheck,
+			StatusCheckContexts:           bp.StatusCheckContexts,
+			RequiredApprovals:             bp.RequiredApprovals,
+			EnableApprovalsWhitelist:      bp.EnableApprovalsWhitelist,
+			ApprovalsWhitelistUsernames:   bp.ApprovalsWhitelistUsernames,
+			ApprovalsWhitelistTeams:       bp.ApprovalsWhitelistTeams,
+			BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
+			BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
+			BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+			DismissStaleApprovals:         bp.DismissStaleApprovals,
+			RequireSignedCommits:          bp.RequireSignedCommits,
+			ProtectedFilePatterns:         bp.ProtectedFilePatterns,
+			UnprotectedFilePatterns:       bp.UnprotectedFilePatterns,
+		})
+	}
+
+	if setting.DisableWebhooks {
+		// leave the webhooks unmanaged
+		config.Webhooks = nil
+	} else {
+		hooks, err := webhook.ListWebhooksByOpts(ctx, &webhook.ListWebhookOptions{RepoID: repo.ID})
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ListWebhooksByOpts", err)
+			return nil, false
+		}
+		for _, w := range hooks {
+			hook := convert.ToHook(ctx.Repo.RepoLink, w)
+			config.Webhooks = append(config.Webhooks, &api.CreateHookOption{
+				Type:         hook.Type,
+				Config:       hook.Config,
+				Events:       hook.Events,
+				BranchFilter: w.BranchFilter,
+				Active:       hook.Active,
+			})
+		}
+	}
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLabelsByRepoID", err)
+		return nil, false
+	}
+	for _, label := range labels {
+		config.Labels = append(config.Labels, &api.CreateLabelOption{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+		})
+	}
+
+	collaborators, err := repo_model.GetCollaborators(ctx, repo.ID, db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCollaborators", err)
+		return nil, false
+	}
+	for _, collaborator := range collaborators {
+		config.Collaborators = append(config.Collaborators, &api.RepoConfigCollaborator{
+			Username:   collaborator.Name,
+			Permission: collaborator.Collaboration.Mode.String(),
+		})
+	}
+
+	return config, true
+}
+
+// checkRepoConfig validates the items of all sections before anything is changed. If
+// the configuration is invalid, write to `ctx` accordingly
+func checkRepoConfig(ctx *context.APIContext, config *api.RepoConfig) bool {
+	if config.Settings != nil && !checkConfigSettings(ctx, config.Settings) {
+		return false
+	}
+
+	names := make(map[string]bool, len(config.Labels))
+	for _, label := range config.Labels {
+		if label == nil || label.Name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "label name is required")
+			return false
+		}
+		if names[label.Name] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("duplicate label: %s", label.Name))
+			return false
+		}
+		names[label.Name] = true
+		if !checkCreateLabelOption(ctx, label) {
+			return false
+		}
+	}
+
+	protections, err := git_model.GetProtectedBranches(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranches", err)
+		return false
+	}
+	protected := make(map[string]bool, len(protections))
+	for _, protection := range protections {
+		protected[protection.BranchName] = true
+	}
+	names = make(map[string]bool, len(config.BranchProtections))
+	for _, protection := range config.BranchProtections {
+		if protection == nil || protection.BranchName == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "branch name of branch protection is required")
+			return false
+		}
+		if names[protection.BranchName] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("duplicate branch protection: %s", protection.BranchName))
+			return false
+		}
+		names[protection.BranchName] = true
+		// Currently protection must match an actual branch
+		if !protected[protection.BranchName] && !git.IsBranchExist(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(), protection.BranchName) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("branch of branch protection does not exist: %s", protection.BranchName))
+			return false
+		}
+		if !checkConfigBranchProtectionWhitelists(ctx, protection) {
+			return false
+		}
+	}
+
+	if config.Webhooks != nil && setting.DisableWebhooks {
+		ctx.Error(http.StatusForbidden, "", "webhooks are disabled")
+		return false
+	}
+	names = make(map[string]bool, len(config.Webhooks))
+	for _, hook := range config.Webhooks {
+		if hook == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", "webhook is empty")
+			return false
+		}
+		if !utils.CheckCreateHookOption(ctx, hook) {
+			return false
+		}
+		key := configWebhookKey(hook.Type, hook.Config["url"])
+		if names[key] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("duplicate %s webhook: %s", hook.Type, hook.Config["url"]))
+			return false
+		}
+		names[key] = true
+	}
+
+	names = make(map[string]bool, len(config.Collaborators))
+	for _, collaborator := range config.Collaborators {
+		if collaborator == nil || collaborator.Username == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "username of collaborator is required")
+			return false
+		}
+		if perm.ParseAccessMode(collaborator.Permission) == perm.AccessModeNone {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid permission of collaborator %s: %s", collaborator.Username, collaborator.Permission))
+			return false
+		}
+		name := strings.ToLower(collaborator.Username)
+		if names[name] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("duplicate collaborator: %s", collaborator.Username))
+			return false
+		}
+		names[name] = true
+
+		u, err := user_model.GetUserByName(ctx, collaborator.Username)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return false
+		}
+		if !u.IsActive {
+			isCollaborator, err := repo_model.IsCollaborator(ctx, ctx.Repo.Repository.ID, u.ID)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "IsCollaborator", err)
+				return false
+			}
+			if !isCollaborator {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("collaborator's account is inactive: %s", u.Name))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkConfigSettings validates the repository settings of a configuration the same way
+// they are validated when they are applied. If they are invalid, write to `ctx` accordingly
+func checkConfigSettings(ctx *context.APIContext, opts *api.EditRepoOption) bool {
+	repo := ctx.Repo.Repository
+
+	// the visibility of a fork is synced with its base repository
+	if opts.Private != nil && !*opts.Private && !repo.IsFork && repo.IsPrivate && setting.Repository.ForcePrivate && !ctx.Doer.IsAdmin {
+		ctx.Error(http.StatusUnprocessableEntity, "Force Private enabled", fmt.Errorf("cannot change private repository to public"))
+		return false
+	}
+
+	if opts.HasIssues != nil && *opts.HasIssues {
+		if opts.ExternalTracker != nil && !unit_model.TypeExternalTracker.UnitGlobalDisabled() {
+			if !validation.IsValidExternalURL(opts.ExternalTracker.ExternalTrackerURL) {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL", fmt.Errorf("External tracker URL not valid"))
+				return false
+			}
+			if len(opts.ExternalTracker.ExternalTrackerFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(opts.ExternalTracker.ExternalTrackerFormat) {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL format", fmt.Errorf("External tracker URL format not valid"))
+				return false
+			}
+		} else if opts.ExternalTracker == nil && opts.InternalTracker != nil {
+			for _, keywords := range [][]string{opts.InternalTracker.CloseKeywords, opts.InternalTracker.ReopenKeywords} {
+				for _, word := range keywords {
+					if !references.IsValidKeyword(word) {
+						ctx.Error(http.StatusUnprocessableEntity, "Invalid issue keyword", fmt.Errorf("Keyword %q not valid", word))
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	if opts.HasWiki != nil && *opts.HasWiki && opts.ExternalWiki != nil && !unit_model.TypeExternalWiki.UnitGlobalDisabled() {
+		if !validation.IsValidExternalURL(opts.ExternalWiki.ExternalWikiURL) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "Invalid external wiki URL")
+			return false
+		}
+	}
+	return true
+}
+
+// checkConfigBranchProtectionWhitelists checks that the users and teams of the whitelists
+// of a branch protection exist. If they don't, write to `ctx` accordingly
+func checkConfigBranchProtectionWhitelists(ctx *context.APIContext, form *api.CreateBranchProtectionOption) bool {
+	for _, names := range [][]string{form.PushWhitelistUsernames, form.MergeWhitelistUsernames, form.ApprovalsWhitelistUsernames} {
+		if _, err := user_model.GetUserIDsByNames(names, false); err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
+			}
+			return false
+		}
+	}
+
+	repo := ctx.Repo.Repository
+	if !repo.Owner.IsOrganization() {
+		return true
+	}
+	for _, names := range [][]string{form.PushWhitelistTeams, form.MergeWhitelistTeams, form.ApprovalsWhitelistTeams} {
+		if _, err := organization.GetTeamIDsByNames(repo.OwnerID, names, false); err != nil {
+			if organization.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+func configWebhookKey(hookType, url string) string {
+	return hookType + " " + url
+}
+
+// applyConfigLabels makes the labels of the current repository match `labels`. If
+// there is an error, write to `ctx` accordingly
+func applyConfigLabels(ctx *context.APIContext, labels []*api.CreateLabelOption) bool {
+	repo := ctx.Repo.Repository
+
+	existing, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLabelsByRepoID", err)
+		return false
+	}
+	byName := make(map[string]*issues_model.Label, len(existing))
+	for _, label := range existing {
+		byName[label.Name] = label
+	}
+
+	for _, form := range labels {
+		label, has := byName[form.Name]
+		if !has {
+			if _, ok := createLabel(ctx, repo, form); !ok {
+				return false
+			}
+			continue
+		}
+		delete(byName, form.Name)
+		if label.Color == form.Color && label.Description == form.Description {
+			continue
+		}
+		label.Color = form.Color
+		label.Description = form.Description
+		if err := issues_model.UpdateLabel(label); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
+			return false
+		}
+	}
+
+	for _, label := range byName {
+		if err := issues_model.DeleteLabel(repo.ID, label.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteLabel", err)
+			return false
+		}
+	}
+	return true
+}
+
+// applyConfigBranchProtections makes the branch protections of the current repository
+// match `protections`. If there is an error, write to `ctx` accordingly
+func applyConfigBranchProtections(ctx *context.APIContext, protections []*api.CreateBranchProtectionOption) bool {
+	repo := ctx.Repo.Repository
+
+	existing, err := git_model.GetProtectedBranches(repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranches", err)
+		return false
+	}
+	byName := make(map[string]*git_model.ProtectedBranch, len(existing))
+	for _, protection := range existing {
+		byName[protection.BranchName] = protection
+	}
+
+	for _, form := range protections {
+		protection, has := byName[form.BranchName]
+		if !has {
+			if _, ok := createBranchProtection(ctx, repo, form); !ok {
+				return false
+			}
+			continue
+		}
+		delete(byName, form.BranchName)
+		if _, ok := saveBranchProtection(ctx, repo, protection, form); !ok {
+			return false
+		}
+	}
+
+	for _, protection := range byName {
+		if err := git_model.DeleteProtectedBranch(repo.ID, protection.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteProtectedBranch", err)
+			return false
+		}
+	}
+	return true
+}
+
+// applyConfigWebhooks makes the webhooks of the current repository match `hooks`. If
+// there is an error, write to `ctx` accordingly
+func applyConfigWebhooks(ctx *context.APIContext, hooks []*api.CreateHookOption) bool {
+	repo := ctx.Repo.Repository
+
+	existing, err := webhook.ListWebhooksByOpts(ctx, &webhook.ListWebhookOptions{RepoID: repo.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListWebhooksByOpts", err)
+		return false
+	}
+	byKey := make(map[string]*webhook.Webhook, len(existing))
+	var unmatched []*webhook.Webhook
+	for _, w := range existing {
+		key := configWebhookKey(w.Type, w.URL)
+		if _, has := byKey[key]; has {
+			unmatched = append(unmatched, w)
+			continue
+		}
+		byKey[key] = w
+	}
+
+	for _, form := range hooks {
+		key := configWebhookKey(form.Type, form.Config["url"])
+		w, has := byKey[key]
+		if !has {
+			if _, ok := utils.CreateRepoHook(ctx, form, repo.ID); !ok {
+				return false
+			}
+			continue
+		}
+		delete(byKey, key)
+		if !utils.ReplaceRepoHook(ctx, form, w) {
+			return false
+		}
+	}
+
+	for _, w := range byKey {
+		unmatched = append(unmatched, w)
+	}
+	for _, w := range unmatched {
+		if err := webhook.DeleteWebhookByRepoID(repo.ID, w.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteWebhookByRepoID", err)
+			return false
+		}
+	}
+	return true
+}
+
+// applyConfigCollaborators makes the collaborators of the current repository match
+// `collaborators`. If there is an error, write to `ctx` accordingly
+func applyConfigCollaborators(ctx *context.APIContext, collaborators []*api.RepoConfigCollaborator) bool {
+	repo := ctx.Repo.Repository
+
+	existing, err := repo_model.GetCollaborators(ctx, repo.ID, db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCollaborators", err)
+		return false
+	}
+	byID := make(map[int64]*repo_model.Collaborator, len(existing))
+	for _, collaborator := range existing {
+		byID[collaborator.ID] = collaborator
+	}
+
+	for _, form := range collaborators {
+		u, err := user_model.GetUserByName(ctx, form.Username)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return false
+		}
+		mode := perm.ParseAccessMode(form.Permission)
+
+		collaborator, has := byID[u.ID]
+		if has {
+			delete(byID, u.ID)
+			if collaborator.Collaboration.Mode == mode {
+				continue
+			}
+		} else {
+			if !u.IsActive {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("collaborator's account is inactive: %s", u.Name))
+				return false
+			}
+			if err := repo_module.AddCollaborator(repo, u); err != nil {
+				ctx.Error(http.StatusInternalServerError, "AddCollaborator", err)
+				return false
+			}
+		}
+		if err := repo_model.ChangeCollaborationAccessMode(repo, u.ID, mode); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationAccessMode", err)
+			return false
+		}
+	}
+
+	for _, collaborator := range byID {
+		if err := models.DeleteCollaboration(repo, collaborator.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteCollaboration", err)
+			return false
+		}
+	}
+	return true
+}
@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models"
@@ -27,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/common"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
@@ -275,12 +277,21 @@ func GetArchive(ctx *context.APIContext) {
 	//   required: true
 	// - name: archive
 	//   in: path
-	//   description: the git reference for download with attached archive format (e.g. master.zip)
+	//   description: the git reference for download with attached archive format (e.g. master.zip, master.tar.gz, master.tar.zst, master.tar.xz or master.bundle), append `.sha256` or `.sha512` to get the checksum or `.asc` to get the detached signature of the archive. Requests without a format or by an abbreviated commit id are redirected to the canonical URL.
 	//   type: string
 	//   required: true
+	// - name: format
+	//   in: query
+	//   description: archive format used if the reference has no attached format, defaults to tar.gz
+	//   type: string
+	//   enum: [zip, tar.gz, tar.zst, tar.xz, bundle]
 	// responses:
 	//   200:
 	//     description: success
+	//   "302":
+	//     description: redirect to the canonical URL of the archive
+	//   "400":
+	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
@@ -299,16 +310,13 @@ func GetArchive(ctx *context.APIContext) {
 }
 
 func archiveDownload(ctx *context.APIContext) {
-	uri := ctx.Params("*")
-	aReq, err := archiver_service.NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri)
-	if err != nil {
-		if errors.Is(err, archiver_service.ErrUnknownArchiveFormat{}) {
-			ctx.Error(http.StatusBadRequest, "unknown archive format", err)
-		} else if errors.Is(err, archiver_service.RepoRefNotFoundError{}) {
-			ctx.Error(http.StatusNotFound, "unrecognized reference", err)
-		} else {
-			ctx.ServerError("archiver_service.NewRequest", err)
-		}
+	uri, suffix := archiver_service.SplitArchiveSuffix(ctx.Params("*"))
+	aReq := newArchiveRequest(ctx, uri)
+	if ctx.Written() {
+		return
+	}
+
+	if common.RedirectToCanonicalArchive(ctx.Context, ctx.Repo.Repository.APIURL()+"/archive", aReq, suffix) {
 		return
 	}
 
@@ -318,9 +326,40 @@ func archiveDownload(ctx *context.APIContext) {
 		return
 	}
 
+	if suffix != "" {
+		downloadArchiveSuffix(ctx, aReq.GetArchiveName(), archiver, suffix)
+		return
+	}
+
 	download(ctx, aReq.GetArchiveName(), archiver)
 }
 
+// downloadArchiveSuffix serves the checksum or signature of an archive
+func downloadArchiveSuffix(ctx *context.APIContext, archiveName string, archiver *repo_model.RepoArchiver, suffix string) {
+	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
+
+	if suffix == archiver_service.SuffixSignature {
+		signature, err := archiver_service.GetArchiveSignature(ctx, ctx.Repo.Repository, archiver)
+		if err != nil {
+			if errors.Is(err, archiver_service.ErrArchiveSignatureUnavailable) {
+				ctx.NotFound(err)
+			} else {
+				ctx.ServerError("GetArchiveSignature", err)
+			}
+			return
+		}
+		ctx.ServeContent(downloadName+suffix, strings.NewReader(signature), archiver.CreatedUnix.AsLocalTime())
+		return
+	}
+
+	checksum, err := archiver_service.GetArchiveChecksum(archiver, suffix, downloadName)
+	if err != nil {
+		ctx.ServerError("GetArchiveChecksum", err)
+		return
+	}
+	ctx.PlainText(http.StatusOK, checksum)
+}
+
 func download(ctx *context.APIContext, archiveName string, archiver *repo_model.RepoArchiver) {
 	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
 
@@ -344,6 +383,136 @@ func download(ctx *context.APIContext, archiveName string, archiver *repo_model.
 	ctx.ServeContent(downloadName, fr, archiver.CreatedUnix.AsLocalTime())
 }
 
+// newArchiveRequest creates the archive request for the uri.
+// If the uri has no extension, the format is taken from the "format" parameter and defaults to tar.gz.
+func newArchiveRequest(ctx *context.APIContext, uri string) *archiver_service.ArchiveRequest {
+	aReq, err := archiver_service.NewRequestWithFormat(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri, ctx.FormTrim("format"))
+	if err != nil {
+		if errors.Is(err, archiver_service.ErrUnknownArchiveFormat{}) {
+			ctx.Error(http.StatusBadRequest, "unknown archive format", err)
+		} else if errors.Is(err, archiver_service.RepoRefNotFoundError{}) || git.IsErrNotExist(err) {
+			ctx.Error(http.StatusNotFound, "unrecognized reference", err)
+		} else {
+			ctx.ServerError("archiver_service.NewRequestWithFormat", err)
+		}
+		return nil
+	}
+	return aReq
+}
+
+func writeArchiveStatus(ctx *context.APIContext, aReq *archiver_service.ArchiveRequest, status archiver_service.ArchiveStatus) {
+	archivePath := util.PathEscapeSegments(aReq.CanonicalPath())
+	statusURL := ctx.Repo.Repository.APIURL() + "/archive_status/" + archivePath
+
+	httpStatus := http.StatusOK
+	if status == archiver_service.ArchiveStatusPending {
+		httpStatus = http.StatusAccepted
+		ctx.Resp.Header().Set("Location", statusURL)
+	}
+
+	ctx.JSON(httpStatus, &api.RepoArchiveStatus{
+		Status:      string(status),
+		CommitID:    aReq.CommitID,
+		StatusURL:   statusURL,
+		DownloadURL: ctx.Repo.Repository.APIURL() + "/archive/" + archivePath,
+	})
+}
+
+// CreateArchive queues the generation of an archive without waiting for it
+func CreateArchive(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/archive/{archive} repository repoCreateArchive
+	// ---
+	// summary: Request the generation of an archive of a repository without waiting for it
+	// description: A failed archive gets generated again. Poll the returned status URL until the archive is ready and download it afterwards.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: archive
+	//   in: path
+	//   description: the git reference with attached archive format (e.g. master.zip, master.tar.gz, master.tar.zst, master.tar.xz or master.bundle)
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "202":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	aReq := newArchiveRequest(ctx, ctx.Params("*"))
+	if ctx.Written() {
+		return
+	}
+
+	status, _, err := aReq.Enqueue(ctx)
+	if err != nil {
+		ctx.ServerError("Enqueue", err)
+		return
+	}
+
+	writeArchiveStatus(ctx, aReq, status)
+}
+
+// GetArchiveStatus gets the generation status of an archive
+func GetArchiveStatus(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/archive_status/{archive} repository repoGetArchiveStatus
+	// ---
+	// summary: Get the generation status of an archive of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: archive
+	//   in: path
+	//   description: the git reference with attached archive format (e.g. master.zip, master.tar.gz, master.tar.zst, master.tar.xz or master.bundle)
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "202":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	aReq := newArchiveRequest(ctx, ctx.Params("*"))
+	if ctx.Written() {
+		return
+	}
+
+	status, _, err := aReq.Status(ctx)
+	if err != nil {
+		ctx.ServerError("Status", err)
+		return
+	}
+
+	writeArchiveStatus(ctx, aReq, status)
+}
+
 // GetEditorconfig get editor config of a repository
 func GetEditorconfig(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/editorconfig/{filepath} repository repoGetEditorConfig
@@ -16,6 +16,8 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -153,3 +155,212 @@ func CreateFork(ctx *context.APIContext) {
 	// TODO change back to 201
 	ctx.JSON(http.StatusAccepted, convert.ToRepo(fork, perm.AccessModeOwner))
 }
+
+func toForkNetwork(ctx *context.APIContext, node *repo_service.ForkNetworkNode) (*api.ForkNetwork, error) {
+	access, err := access_model.AccessLevel(ctx.Doer, node.Repo)
+	if err != nil {
+		return nil, err
+	}
+	network := &api.ForkNetwork{
+		Repository: convert.ToRepo(node.Repo, access),
+		Forks:      make([]*api.ForkNetwork, 0, len(node.Forks)),
+	}
+	for _, fork := range node.Forks {
+		apiFork, err := toForkNetwork(ctx, fork)
+		if err != nil {
+			return nil, err
+		}
+		network.Forks = append(network.Forks, apiFork)
+	}
+	return network, nil
+}
+
+// GetForkNetwork get the fork network of a repository
+func GetForkNetwork(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/forks/network repository repoGetForkNetwork
+	// ---
+	// summary: Get the fork network of a repository
+	// description: The network starts at the root repository, the topmost parent readable by the user, and includes the forks of forks. Repositories the user can't read are left out together with their forks.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: depth
+	//   in: query
+	//   description: levels of forks below the root repository to include, at most 10
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: maximum number of forks to check, forks the user can't read count towards the limit
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkNetwork"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	depth := ctx.FormInt("depth")
+	if depth <= 0 || depth > repo_service.MaxForkNetworkDepth {
+		depth = repo_service.MaxForkNetworkDepth
+	}
+	limit := ctx.FormInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	root, err := repo_service.GetForkNetwork(ctx, ctx.Doer, ctx.Repo.Repository, depth, limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetForkNetwork", err)
+		return
+	}
+	network, err := toForkNetwork(ctx, root)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toForkNetwork", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, network)
+}
+
+// CompareForkWithRoot compare a branch of a fork with a branch of the root of its fork network
+func CompareForkWithRoot(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/fork/compare repository repoCompareForkWithRoot
+	// ---
+	// summary: Compare a fork with the root repository of its fork network
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch of the fork, defaults to its default branch
+	//   type: string
+	// - name: root_branch
+	//   in: query
+	//   description: branch of the root repository, defaults to its default branch
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkComparison"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repo := ctx.Repo.Repository
+	root, err := repo_service.GetRootRepository(ctx, ctx.Doer, repo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRootRepository", err)
+		return
+	}
+	if root.ID == repo.ID {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork of a readable repository")
+		return
+	}
+
+	branch := ctx.FormTrim("branch")
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+	rootBranch := ctx.FormTrim("root_branch")
+	if rootBranch == "" {
+		rootBranch = root.DefaultBranch
+	}
+	if !git.IsBranchExist(ctx, repo.RepoPath(), branch) {
+		ctx.NotFound("branch does not exist")
+		return
+	}
+	if !git.IsBranchExist(ctx, root.RepoPath(), rootBranch) {
+		ctx.NotFound("root branch does not exist")
+		return
+	}
+
+	comparison, err := repo_service.CompareForkWithRoot(ctx, repo, root, branch, rootBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CompareForkWithRoot", err)
+		return
+	}
+	access, err := access_model.AccessLevel(ctx.Doer, root)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ForkComparison{
+		Root:       convert.ToRepo(root, access),
+		RootBranch: comparison.RootBranch,
+		Branch:     comparison.Branch,
+		MergeBase:  comparison.MergeBase,
+		AheadBy:    comparison.AheadBy,
+		BehindBy:   comparison.BehindBy,
+	})
+}
+
+// DetachFork convert a fork into an independent repository
+func DetachFork(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/fork/detach repository repoDetachFork
+	// ---
+	// summary: Detach a fork from its parent repository, converting it into an independent repository
+	// description: The forks of the repository stay forks of it.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repo := ctx.Repo.Repository
+	if !repo.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork")
+		return
+	}
+	if !ctx.Repo.Owner.CanCreateRepo() {
+		ctx.Error(http.StatusForbidden, "", fmt.Sprintf("cannot create more than %d repositories", ctx.Repo.Owner.MaxCreationLimit()))
+		return
+	}
+
+	if err := repo_service.ConvertForkToNormalRepository(repo); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ConvertForkToNormalRepository", err)
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByID(repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepo(repo, ctx.Repo.AccessMode))
+}
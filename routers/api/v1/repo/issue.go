@@ -19,6 +19,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
@@ -30,6 +31,8 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	issue_service "code.gitea.io/gitea/services/issue"
+
+	"xorm.io/builder"
 )
 
 // SearchIssues searches for issues across the repositories that the user has access to
@@ -496,6 +499,210 @@ func ListIssues(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
 }
 
+// ListOrgIssues lists the issues of all repositories of an organization
+func ListOrgIssues(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issues organization orgListIssues
+	// ---
+	// summary: List the issues of all repositories of an organization the user has access to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: whether issue is open or closed
+	//   type: string
+	//   enum: [closed, open, all]
+	// - name: labels
+	//   in: query
+	//   description: comma separated list of label names. Fetch only issues that have any of this labels. Non existent labels are discarded
+	//   type: string
+	// - name: q
+	//   in: query
+	//   description: search string
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: milestones
+	//   in: query
+	//   description: comma separated list of milestone names. Fetch only issues that have any of this milestones. Non existent milestones are discarded
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only show items updated after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
+	// - name: before
+	//   in: query
+	//   description: Only show items updated before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
+	// - name: created_by
+	//   in: query
+	//   description: Only show items which were created by the the given user
+	//   type: string
+	// - name: assigned_by
+	//   in: query
+	//   description: Only show items for which the given user is assigned
+	//   type: string
+	// - name: mentioned_by
+	//   in: query
+	//   description: Only show items in which the given user was mentioned
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	before, since, err := context.GetQueryBeforeSince(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	var isClosed util.OptionalBool
+	switch ctx.FormString("state") {
+	case "closed":
+		isClosed = util.OptionalBoolTrue
+	case "all":
+		isClosed = util.OptionalBoolNone
+	default:
+		isClosed = util.OptionalBoolFalse
+	}
+
+	var isPull util.OptionalBool
+	switch ctx.FormString("type") {
+	case "pulls":
+		isPull = util.OptionalBoolTrue
+	case "issues":
+		isPull = util.OptionalBoolFalse
+	default:
+		isPull = util.OptionalBoolNone
+	}
+
+	// only list issues and pulls of the repositories in which the user can access the respective unit
+	var issueRepoIDs, pullRepoIDs []int64
+	if !isPull.IsTrue() {
+		if issueRepoIDs, err = repo_model.FindAccessibleOrgRepoIDsByUnit(ctx, ctx.Doer, ctx.Org.Organization.ID, unit.TypeIssues); err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindAccessibleOrgRepoIDsByUnit", err)
+			return
+		}
+	}
+	if !isPull.IsFalse() {
+		if pullRepoIDs, err = repo_model.FindAccessibleOrgRepoIDsByUnit(ctx, ctx.Doer, ctx.Org.Organization.ID, unit.TypePullRequests); err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindAccessibleOrgRepoIDsByUnit", err)
+			return
+		}
+	}
+	repoCond := builder.Or(
+		builder.Eq{"issue.is_pull": false}.And(builder.In("issue.repo_id", issueRepoIDs)),
+		builder.Eq{"issue.is_pull": true}.And(builder.In("issue.repo_id", pullRepoIDs)),
+	)
+
+	var issues []*issues_model.Issue
+	var filteredCount int64
+
+	keyword := ctx.FormTrim("q")
+	if strings.IndexByte(keyword, 0) >= 0 {
+		keyword = ""
+	}
+	var issueIDs []int64
+	if len(keyword) > 0 {
+		repoIDSet := make(map[int64]struct{}, len(issueRepoIDs)+len(pullRepoIDs))
+		for _, id := range issueRepoIDs {
+			repoIDSet[id] = struct{}{}
+		}
+		for _, id := range pullRepoIDs {
+			repoIDSet[id] = struct{}{}
+		}
+		repoIDs := container.KeysInt64(repoIDSet)
+		if len(repoIDs) > 0 {
+			if issueIDs, err = issue_indexer.SearchIssuesByKeyword(ctx, repoIDs, keyword); err != nil {
+				ctx.Error(http.StatusInternalServerError, "SearchIssuesByKeyword", err)
+				return
+			}
+		}
+	}
+
+	var includedLabelNames []string
+	if labels := ctx.FormTrim("labels"); len(labels) > 0 {
+		includedLabelNames = strings.Split(labels, ",")
+	}
+
+	var includedMilestones []string
+	if milestones := ctx.FormTrim("milestones"); len(milestones) > 0 {
+		includedMilestones = strings.Split(milestones, ",")
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	createdByID := getUserIDForFilter(ctx, "created_by")
+	if ctx.Written() {
+		return
+	}
+	assignedByID := getUserIDForFilter(ctx, "assigned_by")
+	if ctx.Written() {
+		return
+	}
+	mentionedByID := getUserIDForFilter(ctx, "mentioned_by")
+	if ctx.Written() {
+		return
+	}
+
+	// Only fetch the issues if we either don't have a keyword or the search returned issues
+	// This would otherwise return all issues if no issues were found by the search.
+	if len(keyword) == 0 || len(issueIDs) > 0 {
+		issuesOpt := &issues_model.IssuesOptions{
+			ListOptions:        listOptions,
+			RepoCond:           repoCond,
+			IsClosed:           isClosed,
+			IssueIDs:           issueIDs,
+			IncludedLabelNames: includedLabelNames,
+			IncludeMilestones:  includedMilestones,
+			IsPull:             isPull,
+			UpdatedBeforeUnix:  before,
+			UpdatedAfterUnix:   since,
+			PosterID:           createdByID,
+			AssigneeID:         assignedByID,
+			MentionedID:        mentionedByID,
+		}
+
+		if issues, err = issues_model.Issues(issuesOpt); err != nil {
+			ctx.Error(http.StatusInternalServerError, "Issues", err)
+			return
+		}
+
+		issuesOpt.ListOptions = db.ListOptions{
+			Page: -1,
+		}
+		if filteredCount, err = issues_model.CountIssues(issuesOpt); err != nil {
+			ctx.Error(http.StatusInternalServerError, "CountIssues", err)
+			return
+		}
+	}
+
+	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
+	ctx.SetTotalCountHeader(filteredCount)
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
+
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
 	userName := ctx.FormString(queryName)
 	if len(userName) == 0 {
@@ -0,0 +1,468 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/upload"
+	"code.gitea.io/gitea/modules/web"
+	attachment_service "code.gitea.io/gitea/services/attachment"
+)
+
+// getIssueForAttachments loads the issue of the request, it responds with 404 if attachments are disabled
+func getIssueForAttachments(ctx *context.APIContext) *issues_model.Issue {
+	if !setting.Attachment.Enabled {
+		ctx.NotFound()
+		return nil
+	}
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
+
+// canEditIssueAttachments checks if the doer can change the attachments of an issue or a comment written by the poster.
+// It responds with 403 if not.
+func canEditIssueAttachments(ctx *context.APIContext, issue *issues_model.Issue, posterID int64) bool {
+	if ctx.Doer.ID != posterID && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "user should be the poster or have write permission")
+		return false
+	}
+	return true
+}
+
+// getIssueAttachment loads the attachment of the request which must belong to the issue or the comment
+func getIssueAttachment(ctx *context.APIContext, issueID, commentID int64) *repo_model.Attachment {
+	attach, err := repo_model.GetAttachmentByID(ctx, ctx.ParamsInt64(":attachment_id"))
+	if err != nil {
+		if repo_model.IsErrAttachmentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAttachmentByID", err)
+		}
+		return nil
+	}
+	if attach.IssueID != issueID || attach.CommentID != commentID {
+		ctx.NotFound()
+		return nil
+	}
+	return attach
+}
+
+// pasteIssueAttachment stores the request body as attachment
+func pasteIssueAttachment(ctx *context.APIContext, issueID, commentID int64) *repo_model.Attachment {
+	name := ctx.FormTrim("name")
+	if name == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "name is required")
+		return nil
+	}
+
+	attach, err := attachment_service.UploadIssueAttachment(ctx.Req.Body, ctx.Doer.ID, ctx.Repo.Repository.ID, issueID, commentID, name)
+	if err != nil {
+		handleIssueAttachmentError(ctx, err)
+		return nil
+	}
+	return attach
+}
+
+func handleIssueAttachmentError(ctx *context.APIContext, err error) {
+	switch {
+	case upload.IsErrFileTypeForbidden(err), attachment_service.IsErrInvalidAttachmentURL(err):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	case attachment_service.IsErrAttachmentTooLarge(err):
+		ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+	default:
+		ctx.Error(http.StatusInternalServerError, "UploadIssueAttachment", err)
+	}
+}
+
+// attachmentMarkdown returns the markdown to reference the attachment, images are embedded like pasted images in the editor
+func attachmentMarkdown(attach *repo_model.Attachment) string {
+	link := "/attachments/" + attach.UUID
+	if strings.HasPrefix(mime.TypeByExtension(path.Ext(attach.Name)), "image/") {
+		return fmt.Sprintf("![%s](%s)", attach.Name, link)
+	}
+	return fmt.Sprintf("[%s](%s)", attach.Name, link)
+}
+
+// ListIssueAttachments lists the attachments of an issue
+func ListIssueAttachments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/assets issue issueListAttachments
+	// ---
+	// summary: List the attachments of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+
+	attachments, err := repo_model.GetAttachmentsByIssueID(ctx, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAttachmentsByIssueID", err)
+		return
+	}
+
+	apiAttachments := make([]*api.Attachment, 0, len(attachments))
+	for _, attach := range attachments {
+		apiAttachments = append(apiAttachments, convert.ToAttachment(attach))
+	}
+	ctx.JSON(http.StatusOK, apiAttachments)
+}
+
+// GetIssueAttachment gets an attachment of an issue
+func GetIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/assets/{attachment_id} issue issueGetAttachment
+	// ---
+	// summary: Get an attachment of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Attachment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, issue.ID, 0)
+	if attach == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAttachment(attach))
+}
+
+// CreateIssueAttachment uploads an attachment of an issue
+func CreateIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/assets issue issueCreateAttachment
+	// ---
+	// summary: Upload an attachment of an issue
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the attachment
+	//   type: string
+	//   required: false
+	// - name: attachment
+	//   in: formData
+	//   description: attachment to upload
+	//   type: file
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, issue, issue.PosterID) {
+		return
+	}
+
+	file, header, err := ctx.Req.FormFile("attachment")
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "FormFile", err)
+		return
+	}
+	defer file.Close()
+
+	filename := header.Filename
+	if query := ctx.FormString("name"); query != "" {
+		filename = query
+	}
+
+	attach, err := attachment_service.UploadIssueAttachment(file, ctx.Doer.ID, ctx.Repo.Repository.ID, issue.ID, 0, filename)
+	if err != nil {
+		handleIssueAttachmentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAttachment(attach))
+}
+
+// CreateIssueAttachmentFromURL creates an attachment of an issue from a file downloaded by the server
+func CreateIssueAttachmentFromURL(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/assets/url issue issueCreateAttachmentFromURL
+	// ---
+	// summary: Create an attachment of an issue from a file downloaded by the server
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAttachmentFromURLOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateAttachmentFromURLOptions)
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, issue, issue.PosterID) {
+		return
+	}
+
+	attach, err := attachment_service.FetchIssueAttachment(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, issue.ID, 0, form.URL, form.Name)
+	if err != nil {
+		handleIssueAttachmentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAttachment(attach))
+}
+
+// PasteIssueAttachment uploads the request body as attachment of an issue and returns a markdown reference to it
+func PasteIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/assets/paste issue issuePasteAttachment
+	// ---
+	// summary: Upload the request body as attachment of an issue and get a markdown reference to it
+	// consumes:
+	// - application/octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the attachment
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: content of the attachment
+	//   schema:
+	//     type: string
+	//     format: binary
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PastedAttachment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, issue, issue.PosterID) {
+		return
+	}
+
+	attach := pasteIssueAttachment(ctx, issue.ID, 0)
+	if attach == nil {
+		return
+	}
+	ctx.JSON(http.StatusCreated, &api.PastedAttachment{
+		Attachment: convert.ToAttachment(attach),
+		Markdown:   attachmentMarkdown(attach),
+	})
+}
+
+// DeleteIssueAttachment deletes an attachment of an issue
+func DeleteIssueAttachment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/assets/{attachment_id} issue issueDeleteAttachment
+	// ---
+	// summary: Delete an attachment of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForAttachments(ctx)
+	if issue == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, issue, issue.PosterID) {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, issue.ID, 0)
+	if attach == nil {
+		return
+	}
+	if err := repo_model.DeleteAttachment(attach, true); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
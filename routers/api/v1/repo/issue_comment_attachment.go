@@ -0,0 +1,404 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	attachment_service "code.gitea.io/gitea/services/attachment"
+)
+
+// getCommentForAttachments loads the comment of the request, it responds with 404 if attachments are disabled
+func getCommentForAttachments(ctx *context.APIContext) *issues_model.Comment {
+	if !setting.Attachment.Enabled {
+		ctx.NotFound()
+		return nil
+	}
+	comment, err := issues_model.GetCommentByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrCommentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommentByID", err)
+		}
+		return nil
+	}
+	if err := comment.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return nil
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || comment.Type != issues_model.CommentTypeComment ||
+		!ctx.Repo.CanReadIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	comment.Issue.Repo = ctx.Repo.Repository
+	return comment
+}
+
+// ListIssueCommentAttachments lists the attachments of a comment
+func ListIssueCommentAttachments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/assets issue issueCommentListAttachments
+	// ---
+	// summary: List the attachments of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+
+	if err := comment.LoadAttachments(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttachments", err)
+		return
+	}
+	attachments := comment.Attachments
+
+	apiAttachments := make([]*api.Attachment, 0, len(attachments))
+	for _, attach := range attachments {
+		apiAttachments = append(apiAttachments, convert.ToAttachment(attach))
+	}
+	ctx.JSON(http.StatusOK, apiAttachments)
+}
+
+// GetIssueCommentAttachment gets an attachment of a comment
+func GetIssueCommentAttachment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/assets/{attachment_id} issue issueCommentGetAttachment
+	// ---
+	// summary: Get an attachment of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Attachment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, comment.IssueID, comment.ID)
+	if attach == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAttachment(attach))
+}
+
+// CreateIssueCommentAttachment uploads an attachment of a comment
+func CreateIssueCommentAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/assets issue issueCommentCreateAttachment
+	// ---
+	// summary: Upload an attachment of a comment
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the attachment
+	//   type: string
+	//   required: false
+	// - name: attachment
+	//   in: formData
+	//   description: attachment to upload
+	//   type: file
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, comment.Issue, comment.PosterID) {
+		return
+	}
+
+	file, header, err := ctx.Req.FormFile("attachment")
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "FormFile", err)
+		return
+	}
+	defer file.Close()
+
+	filename := header.Filename
+	if query := ctx.FormString("name"); query != "" {
+		filename = query
+	}
+
+	attach, err := attachment_service.UploadIssueAttachment(file, ctx.Doer.ID, ctx.Repo.Repository.ID, comment.IssueID, comment.ID, filename)
+	if err != nil {
+		handleIssueAttachmentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAttachment(attach))
+}
+
+// CreateIssueCommentAttachmentFromURL creates an attachment of a comment from a file downloaded by the server
+func CreateIssueCommentAttachmentFromURL(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/assets/url issue issueCommentCreateAttachmentFromURL
+	// ---
+	// summary: Create an attachment of a comment from a file downloaded by the server
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateAttachmentFromURLOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateAttachmentFromURLOptions)
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, comment.Issue, comment.PosterID) {
+		return
+	}
+
+	attach, err := attachment_service.FetchIssueAttachment(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, comment.IssueID, comment.ID, form.URL, form.Name)
+	if err != nil {
+		handleIssueAttachmentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAttachment(attach))
+}
+
+// PasteIssueCommentAttachment uploads the request body as attachment of a comment and returns a markdown reference to it
+func PasteIssueCommentAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/assets/paste issue issueCommentPasteAttachment
+	// ---
+	// summary: Upload the request body as attachment of a comment and get a markdown reference to it
+	// consumes:
+	// - application/octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the attachment
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: content of the attachment
+	//   schema:
+	//     type: string
+	//     format: binary
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PastedAttachment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, comment.Issue, comment.PosterID) {
+		return
+	}
+
+	attach := pasteIssueAttachment(ctx, comment.IssueID, comment.ID)
+	if attach == nil {
+		return
+	}
+	ctx.JSON(http.StatusCreated, &api.PastedAttachment{
+		Attachment: convert.ToAttachment(attach),
+		Markdown:   attachmentMarkdown(attach),
+	})
+}
+
+// DeleteIssueCommentAttachment deletes an attachment of a comment
+func DeleteIssueCommentAttachment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/assets/{attachment_id} issue issueCommentDeleteAttachment
+	// ---
+	// summary: Delete an attachment of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getCommentForAttachments(ctx)
+	if comment == nil {
+		return
+	}
+	if !canEditIssueAttachments(ctx, comment.Issue, comment.PosterID) {
+		return
+	}
+
+	attach := getIssueAttachment(ctx, comment.IssueID, comment.ID)
+	if attach == nil {
+		return
+	}
+	if err := repo_model.DeleteAttachment(attach, true); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
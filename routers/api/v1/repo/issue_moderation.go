@@ -0,0 +1,498 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+// getModeratedComment returns the comment of the request if the doer can read it
+func getModeratedComment(ctx *context.APIContext) *issues_model.Comment {
+	comment, err := issues_model.GetCommentByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrCommentNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommentByID", err)
+		}
+		return nil
+	}
+
+	if err = comment.LoadIssueCtx(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return nil
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || !ctx.Repo.CanReadIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	comment.Issue.Repo = ctx.Repo.Repository
+	return comment
+}
+
+// ListIssueCommentRevisions list the revisions of the content of a comment
+func ListIssueCommentRevisions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/revisions issue issueListCommentRevisions
+	// ---
+	// summary: List the revisions of the content of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommentRevisionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getModeratedComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	histories, err := issues_model.FindIssueContentHistories(ctx, comment.IssueID, comment.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIssueContentHistories", err)
+		return
+	}
+
+	editors := make(map[int64]*user_model.User)
+	revisions := make([]*api.CommentRevision, 0, len(histories))
+	for _, h := range histories {
+		editor, ok := editors[h.PosterID]
+		if !ok {
+			editor, err = user_model.GetUserByIDCtx(ctx, h.PosterID)
+			if err != nil {
+				if !user_model.IsErrUserNotExist(err) {
+					ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+					return
+				}
+				editor = user_model.NewGhostUser()
+			}
+			editors[h.PosterID] = editor
+		}
+		revisions = append(revisions, convert.ToCommentRevision(h, editor))
+	}
+
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// HideIssueComment hides a comment
+func HideIssueComment(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/comments/{id}/hide issue issueHideComment
+	// ---
+	// summary: Hide a comment
+	// description: The comment is collapsed for everyone but its content is kept. The moderation is recorded in the moderation log of the repository.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/HideIssueCommentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.HideIssueCommentOption)
+
+	comment := getModeratedComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if comment.Type != issues_model.CommentTypeComment {
+		ctx.Error(http.StatusUnprocessableEntity, "", "only comments can be hidden")
+		return
+	}
+	if !issues_model.IsValidCommentHideReason(form.Reason) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown reason %q", form.Reason))
+		return
+	}
+
+	if err := comment_service.HideComment(ctx.Doer, comment, form.Reason); err != nil {
+		ctx.Error(http.StatusInternalServerError, "HideComment", err)
+		return
+	}
+
+	if err := comment.LoadPoster(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadPoster", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToComment(comment))
+}
+
+// UnhideIssueComment makes a hidden comment visible again
+func UnhideIssueComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/hide issue issueUnhideComment
+	// ---
+	// summary: Make a hidden comment visible again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getModeratedComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	if comment.IsHidden() {
+		if err := comment_service.UnhideComment(ctx.Doer, comment); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UnhideComment", err)
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// LockIssue locks the conversation of an issue
+func LockIssue(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/lock issue issueLock
+	// ---
+	// summary: Lock the conversation of an issue or pull request
+	// description: Only users with write access can comment on a locked conversation. A lock with a duration is removed automatically when it expires.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/LockIssueOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.LockIssueOption)
+
+	issue := getIssueForLock(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if issue.IsLocked {
+		ctx.Error(http.StatusConflict, "", "the conversation is already locked")
+		return
+	}
+
+	if form.Reason != "" {
+		valid := false
+		for _, reason := range setting.Repository.Issue.LockReasons {
+			if reason == form.Reason {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown reason %q", form.Reason))
+			return
+		}
+	}
+
+	var until timeutil.TimeStamp
+	if form.Duration != "" {
+		duration, err := time.ParseDuration(form.Duration)
+		if err != nil || duration <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid duration %q", form.Duration))
+			return
+		}
+		until = timeutil.TimeStamp(time.Now().Add(duration).Unix())
+	}
+
+	if err := issues_model.LockIssue(&issues_model.IssueLockOptions{
+		Doer:   ctx.Doer,
+		Issue:  issue,
+		Reason: form.Reason,
+		Until:  until,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LockIssue", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnlockIssue unlocks the conversation of an issue
+func UnlockIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/lock issue issueUnlock
+	// ---
+	// summary: Unlock the conversation of an issue or pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForLock(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.UnlockIssue(&issues_model.IssueLockOptions{
+		Doer:  ctx.Doer,
+		Issue: issue,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnlockIssue", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getIssueForLock(ctx *context.APIContext) *issues_model.Issue {
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Status(http.StatusForbidden)
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
+
+// ListModerationLogs list the moderation log of a repository
+func ListModerationLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/moderation/log repository repoListModerationLogs
+	// ---
+	// summary: List the moderations of the issues and comments of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: action
+	//   in: query
+	//   description: filter by the action
+	//   type: string
+	//   enum: [hide_comment, unhide_comment, edit_comment, delete_comment, lock, unlock]
+	// - name: index
+	//   in: query
+	//   description: filter by the index of the issue
+	//   type: integer
+	//   format: int64
+	// - name: doer
+	//   in: query
+	//   description: filter by the username of the moderator
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ModerationLogList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := &issues_model.ModerationLogSearchOptions{
+		RepoID:    ctx.Repo.Repository.ID,
+		Action:    issues_model.ModerationAction(ctx.FormTrim("action")),
+		Paginator: &listOptions,
+	}
+	if opts.Action != "" && !opts.Action.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown action %q", opts.Action))
+		return
+	}
+
+	if index := ctx.FormInt64("index"); index != 0 {
+		issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, index)
+		if err != nil {
+			if issues_model.IsErrIssueNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+			}
+			return
+		}
+		opts.IssueID = issue.ID
+	}
+
+	if name := ctx.FormTrim("doer"); name != "" {
+		doer, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		opts.DoerID = doer.ID
+	}
+
+	logs, count, err := issues_model.SearchModerationLogs(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchModerationLogs", err)
+		return
+	}
+	if err := logs.LoadAttributes(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	apiLogs := make([]*api.ModerationLog, 0, len(logs))
+	for _, l := range logs {
+		apiLogs = append(apiLogs, convert.ToModerationLog(l))
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiLogs)
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
@@ -54,6 +56,8 @@ func AddIssueSubscription(ctx *context.APIContext) {
 	//     description: Successfully Subscribed
 	//   "304":
 	//     description: User can only subscribe itself if he is no admin
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
@@ -98,6 +102,8 @@ func DelIssueSubscription(ctx *context.APIContext) {
 	//     description: Successfully Unsubscribed
 	//   "304":
 	//     description: User can only subscribe itself if he is no admin
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
@@ -127,10 +133,17 @@ func setIssueSubscription(ctx *context.APIContext, watch bool) {
 		return
 	}
 
-	// only admin and user for itself can change subscription
-	if user.ID != ctx.Doer.ID && !ctx.Doer.IsAdmin {
-		ctx.Error(http.StatusForbidden, "User", fmt.Errorf("%s is not permitted to change subscriptions for %s", ctx.Doer.Name, user.Name))
-		return
+	// admins, the user for itself and team members who can write to the issues of the repository can change subscriptions
+	reason := issues_model.IssueWatchReasonManual
+	if user.ID != ctx.Doer.ID {
+		if canChange, err := canChangeIssueSubscriptionOf(ctx, issue, user); err != nil {
+			ctx.Error(http.StatusInternalServerError, "canChangeIssueSubscriptionOf", err)
+			return
+		} else if !canChange {
+			ctx.Error(http.StatusForbidden, "User", fmt.Errorf("%s is not permitted to change subscriptions for %s", ctx.Doer.Name, user.Name))
+			return
+		}
+		reason = issues_model.IssueWatchReasonTeam
 	}
 
 	current, err := issues_model.CheckIssueWatch(user, issue)
@@ -146,14 +159,34 @@ func setIssueSubscription(ctx *context.APIContext, watch bool) {
 	}
 
 	// Update watch state
-	if err := issues_model.CreateOrUpdateIssueWatch(user.ID, issue.ID, watch); err != nil {
-		ctx.Error(http.StatusInternalServerError, "CreateOrUpdateIssueWatch", err)
+	if err := issues_model.CreateOrUpdateIssueWatchWithReason(ctx, user.ID, issue.ID, watch, reason); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateOrUpdateIssueWatchWithReason", err)
 		return
 	}
 
 	ctx.Status(http.StatusCreated)
 }
 
+// canChangeIssueSubscriptionOf checks if the doer may change the subscription of another user to an issue.
+// Besides admins, users who can write to the issues of a repository may subscribe the members of the teams of the repository.
+func canChangeIssueSubscriptionOf(ctx *context.APIContext, issue *issues_model.Issue, user *user_model.User) (bool, error) {
+	if ctx.Doer.IsAdmin {
+		return true, nil
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) || !ctx.Repo.Owner.IsOrganization() {
+		return false, nil
+	}
+	teams, err := organization.GetUserRepoTeams(ctx, ctx.Repo.Owner.ID, user.ID, ctx.Repo.Repository.ID)
+	if err != nil || len(teams) == 0 {
+		return false, err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, ctx.Repo.Repository, user)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull), nil
+}
+
 // CheckIssueSubscription check if user is subscribed to an issue
 func CheckIssueSubscription(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/subscriptions/check issue issueCheckSubscription
@@ -197,19 +230,86 @@ func CheckIssueSubscription(ctx *context.APIContext) {
 		return
 	}
 
-	watching, err := issues_model.CheckIssueWatch(ctx.Doer, issue)
+	writeIssueWatchInfo(ctx, issue, ctx.Doer)
+}
+
+// GetIssueSubscription returns the subscription of a user to an issue
+func GetIssueSubscription(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/subscriptions/{user} issue issueGetSubscription
+	// ---
+	// summary: Get the subscription of a user to an issue and the reason of it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: user
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WatchInfo"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
-		ctx.InternalServerError(err)
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+
 		return
 	}
-	ctx.JSON(http.StatusOK, api.WatchInfo{
-		Subscribed:    watching,
-		Ignored:       !watching,
-		Reason:        nil,
+
+	user, err := user_model.GetUserByName(ctx, ctx.Params(":user"))
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+
+		return
+	}
+
+	writeIssueWatchInfo(ctx, issue, user)
+}
+
+func writeIssueWatchInfo(ctx *context.APIContext, issue *issues_model.Issue, user *user_model.User) {
+	reason, err := issues_model.GetIssueWatchReason(ctx, user, issue)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueWatchReason", err)
+		return
+	}
+	info := api.WatchInfo{
+		Subscribed:    reason != issues_model.IssueWatchReasonNone,
+		Ignored:       reason == issues_model.IssueWatchReasonNone,
 		CreatedAt:     issue.CreatedUnix.AsTime(),
 		URL:           issue.APIURL() + "/subscriptions",
 		RepositoryURL: ctx.Repo.Repository.APIURL(),
-	})
+	}
+	if info.Subscribed {
+		info.Reason = string(reason)
+	}
+	ctx.JSON(http.StatusOK, info)
 }
 
 // GetIssueSubscribers return subscribers of an issue
@@ -0,0 +1,153 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strconv"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+func getIssueForTasks(ctx *context.APIContext) *issues_model.Issue {
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
+
+// ListIssueTasks list the tasks of the task list in the body of an issue
+func ListIssueTasks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/tasks issue issueListTasks
+	// ---
+	// summary: List the tasks of the task list in the body of an issue or pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueTaskList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForTasks(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToIssueTaskList(markdown.ParseTasks(issue.Content)))
+}
+
+// EditIssueTask checks or unchecks a task in the body of an issue
+func EditIssueTask(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index}/tasks/{task} issue issueEditTask
+	// ---
+	// summary: Check or uncheck a task in the body of an issue or pull request without replacing the whole body
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: task
+	//   in: path
+	//   description: position of the task in the body, starting at 0
+	//   type: integer
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueTaskOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueTask"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*api.EditIssueTaskOption)
+
+	index, err := strconv.Atoi(ctx.Params(":task"))
+	if err != nil {
+		ctx.NotFound()
+		return
+	}
+
+	issue := getIssueForTasks(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !issue.IsPoster(ctx.Doer.ID) && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	task, err := issue_service.SetTaskChecked(issue, ctx.Doer, index, *form.Checked, form.Text)
+	if err != nil {
+		switch {
+		case issue_service.IsErrTaskNotExist(err):
+			ctx.NotFound()
+		case issue_service.IsErrTaskTextMismatch(err):
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "SetTaskChecked", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToIssueTask(task))
+}
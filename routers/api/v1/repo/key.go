@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
@@ -18,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
@@ -178,6 +180,19 @@ func HandleCheckKeyStringError(ctx *context.APIContext, err error) {
 	}
 }
 
+// KeyExpiryFromOption returns the expiry of the key to create, it is zero if the key never expires.
+// It writes an error response and returns false if the expiry is not in the future.
+func KeyExpiryFromOption(ctx *context.APIContext, form *api.CreateKeyOption) (timeutil.TimeStamp, bool) {
+	if form.ExpiresAt == nil || form.ExpiresAt.IsZero() {
+		return 0, true
+	}
+	if !form.ExpiresAt.After(time.Now()) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "expires_at must be in the future")
+		return 0, false
+	}
+	return timeutil.TimeStamp(form.ExpiresAt.Unix()), true
+}
+
 // HandleAddKeyError handle add key error
 func HandleAddKeyError(ctx *context.APIContext, err error) {
 	switch {
@@ -231,12 +246,25 @@ func CreateDeployKey(ctx *context.APIContext) {
 		return
 	}
 
+	expires, ok := KeyExpiryFromOption(ctx, form)
+	if !ok {
+		return
+	}
+
 	key, err := asymkey_model.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, form.ReadOnly)
 	if err != nil {
 		HandleAddKeyError(ctx, err)
 		return
 	}
 
+	if expires > 0 {
+		if err := asymkey_model.SetDeployKeyExpiry(ctx, key.ID, expires); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetDeployKeyExpiry", err)
+			return
+		}
+		key.ExpiresUnix = expires
+	}
+
 	key.Content = content
 	apiLink := composeDeployKeysAPILink(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
 	ctx.JSON(http.StatusCreated, convert.ToDeployKey(apiLink, key))
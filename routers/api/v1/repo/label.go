@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -146,23 +147,12 @@ func CreateLabel(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.CreateLabelOption)
-	form.Color = strings.Trim(form.Color, " ")
-	if len(form.Color) == 6 {
-		form.Color = "#" + form.Color
-	}
-	if !issues_model.LabelColorPattern.MatchString(form.Color) {
-		ctx.Error(http.StatusUnprocessableEntity, "ColorPattern", fmt.Errorf("bad color code: %s", form.Color))
+	if !checkCreateLabelOption(ctx, form) {
 		return
 	}
 
-	label := &issues_model.Label{
-		Name:        form.Name,
-		Color:       form.Color,
-		RepoID:      ctx.Repo.Repository.ID,
-		Description: form.Description,
-	}
-	if err := issues_model.NewLabel(ctx, label); err != nil {
-		ctx.Error(http.StatusInternalServerError, "NewLabel", err)
+	label, ok := createLabel(ctx, ctx.Repo.Repository, form)
+	if !ok {
 		return
 	}
 
@@ -273,3 +263,33 @@ func DeleteLabel(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// checkCreateLabelOption normalizes and validates the color of the label. If
+// the color is invalid, write to `ctx` accordingly
+func checkCreateLabelOption(ctx *context.APIContext, form *api.CreateLabelOption) bool {
+	form.Color = strings.Trim(form.Color, " ")
+	if len(form.Color) == 6 {
+		form.Color = "#" + form.Color
+	}
+	if !issues_model.LabelColorPattern.MatchString(form.Color) {
+		ctx.Error(http.StatusUnprocessableEntity, "ColorPattern", fmt.Errorf("bad color code: %s", form.Color))
+		return false
+	}
+	return true
+}
+
+// createLabel creates the label specified by `form` in the repository. If
+// there is an error, write to `ctx` accordingly. Return (label, ok)
+func createLabel(ctx *context.APIContext, repo *repo_model.Repository, form *api.CreateLabelOption) (*issues_model.Label, bool) {
+	label := &issues_model.Label{
+		Name:        form.Name,
+		Color:       form.Color,
+		RepoID:      repo.ID,
+		Description: form.Description,
+	}
+	if err := issues_model.NewLabel(ctx, label); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewLabel", err)
+		return nil, false
+	}
+	return label, true
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/outline"
+	"code.gitea.io/gitea/modules/setting"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// GetFileOutline gets the outline of a file
+func GetFileOutline(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/outline/{filepath} repository repoGetFileOutline
+	// ---
+	// summary: Gets the outline of the functions, types and headings of a file
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file in the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileOutline"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !setting.Repository.Outline.Enabled {
+		ctx.NotFound()
+		return
+	}
+
+	o, err := files_service.GetFileOutline(ctx, ctx.Repo.Repository, ctx.Params("*"), ctx.FormTrim("ref"))
+	if err != nil {
+		switch {
+		case git.IsErrNotExist(err):
+			ctx.NotFound("GetFileOutline", err)
+		case errors.Is(err, outline.ErrFileTooLarge):
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+		case files_service.IsErrNotAFile(err), models.IsErrFilenameInvalid(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "GetFileOutline", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, o)
+}
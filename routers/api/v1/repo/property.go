@@ -0,0 +1,94 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListProperties returns the custom properties of a repository
+func ListProperties(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/properties repository repoListProperties
+	// ---
+	// summary: Get the custom properties of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoProperties"
+
+	properties, err := repo_model.GetRepoProperties(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoProperties", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, properties)
+}
+
+// EditProperties changes the custom properties of a repository
+func EditProperties(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/properties repository repoEditProperties
+	// ---
+	// summary: Change the custom properties of a repository
+	// description: The properties must be defined by the organization owning the repository.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoPropertiesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoProperties"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditRepoPropertiesOption)
+	properties := make(map[string]string, len(form.Properties))
+	for name, value := range form.Properties {
+		properties[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	if err := repo_model.SetRepoProperties(ctx, ctx.Repo.Repository, properties); err != nil {
+		if repo_model.IsErrPropertyDefinitionNotExist(err) || repo_model.IsErrInvalidPropertyValue(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetRepoProperties", err)
+		}
+		return
+	}
+
+	ListProperties(ctx)
+}
@@ -7,6 +7,7 @@ package repo
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
@@ -26,6 +27,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
@@ -33,6 +35,7 @@ import (
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/automerge"
 	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/gitdiff"
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -105,28 +108,105 @@ func ListPullRequests(ctx *context.APIContext) {
 		return
 	}
 
+	serveAPIPullRequests(ctx, prs, maxResults, listOptions.PageSize)
+}
+
+// ListOrgPullRequests returns the pull requests of all repositories of an organization
+func ListOrgPullRequests(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/pulls organization orgListPullRequests
+	// ---
+	// summary: List the pull requests of all repositories of an organization the user has access to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: "State of pull request: open or closed (optional)"
+	//   type: string
+	//   enum: [closed, open, all]
+	// - name: sort
+	//   in: query
+	//   description: "Type of sort"
+	//   type: string
+	//   enum: [oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority]
+	// - name: milestone
+	//   in: query
+	//   description: "ID of the milestone"
+	//   type: integer
+	//   format: int64
+	// - name: labels
+	//   in: query
+	//   description: "Label IDs"
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: integer
+	//     format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listOptions := utils.GetListOptions(ctx)
+
+	repoIDs, err := repo_model.FindAccessibleOrgRepoIDsByUnit(ctx, ctx.Doer, ctx.Org.Organization.ID, unit.TypePullRequests)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAccessibleOrgRepoIDsByUnit", err)
+		return
+	}
+
+	prs, maxResults, err := issues_model.PullRequestsInRepos(repoIDs, &issues_model.PullRequestsOptions{
+		ListOptions: listOptions,
+		State:       ctx.FormTrim("state"),
+		SortType:    ctx.FormTrim("sort"),
+		Labels:      ctx.FormStrings("labels"),
+		MilestoneID: ctx.FormInt64("milestone"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "PullRequestsInRepos", err)
+		return
+	}
+
+	serveAPIPullRequests(ctx, prs, maxResults, listOptions.PageSize)
+}
+
+func serveAPIPullRequests(ctx *context.APIContext, prs []*issues_model.PullRequest, maxResults int64, pageSize int) {
 	apiPrs := make([]*api.PullRequest, len(prs))
 	for i := range prs {
-		if err = prs[i].LoadIssue(); err != nil {
+		if err := prs[i].LoadIssue(); err != nil {
 			ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
 			return
 		}
-		if err = prs[i].LoadAttributes(); err != nil {
+		if err := prs[i].LoadAttributes(); err != nil {
 			ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
 			return
 		}
-		if err = prs[i].LoadBaseRepoCtx(ctx); err != nil {
+		if err := prs[i].LoadBaseRepoCtx(ctx); err != nil {
 			ctx.Error(http.StatusInternalServerError, "LoadBaseRepo", err)
 			return
 		}
-		if err = prs[i].LoadHeadRepoCtx(ctx); err != nil {
+		if err := prs[i].LoadHeadRepoCtx(ctx); err != nil {
 			ctx.Error(http.StatusInternalServerError, "LoadHeadRepo", err)
 			return
 		}
 		apiPrs[i] = convert.ToAPIPullRequest(ctx, prs[i], ctx.Doer)
 	}
 
-	ctx.SetLinkHeader(int(maxResults), listOptions.PageSize)
+	ctx.SetLinkHeader(int(maxResults), pageSize)
 	ctx.SetTotalCountHeader(maxResults)
 	ctx.JSON(http.StatusOK, &apiPrs)
 }
@@ -779,6 +859,8 @@ func MergePullRequest(ctx *context.APIContext) {
 			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
 		} else if asymkey_service.IsErrWontSign(err) {
 			ctx.Error(http.StatusMethodNotAllowed, fmt.Sprintf("Protected branch %s requires signed commits but this merge would not be signed", pr.BaseBranch), err)
+		} else if errors.Is(err, pull_service.ErrChecklistIncomplete) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR merge checklist is incomplete", err)
 		} else {
 			ctx.InternalServerError(err)
 		}
@@ -1323,3 +1405,248 @@ func GetPullRequestCommits(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &apiCommits)
 }
+
+// GetPullRequestFiles gets the files changed by a given PR
+func GetPullRequestFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/files repository repoGetPullRequestFiles
+	// ---
+	// summary: Get the changed files of a pull request with their statistics, the hunks can be loaded per file
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace behavior
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol, show-all]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChangedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	gitRepo, diffOptions, closer, ok := preparePullDiff(ctx)
+	if !ok {
+		return
+	}
+	defer closer.Close()
+
+	stats, err := gitdiff.GetDiffFileStats(gitRepo, diffOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffFileStats", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	totalNumberOfFiles := len(stats)
+	totalNumberOfPages := int(math.Ceil(float64(totalNumberOfFiles) / float64(listOptions.PageSize)))
+
+	start, end := listOptions.GetStartEnd()
+	if start > totalNumberOfFiles {
+		start = totalNumberOfFiles
+	}
+	if end > totalNumberOfFiles {
+		end = totalNumberOfFiles
+	}
+
+	apiFiles := make([]*api.ChangedFile, 0, end-start)
+	for _, stat := range stats[start:end] {
+		apiFiles = append(apiFiles, convert.ToChangedFile(stat))
+	}
+
+	ctx.SetLinkHeader(totalNumberOfFiles, listOptions.PageSize)
+	ctx.SetTotalCountHeader(int64(totalNumberOfFiles))
+
+	ctx.RespHeader().Set("X-Page", strconv.Itoa(listOptions.Page))
+	ctx.RespHeader().Set("X-PerPage", strconv.Itoa(listOptions.PageSize))
+	ctx.RespHeader().Set("X-PageCount", strconv.Itoa(totalNumberOfPages))
+	ctx.RespHeader().Set("X-HasMore", strconv.FormatBool(listOptions.Page < totalNumberOfPages))
+	ctx.AppendAccessControlExposeHeaders("X-Page", "X-PerPage", "X-PageCount", "X-HasMore")
+
+	ctx.JSON(http.StatusOK, &apiFiles)
+}
+
+// GetPullRequestFileHunks gets the hunks of a single file changed by a given PR
+func GetPullRequestFileHunks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/files/hunks repository repoGetPullRequestFileHunks
+	// ---
+	// summary: Get the hunks of a file changed by a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: path
+	//   in: query
+	//   description: path of the file
+	//   type: string
+	//   required: true
+	// - name: previous_path
+	//   in: query
+	//   description: previous path of the file if it was renamed
+	//   type: string
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace behavior
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol, show-all]
+	// - name: binary
+	//   in: query
+	//   description: include the size, type and image dimensions of both versions of a binary file
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChangedFileHunks"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	path := ctx.FormString("path")
+	if path == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "path is required")
+		return
+	}
+	files := []string{path}
+	if previousPath := ctx.FormString("previous_path"); previousPath != "" && previousPath != path {
+		// both paths are needed to detect the rename
+		files = append(files, previousPath)
+	}
+
+	gitRepo, diffOptions, closer, ok := preparePullDiff(ctx)
+	if !ok {
+		return
+	}
+	defer closer.Close()
+
+	diffOptions.MaxFiles = len(files)
+	diff, err := gitdiff.GetDiff(gitRepo, diffOptions, files...)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiff", err)
+		return
+	}
+
+	for _, file := range diff.Files {
+		if file.Name != path && (file.Name != "" || file.OldName != path) {
+			continue
+		}
+		hunks := convert.ToChangedFileHunks(file)
+		if file.IsBin && ctx.FormBool("binary") {
+			binaryDiff, err := getPullBinaryDiff(gitRepo, diffOptions, file)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetBinaryDiff", err)
+				return
+			}
+			if binaryDiff != nil {
+				hunks.Binary = convert.ToBinaryDiff(binaryDiff)
+			}
+		}
+		ctx.JSON(http.StatusOK, hunks)
+		return
+	}
+	ctx.NotFound()
+}
+
+func getPullBinaryDiff(gitRepo *git.Repository, diffOptions *gitdiff.DiffOptions, file *gitdiff.DiffFile) (*gitdiff.BinaryDiff, error) {
+	baseCommit, err := gitRepo.GetCommit(diffOptions.BeforeCommitID)
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := gitRepo.GetCommit(diffOptions.AfterCommitID)
+	if err != nil {
+		return nil, err
+	}
+	return gitdiff.GetBinaryDiff(baseCommit, headCommit, file)
+}
+
+// preparePullDiff returns the repository and the options for the diff of the pull request of the
+// current request. If there is an error, write to `ctx` accordingly
+func preparePullDiff(ctx *context.APIContext) (*git.Repository, *gitdiff.DiffOptions, io.Closer, bool) {
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return nil, nil, nil, false
+	}
+
+	if err := pr.LoadBaseRepoCtx(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil, nil, nil, false
+	}
+
+	baseGitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, pr.BaseRepo.RepoPath())
+	if err != nil {
+		ctx.ServerError("OpenRepository", err)
+		return nil, nil, nil, false
+	}
+
+	headCommitID, err := baseGitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		_ = closer.Close()
+		ctx.ServerError("GetRefCommitID", err)
+		return nil, nil, nil, false
+	}
+
+	mergeBase := pr.MergeBase
+	if mergeBase == "" {
+		if mergeBase, _, err = baseGitRepo.GetMergeBase("", pr.BaseBranch, pr.GetGitRefName()); err != nil {
+			_ = closer.Close()
+			ctx.ServerError("GetMergeBase", err)
+			return nil, nil, nil, false
+		}
+	}
+
+	diffOptions := &gitdiff.DiffOptions{
+		BeforeCommitID:    mergeBase,
+		AfterCommitID:     headCommitID,
+		MaxLines:          setting.Git.MaxGitDiffLines,
+		MaxLineCharacters: setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:          setting.Git.MaxGitDiffFiles,
+	}
+	if whitespace := ctx.FormString("whitespace"); whitespace != "" {
+		diffOptions.WhitespaceBehavior = gitdiff.GetWhitespaceFlag(whitespace)
+	}
+	return baseGitRepo, diffOptions, closer, true
+}
@@ -0,0 +1,325 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ListMergeChecklistItems list the merge checklist of a repository
+func ListMergeChecklistItems(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/merge_checklist repository repoListMergeChecklistItems
+	// ---
+	// summary: List the merge checklist of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeChecklistItemList"
+
+	items, err := issues_model.GetMergeChecklistItems(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeChecklistItems", err)
+		return
+	}
+
+	apiItems := make([]*api.MergeChecklistItem, 0, len(items))
+	for _, item := range items {
+		apiItems = append(apiItems, convert.ToMergeChecklistItem(item))
+	}
+	ctx.JSON(http.StatusOK, apiItems)
+}
+
+// CreateMergeChecklistItem add an item to the merge checklist of a repository
+func CreateMergeChecklistItem(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/merge_checklist repository repoCreateMergeChecklistItem
+	// ---
+	// summary: Add an item to the merge checklist of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateMergeChecklistItemOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/MergeChecklistItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateMergeChecklistItemOption)
+
+	item := &issues_model.MergeChecklistItem{
+		RepoID: ctx.Repo.Repository.ID,
+		Name:   form.Name,
+	}
+	if err := issues_model.CreateMergeChecklistItem(ctx, item); err != nil {
+		if issues_model.IsErrMergeChecklistItemAlreadyExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateMergeChecklistItem", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToMergeChecklistItem(item))
+}
+
+// DeleteMergeChecklistItem remove an item from the merge checklist of a repository
+func DeleteMergeChecklistItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/merge_checklist/{id} repository repoDeleteMergeChecklistItem
+	// ---
+	// summary: Remove an item from the merge checklist of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the item
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := issues_model.DeleteMergeChecklistItem(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if issues_model.IsErrMergeChecklistItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteMergeChecklistItem", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetPullMergeChecklist get the merge checklist of a pull request
+func GetPullMergeChecklist(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/checklist repository repoGetPullMergeChecklist
+	// ---
+	// summary: Get the state of the merge checklist of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullMergeChecklist"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestForMergeChecklist(ctx)
+	if ctx.Written() {
+		return
+	}
+	respondPullMergeChecklist(ctx, pr)
+}
+
+// CheckPullMergeChecklistItem check an item of the merge checklist on a pull request
+func CheckPullMergeChecklistItem(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/pulls/{index}/checklist/{id} repository repoCheckPullMergeChecklistItem
+	// ---
+	// summary: Check an item of the merge checklist on a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the item
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullMergeChecklist"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestForMergeChecklist(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := issues_model.CheckMergeChecklistItem(ctx, pr, ctx.ParamsInt64(":id"), ctx.Doer.ID); err != nil {
+		if issues_model.IsErrMergeChecklistItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CheckMergeChecklistItem", err)
+		}
+		return
+	}
+	respondPullMergeChecklist(ctx, pr)
+}
+
+// UncheckPullMergeChecklistItem uncheck an item of the merge checklist on a pull request
+func UncheckPullMergeChecklistItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/checklist/{id} repository repoUncheckPullMergeChecklistItem
+	// ---
+	// summary: Uncheck an item of the merge checklist on a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the item
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullMergeChecklist"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestForMergeChecklist(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := issues_model.UncheckMergeChecklistItem(ctx, pr, ctx.ParamsInt64(":id")); err != nil {
+		if issues_model.IsErrMergeChecklistItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UncheckMergeChecklistItem", err)
+		}
+		return
+	}
+	respondPullMergeChecklist(ctx, pr)
+}
+
+func getPullRequestForMergeChecklist(ctx *context.APIContext) *issues_model.PullRequest {
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	return pr
+}
+
+func respondPullMergeChecklist(ctx *context.APIContext, pr *issues_model.PullRequest) {
+	states, err := issues_model.GetMergeChecklistState(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergeChecklistState", err)
+		return
+	}
+
+	userIDs := make([]int64, 0, len(states))
+	for _, s := range states {
+		if s.IsChecked() {
+			userIDs = append(userIDs, s.Check.DoerID)
+		}
+	}
+	users, err := user_model.GetUsersByIDs(userIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+	userMap := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		userMap[u.ID] = u
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPullMergeChecklist(states, userMap, ctx.Doer))
+}
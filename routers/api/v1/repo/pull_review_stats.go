@@ -0,0 +1,51 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// GetPullReviewStats gets the review statistics of the pull requests of a repository
+func GetPullReviewStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/stats repository repoGetPullReviewStats
+	// ---
+	// summary: Get the review statistics of the pull requests of a repository
+	// description: The statistics are computed periodically, pull requests changed since the last computation are not included yet.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: first day of the statistics (date or RFC3339 timestamp, defaults to 29 days before until)
+	//   type: string
+	// - name: until
+	//   in: query
+	//   description: last day of the statistics (date or RFC3339 timestamp, defaults to today)
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.ServeReviewStats(ctx, issues_model.ReviewStatsOptions{
+		RepoID: ctx.Repo.Repository.ID,
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// CompareReleases returns the changes between two releases or tags
+func CompareReleases(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/compare/{from}/{to} repository repoCompareReleases
+	// ---
+	// summary: Get the commits, merged pull requests and contributors between two releases or tags
+	// description: The commits are paginated, the pull requests and contributors always cover the whole range.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: from
+	//   in: path
+	//   description: tag name of the older release
+	//   type: string
+	//   required: true
+	// - name: to
+	//   in: path
+	//   description: tag name of the newer release
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of the commits to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the commits
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReleaseComparison"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	from, to := ctx.Params(":from"), ctx.Params(":to")
+	fromCommit := getTagCommit(ctx, from)
+	if ctx.Written() {
+		return
+	}
+	toCommit := getTagCommit(ctx, to)
+	if ctx.Written() {
+		return
+	}
+
+	commits, err := ctx.Repo.GitRepo.CommitsBetween(toCommit, fromCommit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CommitsBetween", err)
+		return
+	}
+
+	commitIDs := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		commitIDs = append(commitIDs, commit.ID.String())
+	}
+	prs, err := issues_model.GetMergedPullRequestsByMergedCommitIDs(ctx, ctx.Repo.Repository.ID, commitIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMergedPullRequestsByMergedCommitIDs", err)
+		return
+	}
+	apiPRs := make([]*api.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if apiPR := convert.ToAPIPullRequest(ctx, pr, ctx.Doer); apiPR != nil {
+			apiPRs = append(apiPRs, apiPR)
+		}
+	}
+
+	userCache := make(map[string]*user_model.User)
+	contributors, err := releaseContributors(ctx, commits, userCache)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "releaseContributors", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.Page <= 0 {
+		listOptions.Page = 1
+	}
+	if listOptions.PageSize > setting.Git.CommitsRangeSize {
+		listOptions.PageSize = setting.Git.CommitsRangeSize
+	}
+	start, end := listOptions.GetStartEnd()
+	if start > len(commits) {
+		start = len(commits)
+	}
+	if end > len(commits) {
+		end = len(commits)
+	}
+
+	apiCommits := make([]*api.Commit, 0, end-start)
+	for _, commit := range commits[start:end] {
+		apiCommit, err := convert.ToCommit(ctx.Repo.Repository, ctx.Repo.GitRepo, commit, userCache)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+			return
+		}
+		apiCommits = append(apiCommits, apiCommit)
+	}
+
+	ctx.SetLinkHeader(len(commits), listOptions.PageSize)
+	ctx.SetTotalCountHeader(int64(len(commits)))
+	ctx.JSON(http.StatusOK, &api.ReleaseComparison{
+		From:         from,
+		To:           to,
+		FromCommit:   fromCommit.ID.String(),
+		ToCommit:     toCommit.ID.String(),
+		TotalCommits: len(commits),
+		Commits:      apiCommits,
+		PullRequests: apiPRs,
+		Contributors: contributors,
+	})
+}
+
+// getTagCommit returns the commit of a tag.
+// If the tag does not exist, write to `ctx` accordingly
+func getTagCommit(ctx *context.APIContext, name string) *git.Commit {
+	commit, err := ctx.Repo.GitRepo.GetTagCommit(name)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetTagCommit", err)
+		}
+		return nil
+	}
+	return commit
+}
+
+// releaseContributors summarizes the authors of the commits by email, most commits first
+func releaseContributors(ctx *context.APIContext, commits []*git.Commit, userCache map[string]*user_model.User) ([]*api.ReleaseContributor, error) {
+	contributors := make([]*api.ReleaseContributor, 0, 10)
+	byEmail := make(map[string]*api.ReleaseContributor)
+	for _, commit := range commits {
+		email := strings.ToLower(commit.Author.Email)
+		if contributor, ok := byEmail[email]; ok {
+			contributor.Commits++
+			continue
+		}
+
+		contributor := &api.ReleaseContributor{
+			Name:    commit.Author.Name,
+			Email:   commit.Author.Email,
+			Commits: 1,
+		}
+		u, ok := userCache[commit.Author.Email]
+		if !ok {
+			var err error
+			u, err = user_model.GetUserByEmailContext(ctx, commit.Author.Email)
+			if err != nil && !user_model.IsErrUserNotExist(err) {
+				return nil, err
+			}
+			if u != nil {
+				userCache[commit.Author.Email] = u
+			}
+		}
+		if u != nil {
+			contributor.User = convert.ToUser(u, ctx.Doer)
+		}
+		byEmail[email] = contributor
+		contributors = append(contributors, contributor)
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Commits > contributors[j].Commits
+	})
+	return contributors, nil
+}
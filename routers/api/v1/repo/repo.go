@@ -6,6 +6,7 @@
 package repo
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -30,6 +32,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
 )
 
 // Search repositories via options
@@ -97,6 +100,13 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: if `uid` is given, search only for repos that the user owns
 	//   type: boolean
+	// - name: property
+	//   in: query
+	//   description: only repos with the custom property value, given as `name:value`
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
 	// - name: sort
 	//   in: query
 	//   description: sort repos by attribute. Supported values are
@@ -167,6 +177,18 @@ func Search(ctx *context.APIContext) {
 		opts.Archived = util.OptionalBoolOf(ctx.FormBool("archived"))
 	}
 
+	if properties := ctx.FormStrings("property"); len(properties) > 0 {
+		opts.Properties = make(map[string]string, len(properties))
+		for _, property := range properties {
+			name, value, ok := strings.Cut(property, ":")
+			if !ok || name == "" {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid property filter: \"%s\"", property))
+				return
+			}
+			opts.Properties[name] = value
+		}
+	}
+
 	if ctx.FormString("is_private") != "" {
 		opts.IsPrivate = util.OptionalBoolOf(ctx.FormBool("is_private"))
 	}
@@ -228,28 +250,65 @@ func Search(ctx *context.APIContext) {
 
 // CreateUserRepo create a repository for a user
 func CreateUserRepo(ctx *context.APIContext, owner *user_model.User, opt api.CreateRepoOption) {
+	initialFiles, ok := prepareInitialFiles(ctx, opt.Files)
+	if !ok {
+		return
+	}
+	if len(initialFiles) > 0 || opt.ReadmeContent != "" || opt.GitignoreContent != "" || opt.LicenseContent != "" {
+		opt.AutoInit = true
+	}
 	if opt.AutoInit && opt.Readme == "" {
 		opt.Readme = "Default"
 	}
+
+	// validate everything applied after the creation up front to avoid needless rollbacks
+	for _, label := range opt.Labels {
+		if !checkCreateLabelOption(ctx, label) {
+			return
+		}
+	}
+	for _, hook := range opt.Webhooks {
+		if !utils.CheckCreateHookOption(ctx, hook) {
+			return
+		}
+	}
+	if len(opt.BranchProtections) > 0 && !opt.AutoInit {
+		ctx.Error(http.StatusUnprocessableEntity, "", "branch protections require an initialized repository")
+		return
+	}
+	topics, ok := prepareTopics(ctx, opt.Topics)
+	if !ok {
+		return
+	}
+
 	repo, err := repo_service.CreateRepository(ctx.Doer, owner, repo_module.CreateRepoOptions{
-		Name:          opt.Name,
-		Description:   opt.Description,
-		IssueLabels:   opt.IssueLabels,
-		Gitignores:    opt.Gitignores,
-		License:       opt.License,
-		Readme:        opt.Readme,
-		IsPrivate:     opt.Private,
-		AutoInit:      opt.AutoInit,
-		DefaultBranch: opt.DefaultBranch,
-		TrustModel:    repo_model.ToTrustModel(opt.TrustModel),
-		IsTemplate:    opt.Template,
+		Name:             opt.Name,
+		Description:      opt.Description,
+		IssueLabels:      opt.IssueLabels,
+		Gitignores:       opt.Gitignores,
+		License:          opt.License,
+		Readme:           opt.Readme,
+		IsPrivate:        opt.Private,
+		AutoInit:         opt.AutoInit,
+		DefaultBranch:    opt.DefaultBranch,
+		TrustModel:       repo_model.ToTrustModel(opt.TrustModel),
+		IsTemplate:       opt.Template,
+		ReadmeContent:    opt.ReadmeContent,
+		GitignoreContent: opt.GitignoreContent,
+		LicenseContent:   opt.LicenseContent,
+		InitialFiles:     initialFiles,
+		Topics:           topics,
+		Properties:       opt.Properties,
 	})
 	if err != nil {
 		if repo_model.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if db.IsErrNameReserved(err) ||
 			db.IsErrNamePatternNotAllowed(err) ||
-			repo_module.IsErrIssueLabelTemplateLoad(err) {
+			repo_module.IsErrIssueLabelTemplateLoad(err) ||
+			organization.IsErrRepoPolicyViolation(err) ||
+			repo_model.IsErrPropertyDefinitionNotExist(err) ||
+			repo_model.IsErrInvalidPropertyValue(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -261,11 +320,81 @@ func CreateUserRepo(ctx *context.APIContext, owner *user_model.User, opt api.Cre
 	repo, err = repo_model.GetRepositoryByID(repo.ID)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		return
+	}
+	repo.Owner = owner
+
+	if !provisionRepo(ctx, repo, &opt) {
+		// the response has been written already, remove the partially provisioned repository
+		if err := repo_service.DeleteRepository(ctx, ctx.Doer, repo, false); err != nil {
+			log.Error("Unable to delete partially provisioned repository %s: %v", repo.FullName(), err)
+		}
+		return
 	}
 
 	ctx.JSON(http.StatusCreated, convert.ToRepo(repo, perm.AccessModeOwner))
 }
 
+// prepareInitialFiles decodes the additional files of the initial commit. If
+// a file is invalid, write to `ctx` accordingly
+func prepareInitialFiles(ctx *context.APIContext, files []*api.CreateRepoFileOption) (map[string][]byte, bool) {
+	initialFiles := make(map[string][]byte, len(files))
+	for _, file := range files {
+		treePath := files_service.CleanUploadFileName(file.Path)
+		if treePath == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid file path: %s", file.Path))
+			return nil, false
+		}
+		if _, has := initialFiles[treePath]; has {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("duplicate file path: %s", treePath))
+			return nil, false
+		}
+		content, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid content of file %s: %v", treePath, err))
+			return nil, false
+		}
+		initialFiles[treePath] = content
+	}
+	return initialFiles, true
+}
+
+// prepareTopics sanitizes the topics of a new repository. If a topic is
+// invalid, write to `ctx` accordingly
+func prepareTopics(ctx *context.APIContext, topics []string) ([]string, bool) {
+	validTopics, invalidTopics := repo_model.SanitizeAndValidateTopics(topics)
+	if len(validTopics) > 25 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Exceeding maximum number of topics per repo")
+		return nil, false
+	}
+	if len(invalidTopics) > 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("Topic names are invalid: %s", strings.Join(invalidTopics, ", ")))
+		return nil, false
+	}
+	return validTopics, true
+}
+
+// provisionRepo creates the labels, branch protections and webhooks of a newly
+// created repository. If there is an error, write to `ctx` accordingly
+func provisionRepo(ctx *context.APIContext, repo *repo_model.Repository, opt *api.CreateRepoOption) bool {
+	for _, label := range opt.Labels {
+		if _, ok := createLabel(ctx, repo, label); !ok {
+			return false
+		}
+	}
+	for _, protection := range opt.BranchProtections {
+		if _, ok := createBranchProtection(ctx, repo, protection); !ok {
+			return false
+		}
+	}
+	for _, hook := range opt.Webhooks {
+		if _, ok := utils.CreateRepoHook(ctx, hook, repo.ID); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Create one repository of mine
 func Create(ctx *context.APIContext) {
 	// swagger:operation POST /user/repos repository user createCurrentUserRepo
@@ -761,10 +890,21 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			var config *repo_model.IssuesConfig
 
 			if opts.InternalTracker != nil {
+				for _, keywords := range [][]string{opts.InternalTracker.CloseKeywords, opts.InternalTracker.ReopenKeywords} {
+					for _, word := range keywords {
+						if !references.IsValidKeyword(word) {
+							err := fmt.Errorf("Keyword %q not valid", word)
+							ctx.Error(http.StatusUnprocessableEntity, "Invalid issue keyword", err)
+							return err
+						}
+					}
+				}
 				config = &repo_model.IssuesConfig{
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					CloseKeywords:                    opts.InternalTracker.CloseKeywords,
+					ReopenKeywords:                   opts.InternalTracker.ReopenKeywords,
 				}
 			} else if unit, err := repo.GetUnit(unit_model.TypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -1080,3 +1220,28 @@ func GetIssueTemplates(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, ctx.IssueTemplatesFromDefaultBranch())
 }
+
+// GetIssueConfig returns the issue template chooser configuration of a repository
+func GetIssueConfig(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_config repository repoGetIssueConfig
+	// ---
+	// summary: Get the issue template chooser configuration of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueConfig"
+
+	ctx.JSON(http.StatusOK, ctx.IssueConfigFromDefaultBranch())
+}
@@ -7,16 +7,19 @@ package repo
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
@@ -99,6 +102,15 @@ func Transfer(ctx *context.APIContext) {
 		}
 	}
 
+	var scheduled timeutil.TimeStamp
+	if opts.ScheduledAt != nil {
+		if !opts.ScheduledAt.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "ScheduledAt", "the scheduled time must be in the future")
+			return
+		}
+		scheduled = timeutil.TimeStamp(opts.ScheduledAt.Unix())
+	}
+
 	if ctx.Repo.GitRepo != nil {
 		ctx.Repo.GitRepo.Close()
 		ctx.Repo.GitRepo = nil
@@ -106,7 +118,7 @@ func Transfer(ctx *context.APIContext) {
 
 	oldFullname := ctx.Repo.Repository.FullName()
 
-	if err := repo_service.StartRepositoryTransfer(ctx.Doer, newOwner, ctx.Repo.Repository, teams); err != nil {
+	if err := repo_service.StartScheduledRepositoryTransfer(ctx.Doer, newOwner, ctx.Repo.Repository, teams, scheduled); err != nil {
 		if models.IsErrRepoTransferInProgress(err) {
 			ctx.Error(http.StatusConflict, "StartRepositoryTransfer", err)
 			return
@@ -227,8 +239,224 @@ func acceptOrRejectRepoTransfer(ctx *context.APIContext, accept bool) error {
 	}
 
 	if accept {
-		return repo_service.TransferOwnership(repoTransfer.Doer, repoTransfer.Recipient, ctx.Repo.Repository, repoTransfer.Teams)
+		return repo_service.AcceptTransferOwnership(ctx.Doer, ctx.Repo.Repository, repoTransfer)
+	}
+
+	return repo_service.RejectTransferOwnership(ctx.Doer, ctx.Repo.Repository)
+}
+
+// GetTransfer returns the pending transfer of a repo
+func GetTransfer(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/transfer repository repoGetTransfer
+	// ---
+	// summary: Get the pending transfer of a repo
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoTransfer"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repoTransfer := getPendingTransferForDoer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToRepoTransfer(repoTransfer))
+}
+
+// EditTransfer changes the schedule of the pending transfer of a repo
+func EditTransfer(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/transfer repository repoEditTransfer
+	// ---
+	// summary: Change the schedule of the pending transfer of a repo
+	// description: If an accepted transfer is not scheduled anymore or the scheduled time has already been reached, the transfer is executed directly.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoTransferOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoTransfer"
+	//   "202":
+	//     "$ref": "#/responses/Repository"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.EditRepoTransferOption)
+
+	repoTransfer := getPendingTransferForDoer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var scheduled timeutil.TimeStamp
+	if opts.ScheduledAt != nil {
+		if !opts.ScheduledAt.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "ScheduledAt", "the scheduled time must be in the future")
+			return
+		}
+		scheduled = timeutil.TimeStamp(opts.ScheduledAt.Unix())
+	}
+
+	if ctx.Repo.GitRepo != nil {
+		ctx.Repo.GitRepo.Close()
+		ctx.Repo.GitRepo = nil
+	}
+
+	if err := repo_service.ScheduleTransferOwnership(ctx.Doer, ctx.Repo.Repository, repoTransfer, scheduled); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	if ctx.Repo.Repository.Status != repo_model.RepositoryPendingTransfer {
+		ctx.JSON(http.StatusAccepted, convert.ToRepo(ctx.Repo.Repository, ctx.Repo.AccessMode))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToRepoTransfer(repoTransfer))
+}
+
+// CancelTransfer cancels the pending transfer of a repo
+func CancelTransfer(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/transfer repository repoCancelTransfer
+	// ---
+	// summary: Cancel the pending transfer of a repo
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if _, err := models.GetPendingRepositoryTransfer(ctx.Repo.Repository); err != nil {
+		if models.IsErrNoPendingTransfer(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.InternalServerError(err)
+		return
+	}
+
+	if err := repo_service.CancelTransferOwnership(ctx.Doer, ctx.Repo.Repository); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// getPendingTransferForDoer returns the pending transfer of the repo if the doer is the owner of the repo or can accept it
+func getPendingTransferForDoer(ctx *context.APIContext) *models.RepoTransfer {
+	repoTransfer, err := models.GetPendingRepositoryTransfer(ctx.Repo.Repository)
+	if err != nil {
+		if models.IsErrNoPendingTransfer(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+
+	if err := repoTransfer.LoadAttributes(); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+
+	if !ctx.Repo.IsOwner() && !repoTransfer.CanUserAcceptTransfer(ctx.Doer) {
+		ctx.NotFound()
+		return nil
+	}
+
+	return repoTransfer
+}
+
+// ListMyTransfers lists the pending repository transfers the authenticated user can accept or reject
+func ListMyTransfers(ctx *context.APIContext) {
+	// swagger:operation GET /user/transfers user userListRepoTransfers
+	// ---
+	// summary: List the pending repository transfers the authenticated user can accept or reject
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoTransferList"
+
+	transfers, err := models.GetPendingRepositoryTransfersForUser(ctx, ctx.Doer)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiTransfers := make([]*api.RepoTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		if err := transfer.LoadAttributes(); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+
+		repo, err := repo_model.GetRepositoryByID(transfer.RepoID)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		if err := repo.GetOwner(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		accessMode, err := access_model.AccessLevel(ctx.Doer, repo)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+
+		apiTransfer := convert.ToRepoTransfer(transfer)
+		apiTransfer.Repository = convert.ToRepo(repo, accessMode)
+		apiTransfers = append(apiTransfers, apiTransfer)
 	}
 
-	return models.CancelRepositoryTransfer(ctx.Repo.Repository)
+	ctx.JSON(http.StatusOK, apiTransfers)
 }
@@ -14,3 +14,17 @@ type swaggerResponseActivityPub struct {
 	// in:body
 	Body api.ActivityPub `json:"body"`
 }
+
+// FederatedInstance
+// swagger:response FederatedInstance
+type swaggerResponseFederatedInstance struct {
+	// in:body
+	Body api.FederatedInstance `json:"body"`
+}
+
+// FederatedInstanceList
+// swagger:response FederatedInstanceList
+type swaggerResponseFederatedInstanceList struct {
+	// in:body
+	Body []api.FederatedInstance `json:"body"`
+}
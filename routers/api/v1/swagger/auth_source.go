@@ -0,0 +1,23 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// AuthSource
+// swagger:response AuthSource
+type swaggerResponseAuthSource struct {
+	// in:body
+	Body api.AuthSource `json:"body"`
+}
+
+// AuthSourceList
+// swagger:response AuthSourceList
+type swaggerResponseAuthSourceList struct {
+	// in:body
+	Body []api.AuthSource `json:"body"`
+}
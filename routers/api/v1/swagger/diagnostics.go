@@ -0,0 +1,23 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// DiagnosticRequestList
+// swagger:response DiagnosticRequestList
+type swaggerResponseDiagnosticRequestList struct {
+	// in:body
+	Body []api.DiagnosticRequest `json:"body"`
+}
+
+// DiagnosticSlowQueryList
+// swagger:response DiagnosticSlowQueryList
+type swaggerResponseDiagnosticSlowQueryList struct {
+	// in:body
+	Body []api.DiagnosticSlowQuery `json:"body"`
+}
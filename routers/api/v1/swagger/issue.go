@@ -92,6 +92,34 @@ type swaggerIssueDeadline struct {
 	Body api.IssueDeadline `json:"body"`
 }
 
+// IssueTask
+// swagger:response IssueTask
+type swaggerIssueTask struct {
+	// in:body
+	Body api.IssueTask `json:"body"`
+}
+
+// IssueTaskList
+// swagger:response IssueTaskList
+type swaggerIssueTaskList struct {
+	// in:body
+	Body []api.IssueTask `json:"body"`
+}
+
+// CommentRevisionList
+// swagger:response CommentRevisionList
+type swaggerCommentRevisionList struct {
+	// in:body
+	Body []api.CommentRevision `json:"body"`
+}
+
+// ModerationLogList
+// swagger:response ModerationLogList
+type swaggerModerationLogList struct {
+	// in:body
+	Body []api.ModerationLog `json:"body"`
+}
+
 // IssueTemplates
 // swagger:response IssueTemplates
 type swaggerIssueTemplates struct {
@@ -99,6 +127,13 @@ type swaggerIssueTemplates struct {
 	Body []api.IssueTemplate `json:"body"`
 }
 
+// IssueConfig
+// swagger:response IssueConfig
+type swaggerIssueConfig struct {
+	// in:body
+	Body api.IssueConfig `json:"body"`
+}
+
 // StopWatch
 // swagger:response StopWatch
 type swaggerResponseStopWatch struct {
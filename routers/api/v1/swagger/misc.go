@@ -21,3 +21,24 @@ type swaggerResponseStringSlice struct {
 	// in:body
 	Body []string `json:"body"`
 }
+
+// GlobalSearchResults
+// swagger:response GlobalSearchResults
+type swaggerResponseGlobalSearchResults struct {
+	// in:body
+	Body api.GlobalSearchResults `json:"body"`
+}
+
+// UsageReport
+// swagger:response UsageReport
+type swaggerResponseUsageReport struct {
+	// in:body
+	Body api.UsageReport `json:"body"`
+}
+
+// RepoStatsRecalculation
+// swagger:response RepoStatsRecalculation
+type swaggerResponseRepoStatsRecalculation struct {
+	// in:body
+	Body api.RepoStatsRecalculation `json:"body"`
+}
@@ -42,6 +42,12 @@ type swaggerParameterBodies struct {
 	CreateIssueCommentOption api.CreateIssueCommentOption
 	// in:body
 	EditIssueCommentOption api.EditIssueCommentOption
+	// in:body
+	EditIssueTaskOption api.EditIssueTaskOption
+	// in:body
+	HideIssueCommentOption api.HideIssueCommentOption
+	// in:body
+	LockIssueOption api.LockIssueOption
 
 	// in:body
 	IssueLabelsOption api.IssueLabelsOption
@@ -86,6 +92,8 @@ type swaggerParameterBodies struct {
 	// in:body
 	TransferRepoOption api.TransferRepoOption
 	// in:body
+	EditRepoTransferOption api.EditRepoTransferOption
+	// in:body
 	CreateForkOption api.CreateForkOption
 	// in:body
 	GenerateRepoOption api.GenerateRepoOption
@@ -98,6 +106,11 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditTeamOption api.EditTeamOption
 
+	// in:body
+	CreateOrgJoinRequestOption api.CreateOrgJoinRequestOption
+	// in:body
+	ApproveOrgJoinRequestOption api.ApproveOrgJoinRequestOption
+
 	// in:body
 	AddTimeOption api.AddTimeOption
 
@@ -107,9 +120,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditUserOption api.EditUserOption
 
+	// in:body
+	AdminBulkUsersOption api.AdminBulkUsersOption
+
 	// in:body
 	EditAttachmentOptions api.EditAttachmentOptions
 
+	// in:body
+	CreateAttachmentFromURLOptions api.CreateAttachmentFromURLOptions
+
 	// in:body
 	CreateFileOptions api.CreateFileOptions
 
@@ -172,4 +191,61 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	CreatePushMirrorOption api.CreatePushMirrorOption
+
+	// in:body
+	EditPackageOption api.EditPackageOption
+
+	// in:body
+	EditPackageAccessOption api.EditPackageAccessOption
+
+	// in:body
+	PackageTeamAccessOption api.PackageTeamAccessOption
+
+	// in:body
+	SetFederatedInstanceOption api.SetFederatedInstanceOption
+
+	// in:body
+	CreatePackageMirrorOption api.CreatePackageMirrorOption
+
+	// in:body
+	EditPackageMirrorOption api.EditPackageMirrorOption
+
+	// in:body
+	ApplyDefaultHookOption api.ApplyDefaultHookOption
+
+	// in:body
+	RecalculateRepoStatsOption api.RecalculateRepoStatsOption
+
+	// in:body
+	RepoConfig api.RepoConfig
+
+	// in:body
+	CreatePropertyDefinitionOption api.CreatePropertyDefinitionOption
+
+	// in:body
+	EditPropertyDefinitionOption api.EditPropertyDefinitionOption
+
+	// in:body
+	EditRepoPropertiesOption api.EditRepoPropertiesOption
+
+	// in:body
+	SetRepoPolicyOption api.SetRepoPolicyOption
+
+	// in:body
+	CreateNamePolicyOption api.CreateNamePolicyOption
+
+	// in:body
+	CreateNameClaimOption api.CreateNameClaimOption
+
+	// in:body
+	CreateServiceAccountOption api.CreateServiceAccountOption
+
+	// in:body
+	EditServiceAccountOption api.EditServiceAccountOption
+
+	// in:body
+	CreateMergeChecklistItemOption api.CreateMergeChecklistItemOption
+
+	// in:body
+	SetPackageContainerDomainOption api.SetPackageContainerDomainOption
 }
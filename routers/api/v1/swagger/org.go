@@ -42,3 +42,52 @@ type swaggerResponseOrganizationPermissions struct {
 	// in:body
 	Body api.OrganizationPermissions `json:"body"`
 }
+
+// OrgJoinRequest
+// swagger:response OrgJoinRequest
+type swaggerResponseOrgJoinRequest struct {
+	// in:body
+	Body api.OrgJoinRequest `json:"body"`
+}
+
+// OrgJoinRequestList
+// swagger:response OrgJoinRequestList
+type swaggerResponseOrgJoinRequestList struct {
+	// in:body
+	Body []api.OrgJoinRequest `json:"body"`
+}
+
+// PropertyDefinition
+// swagger:response PropertyDefinition
+type swaggerResponsePropertyDefinition struct {
+	// in:body
+	Body api.PropertyDefinition `json:"body"`
+}
+
+// PropertyDefinitionList
+// swagger:response PropertyDefinitionList
+type swaggerResponsePropertyDefinitionList struct {
+	// in:body
+	Body []api.PropertyDefinition `json:"body"`
+}
+
+// RepoPolicy
+// swagger:response RepoPolicy
+type swaggerResponseRepoPolicy struct {
+	// in:body
+	Body api.RepoPolicy `json:"body"`
+}
+
+// ServiceAccount
+// swagger:response ServiceAccount
+type swaggerResponseServiceAccount struct {
+	// in:body
+	Body api.ServiceAccount `json:"body"`
+}
+
+// ServiceAccountList
+// swagger:response ServiceAccountList
+type swaggerResponseServiceAccountList struct {
+	// in:body
+	Body []api.ServiceAccount `json:"body"`
+}
@@ -28,3 +28,87 @@ type swaggerResponsePackageFileList struct {
 	// in:body
 	Body []api.PackageFile `json:"body"`
 }
+
+// PackageVersionDiff
+// swagger:response PackageVersionDiff
+type swaggerResponsePackageVersionDiff struct {
+	// in:body
+	Body api.PackageVersionDiff `json:"body"`
+}
+
+// PackageDependencyList
+// swagger:response PackageDependencyList
+type swaggerResponsePackageDependencyList struct {
+	// in:body
+	Body []api.PackageDependency `json:"body"`
+}
+
+// PackageDependentList
+// swagger:response PackageDependentList
+type swaggerResponsePackageDependentList struct {
+	// in:body
+	Body []api.PackageDependent `json:"body"`
+}
+
+// PackageAccess
+// swagger:response PackageAccess
+type swaggerResponsePackageAccess struct {
+	// in:body
+	Body api.PackageAccess `json:"body"`
+}
+
+// PackageDownloadStats
+// swagger:response PackageDownloadStats
+type swaggerResponsePackageDownloadStats struct {
+	// in:body
+	Body api.PackageDownloadStats `json:"body"`
+}
+
+// PackageBlob
+// swagger:response PackageBlob
+type swaggerResponsePackageBlob struct {
+	// in:body
+	Body api.PackageBlob `json:"body"`
+}
+
+// PackageAuditLogList
+// swagger:response PackageAuditLogList
+type swaggerResponsePackageAuditLogList struct {
+	// in:body
+	Body []api.PackageAuditLog `json:"body"`
+}
+
+// PackageMirror
+// swagger:response PackageMirror
+type swaggerResponsePackageMirror struct {
+	// in:body
+	Body api.PackageMirror `json:"body"`
+}
+
+// PackageMirrorList
+// swagger:response PackageMirrorList
+type swaggerResponsePackageMirrorList struct {
+	// in:body
+	Body []api.PackageMirror `json:"body"`
+}
+
+// PackageMirrorSyncResult
+// swagger:response PackageMirrorSyncResult
+type swaggerResponsePackageMirrorSyncResult struct {
+	// in:body
+	Body api.PackageMirrorSyncResult `json:"body"`
+}
+
+// PackageVendorGrantList
+// swagger:response PackageVendorGrantList
+type swaggerResponsePackageVendorGrantList struct {
+	// in:body
+	Body []api.PackageVendorGrant `json:"body"`
+}
+
+// PackageContainerDomain
+// swagger:response PackageContainerDomain
+type swaggerResponsePackageContainerDomain struct {
+	// in:body
+	Body api.PackageContainerDomain `json:"body"`
+}
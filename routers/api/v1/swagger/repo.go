@@ -15,6 +15,20 @@ type swaggerResponseRepository struct {
 	Body api.Repository `json:"body"`
 }
 
+// RepoTransfer
+// swagger:response RepoTransfer
+type swaggerResponseRepoTransfer struct {
+	// in:body
+	Body api.RepoTransfer `json:"body"`
+}
+
+// RepoTransferList
+// swagger:response RepoTransferList
+type swaggerResponseRepoTransferList struct {
+	// in:body
+	Body []api.RepoTransfer `json:"body"`
+}
+
 // RepositoryList
 // swagger:response RepositoryList
 type swaggerResponseRepositoryList struct {
@@ -22,6 +36,20 @@ type swaggerResponseRepositoryList struct {
 	Body []api.Repository `json:"body"`
 }
 
+// ForkNetwork
+// swagger:response ForkNetwork
+type swaggerResponseForkNetwork struct {
+	// in:body
+	Body api.ForkNetwork `json:"body"`
+}
+
+// ForkComparison
+// swagger:response ForkComparison
+type swaggerResponseForkComparison struct {
+	// in:body
+	Body api.ForkComparison `json:"body"`
+}
+
 // Branch
 // swagger:response Branch
 type swaggerResponseBranch struct {
@@ -99,6 +127,13 @@ type swaggerResponseHookList struct {
 	Body []api.Hook `json:"body"`
 }
 
+// ApplyDefaultHookResult
+// swagger:response ApplyDefaultHookResult
+type swaggerResponseApplyDefaultHookResult struct {
+	// in:body
+	Body api.ApplyDefaultHookResult `json:"body"`
+}
+
 // GitHook
 // swagger:response GitHook
 type swaggerResponseGitHook struct {
@@ -127,6 +162,13 @@ type swaggerResponseReleaseList struct {
 	Body []api.Release `json:"body"`
 }
 
+// ReleaseComparison
+// swagger:response ReleaseComparison
+type swaggerResponseReleaseComparison struct {
+	// in:body
+	Body api.ReleaseComparison `json:"body"`
+}
+
 // PullRequest
 // swagger:response PullRequest
 type swaggerResponsePullRequest struct {
@@ -211,6 +253,13 @@ type swaggerResponseAttachment struct {
 	Body api.Attachment `json:"body"`
 }
 
+// PastedAttachment
+// swagger:response PastedAttachment
+type swaggerResponsePastedAttachment struct {
+	// in: body
+	Body api.PastedAttachment `json:"body"`
+}
+
 // GitTreeResponse
 // swagger:response GitTreeResponse
 type swaggerGitTreeResponse struct {
@@ -289,6 +338,13 @@ type swaggerContentsListResponse struct {
 	Body []api.ContentsResponse `json:"body"`
 }
 
+// FileOutline
+// swagger:response FileOutline
+type swaggerFileOutline struct {
+	// in: body
+	Body api.FileOutline `json:"body"`
+}
+
 // FileDeleteResponse
 // swagger:response FileDeleteResponse
 type swaggerFileDeleteResponse struct {
@@ -365,3 +421,94 @@ type swaggerRepoCollaboratorPermission struct {
 	// in:body
 	Body api.RepoCollaboratorPermission `json:"body"`
 }
+
+// CollaborationList
+// swagger:response CollaborationList
+type swaggerCollaborationList struct {
+	// in:body
+	Body []api.Collaboration `json:"body"`
+}
+
+// RepoArchiveStatus
+// swagger:response RepoArchiveStatus
+type swaggerResponseRepoArchiveStatus struct {
+	// in:body
+	Body api.RepoArchiveStatus `json:"body"`
+}
+
+// PullReviewStats
+// swagger:response PullReviewStats
+type swaggerResponsePullReviewStats struct {
+	// in:body
+	Body api.PullReviewStats `json:"body"`
+}
+
+// RepoConfig
+// swagger:response RepoConfig
+type swaggerResponseRepoConfig struct {
+	// in:body
+	Body api.RepoConfig `json:"body"`
+}
+
+// ChangedFileList
+// swagger:response ChangedFileList
+type swaggerChangedFileList struct {
+	// in:body
+	Body []api.ChangedFile `json:"body"`
+}
+
+// ChangedFileHunks
+// swagger:response ChangedFileHunks
+type swaggerResponseChangedFileHunks struct {
+	// in:body
+	Body api.ChangedFileHunks `json:"body"`
+}
+
+// BlobDiff
+// swagger:response BlobDiff
+type swaggerResponseBlobDiff struct {
+	// in:body
+	Body api.BlobDiff `json:"body"`
+}
+
+// RepoProperties
+// swagger:response RepoProperties
+type swaggerResponseRepoProperties struct {
+	// in:body
+	Body map[string]string `json:"body"`
+}
+
+// MergeChecklistItem
+// swagger:response MergeChecklistItem
+type swaggerResponseMergeChecklistItem struct {
+	// in:body
+	Body api.MergeChecklistItem `json:"body"`
+}
+
+// MergeChecklistItemList
+// swagger:response MergeChecklistItemList
+type swaggerResponseMergeChecklistItemList struct {
+	// in:body
+	Body []api.MergeChecklistItem `json:"body"`
+}
+
+// PullMergeChecklist
+// swagger:response PullMergeChecklist
+type swaggerResponsePullMergeChecklist struct {
+	// in:body
+	Body api.PullMergeChecklist `json:"body"`
+}
+
+// BundleRefUpdateList
+// swagger:response BundleRefUpdateList
+type swaggerResponseBundleRefUpdateList struct {
+	// in:body
+	Body []api.BundleRefUpdate `json:"body"`
+}
+
+// Badge
+// swagger:response Badge
+type swaggerResponseBadge struct {
+	// in:body
+	Body api.Badge `json:"body"`
+}
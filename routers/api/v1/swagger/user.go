@@ -23,6 +23,13 @@ type swaggerResponseUserList struct {
 	Body []api.User `json:"body"`
 }
 
+// AdminBulkUserResultList
+// swagger:response AdminBulkUserResultList
+type swaggerResponseAdminBulkUserResultList struct {
+	// in:body
+	Body []api.AdminBulkUserResult `json:"body"`
+}
+
 // EmailList
 // swagger:response EmailList
 type swaggerResponseEmailList struct {
@@ -49,3 +56,38 @@ type swaggerResponseUserSettings struct {
 	// in:body
 	Body []api.UserSettings `json:"body"`
 }
+
+// TwoFactorRecovery
+// swagger:response TwoFactorRecovery
+type swaggerResponseTwoFactorRecovery struct {
+	// in:body
+	Body api.TwoFactorRecovery `json:"body"`
+}
+
+// NamePolicy
+// swagger:response NamePolicy
+type swaggerResponseNamePolicy struct {
+	// in:body
+	Body api.NamePolicy `json:"body"`
+}
+
+// NamePolicyList
+// swagger:response NamePolicyList
+type swaggerResponseNamePolicyList struct {
+	// in:body
+	Body []api.NamePolicy `json:"body"`
+}
+
+// NameClaim
+// swagger:response NameClaim
+type swaggerResponseNameClaim struct {
+	// in:body
+	Body api.NameClaim `json:"body"`
+}
+
+// NameClaimList
+// swagger:response NameClaimList
+type swaggerResponseNameClaimList struct {
+	// in:body
+	Body []api.NameClaim `json:"body"`
+}
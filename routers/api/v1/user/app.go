@@ -12,6 +12,7 @@ import (
 	"strconv"
 
 	auth_model "code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -63,6 +64,7 @@ func ListAccessTokens(ctx *context.APIContext) {
 			ID:             tokens[i].ID,
 			Name:           tokens[i].Name,
 			TokenLastEight: tokens[i].TokenLastEight,
+			Scope:          string(tokens[i].Scope),
 		}
 	}
 
@@ -96,32 +98,60 @@ func CreateAccessToken(ctx *context.APIContext) {
 	//     "$ref": "#/responses/error"
 
 	form := web.GetForm(ctx).(*api.CreateAccessTokenOption)
+	CreateUserAccessToken(ctx, *form, ctx.Doer.ID)
+}
 
+// CreateUserAccessToken creates a new access token for the user with the id `uid`.
+// If there is an error, write to `ctx` accordingly and return nil
+func CreateUserAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption, uid int64) *auth_model.AccessToken {
 	t := &auth_model.AccessToken{
-		UID:  ctx.Doer.ID,
-		Name: form.Name,
+		UID:   uid,
+		Name:  form.Name,
+		Scope: auth_model.AccessTokenScope(form.Scope),
+	}
+	if !t.Scope.IsValid() {
+		ctx.Error(http.StatusBadRequest, "AccessTokenScope", fmt.Errorf("invalid access token scope: %s", form.Scope))
+		return nil
+	}
+	if form.PackageOwner != "" {
+		if !t.Scope.IsPackageScope() {
+			ctx.Error(http.StatusBadRequest, "AccessTokenScope", errors.New("a package owner requires a package scope"))
+			return nil
+		}
+		owner, err := user_model.GetUserByName(ctx, form.PackageOwner)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusBadRequest, "GetUserByName", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return nil
+		}
+		t.OwnerID = owner.ID
 	}
 
 	exist, err := auth_model.AccessTokenByNameExists(t)
 	if err != nil {
 		ctx.InternalServerError(err)
-		return
+		return nil
 	}
 	if exist {
 		ctx.Error(http.StatusBadRequest, "AccessTokenByNameExists", errors.New("access token name has been used already"))
-		return
+		return nil
 	}
 
 	if err := auth_model.NewAccessToken(t); err != nil {
 		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
-		return
+		return nil
 	}
 	ctx.JSON(http.StatusCreated, &api.AccessToken{
 		Name:           t.Name,
 		Token:          t.Token,
 		ID:             t.ID,
 		TokenLastEight: t.TokenLastEight,
+		Scope:          string(t.Scope),
 	})
+	return t
 }
 
 // DeleteAccessToken delete access tokens
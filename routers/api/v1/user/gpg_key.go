@@ -124,7 +124,8 @@ func GetGPGKey(ctx *context.APIContext) {
 }
 
 // CreateUserGPGKey creates new GPG key to given user by ID.
-func CreateUserGPGKey(ctx *context.APIContext, form api.CreateGPGKeyOption, uid int64) {
+// If there is an error, write to `ctx` accordingly and return nil
+func CreateUserGPGKey(ctx *context.APIContext, form api.CreateGPGKeyOption, uid int64) *asymkey_model.GPGKey {
 	token := asymkey_model.VerificationToken(ctx.Doer, 1)
 	lastToken := asymkey_model.VerificationToken(ctx.Doer, 0)
 
@@ -134,9 +135,10 @@ func CreateUserGPGKey(ctx *context.APIContext, form api.CreateGPGKeyOption, uid
 	}
 	if err != nil {
 		HandleAddGPGKeyError(ctx, err, token)
-		return
+		return nil
 	}
 	ctx.JSON(http.StatusCreated, convert.ToGPGKey(keys[0]))
+	return keys[0]
 }
 
 // GetVerificationToken returns the current token to be signed for this user
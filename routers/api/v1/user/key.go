@@ -193,17 +193,31 @@ func GetPublicKey(ctx *context.APIContext) {
 }
 
 // CreateUserPublicKey creates new public key to given user by ID.
-func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid int64) {
+// If there is an error, write to `ctx` accordingly and return nil
+func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid int64) *asymkey_model.PublicKey {
 	content, err := asymkey_model.CheckPublicKeyString(form.Key)
 	if err != nil {
 		repo.HandleCheckKeyStringError(ctx, err)
-		return
+		return nil
+	}
+
+	expires, ok := repo.KeyExpiryFromOption(ctx, &form)
+	if !ok {
+		return nil
 	}
 
 	key, err := asymkey_model.AddPublicKey(uid, form.Title, content, 0)
 	if err != nil {
 		repo.HandleAddKeyError(ctx, err)
-		return
+		return nil
+	}
+
+	if expires > 0 {
+		if err := asymkey_model.SetPublicKeyExpiry(ctx, key.ID, expires); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetPublicKeyExpiry", err)
+			return nil
+		}
+		key.ExpiresUnix = expires
 	}
 	apiLink := composePublicKeysAPILink()
 	apiKey := convert.ToPublicKey(apiLink, key)
@@ -211,6 +225,7 @@ func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid
 		apiKey, _ = appendPrivateInformation(apiKey, key, ctx.Doer)
 	}
 	ctx.JSON(http.StatusCreated, apiKey)
+	return key
 }
 
 // CreatePublicKey create one public key for me
@@ -0,0 +1,52 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ListCollaborations writes the collaborations of the repository, of all repositories if repoID is 0
+func ListCollaborations(ctx *context.APIContext, repoID int64) {
+	opts := &repo_model.FindCollaborationsOptions{
+		ListOptions:  GetListOptions(ctx),
+		RepoID:       repoID,
+		OnlyExpiring: ctx.FormBool("only_expiring"),
+	}
+	if before := ctx.FormTrim("expires_before"); before != "" {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		opts.ExpiresBefore = timeutil.TimeStamp(t.Unix())
+	}
+
+	collaborations, count, err := repo_model.FindCollaborations(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindCollaborations", err)
+		return
+	}
+
+	apiCollaborations := make([]*api.Collaboration, 0, len(collaborations))
+	for _, c := range collaborations {
+		apiCollaboration, err := convert.ToCollaboration(ctx, c, repoID == 0, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCollaboration", err)
+			return
+		}
+		apiCollaborations = append(apiCollaborations, apiCollaboration)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiCollaborations)
+}
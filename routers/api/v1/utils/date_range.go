@@ -0,0 +1,52 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+)
+
+// DateFormat is the format of the days of statistics
+const DateFormat = "2006-01-02"
+
+// ParseDateRange parses the since and until query parameters of statistics.
+// Both accept a date (YYYY-MM-DD) or a RFC3339 timestamp and default to the last defaultDays days.
+// The range must not exceed maxDays days.
+func ParseDateRange(ctx *context.APIContext, defaultDays, maxDays int) (since, until time.Time, err error) {
+	parse := func(name string, def time.Time) (time.Time, error) {
+		value := ctx.FormTrim(name)
+		if value == "" {
+			return def, nil
+		}
+		if t, err := time.Parse(DateFormat, value); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return t, fmt.Errorf("invalid %s parameter: %s", name, value)
+		}
+		return t, nil
+	}
+
+	until, err = parse("until", time.Now())
+	if err != nil {
+		return since, until, err
+	}
+	since, err = parse("since", until.AddDate(0, 0, 1-defaultDays))
+	if err != nil {
+		return since, until, err
+	}
+	if until.Before(since) {
+		return since, until, errors.New("until must not be before since")
+	}
+	if until.Sub(since) >= time.Duration(maxDays)*24*time.Hour {
+		return since, until, fmt.Errorf("the range must not exceed %d days", maxDays)
+	}
+	return since, until, nil
+}
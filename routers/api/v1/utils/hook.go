@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	webhook_service "code.gitea.io/gitea/services/webhook"
@@ -21,7 +22,20 @@ import (
 // GetOrgHook get an organization's webhook. If there is an error, write to
 // `ctx` accordingly and return the error
 func GetOrgHook(ctx *context.APIContext, orgID, hookID int64) (*webhook.Webhook, error) {
+	return getOrgHook(ctx, orgID, hookID, false)
+}
+
+// GetOrgDefaultHook get a default webhook of an organization. If there is an error, write to
+// `ctx` accordingly and return the error
+func GetOrgDefaultHook(ctx *context.APIContext, orgID, hookID int64) (*webhook.Webhook, error) {
+	return getOrgHook(ctx, orgID, hookID, true)
+}
+
+func getOrgHook(ctx *context.APIContext, orgID, hookID int64, isDefault bool) (*webhook.Webhook, error) {
 	w, err := webhook.GetWebhookByOrgID(orgID, hookID)
+	if err == nil && w.IsDefaultWebhook != isDefault {
+		err = webhook.ErrWebhookNotExist{ID: hookID}
+	}
 	if err != nil {
 		if webhook.IsErrWebhookNotExist(err) {
 			ctx.NotFound()
@@ -33,6 +47,24 @@ func GetOrgHook(ctx *context.APIContext, orgID, hookID int64) (*webhook.Webhook,
 	return w, nil
 }
 
+// GetAdminDefaultHook get an admin-default webhook. If there is an error, write to
+// `ctx` accordingly and return the error
+func GetAdminDefaultHook(ctx *context.APIContext, hookID int64) (*webhook.Webhook, error) {
+	w, err := webhook.GetSystemOrDefaultWebhook(hookID)
+	if err == nil && w.IsSystemWebhook {
+		err = webhook.ErrWebhookNotExist{ID: hookID}
+	}
+	if err != nil {
+		if webhook.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSystemOrDefaultWebhook", err)
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
 // GetRepoHook get a repo's webhook. If there is an error, write to `ctx`
 // accordingly and return the error
 func GetRepoHook(ctx *context.APIContext, repoID, hookID int64) (*webhook.Webhook, error) {
@@ -71,32 +103,92 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 // AddOrgHook add a hook to an organization. Writes to `ctx` accordingly
 func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	org := ctx.Org.Organization
-	hook, ok := addHook(ctx, form, org.ID, 0)
+	hook, ok := addHook(ctx, form, org.ID, 0, false)
+	if ok {
+		ctx.JSON(http.StatusCreated, convert.ToHook(org.AsUser().HomeLink(), hook))
+	}
+}
+
+// AddOrgDefaultHook add a default hook to an organization. Writes to `ctx` accordingly
+func AddOrgDefaultHook(ctx *context.APIContext, form *api.CreateHookOption) {
+	org := ctx.Org.Organization
+	hook, ok := addHook(ctx, form, org.ID, 0, true)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(org.AsUser().HomeLink(), hook))
 	}
 }
 
+// AddAdminDefaultHook add an admin-default hook. Writes to `ctx` accordingly
+func AddAdminDefaultHook(ctx *context.APIContext, form *api.CreateHookOption) {
+	hook, ok := addHook(ctx, form, 0, 0, false)
+	if ok {
+		ctx.JSON(http.StatusCreated, convert.ToHook(setting.AppSubURL+"/admin", hook))
+	}
+}
+
 // AddRepoHook add a hook to a repo. Writes to `ctx` accordingly
 func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	repo := ctx.Repo
-	hook, ok := addHook(ctx, form, 0, repo.Repository.ID)
+	hook, ok := addHook(ctx, form, 0, repo.Repository.ID, false)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(repo.RepoLink, hook))
 	}
 }
 
+// CreateRepoHook add a hook to the repository with the id `repoID`. If there is
+// an error, write to `ctx` accordingly. Return (webhook, ok)
+func CreateRepoHook(ctx *context.APIContext, form *api.CreateHookOption, repoID int64) (*webhook.Webhook, bool) {
+	return addHook(ctx, form, 0, repoID, false)
+}
+
+// ReplaceRepoHook replaces the settings of the repository hook `w` by the ones of
+// `form`, the secret is kept if `form` has none. If there is an error, write to
+// `ctx` accordingly. Return whether successful
+func ReplaceRepoHook(ctx *context.APIContext, form *api.CreateHookOption, w *webhook.Webhook) bool {
+	if secret, ok := form.Config["secret"]; ok {
+		w.Secret = secret
+	}
+	active := form.Active
+	return editHook(ctx, &api.EditHookOption{
+		Config:       form.Config,
+		Events:       form.Events,
+		BranchFilter: form.BranchFilter,
+		Active:       &active,
+	}, w)
+}
+
 func issuesHook(events []string, event string) bool {
 	return util.IsStringInSlice(event, events, true) || util.IsStringInSlice(string(webhook.HookEventIssues), events, true)
 }
 
+// releaseHook returns true if any release event is selected, all of them are covered by the release subscription
+func releaseHook(events []string) bool {
+	return util.IsStringInSlice(string(webhook.HookEventRelease), events, true) ||
+		util.IsStringInSlice(string(webhook.HookEventReleasePublished), events, true) ||
+		util.IsStringInSlice(string(webhook.HookEventReleaseEdited), events, true)
+}
+
 func pullHook(events []string, event string) bool {
 	return util.IsStringInSlice(event, events, true) || util.IsStringInSlice(string(webhook.HookEventPullRequest), events, true)
 }
 
-// addHook add the hook specified by `form`, `orgID` and `repoID`. If there is
-// an error, write to `ctx` accordingly. Return (webhook, ok)
-func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64) (*webhook.Webhook, bool) {
+// issueCommentHook returns true if any issue comment event is selected, all of them are covered by the issue comment subscription
+func issueCommentHook(events []string) bool {
+	return issuesHook(events, string(webhook.HookEventIssueComment)) ||
+		util.IsStringInSlice(string(webhook.HookEventIssueCommentEdited), events, true) ||
+		util.IsStringInSlice(string(webhook.HookEventIssueCommentDeleted), events, true)
+}
+
+// pullRequestCommentHook returns true if any pull request comment event is selected, all of them are covered by the pull request comment subscription
+func pullRequestCommentHook(events []string) bool {
+	return pullHook(events, string(webhook.HookEventPullRequestComment)) ||
+		util.IsStringInSlice(string(webhook.HookEventPullRequestCommentEdited), events, true) ||
+		util.IsStringInSlice(string(webhook.HookEventPullRequestCommentDeleted), events, true)
+}
+
+// addHook add the hook specified by `form`, `orgID` and `repoID`, `isDefault` marks a default hook of
+// the organization. If there is an error, write to `ctx` accordingly. Return (webhook, ok)
+func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64, isDefault bool) (*webhook.Webhook, bool) {
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
@@ -117,22 +209,25 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				IssueAssign:          issuesHook(form.Events, string(webhook.HookEventIssueAssign)),
 				IssueLabel:           issuesHook(form.Events, string(webhook.HookEventIssueLabel)),
 				IssueMilestone:       issuesHook(form.Events, string(webhook.HookEventIssueMilestone)),
-				IssueComment:         issuesHook(form.Events, string(webhook.HookEventIssueComment)),
+				IssueComment:         issueCommentHook(form.Events),
 				Push:                 util.IsStringInSlice(string(webhook.HookEventPush), form.Events, true),
 				PullRequest:          pullHook(form.Events, "pull_request_only"),
 				PullRequestAssign:    pullHook(form.Events, string(webhook.HookEventPullRequestAssign)),
 				PullRequestLabel:     pullHook(form.Events, string(webhook.HookEventPullRequestLabel)),
 				PullRequestMilestone: pullHook(form.Events, string(webhook.HookEventPullRequestMilestone)),
-				PullRequestComment:   pullHook(form.Events, string(webhook.HookEventPullRequestComment)),
+				PullRequestComment:   pullRequestCommentHook(form.Events),
 				PullRequestReview:    pullHook(form.Events, "pull_request_review"),
 				PullRequestSync:      pullHook(form.Events, string(webhook.HookEventPullRequestSync)),
 				Repository:           util.IsStringInSlice(string(webhook.HookEventRepository), form.Events, true),
-				Release:              util.IsStringInSlice(string(webhook.HookEventRelease), form.Events, true),
+				Release:              releaseHook(form.Events),
+				Reaction:             util.IsStringInSlice(string(webhook.HookEventReaction), form.Events, true),
+				Digest:               util.IsStringInSlice(string(webhook.HookEventDigest), form.Events, true),
 			},
 			BranchFilter: form.BranchFilter,
 		},
-		IsActive: form.Active,
-		Type:     form.Type,
+		IsActive:         form.Active,
+		Type:             form.Type,
+		IsDefaultWebhook: isDefault,
 	}
 	if w.Type == webhook.SLACK {
 		channel, ok := form.Config["channel"]
@@ -187,6 +282,39 @@ func EditOrgHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64
 	ctx.JSON(http.StatusOK, convert.ToHook(org.AsUser().HomeLink(), updated))
 }
 
+// EditOrgDefaultHook edit default webhook `w` according to `form`. Writes to `ctx` accordingly
+func EditOrgDefaultHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
+	org := ctx.Org.Organization
+	hook, err := GetOrgDefaultHook(ctx, org.ID, hookID)
+	if err != nil {
+		return
+	}
+	if !editHook(ctx, form, hook) {
+		return
+	}
+	updated, err := GetOrgDefaultHook(ctx, org.ID, hookID)
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(org.AsUser().HomeLink(), updated))
+}
+
+// EditAdminDefaultHook edit admin-default webhook `w` according to `form`. Writes to `ctx` accordingly
+func EditAdminDefaultHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
+	hook, err := GetAdminDefaultHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	if !editHook(ctx, form, hook) {
+		return
+	}
+	updated, err := GetAdminDefaultHook(ctx, hookID)
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(setting.AppSubURL+"/admin", updated))
+}
+
 // EditRepoHook edit webhook `w` according to `form`. Writes to `ctx` accordingly
 func EditRepoHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
 	repo := ctx.Repo
@@ -249,7 +377,9 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.Delete = util.IsStringInSlice(string(webhook.HookEventDelete), form.Events, true)
 	w.Fork = util.IsStringInSlice(string(webhook.HookEventFork), form.Events, true)
 	w.Repository = util.IsStringInSlice(string(webhook.HookEventRepository), form.Events, true)
-	w.Release = util.IsStringInSlice(string(webhook.HookEventRelease), form.Events, true)
+	w.Release = releaseHook(form.Events)
+	w.Reaction = util.IsStringInSlice(string(webhook.HookEventReaction), form.Events, true)
+	w.Digest = util.IsStringInSlice(string(webhook.HookEventDigest), form.Events, true)
 	w.BranchFilter = form.BranchFilter
 
 	// Issues
@@ -257,14 +387,14 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.IssueAssign = issuesHook(form.Events, string(webhook.HookEventIssueAssign))
 	w.IssueLabel = issuesHook(form.Events, string(webhook.HookEventIssueLabel))
 	w.IssueMilestone = issuesHook(form.Events, string(webhook.HookEventIssueMilestone))
-	w.IssueComment = issuesHook(form.Events, string(webhook.HookEventIssueComment))
+	w.IssueComment = issueCommentHook(form.Events)
 
 	// Pull requests
 	w.PullRequest = pullHook(form.Events, "pull_request_only")
 	w.PullRequestAssign = pullHook(form.Events, string(webhook.HookEventPullRequestAssign))
 	w.PullRequestLabel = pullHook(form.Events, string(webhook.HookEventPullRequestLabel))
 	w.PullRequestMilestone = pullHook(form.Events, string(webhook.HookEventPullRequestMilestone))
-	w.PullRequestComment = pullHook(form.Events, string(webhook.HookEventPullRequestComment))
+	w.PullRequestComment = pullRequestCommentHook(form.Events)
 	w.PullRequestReview = pullHook(form.Events, "pull_request_review")
 	w.PullRequestSync = pullHook(form.Events, string(webhook.HookEventPullRequestSync))
 
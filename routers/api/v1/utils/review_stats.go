@@ -0,0 +1,73 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+const (
+	defaultReviewStatsDays = 30
+	maxReviewStatsDays     = 366
+)
+
+func toPullReviewDurations(stats *pull_service.DurationStats) *api.PullReviewDurations {
+	return &api.PullReviewDurations{
+		Count:          stats.Count,
+		AverageSeconds: int64(stats.Average / time.Second),
+		MedianSeconds:  int64(stats.Median / time.Second),
+		P90Seconds:     int64(stats.P90 / time.Second),
+	}
+}
+
+// ServeReviewStats writes the review statistics of the pull requests of the repositories selected by the options
+func ServeReviewStats(ctx *context.APIContext, opts issues_model.ReviewStatsOptions) {
+	since, until, err := ParseDateRange(ctx, defaultReviewStatsDays, maxReviewStatsDays)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	stats, err := pull_service.GetReviewStats(ctx, opts, since, until)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewStats", err)
+		return
+	}
+
+	apiStats := &api.PullReviewStats{
+		Since:             stats.Since.FormatInLocation(DateFormat, time.UTC),
+		Until:             stats.Until.FormatInLocation(DateFormat, time.UTC),
+		Opened:            stats.Opened,
+		Merged:            stats.Merged,
+		TimeToFirstReview: toPullReviewDurations(stats.TimeToFirstReview),
+		TimeToMerge:       toPullReviewDurations(stats.TimeToMerge),
+		Sizes:             make([]*api.PullSizeCount, 0, len(stats.Sizes)),
+		Reviewers:         make([]*api.PullReviewerStats, 0, len(stats.Reviewers)),
+	}
+	for _, s := range stats.Sizes {
+		apiStats.Sizes = append(apiStats.Sizes, &api.PullSizeCount{
+			Size:  s.Size,
+			Count: s.Count,
+		})
+	}
+	for _, r := range stats.Reviewers {
+		apiStats.Reviewers = append(apiStats.Reviewers, &api.PullReviewerStats{
+			Reviewer:   convert.ToUser(r.Reviewer, ctx.Doer),
+			Reviews:    r.Reviews,
+			Approvals:  r.Approvals,
+			Rejections: r.Rejections,
+			Comments:   r.Comments,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, apiStats)
+}
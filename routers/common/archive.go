@@ -0,0 +1,32 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package common
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+)
+
+// RedirectToCanonicalArchive redirects a request for an archive without a format or by an abbreviated
+// commit id to the canonical URL of the archive below archiveLink, so caches don't store duplicates.
+// It returns true if the request was redirected.
+func RedirectToCanonicalArchive(ctx *context.Context, archiveLink string, aReq *archiver_service.ArchiveRequest, suffix string) bool {
+	canonicalPath := aReq.CanonicalPath() + suffix
+	if canonicalPath == ctx.Params("*") {
+		return false
+	}
+
+	query := ctx.Req.URL.Query()
+	query.Del("format")
+	redirect := archiveLink + "/" + util.PathEscapeSegments(canonicalPath)
+	if len(query) > 0 {
+		redirect += "?" + query.Encode()
+	}
+	ctx.Redirect(redirect, http.StatusFound)
+	return true
+}
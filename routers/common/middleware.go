@@ -57,6 +57,7 @@ func Middlewares() []func(http.Handler) http.Handler {
 	if setting.EnableAccessLog {
 		handlers = append(handlers, context.AccessLogger())
 	}
+	handlers = append(handlers, context.RequestRecorder())
 
 	handlers = append(handlers, func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
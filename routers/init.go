@@ -23,6 +23,8 @@ import (
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/external"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/pushvalidation"
+	"code.gitea.io/gitea/modules/refevents"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/ssh"
 	"code.gitea.io/gitea/modules/storage"
@@ -32,6 +34,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	packages_router "code.gitea.io/gitea/routers/api/packages"
+	container_router "code.gitea.io/gitea/routers/api/packages/container"
 	apiv1 "code.gitea.io/gitea/routers/api/v1"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/routers/private"
@@ -43,7 +46,9 @@ import (
 	"code.gitea.io/gitea/services/mailer"
 	repo_migrations "code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	pages_service "code.gitea.io/gitea/services/pages"
 	pull_service "code.gitea.io/gitea/services/pull"
+	release_service "code.gitea.io/gitea/services/release"
 	repo_service "code.gitea.io/gitea/services/repository"
 	"code.gitea.io/gitea/services/repository/archiver"
 	"code.gitea.io/gitea/services/task"
@@ -124,6 +129,8 @@ func GlobalInitInstalled(ctx context.Context) {
 	highlight.NewContext()
 	external.RegisterRenderers()
 	markup.Init()
+	mustInit(pushvalidation.Init)
+	mustInit(refevents.Init)
 
 	if setting.EnableSQLite3 {
 		log.Info("SQLite3 support is enabled")
@@ -138,6 +145,8 @@ func GlobalInitInstalled(ctx context.Context) {
 
 	models.NewRepoContext()
 	mustInit(repo_service.Init)
+	mustInit(release_service.Init)
+	mustInit(pages_service.Init)
 
 	// Booting long running goroutines.
 	issue_indexer.InitIssueIndexer(false)
@@ -170,6 +179,9 @@ func NormalRoutes(ctx context.Context) *web.Route {
 	for _, middle := range common.Middlewares() {
 		r.Use(middle)
 	}
+	if setting.Packages.Enabled {
+		r.Use(container_router.DomainRouting)
+	}
 
 	r.Mount("/", web_routers.Routes(ctx))
 	r.Mount("/api/v1", apiv1.Routes(ctx))
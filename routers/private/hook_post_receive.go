@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/refevents"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -92,6 +93,20 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	// Notify the replication consumers in the background, the broker must not delay the push.
+	// The events may be published before the push updates above are processed by the push_update queue.
+	if refevents.IsEnabled() {
+		if repo == nil {
+			repo = loadRepository(ctx, ownerName, repoName)
+			if ctx.Written() {
+				// Error handled in loadRepository
+				return
+			}
+			wasEmpty = repo.IsEmpty
+		}
+		queueRefEvents(opts, repo)
+	}
+
 	// Handle Push Options
 	if len(opts.GitPushOptions) > 0 {
 		// load the repository
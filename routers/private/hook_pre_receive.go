@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/pushvalidation"
 	"code.gitea.io/gitea/modules/web"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
@@ -157,6 +158,13 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 		return
 	}
 
+	// Validate the pushed files against the push validation rules configured for the repository
+	if newCommitID != git.EmptySHA && !ctx.opts.IsWiki && pushvalidation.HasValidators(repo.FullName()) {
+		if !validatePushedFiles(ctx, branchName, oldCommitID, newCommitID) {
+			return
+		}
+	}
+
 	protectBranch, err := git_model.GetProtectedBranchBy(ctx, repo.ID, branchName)
 	if err != nil {
 		log.Error("Unable to get protected branch: %s in %-v Error: %v", branchName, repo, err)
@@ -0,0 +1,67 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/pushvalidation"
+)
+
+// validatePushedFiles runs the push validators on the files changed by the push,
+// it returns false and writes the response if the push is rejected
+func validatePushedFiles(ctx *preReceiveContext, branchName, oldCommitID, newCommitID string) bool {
+	repo := ctx.Repo.Repository
+	gitRepo := ctx.Repo.GitRepo
+
+	// A new branch is compared with the default branch, so only the files introduced by it are validated
+	baseCommitID := oldCommitID
+	if baseCommitID == git.EmptySHA {
+		baseCommitID = git.EmptyTreeSHA
+		if branchName != repo.DefaultBranch {
+			if commitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch); err == nil {
+				baseCommitID = commitID
+			}
+		}
+	}
+
+	blobs, err := git.GetChangedBlobs(gitRepo, baseCommitID, newCommitID, ctx.env)
+	if err != nil {
+		log.Error("Unable to get changed files from %s to %s in %-v: %v", baseCommitID, newCommitID, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to get changed files from %s to %s: %v", baseCommitID, newCommitID, err),
+		})
+		return false
+	}
+
+	files := make([]*pushvalidation.File, 0, len(blobs))
+	for _, blob := range blobs {
+		blob := blob
+		files = append(files, pushvalidation.NewFile(blob.Path, blob.Size, func() ([]byte, error) {
+			return git.ReadChangedBlob(gitRepo, blob, ctx.env)
+		}))
+	}
+
+	violations, err := pushvalidation.Validate(repo.FullName(), files)
+	if err != nil {
+		log.Error("Unable to validate files from %s to %s in %-v: %v", baseCommitID, newCommitID, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to validate files from %s to %s: %v", baseCommitID, newCommitID, err),
+		})
+		return false
+	}
+	if len(violations) > 0 {
+		log.Warn("Forbidden: Branch: %s in %-v rejected by %d push validation violations", branchName, repo, len(violations))
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: pushvalidation.FormatViolations(branchName, violations),
+		})
+		return false
+	}
+	return true
+}
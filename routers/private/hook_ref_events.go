@@ -0,0 +1,28 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/refevents"
+)
+
+// queueRefEvents queues the updated references of a push to be published to the configured broker.
+// The push has already been accepted, so failures are only logged.
+func queueRefEvents(opts *private.HookOptions, repo *repo_model.Repository) {
+	events := make([]*refevents.Event, 0, len(opts.RefFullNames))
+	for i := range opts.OldCommitIDs {
+		if !refevents.ShouldPublish(opts.RefFullNames[i]) {
+			continue
+		}
+		events = append(events, refevents.NewEvent(repo.ID, repo.FullName(), opts.IsWiki, opts.RefFullNames[i], opts.OldCommitIDs[i], opts.NewCommitIDs[i], opts.UserID, opts.UserName))
+	}
+
+	if err := refevents.Enqueue(events); err != nil {
+		log.Error("Unable to queue %d ref events of %-v: %v", len(events), repo, err)
+	}
+}
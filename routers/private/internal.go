@@ -72,6 +72,9 @@ func Routes() *web.Route {
 	r.Post("/manager/add-logger", bind(private.LoggerOptions{}), AddLogger)
 	r.Post("/manager/remove-logger/{group}/{name}", RemoveLogger)
 	r.Get("/manager/processes", Processes)
+	r.Post("/manager/repo-stats/recalculation", bind(private.RecalculateRepoStatsOptions{}), RecalculateRepoStats)
+	r.Get("/manager/repo-stats/recalculation", GetRepoStatsRecalculation)
+	r.Post("/manager/repo-stats/recalculation/cancel", CancelRepoStatsRecalculation)
 	r.Post("/mail/send", SendEmail)
 	r.Post("/restore_repo", RestoreRepo)
 
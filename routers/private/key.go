@@ -37,7 +37,8 @@ func UpdatePublicKeyInRepo(ctx *context.PrivateContext) {
 		return
 	}
 	deployKey.UpdatedUnix = timeutil.TimeStampNow()
-	if err = asymkey_model.UpdateDeployKeyCols(deployKey, "updated_unix"); err != nil {
+	deployKey.LastUsedUnix = deployKey.UpdatedUnix
+	if err = asymkey_model.UpdateDeployKeyCols(deployKey, "updated_unix", "last_used_unix"); err != nil {
 		ctx.JSON(http.StatusInternalServerError, private.Response{
 			Err: err.Error(),
 		})
@@ -0,0 +1,82 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/web"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// RecalculateRepoStats starts the recalculation of the statistics of all repositories
+func RecalculateRepoStats(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.RecalculateRepoStatsOptions)
+
+	kinds := make([]repo_model.StatsRecalculationKind, 0, len(opts.Kinds))
+	for _, k := range opts.Kinds {
+		kind := repo_model.StatsRecalculationKind(strings.ToLower(strings.TrimSpace(k)))
+		if !kind.IsValid() {
+			ctx.JSON(http.StatusBadRequest, private.Response{
+				Err: fmt.Sprintf("Unknown kind of repository statistics: %s", k),
+			})
+			return
+		}
+		kinds = append(kinds, kind)
+	}
+
+	r, err := repo_service.StartStatsRecalculation(ctx, 0, kinds)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if repo_model.IsErrStatsRecalculationRunning(err) {
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoStatsRecalculation(r))
+}
+
+// GetRepoStatsRecalculation returns the progress of the latest recalculation of the repository statistics
+func GetRepoStatsRecalculation(ctx *context.PrivateContext) {
+	r, err := repo_model.GetLatestStatsRecalculation(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	} else if r == nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			Err: "The repository statistics were never recalculated",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoStatsRecalculation(r))
+}
+
+// CancelRepoStatsRecalculation cancels the running recalculation of the repository statistics
+func CancelRepoStatsRecalculation(ctx *context.PrivateContext) {
+	r, err := repo_model.CancelStatsRecalculation(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	} else if r == nil {
+		ctx.JSON(http.StatusNotFound, private.Response{
+			Err: "No recalculation of the repository statistics is running",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoStatsRecalculation(r))
+}
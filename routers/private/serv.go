@@ -51,6 +51,13 @@ func ServNoCommand(ctx *context.PrivateContext) {
 	}
 	results.Key = key
 
+	if key.Type != asymkey_model.KeyTypeDeploy && key.IsExpired() {
+		ctx.JSON(http.StatusUnauthorized, private.Response{
+			Err: fmt.Sprintf("Key %d:%s expired on %s", key.ID, key.Name, key.ExpiresUnix.FormatDate()),
+		})
+		return
+	}
+
 	if key.Type == asymkey_model.KeyTypeUser || key.Type == asymkey_model.KeyTypePrincipal {
 		user, err := user_model.GetUserByID(key.OwnerID)
 		if err != nil {
@@ -214,6 +221,14 @@ func ServCommand(ctx *context.PrivateContext) {
 	results.KeyID = key.ID
 	results.UserID = key.OwnerID
 
+	if key.Type != asymkey_model.KeyTypeDeploy && key.IsExpired() {
+		ctx.JSON(http.StatusUnauthorized, private.ErrServCommand{
+			Results: results,
+			Err:     fmt.Sprintf("Public Key: %d:%s expired on %s", key.ID, key.Name, key.ExpiresUnix.FormatDate()),
+		})
+		return
+	}
+
 	// If repo doesn't exist, deploy key doesn't make sense
 	if !repoExist && key.Type == asymkey_model.KeyTypeDeploy {
 		ctx.JSON(http.StatusNotFound, private.ErrServCommand{
@@ -249,6 +264,14 @@ func ServCommand(ctx *context.PrivateContext) {
 		results.DeployKeyID = deployKey.ID
 		results.KeyName = deployKey.Name
 
+		if deployKey.IsExpired() {
+			ctx.JSON(http.StatusUnauthorized, private.ErrServCommand{
+				Results: results,
+				Err:     fmt.Sprintf("Deploy Key: %d:%s expired on %s", deployKey.ID, deployKey.Name, deployKey.ExpiresUnix.FormatDate()),
+			})
+			return
+		}
+
 		// FIXME: Deploy keys aren't really the owner of the repo pushing changes
 		// however we don't have good way of representing deploy keys in hook.go
 		// so for now use the owner of the repository
@@ -56,6 +56,12 @@ func Authentications(ctx *context.Context) {
 		return
 	}
 
+	ctx.Data["SourceHealths"], err = auth.GetSourceHealthMap(ctx)
+	if err != nil {
+		ctx.ServerError("auth.GetSourceHealthMap", err)
+		return
+	}
+
 	ctx.Data["Total"] = auth.CountSources()
 	ctx.HTML(http.StatusOK, tplAuths)
 }
@@ -316,6 +322,32 @@ func NewAuthSourcePost(ctx *context.Context) {
 	ctx.Redirect(setting.AppSubURL + "/admin/auths")
 }
 
+// prepareAuthSourceHealthAndFallbacks loads the health of the source and the sources which can be used as its fallback
+func prepareAuthSourceHealthAndFallbacks(ctx *context.Context, source *auth.Source) {
+	health, err := auth.GetSourceHealth(ctx, source.ID)
+	if err != nil {
+		ctx.ServerError("auth.GetSourceHealth", err)
+		return
+	}
+	ctx.Data["SourceHealth"] = health
+
+	sources, err := auth.Sources()
+	if err != nil {
+		ctx.ServerError("auth.Sources", err)
+		return
+	}
+	fallbacks := make([]*auth.Source, 0, len(sources))
+	for _, s := range sources {
+		if _, ok := s.Cfg.(auth_service.PasswordAuthenticator); ok && s.ID != source.ID {
+			fallbacks = append(fallbacks, s)
+		}
+		if s.ID == source.FallbackSourceID {
+			ctx.Data["FallbackSource"] = s
+		}
+	}
+	ctx.Data["FallbackSources"] = fallbacks
+}
+
 // EditAuthSource render editing auth source page
 func EditAuthSource(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.auths.edit")
@@ -335,6 +367,11 @@ func EditAuthSource(ctx *context.Context) {
 	ctx.Data["Source"] = source
 	ctx.Data["HasTLS"] = source.HasTLS()
 
+	prepareAuthSourceHealthAndFallbacks(ctx, source)
+	if ctx.Written() {
+		return
+	}
+
 	if source.IsOAuth2() {
 		type Named interface {
 			Name() string
@@ -370,11 +407,33 @@ func EditAuthSourcePost(ctx *context.Context) {
 	ctx.Data["Source"] = source
 	ctx.Data["HasTLS"] = source.HasTLS()
 
+	prepareAuthSourceHealthAndFallbacks(ctx, source)
+	if ctx.Written() {
+		return
+	}
+
 	if ctx.HasError() {
 		ctx.HTML(http.StatusOK, tplAuthEdit)
 		return
 	}
 
+	if form.FallbackSourceID != 0 {
+		if form.FallbackSourceID == source.ID {
+			ctx.Data["Err_FallbackSource"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.fallback_source_self"), tplAuthEdit, form)
+			return
+		}
+		if _, err := auth.GetSourceByID(form.FallbackSourceID); err != nil {
+			if auth.IsErrSourceNotExist(err) {
+				ctx.Data["Err_FallbackSource"] = true
+				ctx.RenderWithErr(ctx.Tr("admin.auths.fallback_source_not_exist"), tplAuthEdit, form)
+			} else {
+				ctx.ServerError("auth.GetSourceByID", err)
+			}
+			return
+		}
+	}
+
 	var config convert.Conversion
 	switch auth.Type(form.Type) {
 	case auth.LDAP, auth.DLDAP:
@@ -402,6 +461,7 @@ func EditAuthSourcePost(ctx *context.Context) {
 	source.Name = form.Name
 	source.IsActive = form.IsActive
 	source.IsSyncEnabled = form.IsSyncEnabled
+	source.FallbackSourceID = form.FallbackSourceID
 	source.Cfg = config
 	// FIXME: if the name conflicts, it will result in 500: Error 1062: Duplicate entry 'aa' for key 'login_source.UQE_login_source_name'
 	if err := auth.UpdateSource(source); err != nil {
@@ -445,3 +505,29 @@ func DeleteAuthSource(ctx *context.Context) {
 		"redirect": setting.AppSubURL + "/admin/auths",
 	})
 }
+
+// CheckAuthSourceHealth checks the health of an auth source
+func CheckAuthSourceHealth(ctx *context.Context) {
+	source, err := auth.GetSourceByID(ctx.ParamsInt64(":authid"))
+	if err != nil {
+		if auth.IsErrSourceNotExist(err) {
+			ctx.NotFound("auth.GetSourceByID", err)
+		} else {
+			ctx.ServerError("auth.GetSourceByID", err)
+		}
+		return
+	}
+
+	if !source.IsHealthCheckable() {
+		ctx.Flash.Error(ctx.Tr("admin.auths.health.not_checkable"))
+	} else if health, err := auth_service.CheckSourceHealth(ctx, source); err != nil {
+		ctx.ServerError("auth_service.CheckSourceHealth", err)
+		return
+	} else if health.IsHealthy {
+		ctx.Flash.Success(ctx.Tr("admin.auths.health.check_success"))
+	} else {
+		ctx.Flash.Error(ctx.Tr("admin.auths.health.check_failed", health.Error))
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/admin/auths/" + strconv.FormatInt(source.ID, 10))
+}
@@ -10,15 +10,18 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	packages_service "code.gitea.io/gitea/services/packages"
 )
 
 const (
-	tplPackagesList base.TplName = "admin/packages/list"
+	tplPackagesList  base.TplName = "admin/packages/list"
+	tplPackagesAudit base.TplName = "admin/packages/audit"
 )
 
 // Packages shows all packages
@@ -77,6 +80,89 @@ func Packages(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplPackagesList)
 }
 
+// PackagesAudit shows the package audit log
+func PackagesAudit(ctx *context.Context) {
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	owner := ctx.FormTrim("owner")
+	actor := ctx.FormTrim("actor")
+	action := ctx.FormTrim("action")
+	packageType := ctx.FormTrim("type")
+	name := ctx.FormTrim("name")
+
+	ctx.Data["Title"] = ctx.Tr("admin.packages.audit")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminPackages"] = true
+	ctx.Data["Owner"] = owner
+	ctx.Data["Actor"] = actor
+	ctx.Data["Action"] = action
+	ctx.Data["PackageType"] = packageType
+	ctx.Data["Name"] = name
+
+	opts := &packages_model.AuditLogSearchOptions{
+		Scope:       packages_model.AuditScopePackage,
+		Action:      packages_model.AuditAction(action),
+		PackageType: packages_model.Type(packageType),
+		PackageName: name,
+		Paginator: &db.ListOptions{
+			PageSize: setting.UI.PackagesPagingNum,
+			Page:     page,
+		},
+	}
+
+	found := resolveAuditUser(ctx, owner, &opts.OwnerID) && resolveAuditUser(ctx, actor, &opts.ActorID)
+	if ctx.Written() {
+		return
+	}
+
+	var logs packages_model.AuditLogList
+	var total int64
+	if found {
+		var err error
+		logs, total, err = packages_model.SearchAuditLogs(ctx, opts)
+		if err != nil {
+			ctx.ServerError("SearchAuditLogs", err)
+			return
+		}
+		if err := logs.LoadOwners(ctx); err != nil {
+			ctx.ServerError("LoadOwners", err)
+			return
+		}
+	}
+
+	ctx.Data["AuditLogs"] = logs
+	ctx.Data["Total"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.PackagesPagingNum, page, 5)
+	pager.AddParamString("owner", owner)
+	pager.AddParamString("actor", actor)
+	pager.AddParamString("action", action)
+	pager.AddParamString("type", packageType)
+	pager.AddParamString("name", name)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplPackagesAudit)
+}
+
+// resolveAuditUser sets id to the id of the named user.
+// It returns false if the user does not exist, nothing can match the filter then.
+func resolveAuditUser(ctx *context.Context, name string, id *int64) bool {
+	if name == "" {
+		return true
+	}
+	u, err := user_model.GetUserByName(ctx, name)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			ctx.ServerError("GetUserByName", err)
+		}
+		return false
+	}
+	*id = u.ID
+	return true
+}
+
 // DeletePackageVersion deletes a package version
 func DeletePackageVersion(ctx *context.Context) {
 	pv, err := packages_model.GetVersionByID(db.DefaultContext, ctx.FormInt64("id"))
@@ -85,8 +171,9 @@ func DeletePackageVersion(ctx *context.Context) {
 		return
 	}
 
-	if err := packages_service.RemovePackageVersion(ctx.Doer, pv); err != nil {
-		ctx.ServerError("RemovePackageVersion", err)
+	// Deleting a package version in the admin panel overrides the immutability of the versions
+	if err := packages_service.ForceRemovePackageVersion(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, pv); err != nil {
+		ctx.ServerError("ForceRemovePackageVersion", err)
 		return
 	}
 
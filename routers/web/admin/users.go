@@ -69,6 +69,7 @@ func Users(ctx *context.Context) {
 			PageSize: setting.UI.Admin.UserPagingNum,
 		},
 		SearchByEmail:      true,
+		IncludeBots:        true,
 		IsActive:           util.OptionalBoolParse(statusFilterMap["is_active"]),
 		IsAdmin:            util.OptionalBoolParse(statusFilterMap["is_admin"]),
 		IsRestricted:       util.OptionalBoolParse(statusFilterMap["is_restricted"]),
@@ -6,12 +6,15 @@ package auth
 
 import (
 	"errors"
+	"net"
 	"net/http"
 
 	"code.gitea.io/gitea/models/auth"
+	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/externalaccount"
@@ -33,11 +36,23 @@ func TwoFactor(ctx *context.Context) {
 	}
 
 	// Ensure user is in a 2FA session.
-	if ctx.Session.Get("twofaUid") == nil {
+	id, ok := ctx.Session.Get("twofaUid").(int64)
+	if !ok {
 		ctx.ServerError("UserSignIn", errors.New("not in 2FA session"))
 		return
 	}
 
+	// TOTP passcodes are disabled for the user
+	keysOnly, err := user_model.IsSecurityKeysOnly(id)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	if keysOnly {
+		ctx.Redirect(setting.AppSubURL + "/user/webauthn")
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplTwofa)
 }
 
@@ -54,6 +69,16 @@ func TwoFactorPost(ctx *context.Context) {
 	}
 
 	id := idSess.(int64)
+	keysOnly, err := user_model.IsSecurityKeysOnly(id)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	if keysOnly {
+		ctx.RenderWithErr(ctx.Tr("auth.twofa_security_keys_only"), tplTwofa, forms.TwoFactorAuthForm{})
+		return
+	}
+
 	twofa, err := auth.GetTwoFactorByUID(id)
 	if err != nil {
 		ctx.ServerError("UserSignIn", err)
@@ -127,14 +152,21 @@ func TwoFactorScratchPost(ctx *context.Context) {
 	}
 
 	id := idSess.(int64)
-	twofa, err := auth.GetTwoFactorByUID(id)
+	keysOnly, err := user_model.IsSecurityKeysOnly(id)
 	if err != nil {
 		ctx.ServerError("UserSignIn", err)
 		return
 	}
 
-	// Validate the passcode with the stored TOTP secret.
-	if twofa.VerifyScratchToken(form.Token) {
+	// Users who only registered security keys have no scratch code
+	twofa, err := auth.GetTwoFactorByUID(id)
+	if err != nil && !auth.IsErrTwoFactorNotEnrolled(err) {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+
+	// Validate the scratch code, unless only security keys are allowed.
+	if twofa != nil && !keysOnly && twofa.VerifyScratchToken(form.Token) {
 		// Invalidate the scratch token.
 		_, err = twofa.GenerateScratchToken()
 		if err != nil {
@@ -162,5 +194,43 @@ func TwoFactorScratchPost(ctx *context.Context) {
 		return
 	}
 
+	// A recovery code issued by an administrator is valid once in every mode.
+	ok, err := auth.UseTwoFactorRecovery(ctx, id, form.Token)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	if ok {
+		remember := ctx.Session.Get("twofaRemember").(bool)
+		u, err := user_model.GetUserByID(id)
+		if err != nil {
+			ctx.ServerError("UserSignIn", err)
+			return
+		}
+
+		ip := ctx.RemoteAddr()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		if err := packages_model.InsertAuditLog(ctx, &packages_model.PackageAuditLog{
+			OwnerID:   u.ID,
+			Action:    packages_model.AuditActionUseTwoFactorRecovery,
+			ActorID:   u.ID,
+			ActorName: u.Name,
+			IP:        ip,
+			UserAgent: ctx.Req.UserAgent(),
+		}); err != nil {
+			log.Error("Error recording the use of a recovery code by %s in the audit log: %v", u.Name, err)
+		}
+
+		handleSignInFull(ctx, u, remember, false)
+		if ctx.Written() {
+			return
+		}
+		ctx.Flash.Info(ctx.Tr("auth.twofa_recovery_used"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/security")
+		return
+	}
+
 	ctx.RenderWithErr(ctx.Tr("auth.twofa_scratch_token_incorrect"), tplTwofaScratch, forms.TwoFactorScratchAuthForm{})
 }
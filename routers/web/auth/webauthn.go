@@ -33,11 +33,19 @@ func WebAuthn(ctx *context.Context) {
 	}
 
 	// Ensure user is in a 2FA session.
-	if ctx.Session.Get("twofaUid") == nil {
+	id, ok := ctx.Session.Get("twofaUid").(int64)
+	if !ok {
 		ctx.ServerError("UserSignIn", errors.New("not in WebAuthn session"))
 		return
 	}
 
+	keysOnly, err := user_model.IsSecurityKeysOnly(id)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	ctx.Data["SecurityKeysOnly"] = keysOnly
+
 	ctx.HTML(http.StatusOK, tplWebAuthn)
 }
 
@@ -0,0 +1,57 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package explore
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	search_service "code.gitea.io/gitea/services/search"
+)
+
+// Search searches repositories, code, issues, users and packages for the omnibox
+func Search(ctx *context.Context) {
+	types, err := search_service.ParseTypes(ctx.FormStrings("type"))
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"ok":    false,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	results, err := search_service.Search(ctx, &search_service.Options{
+		ListOptions: db.ListOptions{
+			Page:     ctx.FormInt("page"),
+			PageSize: convert.ToCorrectPageSize(ctx.FormInt("limit")),
+		},
+		Doer:    ctx.Doer,
+		Keyword: ctx.FormTrim("q"),
+		Types:   types,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"ok":    false,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	apiResults, err := convert.ToGlobalSearchResults(ctx, results, ctx.Doer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"ok":    false,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"ok":   true,
+		"data": apiResults,
+	})
+}
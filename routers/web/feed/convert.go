@@ -206,7 +206,9 @@ func feedActionsToFeedItems(ctx *context.Context, actions activities_model.Actio
 					)
 				}
 
-				if push.Len > 1 {
+				if push.CommitsURL != "" {
+					link = &feeds.Link{Href: fmt.Sprintf("%s/%s", setting.AppSubURL, push.CommitsURL)}
+				} else if push.Len > 1 {
 					link = &feeds.Link{Href: fmt.Sprintf("%s/%s", setting.AppSubURL, push.CompareURL)}
 				} else if push.Len == 1 {
 					link = &feeds.Link{Href: fmt.Sprintf("%s/commit/%s", act.GetRepoLink(), push.Commits[0].Sha1)}
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	user_setting "code.gitea.io/gitea/routers/web/user/setting"
 	"code.gitea.io/gitea/services/forms"
@@ -47,6 +48,9 @@ func Settings(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["CurrentVisibility"] = ctx.Org.Organization.Visibility
 	ctx.Data["RepoAdminChangeTeamAccess"] = ctx.Org.Organization.RepoAdminChangeTeamAccess
+	if !user_setting.LoadPackagesAnonymousRead(ctx, ctx.Org.Organization.AsUser()) {
+		return
+	}
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
@@ -123,6 +127,14 @@ func SettingsPost(ctx *context.Context) {
 		return
 	}
 
+	if !user_setting.UpdatePackagesAnonymousRead(ctx, org.AsUser(), form.PackagesAnonymousRead) {
+		return
+	}
+
+	if !user_setting.UpdatePackagesImmutableVersions(ctx, org.AsUser(), form.PackagesImmutableVersions) {
+		return
+	}
+
 	// update forks visibility
 	if visibilityChanged {
 		repos, _, err := repo_model.GetUserRepositories(&repo_model.SearchRepoOptions{
@@ -215,7 +227,7 @@ func Webhooks(ctx *context.Context) {
 	ctx.Data["BaseLinkNew"] = ctx.Org.OrgLink + "/settings/hooks"
 	ctx.Data["Description"] = ctx.Tr("org.settings.hooks_desc")
 
-	ws, err := webhook.ListWebhooksByOpts(ctx, &webhook.ListWebhookOptions{OrgID: ctx.Org.Organization.ID})
+	ws, err := webhook.ListWebhooksByOpts(ctx, &webhook.ListWebhookOptions{OrgID: ctx.Org.Organization.ID, IsDefault: util.OptionalBoolFalse})
 	if err != nil {
 		ctx.ServerError("GetWebhooksByOrgId", err)
 		return
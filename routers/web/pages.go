@@ -0,0 +1,81 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/routers/web/repo"
+)
+
+// pagesHost serves the pages of the repositories of an owner on the pages domain: <owner>.<domain>/<repo>/<path>
+func pagesHost(ctx *context.Context) {
+	host := strings.ToLower(ctx.Req.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ownerName := strings.TrimSuffix(host, "."+setting.Pages.Domain)
+	if ownerName == host || ownerName == "" || strings.Contains(ownerName, ".") {
+		return
+	}
+
+	if ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead {
+		ctx.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if setting.Service.RequireSignInView && !ctx.IsSigned {
+		ctx.NotFound("RequireSignInView", nil)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ctx.Req.URL.Path, "/"), "/", 2)
+	if parts[0] == "" {
+		ctx.NotFound("pagesHost", nil)
+		return
+	}
+
+	r, err := repo_model.GetRepositoryByOwnerAndNameCtx(ctx, ownerName, parts[0])
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByOwnerAndName", nil)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, r, ctx.Doer)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanRead(unit.TypePages) {
+		ctx.NotFound("CanRead", nil)
+		return
+	}
+
+	if len(parts) == 1 {
+		ctx.Redirect(ctx.Req.URL.EscapedPath()+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, r.RepoPath())
+	if err != nil {
+		ctx.ServerError("OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	repo.ServePage(ctx, r, gitRepo, parts[1], false)
+}
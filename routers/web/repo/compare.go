@@ -34,6 +34,7 @@ import (
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/gitdiff"
+	pull_service "code.gitea.io/gitea/services/pull"
 )
 
 const (
@@ -62,6 +63,7 @@ func setCompareContext(ctx *context.Context, base, head *git.Commit, headOwner,
 	setPathsCompareContext(ctx, base, head, headOwner, headName)
 	setImageCompareContext(ctx)
 	setCsvCompareContext(ctx)
+	setBinaryCompareContext(ctx)
 }
 
 // SourceCommitURL creates a relative URL for a commit in the given repository
@@ -175,6 +177,21 @@ func setCsvCompareContext(ctx *context.Context) {
 	}
 }
 
+// setBinaryCompareContext sets context data that is required by the binary compare template
+func setBinaryCompareContext(ctx *context.Context) {
+	ctx.Data["CreateBinaryDiff"] = func(diffFile *gitdiff.DiffFile, baseCommit, headCommit *git.Commit) *gitdiff.BinaryDiff {
+		if diffFile == nil {
+			return nil
+		}
+		binaryDiff, err := gitdiff.GetBinaryDiff(baseCommit, headCommit, diffFile)
+		if err != nil {
+			log.Error("GetBinaryDiff error for file %s in %s: %v", diffFile.Name, ctx.Repo.Repository.Name, err)
+			return nil
+		}
+		return binaryDiff
+	}
+}
+
 // CompareInfo represents the collected results from ParseCompareInfo
 type CompareInfo struct {
 	HeadUser         *user_model.User
@@ -784,12 +801,23 @@ func CompareDiff(ctx *context.Context) {
 	ctx.Data["IsRepoToolbarCommits"] = true
 	ctx.Data["IsDiffCompare"] = true
 	ctx.Data["RequireTribute"] = true
-	setTemplateIfExists(ctx, pullRequestTemplateKey, nil, pullRequestTemplateCandidates)
+	ctx.Data["PullRequestTemplates"] = ctx.PullRequestTemplatesFromDefaultBranch()
+	ctx.Data["SelectedPullRequestTemplate"] = ctx.FormString("template")
+	ctx.Data["ExpandNewPrForm"] = ctx.FormString("template") != ""
+	setTemplateIfExists(ctx, pullRequestTemplateKey, context.PullRequestTemplateDirCandidates, pullRequestTemplateCandidates)
 
 	// If a template content is set, prepend the "content". In this case that's only
 	// applicable if you have one commit to compare and that commit has a message.
 	// In that case the commit message will be prepend to the template body.
 	if templateContent, ok := ctx.Data[pullRequestTemplateKey].(string); ok && templateContent != "" {
+		vars, err := pull_service.TemplateVars(ctx, ctx.Repo.Repository, ci.HeadRepo, ci.BaseBranch, ci.HeadBranch, ci.CompareInfo.Commits)
+		if err != nil {
+			ctx.ServerError("TemplateVars", err)
+			return
+		}
+		templateContent = pull_service.ExpandTemplate(templateContent, vars)
+		ctx.Data[pullRequestTemplateKey] = templateContent
+
 		if content, ok := ctx.Data["content"].(string); ok && content != "" {
 			// Re-use the same key as that's priortized over the "content" key.
 			// Add two new lines between the content to ensure there's always at least
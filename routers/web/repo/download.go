@@ -6,17 +6,28 @@
 package repo
 
 import (
+	goctx "context"
+	"fmt"
+	"net/http"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/httpcache"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/common"
 )
 
@@ -168,3 +179,223 @@ func DownloadByIDOrLFS(ctx *context.Context) {
 		ctx.ServerError("ServeBlob", err)
 	}
 }
+
+// ImmutableRawAssignment assigns the repository of an immutable raw link.
+// Requests with a valid signature get read access to the code of the repository without authentication.
+func ImmutableRawAssignment(ctx *context.Context) goctx.CancelFunc {
+	signature := ctx.FormString("signature")
+	if signature == "" {
+		return context.RepoAssignment(ctx)
+	}
+
+	repo, err := repo_model.GetRepositoryByOwnerAndNameCtx(ctx, ctx.Params("username"), ctx.Params("reponame"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByOwnerAndName", nil)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return nil
+	}
+
+	if !repo_module.VerifyRawBlobSignature(repo.ID, ctx.Params("sha"), ctx.FormString("expires"), signature) {
+		ctx.NotFound("VerifyRawBlobSignature", nil)
+		return nil
+	}
+
+	codeUnit, err := repo.GetUnit(unit_model.TypeCode)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			ctx.NotFound("GetUnit", nil)
+		} else {
+			ctx.ServerError("GetUnit", err)
+		}
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		ctx.ServerError("OpenRepository", err)
+		return nil
+	}
+
+	ctx.Repo.Repository = repo
+	ctx.Repo.GitRepo = gitRepo
+	ctx.Repo.Permission = access_model.Permission{
+		AccessMode: perm.AccessModeRead,
+		Units:      []*repo_model.RepoUnit{codeUnit},
+	}
+	ctx.Data["IsSignedRawAccess"] = true
+	// the signature was verified, so the expiry is a valid timestamp
+	ctx.Data["SignedRawAccessExpires"], _ = strconv.ParseInt(ctx.FormString("expires"), 10, 64)
+
+	return func() {
+		gitRepo.Close()
+	}
+}
+
+// DownloadImmutableBlob serves a blob by its full sha. The content of the response never changes and may be cached forever.
+func DownloadImmutableBlob(ctx *context.Context) {
+	sha := ctx.Params("sha")
+	if len(sha) != 40 || !git.SHAPattern.MatchString(sha) {
+		ctx.NotFound("DownloadImmutableBlob", nil)
+		return
+	}
+
+	blob, err := ctx.Repo.GitRepo.GetBlob(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetBlob", nil)
+		} else {
+			ctx.ServerError("GetBlob", err)
+		}
+		return
+	}
+
+	// the path is only used for the file name and the content type of the response
+	ctx.Repo.TreePath = ctx.Params("*")
+
+	// blobs of private repositories must not be stored in shared caches
+	visibility := "public"
+	maxAge := int64(setting.Repository.Raw.ImmutableMaxAge.Seconds())
+	if ctx.Repo.Repository.IsPrivate {
+		visibility = "private"
+		// a signed link must not be served from the cache after the signature expired
+		if expires, ok := ctx.Data["SignedRawAccessExpires"].(int64); ok {
+			if remaining := expires - time.Now().Unix(); remaining < maxAge {
+				maxAge = remaining
+			}
+			if maxAge < 0 {
+				maxAge = 0
+			}
+		}
+	}
+	cacheControl := fmt.Sprintf("%s, max-age=%d, immutable", visibility, maxAge)
+	ctx.Resp.Before(func(resp context.ResponseWriter) {
+		resp.Header().Set("Cache-Control", cacheControl)
+	})
+
+	if err = common.ServeBlob(ctx, blob, time.Time{}); err != nil {
+		ctx.ServerError("ServeBlob", err)
+	}
+}
+
+// SetRawHeaders applies the configured CORS, cache control and custom headers to raw and media responses
+func SetRawHeaders(ctx *context.Context) {
+	allowDomains := setting.Repository.Raw.CORSAllowDomain
+	cacheControl := setting.Repository.Raw.CacheControl
+
+	if setting.Repository.Raw.AllowRepoOverride {
+		if u, err := ctx.Repo.Repository.GetUnit(unit_model.TypeCode); err == nil {
+			cfg := u.CodeConfig()
+			if len(cfg.RawCORSAllowDomain) > 0 {
+				// a repository may only narrow the origins allowed by the instance
+				allowDomains = narrowRawAllowDomains(allowDomains, cfg.RawCORSAllowDomain)
+			}
+			if cfg.RawCacheControl != "" {
+				cacheControl = cfg.RawCacheControl
+			}
+		}
+	}
+
+	repo := ctx.Repo.Repository
+	if err := repo.GetOwner(ctx); err != nil {
+		ctx.ServerError("GetOwner", err)
+		return
+	}
+	if cacheControl != "" && (repo.IsPrivate || repo.Owner.Visibility != structs.VisibleTypePublic) {
+		// content of non-public repositories must never end up in shared caches
+		cacheControl = privateCacheControl(cacheControl)
+	}
+
+	header := ctx.Resp.Header()
+	for name, value := range setting.Repository.Raw.Headers {
+		header.Set(name, value)
+	}
+
+	if cacheControl != "" {
+		// the serve functions set their own Cache-Control header, so it has to be replaced right before it is sent
+		ctx.Resp.Before(func(resp context.ResponseWriter) {
+			resp.Header().Set("Cache-Control", cacheControl)
+		})
+	}
+
+	origin := ctx.Req.Header.Get("Origin")
+	if origin == "" || len(allowDomains) == 0 {
+		return
+	}
+
+	header.Add("Vary", "Origin")
+
+	allowedOrigin := matchRawAllowedOrigin(allowDomains, origin)
+	if allowedOrigin == "" {
+		return
+	}
+
+	header.Set("Access-Control-Allow-Origin", allowedOrigin)
+	// browsers reject the wildcard for credentialed requests, so credentials are only allowed for listed origins
+	if setting.Repository.Raw.CORSAllowCredentials && allowedOrigin != "*" {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	header.Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Content-Disposition, ETag, Last-Modified")
+
+	if ctx.Req.Method == http.MethodOptions {
+		header.Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		header.Set("Access-Control-Allow-Headers", "Authorization, If-Modified-Since, If-None-Match, Range")
+		if setting.Repository.Raw.CORSMaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(setting.Repository.Raw.CORSMaxAge.Seconds())))
+		}
+	}
+}
+
+// RawPreflight answers CORS preflight requests for raw and media endpoints
+func RawPreflight(ctx *context.Context) {
+	if ctx.Resp.Header().Get("Access-Control-Allow-Origin") == "" {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// matchRawAllowedOrigin returns the value of the Access-Control-Allow-Origin header for the origin or an empty string if the origin is not allowed
+func matchRawAllowedOrigin(allowDomains []string, origin string) string {
+	for _, domain := range allowDomains {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), "/")
+		if domain == "*" {
+			return "*"
+		}
+		if strings.EqualFold(domain, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// narrowRawAllowDomains returns the origins of the repository override which are also allowed by the instance
+func narrowRawAllowDomains(instanceDomains, repoDomains []string) []string {
+	domains := make([]string, 0, len(repoDomains))
+	for _, domain := range repoDomains {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), "/")
+		if domain == "" || domain == "*" {
+			continue
+		}
+		if matchRawAllowedOrigin(instanceDomains, domain) != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// privateCacheControl replaces the public directives of the Cache-Control value with private
+func privateCacheControl(cacheControl string) string {
+	directives := []string{"private"}
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		name := strings.ToLower(directive)
+		if name == "" || name == "public" || name == "private" || strings.HasPrefix(name, "s-maxage") {
+			continue
+		}
+		directives = append(directives, directive)
+	}
+	return strings.Join(directives, ", ")
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRawAllowedOrigin(t *testing.T) {
+	cases := []struct {
+		AllowDomains []string
+		Origin       string
+		Expected     string
+	}{
+		{[]string{"*"}, "https://example.com", "*"},
+		{[]string{"https://example.com/"}, "https://example.com", "https://example.com"},
+		{[]string{"https://Example.com"}, "https://example.com", "https://example.com"},
+		{[]string{"https://example.com"}, "https://example.org", ""},
+		{[]string{"https://example.com", " https://example.org"}, "https://example.org", "https://example.org"},
+		{[]string{}, "https://example.com", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Expected, matchRawAllowedOrigin(c.AllowDomains, c.Origin), "%v %s", c.AllowDomains, c.Origin)
+	}
+}
+
+func TestNarrowRawAllowDomains(t *testing.T) {
+	cases := []struct {
+		InstanceDomains []string
+		RepoDomains     []string
+		Expected        []string
+	}{
+		{[]string{"*"}, []string{"https://example.com"}, []string{"https://example.com"}},
+		{[]string{"*"}, []string{"*"}, []string{}},
+		{[]string{"https://example.com"}, []string{"*", "https://example.com/"}, []string{"https://example.com"}},
+		{[]string{"https://example.com"}, []string{"https://example.org"}, []string{}},
+		{[]string{}, []string{"https://example.com"}, []string{}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Expected, narrowRawAllowDomains(c.InstanceDomains, c.RepoDomains), "%v %v", c.InstanceDomains, c.RepoDomains)
+	}
+}
+
+func TestPrivateCacheControl(t *testing.T) {
+	cases := map[string]string{
+		"public, max-age=300":         "private, max-age=300",
+		"max-age=300, s-maxage=600":   "private, max-age=300",
+		"private, no-cache":           "private, no-cache",
+		"Public,max-age=60,immutable": "private, max-age=60, immutable",
+		"no-store":                    "private, no-store",
+	}
+
+	for value, expected := range cases {
+		assert.Equal(t, expected, privateCacheControl(value), value)
+	}
+}
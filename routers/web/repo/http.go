@@ -10,6 +10,7 @@ import (
 	"compress/gzip"
 	gocontext "context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path"
@@ -27,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -287,7 +289,7 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 		dir = repo_model.RepoPath(username, wikiRepoName)
 	}
 
-	return &serviceHandler{cfg, w, r, dir, cfg.Env}
+	return &serviceHandler{cfg, w, r, dir, cfg.Env, repo.ID, 0}
 }
 
 var (
@@ -344,6 +346,9 @@ type serviceHandler struct {
 	r       *http.Request
 	dir     string
 	environ []string
+
+	repoID         int64
+	bandwidthLimit int64
 }
 
 func (h *serviceHandler) setHeaderNoCache() {
@@ -476,7 +481,7 @@ func serviceRPC(ctx gocontext.Context, h serviceHandler, service string) {
 	if err := cmd.Run(&git.RunOpts{
 		Dir:               h.dir,
 		Env:               append(os.Environ(), h.environ...),
-		Stdout:            h.w,
+		Stdout:            ratelimit.NewThrottledWriter(h.r.Context(), h.w, h.bandwidthLimit),
 		Stdin:             reqBody,
 		Stderr:            &stderr,
 		UseContextTimeout: true,
@@ -488,10 +493,65 @@ func serviceRPC(ctx gocontext.Context, h serviceHandler, service string) {
 	}
 }
 
+var (
+	uploadPackRepoLimiter *ratelimit.ConcurrencyLimiter
+	uploadPackUserLimiter *ratelimit.ConcurrencyLimiter
+	uploadPackLimiterOnce sync.Once
+)
+
+// acquireUploadPack checks the concurrency limits of upload-pack operations for the repository and the user.
+// If a limit is reached, the response gets written and false is returned. Otherwise the returned function must
+// be called when the operation has finished.
+func acquireUploadPack(ctx *context.Context, h *serviceHandler) (func(), bool) {
+	uploadPackLimiterOnce.Do(func() {
+		uploadPackRepoLimiter = ratelimit.NewConcurrencyLimiter(setting.Git.HTTP.MaxConcurrentUploadPackPerRepo)
+		uploadPackUserLimiter = ratelimit.NewConcurrencyLimiter(setting.Git.HTTP.MaxConcurrentUploadPackPerUser)
+	})
+
+	repoKey := strconv.FormatInt(h.repoID, 10)
+
+	var userKey string
+	if ctx.Doer != nil {
+		userKey = "user:" + strconv.FormatInt(ctx.Doer.ID, 10)
+	} else {
+		host, _, err := net.SplitHostPort(ctx.RemoteAddr())
+		if err != nil {
+			host = ctx.RemoteAddr()
+		}
+		userKey = "ip:" + host
+	}
+
+	if !uploadPackRepoLimiter.Acquire(repoKey) {
+		ctx.Resp.Header().Set("Retry-After", "60")
+		ctx.PlainText(http.StatusTooManyRequests, "Too many concurrent clones of this repository, please try again later")
+		return nil, false
+	}
+	if !uploadPackUserLimiter.Acquire(userKey) {
+		uploadPackRepoLimiter.Release(repoKey)
+		ctx.Resp.Header().Set("Retry-After", "60")
+		ctx.PlainText(http.StatusTooManyRequests, "Too many concurrent clones, please try again later")
+		return nil, false
+	}
+
+	return func() {
+		uploadPackUserLimiter.Release(userKey)
+		uploadPackRepoLimiter.Release(repoKey)
+	}, true
+}
+
 // ServiceUploadPack implements Git Smart HTTP protocol
 func ServiceUploadPack(ctx *context.Context) {
 	h := httpBase(ctx)
 	if h != nil {
+		release, ok := acquireUploadPack(ctx, h)
+		if !ok {
+			return
+		}
+		defer release()
+
+		if ctx.Doer == nil {
+			h.bandwidthLimit = setting.Git.HTTP.AnonymousBandwidthLimit
+		}
 		serviceRPC(ctx, *h, "upload-pack")
 	}
 }
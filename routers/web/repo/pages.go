@@ -0,0 +1,110 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+	pages_service "code.gitea.io/gitea/services/pages"
+)
+
+// MustEnablePages checks if the pages of the repository can be read
+func MustEnablePages(ctx *context.Context) {
+	if !setting.Pages.Enabled || !ctx.Repo.CanRead(unit.TypePages) {
+		ctx.NotFound("MustEnablePages", nil)
+		return
+	}
+}
+
+// Pages serves the deployed pages of the repository below the repository URL
+func Pages(ctx *context.Context) {
+	// the pages share the origin with Gitea so they must not be able to access it
+	ServePage(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.Params("*"), true)
+}
+
+// ServePage serves the file of the deployed pages at the path or the 404.html page of the pages.
+// Sandboxed pages are served with a content security policy which isolates them from the origin.
+func ServePage(ctx *context.Context, repo *repo_model.Repository, gitRepo *git.Repository, p string, sandbox bool) {
+	status := http.StatusOK
+	blob, name, err := pages_service.GetPage(ctx, repo, gitRepo, p)
+	if err == pages_service.ErrPageNotExist {
+		status = http.StatusNotFound
+		blob, name, err = pages_service.GetPage(ctx, repo, gitRepo, "404.html")
+	}
+	if err != nil {
+		if err == pages_service.ErrPageNotExist {
+			ctx.NotFound("GetPage", nil)
+		} else {
+			ctx.ServerError("GetPage", err)
+		}
+		return
+	}
+
+	// relative links of a directory index must resolve inside the directory
+	if status == http.StatusOK && path.Base(name) == "index.html" && path.Base(name) != path.Base(p) && !strings.HasSuffix(ctx.Req.URL.Path, "/") {
+		ctx.Redirect(ctx.Req.URL.EscapedPath()+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	if status == http.StatusOK && httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, `"`+blob.ID.String()+`"`) {
+		return
+	}
+	// a new deployment must be visible immediately
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		ctx.ServerError("DataAsync", err)
+		return
+	}
+	defer dataRc.Close()
+
+	buf := make([]byte, 1024)
+	n, err := util.ReadAtMost(dataRc, buf)
+	if err != nil {
+		ctx.ServerError("ReadAtMost", err)
+		return
+	}
+	buf = buf[:n]
+
+	ext := strings.ToLower(path.Ext(name))
+	contentType := ""
+	if setting.MimeTypeMap.Enabled {
+		contentType = setting.MimeTypeMap.Map[ext]
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(ext)
+	}
+	if contentType == "" {
+		contentType = typesniffer.DetectContentType(buf).GetMimeType()
+	}
+
+	ctx.Resp.Header().Set("Content-Type", contentType)
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(blob.Size(), 10))
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+	if sandbox {
+		ctx.Resp.Header().Set("Content-Security-Policy", "sandbox allow-scripts allow-forms allow-popups")
+	}
+	ctx.Resp.WriteHeader(status)
+
+	if _, err := io.Copy(ctx.Resp, io.MultiReader(bytes.NewReader(buf), dataRc)); err != nil {
+		log.Error("Error whilst copying page %s of %-v to response: %v", name, repo, err)
+	}
+}
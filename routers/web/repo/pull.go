@@ -933,6 +933,9 @@ func MergePullRequest(ctx *context.Context) {
 		} else if errors.Is(err, pull_service.ErrDependenciesLeft) {
 			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.pr_close_blocked"))
 			ctx.Redirect(issue.Link())
+		} else if errors.Is(err, pull_service.ErrChecklistIncomplete) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.blocked_by_merge_checklist"))
+			ctx.Redirect(issue.Link())
 		} else {
 			ctx.ServerError("WebCheck", err)
 		}
@@ -1113,6 +1116,30 @@ func CancelAutoMergePullRequest(ctx *context.Context) {
 	ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index))
 }
 
+// UpdateMergeChecklistItem checks or unchecks an item of the merge checklist on a pull request
+func UpdateMergeChecklistItem(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var err error
+	if ctx.FormBool("checked") {
+		err = issues_model.CheckMergeChecklistItem(ctx, issue.PullRequest, ctx.ParamsInt64(":id"), ctx.Doer.ID)
+	} else {
+		err = issues_model.UncheckMergeChecklistItem(ctx, issue.PullRequest, ctx.ParamsInt64(":id"))
+	}
+	if err != nil {
+		if issues_model.IsErrMergeChecklistItemNotExist(err) {
+			ctx.NotFound("MergeChecklistItemNotExist", err)
+		} else {
+			ctx.ServerError("UpdateMergeChecklistItem", err)
+		}
+		return
+	}
+	ctx.Redirect(issue.Link())
+}
+
 func stopTimerIfAvailable(user *user_model.User, issue *issues_model.Issue) error {
 	if issues_model.StopwatchExists(user.ID, issue.ID) {
 		if err := issues_model.CreateOrStopIssueStopwatch(user, issue); err != nil {
@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
@@ -28,6 +29,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/forms"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
@@ -190,6 +192,21 @@ func handleCreateError(ctx *context.Context, owner *user_model.User, err error,
 	case db.IsErrNamePatternNotAllowed(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(db.ErrNamePatternNotAllowed).Pattern), tpl, form)
+	case organization.IsErrRepoPolicyViolation(err):
+		violation := err.(organization.ErrRepoPolicyViolation)
+		switch violation.Rule {
+		case organization.RepoPolicyRuleNamePattern:
+			ctx.Data["Err_RepoName"] = true
+			if violation.Description != "" {
+				ctx.RenderWithErr(ctx.Tr("repo.form.policy_name_convention", violation.Description), tpl, form)
+			} else {
+				ctx.RenderWithErr(ctx.Tr("repo.form.policy_name_pattern", violation.Value), tpl, form)
+			}
+		case organization.RepoPolicyRuleTopic:
+			ctx.RenderWithErr(ctx.Tr("repo.form.policy_topic_required"), tpl, form)
+		default:
+			ctx.RenderWithErr(ctx.Tr("repo.form.policy_property_required", violation.Value), tpl, form)
+		}
 	default:
 		ctx.ServerError(name, err)
 	}
@@ -334,12 +351,16 @@ func acceptOrRejectRepoTransfer(ctx *context.Context, accept bool) error {
 			ctx.Repo.GitRepo = nil
 		}
 
-		if err := repo_service.TransferOwnership(repoTransfer.Doer, repoTransfer.Recipient, ctx.Repo.Repository, repoTransfer.Teams); err != nil {
+		if err := repo_service.AcceptTransferOwnership(ctx.Doer, ctx.Repo.Repository, repoTransfer); err != nil {
 			return err
 		}
-		ctx.Flash.Success(ctx.Tr("repo.settings.transfer.success"))
+		if ctx.Repo.Repository.Status == repo_model.RepositoryPendingTransfer {
+			ctx.Flash.Success(ctx.Tr("repo.settings.transfer.scheduled", repoTransfer.ScheduledUnix.FormatLong()))
+		} else {
+			ctx.Flash.Success(ctx.Tr("repo.settings.transfer.success"))
+		}
 	} else {
-		if err := models.CancelRepositoryTransfer(ctx.Repo.Repository); err != nil {
+		if err := repo_service.RejectTransferOwnership(ctx.Doer, ctx.Repo.Repository); err != nil {
 			return err
 		}
 		ctx.Flash.Success(ctx.Tr("repo.settings.transfer.rejected"))
@@ -383,28 +404,63 @@ func RedirectDownload(ctx *context.Context) {
 
 // Download an archive of a repository
 func Download(ctx *context.Context) {
-	uri := ctx.Params("*")
-	aReq, err := archiver_service.NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri)
+	uri, suffix := archiver_service.SplitArchiveSuffix(ctx.Params("*"))
+	aReq, err := archiver_service.NewRequestWithFormat(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri, ctx.FormTrim("format"))
 	if err != nil {
 		if errors.Is(err, archiver_service.ErrUnknownArchiveFormat{}) {
 			ctx.Error(http.StatusBadRequest, err.Error())
-		} else if errors.Is(err, archiver_service.RepoRefNotFoundError{}) {
+		} else if errors.Is(err, archiver_service.RepoRefNotFoundError{}) || git.IsErrNotExist(err) {
 			ctx.Error(http.StatusNotFound, err.Error())
 		} else {
-			ctx.ServerError("archiver_service.NewRequest", err)
+			ctx.ServerError("archiver_service.NewRequestWithFormat", err)
 		}
 		return
 	}
 
+	if common.RedirectToCanonicalArchive(ctx, ctx.Repo.RepoLink+"/archive", aReq, suffix) {
+		return
+	}
+
 	archiver, err := aReq.Await(ctx)
 	if err != nil {
 		ctx.ServerError("archiver.Await", err)
 		return
 	}
 
+	if suffix != "" {
+		downloadArchiveSuffix(ctx, aReq.GetArchiveName(), archiver, suffix)
+		return
+	}
+
 	download(ctx, aReq.GetArchiveName(), archiver)
 }
 
+// downloadArchiveSuffix serves the checksum or signature of an archive
+func downloadArchiveSuffix(ctx *context.Context, archiveName string, archiver *repo_model.RepoArchiver, suffix string) {
+	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
+
+	if suffix == archiver_service.SuffixSignature {
+		signature, err := archiver_service.GetArchiveSignature(ctx, ctx.Repo.Repository, archiver)
+		if err != nil {
+			if errors.Is(err, archiver_service.ErrArchiveSignatureUnavailable) {
+				ctx.NotFound("GetArchiveSignature", err)
+			} else {
+				ctx.ServerError("GetArchiveSignature", err)
+			}
+			return
+		}
+		ctx.ServeContent(downloadName+suffix, strings.NewReader(signature), archiver.CreatedUnix.AsLocalTime())
+		return
+	}
+
+	checksum, err := archiver_service.GetArchiveChecksum(archiver, suffix, downloadName)
+	if err != nil {
+		ctx.ServerError("GetArchiveChecksum", err)
+		return
+	}
+	ctx.PlainText(http.StatusOK, checksum)
+}
+
 func download(ctx *context.Context, archiveName string, archiver *repo_model.RepoArchiver) {
 	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
 
@@ -444,25 +500,21 @@ func InitiateDownload(ctx *context.Context) {
 		return
 	}
 
-	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	// polling requests must not retry a failed archive again and again
+	var status archiver_service.ArchiveStatus
+	if ctx.FormBool("poll") {
+		status, _, err = aReq.Status(ctx)
+	} else {
+		status, _, err = aReq.Enqueue(ctx)
+	}
 	if err != nil {
-		ctx.ServerError("archiver_service.StartArchive", err)
+		ctx.ServerError("archiver_service.Enqueue", err)
 		return
 	}
-	if archiver == nil || archiver.Status != repo_model.ArchiverReady {
-		if err := archiver_service.StartArchive(aReq); err != nil {
-			ctx.ServerError("archiver_service.StartArchive", err)
-			return
-		}
-	}
-
-	var completed bool
-	if archiver != nil && archiver.Status == repo_model.ArchiverReady {
-		completed = true
-	}
 
 	ctx.JSON(http.StatusOK, map[string]interface{}{
-		"complete": completed,
+		"complete": status == archiver_service.ArchiveStatusReady,
+		"status":   status,
 	})
 }
 
@@ -30,9 +30,11 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	mirror_module "code.gitea.io/gitea/modules/mirror"
+	"code.gitea.io/gitea/modules/references"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
@@ -44,6 +46,8 @@ import (
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	org_service "code.gitea.io/gitea/services/org"
+	pages_service "code.gitea.io/gitea/services/pages"
+	release_service "code.gitea.io/gitea/services/release"
 	repo_service "code.gitea.io/gitea/services/repository"
 	wiki_service "code.gitea.io/gitea/services/wiki"
 )
@@ -74,6 +78,15 @@ func SettingsCtxData(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 	ctx.Data["CodeIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["RawOverrideEnabled"] = setting.Repository.Raw.AllowRepoOverride
+	ctx.Data["ReleasePackageTypes"] = release_service.PackageTypes
+	ctx.Data["ReleasePackageRules"] = release_service.FormatPackageRules(ctx.Repo.Repository.MustGetUnit(unit_model.TypeReleases).ReleasesConfig().PackageRules)
+	if issuesUnit, err := ctx.Repo.Repository.GetUnit(unit_model.TypeIssues); err == nil {
+		ctx.Data["IssueCloseKeywords"] = strings.Join(issuesUnit.IssuesConfig().CloseKeywords, ", ")
+		ctx.Data["IssueReopenKeywords"] = strings.Join(issuesUnit.IssuesConfig().ReopenKeywords, ", ")
+	}
+	ctx.Data["DefaultIssueCloseKeywords"] = strings.Join(setting.Repository.PullRequest.CloseKeywords, ", ")
+	ctx.Data["DefaultIssueReopenKeywords"] = strings.Join(setting.Repository.PullRequest.ReopenKeywords, ", ")
 
 	if ctx.Doer.IsAdmin {
 		if setting.Indexer.RepoIndexerEnabled {
@@ -97,6 +110,19 @@ func SettingsCtxData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["PushMirrors"] = pushMirrors
+
+	if setting.Pages.Enabled {
+		if pagesUnit, err := ctx.Repo.Repository.GetUnit(unit_model.TypePages); err == nil {
+			ctx.Data["PagesConfig"] = pagesUnit.PagesConfig()
+			ctx.Data["PagesSiteURL"] = pages_service.SiteURL(ctx.Repo.Repository)
+			deployment, err := repo_model.GetPagesDeployment(ctx, ctx.Repo.Repository.ID)
+			if err != nil {
+				ctx.ServerError("GetPagesDeployment", err)
+				return
+			}
+			ctx.Data["PagesDeployment"] = deployment
+		}
+	}
 }
 
 // Settings show a repository's settings page
@@ -118,6 +144,7 @@ func SettingsPost(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 	ctx.Data["CodeIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["RawOverrideEnabled"] = setting.Repository.Raw.AllowRepoOverride
 
 	repo := ctx.Repo.Repository
 
@@ -452,6 +479,16 @@ func SettingsPost(ctx *context.Context) {
 			})
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeIssues)
 		} else if form.EnableIssues && !form.EnableExternalTracker && !unit_model.TypeIssues.UnitGlobalDisabled() {
+			closeKeywords, reopenKeywords := splitKeywords(form.IssueCloseKeywords), splitKeywords(form.IssueReopenKeywords)
+			for _, keywords := range [][]string{closeKeywords, reopenKeywords} {
+				for _, word := range keywords {
+					if !references.IsValidKeyword(word) {
+						ctx.Flash.Error(ctx.Tr("repo.settings.issue_keywords_error", word))
+						ctx.Redirect(repo.Link() + "/settings")
+						return
+					}
+				}
+			}
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
 				Type:   unit_model.TypeIssues,
@@ -459,6 +496,8 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					CloseKeywords:                    closeKeywords,
+					ReopenKeywords:                   reopenKeywords,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeExternalTracker)
@@ -489,6 +528,19 @@ func SettingsPost(ctx *context.Context) {
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypePackages)
 		}
 
+		if form.EnablePages && !unit_model.TypePages.UnitGlobalDisabled() {
+			units = append(units, repo_model.RepoUnit{
+				RepoID: repo.ID,
+				Type:   unit_model.TypePages,
+				Config: &repo_model.PagesConfig{
+					Branch:    strings.TrimSpace(form.PagesBranch),
+					Directory: strings.Trim(strings.TrimSpace(form.PagesDirectory), "/"),
+				},
+			})
+		} else if !unit_model.TypePages.UnitGlobalDisabled() {
+			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypePages)
+		}
+
 		if form.EnablePulls && !unit_model.TypePullRequests.UnitGlobalDisabled() {
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
@@ -514,6 +566,14 @@ func SettingsPost(ctx *context.Context) {
 			ctx.ServerError("UpdateRepositoryUnits", err)
 			return
 		}
+		if form.EnablePages && !unit_model.TypePages.UnitGlobalDisabled() {
+			if err := pages_service.TriggerDeploy(ctx, repo, ""); err != nil {
+				log.Error("TriggerDeploy: %v", err)
+			}
+		} else if err := repo_model.DeletePagesDeployment(ctx, repo.ID); err != nil {
+			ctx.ServerError("DeletePagesDeployment", err)
+			return
+		}
 		if repoChanged {
 			if err := repo_service.UpdateRepository(repo, false); err != nil {
 				ctx.ServerError("UpdateRepository", err)
@@ -525,6 +585,72 @@ func SettingsPost(ctx *context.Context) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
 
+	case "raw":
+		if !setting.Repository.Raw.AllowRepoOverride {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		u, err := repo.GetUnit(unit_model.TypeCode)
+		if err != nil {
+			ctx.ServerError("GetUnit", err)
+			return
+		}
+
+		cfg := u.CodeConfig()
+		cfg.RawCORSAllowDomain = make([]string, 0, 2)
+		for _, domain := range strings.Split(form.RawCORSAllowDomain, ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				cfg.RawCORSAllowDomain = append(cfg.RawCORSAllowDomain, domain)
+			}
+		}
+		cfg.RawCacheControl = strings.TrimSpace(form.RawCacheControl)
+		u.Config = cfg
+
+		if err := repo_model.UpdateRepoUnit(u); err != nil {
+			ctx.ServerError("UpdateRepoUnit", err)
+			return
+		}
+		log.Trace("Repository raw settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
+	case "release_packages":
+		u, err := repo.GetUnit(unit_model.TypeReleases)
+		if err != nil {
+			if repo_model.IsErrUnitTypeNotExist(err) {
+				ctx.NotFound("", nil)
+				return
+			}
+			ctx.ServerError("GetUnit", err)
+			return
+		}
+
+		rules, err := release_service.ParsePackageRules(form.ReleasePackageRules)
+		if err != nil {
+			if release_service.IsErrInvalidPackageRule(err) {
+				ctx.Flash.Error(ctx.Tr("repo.settings.release_package_rules_invalid", err.(release_service.ErrInvalidPackageRule).Rule))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
+			ctx.ServerError("ParsePackageRules", err)
+			return
+		}
+
+		cfg := u.ReleasesConfig()
+		cfg.PackageRules = rules
+		u.Config = cfg
+
+		if err := repo_model.UpdateRepoUnit(u); err != nil {
+			ctx.ServerError("UpdateRepoUnit", err)
+			return
+		}
+		log.Trace("Repository release package settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	case "signing":
 		changed := false
 		trustModel := repo_model.ToTrustModel(form.TrustModel)
@@ -729,8 +855,8 @@ func SettingsPost(ctx *context.Context) {
 			return
 		}
 
-		if err := models.CancelRepositoryTransfer(ctx.Repo.Repository); err != nil {
-			ctx.ServerError("CancelRepositoryTransfer", err)
+		if err := repo_service.CancelTransferOwnership(ctx.Doer, ctx.Repo.Repository); err != nil {
+			ctx.ServerError("CancelTransferOwnership", err)
 			return
 		}
 
@@ -828,6 +954,17 @@ func SettingsPost(ctx *context.Context) {
 	}
 }
 
+// splitKeywords splits a comma separated list of keywords
+func splitKeywords(s string) []string {
+	keywords := make([]string, 0, 5)
+	for _, word := range strings.Split(s, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
 func handleSettingRemoteAddrError(ctx *context.Context, err error, form *forms.RepoSettingForm) {
 	if models.IsErrInvalidCloneAddr(err) {
 		addrErr := err.(*models.ErrInvalidCloneAddr)
@@ -917,11 +1054,31 @@ func CollaborationPost(ctx *context.Context) {
 		return
 	}
 
+	var expires timeutil.TimeStamp
+	if date := ctx.FormTrim("expires"); date != "" {
+		t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		// the access ends with the given day
+		t = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+		if err != nil || !t.After(time.Now()) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.collaboration.invalid_expiry"))
+			ctx.Redirect(setting.AppSubURL + ctx.Req.URL.EscapedPath())
+			return
+		}
+		expires = timeutil.TimeStamp(t.Unix())
+	}
+
 	if err = repo_module.AddCollaborator(ctx.Repo.Repository, u); err != nil {
 		ctx.ServerError("AddCollaborator", err)
 		return
 	}
 
+	if expires != 0 {
+		if err := repo_model.SetCollaborationExpiry(ctx, ctx.Repo.Repository.ID, u.ID, expires); err != nil {
+			ctx.ServerError("SetCollaborationExpiry", err)
+			return
+		}
+	}
+
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.Doer, ctx.Repo.Repository)
 	}
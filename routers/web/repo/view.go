@@ -9,6 +9,7 @@ import (
 	"bytes"
 	gocontext "context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	gotemplate "html/template"
 	"io"
@@ -34,6 +35,7 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/outline"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -357,6 +359,20 @@ func renderReadmeFile(ctx *context.Context, readmeFile *namedBlob, readmeTreelin
 	}
 }
 
+// outlineEntry is a symbol of the outline of a file with its nesting depth
+type outlineEntry struct {
+	*outline.Symbol
+	Depth int
+}
+
+func flattenOutline(symbols []*outline.Symbol, depth int, entries []*outlineEntry) []*outlineEntry {
+	for _, s := range symbols {
+		entries = append(entries, &outlineEntry{Symbol: s, Depth: depth})
+		entries = flattenOutline(s.Children, depth+1, entries)
+	}
+	return entries
+}
+
 func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink string) {
 	ctx.Data["IsViewFile"] = true
 	blob := entry.Blob()
@@ -373,6 +389,7 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 	ctx.Data["FileIsSymlink"] = entry.IsLink()
 	ctx.Data["FileName"] = blob.Name()
 	ctx.Data["RawFileLink"] = rawLink + "/" + util.PathEscapeSegments(ctx.Repo.TreePath)
+	ctx.Data["ImmutableRawFileLink"] = repo_module.ImmutableRawLink(ctx.Repo.Repository, blob.ID.String(), ctx.Repo.TreePath)
 
 	buf := make([]byte, 1024)
 	n, _ := util.ReadAtMost(dataRc, buf)
@@ -419,6 +436,8 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 
 				fileSize = meta.Size
 				ctx.Data["RawFileLink"] = ctx.Repo.RepoLink + "/media/" + ctx.Repo.BranchNameSubURL() + "/" + util.PathEscapeSegments(ctx.Repo.TreePath)
+				// the immutable raw link would serve the LFS pointer instead of the content
+				ctx.Data["ImmutableRawFileLink"] = ""
 			}
 		}
 	}
@@ -574,6 +593,15 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 			ctx.Data["EscapeStatus"] = status
 			ctx.Data["FileContent"] = fileContent
 			ctx.Data["LineEscapeStatus"] = statuses
+
+			if setting.Repository.Outline.Enabled && !isLFSFile {
+				o, err := outline.GetBlobOutline(ctx, blob, ctx.Repo.TreePath)
+				if err == nil {
+					ctx.Data["FileOutline"] = flattenOutline(o.Symbols, 0, nil)
+				} else if !errors.Is(err, outline.ErrFileTooLarge) {
+					log.Error("Unable to generate the outline of %-v:%s. Error: %v", ctx.Repo.Repository, ctx.Repo.TreePath, err)
+				}
+			}
 		}
 		if !isLFSFile {
 			if ctx.Repo.CanEnableEditor(ctx.Doer) {
@@ -941,6 +969,10 @@ func renderCode(ctx *context.Context) {
 		return
 	}
 
+	if len(ctx.Repo.TreePath) == 0 {
+		ctx.Data["HealthFiles"] = ctx.HealthFilesFromDefaultBranch()
+	}
+
 	// Get current entry user currently looking at.
 	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 	if err != nil {
@@ -180,6 +180,8 @@ func ParseHookEvent(form forms.WebhookForm) *webhook.HookEvent {
 			PullRequestSync:      form.PullRequestSync,
 			Repository:           form.Repository,
 			Package:              form.Package,
+			Reaction:             form.Reaction,
+			Digest:               form.Digest,
 		},
 		BranchFilter: form.BranchFilter,
 	}
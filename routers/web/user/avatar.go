@@ -5,13 +5,17 @@
 package user
 
 import (
+	"net/http"
 	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/avatars"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 func cacheableRedirect(ctx *context.Context, location string) {
@@ -41,7 +45,7 @@ func AvatarByUserName(ctx *context.Context) {
 	cacheableRedirect(ctx, user.AvatarLinkWithSize(size))
 }
 
-// AvatarByEmailHash redirects the browser to the email avatar link
+// AvatarByEmailHash redirects the browser to the email avatar link or serves the external avatar if the avatar proxy is enabled
 func AvatarByEmailHash(ctx *context.Context) {
 	hash := ctx.Params(":hash")
 	email, err := avatars.GetEmailForHash(hash)
@@ -50,5 +54,24 @@ func AvatarByEmailHash(ctx *context.Context) {
 		return
 	}
 	size := ctx.FormInt("size")
-	cacheableRedirect(ctx, avatars.GenerateEmailAvatarFinalLink(email, size))
+	link := avatars.GenerateEmailAvatarFinalLink(email, size)
+	if !setting.Avatar.EnableProxy || link == avatars.DefaultAvatarLink() {
+		cacheableRedirect(ctx, link)
+		return
+	}
+
+	obj, err := avatar.OpenProxied(ctx, link)
+	if err != nil {
+		log.Warn("Unable to proxy the avatar %s: %v", link, err)
+		cacheableRedirect(ctx, avatars.DefaultAvatarLink())
+		return
+	}
+	defer obj.Close()
+	fi, err := obj.Stat()
+	if err != nil {
+		ctx.ServerError("Stat", err)
+		return
+	}
+	httpcache.AddCacheControlToHeader(ctx.Resp.Header(), setting.Avatar.ProxyCacheTTL)
+	http.ServeContent(ctx.Resp, ctx.Req, hash, fi.ModTime(), obj)
 }
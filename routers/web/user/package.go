@@ -6,6 +6,7 @@ package user
 
 import (
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	org_model "code.gitea.io/gitea/models/organization"
@@ -17,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
@@ -29,6 +31,10 @@ const (
 	tplPackagesView       base.TplName = "package/view"
 	tplPackageVersionList base.TplName = "user/overview/package_versions"
 	tplPackagesSettings   base.TplName = "package/settings"
+	tplPackagesCompare    base.TplName = "package/compare"
+
+	// downloadStatsDays is the number of days shown in the download statistics of the package page
+	downloadStatsDays = 30
 )
 
 // ListPackages displays a list of all packages of the context user
@@ -45,10 +51,11 @@ func ListPackages(ctx *context.Context) {
 			PageSize: setting.UI.PackagesPagingNum,
 			Page:     page,
 		},
-		OwnerID:    ctx.ContextUser.ID,
-		Type:       packages_model.Type(packageType),
-		Name:       packages_model.SearchValue{Value: query},
-		IsInternal: util.OptionalBoolFalse,
+		OwnerID:     ctx.ContextUser.ID,
+		Type:        packages_model.Type(packageType),
+		Name:        packages_model.SearchValue{Value: query},
+		IsInternal:  util.OptionalBoolFalse,
+		HidePrivate: !ctx.Package.CanSeePrivatePackages(),
 	})
 	if err != nil {
 		ctx.ServerError("SearchLatestVersions", err)
@@ -193,8 +200,45 @@ func ViewPackageVersion(ctx *context.Context) {
 	ctx.Data["LatestVersions"] = pvs
 	ctx.Data["TotalVersionCount"] = total
 
+	dependencies, err := packages_model.GetDependenciesByVersionID(ctx, pd.Version.ID)
+	if err != nil {
+		ctx.ServerError("GetDependenciesByVersionID", err)
+		return
+	}
+	ctx.Data["Dependencies"] = dependencies
+
+	dependents, totalDependents, err := packages_model.SearchDependents(ctx, ctx.Doer, pd.Package, db.NewAbsoluteListOptions(0, maxDependentsShown))
+	if err != nil {
+		ctx.ServerError("SearchDependents", err)
+		return
+	}
+	dependentDescriptors := make([]*packageDependent, 0, len(dependents))
+	for _, dependent := range dependents {
+		dependentPd, err := packages_model.GetPackageDescriptor(ctx, dependent.Version)
+		if err != nil {
+			ctx.ServerError("GetPackageDescriptor", err)
+			return
+		}
+		dependentDescriptors = append(dependentDescriptors, &packageDependent{
+			Descriptor: dependentPd,
+			Dependency: dependent.Dependency,
+		})
+	}
+	ctx.Data["Dependents"] = dependentDescriptors
+	ctx.Data["TotalDependentCount"] = totalDependents
+
 	ctx.Data["CanWritePackages"] = ctx.Package.AccessMode >= perm.AccessModeWrite || ctx.IsUserSiteAdmin()
 
+	now := time.Now()
+	stats, err := packages_service.GetDownloadStats(ctx, pd.Package, pd.Version, now.AddDate(0, 0, 1-downloadStatsDays), now)
+	if err != nil {
+		ctx.ServerError("GetDownloadStats", err)
+		return
+	}
+	ctx.Data["DownloadStats"] = stats
+	ctx.Data["DownloadStatsDays"] = downloadStatsDays
+	ctx.Data["DownloadBars"] = toDownloadBars(stats)
+
 	hasRepositoryAccess := false
 	if pd.Repository != nil {
 		permission, err := access_model.GetUserRepoPermission(ctx, pd.Repository, ctx.Doer)
@@ -209,6 +253,116 @@ func ViewPackageVersion(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplPackagesView)
 }
 
+// maxDependentsShown is the number of dependents listed on the package page
+const maxDependentsShown = 10
+
+// packageDependent is a package version which depends on the viewed package
+type packageDependent struct {
+	Descriptor *packages_model.PackageDescriptor
+	Dependency *packages_model.PackageDependency
+}
+
+// downloadBar is a bar of the download chart on the package page
+type downloadBar struct {
+	Date   string
+	Count  int64
+	Height int // percentage of the day with the most downloads
+}
+
+func toDownloadBars(stats *packages_service.DownloadStats) []*downloadBar {
+	max := int64(0)
+	for _, d := range stats.Days {
+		if d.Count > max {
+			max = d.Count
+		}
+	}
+
+	bars := make([]*downloadBar, 0, len(stats.Days))
+	for _, d := range stats.Days {
+		bar := &downloadBar{
+			Date:  d.Day.FormatInLocation("2006-01-02", time.UTC),
+			Count: d.Count,
+		}
+		if max > 0 {
+			bar.Height = int(d.Count * 100 / max)
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// maxCompareVersionsShown is the number of versions which can be selected to compare with
+const maxCompareVersionsShown = 50
+
+// ComparePackageVersions displays the differences between a package version and another version of the package.
+// The version published before is compared if no other version is selected.
+func ComparePackageVersions(ctx *context.Context) {
+	pd := ctx.Package.Descriptor
+
+	ctx.Data["Title"] = pd.Package.Name
+	ctx.Data["IsPackagesPage"] = true
+	ctx.Data["ContextUser"] = ctx.ContextUser
+	ctx.Data["PackageDescriptor"] = pd
+
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		Paginator:  db.NewAbsoluteListOptions(0, maxCompareVersionsShown),
+		PackageID:  pd.Package.ID,
+		IsInternal: util.OptionalBoolFalse,
+	})
+	if err != nil {
+		ctx.ServerError("SearchVersions", err)
+		return
+	}
+	versions := make([]*packages_model.PackageVersion, 0, len(pvs))
+	for _, pv := range pvs {
+		if pv.ID != pd.Version.ID {
+			versions = append(versions, pv)
+		}
+	}
+	ctx.Data["Versions"] = versions
+
+	var basePv *packages_model.PackageVersion
+	if baseVersion := ctx.FormTrim("base"); baseVersion != "" {
+		basePv, err = packages_model.GetVersionByNameAndVersion(ctx, pd.Owner.ID, pd.Package.Type, pd.Package.Name, baseVersion)
+		if err != nil {
+			if err == packages_model.ErrPackageNotExist {
+				ctx.NotFound("GetVersionByNameAndVersion", err)
+			} else {
+				ctx.ServerError("GetVersionByNameAndVersion", err)
+			}
+			return
+		}
+	} else {
+		// the versions are sorted from the newest to the oldest
+		for _, pv := range versions {
+			if pv.CreatedUnix < pd.Version.CreatedUnix || (pv.CreatedUnix == pd.Version.CreatedUnix && pv.ID < pd.Version.ID) {
+				basePv = pv
+				break
+			}
+		}
+	}
+	if basePv == nil {
+		ctx.HTML(http.StatusOK, tplPackagesCompare)
+		return
+	}
+
+	basePd, err := packages_model.GetPackageDescriptor(ctx, basePv)
+	if err != nil {
+		ctx.ServerError("GetPackageDescriptor", err)
+		return
+	}
+	ctx.Data["BaseDescriptor"] = basePd
+
+	comparison, err := packages_service.CompareVersions(basePd, pd)
+	if err != nil {
+		ctx.ServerError("CompareVersions", err)
+		return
+	}
+	ctx.Data["Comparison"] = comparison
+
+	ctx.HTML(http.StatusOK, tplPackagesCompare)
+}
+
 // ListPackageVersions lists all versions of a package
 func ListPackageVersions(ctx *context.Context) {
 	p, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.Type(ctx.Params("type")), ctx.Params("name"))
@@ -358,7 +512,12 @@ func PackageSettingsPost(ctx *context.Context) {
 		ctx.Redirect(ctx.Link)
 		return
 	case "delete":
-		err := packages_service.RemovePackageVersion(ctx.Doer, ctx.Package.Descriptor.Version)
+		err := packages_service.RemovePackageVersion(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, ctx.Package.Descriptor.Version)
+		if err == packages_service.ErrVersionImmutable {
+			ctx.Flash.Error(ctx.Tr("packages.settings.delete.immutable"))
+			ctx.Redirect(ctx.Link)
+			return
+		}
 		if err != nil {
 			log.Error("Error deleting package: %v", err)
 			ctx.Flash.Error(ctx.Tr("packages.settings.delete.error"))
@@ -383,14 +542,18 @@ func DownloadPackageFile(ctx *context.Context) {
 		return
 	}
 
-	s, _, err := packages_service.GetPackageFileStream(
-		ctx,
+	s, u, _, err := packages_service.GetPackageFileStream(
+		packages_module.WithRequest(ctx, ctx.Req),
 		pf,
 	)
 	if err != nil {
 		ctx.ServerError("GetPackageFileStream", err)
 		return
 	}
+	if u != nil {
+		ctx.Redirect(u.String(), http.StatusFound)
+		return
+	}
 	defer s.Close()
 
 	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsLocalTime())
@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	auth_model "code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
@@ -44,8 +45,27 @@ func ApplicationsPost(ctx *context.Context) {
 	}
 
 	t := &auth_model.AccessToken{
-		UID:  ctx.Doer.ID,
-		Name: form.Name,
+		UID:   ctx.Doer.ID,
+		Name:  form.Name,
+		Scope: auth_model.AccessTokenScope(form.Scope),
+	}
+	if !t.Scope.IsValid() || (form.PackageOwner != "" && !t.Scope.IsPackageScope()) {
+		ctx.Flash.Error(ctx.Tr("settings.token_scope_invalid"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+		return
+	}
+	if form.PackageOwner != "" {
+		owner, err := user_model.GetUserByName(ctx, form.PackageOwner)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Flash.Error(ctx.Tr("form.user_not_exist"))
+				ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
+			return
+		}
+		t.OwnerID = owner.ID
 	}
 
 	exist, err := auth_model.AccessTokenByNameExists(t)
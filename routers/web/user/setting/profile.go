@@ -17,11 +17,13 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/typesniffer"
@@ -30,6 +32,7 @@ import (
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/agit"
 	"code.gitea.io/gitea/services/forms"
+	packages_service "code.gitea.io/gitea/services/packages"
 	container_service "code.gitea.io/gitea/services/packages/container"
 	user_service "code.gitea.io/gitea/services/user"
 )
@@ -47,9 +50,94 @@ func Profile(ctx *context.Context) {
 	ctx.Data["PageIsSettingsProfile"] = true
 	ctx.Data["AllowedUserVisibilityModes"] = setting.Service.AllowedUserVisibilityModesSlice.ToVisibleTypeSlice()
 
+	if !LoadPackagesAnonymousRead(ctx, ctx.Doer) {
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplSettingsProfile)
 }
 
+// LoadPackagesAnonymousRead loads the settings if anonymous users can read the packages of the owner and if the package versions are immutable
+func LoadPackagesAnonymousRead(ctx *context.Context, owner *user_model.User) bool {
+	ctx.Data["IsPackageEnabled"] = setting.Packages.Enabled
+	if !setting.Packages.Enabled {
+		return true
+	}
+
+	val, err := user_model.GetUserSetting(owner.ID, user_model.SettingsKeyPackagesAnonymousRead)
+	if err != nil {
+		ctx.ServerError("GetUserSetting", err)
+		return false
+	}
+	ctx.Data["PackagesAnonymousRead"] = val == "true"
+
+	val, err = user_model.GetUserSetting(owner.ID, user_model.SettingsKeyPackagesImmutableVersions)
+	if err != nil {
+		ctx.ServerError("GetUserSetting", err)
+		return false
+	}
+	ctx.Data["PackagesImmutableVersions"] = val == "true"
+	return true
+}
+
+// UpdatePackagesAnonymousRead stores the setting if anonymous users can read the packages of the owner.
+// Only public owners can allow it, the setting is removed if the owner is not public anymore.
+func UpdatePackagesAnonymousRead(ctx *context.Context, owner *user_model.User, allow bool) bool {
+	if !setting.Packages.Enabled {
+		return true
+	}
+	allow = allow && owner.Visibility.IsPublic()
+
+	val, err := user_model.GetUserSetting(owner.ID, user_model.SettingsKeyPackagesAnonymousRead)
+	if err != nil {
+		ctx.ServerError("GetUserSetting", err)
+		return false
+	}
+	if (val == "true") == allow {
+		return true
+	}
+
+	if allow {
+		err = user_model.SetUserSetting(owner.ID, user_model.SettingsKeyPackagesAnonymousRead, "true")
+	} else {
+		err = user_model.DeleteUserSetting(owner.ID, user_model.SettingsKeyPackagesAnonymousRead)
+	}
+	if err != nil {
+		ctx.ServerError("SetUserSetting", err)
+		return false
+	}
+
+	detail := "anonymous read: disabled"
+	if allow {
+		detail = "anonymous read: enabled"
+	}
+	packages_service.RecordAudit(packages_module.WithRequest(ctx, ctx.Req), ctx.Doer, &packages_model.PackageAuditLog{
+		OwnerID: owner.ID,
+		Action:  packages_model.AuditActionPermission,
+		Detail:  detail,
+	})
+	return true
+}
+
+// UpdatePackagesImmutableVersions stores the setting if the published package versions of the owner are immutable
+func UpdatePackagesImmutableVersions(ctx *context.Context, owner *user_model.User, immutable bool) bool {
+	if !setting.Packages.Enabled {
+		return true
+	}
+
+	var err error
+	if immutable {
+		err = user_model.SetUserSetting(owner.ID, user_model.SettingsKeyPackagesImmutableVersions, "true")
+	} else {
+		err = user_model.DeleteUserSetting(owner.ID, user_model.SettingsKeyPackagesImmutableVersions)
+	}
+	if err != nil {
+		ctx.ServerError("SetUserSetting", err)
+		return false
+	}
+	return true
+}
+
 // HandleUsernameChange handle username changes from user settings and admin interface
 func HandleUsernameChange(ctx *context.Context, user *user_model.User, newName string) error {
 	// Non-local users are not allowed to change their username.
@@ -138,6 +226,14 @@ func ProfilePost(ctx *context.Context) {
 		return
 	}
 
+	if !UpdatePackagesAnonymousRead(ctx, ctx.Doer, form.PackagesAnonymousRead) {
+		return
+	}
+
+	if !UpdatePackagesImmutableVersions(ctx, ctx.Doer, form.PackagesImmutableVersions) {
+		return
+	}
+
 	// Update the language to the one we just set
 	middleware.SetLocaleCookie(ctx.Resp, ctx.Doer.Language, 0)
 
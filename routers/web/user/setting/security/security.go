@@ -69,6 +69,13 @@ func loadSecurityData(ctx *context.Context) {
 	}
 	ctx.Data["WebAuthnCredentials"] = credentials
 
+	keysOnly, err := user_model.IsSecurityKeysOnly(ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("IsSecurityKeysOnly", err)
+		return
+	}
+	ctx.Data["SecurityKeysOnly"] = keysOnly
+
 	tokens, err := auth_model.ListAccessTokens(auth_model.ListAccessTokensOptions{UserID: ctx.Doer.ID})
 	if err != nil {
 		ctx.ServerError("ListAccessTokens", err)
@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
 	wa "code.gitea.io/gitea/modules/auth/webauthn"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -111,6 +112,27 @@ func WebauthnRegisterPost(ctx *context.Context) {
 // WebauthnDelete deletes an security key by id
 func WebauthnDelete(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.WebauthnDeleteForm)
+
+	keysOnly, err := user_model.IsSecurityKeysOnly(ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("IsSecurityKeysOnly", err)
+		return
+	}
+	if keysOnly {
+		credentials, err := auth.GetWebAuthnCredentialsByUID(ctx.Doer.ID)
+		if err != nil {
+			ctx.ServerError("GetWebAuthnCredentialsByUID", err)
+			return
+		}
+		if len(credentials) <= 1 {
+			ctx.Flash.Error(ctx.Tr("settings.webauthn_keys_only_last_key"))
+			ctx.JSON(http.StatusOK, map[string]interface{}{
+				"redirect": setting.AppSubURL + "/user/settings/security",
+			})
+			return
+		}
+	}
+
 	if _, err := auth.DeleteCredential(form.ID, ctx.Doer.ID); err != nil {
 		ctx.ServerError("GetWebAuthnCredentialByID", err)
 		return
@@ -119,3 +141,37 @@ func WebauthnDelete(ctx *context.Context) {
 		"redirect": setting.AppSubURL + "/user/settings/security",
 	})
 }
+
+// WebauthnKeysOnly enables or disables that only security keys are allowed as second factor
+func WebauthnKeysOnly(ctx *context.Context) {
+	keysOnly := ctx.FormBool("keys_only")
+
+	var err error
+	if keysOnly {
+		var exists bool
+		exists, err = auth.ExistsWebAuthnCredentialsForUID(ctx.Doer.ID)
+		if err != nil {
+			ctx.ServerError("ExistsWebAuthnCredentialsForUID", err)
+			return
+		}
+		if !exists {
+			ctx.Flash.Error(ctx.Tr("settings.webauthn_keys_only_no_keys"))
+			ctx.Redirect(setting.AppSubURL + "/user/settings/security")
+			return
+		}
+		err = user_model.SetUserSetting(ctx.Doer.ID, user_model.SettingsKeySecurityKeysOnly, "true")
+	} else {
+		err = user_model.DeleteUserSetting(ctx.Doer.ID, user_model.SettingsKeySecurityKeysOnly)
+	}
+	if err != nil {
+		ctx.ServerError("SetUserSetting", err)
+		return
+	}
+
+	if keysOnly {
+		ctx.Flash.Success(ctx.Tr("settings.webauthn_keys_only_enabled"))
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.webauthn_keys_only_disabled"))
+	}
+	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
+}
@@ -10,9 +10,9 @@ import (
 	"os"
 	"path"
 
+	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/models/unit"
-	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/httpcache"
@@ -173,6 +173,9 @@ func Routes(ctx gocontext.Context) *web.Route {
 	if setting.Metrics.Enabled {
 		c := metrics.NewCollector()
 		prometheus.MustRegister(c)
+		if setting.Packages.Enabled {
+			prometheus.MustRegister(metrics.NewPackageCollector())
+		}
 
 		routes.Get("/metrics", append(common, Metrics)...)
 	}
@@ -216,6 +219,9 @@ func Routes(ctx gocontext.Context) *web.Route {
 	// TODO: These really seem like things that could be folded into Contexter or as helper functions
 	common = append(common, user.GetNotificationCount)
 	common = append(common, repo.GetActiveStopwatch)
+	if setting.Pages.Enabled && setting.Pages.Domain != "" {
+		common = append(common, pagesHost)
+	}
 	common = append(common, goGet)
 
 	others := web.NewRoute()
@@ -324,6 +330,7 @@ func RegisterRoutes(m *web.Route) {
 		m.Get("/organizations", explore.Organizations)
 		m.Get("/code", explore.Code)
 		m.Get("/topics/search", explore.TopicSearch)
+		m.Get("/search", explore.Search)
 	}, ignExploreSignIn)
 	m.Group("/issues", func() {
 		m.Get("", user.Issues)
@@ -414,6 +421,7 @@ func RegisterRoutes(m *web.Route) {
 				m.Post("/request_register", bindIgnErr(forms.WebauthnRegistrationForm{}), security.WebAuthnRegister)
 				m.Post("/register", security.WebauthnRegisterPost)
 				m.Post("/delete", bindIgnErr(forms.WebauthnDeleteForm{}), security.WebauthnDelete)
+				m.Post("/keys_only", security.WebauthnKeysOnly)
 			})
 			m.Group("/openid", func() {
 				m.Post("", bindIgnErr(forms.AddOpenIDForm{}), security.OpenIDPost)
@@ -517,6 +525,7 @@ func RegisterRoutes(m *web.Route) {
 		if setting.Packages.Enabled {
 			m.Group("/packages", func() {
 				m.Get("", admin.Packages)
+				m.Get("/audit", admin.PackagesAudit)
 				m.Post("/delete", admin.DeletePackageVersion)
 			})
 		}
@@ -562,6 +571,7 @@ func RegisterRoutes(m *web.Route) {
 			m.Combo("/{authid}").Get(admin.EditAuthSource).
 				Post(bindIgnErr(forms.AuthenticationForm{}), admin.EditAuthSourcePost)
 			m.Post("/{authid}/delete", admin.DeleteAuthSource)
+			m.Post("/{authid}/check_health", admin.CheckAuthSourceHealth)
 		})
 
 		m.Group("/notices", func() {
@@ -729,6 +739,7 @@ func RegisterRoutes(m *web.Route) {
 					m.Get("/versions", user.ListPackageVersions)
 					m.Group("/{version}", func() {
 						m.Get("", user.ViewPackageVersion)
+						m.Get("/compare", user.ComparePackageVersions)
 						m.Get("/files/{fileid}", user.DownloadPackageFile)
 						m.Group("/settings", func() {
 							m.Get("", user.PackageSettings)
@@ -1018,7 +1029,7 @@ func RegisterRoutes(m *web.Route) {
 				return
 			}
 			ctx.Data["CommitsCount"] = ctx.Repo.CommitsCount
-			ctx.Repo.GitRepo.LastCommitCache = git.NewLastCommitCache(ctx.Repo.CommitsCount, ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, cache.GetCache())
+			ctx.Repo.GitRepo.LastCommitCache = git.NewLastCommitCache(ctx.Repo.CommitsCount, ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, git_model.GetLastCommitStore(ctx.Repo.Repository.ID))
 		})
 	}, ignSignIn, context.RepoAssignment, context.UnitTypes(), reqRepoReleaseReader)
 
@@ -1099,6 +1110,11 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/raw/*", repo.WikiRaw)
 		}, repo.MustEnableWiki)
 
+		m.Group("/pages", func() {
+			m.Get("", repo.Pages)
+			m.Get("/*", repo.Pages)
+		}, repo.MustEnablePages)
+
 		m.Group("/activity", func() {
 			m.Get("", repo.Activity)
 			m.Get("/{period}", repo.Activity)
@@ -1146,6 +1162,7 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", context.RepoMustNotBeArchived(), bindIgnErr(forms.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
+			m.Post("/merge_checklist/{id}", context.RepoMustNotBeArchived(), context.RequireRepoWriter(unit.TypePullRequests), repo.UpdateMergeChecklistItem)
 			m.Post("/update", repo.UpdatePullRequest)
 			m.Post("/set_allow_maintainer_edit", bindIgnErr(forms.UpdateAllowEditsForm{}), repo.SetAllowEdits)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
@@ -1166,7 +1183,8 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), repo.DownloadByIDOrLFS)
 			// "/*" route is deprecated, and kept for backward compatibility
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownloadOrLFS)
-		}, repo.MustBeNotEmpty, reqRepoCodeReader)
+			m.Options("/*", repo.RawPreflight)
+		}, repo.MustBeNotEmpty, reqRepoCodeReader, repo.SetRawHeaders)
 
 		m.Group("/raw", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.SingleDownload)
@@ -1175,7 +1193,8 @@ func RegisterRoutes(m *web.Route) {
 			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), repo.DownloadByID)
 			// "/*" route is deprecated, and kept for backward compatibility
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownload)
-		}, repo.MustBeNotEmpty, reqRepoCodeReader)
+			m.Options("/*", repo.RawPreflight)
+		}, repo.MustBeNotEmpty, reqRepoCodeReader, repo.SetRawHeaders)
 
 		m.Group("/render", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RenderFile)
@@ -1221,6 +1240,12 @@ func RegisterRoutes(m *web.Route) {
 
 	m.Post("/{username}/{reponame}/lastcommit/*", ignSignInAndCsrf, context.RepoAssignment, context.UnitTypes(), context.RepoRefByType(context.RepoRefCommit), reqRepoCodeReader, repo.LastCommit)
 
+	// immutable raw links are addressed by the blob sha and may be signed to allow access to private repositories without authentication
+	m.Group("/{username}/{reponame}/raw/blob/{sha}", func() {
+		m.Get("/*", repo.MustBeNotEmpty, reqRepoCodeReader, repo.DownloadImmutableBlob)
+		m.Options("/*", repo.RawPreflight)
+	}, ignSignIn, repo.ImmutableRawAssignment, repo.SetRawHeaders)
+
 	m.Group("/{username}/{reponame}", func() {
 		m.Get("/stars", repo.Stars)
 		m.Get("/watchers", repo.Watchers)
@@ -7,6 +7,7 @@ package asymkey
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
@@ -128,6 +129,23 @@ func PublicSigningKey(ctx context.Context, repoPath string) (string, error) {
 	return content, nil
 }
 
+// SignDetached creates an armored detached signature of the content with the signing key of the provided repository directory.
+// An empty signature is returned if there is no signing key.
+func SignDetached(ctx context.Context, repoPath string, content io.Reader) (string, error) {
+	signingKey, _ := SigningKey(ctx, repoPath)
+	if signingKey == "" {
+		return "", nil
+	}
+
+	signature, stderr, err := process.GetManager().ExecDirEnvStdIn(ctx, -1, repoPath,
+		"gpg --detach-sign", nil, content, "gpg", "--batch", "--armor", "--detach-sign", "--local-user", signingKey)
+	if err != nil {
+		log.Error("Unable to create detached signature in %s: %s, %s, %v", repoPath, signingKey, stderr, err)
+		return "", err
+	}
+	return signature, nil
+}
+
 // SignInitialCommit determines if we should sign the initial commit to this repository
 func SignInitialCommit(ctx context.Context, repoPath string, u *user_model.User) (bool, string, *git.Signature, error) {
 	rules := signingModeFromStrings(setting.Repository.Signing.InitialCommit)
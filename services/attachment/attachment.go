@@ -7,11 +7,15 @@ package attachment
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/util"
@@ -27,11 +31,13 @@ func NewAttachment(attach *repo_model.Attachment, file io.Reader) (*repo_model.A
 
 	err := db.WithTx(func(ctx context.Context) error {
 		attach.UUID = uuid.New().String()
-		size, err := storage.Attachments.Save(attach.RelativePath(), file, -1)
+		hash := sha256.New()
+		size, err := storage.Attachments.Save(attach.RelativePath(), io.TeeReader(file, hash), -1)
 		if err != nil {
 			return fmt.Errorf("Create: %v", err)
 		}
 		attach.Size = size
+		attach.HashSHA256 = hex.EncodeToString(hash.Sum(nil))
 
 		return db.Insert(ctx, attach)
 	})
@@ -41,18 +47,89 @@ func NewAttachment(attach *repo_model.Attachment, file io.Reader) (*repo_model.A
 
 // UploadAttachment upload new attachment into storage and update database
 func UploadAttachment(file io.Reader, actorID, repoID, releaseID int64, fileName, allowedTypes string) (*repo_model.Attachment, error) {
-	buf := make([]byte, 1024)
-	n, _ := util.ReadAtMost(file, buf)
-	buf = buf[:n]
+	return uploadAttachment(file, &repo_model.Attachment{
+		RepoID:     repoID,
+		UploaderID: actorID,
+		ReleaseID:  releaseID,
+		Name:       fileName,
+	}, allowedTypes)
+}
 
-	if err := upload.Verify(buf, fileName, allowedTypes); err != nil {
-		return nil, err
+// UploadIssueAttachment uploads a new attachment of an issue or of a comment if commentID is set.
+// The type and the size of the file are checked against the attachment settings.
+func UploadIssueAttachment(file io.Reader, actorID, repoID, issueID, commentID int64, fileName string) (*repo_model.Attachment, error) {
+	var limited *sizeLimitedReader
+	if setting.Attachment.MaxSize > 0 {
+		limited = &sizeLimitedReader{
+			r:         file,
+			remaining: setting.Attachment.MaxSize << 20,
+			maxSize:   setting.Attachment.MaxSize << 20,
+		}
+		file = limited
 	}
 
-	return NewAttachment(&repo_model.Attachment{
+	attach, err := uploadAttachment(file, &repo_model.Attachment{
 		RepoID:     repoID,
 		UploaderID: actorID,
-		ReleaseID:  releaseID,
+		IssueID:    issueID,
+		CommentID:  commentID,
 		Name:       fileName,
-	}, io.MultiReader(bytes.NewReader(buf), file))
+	}, setting.Attachment.AllowedTypes)
+	if err != nil && limited != nil && limited.remaining < 0 {
+		// the storage wraps the error of the reader
+		return nil, ErrAttachmentTooLarge{MaxSize: limited.maxSize}
+	}
+	return attach, err
+}
+
+func uploadAttachment(file io.Reader, attach *repo_model.Attachment, allowedTypes string) (*repo_model.Attachment, error) {
+	buf := make([]byte, 1024)
+	n, err := util.ReadAtMost(file, buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	if err := upload.Verify(buf, attach.Name, allowedTypes); err != nil {
+		return nil, err
+	}
+
+	return NewAttachment(attach, io.MultiReader(bytes.NewReader(buf), file))
+}
+
+// ErrAttachmentTooLarge represents a "AttachmentTooLarge" kind of error.
+type ErrAttachmentTooLarge struct {
+	MaxSize int64
+}
+
+// IsErrAttachmentTooLarge checks if an error is a ErrAttachmentTooLarge.
+func IsErrAttachmentTooLarge(err error) bool {
+	_, ok := err.(ErrAttachmentTooLarge)
+	return ok
+}
+
+func (err ErrAttachmentTooLarge) Error() string {
+	return fmt.Sprintf("attachment is larger than %s", base.FileSize(err.MaxSize))
+}
+
+// sizeLimitedReader returns ErrAttachmentTooLarge instead of reading more than the maximum size
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	maxSize   int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrAttachmentTooLarge{MaxSize: l.maxSize}
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, ErrAttachmentTooLarge{MaxSize: l.maxSize}
+	}
+	return n, err
 }
@@ -0,0 +1,85 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrInvalidAttachmentURL represents a "InvalidAttachmentURL" kind of error.
+type ErrInvalidAttachmentURL struct {
+	URL    string
+	Reason string
+}
+
+// IsErrInvalidAttachmentURL checks if an error is a ErrInvalidAttachmentURL.
+func IsErrInvalidAttachmentURL(err error) bool {
+	_, ok := err.(ErrInvalidAttachmentURL)
+	return ok
+}
+
+func (err ErrInvalidAttachmentURL) Error() string {
+	return fmt.Sprintf("unable to fetch the attachment from %s: %s", util.SanitizeCredentialURLs(err.URL), err.Reason)
+}
+
+func newFetchClient() *http.Client {
+	allowList := setting.Attachment.FetchAllowedHostList
+	if allowList == "" {
+		allowList = hostmatcher.MatchBuiltinExternal
+	}
+
+	return &http.Client{
+		Timeout: setting.Attachment.FetchTimeout,
+		Transport: &http.Transport{
+			Proxy:       proxy.Proxy(),
+			DialContext: hostmatcher.NewDialContext("attachment", hostmatcher.ParseHostMatchList("attachment.FETCH_ALLOWED_HOST_LIST", allowList), nil),
+		},
+	}
+}
+
+// FetchIssueAttachment downloads the file from the url and stores it as attachment of an issue or of a comment if commentID is set.
+// The file name defaults to the last element of the url path.
+func FetchIssueAttachment(ctx context.Context, actorID, repoID, issueID, commentID int64, fileURL, fileName string) (*repo_model.Attachment, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidAttachmentURL{URL: fileURL, Reason: "only http and https urls are supported"}
+	}
+
+	if fileName == "" {
+		fileName = path.Base(u.Path)
+		if fileName == "/" || fileName == "." {
+			fileName = "attachment"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := newFetchClient().Do(req)
+	if err != nil {
+		return nil, ErrInvalidAttachmentURL{URL: fileURL, Reason: util.SanitizeCredentialURLs(err.Error())}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrInvalidAttachmentURL{URL: fileURL, Reason: fmt.Sprintf("unexpected status code %d", resp.StatusCode)}
+	}
+	if maxSize := setting.Attachment.MaxSize << 20; maxSize > 0 && resp.ContentLength > maxSize {
+		return nil, ErrAttachmentTooLarge{MaxSize: maxSize}
+	}
+
+	return UploadIssueAttachment(resp.Body, actorID, repoID, issueID, commentID, fileName)
+}
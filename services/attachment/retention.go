@@ -0,0 +1,98 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+const retentionBatchSize = 100
+
+// RetentionReport holds the attachments deleted by a cleanup, or the ones which would be deleted by a dry run
+type RetentionReport struct {
+	DryRun               bool
+	NumOrphaned          int64
+	OrphanedSize         int64
+	NumClosedIssue       int64
+	ClosedIssueSize      int64
+	ClosedIssueOlderThan time.Duration
+}
+
+// String returns a summary of the report
+func (r *RetentionReport) String() string {
+	verb := "Deleted"
+	if r.DryRun {
+		verb = "Dry run, would delete"
+	}
+	msg := fmt.Sprintf("%s %d attachments (%s) of deleted issues and comments", verb, r.NumOrphaned, base.FileSize(r.OrphanedSize))
+	if r.ClosedIssueOlderThan > 0 {
+		msg += fmt.Sprintf(" and %d attachments (%s) of closed issues older than %s", r.NumClosedIssue, base.FileSize(r.ClosedIssueSize), r.ClosedIssueOlderThan)
+	}
+	return msg
+}
+
+// CleanupAttachments deletes the attachments of deleted issues and comments and, if closedIssueOlderThan is not zero,
+// the attachments of closed issues uploaded before that time. A dry run only reports what would be deleted.
+func CleanupAttachments(ctx context.Context, closedIssueOlderThan time.Duration, dryRun bool) (*RetentionReport, error) {
+	report := &RetentionReport{
+		DryRun:               dryRun,
+		ClosedIssueOlderThan: closedIssueOlderThan,
+	}
+
+	var err error
+	orphanedCond := repo_model.OrphanedIssueAttachmentsCond()
+	if report.NumOrphaned, report.OrphanedSize, err = cleanupAttachmentsByCond(ctx, orphanedCond, dryRun); err != nil {
+		return nil, fmt.Errorf("cleanup orphaned attachments: %w", err)
+	}
+
+	if closedIssueOlderThan > 0 {
+		closedCond := repo_model.ClosedIssueAttachmentsCond(timeutil.TimeStamp(time.Now().Add(-closedIssueOlderThan).Unix()))
+		if report.NumClosedIssue, report.ClosedIssueSize, err = cleanupAttachmentsByCond(ctx, closedCond, dryRun); err != nil {
+			return nil, fmt.Errorf("cleanup attachments of closed issues: %w", err)
+		}
+	}
+
+	log.Info("CleanupAttachments: %s", report)
+	return report, nil
+}
+
+// cleanupAttachmentsByCond deletes the attachments matching the condition together with their files
+func cleanupAttachmentsByCond(ctx context.Context, cond builder.Cond, dryRun bool) (count, size int64, err error) {
+	if dryRun {
+		return repo_model.CountAttachmentsByCond(ctx, cond)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return count, size, fmt.Errorf("aborted after deleting %d attachments: %w", count, ctx.Err())
+		default:
+		}
+
+		attachments, err := repo_model.FindAttachmentsByCond(ctx, cond, retentionBatchSize)
+		if err != nil {
+			return count, size, err
+		}
+		if len(attachments) == 0 {
+			return count, size, nil
+		}
+		if _, err := repo_model.DeleteAttachments(ctx, attachments, true); err != nil {
+			return count, size, err
+		}
+		for _, a := range attachments {
+			count++
+			size += a.Size
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupAttachments(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	orphaned := &repo_model.Attachment{
+		UUID:      "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380b01",
+		RepoID:    1,
+		IssueID:   1,
+		CommentID: 9999,
+		Name:      "orphaned.txt",
+		Size:      42,
+	}
+	assert.NoError(t, db.Insert(db.DefaultContext, orphaned))
+
+	report, err := CleanupAttachments(db.DefaultContext, 24*time.Hour, true)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, report.NumOrphaned)
+	assert.EqualValues(t, 42, report.OrphanedSize)
+	// the attachments of the closed issues 4 and 5
+	assert.EqualValues(t, 4, report.NumClosedIssue)
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: orphaned.ID})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: 2})
+
+	report, err = CleanupAttachments(db.DefaultContext, 0, false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, report.NumOrphaned)
+	assert.EqualValues(t, 0, report.NumClosedIssue)
+	unittest.AssertNotExistsBean(t, &repo_model.Attachment{ID: orphaned.ID})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: 2})
+
+	report, err = CleanupAttachments(db.DefaultContext, 24*time.Hour, false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, report.NumOrphaned)
+	assert.EqualValues(t, 4, report.NumClosedIssue)
+	for _, id := range []int64{2, 5, 6, 7} {
+		unittest.AssertNotExistsBean(t, &repo_model.Attachment{ID: id})
+	}
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: 1})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: 9})
+}
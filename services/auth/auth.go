@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/auth/webauthn"
@@ -36,6 +37,27 @@ func isContainerPath(req *http.Request) bool {
 	return strings.HasPrefix(req.URL.Path, "/v2/")
 }
 
+// isPackagePath checks if the request targets an endpoint of the package registry
+func isPackagePath(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.Path, "/api/packages/") || strings.HasPrefix(req.URL.Path, "/api/v1/packages/") || isContainerPath(req)
+}
+
+// isAccessTokenScopeAllowed checks if the scope of the access token permits the request
+func isAccessTokenScopeAllowed(req *http.Request, scope auth_model.AccessTokenScope) bool {
+	return !scope.IsPackageScope() || isPackagePath(req)
+}
+
+// StoreAccessTokenScope stores the scope of the access token which was used to authenticate the request
+func StoreAccessTokenScope(store DataStore, scope auth_model.AccessTokenScope, ownerID int64) {
+	store.GetData()["ApiTokenScope"] = scope
+	store.GetData()["ApiTokenOwnerID"] = ownerID
+}
+
+// StoreAccessTokenID stores the id of the access token which was used to authenticate the request
+func StoreAccessTokenID(store DataStore, tokenID int64) {
+	store.GetData()["ApiTokenID"] = tokenID
+}
+
 var (
 	gitRawReleasePathRe = regexp.MustCompile(`^/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+/(?:(?:git-(?:(?:upload)|(?:receive))-pack$)|(?:info/refs$)|(?:HEAD$)|(?:objects/)|(?:raw/)|(?:releases/download/))`)
 	lfsPathRe           = regexp.MustCompile(`^/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+/info/lfs/`)
@@ -87,6 +87,11 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 
 	token, err := auth_model.GetAccessTokenBySHA(authToken)
 	if err == nil {
+		if !isAccessTokenScopeAllowed(req, token.Scope) {
+			log.Trace("Basic Authorization: AccessToken of user[%d] is restricted to scope %s", token.UID, token.Scope)
+			return nil
+		}
+
 		log.Trace("Basic Authorization: Valid AccessToken for user[%d]", uid)
 		u, err := user_model.GetUserByID(token.UID)
 		if err != nil {
@@ -100,6 +105,8 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 		}
 
 		store.GetData()["IsApiToken"] = true
+		StoreAccessTokenScope(store, token.Scope, token.OwnerID)
+		StoreAccessTokenID(store, token.ID)
 		return u
 	} else if !auth_model.IsErrAccessTokenNotExist(err) && !auth_model.IsErrAccessTokenEmpty(err) {
 		log.Error("GetAccessTokenBySha: %v", err)
@@ -0,0 +1,68 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// CheckSourceHealth checks if the external service of the login source is reachable and stores the result
+func CheckSourceHealth(ctx context.Context, source *auth.Source) (*auth.SourceHealth, error) {
+	checker, ok := source.Cfg.(auth.HealthChecker)
+	if !ok {
+		return nil, fmt.Errorf("login source %s does not support health checks", source.Name)
+	}
+
+	checkErr := checker.CheckHealth(ctx)
+	if checkErr != nil {
+		log.Warn("Login source %s is unhealthy: %v", source.Name, checkErr)
+	}
+	return auth.UpdateSourceHealth(ctx, source.ID, checkErr)
+}
+
+// CheckSourcesHealth checks the health of all active login sources
+func CheckSourcesHealth(ctx context.Context) error {
+	log.Trace("Doing: CheckSourcesHealth")
+
+	sources, err := auth.AllActiveSources()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		if !source.IsHealthCheckable() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			log.Warn("CheckSourcesHealth: Cancelled before check of %s", source.Name)
+			return db.ErrCancelledf("Before check of %s", source.Name)
+		default:
+		}
+
+		if _, err := CheckSourceHealth(ctx, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSourceUnavailable checks if the external service of the login source can't be reached at the moment
+func isSourceUnavailable(ctx context.Context, source *auth.Source) bool {
+	if !source.IsHealthCheckable() {
+		return false
+	}
+	health, err := CheckSourceHealth(ctx, source)
+	if err != nil {
+		log.Error("CheckSourceHealth: %v", err)
+		return false
+	}
+	return !health.IsHealthy
+}
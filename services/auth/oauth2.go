@@ -97,11 +97,17 @@ func (o *OAuth2) userIDFromToken(req *http.Request, store DataStore) int64 {
 		}
 		return 0
 	}
+	if !isAccessTokenScopeAllowed(req, t.Scope) {
+		log.Trace("OAuth2 Authorization: AccessToken of user[%d] is restricted to scope %s", t.UID, t.Scope)
+		return 0
+	}
 	t.UpdatedUnix = timeutil.TimeStampNow()
 	if err = auth_model.UpdateAccessToken(t); err != nil {
 		log.Error("UpdateAccessToken: %v", err)
 	}
 	store.GetData()["IsApiToken"] = true
+	StoreAccessTokenScope(store, t.Scope, t.OwnerID)
+	StoreAccessTokenID(store, t.ID)
 	return t.UID
 }
 
@@ -56,6 +56,11 @@ func UserSignIn(username, password string) (*user_model.User, *auth.Source, erro
 		}
 
 		if hasUser {
+			// service accounts can only authenticate with access tokens
+			if user.IsBot() {
+				return nil, nil, user_model.ErrUserProhibitLogin{UID: user.ID, Name: user.Name}
+			}
+
 			source, err := auth.GetSourceByID(user.LoginSource)
 			if err != nil {
 				return nil, nil, err
@@ -70,10 +75,17 @@ func UserSignIn(username, password string) (*user_model.User, *auth.Source, erro
 				return nil, nil, smtp.ErrUnsupportedLoginType
 			}
 
-			user, err := authenticator.Authenticate(user, user.LoginName, password)
+			authUser, err := authenticator.Authenticate(user, user.LoginName, password)
 			if err != nil {
-				return nil, nil, err
+				if source.FallbackSourceID == 0 || !isSourceUnavailable(db.DefaultContext, source) {
+					return nil, nil, err
+				}
+				authUser, source, err = authenticateWithFallbackSources(user, source, password)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
+			user = authUser
 
 			// WARN: DON'T check user.IsActive, that will be checked on reqSign so that
 			// user could be hint to resend confirm email.
@@ -123,3 +135,27 @@ func UserSignIn(username, password string) (*user_model.User, *auth.Source, erro
 
 	return nil, nil, user_model.ErrUserNotExist{Name: username}
 }
+
+// authenticateWithFallbackSources tries the fallback sources of the unavailable login source of the user
+func authenticateWithFallbackSources(user *user_model.User, source *auth.Source, password string) (*user_model.User, *auth.Source, error) {
+	fallbacks, err := auth.GetFallbackSources(source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, fallback := range fallbacks {
+		authenticator, ok := fallback.Cfg.(PasswordAuthenticator)
+		if !ok {
+			continue
+		}
+
+		authUser, err := authenticator.Authenticate(user, user.LoginName, password)
+		if err == nil {
+			log.Warn("User '%s' signed in via fallback source '%s' because '%s' is unavailable", user.Name, fallback.Name, source.Name)
+			return authUser, fallback, nil
+		}
+		log.Debug("Failed to login '%s' via fallback source '%s': %v", user.Name, fallback.Name, err)
+	}
+
+	return nil, nil, user_model.ErrUserNotExist{Name: user.Name}
+}
@@ -36,6 +36,15 @@ func DeleteSource(source *auth.Source) error {
 		}
 	}
 
+	// sources using this source as fallback no longer have one
+	if _, err := db.GetEngine(db.DefaultContext).Where("fallback_source_id = ?", source.ID).Cols("fallback_source_id").Update(&auth.Source{}); err != nil {
+		return err
+	}
+
+	if err := auth.DeleteSourceHealth(db.DefaultContext, source.ID); err != nil {
+		return err
+	}
+
 	_, err = db.GetEngine(db.DefaultContext).ID(source.ID).Delete(new(auth.Source))
 	return err
 }
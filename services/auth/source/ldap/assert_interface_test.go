@@ -19,6 +19,7 @@ type sourceInterface interface {
 	auth.LocalTwoFASkipper
 	auth_model.SSHKeyProvider
 	auth_model.Config
+	auth_model.HealthChecker
 	auth_model.SkipVerifiable
 	auth_model.HasTLSer
 	auth_model.UseTLSer
@@ -0,0 +1,26 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ldap
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth checks if the LDAP server is reachable and accepts the bind credentials
+func (source *Source) CheckHealth(ctx context.Context) error {
+	l, err := dial(source)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	if source.BindDN != "" && source.BindPassword != "" {
+		if err := l.Bind(source.BindDN, source.BindPassword); err != nil {
+			return fmt.Errorf("failed to bind as %s: %w", source.BindDN, err)
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 
 type sourceInterface interface {
 	auth_model.Config
+	auth_model.HealthChecker
 	auth_model.SourceSettable
 	auth_model.RegisterableSource
 	auth.PasswordAuthenticator
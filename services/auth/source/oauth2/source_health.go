@@ -0,0 +1,53 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/proxy"
+)
+
+const healthCheckTimeout = 10 * time.Second
+
+// CheckHealth checks if the OpenID Connect discovery document or the custom authorization url of the provider is reachable.
+// Providers with well-known urls are not checked.
+func (source *Source) CheckHealth(ctx context.Context) error {
+	var url string
+	if source.Provider == (&OpenIDProvider{}).Name() {
+		url = source.OpenIDConnectAutoDiscoveryURL
+	} else if source.CustomURLMapping != nil {
+		url = source.CustomURLMapping.AuthURL
+	}
+	if url == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: healthCheckTimeout,
+		Transport: &http.Transport{
+			Proxy: proxy.Proxy(),
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	// the authorization url may reject a request without parameters, only server errors are treated as unhealthy
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return nil
+}
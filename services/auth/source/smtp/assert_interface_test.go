@@ -16,6 +16,7 @@ import (
 type sourceInterface interface {
 	auth.PasswordAuthenticator
 	auth_model.Config
+	auth_model.HealthChecker
 	auth_model.SkipVerifiable
 	auth_model.HasTLSer
 	auth_model.UseTLSer
@@ -0,0 +1,46 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const healthCheckTimeout = 10 * time.Second
+
+// CheckHealth checks if the SMTP server is reachable and answers the greeting
+func (source *Source) CheckHealth(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: healthCheckTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(source.Addr, strconv.Itoa(source.Port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(healthCheckTimeout)); err != nil {
+		return err
+	}
+
+	if source.UseTLS() {
+		conn = tls.Client(conn, &tls.Config{
+			InsecureSkipVerify: source.SkipVerify,
+			ServerName:         source.Addr,
+		})
+	}
+
+	client, err := smtp.NewClient(conn, source.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to create NewClient: %w", err)
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
@@ -63,6 +63,18 @@ func UpdateComment(c *issues_model.Comment, doer *user_model.User, oldContent st
 		if err != nil {
 			return err
 		}
+
+		if doer.ID != c.PosterID {
+			if err := issues_model.InsertModerationLog(db.DefaultContext, &issues_model.ModerationLog{
+				RepoID:    c.Issue.RepoID,
+				IssueID:   c.IssueID,
+				CommentID: c.ID,
+				DoerID:    doer.ID,
+				Action:    issues_model.ModerationActionEditComment,
+			}); err != nil {
+				return err
+			}
+		}
 	}
 
 	notification.NotifyUpdateComment(doer, c, oldContent)
@@ -81,6 +93,20 @@ func DeleteComment(doer *user_model.User, comment *issues_model.Comment) error {
 	if err := issues_model.DeleteComment(ctx, comment); err != nil {
 		return err
 	}
+	if doer.ID != comment.PosterID {
+		if err := comment.LoadIssueCtx(ctx); err != nil {
+			return err
+		}
+		if err := issues_model.InsertModerationLog(ctx, &issues_model.ModerationLog{
+			RepoID:    comment.Issue.RepoID,
+			IssueID:   comment.IssueID,
+			CommentID: comment.ID,
+			DoerID:    doer.ID,
+			Action:    issues_model.ModerationActionDeleteComment,
+		}); err != nil {
+			return err
+		}
+	}
 	if err := committer.Commit(); err != nil {
 		return err
 	}
@@ -89,3 +115,55 @@ func DeleteComment(doer *user_model.User, comment *issues_model.Comment) error {
 
 	return nil
 }
+
+// HideComment hides the comment for the given reason, the comment is still available but collapsed
+func HideComment(doer *user_model.User, comment *issues_model.Comment, reason string) error {
+	return updateCommentHidden(doer, comment, reason, true)
+}
+
+// UnhideComment makes a hidden comment visible again
+func UnhideComment(doer *user_model.User, comment *issues_model.Comment) error {
+	return updateCommentHidden(doer, comment, "", false)
+}
+
+func updateCommentHidden(doer *user_model.User, comment *issues_model.Comment, reason string, hide bool) error {
+	ctx, committer, err := db.TxContext()
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if err := comment.LoadIssueCtx(ctx); err != nil {
+		return err
+	}
+
+	action := issues_model.ModerationActionUnhideComment
+	if hide {
+		action = issues_model.ModerationActionHideComment
+		comment.HiddenByID = doer.ID
+		comment.HiddenBy = doer
+		comment.HiddenReason = reason
+		comment.HiddenUnix = timeutil.TimeStampNow()
+	} else {
+		comment.HiddenByID = 0
+		comment.HiddenBy = nil
+		comment.HiddenReason = ""
+		comment.HiddenUnix = 0
+	}
+
+	if err := issues_model.UpdateCommentHidden(ctx, comment); err != nil {
+		return err
+	}
+	if err := issues_model.InsertModerationLog(ctx, &issues_model.ModerationLog{
+		RepoID:    comment.Issue.RepoID,
+		IssueID:   comment.IssueID,
+		CommentID: comment.ID,
+		DoerID:    doer.ID,
+		Action:    action,
+		Reason:    reason,
+	}); err != nil {
+		return err
+	}
+
+	return committer.Commit()
+}
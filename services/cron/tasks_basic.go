@@ -9,16 +9,23 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	activities_model "code.gitea.io/gitea/models/activities"
 	git_model "code.gitea.io/gitea/models/git"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/auth"
+	digest_service "code.gitea.io/gitea/services/digest"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mailer"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	packages_service "code.gitea.io/gitea/services/packages"
+	package_mirror_service "code.gitea.io/gitea/services/packages/mirror"
+	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 func registerUpdateMirrorTask() {
@@ -100,6 +107,99 @@ func registerSyncExternalUsers() {
 	})
 }
 
+func registerCheckAuthSourcesHealth() {
+	RegisterTaskFatal("check_auth_sources_health", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 5m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return auth.CheckSourcesHealth(ctx)
+	})
+}
+
+func registerRevokeExpiredCollaborations() {
+	type RevokeExpiredCollaborationsConfig struct {
+		BaseConfig
+		RemindBefore time.Duration
+	}
+	RegisterTaskFatal("revoke_expired_collaborations", &RevokeExpiredCollaborationsConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@every 1h",
+		},
+		RemindBefore: 72 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		recConfig := config.(*RevokeExpiredCollaborationsConfig)
+		return repo_service.RevokeExpiredCollaborations(ctx, recConfig.RemindBefore)
+	})
+}
+
+func registerRemindExpiringKeys() {
+	type RemindExpiringKeysConfig struct {
+		BaseConfig
+		RemindBefore time.Duration
+	}
+	RegisterTaskFatal("remind_expiring_keys", &RemindExpiringKeysConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 1h",
+		},
+		RemindBefore: 7 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		rekConfig := config.(*RemindExpiringKeysConfig)
+		return mailer.RemindExpiringKeys(ctx, rekConfig.RemindBefore)
+	})
+}
+
+func registerAggregateReviewStats() {
+	RegisterTaskFatal("aggregate_review_stats", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return pull_service.AggregateReviewStats(ctx)
+	})
+}
+
+func registerAggregateUsage() {
+	RegisterTaskFatal("aggregate_usage", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 365 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		if _, err := activities_model.CreateUsageReport(ctx); err != nil {
+			return err
+		}
+		return activities_model.DeleteUsageReportsOlderThan(ctx, realConfig.OlderThan)
+	})
+}
+
+func registerTransferScheduledRepositories() {
+	RegisterTaskFatal("transfer_scheduled_repositories", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 5m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return repo_service.TransferScheduledRepositories(ctx)
+	})
+}
+
+func registerUnlockExpiredIssues() {
+	RegisterTaskFatal("unlock_expired_issues", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 10m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return issue_service.UnlockExpiredIssues(ctx)
+	})
+}
+
 func registerDeletedBranchesCleanup() {
 	RegisterTaskFatal("deleted_branches_cleanup", &OlderThanConfig{
 		BaseConfig: BaseConfig{
@@ -115,6 +215,20 @@ func registerDeletedBranchesCleanup() {
 	})
 }
 
+func registerCleanupLastCommitCache() {
+	RegisterTaskFatal("cleanup_last_commit_cache", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: setting.CacheService.LastCommit.TTL,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return git_model.DeleteOldLastCommitCache(ctx, realConfig.OlderThan)
+	})
+}
+
 func registerUpdateMigrationPosterID() {
 	RegisterTaskFatal("update_migration_poster_id", &BaseConfig{
 		Enabled:    true,
@@ -155,6 +269,36 @@ func registerCleanupPackages() {
 	})
 }
 
+func registerSyncPackageMirrors() {
+	RegisterTaskFatal("sync_package_mirrors", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 10m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return package_mirror_service.Update(ctx)
+	})
+}
+
+func registerProcessNameClaims() {
+	RegisterTaskFatal("process_name_claims", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return user_service.ProcessNameClaims(ctx)
+	})
+}
+
+func registerSendWebhookDigests() {
+	RegisterTaskFatal("send_webhook_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@weekly",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return digest_service.SendDigests(ctx)
+	})
+}
+
 func initBasicTasks() {
 	if setting.Mirror.Enabled {
 		registerUpdateMirrorTask()
@@ -163,12 +307,27 @@ func initBasicTasks() {
 	registerCheckRepoStats()
 	registerArchiveCleanup()
 	registerSyncExternalUsers()
+	registerCheckAuthSourcesHealth()
+	registerRevokeExpiredCollaborations()
+	registerRemindExpiringKeys()
+	registerAggregateReviewStats()
+	registerAggregateUsage()
+	registerTransferScheduledRepositories()
+	registerUnlockExpiredIssues()
+	registerProcessNameClaims()
 	registerDeletedBranchesCleanup()
+	if setting.CacheService.LastCommit.Store == setting.LastCommitStoreDatabase {
+		registerCleanupLastCommitCache()
+	}
 	if !setting.Repository.DisableMigrations {
 		registerUpdateMigrationPosterID()
 	}
 	registerCleanupHookTaskTable()
+	if !setting.DisableWebhooks {
+		registerSendWebhookDigests()
+	}
 	if setting.Packages.Enabled {
 		registerCleanupPackages()
+		registerSyncPackageMirrors()
 	}
 }
@@ -13,8 +13,10 @@ import (
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/updatechecker"
+	attachment_service "code.gitea.io/gitea/services/attachment"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
 	user_service "code.gitea.io/gitea/services/user"
@@ -170,6 +172,33 @@ func registerDeleteOldSystemNotices() {
 	})
 }
 
+func registerCleanupAttachments() {
+	type CleanupAttachmentsConfig struct {
+		BaseConfig
+		DryRun               bool
+		ClosedIssueOlderThan time.Duration
+	}
+	RegisterTaskFatal("cleanup_attachments", &CleanupAttachmentsConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 168h",
+		},
+		DryRun:               true,
+		ClosedIssueOlderThan: 0,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		caConfig := config.(*CleanupAttachmentsConfig)
+		report, err := attachment_service.CleanupAttachments(ctx, caConfig.ClosedIssueOlderThan, caConfig.DryRun)
+		if err != nil {
+			return err
+		}
+		if err := admin.CreateNotice(ctx, admin.NoticeTask, report.String()); err != nil {
+			log.Error("CreateNotice: %v", err)
+		}
+		return nil
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
 	registerDeleteRepositoryArchives()
@@ -183,4 +212,5 @@ func initExtendedTasks() {
 	registerDeleteOldActions()
 	registerUpdateGiteaChecker()
 	registerDeleteOldSystemNotices()
+	registerCleanupAttachments()
 }
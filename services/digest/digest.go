@@ -0,0 +1,163 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// DigestPeriod is the time span summarized by a digest
+const DigestPeriod = 7 * 24 * time.Hour
+
+// SendDigests delivers the summary of the last week to all webhooks which enabled the digest event.
+// Repository webhooks receive the digest of their repository, organization webhooks a single digest of all repositories of the organization.
+// Digests without any activity are not sent.
+func SendDigests(ctx context.Context) error {
+	if setting.DisableWebhooks {
+		return nil
+	}
+
+	hooks, err := webhook_model.FindDigestWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("FindDigestWebhooks: %w", err)
+	}
+
+	until := time.Now()
+	since := until.Add(-DigestPeriod)
+
+	// repositories are summarized once even if several webhooks receive them
+	summaries := make(map[int64]*api.DigestRepository)
+
+	for _, w := range hooks {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("aborted before sending the digest of webhook %d: %w", w.ID, ctx.Err())
+		default:
+		}
+
+		p, err := createDigestPayload(ctx, w, since, until, summaries)
+		if err != nil {
+			log.Error("Unable to create the digest of webhook %d: %v", w.ID, err)
+			continue
+		}
+		if p == nil {
+			continue
+		}
+
+		if err := webhook_service.PrepareDigestWebhook(w, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createDigestPayload returns the digest for the repository or organization of the webhook or nil if there was no activity
+func createDigestPayload(ctx context.Context, w *webhook_model.Webhook, since, until time.Time, summaries map[int64]*api.DigestRepository) (*api.DigestPayload, error) {
+	p := &api.DigestPayload{
+		Since:        since,
+		Until:        until,
+		Repositories: make([]*api.DigestRepository, 0, 1),
+	}
+
+	var repos []*repo_model.Repository
+	if w.OrgID > 0 {
+		org, err := organization.GetOrgByID(ctx, w.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		p.Organization = convert.ToOrganization(org)
+
+		if repos, err = organization.GetOrgRepositories(ctx, org.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		repo, err := repo_model.GetRepositoryByIDCtx(ctx, w.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		repos = []*repo_model.Repository{repo}
+	}
+
+	for _, repo := range repos {
+		summary, ok := summaries[repo.ID]
+		if !ok {
+			var err error
+			if summary, err = summarizeRepository(ctx, repo, since); err != nil {
+				return nil, fmt.Errorf("summarize repository %d: %w", repo.ID, err)
+			}
+			summaries[repo.ID] = summary
+		}
+		if summary != nil {
+			p.Repositories = append(p.Repositories, summary)
+		}
+	}
+
+	if len(p.Repositories) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// summarizeRepository returns the activity of the repository since the given time or nil if there was none
+func summarizeRepository(ctx context.Context, repo *repo_model.Repository, since time.Time) (*api.DigestRepository, error) {
+	stats, err := activities_model.GetActivityStats(ctx, repo, since, true, true, true, !repo.IsEmpty)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &api.DigestRepository{
+		Repository:         convert.ToRepo(repo, perm.AccessModeRead),
+		Commits:            stats.Code.CommitCount,
+		CommitAuthors:      stats.Code.AuthorCount,
+		MergedPullRequests: make([]*api.DigestEntry, 0, len(stats.MergedPRs)),
+		NewIssues:          make([]*api.DigestEntry, 0, len(stats.OpenedIssues)),
+		Releases:           make([]*api.DigestEntry, 0, len(stats.PublishedReleases)),
+	}
+	for _, pr := range stats.MergedPRs {
+		pr.Issue.Repo = repo
+		summary.MergedPullRequests = append(summary.MergedPullRequests, &api.DigestEntry{
+			Number:  pr.Issue.Index,
+			Title:   pr.Issue.Title,
+			HTMLURL: pr.Issue.HTMLURL(),
+		})
+	}
+	for _, issue := range stats.OpenedIssues {
+		issue.Repo = repo
+		summary.NewIssues = append(summary.NewIssues, &api.DigestEntry{
+			Number:  issue.Index,
+			Title:   issue.Title,
+			HTMLURL: issue.HTMLURL(),
+		})
+	}
+	for _, rel := range stats.PublishedReleases {
+		rel.Repo = repo
+		title := rel.Title
+		if title == "" {
+			title = rel.TagName
+		}
+		summary.Releases = append(summary.Releases, &api.DigestEntry{
+			Title:   title,
+			HTMLURL: rel.HTMLURL(),
+		})
+	}
+
+	if summary.Commits == 0 && len(summary.MergedPullRequests) == 0 && len(summary.NewIssues) == 0 && len(summary.Releases) == 0 {
+		return nil, nil
+	}
+	return summary, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package digest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	unittest.MainTest(m, &unittest.TestOptions{
+		GiteaRootPath: filepath.Join("..", ".."),
+	})
+}
+
+func TestCreateDigestPayload(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	w := &webhook_model.Webhook{RepoID: 1}
+	until := time.Now()
+
+	// the fixtures have no activity during the last week
+	p, err := createDigestPayload(db.DefaultContext, w, until.Add(-7*24*time.Hour), until, map[int64]*api.DigestRepository{})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = createDigestPayload(db.DefaultContext, w, time.Unix(0, 0), until, map[int64]*api.DigestRepository{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, p) && assert.Len(t, p.Repositories, 1) {
+		assert.Nil(t, p.Organization)
+		assert.Equal(t, "user2/repo1", p.Repositories[0].Repository.FullName)
+		assert.NotEmpty(t, p.Repositories[0].NewIssues)
+		assert.NotZero(t, p.Repositories[0].Commits)
+	}
+
+	// organization webhooks receive the repositories of the organization with activity
+	p, err = createDigestPayload(db.DefaultContext, &webhook_model.Webhook{OrgID: 3}, time.Unix(0, 0), until, map[int64]*api.DigestRepository{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "user3", p.Organization.UserName)
+		for _, r := range p.Repositories {
+			assert.Equal(t, "user3", r.Repository.Owner.UserName)
+		}
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	hook := &webhook_model.Webhook{
+		RepoID:      1,
+		URL:         "http://www.example.com/digest",
+		ContentType: webhook_model.ContentTypeJSON,
+		IsActive:    true,
+		Events:      `{"push_only":false,"send_everything":false,"choose_events":true,"events":{"digest":true}}`,
+	}
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, hook))
+
+	// no digest is sent without activity during the last week
+	assert.NoError(t, SendDigests(db.DefaultContext))
+	unittest.AssertNotExistsBean(t, &webhook_model.HookTask{HookID: hook.ID})
+}
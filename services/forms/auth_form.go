@@ -44,6 +44,7 @@ type AuthenticationForm struct {
 	AllowDeactivateAll            bool
 	IsActive                      bool
 	IsSyncEnabled                 bool
+	FallbackSourceID              int64
 	SMTPAuth                      string
 	SMTPAddr                      string
 	SMTPPort                      int
@@ -45,6 +45,8 @@ type UpdateOrgSettingForm struct {
 	Visibility                structs.VisibleType
 	MaxRepoCreation           int
 	RepoAdminChangeTeamAccess bool
+	PackagesAnonymousRead     bool
+	PackagesImmutableVersions bool
 }
 
 // Validate validates the fields
@@ -147,6 +147,9 @@ type RepoSettingForm struct {
 	EnableCloseIssuesViaCommitInAnyBranch bool
 	EnableProjects                        bool
 	EnablePackages                        bool
+	EnablePages                           bool
+	PagesBranch                           string
+	PagesDirectory                        string
 	EnablePulls                           bool
 	PullsIgnoreWhitespace                 bool
 	PullsAllowMerge                       bool
@@ -161,8 +164,17 @@ type RepoSettingForm struct {
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
+	IssueCloseKeywords                    string
+	IssueReopenKeywords                   string
 	IsArchived                            bool
 
+	// Raw Settings
+	RawCORSAllowDomain string `form:"raw_cors_allow_domain"`
+	RawCacheControl    string `form:"raw_cache_control"`
+
+	// Release Package Settings
+	ReleasePackageRules string `form:"release_package_rules"`
+
 	// Signing Settings
 	TrustModel string
 
@@ -244,6 +256,8 @@ type WebhookForm struct {
 	PullRequestSync      bool
 	Repository           bool
 	Package              bool
+	Reaction             bool
+	Digest               bool
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
 }
@@ -242,14 +242,16 @@ func (f *IntrospectTokenForm) Validate(req *http.Request, errs binding.Errors) b
 
 // UpdateProfileForm form for updating profile
 type UpdateProfileForm struct {
-	Name                string `binding:"AlphaDashDot;MaxSize(40)"`
-	FullName            string `binding:"MaxSize(100)"`
-	KeepEmailPrivate    bool
-	Website             string `binding:"ValidSiteUrl;MaxSize(255)"`
-	Location            string `binding:"MaxSize(50)"`
-	Description         string `binding:"MaxSize(255)"`
-	Visibility          structs.VisibleType
-	KeepActivityPrivate bool
+	Name                      string `binding:"AlphaDashDot;MaxSize(40)"`
+	FullName                  string `binding:"MaxSize(100)"`
+	KeepEmailPrivate          bool
+	Website                   string `binding:"ValidSiteUrl;MaxSize(255)"`
+	Location                  string `binding:"MaxSize(50)"`
+	Description               string `binding:"MaxSize(255)"`
+	Visibility                structs.VisibleType
+	KeepActivityPrivate       bool
+	PackagesAnonymousRead     bool
+	PackagesImmutableVersions bool
 }
 
 // Validate validates the fields
@@ -368,7 +370,9 @@ func (f *AddKeyForm) Validate(req *http.Request, errs binding.Errors) binding.Er
 
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
-	Name string `binding:"Required;MaxSize(255)"`
+	Name         string `binding:"Required;MaxSize(255)"`
+	Scope        string
+	PackageOwner string
 }
 
 // Validate validates the fields
@@ -0,0 +1,104 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"image"
+	_ "image/gif"  // for reading the size of gif images
+	_ "image/jpeg" // for reading the size of jpeg images
+	_ "image/png"  // for reading the size of png images
+
+	"code.gitea.io/gitea/modules/git"
+)
+
+// BinaryDiffBlob describes one version of a changed binary file
+type BinaryDiffBlob struct {
+	ID       string
+	Size     int64
+	MimeType string
+	IsImage  bool
+	// Width and Height are only known for raster images in a supported format
+	Width  int
+	Height int
+}
+
+// BinaryDiff describes the change of a binary file by the metadata of both versions.
+// Before is nil for an added file and After is nil for a deleted one.
+type BinaryDiff struct {
+	Before *BinaryDiffBlob
+	After  *BinaryDiffBlob
+}
+
+// SizeChange returns the difference between the size after and the size before the change
+func (d *BinaryDiff) SizeChange() int64 {
+	var change int64
+	if d.After != nil {
+		change += d.After.Size
+	}
+	if d.Before != nil {
+		change -= d.Before.Size
+	}
+	return change
+}
+
+// GetBinaryDiff collects the metadata of both versions of a binary diff file. It returns nil
+// for text files and files stored in LFS, whose blobs only contain the pointer.
+func GetBinaryDiff(baseCommit, headCommit *git.Commit, diffFile *DiffFile) (*BinaryDiff, error) {
+	if !diffFile.IsBin || diffFile.IsLFSFile {
+		return nil, nil
+	}
+
+	diff := &BinaryDiff{}
+	var err error
+	if baseCommit != nil && !diffFile.IsCreated {
+		if diff.Before, err = getBinaryDiffBlob(baseCommit, diffFile.OldName); err != nil {
+			return nil, err
+		}
+	}
+	if headCommit != nil && !diffFile.IsDeleted {
+		if diff.After, err = getBinaryDiffBlob(headCommit, diffFile.Name); err != nil {
+			return nil, err
+		}
+	}
+	if diff.Before == nil && diff.After == nil {
+		return nil, nil
+	}
+	return diff, nil
+}
+
+func getBinaryDiffBlob(commit *git.Commit, treePath string) (*BinaryDiffBlob, error) {
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	st, err := blob.GuessContentType()
+	if err != nil {
+		return nil, err
+	}
+	result := &BinaryDiffBlob{
+		ID:       blob.ID.String(),
+		Size:     blob.Size(),
+		MimeType: st.GetMimeType(),
+		IsImage:  st.IsImage() || st.IsSvgImage(),
+	}
+
+	if st.IsImage() && !st.IsSvgImage() {
+		reader, err := blob.DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		// only the header is read, unknown formats simply have no size
+		if config, _, err := image.DecodeConfig(reader); err == nil {
+			result.Width = config.Width
+			result.Height = config.Height
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBinaryDiff(t *testing.T) {
+	gitRepo, err := git.OpenRepository(git.DefaultContext, "./testdata/academic-module")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gitRepo.Close()
+
+	const (
+		beforeCommitID = "1adb9637ecc62bdf91a35675ac5ccee07dafc28d"
+		afterCommitID  = "11d60131c8fdcf727e7696dd02a140d15dc4628b"
+	)
+	baseCommit, err := gitRepo.GetCommit(beforeCommitID)
+	assert.NoError(t, err)
+	headCommit, err := gitRepo.GetCommit(afterCommitID)
+	assert.NoError(t, err)
+
+	diff, err := GetDiff(gitRepo, &DiffOptions{
+		BeforeCommitID:    beforeCommitID,
+		AfterCommitID:     afterCommitID,
+		MaxLines:          setting.Git.MaxGitDiffLines,
+		MaxLineCharacters: setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:          setting.Git.MaxGitDiffFiles,
+	}, "Resources/views/people/dlc_student/back.jpg", "Resources/lang/en/common.php")
+	if !assert.NoError(t, err) || !assert.Len(t, diff.Files, 2) {
+		return
+	}
+
+	for _, file := range diff.Files {
+		binaryDiff, err := GetBinaryDiff(baseCommit, headCommit, file)
+		assert.NoError(t, err)
+		if file.Name == "Resources/lang/en/common.php" {
+			assert.Nil(t, binaryDiff)
+			continue
+		}
+
+		if !assert.NotNil(t, binaryDiff) {
+			continue
+		}
+		assert.Nil(t, binaryDiff.Before)
+		assert.Equal(t, &BinaryDiffBlob{
+			ID:       "9d9398e5c43a51e5fc105ac80ad3256375228cd8",
+			Size:     103508,
+			MimeType: "image/jpeg",
+			IsImage:  true,
+			Width:    638,
+			Height:   1011,
+		}, binaryDiff.After)
+		assert.EqualValues(t, 103508, binaryDiff.SizeChange())
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrBlobTooLarge is returned if a blob is too large to be diffed
+var ErrBlobTooLarge = errors.New("blob is too large to be diffed")
+
+// BlobDiffSide is one of the two blobs of a blob diff
+type BlobDiffSide struct {
+	// Ref and CommitID are empty if the blob was addressed by its id
+	Ref      string
+	CommitID string
+	TreePath string
+	Blob     *git.Blob
+}
+
+// GetBlobDiffSide resolves a revision of the form `<ref>:<path>` or the full id of a blob in the repository
+func GetBlobDiffSide(gitRepo *git.Repository, revision string) (*BlobDiffSide, error) {
+	ref, treePath, hasPath := strings.Cut(revision, ":")
+	if !hasPath {
+		// the blob might not exist or be another type of object
+		objectType, _, runErr := git.NewCommand(gitRepo.Ctx, "cat-file", "-t", revision).RunStdString(&git.RunOpts{Dir: gitRepo.Path})
+		if runErr != nil || strings.TrimSpace(objectType) != "blob" {
+			return nil, git.ErrNotExist{ID: revision}
+		}
+		blob, err := gitRepo.GetBlob(revision)
+		if err != nil {
+			return nil, err
+		}
+		return &BlobDiffSide{Blob: blob}, nil
+	}
+
+	treePath = strings.TrimPrefix(path.Clean("/"+treePath), "/")
+	if ref == "" || treePath == "" {
+		return nil, git.ErrNotExist{ID: revision}
+	}
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDir() || entry.IsSubModule() {
+		return nil, git.ErrNotExist{ID: revision}
+	}
+	return &BlobDiffSide{
+		Ref:      ref,
+		CommitID: commit.ID.String(),
+		TreePath: treePath,
+		Blob:     entry.Blob(),
+	}, nil
+}
+
+// GetUnifiedBlobDiff returns the unified diff of the content of two blobs, which may belong to different repositories.
+// The diff has the same format as the diff of a file between two commits, an empty diff means the contents are equal.
+func GetUnifiedBlobDiff(ctx context.Context, base, head *BlobDiffSide, contextLines int) ([]byte, error) {
+	for _, side := range []*BlobDiffSide{base, head} {
+		if side.Blob.Size() > setting.UI.MaxDisplayFileSize {
+			return nil, ErrBlobTooLarge
+		}
+	}
+
+	tmpPath, err := os.MkdirTemp(os.TempDir(), "gitea-blob-diff")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory: %w", err)
+	}
+	defer func() {
+		if err := util.RemoveAll(tmpPath); err != nil {
+			log.Error("Unable to remove temporary directory %s: %v", tmpPath, err)
+		}
+	}()
+
+	// git diff --no-index prints the paths as given, so the prefixes are part of the file names
+	basePath := path.Join("a", blobDiffName(base))
+	headPath := path.Join("b", blobDiffName(head))
+	if err := writeBlobToFile(base.Blob, filepath.Join(tmpPath, filepath.FromSlash(basePath))); err != nil {
+		return nil, err
+	}
+	if err := writeBlobToFile(head.Blob, filepath.Join(tmpPath, filepath.FromSlash(headPath))); err != nil {
+		return nil, err
+	}
+
+	if contextLines < 0 {
+		contextLines = 0
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err = git.NewCommand(ctx, "diff", "--no-index", "--no-color", "--no-ext-diff", "--no-textconv", "--no-prefix", "-U"+strconv.Itoa(contextLines), "--", basePath, headPath).
+		Run(&git.RunOpts{
+			Dir:    tmpPath,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+	if err != nil {
+		// the exit code 1 means the files differ
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("unable to diff blobs %s and %s: %w - %s", base.Blob.ID, head.Blob.ID, err, stderr.String())
+		}
+	}
+	return stdout.Bytes(), nil
+}
+
+// GetBlobDiff returns the parsed diff of the content of two blobs, the diff contains no file if the contents are equal
+func GetBlobDiff(ctx context.Context, base, head *BlobDiffSide, contextLines int) (*Diff, error) {
+	patch, err := GetUnifiedBlobDiff(ctx, base, head, contextLines)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatch(setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, 1, bytes.NewReader(patch), "")
+}
+
+// blobDiffName returns the name of the blob in the diff, which is the blob id if it wasn't addressed by a path
+func blobDiffName(side *BlobDiffSide) string {
+	if side.TreePath != "" {
+		return side.TreePath
+	}
+	return side.Blob.ID.String()
+}
+
+func writeBlobToFile(blob *git.Blob, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+	rd, err := blob.DataAsync()
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, rd)
+	return err
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitdiff
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlobDiff(t *testing.T) {
+	gitRepo, err := git.OpenRepository(git.DefaultContext, "./testdata/academic-module")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gitRepo.Close()
+
+	base, err := GetBlobDiffSide(gitRepo, "1adb9637ecc62bdf91a35675ac5ccee07dafc28d:Resources/lang/en/common.php")
+	assert.NoError(t, err)
+	assert.Equal(t, "Resources/lang/en/common.php", base.TreePath)
+	assert.Equal(t, "5000ea7b5c019eaa01b547abf6febabea0f89ed1", base.Blob.ID.String())
+
+	head, err := GetBlobDiffSide(gitRepo, "0fc8c57f5666c25eafbd2796da58ba186468858b")
+	assert.NoError(t, err)
+	assert.Empty(t, head.TreePath)
+
+	for _, revision := range []string{
+		"1adb9637ecc62bdf91a35675ac5ccee07dafc28d:Resources/lang/en/missing.php",
+		"1adb9637ecc62bdf91a35675ac5ccee07dafc28d:Resources",
+		"1adb9637ecc62bdf91a35675ac5ccee07dafc28d",
+		"0000000000000000000000000000000000000001",
+	} {
+		_, err = GetBlobDiffSide(gitRepo, revision)
+		assert.True(t, git.IsErrNotExist(err), revision)
+	}
+
+	patch, err := GetUnifiedBlobDiff(git.DefaultContext, base, base, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, patch)
+
+	patch, err = GetUnifiedBlobDiff(git.DefaultContext, base, head, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, `diff --git a/Resources/lang/en/common.php b/0fc8c57f5666c25eafbd2796da58ba186468858b
+index 5000ea7..0fc8c57 100644
+--- a/Resources/lang/en/common.php
++++ b/0fc8c57f5666c25eafbd2796da58ba186468858b
+@@ -19,2 +19,4 @@ return [
+     'btn-clear'                  => 'Clear',
++    'btn-upload-photo'           => 'Upload photo',
++    'btn-print'                  => 'Print'
+ ];
+`, string(patch))
+
+	diff, err := GetBlobDiff(git.DefaultContext, base, head, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, diff.Files, 1) {
+		file := diff.Files[0]
+		assert.Equal(t, 2, file.Addition)
+		assert.Equal(t, 0, file.Deletion)
+		assert.False(t, file.IsBin)
+	}
+}
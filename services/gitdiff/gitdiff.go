@@ -1082,28 +1082,20 @@ func GetDiff(gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff
 		argsLength += len(files) + 1
 	}
 
+	beforeCommitID, err := getDiffBase(commit, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	diffArgs := make([]string, 0, argsLength)
-	if (len(opts.BeforeCommitID) == 0 || opts.BeforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
-		diffArgs = append(diffArgs, "diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M")
-		if len(opts.WhitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, opts.WhitespaceBehavior)
-		}
-		// append empty tree ref
-		diffArgs = append(diffArgs, "4b825dc642cb6eb9a060e54bf8d69288fbee4904")
-		diffArgs = append(diffArgs, opts.AfterCommitID)
-	} else {
-		actualBeforeCommitID := opts.BeforeCommitID
-		if len(actualBeforeCommitID) == 0 {
-			parentCommit, _ := commit.Parent(0)
-			actualBeforeCommitID = parentCommit.ID.String()
-		}
-		diffArgs = append(diffArgs, "diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M")
-		if len(opts.WhitespaceBehavior) != 0 {
-			diffArgs = append(diffArgs, opts.WhitespaceBehavior)
-		}
-		diffArgs = append(diffArgs, actualBeforeCommitID)
-		diffArgs = append(diffArgs, opts.AfterCommitID)
-		opts.BeforeCommitID = actualBeforeCommitID
+	diffArgs = append(diffArgs, "diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M")
+	if len(opts.WhitespaceBehavior) != 0 {
+		diffArgs = append(diffArgs, opts.WhitespaceBehavior)
+	}
+	diffArgs = append(diffArgs, beforeCommitID)
+	diffArgs = append(diffArgs, opts.AfterCommitID)
+	if beforeCommitID != git.EmptyTreeSHA {
+		opts.BeforeCommitID = beforeCommitID
 	}
 
 	// In git 2.31, git diff learned --skip-to which we can use to shortcut skip to file
@@ -1219,6 +1211,47 @@ func GetDiff(gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff
 	return diff, nil
 }
 
+// getDiffBase returns the revision the diff of `opts` starts from: the before commit if it is set,
+// otherwise the parent of the after commit or the empty tree for a root commit
+func getDiffBase(commit *git.Commit, opts *DiffOptions) (string, error) {
+	if (len(opts.BeforeCommitID) == 0 || opts.BeforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
+		return git.EmptyTreeSHA, nil
+	}
+	if len(opts.BeforeCommitID) != 0 {
+		return opts.BeforeCommitID, nil
+	}
+	parentCommit, err := commit.Parent(0)
+	if err != nil {
+		return "", err
+	}
+	return parentCommit.ID.String(), nil
+}
+
+// GetDiffFileStats returns the changed files of the diff described by `opts` with their number of
+// additions and deletions. Unlike GetDiff no patch is generated, so it is cheap even for diffs with
+// thousands of files and can be used to list them before their hunks are loaded one by one.
+func GetDiffFileStats(gitRepo *git.Repository, opts *DiffOptions, files ...string) ([]*git.DiffFileStat, error) {
+	commit, err := gitRepo.GetCommit(opts.AfterCommitID)
+	if err != nil {
+		return nil, err
+	}
+	beforeCommitID, err := getDiffBase(commit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, len(files)+4)
+	if len(opts.WhitespaceBehavior) != 0 {
+		args = append(args, opts.WhitespaceBehavior)
+	}
+	args = append(args, beforeCommitID, opts.AfterCommitID)
+	if len(files) > 0 {
+		args = append(args, "--")
+		args = append(args, files...)
+	}
+	return git.GetDiffFileStats(gitRepo.Ctx, gitRepo.Path, args...)
+}
+
 // SyncAndGetUserSpecificDiff is like GetDiff, except that user specific data such as which files the given user has already viewed on the given PR will also be set
 // Additionally, the database asynchronously is updated if files have changed since the last review
 func SyncAndGetUserSpecificDiff(ctx context.Context, userID int64, pull *issues_model.PullRequest, gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff, error) {
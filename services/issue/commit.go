@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 const (
@@ -102,20 +103,38 @@ func getIssueFromRef(repo *repo_model.Repository, index int64) (*issues_model.Is
 
 // UpdateIssuesCommit checks if issues are manipulated by commit message.
 func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, commits []*repository.PushCommit, branchName string) error {
+	type markKey struct {
+		ID     int64
+		Action references.XRefAction
+	}
+
+	type statusChange struct {
+		Issue *issues_model.Issue
+		Close bool
+	}
+
+	// Large pushes (e.g. history imports) reference an issue only once per action
+	// instead of once per commit to not flood the issue with references.
+	// Issues closed and reopened by them only change to their final status once to not flood the watchers with notifications.
+	isLargePush := setting.Repository.LargePushThreshold > 0 && len(commits) > setting.Repository.LargePushThreshold
+	refMarked := make(map[markKey]bool)
+	statusChanges := make(map[int64]*statusChange)
+	statusChangeOrder := make([]int64, 0, 10)
+
+	// Commits are parsed with the keywords of the pushed repository, which may be localized
+	keywords := references.NewKeywords(repo.IssueKeywords(db.DefaultContext))
+
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
 
-		type markKey struct {
-			ID     int64
-			Action references.XRefAction
+		if !isLargePush {
+			refMarked = make(map[markKey]bool)
 		}
-
-		refMarked := make(map[markKey]bool)
 		var refRepo *repo_model.Repository
 		var refIssue *issues_model.Issue
 		var err error
-		for _, ref := range references.FindAllIssueReferences(c.Message) {
+		for _, ref := range references.FindAllIssueReferencesWithKeywords(c.Message, keywords) {
 
 			// issue is from another repo
 			if len(ref.Owner) > 0 && len(ref.Name) > 0 {
@@ -144,13 +163,15 @@ func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, comm
 			}
 
 			key := markKey{ID: refIssue.ID, Action: ref.Action}
-			if refMarked[key] {
+			isMarked := refMarked[key]
+			if isMarked && !isLargePush {
 				continue
 			}
 			refMarked[key] = true
 
 			// FIXME: this kind of condition is all over the code, it should be consolidated in a single place
-			canclose := perm.IsAdmin() || perm.IsOwner() || perm.CanWriteIssuesOrPulls(refIssue.IsPull) || refIssue.PosterID == doer.ID
+			// Being the poster of an issue is not enough to close it from the commits of another repository
+			canclose := perm.IsAdmin() || perm.IsOwner() || perm.CanWriteIssuesOrPulls(refIssue.IsPull) || (refIssue.PosterID == doer.ID && refRepo.ID == repo.ID)
 			cancomment := canclose || perm.CanReadIssuesOrPulls(refIssue.IsPull)
 
 			// Don't proceed if the user can't comment
@@ -158,9 +179,11 @@ func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, comm
 				continue
 			}
 
-			message := fmt.Sprintf(`<a href="%s/commit/%s">%s</a>`, html.EscapeString(repo.Link()), html.EscapeString(url.PathEscape(c.Sha1)), html.EscapeString(strings.SplitN(c.Message, "\n", 2)[0]))
-			if err = issues_model.CreateRefComment(doer, refRepo, refIssue, message, c.Sha1); err != nil {
-				return err
+			if !isMarked {
+				message := fmt.Sprintf(`<a href="%s/commit/%s">%s</a>`, html.EscapeString(repo.Link()), html.EscapeString(url.PathEscape(c.Sha1)), html.EscapeString(strings.SplitN(c.Message, "\n", 2)[0]))
+				if err = issues_model.CreateRefComment(doer, refRepo, refIssue, message, c.Sha1); err != nil {
+					return err
+				}
 			}
 
 			// Only issues can be closed/reopened this way, and user needs the correct permissions
@@ -173,9 +196,15 @@ func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, comm
 				continue
 			}
 
+			// Issues of other repositories can only be closed/reopened if they belong to the same owner
+			if refRepo.ID != repo.ID && refRepo.OwnerID != repo.OwnerID {
+				continue
+			}
+
 			if !repo.CloseIssuesViaCommitInAnyBranch {
 				// If the issue was specified to be in a particular branch, don't allow commits in other branches to close it
-				if refIssue.Ref != "" {
+				// The branch of an issue in another repository refers to that repository, so only the default branch applies
+				if refIssue.Ref != "" && refRepo.ID == repo.ID {
 					if branchName != refIssue.Ref {
 						continue
 					}
@@ -190,6 +219,14 @@ func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, comm
 					return err
 				}
 			}
+			if isLargePush {
+				refIssue.Repo = refRepo
+				if _, ok := statusChanges[refIssue.ID]; !ok {
+					statusChangeOrder = append(statusChangeOrder, refIssue.ID)
+				}
+				statusChanges[refIssue.ID] = &statusChange{Issue: refIssue, Close: close}
+				continue
+			}
 			if close != refIssue.IsClosed {
 				refIssue.Repo = refRepo
 				if err := ChangeStatus(refIssue, doer, close); err != nil {
@@ -198,5 +235,14 @@ func UpdateIssuesCommit(doer *user_model.User, repo *repo_model.Repository, comm
 			}
 		}
 	}
+
+	for _, id := range statusChangeOrder {
+		change := statusChanges[id]
+		if change.Close != change.Issue.IsClosed {
+			if err := ChangeStatus(change.Issue, doer, change.Close); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
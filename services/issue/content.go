@@ -5,11 +5,53 @@
 package issue
 
 import (
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/sync"
 )
 
+// issueContentPool serializes the task changes of an issue
+var issueContentPool = sync.NewExclusivePool()
+
+// ErrTaskNotExist represents a "TaskNotExist" kind of error.
+type ErrTaskNotExist struct {
+	IssueID int64
+	Index   int
+}
+
+// IsErrTaskNotExist checks if an error is an ErrTaskNotExist.
+func IsErrTaskNotExist(err error) bool {
+	_, ok := err.(ErrTaskNotExist)
+	return ok
+}
+
+func (err ErrTaskNotExist) Error() string {
+	return fmt.Sprintf("task does not exist [issue_id: %d, index: %d]", err.IssueID, err.Index)
+}
+
+// ErrTaskTextMismatch represents a "TaskTextMismatch" kind of error.
+type ErrTaskTextMismatch struct {
+	IssueID int64
+	Index   int
+	Text    string
+}
+
+// IsErrTaskTextMismatch checks if an error is an ErrTaskTextMismatch.
+func IsErrTaskTextMismatch(err error) bool {
+	_, ok := err.(ErrTaskTextMismatch)
+	return ok
+}
+
+func (err ErrTaskTextMismatch) Error() string {
+	return fmt.Sprintf("text of task differs [issue_id: %d, index: %d, text: %s]", err.IssueID, err.Index, err.Text)
+}
+
 // ChangeContent changes issue content, as the given user.
 func ChangeContent(issue *issues_model.Issue, doer *user_model.User, content string) (err error) {
 	oldContent := issue.Content
@@ -22,3 +64,36 @@ func ChangeContent(issue *issues_model.Issue, doer *user_model.User, content str
 
 	return nil
 }
+
+// SetTaskChecked checks or unchecks the task with the index in the content of the issue, as the given user.
+// The content is reloaded while holding a lock for the issue, so concurrent changes of different tasks don't get lost.
+// If expectedText is not nil, the change is rejected if the text of the task differs.
+func SetTaskChecked(issue *issues_model.Issue, doer *user_model.User, index int, checked bool, expectedText *string) (*markdown.Task, error) {
+	key := strconv.FormatInt(issue.ID, 10)
+	issueContentPool.CheckIn(key)
+	defer issueContentPool.CheckOut(key)
+
+	current, err := issues_model.GetIssueByID(db.DefaultContext, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue.Content = current.Content
+
+	tasks := markdown.ParseTasks(issue.Content)
+	if index < 0 || index >= len(tasks) {
+		return nil, ErrTaskNotExist{IssueID: issue.ID, Index: index}
+	}
+	task := tasks[index]
+	if expectedText != nil && *expectedText != task.Text {
+		return nil, ErrTaskTextMismatch{IssueID: issue.ID, Index: index, Text: task.Text}
+	}
+	if task.Checked == checked {
+		return task, nil
+	}
+
+	if err := ChangeContent(issue, doer, markdown.SetTaskChecked(issue.Content, task, checked)); err != nil {
+		return nil, err
+	}
+	task.Checked = checked
+	return task, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// UnlockExpiredIssues unlocks the issues whose timed lock has expired.
+// The unlock is done in the name of the user who locked the issue.
+func UnlockExpiredIssues(ctx context.Context) error {
+	issues, err := issues_model.FindExpiredIssueLocks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before unlocking issue %d", issue.ID)
+		default:
+		}
+
+		if err := issue.LoadRepo(ctx); err != nil {
+			log.Error("Unable to load repository of issue %d: %v", issue.ID, err)
+			continue
+		}
+
+		doer := user_model.NewGhostUser()
+		lockLog, err := issues_model.GetLatestModerationLog(ctx, issue.ID, issues_model.ModerationActionLock)
+		if err != nil {
+			log.Error("Unable to get the lock of issue %d: %v", issue.ID, err)
+			continue
+		}
+		if lockLog != nil {
+			if u, err := user_model.GetUserByIDCtx(ctx, lockLog.DoerID); err == nil {
+				doer = u
+			} else if !user_model.IsErrUserNotExist(err) {
+				log.Error("Unable to get user %d: %v", lockLog.DoerID, err)
+				continue
+			}
+		}
+
+		if err := issues_model.UnlockIssue(&issues_model.IssueLockOptions{
+			Doer:  doer,
+			Issue: issue,
+		}); err != nil {
+			log.Error("Unable to unlock issue %d: %v", issue.ID, err)
+		}
+	}
+
+	return nil
+}
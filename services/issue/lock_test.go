@@ -0,0 +1,50 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockExpiredIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	expired := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, expired.LoadRepo(db.DefaultContext))
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{
+		Doer:  doer,
+		Issue: expired,
+		Until: timeutil.TimeStampNow() - 60,
+	}))
+
+	running := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, running.LoadRepo(db.DefaultContext))
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{
+		Doer:  doer,
+		Issue: running,
+		Until: timeutil.TimeStampNow() + 3600,
+	}))
+
+	assert.NoError(t, UnlockExpiredIssues(db.DefaultContext))
+
+	expired = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.False(t, expired.IsLocked)
+	assert.EqualValues(t, 0, expired.LockedUntilUnix)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: expired.ID, Type: issues_model.CommentTypeUnlock, PosterID: doer.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.ModerationLog{IssueID: expired.ID, Action: issues_model.ModerationActionUnlock, DoerID: doer.ID})
+
+	running = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.True(t, running.IsLocked)
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// CreateReaction creates a reaction on an issue, or on one of its comments if comment isn't nil, and notifies the receivers.
+// Like issues_model.CreateReaction the existing reaction is returned together with ErrReactionAlreadyExist.
+func CreateReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, content string) (*issues_model.Reaction, error) {
+	opts := &issues_model.ReactionOptions{
+		Type:    content,
+		DoerID:  doer.ID,
+		IssueID: issue.ID,
+	}
+	if comment != nil {
+		opts.CommentID = comment.ID
+	}
+
+	reaction, err := issues_model.CreateReaction(opts)
+	if err != nil {
+		return reaction, err
+	}
+
+	notification.NotifyCreateReaction(doer, issue, comment, reaction)
+
+	return reaction, nil
+}
+
+// DeleteReaction deletes a reaction of the doer on an issue, or on one of its comments if comment isn't nil.
+// The receivers are only notified if the reaction existed.
+func DeleteReaction(doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, content string) error {
+	findOpts := issues_model.FindReactionsOptions{
+		IssueID:  issue.ID,
+		UserID:   doer.ID,
+		Reaction: content,
+		// issue reactions have no comment
+		CommentID: -1,
+	}
+	if comment != nil {
+		findOpts.CommentID = comment.ID
+	}
+	reactions, _, err := issues_model.FindReactions(db.DefaultContext, findOpts)
+	if err != nil {
+		return err
+	}
+
+	if comment != nil {
+		err = issues_model.DeleteCommentReaction(doer.ID, issue.ID, comment.ID, content)
+	} else {
+		err = issues_model.DeleteIssueReaction(doer.ID, issue.ID, content)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(reactions) > 0 {
+		notification.NotifyDeleteReaction(doer, issue, comment, reactions[0])
+	}
+	return nil
+}
@@ -41,10 +41,21 @@ const (
 	mailAuthResetPassword  base.TplName = "auth/reset_passwd"
 	mailAuthRegisterNotify base.TplName = "auth/register_notify"
 
-	mailNotifyCollaborator base.TplName = "notify/collaborator"
+	mailNotifyCollaborator         base.TplName = "notify/collaborator"
+	mailNotifyCollaboratorExpiring base.TplName = "notify/collaborator_expiring"
+	mailNotifyCollaboratorExpired  base.TplName = "notify/collaborator_expired"
 
 	mailRepoTransferNotify base.TplName = "notify/repo_transfer"
 
+	mailNotifyKeyExpiring           base.TplName = "notify/key_expiring"
+	mailNotifyCredentialProvisioned base.TplName = "notify/credential_provisioned"
+	mailNotifyTwoFactorRecovery     base.TplName = "notify/twofa_recovery"
+
+	mailNotifyNameClaim base.TplName = "notify/name_claim"
+
+	mailOrgJoinRequest         base.TplName = "notify/org_join_request"
+	mailOrgJoinRequestReviewed base.TplName = "notify/org_join_request_reviewed"
+
 	// There's no actual limit for subject in RFC 5322
 	mailMaxSubjectRunes = 256
 )
@@ -0,0 +1,234 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendKeyExpiringMail reminds the user that a SSH key expires soon.
+// If repo is set, the key is a deploy key of the repository.
+func SendKeyExpiringMail(u *user_model.User, keyName string, expires timeutil.TimeStamp, repo *repo_model.Repository) error {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+
+	var subject, repoName, link string
+	if repo != nil {
+		repoName = repo.FullName()
+		subject = locale.Tr("mail.deploy_key.expiring.subject", keyName, repoName)
+		link = repo.HTMLURL() + "/settings/keys"
+	} else {
+		subject = locale.Tr("mail.key.expiring.subject", keyName)
+		link = setting.AppURL + "user/settings/keys"
+	}
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"KeyName":  keyName,
+		"RepoName": repoName,
+		"Expires":  expires.FormatDate(),
+		"Link":     link,
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyKeyExpiring), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, key expiry", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
+
+// RemindExpiringKeys reminds the owners of SSH keys and the owners of repositories with deploy keys
+// which expire within remindBefore. Every key is only reminded about once per expiry.
+func RemindExpiringKeys(ctx context.Context, remindBefore time.Duration) error {
+	if remindBefore <= 0 {
+		return nil
+	}
+
+	before := timeutil.TimeStampNow().AddDuration(remindBefore)
+
+	keys, err := asymkey_model.FindExpiringPublicKeys(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before reminding key %d", key.ID)
+		default:
+		}
+
+		u, err := user_model.GetUserByIDCtx(ctx, key.OwnerID)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			return err
+		}
+		if u != nil {
+			if err := SendKeyExpiringMail(u, key.Name, key.ExpiresUnix, nil); err != nil {
+				log.Error("SendKeyExpiringMail: %v", err)
+			}
+		}
+
+		if err := asymkey_model.MarkPublicKeyReminded(ctx, key.ID); err != nil {
+			return err
+		}
+	}
+
+	deployKeys, err := asymkey_model.FindExpiringDeployKeys(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, key := range deployKeys {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before reminding deploy key %d", key.ID)
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByIDCtx(ctx, key.RepoID)
+		if err != nil && !repo_model.IsErrRepoNotExist(err) {
+			return err
+		}
+		if repo != nil {
+			users, err := getRepoKeyAdmins(ctx, repo)
+			if err != nil {
+				return err
+			}
+			for _, u := range users {
+				if err := SendKeyExpiringMail(u, key.Name, key.ExpiresUnix, repo); err != nil {
+					log.Error("SendKeyExpiringMail: %v", err)
+				}
+			}
+		}
+
+		if err := asymkey_model.MarkDeployKeyReminded(ctx, key.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRepoKeyAdmins returns the users who manage the deploy keys of the repository:
+// the owner of a personal repository or the owners of an organization
+func getRepoKeyAdmins(ctx context.Context, repo *repo_model.Repository) ([]*user_model.User, error) {
+	if err := repo.GetOwner(ctx); err != nil {
+		return nil, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return []*user_model.User{repo.Owner}, nil
+	}
+
+	team, err := organization.GetOwnerTeam(ctx, repo.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := team.GetMembersCtx(ctx); err != nil {
+		return nil, err
+	}
+	return team.Members, nil
+}
+
+// SendCredentialProvisionedMail informs the user that an administrator added a SSH key, a GPG key
+// or an access token to the account. kind is one of "ssh_key", "gpg_key" and "token".
+func SendCredentialProvisionedMail(u, doer *user_model.User, kind, name string) error {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	kindName := locale.Tr("mail.credential.kind." + kind)
+	subject := locale.Tr("mail.credential.provisioned.subject", kindName)
+
+	link := setting.AppURL + "user/settings/keys"
+	if kind == "token" {
+		link = setting.AppURL + "user/settings/applications"
+	}
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"Doer":     doer,
+		"Kind":     kindName,
+		"Name":     name,
+		"Link":     link,
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyCredentialProvisioned), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, provisioned %s", u.ID, kind)
+
+	SendAsync(msg)
+	return nil
+}
+
+// SendTwoFactorRecoveryMail informs the user that an administrator issued a two-factor recovery code for the account
+func SendTwoFactorRecoveryMail(u, doer *user_model.User, expires timeutil.TimeStamp) error {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	subject := locale.Tr("mail.twofa_recovery.subject")
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"Doer":     doer,
+		"Expires":  expires.FormatLong(),
+		"Link":     setting.AppURL + "user/settings/security",
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyTwoFactorRecovery), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, two-factor recovery code issued", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
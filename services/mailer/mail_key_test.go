@@ -0,0 +1,44 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"testing"
+	"time"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemindExpiringKeys(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	now := timeutil.TimeStampNow()
+
+	// the key expires after the reminder period
+	assert.NoError(t, asymkey_model.SetPublicKeyExpiry(db.DefaultContext, 1, now.Add(48*3600)))
+	assert.NoError(t, RemindExpiringKeys(db.DefaultContext, 24*time.Hour))
+
+	key := unittest.AssertExistsAndLoadBean(t, &asymkey_model.PublicKey{ID: 1})
+	assert.Zero(t, key.RemindedUnix)
+	assert.False(t, key.IsExpired())
+
+	assert.NoError(t, asymkey_model.SetPublicKeyExpiry(db.DefaultContext, 1, now.Add(3600)))
+	assert.NoError(t, RemindExpiringKeys(db.DefaultContext, 24*time.Hour))
+
+	key = unittest.AssertExistsAndLoadBean(t, &asymkey_model.PublicKey{ID: 1})
+	assert.NotZero(t, key.RemindedUnix)
+
+	// a new expiry resets the reminder
+	assert.NoError(t, asymkey_model.SetPublicKeyExpiry(db.DefaultContext, 1, now.Add(-60)))
+
+	key = unittest.AssertExistsAndLoadBean(t, &asymkey_model.PublicKey{ID: 1})
+	assert.Zero(t, key.RemindedUnix)
+	assert.True(t, key.IsExpired())
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendNameClaimMail informs a dormant user that the name of the account will be released
+// at the end of the grace period unless the user signs in.
+func SendNameClaimMail(u *user_model.User, claim *user_model.NameClaim) error {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	subject := locale.Tr("mail.name_claim.subject", u.Name)
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"Name":     u.Name,
+		"Deadline": claim.DeadlineUnix.FormatDate(),
+		"Link":     setting.AppURL + "user/login",
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyNameClaim), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, name claim", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
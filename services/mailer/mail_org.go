@@ -0,0 +1,106 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendOrgJoinRequestMail notifies the owners of the organization about a new join request
+func SendOrgJoinRequestMail(requester *user_model.User, org *organization.Organization, team *organization.Team, jr *organization.JoinRequest, owners []*user_model.User) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	langMap := make(map[string][]string)
+	for _, owner := range owners {
+		if !owner.IsActive {
+			// don't send emails to inactive users
+			continue
+		}
+		langMap[owner.Language] = append(langMap[owner.Language], owner.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+
+		subject := locale.Tr("mail.org.join_request.subject", requester.DisplayName(), org.DisplayName())
+		if team != nil {
+			subject = locale.Tr("mail.org.join_request.subject_team", requester.DisplayName(), team.Name, org.DisplayName())
+		}
+
+		data := map[string]interface{}{
+			"Subject":  subject,
+			"Org":      org.DisplayName(),
+			"Message":  jr.Message,
+			"Link":     org.HTMLURL(),
+			"Language": locale.Language(),
+			// helper
+			"locale":    locale,
+			"Str2html":  templates.Str2html,
+			"DotEscape": templates.DotEscape,
+		}
+
+		var content bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&content, string(mailOrgJoinRequest), data); err != nil {
+			return err
+		}
+
+		msg := NewMessage(tos, subject, content.String())
+		msg.Info = fmt.Sprintf("OrgID: %d, join request %d", org.ID, jr.ID)
+
+		SendAsync(msg)
+	}
+	return nil
+}
+
+// SendOrgJoinRequestReviewedMail notifies the requester about the review of the join request
+func SendOrgJoinRequestReviewedMail(requester *user_model.User, org *organization.Organization, team *organization.Team, jr *organization.JoinRequest) error {
+	if setting.MailService == nil || !requester.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(requester.Language)
+
+	subject := locale.Tr("mail.org.join_request.rejected.subject", org.DisplayName())
+	teamName := ""
+	if jr.Status == organization.JoinRequestStatusApproved {
+		subject = locale.Tr("mail.org.join_request.approved.subject", org.DisplayName())
+		if team != nil {
+			teamName = team.Name
+		}
+	}
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"Team":     teamName,
+		"Link":     org.HTMLURL(),
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailOrgJoinRequestReviewed), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{requester.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, join request %d reviewed", requester.ID, jr.ID)
+
+	SendAsync(msg)
+	return nil
+}
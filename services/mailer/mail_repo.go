@@ -14,6 +14,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/translation"
 )
 
@@ -89,3 +90,45 @@ func sendRepoTransferNotifyMailPerLang(lang string, newOwner, doer *user_model.U
 	SendAsync(msg)
 	return nil
 }
+
+// SendCollaboratorExpiryMail reminds the collaborator that the access to the repository expires soon
+// or notifies about the revoked access if the collaboration expired already
+func SendCollaboratorExpiryMail(u *user_model.User, repo *repo_model.Repository, expires timeutil.TimeStamp, expired bool) error {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	repoName := repo.FullName()
+
+	tpl := mailNotifyCollaboratorExpiring
+	subject := locale.Tr("mail.repo.collaborator.expiring.subject", repoName)
+	if expired {
+		tpl = mailNotifyCollaboratorExpired
+		subject = locale.Tr("mail.repo.collaborator.expired.subject", repoName)
+	}
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"RepoName": repoName,
+		"Expires":  expires.FormatDate(),
+		"Link":     repo.HTMLURL(),
+		"Language": locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(tpl), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, collaborator expiry", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
@@ -7,6 +7,8 @@ package migrations
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -314,9 +316,14 @@ func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 				if rc == nil {
 					return nil
 				}
-				_, err = storage.Attachments.Save(attach.RelativePath(), rc, int64(*asset.Size))
+				hash := sha256.New()
+				_, err = storage.Attachments.Save(attach.RelativePath(), io.TeeReader(rc, hash), int64(*asset.Size))
 				rc.Close()
-				return err
+				if err != nil {
+					return err
+				}
+				attach.HashSHA256 = hex.EncodeToString(hash.Sum(nil))
+				return nil
 			}()
 			if err != nil {
 				return err
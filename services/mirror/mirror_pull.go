@@ -497,6 +497,9 @@ func SyncPullMirror(ctx context.Context, repoID int64) bool {
 		}
 
 		theCommits.CompareURL = m.Repo.ComposeCompareURL(oldCommitID, newCommitID)
+		if theCommits.IsLargePush() {
+			theCommits.CommitsURL = m.Repo.ComposeCommitsURL(newCommitID)
+		}
 
 		notification.NotifySyncPushCommits(m.Repo.MustOwner(), m.Repo, &repo_module.PushUpdateOptions{
 			RefFullName: result.refName,
@@ -0,0 +1,136 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"context"
+	"errors"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+var (
+	// ErrAlreadyMember is returned if the user is already a member of the organization or team
+	ErrAlreadyMember = errors.New("user is already a member")
+	// ErrJoinRequestTeamRequired is returned if a request without team gets approved without choosing a team
+	ErrJoinRequestTeamRequired = errors.New("a team must be chosen to approve the join request")
+	// ErrJoinRequestReviewed is returned if the join request is not pending anymore
+	ErrJoinRequestReviewed = errors.New("join request was already reviewed")
+)
+
+// RequestToJoin creates a join request of the user for the organization or one of its teams and notifies the owners
+func RequestToJoin(ctx context.Context, doer *user_model.User, org *organization.Organization, team *organization.Team, message string) (*organization.JoinRequest, error) {
+	var (
+		isMember bool
+		err      error
+		teamID   int64
+	)
+	if team != nil {
+		teamID = team.ID
+		isMember, err = organization.IsTeamMember(ctx, org.ID, team.ID, doer.ID)
+	} else {
+		isMember, err = organization.IsOrganizationMember(ctx, org.ID, doer.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, ErrAlreadyMember
+	}
+
+	jr := &organization.JoinRequest{
+		OrgID:   org.ID,
+		TeamID:  teamID,
+		UserID:  doer.ID,
+		Message: message,
+	}
+	if err := organization.CreateJoinRequest(ctx, jr); err != nil {
+		return nil, err
+	}
+
+	owners, err := organization.GetOwnerTeam(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := owners.GetMembersCtx(ctx); err != nil {
+		return nil, err
+	}
+	if err := mailer.SendOrgJoinRequestMail(doer, org, team, jr, owners.Members); err != nil {
+		log.Error("SendOrgJoinRequestMail: %v", err)
+	}
+
+	return jr, nil
+}
+
+// ApproveJoinRequest adds the requester to the team and notifies the requester.
+// The team is required if the request was not made for a specific team.
+func ApproveJoinRequest(ctx context.Context, doer *user_model.User, org *organization.Organization, jr *organization.JoinRequest, team *organization.Team) error {
+	if !jr.IsPending() {
+		return ErrJoinRequestReviewed
+	}
+
+	if team == nil {
+		if jr.TeamID == 0 {
+			return ErrJoinRequestTeamRequired
+		}
+
+		var err error
+		team, err = organization.GetTeamByID(ctx, jr.TeamID)
+		if err != nil {
+			return err
+		}
+	}
+	if team.OrgID != org.ID {
+		return organization.ErrTeamNotExist{OrgID: org.ID, TeamID: team.ID}
+	}
+
+	requester, err := user_model.GetUserByIDCtx(ctx, jr.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := models.AddTeamMember(team, requester.ID); err != nil {
+		return err
+	}
+
+	jr.TeamID = team.ID
+	jr.Status = organization.JoinRequestStatusApproved
+	jr.ReviewerID = doer.ID
+	if err := organization.UpdateJoinRequestStatus(ctx, jr); err != nil {
+		return err
+	}
+
+	if err := mailer.SendOrgJoinRequestReviewedMail(requester, org, team, jr); err != nil {
+		log.Error("SendOrgJoinRequestReviewedMail: %v", err)
+	}
+	return nil
+}
+
+// RejectJoinRequest rejects the join request and notifies the requester
+func RejectJoinRequest(ctx context.Context, doer *user_model.User, org *organization.Organization, jr *organization.JoinRequest) error {
+	if !jr.IsPending() {
+		return ErrJoinRequestReviewed
+	}
+
+	requester, err := user_model.GetUserByIDCtx(ctx, jr.UserID)
+	if err != nil {
+		return err
+	}
+
+	jr.Status = organization.JoinRequestStatusRejected
+	jr.ReviewerID = doer.ID
+	if err := organization.UpdateJoinRequestStatus(ctx, jr); err != nil {
+		return err
+	}
+
+	if err := mailer.SendOrgJoinRequestReviewedMail(requester, org, nil, jr); err != nil {
+		log.Error("SendOrgJoinRequestReviewedMail: %v", err)
+	}
+	return nil
+}
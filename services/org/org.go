@@ -40,6 +40,22 @@ func DeleteOrganization(org *organization.Organization) error {
 		return models.ErrUserOwnPackages{UID: org.ID}
 	}
 
+	if err := packages_model.DeletePackageSharesByOrgID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeletePackageSharesByOrgID: %v", err)
+	}
+
+	if err := packages_model.DeleteMirrorsByOwner(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteMirrorsByOwner: %v", err)
+	}
+
+	if err := packages_model.DeleteVendorGrantsByOwner(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteVendorGrantsByOwner: %v", err)
+	}
+
+	if err := packages_model.DeleteContainerDomainByOwner(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteContainerDomainByOwner: %v", err)
+	}
+
 	if err := organization.DeleteOrganization(ctx, org); err != nil {
 		return fmt.Errorf("DeleteOrganization: %v", err)
 	}
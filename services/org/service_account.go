@@ -0,0 +1,53 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	user_service "code.gitea.io/gitea/services/user"
+)
+
+// CreateServiceAccount creates a service account owned by the organization. It has no password
+// and uses a no-reply email address, it can only authenticate with access tokens.
+// The service account gets access to repositories by being added to teams.
+func CreateServiceAccount(ctx context.Context, org *organization.Organization, doer, u *user_model.User) error {
+	u.Type = user_model.UserTypeBot
+	u.Email = fmt.Sprintf("%s@%s", strings.ToLower(u.Name), setting.Service.NoReplyAddress)
+
+	if err := user_model.CreateUser(u, &user_model.CreateUserOverwriteOptions{
+		IsActive:         util.OptionalBoolTrue,
+		KeepEmailPrivate: util.OptionalBoolTrue,
+	}); err != nil {
+		return err
+	}
+
+	return organization.AddServiceAccount(ctx, &organization.ServiceAccount{
+		OrgID:     org.ID,
+		UserID:    u.ID,
+		CreatorID: doer.ID,
+	})
+}
+
+// DeleteServiceAccount removes the service account from the teams it was added to and deletes it
+func DeleteServiceAccount(ctx context.Context, u *user_model.User) error {
+	ous, err := organization.GetOrgUsersByUserID(u.ID, &organization.SearchOrganizationsOptions{All: true})
+	if err != nil {
+		return err
+	}
+	for _, ou := range ous {
+		if err := models.RemoveOrgUser(ou.OrgID, u.ID); err != nil {
+			return err
+		}
+	}
+	return user_service.DeleteUser(ctx, u, false)
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+// GetOwnerAccessMode returns the access mode the doer has on the packages of the owner
+func GetOwnerAccessMode(ctx context.Context, doer, owner *user_model.User) (perm.AccessMode, error) {
+	if doer == nil || doer.IsGhost() {
+		if owner.Visibility == structs.VisibleTypePublic {
+			return perm.AccessModeRead, nil
+		}
+		return perm.AccessModeNone, nil
+	}
+
+	if owner.IsOrganization() {
+		org := organization.OrgFromUser(owner)
+
+		mode, err := org.GetOrgUserMaxAuthorizeLevel(doer.ID)
+		if err != nil {
+			return perm.AccessModeNone, err
+		}
+		if mode < perm.AccessModeWrite {
+			teams, err := organization.GetUserOrgTeams(ctx, org.ID, doer.ID)
+			if err != nil {
+				return perm.AccessModeNone, err
+			}
+			for _, t := range teams {
+				if teamMode := t.UnitAccessModeCtx(ctx, unit.TypePackages); mode < teamMode {
+					mode = teamMode
+				}
+			}
+		}
+		if mode == perm.AccessModeNone && organization.HasOrgOrUserVisible(ctx, owner, doer) {
+			mode = perm.AccessModeRead
+		}
+		return mode, nil
+	}
+
+	if doer.ID == owner.ID {
+		return perm.AccessModeOwner, nil
+	}
+	if owner.Visibility == structs.VisibleTypePublic || owner.Visibility == structs.VisibleTypeLimited {
+		return perm.AccessModeRead, nil
+	}
+	return perm.AccessModeNone, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"net"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+)
+
+// RecordAudit adds the entry to the audit log.
+// The actor is taken from doer, the client address and the user agent from the request stored in the context.
+// Failures are logged only, the audited operation has already happened.
+func RecordAudit(ctx context.Context, doer *user_model.User, entry *packages_model.PackageAuditLog) {
+	if doer != nil {
+		entry.ActorID = doer.ID
+		entry.ActorName = doer.Name
+	}
+
+	entry.IP = packages_module.RemoteAddrFromContext(ctx)
+	if host, _, err := net.SplitHostPort(entry.IP); err == nil {
+		entry.IP = host
+	}
+	entry.UserAgent = packages_module.UserAgentFromContext(ctx)
+
+	if err := packages_model.InsertAuditLog(ctx, entry); err != nil {
+		log.Error("Error recording %s of package %s/%s in the audit log: %v", entry.Action, entry.PackageType, entry.PackageName, err)
+	}
+}
+
+// RecordVersionAudit adds an operation on a package version to the audit log
+func RecordVersionAudit(ctx context.Context, doer *user_model.User, action packages_model.AuditAction, pd *packages_model.PackageDescriptor, filename, detail string) {
+	RecordAudit(ctx, doer, &packages_model.PackageAuditLog{
+		OwnerID:     pd.Package.OwnerID,
+		Action:      action,
+		PackageType: pd.Package.Type,
+		PackageName: pd.Package.Name,
+		Version:     pd.Version.Version,
+		Filename:    filename,
+		Detail:      detail,
+	})
+}
+
+func recordPublishAudit(ctx context.Context, doer *user_model.User, pi *PackageInfo, pf *packages_model.PackageFile) {
+	RecordAudit(ctx, doer, &packages_model.PackageAuditLog{
+		OwnerID:     pi.Owner.ID,
+		Action:      packages_model.AuditActionPublish,
+		PackageType: pi.PackageType,
+		PackageName: pi.Name,
+		Version:     pi.Version,
+		Filename:    pf.Name,
+	})
+}
+
+// RecordPackageAudit adds an operation on a package which doesn't affect a single version to the audit log
+func RecordPackageAudit(ctx context.Context, doer *user_model.User, action packages_model.AuditAction, p *packages_model.Package, detail string) {
+	RecordAudit(ctx, doer, &packages_model.PackageAuditLog{
+		OwnerID:     p.OwnerID,
+		Action:      action,
+		PackageType: p.Type,
+		PackageName: p.Name,
+		Detail:      detail,
+	})
+}
+
+// RecordDownloadAudit adds the download of the package file to the audit log
+func RecordDownloadAudit(ctx context.Context, doer *user_model.User, pf *packages_model.PackageFile) {
+	pv, err := packages_model.GetVersionByID(ctx, pf.VersionID)
+	if err != nil {
+		log.Error("Error getting package version %d: %v", pf.VersionID, err)
+		return
+	}
+	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+	if err != nil {
+		log.Error("Error getting package %d: %v", pv.PackageID, err)
+		return
+	}
+
+	RecordAudit(ctx, doer, &packages_model.PackageAuditLog{
+		OwnerID:     p.OwnerID,
+		Action:      packages_model.AuditActionDownload,
+		PackageType: p.Type,
+		PackageName: p.Name,
+		Version:     pv.Version,
+		Filename:    pf.Name,
+	})
+}
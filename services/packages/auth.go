@@ -10,26 +10,33 @@ import (
 	"strings"
 	"time"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-type packageClaims struct {
+// PackageClaims are the claims of a package authorization token
+type PackageClaims struct {
 	jwt.RegisteredClaims
-	UserID int64
+	UserID  int64
+	Scope   auth_model.AccessTokenScope `json:",omitempty"`
+	OwnerID int64                       `json:",omitempty"`
 }
 
-func CreateAuthorizationToken(u *user_model.User) (string, error) {
+// CreateAuthorizationToken creates a token for the user which keeps the restrictions of the access token used to log in
+func CreateAuthorizationToken(u *user_model.User, scope auth_model.AccessTokenScope, ownerID int64) (string, error) {
 	now := time.Now()
 
-	claims := packageClaims{
+	claims := PackageClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		UserID: u.ID,
+		UserID:  u.ID,
+		Scope:   scope,
+		OwnerID: ownerID,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -41,26 +48,27 @@ func CreateAuthorizationToken(u *user_model.User) (string, error) {
 	return tokenString, nil
 }
 
-func ParseAuthorizationToken(req *http.Request) (int64, error) {
+// ParseAuthorizationToken parses the package authorization token of the request
+func ParseAuthorizationToken(req *http.Request) (*PackageClaims, error) {
 	parts := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("no token")
+		return nil, fmt.Errorf("no token")
 	}
 
-	token, err := jwt.ParseWithClaims(parts[1], &packageClaims{}, func(t *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(parts[1], &PackageClaims{}, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
 		return []byte(setting.SecretKey), nil
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	c, ok := token.Claims.(*packageClaims)
+	c, ok := token.Claims.(*PackageClaims)
 	if !token.Valid || !ok {
-		return 0, fmt.Errorf("invalid token claim")
+		return nil, fmt.Errorf("invalid token claim")
 	}
 
-	return c.UserID, nil
+	return c, nil
 }
@@ -0,0 +1,182 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+)
+
+// MetadataChange is a metadata field which differs between two package versions.
+// Nested fields are addressed by their path like `repository.url` or `authors[0].name`.
+type MetadataChange struct {
+	Key      string
+	Status   packages_module.ContentDiffStatus
+	OldValue string
+	NewValue string
+}
+
+// DependencyChange is a dependency which differs between two package versions
+type DependencyChange struct {
+	Kind     packages_model.DependencyKind
+	Name     string
+	Status   packages_module.ContentDiffStatus
+	OldRange string
+	NewRange string
+}
+
+// VersionComparison holds the differences between two versions of a package
+type VersionComparison struct {
+	Metadata     []*MetadataChange
+	Dependencies []*DependencyChange
+	// Files is only set if the contents of the package type can be compared
+	Files          []*packages_module.ContentDiffEntry
+	FilesSupported bool
+}
+
+// CompareVersions compares the metadata, the dependencies and the files of two versions of a package
+func CompareVersions(from, to *packages_model.PackageDescriptor) (*VersionComparison, error) {
+	fromMetadata, err := flattenMetadata(from.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	toMetadata, err := flattenMetadata(to.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &VersionComparison{
+		Metadata:     diffMetadata(fromMetadata, toMetadata),
+		Dependencies: diffDependencies(packages_model.DependenciesFromMetadata(from.Metadata), packages_model.DependenciesFromMetadata(to.Metadata)),
+	}
+
+	c.Files, err = DiffPackageVersions(from, to, false)
+	if err != nil && err != ErrDiffNotSupported {
+		return nil, err
+	}
+	c.FilesSupported = err == nil
+	return c, nil
+}
+
+func diffMetadata(from, to map[string]string) []*MetadataChange {
+	changes := make([]*MetadataChange, 0, 10)
+	for key, oldValue := range from {
+		newValue, has := to[key]
+		if !has {
+			changes = append(changes, &MetadataChange{Key: key, Status: packages_module.ContentDiffStatusRemoved, OldValue: oldValue})
+		} else if newValue != oldValue {
+			changes = append(changes, &MetadataChange{Key: key, Status: packages_module.ContentDiffStatusModified, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for key, newValue := range to {
+		if _, has := from[key]; !has {
+			changes = append(changes, &MetadataChange{Key: key, Status: packages_module.ContentDiffStatusAdded, NewValue: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+func diffDependencies(from, to []*packages_model.PackageDependency) []*DependencyChange {
+	dependencyKey := func(d *packages_model.PackageDependency) string {
+		return string(d.Kind) + "/" + strings.ToLower(d.Name)
+	}
+
+	toByKey := make(map[string]*packages_model.PackageDependency, len(to))
+	for _, d := range to {
+		toByKey[dependencyKey(d)] = d
+	}
+
+	changes := make([]*DependencyChange, 0, 10)
+	for _, d := range from {
+		key := dependencyKey(d)
+		newDep, has := toByKey[key]
+		if !has {
+			changes = append(changes, &DependencyChange{Kind: d.Kind, Name: d.Name, Status: packages_module.ContentDiffStatusRemoved, OldRange: d.VersionRange})
+			continue
+		}
+		delete(toByKey, key)
+		if newDep.VersionRange != d.VersionRange {
+			changes = append(changes, &DependencyChange{Kind: d.Kind, Name: newDep.Name, Status: packages_module.ContentDiffStatusModified, OldRange: d.VersionRange, NewRange: newDep.VersionRange})
+		}
+	}
+	for _, d := range to {
+		if _, has := toByKey[dependencyKey(d)]; has {
+			changes = append(changes, &DependencyChange{Kind: d.Kind, Name: d.Name, Status: packages_module.ContentDiffStatusAdded, NewRange: d.VersionRange})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind > changes[j].Kind // runtime first
+		}
+		return strings.ToLower(changes[i].Name) < strings.ToLower(changes[j].Name)
+	})
+	return changes
+}
+
+// flattenMetadata converts the metadata of a package version into a map of field paths and values
+func flattenMetadata(metadata interface{}) (map[string]string, error) {
+	fields := make(map[string]string)
+	if metadata == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	flattenValue(fields, "", v)
+	return fields, nil
+}
+
+func flattenValue(fields map[string]string, key string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, value := range t {
+			if key != "" {
+				k = key + "." + k
+			}
+			flattenValue(fields, k, value)
+		}
+	case []interface{}:
+		// lists of plain values are compared as a whole
+		values := make([]string, 0, len(t))
+		for i, value := range t {
+			switch value := value.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenValue(fields, fmt.Sprintf("%s[%d]", key, i), value)
+			case nil:
+			case float64:
+				values = append(values, strconv.FormatFloat(value, 'f', -1, 64))
+			default:
+				values = append(values, fmt.Sprint(value))
+			}
+		}
+		if len(values) > 0 {
+			fields[key] = strings.Join(values, ", ")
+		}
+	case nil:
+	case string:
+		if t != "" {
+			fields[key] = t
+		}
+	case float64:
+		fields[key] = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		fields[key] = fmt.Sprint(t)
+	}
+}
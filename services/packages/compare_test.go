@@ -0,0 +1,70 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"testing"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	packages_module "code.gitea.io/gitea/modules/packages"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenMetadata(t *testing.T) {
+	fields, err := flattenMetadata(map[string]interface{}{
+		"description": "test",
+		"empty":       "",
+		"count":       1500000,
+		"keywords":    []string{"a", "b"},
+		"repository":  map[string]interface{}{"type": "git", "url": "https://gitea.io"},
+		"authors":     []map[string]string{{"name": "Gitea"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"description":     "test",
+		"count":           "1500000",
+		"keywords":        "a, b",
+		"repository.type": "git",
+		"repository.url":  "https://gitea.io",
+		"authors[0].name": "Gitea",
+	}, fields)
+
+	fields, err = flattenMetadata(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestDiffMetadata(t *testing.T) {
+	changes := diffMetadata(
+		map[string]string{"description": "old", "license": "MIT", "removed": "value"},
+		map[string]string{"description": "new", "license": "MIT", "added": "value"},
+	)
+	assert.Equal(t, []*MetadataChange{
+		{Key: "added", Status: packages_module.ContentDiffStatusAdded, NewValue: "value"},
+		{Key: "description", Status: packages_module.ContentDiffStatusModified, OldValue: "old", NewValue: "new"},
+		{Key: "removed", Status: packages_module.ContentDiffStatusRemoved, OldValue: "value"},
+	}, changes)
+}
+
+func TestDiffDependencies(t *testing.T) {
+	changes := diffDependencies(
+		[]*packages_model.PackageDependency{
+			{Kind: packages_model.DependencyKindRuntime, Name: "changed", VersionRange: "^1.0"},
+			{Kind: packages_model.DependencyKindRuntime, Name: "removed", VersionRange: "^1.0"},
+			{Kind: packages_model.DependencyKindDevelopment, Name: "same", VersionRange: "^1.0"},
+		},
+		[]*packages_model.PackageDependency{
+			{Kind: packages_model.DependencyKindRuntime, Name: "Changed", VersionRange: "^2.0"},
+			{Kind: packages_model.DependencyKindDevelopment, Name: "same", VersionRange: "^1.0"},
+			{Kind: packages_model.DependencyKindDevelopment, Name: "added"},
+		},
+	)
+	assert.Equal(t, []*DependencyChange{
+		{Kind: packages_model.DependencyKindRuntime, Name: "Changed", Status: packages_module.ContentDiffStatusModified, OldRange: "^1.0", NewRange: "^2.0"},
+		{Kind: packages_model.DependencyKindRuntime, Name: "removed", Status: packages_module.ContentDiffStatusRemoved, OldRange: "^1.0"},
+		{Kind: packages_model.DependencyKindDevelopment, Name: "added", Status: packages_module.ContentDiffStatusAdded},
+	}, changes)
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package composer
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+)
+
+// Vendor returns the vendor of a composer package name like `vendor/package`
+func Vendor(packageName string) string {
+	vendor, _, _ := strings.Cut(packageName, "/")
+	return strings.ToLower(vendor)
+}
+
+// CanPublish checks if the doer may publish the package in the registry of the owner.
+// The vendors of an organization can be reserved for teams, the organization owners and site admins may always publish.
+func CanPublish(ctx context.Context, owner, doer *user_model.User, packageName string) (bool, error) {
+	if !owner.IsOrganization() || doer.IsAdmin {
+		return true, nil
+	}
+
+	isOwner, err := organization.IsOrganizationOwner(ctx, owner.ID, doer.ID)
+	if err != nil || isOwner {
+		return isOwner, err
+	}
+
+	return packages_model.CanPublishVendor(ctx, owner.ID, Vendor(packageName), doer.ID)
+}
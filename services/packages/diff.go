@@ -0,0 +1,102 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"errors"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	packages_module "code.gitea.io/gitea/modules/packages"
+)
+
+// maxDiffContentSize is the maximum size of a text file whose content is compared
+const maxDiffContentSize = 1024 * 1024
+
+// ErrDiffNotSupported indicates that the contents of a package type can't be compared
+var ErrDiffNotSupported = errors.New("comparing the contents of this package type is not supported")
+
+// DiffPackageVersions compares the files contained in two versions of a package.
+// The unified diffs of changed text files are included if withContent is set.
+func DiffPackageVersions(from, to *packages_model.PackageDescriptor, withContent bool) ([]*packages_module.ContentDiffEntry, error) {
+	fromEntries, err := getContentEntries(from, withContent)
+	if err != nil {
+		return nil, err
+	}
+	toEntries, err := getContentEntries(to, withContent)
+	if err != nil {
+		return nil, err
+	}
+	return packages_module.DiffContentEntries(fromEntries, toEntries), nil
+}
+
+// getContentEntries lists the files contained in a package version.
+// The files of generic packages are compared directly, npm and composer packages are archives.
+func getContentEntries(pd *packages_model.PackageDescriptor, withContent bool) ([]*packages_module.ContentEntry, error) {
+	maxContentSize := int64(0)
+	if withContent {
+		maxContentSize = maxDiffContentSize
+	}
+
+	switch pd.Package.Type {
+	case packages_model.TypeGeneric:
+		entries := make([]*packages_module.ContentEntry, 0, len(pd.Files))
+		for _, pfd := range pd.Files {
+			entry, err := readPackageFileEntry(pfd, maxContentSize)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	case packages_model.TypeNpm:
+		pfd := findPackageFile(pd, ".tgz")
+		if pfd == nil {
+			return nil, nil
+		}
+		s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pfd.Blob.HashSHA256))
+		if err != nil {
+			return nil, err
+		}
+		defer s.Close()
+		return packages_module.ReadTarGzEntries(s, true, maxContentSize)
+	case packages_model.TypeComposer:
+		pfd := findPackageFile(pd, ".zip")
+		if pfd == nil {
+			return nil, nil
+		}
+		s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pfd.Blob.HashSHA256))
+		if err != nil {
+			return nil, err
+		}
+		defer s.Close()
+		buf, err := packages_module.CreateHashedBufferFromReader(s, 32*1024*1024)
+		if err != nil {
+			return nil, err
+		}
+		defer buf.Close()
+		return packages_module.ReadZipEntries(buf, buf.Size(), maxContentSize)
+	}
+	return nil, ErrDiffNotSupported
+}
+
+// findPackageFile returns the first package file with the suffix
+func findPackageFile(pd *packages_model.PackageDescriptor, suffix string) *packages_model.PackageFileDescriptor {
+	for _, pfd := range pd.Files {
+		if strings.HasSuffix(pfd.File.LowerName, suffix) {
+			return pfd
+		}
+	}
+	return nil
+}
+
+func readPackageFileEntry(pfd *packages_model.PackageFileDescriptor, maxContentSize int64) (*packages_module.ContentEntry, error) {
+	s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pfd.Blob.HashSHA256))
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	return packages_module.ReadContentEntry(pfd.File.Name, s, maxContentSize)
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"context"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// DownloadStats contains the aggregated download statistics of a package or a package version
+type DownloadStats struct {
+	Since    timeutil.TimeStamp
+	Until    timeutil.TimeStamp
+	Total    int64
+	Days     []*packages_model.DownloadsPerDay
+	Clients  []*packages_model.DownloadsPerClient
+	Files    []*FileDownloads    // only filled for a package version
+	Versions []*VersionDownloads // only filled for a package
+}
+
+// FileDownloads is the number of downloads of a package file
+type FileDownloads struct {
+	File  *packages_model.PackageFile
+	Count int64
+}
+
+// VersionDownloads is the number of downloads of a package version
+type VersionDownloads struct {
+	Version *packages_model.PackageVersion
+	Count   int64
+}
+
+// GetDownloadStats aggregates the downloads of the package version (or all versions of the package if pv is nil)
+// between the start of the day of since (inclusive) and the end of the day of until (inclusive).
+// Days without downloads are filled with zero counts.
+func GetDownloadStats(ctx context.Context, p *packages_model.Package, pv *packages_model.PackageVersion, since, until time.Time) (*DownloadStats, error) {
+	opts := &packages_model.DownloadStatsOptions{
+		PackageID: p.ID,
+		Since:     packages_model.DayStart(since),
+		Until:     packages_model.DayStart(until) + secondsPerDay,
+	}
+	if pv != nil {
+		opts.VersionID = pv.ID
+	}
+
+	days, err := packages_model.GetDownloadsPerDay(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	clients, err := packages_model.GetDownloadsPerClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &DownloadStats{
+		Since:   opts.Since,
+		Until:   opts.Until - secondsPerDay,
+		Days:    make([]*packages_model.DownloadsPerDay, 0, int64(opts.Until-opts.Since)/secondsPerDay),
+		Clients: clients,
+	}
+
+	for day, i := opts.Since, 0; day < opts.Until; day += secondsPerDay {
+		count := int64(0)
+		if i < len(days) && days[i].Day == day {
+			count = days[i].Count
+			i++
+		}
+		stats.Total += count
+		stats.Days = append(stats.Days, &packages_model.DownloadsPerDay{Day: day, Count: count})
+	}
+
+	if pv != nil {
+		perFile, err := packages_model.GetDownloadsPerFile(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+		if err != nil {
+			return nil, err
+		}
+		files := make(map[int64]*packages_model.PackageFile, len(pfs))
+		for _, pf := range pfs {
+			files[pf.ID] = pf
+		}
+
+		stats.Files = make([]*FileDownloads, 0, len(perFile))
+		for _, d := range perFile {
+			// downloads of deleted files are still part of the total
+			if pf, ok := files[d.FileID]; ok {
+				stats.Files = append(stats.Files, &FileDownloads{File: pf, Count: d.Count})
+			}
+		}
+	} else {
+		perVersion, err := packages_model.GetDownloadsPerVersion(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.Versions = make([]*VersionDownloads, 0, len(perVersion))
+		for _, d := range perVersion {
+			v, err := packages_model.GetVersionByID(ctx, d.VersionID)
+			if err != nil {
+				if err == packages_model.ErrPackageNotExist {
+					continue
+				}
+				return nil, err
+			}
+			stats.Versions = append(stats.Versions, &VersionDownloads{Version: v, Count: d.Count})
+		}
+	}
+
+	return stats, nil
+}
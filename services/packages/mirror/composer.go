@@ -0,0 +1,155 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	composer_module "code.gitea.io/gitea/modules/packages/composer"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/hashicorp/go-version"
+)
+
+type composerVersion struct {
+	Version string `json:"version"`
+	Dist    struct {
+		Type   string `json:"type"`
+		URL    string `json:"url"`
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+}
+
+// expandComposerVersions expands the minified metadata format of Composer 2.
+// Every entry only contains the fields which differ from the previous entry, removed fields have the value "__unset".
+func expandComposerVersions(entries []map[string]interface{}) []map[string]interface{} {
+	expanded := make([]map[string]interface{}, 0, len(entries))
+	var previous map[string]interface{}
+	for _, entry := range entries {
+		current := make(map[string]interface{}, len(previous)+len(entry))
+		for k, v := range previous {
+			current[k] = v
+		}
+		for k, v := range entry {
+			if v == "__unset" {
+				delete(current, k)
+			} else {
+				current[k] = v
+			}
+		}
+		expanded = append(expanded, current)
+		previous = current
+	}
+	return expanded
+}
+
+// syncComposerPackage mirrors the versions of a Composer package using the metadata api of Composer 2.
+// Development versions like dev-main are not mirrored.
+func (s *syncer) syncComposerPackage(name string, constraints version.Constraints) error {
+	var metadata struct {
+		Packages map[string][]map[string]interface{} `json:"packages"`
+		Minified string                              `json:"minified"`
+	}
+	if err := s.getJSON(s.upstreamURL("p2/"+strings.ToLower(name)+".json"), &metadata); err != nil {
+		return err
+	}
+
+	var entries []map[string]interface{}
+	for packageName, e := range metadata.Packages {
+		if strings.EqualFold(packageName, name) {
+			entries = e
+			break
+		}
+	}
+	if metadata.Minified != "" {
+		entries = expandComposerVersions(entries)
+	}
+
+	cvs := make(map[string]*composerVersion, len(entries))
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		cv := &composerVersion{}
+		if err := json.Unmarshal(data, cv); err != nil {
+			return err
+		}
+		cvs[cv.Version] = cv
+		versions = append(versions, cv.Version)
+	}
+
+	for _, uv := range matchingVersions(versions, constraints) {
+		has, err := s.hasVersion(name, uv.Version.String())
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+		if err := s.addComposerVersion(name, uv.Version, cvs[uv.Raw]); err != nil {
+			s.addError(name, uv.Raw, err)
+		}
+	}
+	return nil
+}
+
+func (s *syncer) addComposerVersion(name string, v *version.Version, cv *composerVersion) error {
+	if cv.Dist.Type != "zip" || cv.Dist.URL == "" {
+		return fmt.Errorf("no zip archive is available")
+	}
+
+	buf, err := s.download(cv.Dist.URL)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	if cv.Dist.Shasum != "" {
+		_, hashSHA1, _, _ := buf.Sums()
+		if !strings.EqualFold(hex.EncodeToString(hashSHA1), cv.Dist.Shasum) {
+			return fmt.Errorf("checksum mismatch of %s", cv.Dist.URL)
+		}
+	}
+
+	cp, err := composer_module.ParsePackage(buf, buf.Size())
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(cp.Name, name) {
+		return fmt.Errorf("archive contains package %s", cp.Name)
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// the version of the upstream registry wins over the one of the composer.json
+	cp.Version = v.String()
+
+	return s.create(
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Name:    cp.Name,
+				Version: cp.Version,
+			},
+			SemverCompatible: true,
+			Metadata:         cp.Metadata,
+			VersionProperties: map[string]string{
+				composer_module.TypeProperty: cp.Type,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: strings.ToLower(fmt.Sprintf("%s.%s.zip", strings.ReplaceAll(cp.Name, "/", "-"), cp.Version)),
+			},
+			Data: buf,
+		},
+	)
+}
@@ -0,0 +1,314 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/hashicorp/go-version"
+)
+
+// DefaultInterval is the sync interval of mirrors created without an interval
+const DefaultInterval = 24 * time.Hour
+
+// SupportedTypes are the package types which can be mirrored
+var SupportedTypes = []packages_model.Type{packages_model.TypeComposer, packages_model.TypeNpm}
+
+// IsSupportedType checks if packages of the type can be mirrored
+func IsSupportedType(t packages_model.Type) bool {
+	for _, st := range SupportedTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidMirror represents an invalid mirror configuration
+type ErrInvalidMirror struct {
+	Reason string
+}
+
+// IsErrInvalidMirror checks if an error is a ErrInvalidMirror
+func IsErrInvalidMirror(err error) bool {
+	_, ok := err.(ErrInvalidMirror)
+	return ok
+}
+
+func (err ErrInvalidMirror) Error() string {
+	return fmt.Sprintf("invalid package mirror: %s", err.Reason)
+}
+
+// ValidateMirror checks the configuration of the mirror
+func ValidateMirror(m *packages_model.PackageMirror) error {
+	if !IsSupportedType(m.Type) {
+		return ErrInvalidMirror{fmt.Sprintf("packages of type %s can't be mirrored", m.Type)}
+	}
+
+	u, err := url.Parse(m.UpstreamURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidMirror{fmt.Sprintf("upstream url %q is not a valid http(s) url", m.UpstreamURL)}
+	}
+
+	if len(m.Rules) == 0 {
+		return ErrInvalidMirror{"no packages to mirror are selected"}
+	}
+	names := make(map[string]bool, len(m.Rules))
+	for _, rule := range m.Rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.VersionRange = strings.TrimSpace(rule.VersionRange)
+		if rule.Name == "" {
+			return ErrInvalidMirror{"package name must not be empty"}
+		}
+		if names[strings.ToLower(rule.Name)] {
+			return ErrInvalidMirror{fmt.Sprintf("package %s is selected more than once", rule.Name)}
+		}
+		names[strings.ToLower(rule.Name)] = true
+		if _, err := parseVersionRange(rule.VersionRange); err != nil {
+			return ErrInvalidMirror{fmt.Sprintf("invalid version range %q of package %s", rule.VersionRange, rule.Name)}
+		}
+	}
+
+	if m.Interval < setting.Packages.MirrorMinInterval {
+		return ErrInvalidMirror{fmt.Sprintf("interval must be at least %s", setting.Packages.MirrorMinInterval)}
+	}
+	return nil
+}
+
+func parseVersionRange(versionRange string) (version.Constraints, error) {
+	if versionRange == "" {
+		return nil, nil
+	}
+	return version.NewConstraint(versionRange)
+}
+
+// SyncResult is the result of a mirror sync
+type SyncResult struct {
+	// Synced are the package versions added by the sync as name@version
+	Synced []string
+	Errors []string
+}
+
+type syncer struct {
+	ctx     context.Context
+	client  *http.Client
+	mirror  *packages_model.PackageMirror
+	owner   *user_model.User
+	creator *user_model.User
+	result  *SyncResult
+}
+
+func newHTTPClient() *http.Client {
+	allowList := setting.Packages.MirrorAllowedHostList
+	if allowList == "" {
+		allowList = hostmatcher.MatchBuiltinExternal
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:       proxy.Proxy(),
+			DialContext: hostmatcher.NewDialContext("package mirror", hostmatcher.ParseHostMatchList("packages.MIRROR_ALLOWED_HOST_LIST", allowList), nil),
+		},
+	}
+}
+
+// SyncMirror adds the versions of the upstream packages selected by the rules of the mirror which are not present yet.
+// Errors of single packages or versions don't abort the sync, they are reported in the result and stored as the last error of the mirror.
+func SyncMirror(ctx context.Context, m *packages_model.PackageMirror) (*SyncResult, error) {
+	owner, err := user_model.GetUserByIDCtx(ctx, m.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := user_model.GetUserByIDCtx(ctx, m.CreatorID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		creator = user_model.NewGhostUser()
+	}
+
+	s := &syncer{
+		ctx:     ctx,
+		client:  newHTTPClient(),
+		mirror:  m,
+		owner:   owner,
+		creator: creator,
+		result: &SyncResult{
+			Synced: []string{},
+			Errors: []string{},
+		},
+	}
+
+	for _, rule := range m.Rules {
+		constraints, err := parseVersionRange(rule.VersionRange)
+		if err == nil {
+			switch m.Type {
+			case packages_model.TypeComposer:
+				err = s.syncComposerPackage(rule.Name, constraints)
+			case packages_model.TypeNpm:
+				err = s.syncNpmPackage(rule.Name, constraints)
+			default:
+				err = fmt.Errorf("packages of type %s can't be mirrored", m.Type)
+			}
+		}
+		if err != nil {
+			s.addError(rule.Name, "", err)
+		}
+	}
+
+	m.LastSyncUnix = timeutil.TimeStampNow()
+	m.LastError = strings.Join(s.result.Errors, "\n")
+	m.ScheduleNextSync()
+	if err := packages_model.UpdateMirrorCols(ctx, m, "last_sync_unix", "last_error", "next_sync_unix"); err != nil {
+		return nil, err
+	}
+	return s.result, nil
+}
+
+// Update syncs all mirrors whose next sync is due
+func Update(ctx context.Context) error {
+	mirrors, err := packages_model.GetMirrorsToSync(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mirrors {
+		select {
+		case <-ctx.Done():
+			log.Trace("Package mirror sync aborted due to shutdown")
+			return nil
+		default:
+		}
+
+		result, err := SyncMirror(ctx, m)
+		if err != nil {
+			log.Error("Unable to sync package mirror %d: %v", m.ID, err)
+			continue
+		}
+		if len(result.Errors) > 0 {
+			log.Warn("Package mirror %d synced %d versions with %d errors", m.ID, len(result.Synced), len(result.Errors))
+		} else {
+			log.Trace("Package mirror %d synced %d versions", m.ID, len(result.Synced))
+		}
+	}
+	return nil
+}
+
+func (s *syncer) addError(name, version string, err error) {
+	if version != "" {
+		name += "@" + version
+	}
+	// the upstream url may contain credentials
+	s.result.Errors = append(s.result.Errors, util.SanitizeCredentialURLs(fmt.Sprintf("%s: %v", name, err)))
+}
+
+func (s *syncer) get(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+	return resp, nil
+}
+
+func (s *syncer) getJSON(rawURL string, v interface{}) error {
+	resp, err := s.get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (s *syncer) download(rawURL string) (*packages_module.HashedBuffer, error) {
+	resp, err := s.get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return packages_module.CreateHashedBufferFromReader(resp.Body, 32*1024*1024)
+}
+
+func (s *syncer) upstreamURL(p string) string {
+	return strings.TrimSuffix(s.mirror.UpstreamURL, "/") + "/" + p
+}
+
+func (s *syncer) hasVersion(name, version string) (bool, error) {
+	_, err := packages_model.GetVersionByNameAndVersion(s.ctx, s.owner.ID, s.mirror.Type, name, version)
+	if err == packages_model.ErrPackageNotExist {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *syncer) create(pvci *packages_service.PackageCreationInfo, pfci *packages_service.PackageFileCreationInfo) error {
+	pvci.Owner = s.owner
+	pvci.PackageType = s.mirror.Type
+	pvci.Creator = s.creator
+	pfci.IsLead = true
+
+	if _, _, err := packages_service.CreatePackageAndAddFile(s.ctx, pvci, pfci); err != nil {
+		// the version was added since it was checked
+		if err == packages_model.ErrDuplicatePackageVersion {
+			return nil
+		}
+		return err
+	}
+	s.result.Synced = append(s.result.Synced, pvci.Name+"@"+pvci.Version)
+	return nil
+}
+
+type upstreamVersion struct {
+	Raw     string
+	Version *version.Version
+}
+
+// matchingVersions returns the parsable versions matching the constraints in ascending order
+func matchingVersions(versions []string, constraints version.Constraints) []*upstreamVersion {
+	matching := make([]*upstreamVersion, 0, len(versions))
+	for _, raw := range versions {
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+		if constraints != nil && !constraints.Check(v) {
+			continue
+		}
+		matching = append(matching, &upstreamVersion{Raw: raw, Version: v})
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Version.LessThan(matching[j].Version)
+	})
+	return matching
+}
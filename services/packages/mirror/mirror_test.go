@@ -0,0 +1,77 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"testing"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMirror(t *testing.T) {
+	valid := func() *packages_model.PackageMirror {
+		return &packages_model.PackageMirror{
+			Type:        packages_model.TypeNpm,
+			UpstreamURL: "https://registry.npmjs.org",
+			Rules:       []*packages_model.PackageMirrorRule{{Name: " left-pad ", VersionRange: ">= 1.0, < 2"}},
+			Interval:    DefaultInterval,
+		}
+	}
+
+	m := valid()
+	assert.NoError(t, ValidateMirror(m))
+	assert.Equal(t, "left-pad", m.Rules[0].Name)
+
+	for _, modify := range []func(m *packages_model.PackageMirror){
+		func(m *packages_model.PackageMirror) { m.Type = packages_model.TypeMaven },
+		func(m *packages_model.PackageMirror) { m.UpstreamURL = "registry.npmjs.org" },
+		func(m *packages_model.PackageMirror) { m.Rules = nil },
+		func(m *packages_model.PackageMirror) { m.Rules[0].Name = "" },
+		func(m *packages_model.PackageMirror) { m.Rules[0].VersionRange = "^1.0" },
+		func(m *packages_model.PackageMirror) {
+			m.Rules = append(m.Rules, &packages_model.PackageMirrorRule{Name: "Left-Pad"})
+		},
+		func(m *packages_model.PackageMirror) { m.Interval = time.Minute },
+	} {
+		m := valid()
+		modify(m)
+		err := ValidateMirror(m)
+		assert.Error(t, err)
+		assert.True(t, IsErrInvalidMirror(err))
+	}
+}
+
+func TestMatchingVersions(t *testing.T) {
+	constraints, err := parseVersionRange(">= 1.0, < 2")
+	assert.NoError(t, err)
+
+	versions := func(uvs []*upstreamVersion) []string {
+		raw := make([]string, 0, len(uvs))
+		for _, uv := range uvs {
+			raw = append(raw, uv.Raw)
+		}
+		return raw
+	}
+
+	upstream := []string{"1.10.0", "v1.2.0", "2.0.0", "1.5.0-beta", "0.9.0", "dev-main"}
+	assert.Equal(t, []string{"v1.2.0", "1.10.0"}, versions(matchingVersions(upstream, constraints)))
+	assert.Equal(t, []string{"0.9.0", "v1.2.0", "1.5.0-beta", "1.10.0", "2.0.0"}, versions(matchingVersions(upstream, nil)))
+}
+
+func TestExpandComposerVersions(t *testing.T) {
+	expanded := expandComposerVersions([]map[string]interface{}{
+		{"name": "vendor/package", "version": "2.0.0", "description": "Description"},
+		{"version": "1.0.0"},
+		{"version": "0.1.0", "description": "__unset"},
+	})
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "vendor/package", "version": "2.0.0", "description": "Description"},
+		{"name": "vendor/package", "version": "1.0.0", "description": "Description"},
+		{"name": "vendor/package", "version": "0.1.0"},
+	}, expanded)
+}
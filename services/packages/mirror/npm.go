@@ -0,0 +1,146 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	packages_service "code.gitea.io/gitea/services/packages"
+	npm_service "code.gitea.io/gitea/services/packages/npm"
+
+	"github.com/hashicorp/go-version"
+)
+
+// syncNpmPackage mirrors the versions of a npm package and moves the dist-tags to the mirrored versions.
+// If the upstream latest version is not mirrored, the latest tag points to the highest mirrored version.
+func (s *syncer) syncNpmPackage(name string, constraints version.Constraints) error {
+	var packument npm_module.PackageMetadata
+	// scoped packages are requested as @scope%2fname
+	if err := s.getJSON(s.upstreamURL(strings.Replace(name, "/", "%2f", 1)), &packument); err != nil {
+		return err
+	}
+
+	versions := make([]string, 0, len(packument.Versions))
+	for v := range packument.Versions {
+		versions = append(versions, v)
+	}
+
+	mirrored := make(map[string]string)
+	var highest string
+	for _, uv := range matchingVersions(versions, constraints) {
+		meta := packument.Versions[uv.Raw]
+		if meta.Readme == "" {
+			meta.Readme = packument.Readme
+		}
+
+		// npm normalizes the version the same way
+		localVersion := uv.Version.String()
+		has, err := s.hasVersion(name, localVersion)
+		if err != nil {
+			return err
+		}
+		if !has {
+			if err := s.addNpmVersion(meta); err != nil {
+				s.addError(name, uv.Raw, err)
+				continue
+			}
+		}
+		mirrored[uv.Raw] = localVersion
+		highest = localVersion
+	}
+
+	tags := make(map[string]string, len(packument.DistTags))
+	for tag, v := range packument.DistTags {
+		if localVersion, ok := mirrored[v]; ok {
+			tags[tag] = localVersion
+		}
+	}
+	if _, ok := tags["latest"]; !ok && highest != "" {
+		tags["latest"] = highest
+	}
+	for tag, localVersion := range tags {
+		if err := s.setNpmTag(name, localVersion, tag); err != nil {
+			s.addError(name, localVersion, err)
+		}
+	}
+	return nil
+}
+
+func (s *syncer) addNpmVersion(meta *npm_module.PackageMetadataVersion) error {
+	// old packages only have a hex encoded sha1 checksum
+	if meta.Dist.Integrity == "" && meta.Dist.Shasum != "" {
+		if sum, err := hex.DecodeString(meta.Dist.Shasum); err == nil {
+			meta.Dist.Integrity = "sha1-" + base64.StdEncoding.EncodeToString(sum)
+		}
+	}
+
+	resp, err := s.get(meta.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	p, err := npm_module.NewPackage(meta, data)
+	if err != nil {
+		return err
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(p.Data), 32*1024*1024)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	return s.create(
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Name:    p.Name,
+				Version: p.Version,
+			},
+			SemverCompatible: true,
+			Metadata:         p.Metadata,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: p.Filename,
+			},
+			Data: buf,
+		},
+	)
+}
+
+func (s *syncer) setNpmTag(name, localVersion, tag string) error {
+	pv, err := packages_model.GetVersionByNameAndVersion(s.ctx, s.owner.ID, packages_model.TypeNpm, name, localVersion)
+	if err != nil {
+		return err
+	}
+
+	pvps, err := packages_model.GetPropertiesByName(s.ctx, packages_model.PropertyTypeVersion, pv.ID, npm_module.TagProperty)
+	if err != nil {
+		return err
+	}
+	for _, pvp := range pvps {
+		if pvp.Value == tag {
+			return nil
+		}
+	}
+
+	if err := npm_service.SetPackageTag(tag, pv, false); err != nil && err != npm_service.ErrInvalidTagName {
+		return err
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ package packages
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 	"time"
 
@@ -17,12 +19,17 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/notification"
 	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 	container_service "code.gitea.io/gitea/services/packages/container"
 )
 
+// ErrVersionImmutable is returned if a published package version of an owner with immutable versions should be changed
+var ErrVersionImmutable = errors.New("package version is immutable")
+
 // PackageInfo describes a package
 type PackageInfo struct {
 	Owner       *user_model.User
@@ -39,6 +46,7 @@ type PackageCreationInfo struct {
 	Metadata          interface{}
 	PackageProperties map[string]string
 	VersionProperties map[string]string
+	ReleaseNotes      string // markdown, from the package or its changelog
 }
 
 // PackageFileInfo describes a package file
@@ -51,34 +59,42 @@ type PackageFileInfo struct {
 type PackageFileCreationInfo struct {
 	PackageFileInfo
 	Data              packages_module.HashedSizeReader
+	Blob              *packages_model.PackageBlob // existing blob referenced instead of storing Data
 	IsLead            bool
 	Properties        map[string]string
 	OverwriteExisting bool
 }
 
 // CreatePackageAndAddFile creates a package with a file. If the same package exists already, ErrDuplicatePackageVersion is returned
-func CreatePackageAndAddFile(pvci *PackageCreationInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
-	return createPackageAndAddFile(pvci, pfci, false)
+func CreatePackageAndAddFile(ctx context.Context, pvci *PackageCreationInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
+	return createPackageAndAddFile(ctx, pvci, pfci, false)
 }
 
 // CreatePackageOrAddFileToExisting creates a package with a file or adds the file if the package exists already
-func CreatePackageOrAddFileToExisting(pvci *PackageCreationInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
-	return createPackageAndAddFile(pvci, pfci, true)
+func CreatePackageOrAddFileToExisting(ctx context.Context, pvci *PackageCreationInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
+	return createPackageAndAddFile(ctx, pvci, pfci, true)
 }
 
-func createPackageAndAddFile(pvci *PackageCreationInfo, pfci *PackageFileCreationInfo, allowDuplicate bool) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
-	ctx, committer, err := db.TxContext()
+// createPackageAndAddFile stores the package file and records its publication in the audit log.
+// The request information for the audit log is read from the context.
+func createPackageAndAddFile(ctx context.Context, pvci *PackageCreationInfo, pfci *PackageFileCreationInfo, allowDuplicate bool) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
+	immutable, err := isOverwriteProtected(pvci.Owner.ID, pfci)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbCtx, committer, err := db.TxContext()
 	if err != nil {
 		return nil, nil, err
 	}
 	defer committer.Close()
 
-	pv, created, err := createPackageAndVersion(ctx, pvci, allowDuplicate)
+	pv, created, err := createPackageAndVersion(dbCtx, pvci, allowDuplicate)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	pf, pb, blobCreated, err := addFileToPackageVersion(ctx, pv, pfci)
+	pf, pb, blobCreated, err := addFileToPackageVersion(dbCtx, pv, pfci, immutable)
 	removeBlob := false
 	defer func() {
 		if blobCreated && removeBlob {
@@ -98,8 +114,11 @@ func createPackageAndAddFile(pvci *PackageCreationInfo, pfci *PackageFileCreatio
 		return nil, nil, err
 	}
 
+	metrics.PackageUploads.WithLabelValues(string(pvci.PackageType)).Inc()
+	recordPublishAudit(ctx, pvci.Creator, &pvci.PackageInfo, pf)
+
 	if created {
-		pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+		pd, err := packages_model.GetPackageDescriptor(dbCtx, pv)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -152,6 +171,7 @@ func createPackageAndVersion(ctx context.Context, pvci *PackageCreationInfo, all
 		Version:      pvci.Version,
 		LowerVersion: strings.ToLower(pvci.Version),
 		MetadataJSON: string(metadataJSON),
+		ReleaseNotes: packages_module.TruncateReleaseNotes(pvci.ReleaseNotes),
 	}
 	if pv, err = packages_model.GetOrInsertVersion(ctx, pv); err != nil {
 		if err == packages_model.ErrDuplicatePackageVersion {
@@ -170,25 +190,34 @@ func createPackageAndVersion(ctx context.Context, pvci *PackageCreationInfo, all
 				return nil, false, err
 			}
 		}
+		if err := packages_model.UpdateDependencies(ctx, p.Type, pv); err != nil {
+			log.Error("Error setting package version dependencies: %v", err)
+			return nil, false, err
+		}
 	}
 
 	return pv, versionCreated, nil
 }
 
 // AddFileToExistingPackage adds a file to an existing package. If the package does not exist, ErrPackageNotExist is returned
-func AddFileToExistingPackage(pvi *PackageInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
-	ctx, committer, err := db.TxContext()
+func AddFileToExistingPackage(ctx context.Context, doer *user_model.User, pvi *PackageInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
+	immutable, err := isOverwriteProtected(pvi.Owner.ID, pfci)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbCtx, committer, err := db.TxContext()
 	if err != nil {
 		return nil, nil, err
 	}
 	defer committer.Close()
 
-	pv, err := packages_model.GetVersionByNameAndVersion(ctx, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
+	pv, err := packages_model.GetVersionByNameAndVersion(dbCtx, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	pf, pb, blobCreated, err := addFileToPackageVersion(ctx, pv, pfci)
+	pf, pb, blobCreated, err := addFileToPackageVersion(dbCtx, pv, pfci, immutable)
 	removeBlob := false
 	defer func() {
 		if removeBlob {
@@ -208,6 +237,9 @@ func AddFileToExistingPackage(pvi *PackageInfo, pfci *PackageFileCreationInfo) (
 		return nil, nil, err
 	}
 
+	metrics.PackageUploads.WithLabelValues(string(pvi.PackageType)).Inc()
+	recordPublishAudit(ctx, doer, pvi, pf)
+
 	return pv, pf, nil
 }
 
@@ -224,13 +256,37 @@ func NewPackageBlob(hsr packages_module.HashedSizeReader) *packages_model.Packag
 	}
 }
 
-func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
+// IsVersionImmutable returns true if the published package versions of the owner can't be changed or deleted
+func IsVersionImmutable(ownerID int64) (bool, error) {
+	val, err := user_model.GetUserSetting(ownerID, user_model.SettingsKeyPackagesImmutableVersions)
+	if err != nil {
+		return false, err
+	}
+	return val == "true", nil
+}
+
+// isOverwriteProtected checks if an existing file must not be replaced by the file to create
+func isOverwriteProtected(ownerID int64, pfci *PackageFileCreationInfo) (bool, error) {
+	if !pfci.OverwriteExisting {
+		return false, nil
+	}
+	return IsVersionImmutable(ownerID)
+}
+
+func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo, immutable bool) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
 	log.Trace("Adding package file: %v, %s", pv.ID, pfci.Filename)
 
-	pb, exists, err := packages_model.GetOrInsertBlob(ctx, NewPackageBlob(pfci.Data))
-	if err != nil {
-		log.Error("Error inserting package blob: %v", err)
-		return nil, nil, false, err
+	var err error
+	pb, exists := pfci.Blob, true
+	if pb == nil {
+		pb, exists, err = packages_model.GetOrInsertBlob(ctx, NewPackageBlob(pfci.Data))
+		if err != nil {
+			log.Error("Error inserting package blob: %v", err)
+			return nil, nil, false, err
+		}
+		if exists {
+			metrics.PackageBlobDedupHits.Inc()
+		}
 	}
 	if !exists {
 		contentStore := packages_module.NewContentStore()
@@ -251,6 +307,10 @@ func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVers
 				return pf, pb, !exists, nil
 			}
 
+			if immutable && !pv.IsInternal {
+				return nil, pb, !exists, ErrVersionImmutable
+			}
+
 			if err := packages_model.DeleteAllProperties(ctx, packages_model.PropertyTypeFile, pf.ID); err != nil {
 				return nil, pb, !exists, err
 			}
@@ -286,31 +346,58 @@ func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVers
 }
 
 // RemovePackageVersionByNameAndVersion deletes a package version and all associated files
-func RemovePackageVersionByNameAndVersion(doer *user_model.User, pvi *PackageInfo) error {
-	pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
+func RemovePackageVersionByNameAndVersion(ctx context.Context, doer *user_model.User, pvi *PackageInfo) error {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
 	if err != nil {
 		return err
 	}
 
-	return RemovePackageVersion(doer, pv)
+	return RemovePackageVersion(ctx, doer, pv)
 }
 
-// RemovePackageVersion deletes the package version and all associated files
-func RemovePackageVersion(doer *user_model.User, pv *packages_model.PackageVersion) error {
-	ctx, committer, err := db.TxContext()
+// RemovePackageVersion deletes the package version and all associated files.
+// If the versions of the owner are immutable, ErrVersionImmutable is returned.
+func RemovePackageVersion(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion) error {
+	return removePackageVersion(ctx, doer, pv, false)
+}
+
+// ForceRemovePackageVersion deletes the package version and all associated files even if the versions of the owner are immutable.
+// It must only be used for an explicit override by an admin.
+func ForceRemovePackageVersion(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion) error {
+	return removePackageVersion(ctx, doer, pv, true)
+}
+
+// removePackageVersion deletes the package version and records the deletion in the audit log.
+// The request information for the audit log is read from the context.
+func removePackageVersion(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion, force bool) error {
+	if !force && !pv.IsInternal {
+		p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+		if err != nil {
+			return err
+		}
+		immutable, err := IsVersionImmutable(p.OwnerID)
+		if err != nil {
+			return err
+		}
+		if immutable {
+			return ErrVersionImmutable
+		}
+	}
+
+	dbCtx, committer, err := db.TxContext()
 	if err != nil {
 		return err
 	}
 	defer committer.Close()
 
-	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	pd, err := packages_model.GetPackageDescriptor(dbCtx, pv)
 	if err != nil {
 		return err
 	}
 
 	log.Trace("Deleting package: %v", pv.ID)
 
-	if err := DeletePackageVersionAndReferences(ctx, pv); err != nil {
+	if err := DeletePackageVersionAndReferences(dbCtx, pv); err != nil {
 		return err
 	}
 
@@ -318,6 +405,12 @@ func RemovePackageVersion(doer *user_model.User, pv *packages_model.PackageVersi
 		return err
 	}
 
+	detail := ""
+	if force {
+		detail = "forced"
+	}
+	RecordVersionAudit(ctx, doer, packages_model.AuditActionDelete, pd, "", detail)
+
 	notification.NotifyPackageDelete(doer, pd)
 
 	return nil
@@ -329,6 +422,10 @@ func DeletePackageVersionAndReferences(ctx context.Context, pv *packages_model.P
 		return err
 	}
 
+	if err := packages_model.DeleteDownloadsByVersionID(ctx, pv.ID); err != nil {
+		return err
+	}
+
 	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
 	if err != nil {
 		return err
@@ -353,6 +450,16 @@ func DeletePackageFile(ctx context.Context, pf *packages_model.PackageFile) erro
 
 // Cleanup removes expired package data
 func Cleanup(unused context.Context, olderThan time.Duration) error {
+	err := cleanup(olderThan)
+	if err != nil {
+		metrics.PackageCleanupRuns.WithLabelValues("failure").Inc()
+	} else {
+		metrics.PackageCleanupRuns.WithLabelValues("success").Inc()
+	}
+	return err
+}
+
+func cleanup(olderThan time.Duration) error {
 	ctx, committer, err := db.TxContext()
 	if err != nil {
 		return err
@@ -371,6 +478,12 @@ func Cleanup(unused context.Context, olderThan time.Duration) error {
 		if err := packages_model.DeleteAllProperties(ctx, packages_model.PropertyTypePackage, p.ID); err != nil {
 			return err
 		}
+		if err := packages_model.DeletePackageTeamsByPackageID(ctx, p.ID); err != nil {
+			return err
+		}
+		if err := packages_model.DeletePackageSharesByPackageID(ctx, p.ID); err != nil {
+			return err
+		}
 		if err := packages_model.DeletePackageByID(ctx, p.ID); err != nil {
 			return err
 		}
@@ -391,6 +504,8 @@ func Cleanup(unused context.Context, olderThan time.Duration) error {
 		return err
 	}
 
+	metrics.PackageCleanupRemovedBlobs.Add(float64(len(pbs)))
+
 	contentStore := packages_module.NewContentStore()
 	for _, pb := range pbs {
 		if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
@@ -401,24 +516,24 @@ func Cleanup(unused context.Context, olderThan time.Duration) error {
 	return nil
 }
 
-// GetFileStreamByPackageNameAndVersion returns the content of the specific package file
-func GetFileStreamByPackageNameAndVersion(ctx context.Context, pvi *PackageInfo, pfi *PackageFileInfo) (io.ReadSeekCloser, *packages_model.PackageFile, error) {
+// GetFileStreamByPackageNameAndVersion returns the content of the specific package file or a presigned url to it if the storage serves it directly
+func GetFileStreamByPackageNameAndVersion(ctx context.Context, pvi *PackageInfo, pfi *PackageFileInfo) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
 	log.Trace("Getting package file stream: %v, %v, %s, %s, %s, %s", pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version, pfi.Filename, pfi.CompositeKey)
 
 	pv, err := packages_model.GetVersionByNameAndVersion(ctx, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
 	if err != nil {
 		if err == packages_model.ErrPackageNotExist {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		log.Error("Error getting package: %v", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	return GetFileStreamByPackageVersion(ctx, pv, pfi)
 }
 
-// GetFileStreamByPackageVersionAndFileID returns the content of the specific package file
-func GetFileStreamByPackageVersionAndFileID(ctx context.Context, owner *user_model.User, versionID, fileID int64) (io.ReadSeekCloser, *packages_model.PackageFile, error) {
+// GetFileStreamByPackageVersionAndFileID returns the content of the specific package file or a presigned url to it if the storage serves it directly
+func GetFileStreamByPackageVersionAndFileID(ctx context.Context, owner *user_model.User, versionID, fileID int64) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
 	log.Trace("Getting package file stream: %v, %v, %v", owner.ID, versionID, fileID)
 
 	pv, err := packages_model.GetVersionByID(ctx, versionID)
@@ -426,54 +541,88 @@ func GetFileStreamByPackageVersionAndFileID(ctx context.Context, owner *user_mod
 		if err != packages_model.ErrPackageNotExist {
 			log.Error("Error getting package version: %v", err)
 		}
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
 	if err != nil {
 		log.Error("Error getting package: %v", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if p.OwnerID != owner.ID {
-		return nil, nil, packages_model.ErrPackageNotExist
+		return nil, nil, nil, packages_model.ErrPackageNotExist
 	}
 
 	pf, err := packages_model.GetFileForVersionByID(ctx, versionID, fileID)
 	if err != nil {
 		log.Error("Error getting file: %v", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	return GetPackageFileStream(ctx, pf)
 }
 
-// GetFileStreamByPackageVersion returns the content of the specific package file
-func GetFileStreamByPackageVersion(ctx context.Context, pv *packages_model.PackageVersion, pfi *PackageFileInfo) (io.ReadSeekCloser, *packages_model.PackageFile, error) {
+// GetFileStreamByPackageVersion returns the content of the specific package file or a presigned url to it if the storage serves it directly
+func GetFileStreamByPackageVersion(ctx context.Context, pv *packages_model.PackageVersion, pfi *PackageFileInfo) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
 	pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, pfi.Filename, pfi.CompositeKey)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	return GetPackageFileStream(ctx, pf)
 }
 
-// GetPackageFileStream returns the content of the specific package file
-func GetPackageFileStream(ctx context.Context, pf *packages_model.PackageFile) (io.ReadSeekCloser, *packages_model.PackageFile, error) {
+// GetPackageFileStream returns the content of the specific package file or a presigned url to it if the storage serves it directly
+func GetPackageFileStream(ctx context.Context, pf *packages_model.PackageFile) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
+	return getPackageFileStream(ctx, pf, true)
+}
+
+// OpenPackageFileStream returns the content of the specific package file, even if the storage serves it directly
+func OpenPackageFileStream(ctx context.Context, pf *packages_model.PackageFile) (io.ReadSeekCloser, *packages_model.PackageFile, error) {
+	s, _, pf, err := getPackageFileStream(ctx, pf, false)
+	return s, pf, err
+}
+
+func getPackageFileStream(ctx context.Context, pf *packages_model.PackageFile, allowServeDirect bool) (io.ReadSeekCloser, *url.URL, *packages_model.PackageFile, error) {
 	pb, err := packages_model.GetBlobByID(ctx, pf.BlobID)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	contentStore := packages_module.NewContentStore()
+	key := packages_module.BlobHash256Key(pb.HashSHA256)
+
+	var s io.ReadSeekCloser
+	var u *url.URL
+	if allowServeDirect && contentStore.ShouldServeDirect() {
+		u, err = contentStore.GetServeDirectURL(key, pf.Name)
+		if err != nil && !errors.Is(err, storage.ErrURLNotSupported) {
+			log.Error("Error getting serve direct url for package blob %d: %v", pb.ID, err)
+		}
+	}
+	if u == nil {
+		s, err = contentStore.Get(key)
 	}
 
-	s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pb.HashSHA256))
 	if err == nil {
 		if pf.IsLead {
 			if err := packages_model.IncrementDownloadCounter(ctx, pf.VersionID); err != nil {
 				log.Error("Error incrementing download counter: %v", err)
 			}
 		}
+		RecordDownload(ctx, pf)
+	}
+	return s, u, pf, err
+}
+
+// RecordDownload adds the download of the package file to the download statistics.
+// The client is classified by the user agent stored in the context.
+func RecordDownload(ctx context.Context, pf *packages_model.PackageFile) {
+	client := packages_module.ClassifyUserAgent(packages_module.UserAgentFromContext(ctx))
+	if err := packages_model.RecordDownload(ctx, pf.VersionID, pf.ID, client, time.Now()); err != nil {
+		log.Error("Error recording download of package file %d: %v", pf.ID, err)
 	}
-	return s, pf, err
 }
 
 // RemoveAllPackages for User
@@ -501,5 +650,8 @@ func RemoveAllPackages(ctx context.Context, userID int64) (int, error) {
 			count++
 		}
 	}
+	if err := packages_model.DeleteMirrorsByOwner(ctx, userID); err != nil {
+		return count, fmt.Errorf("DeleteMirrorsByOwner[%d]: %w", userID, err)
+	}
 	return count, nil
 }
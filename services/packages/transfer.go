@@ -0,0 +1,487 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	exportManifestFile  = "manifest.json"
+	exportBlobDir       = "blobs"
+	exportFormatVersion = 1
+)
+
+// ErrInvalidExport indicates an invalid package export archive
+var ErrInvalidExport = errors.New("package export is invalid")
+
+type exportManifest struct {
+	Version  int              `json:"version"`
+	Blobs    []*exportBlob    `json:"blobs"`
+	Packages []*exportPackage `json:"packages"`
+}
+
+type exportBlob struct {
+	Size        int64  `json:"size"`
+	HashMD5     string `json:"hash_md5"`
+	HashSHA1    string `json:"hash_sha1"`
+	HashSHA256  string `json:"hash_sha256"`
+	HashSHA512  string `json:"hash_sha512"`
+	CreatedUnix int64  `json:"created_unix"`
+}
+
+type exportProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type exportPackage struct {
+	Owner            string            `json:"owner"`
+	Type             string            `json:"type"`
+	Name             string            `json:"name"`
+	SemverCompatible bool              `json:"semver_compatible"`
+	Visibility       string            `json:"visibility,omitempty"`
+	Repository       string            `json:"repository,omitempty"`
+	Properties       []*exportProperty `json:"properties"`
+	Versions         []*exportVersion  `json:"versions"`
+}
+
+type exportVersion struct {
+	Version       string            `json:"version"`
+	Creator       string            `json:"creator"`
+	CreatedUnix   int64             `json:"created_unix"`
+	IsInternal    bool              `json:"is_internal"`
+	MetadataJSON  string            `json:"metadata_json"`
+	DownloadCount int64             `json:"download_count"`
+	Properties    []*exportProperty `json:"properties"`
+	Files         []*exportFile     `json:"files"`
+}
+
+type exportFile struct {
+	Name         string            `json:"name"`
+	CompositeKey string            `json:"composite_key"`
+	IsLead       bool              `json:"is_lead"`
+	CreatedUnix  int64             `json:"created_unix"`
+	BlobSHA256   string            `json:"blob_sha256"`
+	Properties   []*exportProperty `json:"properties"`
+}
+
+// ImportResult contains the number of imported and skipped package versions
+type ImportResult struct {
+	ImportedVersions int
+	SkippedVersions  int
+}
+
+func exportProperties(ctx context.Context, refType packages_model.PropertyType, refID int64) ([]*exportProperty, error) {
+	pps, err := packages_model.GetProperties(ctx, refType, refID)
+	if err != nil {
+		return nil, err
+	}
+	properties := make([]*exportProperty, 0, len(pps))
+	for _, pp := range pps {
+		properties = append(properties, &exportProperty{Name: pp.Name, Value: pp.Value})
+	}
+	return properties, nil
+}
+
+// ExportPackages writes the metadata and the blobs of all packages into a zip archive
+func ExportPackages(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := &exportManifest{
+		Version:  exportFormatVersion,
+		Blobs:    make([]*exportBlob, 0, 10),
+		Packages: make([]*exportPackage, 0, 10),
+	}
+	exportedBlobs := make(map[int64]string)
+	contentStore := packages_module.NewContentStore()
+
+	exportBlobByID := func(blobID int64) (string, error) {
+		if hashSHA256, has := exportedBlobs[blobID]; has {
+			return hashSHA256, nil
+		}
+
+		pb, err := packages_model.GetBlobByID(ctx, blobID)
+		if err != nil {
+			return "", err
+		}
+
+		s, err := contentStore.Get(packages_module.BlobHash256Key(pb.HashSHA256))
+		if err != nil {
+			return "", err
+		}
+		defer s.Close()
+
+		fw, err := zw.Create(path.Join(exportBlobDir, pb.HashSHA256))
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(fw, s); err != nil {
+			return "", err
+		}
+
+		manifest.Blobs = append(manifest.Blobs, &exportBlob{
+			Size:        pb.Size,
+			HashMD5:     pb.HashMD5,
+			HashSHA1:    pb.HashSHA1,
+			HashSHA256:  pb.HashSHA256,
+			HashSHA512:  pb.HashSHA512,
+			CreatedUnix: int64(pb.CreatedUnix),
+		})
+		exportedBlobs[blobID] = pb.HashSHA256
+
+		return pb.HashSHA256, nil
+	}
+
+	err := db.IterateObjects(ctx, func(p *packages_model.Package) error {
+		owner, err := user_model.GetUserByIDCtx(ctx, p.OwnerID)
+		if err != nil {
+			return fmt.Errorf("GetUserByID[%d]: %w", p.OwnerID, err)
+		}
+
+		ep := &exportPackage{
+			Owner:            owner.Name,
+			Type:             string(p.Type),
+			Name:             p.Name,
+			SemverCompatible: p.SemverCompatible,
+			Versions:         make([]*exportVersion, 0, 10),
+		}
+		if p.Visibility != packages_model.VisibilityInherit {
+			ep.Visibility = p.Visibility.String()
+		}
+
+		if p.RepoID != 0 {
+			repo, err := repo_model.GetRepositoryByIDCtx(ctx, p.RepoID)
+			if err != nil && !repo_model.IsErrRepoNotExist(err) {
+				return err
+			}
+			if repo != nil {
+				ep.Repository = repo.FullName()
+			}
+		}
+
+		if ep.Properties, err = exportProperties(ctx, packages_model.PropertyTypePackage, p.ID); err != nil {
+			return err
+		}
+
+		pvs := make([]*packages_model.PackageVersion, 0, 10)
+		if err := db.GetEngine(ctx).Where("package_id = ?", p.ID).Asc("id").Find(&pvs); err != nil {
+			return err
+		}
+
+		for _, pv := range pvs {
+			ev := &exportVersion{
+				Version:       pv.Version,
+				CreatedUnix:   int64(pv.CreatedUnix),
+				IsInternal:    pv.IsInternal,
+				MetadataJSON:  pv.MetadataJSON,
+				DownloadCount: pv.DownloadCount,
+				Files:         make([]*exportFile, 0, 5),
+			}
+
+			if creator, err := user_model.GetUserByIDCtx(ctx, pv.CreatorID); err == nil {
+				ev.Creator = creator.Name
+			} else if !user_model.IsErrUserNotExist(err) {
+				return err
+			}
+
+			if ev.Properties, err = exportProperties(ctx, packages_model.PropertyTypeVersion, pv.ID); err != nil {
+				return err
+			}
+
+			pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+			if err != nil {
+				return err
+			}
+			for _, pf := range pfs {
+				ef := &exportFile{
+					Name:         pf.Name,
+					CompositeKey: pf.CompositeKey,
+					IsLead:       pf.IsLead,
+					CreatedUnix:  int64(pf.CreatedUnix),
+				}
+				if ef.BlobSHA256, err = exportBlobByID(pf.BlobID); err != nil {
+					return err
+				}
+				if ef.Properties, err = exportProperties(ctx, packages_model.PropertyTypeFile, pf.ID); err != nil {
+					return err
+				}
+				ev.Files = append(ev.Files, ef)
+			}
+
+			ep.Versions = append(ep.Versions, ev)
+		}
+
+		manifest.Packages = append(manifest.Packages, ep)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fw, err := zw.Create(exportManifestFile)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(fw).Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// ImportPackages imports the packages of an archive created by ExportPackages.
+// Packages of owners which don't exist and versions which exist already are skipped.
+func ImportPackages(ctx context.Context, r io.ReaderAt, size int64) (*ImportResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	manifestFile, has := files[exportManifestFile]
+	if !has {
+		return nil, ErrInvalidExport
+	}
+	mr, err := manifestFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer mr.Close()
+
+	var manifest exportManifest
+	if err := json.NewDecoder(mr).Decode(&manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != exportFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidExport, manifest.Version)
+	}
+
+	blobs := make(map[string]*exportBlob, len(manifest.Blobs))
+	for _, eb := range manifest.Blobs {
+		blobs[eb.HashSHA256] = eb
+	}
+
+	result := &ImportResult{}
+	for _, ep := range manifest.Packages {
+		owner, err := user_model.GetUserByName(ctx, ep.Owner)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				log.Warn("Skipping package %s of unknown owner %s", ep.Name, ep.Owner)
+				result.SkippedVersions += len(ep.Versions)
+				continue
+			}
+			return nil, err
+		}
+
+		for _, ev := range ep.Versions {
+			imported, err := importVersion(ctx, owner, ep, ev, blobs, files)
+			if err != nil {
+				return nil, fmt.Errorf("importing %s/%s/%s %s failed: %w", ep.Owner, ep.Type, ep.Name, ev.Version, err)
+			}
+			if imported {
+				result.ImportedVersions++
+			} else {
+				result.SkippedVersions++
+			}
+		}
+	}
+	return result, nil
+}
+
+func importVersion(ctx context.Context, owner *user_model.User, ep *exportPackage, ev *exportVersion, blobs map[string]*exportBlob, files map[string]*zip.File) (bool, error) {
+	// store the blobs first, unreferenced blobs get removed by the cleanup task
+	for _, ef := range ev.Files {
+		eb, has := blobs[ef.BlobSHA256]
+		if !has {
+			return false, fmt.Errorf("%w: missing blob %s", ErrInvalidExport, ef.BlobSHA256)
+		}
+		if err := importBlobContent(eb, files); err != nil {
+			return false, err
+		}
+	}
+
+	imported := false
+	err := db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+
+		p := &packages_model.Package{
+			OwnerID:          owner.ID,
+			Type:             packages_model.Type(ep.Type),
+			Name:             ep.Name,
+			LowerName:        strings.ToLower(ep.Name),
+			SemverCompatible: ep.SemverCompatible,
+		}
+		if visibility, ok := packages_model.VisibilityFromString(ep.Visibility); ok {
+			p.Visibility = visibility
+		}
+		if ep.Repository != "" {
+			if parts := strings.SplitN(ep.Repository, "/", 2); len(parts) == 2 {
+				repo, err := repo_model.GetRepositoryByOwnerAndNameCtx(ctx, parts[0], parts[1])
+				if err != nil && !repo_model.IsErrRepoNotExist(err) {
+					return err
+				}
+				if repo != nil {
+					p.RepoID = repo.ID
+				}
+			}
+		}
+		p, err := packages_model.TryInsertPackage(ctx, p)
+		if err != nil {
+			if err != packages_model.ErrDuplicatePackage {
+				return err
+			}
+		} else if err := importProperties(ctx, packages_model.PropertyTypePackage, p.ID, ep.Properties); err != nil {
+			return err
+		}
+
+		has, err := e.Exist(&packages_model.PackageVersion{
+			PackageID:    p.ID,
+			LowerVersion: strings.ToLower(ev.Version),
+		})
+		if err != nil || has {
+			return err
+		}
+
+		creatorID := user_model.NewGhostUser().ID
+		if ev.Creator != "" {
+			if creator, err := user_model.GetUserByName(ctx, ev.Creator); err == nil {
+				creatorID = creator.ID
+			} else if !user_model.IsErrUserNotExist(err) {
+				return err
+			}
+		}
+
+		pv := &packages_model.PackageVersion{
+			PackageID:     p.ID,
+			CreatorID:     creatorID,
+			Version:       ev.Version,
+			LowerVersion:  strings.ToLower(ev.Version),
+			CreatedUnix:   timeutil.TimeStamp(ev.CreatedUnix),
+			IsInternal:    ev.IsInternal,
+			MetadataJSON:  ev.MetadataJSON,
+			DownloadCount: ev.DownloadCount,
+		}
+		if _, err := e.NoAutoTime().Insert(pv); err != nil {
+			return err
+		}
+		if err := importProperties(ctx, packages_model.PropertyTypeVersion, pv.ID, ev.Properties); err != nil {
+			return err
+		}
+		if err := packages_model.UpdateDependencies(ctx, p.Type, pv); err != nil {
+			return err
+		}
+
+		for _, ef := range ev.Files {
+			pb, err := importBlob(ctx, blobs[ef.BlobSHA256])
+			if err != nil {
+				return err
+			}
+
+			pf := &packages_model.PackageFile{
+				VersionID:    pv.ID,
+				BlobID:       pb.ID,
+				Name:         ef.Name,
+				LowerName:    strings.ToLower(ef.Name),
+				CompositeKey: ef.CompositeKey,
+				IsLead:       ef.IsLead,
+				CreatedUnix:  timeutil.TimeStamp(ef.CreatedUnix),
+			}
+			if _, err := e.NoAutoTime().Insert(pf); err != nil {
+				return err
+			}
+			if err := importProperties(ctx, packages_model.PropertyTypeFile, pf.ID, ef.Properties); err != nil {
+				return err
+			}
+		}
+
+		imported = true
+		return nil
+	}, ctx)
+	return imported, err
+}
+
+// importBlobContent stores the content of the blob if it doesn't exist already
+func importBlobContent(eb *exportBlob, files map[string]*zip.File) error {
+	contentStore := packages_module.NewContentStore()
+	key := packages_module.BlobHash256Key(eb.HashSHA256)
+
+	if s, err := contentStore.Get(key); err == nil {
+		return s.Close()
+	}
+
+	file, has := files[path.Join(exportBlobDir, eb.HashSHA256)]
+	if !has {
+		return fmt.Errorf("%w: missing blob content %s", ErrInvalidExport, eb.HashSHA256)
+	}
+	fr, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	h := sha256.New()
+	if err := contentStore.Save(key, io.TeeReader(fr, h), eb.Size); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != eb.HashSHA256 {
+		if err := contentStore.Delete(key); err != nil {
+			log.Error("Unable to delete corrupt blob %s: %v", eb.HashSHA256, err)
+		}
+		return fmt.Errorf("%w: checksum mismatch of blob %s", ErrInvalidExport, eb.HashSHA256)
+	}
+	return nil
+}
+
+func importBlob(ctx context.Context, eb *exportBlob) (*packages_model.PackageBlob, error) {
+	pb := &packages_model.PackageBlob{}
+	has, err := db.GetEngine(ctx).Where("hash_sha256 = ?", eb.HashSHA256).Get(pb)
+	if err != nil || has {
+		return pb, err
+	}
+
+	pb = &packages_model.PackageBlob{
+		Size:        eb.Size,
+		HashMD5:     eb.HashMD5,
+		HashSHA1:    eb.HashSHA1,
+		HashSHA256:  eb.HashSHA256,
+		HashSHA512:  eb.HashSHA512,
+		CreatedUnix: timeutil.TimeStamp(eb.CreatedUnix),
+	}
+	if _, err := db.GetEngine(ctx).NoAutoTime().Insert(pb); err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
+
+func importProperties(ctx context.Context, refType packages_model.PropertyType, refID int64, properties []*exportProperty) error {
+	for _, ep := range properties {
+		if _, err := packages_model.InsertProperty(ctx, refType, refID, ep.Name, ep.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ErrPageNotExist represents an error if no page exists at the requested path
+var ErrPageNotExist = errors.New("page does not exist")
+
+// deployQueue represents a queue to deploy the pages of repositories
+var deployQueue queue.UniqueQueue
+
+func handle(data ...queue.Data) []queue.Data {
+	for _, datum := range data {
+		repoID := datum.(int64)
+		if err := Deploy(graceful.GetManager().HammerContext(), repoID); err != nil {
+			log.Warn("Deploying the pages of repository %d failed: %v", repoID, err)
+		}
+	}
+	return nil
+}
+
+// Init starts the queue which deploys the pages
+func Init() error {
+	if !setting.Pages.Enabled {
+		return nil
+	}
+
+	deployQueue = queue.CreateUniqueQueue("pages_deploy", handle, int64(0))
+	if deployQueue == nil {
+		return errors.New("unable to create pages_deploy Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(deployQueue.Run)
+	return nil
+}
+
+// TriggerDeploy queues the deployment of the pages of the repository if they are served from the branch.
+// An empty branch always queues the deployment.
+func TriggerDeploy(ctx context.Context, repo *repo_model.Repository, branch string) error {
+	if deployQueue == nil {
+		return nil
+	}
+
+	pagesUnit, err := repo.GetUnitCtx(ctx, unit.TypePages)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if branch != "" && branch != pagesUnit.PagesConfig().BranchName(repo) {
+		return nil
+	}
+
+	if err := deployQueue.Push(repo.ID); err != nil && err != queue.ErrAlreadyInQueue {
+		return err
+	}
+	return nil
+}
+
+// Deploy serves the current commit of the pages branch of the repository.
+// If the content can't be deployed the failure is recorded, the previously deployed commit
+// is still served and the error is returned.
+func Deploy(ctx context.Context, repoID int64) error {
+	repo, err := repo_model.GetRepositoryByIDCtx(ctx, repoID)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	pagesUnit, err := repo.GetUnitCtx(ctx, unit.TypePages)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return repo_model.DeletePagesDeployment(ctx, repo.ID)
+		}
+		return err
+	}
+	cfg := pagesUnit.PagesConfig()
+
+	d, err := repo_model.GetPagesDeployment(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		d = &repo_model.PagesDeployment{RepoID: repo.ID}
+	}
+
+	directory := cleanPath(cfg.Directory)
+	commitID, deployErr := checkContent(ctx, repo, cfg.BranchName(repo), directory)
+	if deployErr != nil {
+		d.Status = repo_model.PagesDeploymentFailed
+		d.Error = deployErr.Error()
+	} else {
+		d.CommitID = commitID
+		d.Directory = directory
+		d.Status = repo_model.PagesDeploymentDeployed
+		d.Error = ""
+		d.DeployedUnix = timeutil.TimeStampNow()
+	}
+
+	if err := repo_model.SavePagesDeployment(ctx, d); err != nil {
+		return err
+	}
+	return deployErr
+}
+
+// checkContent checks the pages directory of the branch and returns the commit to serve
+func checkContent(ctx context.Context, repo *repo_model.Repository, branch, directory string) (string, error) {
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", fmt.Errorf("branch %q does not exist", branch)
+		}
+		return "", err
+	}
+
+	tree := &commit.Tree
+	if directory != "" {
+		if tree, err = commit.SubTree(directory); err != nil {
+			if git.IsErrNotExist(err) {
+				return "", fmt.Errorf("directory %q does not exist in branch %q", directory, branch)
+			}
+			return "", err
+		}
+	}
+
+	entries, err := tree.ListEntriesRecursive()
+	if err != nil {
+		return "", err
+	}
+
+	var size int64
+	for _, entry := range entries {
+		if entry.IsRegular() || entry.IsExecutable() {
+			size += entry.Size()
+		}
+	}
+	if setting.Pages.MaxSize > 0 && size > setting.Pages.MaxSize*1024*1024 {
+		return "", fmt.Errorf("the pages exceed the maximum size of %d MiB", setting.Pages.MaxSize)
+	}
+
+	return commit.ID.String(), nil
+}
+
+// GetPage returns the blob of the deployed pages at the path and its path relative to the pages root.
+// Directories resolve to their index.html and paths without extension to the html file of the same name.
+func GetPage(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, p string) (*git.Blob, string, error) {
+	d, err := repo_model.GetPagesDeployment(ctx, repo.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if d == nil || !d.IsDeployed() {
+		return nil, "", ErrPageNotExist
+	}
+
+	commit, err := gitRepo.GetCommit(d.CommitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, "", ErrPageNotExist
+		}
+		return nil, "", err
+	}
+
+	p = cleanPath(p)
+	candidates := []string{"index.html"}
+	if p != "" {
+		candidates = []string{p, p + "/index.html", p + ".html"}
+	}
+
+	for _, candidate := range candidates {
+		entry, err := commit.GetTreeEntryByPath(path.Join(d.Directory, candidate))
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, "", err
+		}
+		// links are not followed as they may point outside of the pages
+		if entry.IsRegular() || entry.IsExecutable() {
+			return entry.Blob(), candidate, nil
+		}
+	}
+	return nil, "", ErrPageNotExist
+}
+
+// SiteURL returns the URL the pages of the repository are served at
+func SiteURL(repo *repo_model.Repository) string {
+	if setting.Pages.Domain == "" {
+		return repo.HTMLURL() + "/pages/"
+	}
+
+	scheme := "https"
+	if u, err := url.Parse(setting.AppURL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+	return fmt.Sprintf("%s://%s.%s/%s/", scheme, strings.ToLower(repo.OwnerName), setting.Pages.Domain, url.PathEscape(repo.Name))
+}
+
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
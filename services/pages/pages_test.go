@@ -0,0 +1,100 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	unittest.MainTest(m, &unittest.TestOptions{
+		GiteaRootPath: filepath.Join("..", ".."),
+	})
+}
+
+func TestDeploy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// the pages can't be deployed without the unit
+	assert.NoError(t, Deploy(db.DefaultContext, repo.ID))
+	unittest.AssertNotExistsBean(t, &repo_model.PagesDeployment{RepoID: repo.ID})
+
+	pagesUnit := &repo_model.RepoUnit{
+		RepoID: repo.ID,
+		Type:   unit.TypePages,
+		Config: &repo_model.PagesConfig{},
+	}
+	assert.NoError(t, db.Insert(db.DefaultContext, pagesUnit))
+
+	gitRepo, err := git.OpenRepository(git.DefaultContext, repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	_, _, err = GetPage(db.DefaultContext, repo, gitRepo, "README.md")
+	assert.ErrorIs(t, err, ErrPageNotExist)
+
+	assert.NoError(t, Deploy(db.DefaultContext, repo.ID))
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	assert.NoError(t, err)
+
+	d, err := repo_model.GetPagesDeployment(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.True(t, d.IsDeployed())
+	assert.False(t, d.IsFailed())
+	assert.Equal(t, commit.ID.String(), d.CommitID)
+
+	blob, name, err := GetPage(db.DefaultContext, repo, gitRepo, "/README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "README.md", name)
+	assert.NotNil(t, blob)
+
+	_, _, err = GetPage(db.DefaultContext, repo, gitRepo, "")
+	assert.ErrorIs(t, err, ErrPageNotExist)
+
+	// a failed deployment keeps serving the previous commit
+	pagesUnit.Config = &repo_model.PagesConfig{Directory: "docs"}
+	_, err = db.GetEngine(db.DefaultContext).ID(pagesUnit.ID).Cols("config").Update(pagesUnit)
+	assert.NoError(t, err)
+
+	assert.Error(t, Deploy(db.DefaultContext, repo.ID))
+
+	d, err = repo_model.GetPagesDeployment(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.True(t, d.IsFailed())
+	assert.NotEmpty(t, d.Error)
+	assert.Equal(t, commit.ID.String(), d.CommitID)
+
+	_, _, err = GetPage(db.DefaultContext, repo, gitRepo, "README.md")
+	assert.NoError(t, err)
+}
+
+func TestSiteURL(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	defer func(domain string) {
+		setting.Pages.Domain = domain
+	}(setting.Pages.Domain)
+
+	setting.Pages.Domain = ""
+	assert.Equal(t, repo.HTMLURL()+"/pages/", SiteURL(repo))
+
+	setting.Pages.Domain = "pages.example.com"
+	assert.Equal(t, "https://user2.pages.example.com/repo1/", SiteURL(repo))
+}
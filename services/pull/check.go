@@ -42,6 +42,7 @@ var (
 	ErrIsChecking            = errors.New("cannot merge while conflict checking is in progress")
 	ErrNotMergableState      = errors.New("not in mergeable state")
 	ErrDependenciesLeft      = errors.New("is blocked by an open dependency")
+	ErrChecklistIncomplete   = errors.New("is blocked by unchecked merge checklist items")
 )
 
 // AddToTaskQueue adds itself to pull request test task queue.
@@ -121,6 +122,12 @@ func CheckPullMergable(stdCtx context.Context, doer *user_model.User, perm *acce
 			return ErrDependenciesLeft
 		}
 
+		if complete, err := issues_model.IsMergeChecklistComplete(ctx, pr); err != nil {
+			return err
+		} else if !complete {
+			return ErrChecklistIncomplete
+		}
+
 		return nil
 	}, stdCtx)
 }
@@ -0,0 +1,259 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const reviewStatsBatchSize = 50
+
+// PullSizes are the size classes of pull requests, by their maximum number of changed lines
+var PullSizes = []struct {
+	Name     string
+	MaxLines int64 // -1 for no maximum
+}{
+	{"xs", 9},
+	{"s", 49},
+	{"m", 249},
+	{"l", 999},
+	{"xl", -1},
+}
+
+// PullSize returns the size class of a pull request with the number of changed lines
+func PullSize(lines int64) string {
+	for _, size := range PullSizes {
+		if size.MaxLines == -1 || lines <= size.MaxLines {
+			return size.Name
+		}
+	}
+	return ""
+}
+
+// AggregateReviewStats updates the review statistics of the pull requests which changed since their last aggregation
+func AggregateReviewStats(ctx context.Context) error {
+	var afterID int64
+	for {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before aggregating the review statistics of pull requests after %d", afterID)
+		default:
+		}
+
+		prs, err := issues_model.FindPullRequestsForReviewStats(ctx, afterID, reviewStatsBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, pr := range prs {
+			afterID = pr.ID
+			if err := aggregatePullReviewStat(ctx, pr); err != nil {
+				log.Error("Unable to aggregate the review statistics of pull request %d: %v", pr.ID, err)
+			}
+		}
+		if len(prs) < reviewStatsBatchSize {
+			return nil
+		}
+	}
+}
+
+func aggregatePullReviewStat(ctx context.Context, pr *issues_model.PullRequest) error {
+	if err := pr.LoadIssueCtx(ctx); err != nil {
+		return err
+	}
+	if err := pr.LoadBaseRepoCtx(ctx); err != nil {
+		return err
+	}
+
+	firstReview, err := issues_model.GetFirstReviewUnix(ctx, pr.IssueID, pr.Issue.PosterID)
+	if err != nil {
+		return err
+	}
+
+	stat := &issues_model.PullReviewStat{
+		RepoID:          pr.BaseRepoID,
+		IssueID:         pr.IssueID,
+		PosterID:        pr.Issue.PosterID,
+		CreatedUnix:     pr.Issue.CreatedUnix,
+		FirstReviewUnix: firstReview,
+	}
+	if pr.HasMerged {
+		stat.MergedUnix = pr.MergedUnix
+	}
+
+	if pr.MergeBase != "" {
+		files, additions, deletions, err := git.GetDiffShortStat(ctx, pr.BaseRepo.RepoPath(), pr.MergeBase+"..."+pr.GetGitRefName())
+		if err != nil {
+			// the statistics are still useful without the size
+			log.Warn("Unable to get the size of pull request %d: %v", pr.ID, err)
+		} else {
+			stat.ChangedFiles = int64(files)
+			stat.Additions = int64(additions)
+			stat.Deletions = int64(deletions)
+		}
+	}
+
+	return issues_model.SavePullReviewStat(ctx, stat)
+}
+
+// DurationStats summarizes durations
+type DurationStats struct {
+	Count   int64
+	Average time.Duration
+	Median  time.Duration
+	P90     time.Duration
+}
+
+func newDurationStats(durations []time.Duration) *DurationStats {
+	stats := &DurationStats{Count: int64(len(durations))}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	// migrated pull requests may have been reviewed before they were created
+	for i := range durations {
+		if durations[i] < 0 {
+			durations[i] = 0
+		}
+	}
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	stats.Average = sum / time.Duration(len(durations))
+	stats.Median = durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		stats.Median = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+	}
+	stats.P90 = durations[(len(durations)*9+9)/10-1]
+	return stats
+}
+
+// SizeCount is the number of pull requests of a size class
+type SizeCount struct {
+	Size  string
+	Count int64
+}
+
+// ReviewerStats are the numbers of reviews submitted by a reviewer
+type ReviewerStats struct {
+	Reviewer   *user_model.User
+	Reviews    int64
+	Approvals  int64
+	Rejections int64
+	Comments   int64
+}
+
+// ReviewStats are the review throughput metrics of pull requests in a date range
+type ReviewStats struct {
+	Since             timeutil.TimeStamp
+	Until             timeutil.TimeStamp
+	Opened            int64
+	Merged            int64
+	TimeToFirstReview *DurationStats // of the pull requests first reviewed in the range
+	TimeToMerge       *DurationStats // of the pull requests merged in the range
+	Sizes             []*SizeCount   // of the pull requests merged in the range
+	Reviewers         []*ReviewerStats
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+func dayStart(t time.Time) timeutil.TimeStamp {
+	t = t.UTC()
+	return timeutil.TimeStamp(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix())
+}
+
+// GetReviewStats computes the review statistics of the pull requests selected by the options
+// between the start of the day of since (inclusive) and the end of the day of until (inclusive).
+// The range of the options is ignored.
+func GetReviewStats(ctx context.Context, opts issues_model.ReviewStatsOptions, since, until time.Time) (*ReviewStats, error) {
+	opts.Since = dayStart(since)
+	opts.Until = dayStart(until) + secondsPerDay
+
+	stats := &ReviewStats{
+		Since: opts.Since,
+		Until: opts.Until - secondsPerDay,
+	}
+
+	var err error
+	if stats.Opened, err = issues_model.CountOpenedPullReviewStats(ctx, &opts); err != nil {
+		return nil, err
+	}
+
+	reviewed, err := issues_model.FindReviewedPullReviewStats(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+	durations := make([]time.Duration, 0, len(reviewed))
+	for _, s := range reviewed {
+		durations = append(durations, time.Duration(s.FirstReviewUnix-s.CreatedUnix)*time.Second)
+	}
+	stats.TimeToFirstReview = newDurationStats(durations)
+
+	merged, err := issues_model.FindMergedPullReviewStats(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+	stats.Merged = int64(len(merged))
+	sizes := make(map[string]int64, len(PullSizes))
+	durations = make([]time.Duration, 0, len(merged))
+	for _, s := range merged {
+		durations = append(durations, time.Duration(s.MergedUnix-s.CreatedUnix)*time.Second)
+		sizes[PullSize(s.ChangedLines())]++
+	}
+	stats.TimeToMerge = newDurationStats(durations)
+	stats.Sizes = make([]*SizeCount, 0, len(PullSizes))
+	for _, size := range PullSizes {
+		stats.Sizes = append(stats.Sizes, &SizeCount{Size: size.Name, Count: sizes[size.Name]})
+	}
+
+	counts, err := issues_model.GetReviewCountsPerReviewer(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+	reviewers := make(map[int64]*ReviewerStats)
+	for _, c := range counts {
+		r, ok := reviewers[c.ReviewerID]
+		if !ok {
+			reviewer, err := user_model.GetUserByIDCtx(ctx, c.ReviewerID)
+			if err != nil {
+				if !user_model.IsErrUserNotExist(err) {
+					return nil, err
+				}
+				reviewer = user_model.NewGhostUser()
+			}
+			r = &ReviewerStats{Reviewer: reviewer}
+			reviewers[c.ReviewerID] = r
+			stats.Reviewers = append(stats.Reviewers, r)
+		}
+		r.Reviews += c.Count
+		switch c.Type {
+		case issues_model.ReviewTypeApprove:
+			r.Approvals += c.Count
+		case issues_model.ReviewTypeReject:
+			r.Rejections += c.Count
+		case issues_model.ReviewTypeComment:
+			r.Comments += c.Count
+		}
+	}
+	sort.SliceStable(stats.Reviewers, func(i, j int) bool {
+		if stats.Reviewers[i].Reviews != stats.Reviewers[j].Reviews {
+			return stats.Reviewers[i].Reviews > stats.Reviewers[j].Reviews
+		}
+		return stats.Reviewers[i].Reviewer.ID < stats.Reviewers[j].Reviewer.ID
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullSize(t *testing.T) {
+	assert.Equal(t, "xs", PullSize(0))
+	assert.Equal(t, "xs", PullSize(9))
+	assert.Equal(t, "s", PullSize(10))
+	assert.Equal(t, "m", PullSize(249))
+	assert.Equal(t, "l", PullSize(250))
+	assert.Equal(t, "xl", PullSize(1000))
+}
+
+func TestNewDurationStats(t *testing.T) {
+	stats := newDurationStats(nil)
+	assert.EqualValues(t, 0, stats.Count)
+	assert.EqualValues(t, 0, stats.Median)
+
+	stats = newDurationStats([]time.Duration{4 * time.Second, -time.Second, 2 * time.Second, 10 * time.Second})
+	assert.EqualValues(t, 4, stats.Count)
+	assert.Equal(t, 4*time.Second, stats.Average)
+	assert.Equal(t, 3*time.Second, stats.Median)
+	assert.Equal(t, 10*time.Second, stats.P90)
+}
+
+func TestAggregateReviewStats(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, AggregateReviewStats(db.DefaultContext))
+
+	// the poster's own reviews don't count
+	stat := unittest.AssertExistsAndLoadBean(t, &issues_model.PullReviewStat{IssueID: 2})
+	assert.EqualValues(t, 1, stat.RepoID)
+	assert.False(t, stat.IsReviewed())
+
+	stat = unittest.AssertExistsAndLoadBean(t, &issues_model.PullReviewStat{IssueID: 3})
+	assert.EqualValues(t, 946684812, stat.FirstReviewUnix)
+	assert.False(t, stat.IsMerged())
+
+	// unchanged pull requests are not aggregated again
+	prs, err := issues_model.FindPullRequestsForReviewStats(db.DefaultContext, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, prs)
+
+	day := time.Unix(946684800, 0)
+	stats, err := GetReviewStats(db.DefaultContext, issues_model.ReviewStatsOptions{RepoID: 1}, day, day)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 946684800, stats.Since)
+	assert.EqualValues(t, 946684800, stats.Until)
+	assert.EqualValues(t, 2, stats.Opened)
+	assert.EqualValues(t, 1, stats.TimeToFirstReview.Count)
+	assert.EqualValues(t, 0, stats.TimeToMerge.Count)
+	assert.Len(t, stats.Sizes, len(PullSizes))
+	if assert.Len(t, stats.Reviewers, 5) {
+		assert.EqualValues(t, 1, stats.Reviewers[0].Reviewer.ID)
+		assert.EqualValues(t, 2, stats.Reviewers[0].Reviews)
+		assert.EqualValues(t, 1, stats.Reviewers[0].Approvals)
+		assert.EqualValues(t, 1, stats.Reviewers[0].Comments)
+		assert.EqualValues(t, 0, stats.Reviewers[0].Rejections)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/references"
+)
+
+// templateVariablePattern matches the variables like ${HeadBranch} of pull request templates.
+// Only the braced form is expanded so shell snippets like $HOME in templates are kept.
+var templateVariablePattern = regexp.MustCompile(`\$\{([A-Za-z]+)\}`)
+
+// TemplateVars returns the variables of a pull request template for a pull request from headBranch of headRepo into baseBranch of baseRepo.
+// The linked issues are the issues of the base repository referenced by the commit messages.
+func TemplateVars(ctx context.Context, baseRepo, headRepo *repo_model.Repository, baseBranch, headBranch string, commits []*git.Commit) (map[string]string, error) {
+	closeKeywords, reopenKeywords := baseRepo.IssueKeywords(ctx)
+	keywords := references.NewKeywords(closeKeywords, reopenKeywords)
+	closeWord := "close"
+	if len(closeKeywords) > 0 {
+		closeWord = closeKeywords[0]
+	}
+
+	linked := make([]string, 0, 5)
+	closing := make([]string, 0, 5)
+	seen := make(map[int64]bool)
+	for _, commit := range commits {
+		for _, ref := range references.FindAllIssueReferencesWithKeywords(commit.CommitMessage, keywords) {
+			if ref.Owner != "" && (!strings.EqualFold(ref.Owner, baseRepo.OwnerName) || !strings.EqualFold(ref.Name, baseRepo.Name)) {
+				continue
+			}
+			if seen[ref.Index] {
+				continue
+			}
+			issue, err := issues_model.GetIssueByIndex(baseRepo.ID, ref.Index)
+			if err != nil {
+				if issues_model.IsErrIssueNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			if issue.IsPull {
+				continue
+			}
+			seen[ref.Index] = true
+			linked = append(linked, fmt.Sprintf("#%d", ref.Index))
+			if ref.Action == references.XRefActionCloses {
+				closing = append(closing, fmt.Sprintf("%s #%d", closeWord, ref.Index))
+			}
+		}
+	}
+
+	return map[string]string{
+		"BaseRepoOwnerName": baseRepo.OwnerName,
+		"BaseRepoName":      baseRepo.Name,
+		"BaseBranch":        baseBranch,
+		"HeadRepoOwnerName": headRepo.OwnerName,
+		"HeadRepoName":      headRepo.Name,
+		"HeadBranch":        headBranch,
+		"LinkedIssues":      strings.Join(linked, ", "),
+		"ClosingIssues":     strings.Join(closing, ", "),
+	}, nil
+}
+
+// ExpandTemplate replaces the known variables of a pull request template, unknown variables are kept as they are
+func ExpandTemplate(content string, vars map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(content, func(s string) string {
+		if v, ok := vars[s[2:len(s)-1]]; ok {
+			return v
+		}
+		return s
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateVars(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	commits := []*git.Commit{
+		{CommitMessage: "fix the thing\n\ncloses #1"},
+		// #2 is a pull request, #99 doesn't exist
+		{CommitMessage: "refs #4, #2 and #99, fixes user2/repo1#1"},
+		{CommitMessage: "related to user3/repo3#1"},
+	}
+
+	vars, err := TemplateVars(db.DefaultContext, repo, repo, "master", "feature", commits)
+	assert.NoError(t, err)
+	assert.Equal(t, "master", vars["BaseBranch"])
+	assert.Equal(t, "feature", vars["HeadBranch"])
+	assert.Equal(t, "user2", vars["HeadRepoOwnerName"])
+	assert.Equal(t, "#1, #4", vars["LinkedIssues"])
+	assert.Equal(t, "close #1", vars["ClosingIssues"])
+}
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{
+		"BaseBranch": "main",
+		"HeadBranch": "hotfix/crash",
+	}
+	assert.Equal(t, "Merges hotfix/crash into main", ExpandTemplate("Merges ${HeadBranch} into ${BaseBranch}", vars))
+	assert.Equal(t, "echo $HOME ${Unknown}", ExpandTemplate("echo $HOME ${Unknown}", vars))
+}
@@ -0,0 +1,280 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	nuget_module "code.gitea.io/gitea/modules/packages/nuget"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	rubygems_module "code.gitea.io/gitea/modules/packages/rubygems"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// PackageTypes are the package types release assets can be published as
+var PackageTypes = []packages_model.Type{
+	packages_model.TypeGeneric,
+	packages_model.TypeNuGet,
+	packages_model.TypePyPI,
+	packages_model.TypeRubyGems,
+}
+
+// ErrInvalidPackageRule represents an invalid release package rule
+type ErrInvalidPackageRule struct {
+	Rule string
+}
+
+// IsErrInvalidPackageRule checks if an error is a ErrInvalidPackageRule.
+func IsErrInvalidPackageRule(err error) bool {
+	_, ok := err.(ErrInvalidPackageRule)
+	return ok
+}
+
+func (err ErrInvalidPackageRule) Error() string {
+	return fmt.Sprintf("invalid package rule: %s", err.Rule)
+}
+
+// ParsePackageRules parses package rules in the format "<package type>: <pattern>", one rule per line
+func ParsePackageRules(s string) ([]*repo_model.ReleasePackageRule, error) {
+	rules := make([]*repo_model.ReleasePackageRule, 0, 2)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			return nil, ErrInvalidPackageRule{line}
+		}
+
+		packageType := strings.ToLower(strings.TrimSpace(fields[0]))
+		pattern := strings.TrimSpace(fields[1])
+
+		supported := false
+		for _, pt := range PackageTypes {
+			if string(pt) == packageType {
+				supported = true
+				break
+			}
+		}
+		if !supported || pattern == "" {
+			return nil, ErrInvalidPackageRule{line}
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, ErrInvalidPackageRule{line}
+		}
+
+		rules = append(rules, &repo_model.ReleasePackageRule{
+			Pattern:     pattern,
+			PackageType: packageType,
+		})
+	}
+	return rules, nil
+}
+
+// FormatPackageRules formats the package rules in the format accepted by ParsePackageRules
+func FormatPackageRules(rules []*repo_model.ReleasePackageRule) string {
+	lines := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		lines = append(lines, rule.PackageType+": "+rule.Pattern)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// packagesQueue represents a queue to publish release assets as packages
+var packagesQueue queue.UniqueQueue
+
+func handlePackages(data ...queue.Data) []queue.Data {
+	for _, datum := range data {
+		releaseID := datum.(int64)
+		if err := publishPackages(graceful.GetManager().HammerContext(), releaseID); err != nil {
+			log.Error("publishPackages(%d) failed: %v", releaseID, err)
+		}
+	}
+	return nil
+}
+
+// Init starts the queue which publishes release assets as packages
+func Init() error {
+	packagesQueue = queue.CreateUniqueQueue("release_packages", handlePackages, int64(0))
+	if packagesQueue == nil {
+		return errors.New("unable to create release_packages Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(packagesQueue.Run)
+	return nil
+}
+
+// PublishPackages queues the assets of the release to be published as packages
+func PublishPackages(rel *repo_model.Release) error {
+	if !setting.Packages.Enabled || rel.IsDraft || rel.IsTag || packagesQueue == nil {
+		return nil
+	}
+
+	if err := packagesQueue.Push(rel.ID); err != nil && err != queue.ErrAlreadyInQueue {
+		return err
+	}
+	return nil
+}
+
+// publishPackages publishes the release assets which match a package rule of the repository
+func publishPackages(ctx context.Context, releaseID int64) error {
+	rel, err := repo_model.GetReleaseByID(ctx, releaseID)
+	if err != nil {
+		if repo_model.IsErrReleaseNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if rel.IsDraft || rel.IsTag {
+		return nil
+	}
+
+	if err := rel.LoadAttributes(); err != nil {
+		return err
+	}
+
+	ru, err := rel.Repo.GetUnitCtx(ctx, unit.TypeReleases)
+	if err != nil {
+		if repo_model.IsErrUnitTypeNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	cfg := ru.ReleasesConfig()
+	if len(cfg.PackageRules) == 0 {
+		return nil
+	}
+
+	if err := rel.Repo.GetOwner(ctx); err != nil {
+		return err
+	}
+
+	// the publisher must be allowed to create packages for the owner of the repository
+	mode, err := packages_service.GetOwnerAccessMode(ctx, rel.Publisher, rel.Repo.Owner)
+	if err != nil {
+		return err
+	}
+	if mode < perm.AccessModeWrite {
+		log.Warn("Publisher %d of release %d has no package write access to %s, skipping the package rules", rel.PublisherID, rel.ID, rel.Repo.Owner.Name)
+		return nil
+	}
+
+	for _, attach := range rel.Attachments {
+		rule := cfg.MatchPackageRule(attach.Name)
+		if rule == nil {
+			continue
+		}
+
+		// a broken asset must not prevent the other assets from being published
+		if err := publishPackage(ctx, rel, attach, packages_model.Type(rule.PackageType)); err != nil {
+			log.Error("Unable to publish asset %s of release %d as %s package: %v", attach.Name, rel.ID, rule.PackageType, err)
+		}
+	}
+	return nil
+}
+
+func publishPackage(ctx context.Context, rel *repo_model.Release, attach *repo_model.Attachment, packageType packages_model.Type) error {
+	f, err := storage.Attachments.Open(attach.RelativePath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf, err := packages_module.CreateHashedBufferFromReader(f, 32*1024*1024)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	pci := &packages_service.PackageCreationInfo{
+		PackageInfo: packages_service.PackageInfo{
+			Owner:       rel.Repo.Owner,
+			PackageType: packageType,
+		},
+		SemverCompatible: true,
+		Creator:          rel.Publisher,
+	}
+	pfci := &packages_service.PackageFileCreationInfo{
+		PackageFileInfo: packages_service.PackageFileInfo{
+			Filename: attach.Name,
+		},
+		Data:   buf,
+		IsLead: true,
+	}
+
+	switch packageType {
+	case packages_model.TypeGeneric:
+		pci.Name = rel.Repo.Name
+		pci.Version = rel.TagName
+		pci.SemverCompatible = false
+	case packages_model.TypeNuGet:
+		np, err := nuget_module.ParsePackageMetaData(buf, buf.Size())
+		if err != nil {
+			return err
+		}
+		if np.PackageType != nuget_module.DependencyPackage {
+			return errors.New("unexpected package type")
+		}
+		pci.Name = np.ID
+		pci.Version = np.Version
+		pci.Metadata = np.Metadata
+		pfci.Filename = strings.ToLower(fmt.Sprintf("%s.%s.nupkg", np.ID, np.Version))
+	case packages_model.TypePyPI:
+		pp, err := pypi_module.ParseWheel(buf, buf.Size())
+		if err != nil {
+			return err
+		}
+		pci.Name = pp.Name
+		pci.Version = pp.Version
+		pci.Metadata = pp.Metadata
+	case packages_model.TypeRubyGems:
+		rp, err := rubygems_module.ParsePackageMetaData(buf)
+		if err != nil {
+			return err
+		}
+		pci.Name = rp.Name
+		pci.Version = rp.Version
+		pci.Metadata = rp.Metadata
+		if rp.Metadata.Platform == "" || rp.Metadata.Platform == "ruby" {
+			pfci.Filename = strings.ToLower(fmt.Sprintf("%s-%s.gem", rp.Name, rp.Version))
+		} else {
+			pfci.Filename = strings.ToLower(fmt.Sprintf("%s-%s-%s.gem", rp.Name, rp.Version, rp.Metadata.Platform))
+		}
+	default:
+		return fmt.Errorf("unsupported package type: %s", packageType)
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	pv, _, err := packages_service.CreatePackageOrAddFileToExisting(ctx, pci, pfci)
+	if err != nil {
+		if err == packages_model.ErrDuplicatePackageFile {
+			// the asset has been published already
+			return nil
+		}
+		return err
+	}
+
+	return packages_model.SetRepositoryLink(ctx, pv.PackageID, rel.RepoID)
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageRules(t *testing.T) {
+	rules, err := ParsePackageRules("nuget: *.nupkg\n\n  PyPI : *.whl  \ngeneric: dist-*.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.ReleasePackageRule{
+		{Pattern: "*.nupkg", PackageType: "nuget"},
+		{Pattern: "*.whl", PackageType: "pypi"},
+		{Pattern: "dist-*.tar.gz", PackageType: "generic"},
+	}, rules)
+	assert.Equal(t, "nuget: *.nupkg\npypi: *.whl\ngeneric: dist-*.tar.gz", FormatPackageRules(rules))
+
+	cfg := &repo_model.ReleasesConfig{PackageRules: rules}
+	assert.Equal(t, "pypi", cfg.MatchPackageRule("gitea-1.0-py3-none-any.whl").PackageType)
+	assert.Equal(t, "generic", cfg.MatchPackageRule("dist-linux.tar.gz").PackageType)
+	assert.Nil(t, cfg.MatchPackageRule("gitea.zip"))
+
+	for _, invalid := range []string{"*.nupkg", "unknown: *.zip", "nuget:"} {
+		rules, err := ParsePackageRules(invalid)
+		assert.Nil(t, rules)
+		assert.True(t, IsErrInvalidPackageRule(err), invalid)
+	}
+}
@@ -139,6 +139,10 @@ func CreateRelease(gitRepo *git.Repository, rel *repo_model.Release, attachmentU
 		notification.NotifyNewRelease(rel)
 	}
 
+	if err := PublishPackages(rel); err != nil {
+		log.Error("PublishPackages: %v", err)
+	}
+
 	return nil
 }
 
@@ -269,6 +273,10 @@ func UpdateRelease(doer *user_model.User, gitRepo *git.Repository, rel *repo_mod
 		}
 	}
 
+	if err := PublishPackages(rel); err != nil {
+		log.Error("PublishPackages: %v", err)
+	}
+
 	if !isCreated {
 		notification.NotifyUpdateRelease(doer, rel)
 		return
@@ -31,10 +31,37 @@ import (
 // This is entirely opaque to external entities, though, and mostly used as a
 // handle elsewhere.
 type ArchiveRequest struct {
-	RepoID   int64
-	refName  string
-	Type     git.ArchiveType
-	CommitID string
+	RepoID     int64
+	refName    string
+	isCommitID bool
+	Type       git.ArchiveType
+	CommitID   string
+}
+
+// DefaultArchiveType is the type of archives requested without a format
+const DefaultArchiveType = git.TARGZ
+
+// archiveTypes are the supported archive types, their string form is the file extension
+var archiveTypes = []git.ArchiveType{git.ZIP, git.TARGZ, git.BUNDLE, git.TARZST, git.TARXZ}
+
+// ArchiveTypeFromFormat returns the archive type of a format like "zip" or "tar.gz"
+func ArchiveTypeFromFormat(format string) (git.ArchiveType, error) {
+	for _, t := range archiveTypes {
+		if t.String() == format {
+			return t, nil
+		}
+	}
+	return 0, ErrUnknownArchiveFormat{RequestFormat: format}
+}
+
+// HasArchiveExtension checks if the URI ends with the extension of a supported archive type
+func HasArchiveExtension(uri string) bool {
+	for _, t := range archiveTypes {
+		if strings.HasSuffix(uri, "."+t.String()) {
+			return true
+		}
+	}
+	return false
 }
 
 // SHA1 hashes will only go up to 40 characters, but SHA256 hashes will go all
@@ -72,6 +99,9 @@ func (e RepoRefNotFoundError) Is(err error) bool {
 	return ok
 }
 
+// ErrArchiveFailed is returned if the archive could not be generated
+var ErrArchiveFailed = errors.New("archive generation failed")
+
 // NewRequest creates an archival request, based on the URI.  The
 // resulting ArchiveRequest is suitable for being passed to ArchiveRepository()
 // if it's determined that the request still needs to be satisfied.
@@ -80,23 +110,17 @@ func NewRequest(repoID int64, repo *git.Repository, uri string) (*ArchiveRequest
 		RepoID: repoID,
 	}
 
-	var ext string
-	switch {
-	case strings.HasSuffix(uri, ".zip"):
-		ext = ".zip"
-		r.Type = git.ZIP
-	case strings.HasSuffix(uri, ".tar.gz"):
-		ext = ".tar.gz"
-		r.Type = git.TARGZ
-	case strings.HasSuffix(uri, ".bundle"):
-		ext = ".bundle"
-		r.Type = git.BUNDLE
-	default:
+	for _, t := range archiveTypes {
+		if ext := "." + t.String(); strings.HasSuffix(uri, ext) {
+			r.Type = t
+			r.refName = strings.TrimSuffix(uri, ext)
+			break
+		}
+	}
+	if r.Type == 0 {
 		return nil, ErrUnknownArchiveFormat{RequestFormat: uri}
 	}
 
-	r.refName = strings.TrimSuffix(uri, ext)
-
 	var err error
 	// Get corresponding commit.
 	if repo.IsBranchExist(r.refName) {
@@ -110,13 +134,19 @@ func NewRequest(repoID int64, repo *git.Repository, uri string) (*ArchiveRequest
 			return nil, err
 		}
 	} else if shaRegex.MatchString(r.refName) {
-		if repo.IsCommitExist(r.refName) {
-			r.CommitID = r.refName
-		} else {
-			return nil, git.ErrNotExist{
-				ID: r.refName,
+		// resolve abbreviated commit ids, archives are stored by the full commit id
+		commitID, err := repo.ConvertToSHA1(r.refName)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return nil, git.ErrNotExist{ID: r.refName}
 			}
+			return nil, err
+		}
+		if !repo.IsCommitExist(commitID.String()) {
+			return nil, git.ErrNotExist{ID: r.refName}
 		}
+		r.CommitID = commitID.String()
+		r.isCommitID = true
 	} else {
 		return nil, RepoRefNotFoundError{RefName: r.refName}
 	}
@@ -124,12 +154,101 @@ func NewRequest(repoID int64, repo *git.Repository, uri string) (*ArchiveRequest
 	return r, nil
 }
 
+// NewRequestWithFormat creates an archival request like NewRequest. If the URI has no archive extension
+// but addresses a reference or a commit, the archive has the given format, which defaults to tar.gz.
+// A URI with an unknown extension is still reported as ErrUnknownArchiveFormat.
+func NewRequestWithFormat(repoID int64, repo *git.Repository, uri, format string) (*ArchiveRequest, error) {
+	if HasArchiveExtension(uri) {
+		return NewRequest(repoID, repo, uri)
+	}
+
+	archiveType := DefaultArchiveType
+	if format != "" {
+		var err error
+		if archiveType, err = ArchiveTypeFromFormat(format); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := NewRequest(repoID, repo, uri+"."+archiveType.String())
+	if errors.Is(err, RepoRefNotFoundError{}) || git.IsErrNotExist(err) {
+		return nil, ErrUnknownArchiveFormat{RequestFormat: uri}
+	}
+	return r, err
+}
+
+// CanonicalPath returns the path of the archive with the format as extension.
+// A commit id is expanded to the full commit id, so an archive requested
+// by different abbreviations of the same commit has a single URL.
+func (aReq *ArchiveRequest) CanonicalPath() string {
+	if aReq.isCommitID {
+		return aReq.CommitID + "." + aReq.Type.String()
+	}
+	return aReq.refName + "." + aReq.Type.String()
+}
+
 // GetArchiveName returns the name of the caller, based on the ref used by the
 // caller to create this request.
 func (aReq *ArchiveRequest) GetArchiveName() string {
 	return strings.ReplaceAll(aReq.refName, "/", "-") + "." + aReq.Type.String()
 }
 
+// ArchiveStatus is the state of an archive as reported to clients
+type ArchiveStatus string
+
+// enumerate all archive statuses
+const (
+	ArchiveStatusPending ArchiveStatus = "pending" // queued or being generated
+	ArchiveStatusReady   ArchiveStatus = "ready"
+	ArchiveStatusFailed  ArchiveStatus = "failed"
+)
+
+// Status returns the state of the archive. The RepoArchiver is only returned
+// if the archive is ready.
+func (aReq *ArchiveRequest) Status(ctx context.Context) (ArchiveStatus, *repo_model.RepoArchiver, error) {
+	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	if err != nil {
+		return "", nil, fmt.Errorf("models.GetRepoArchiver: %v", err)
+	}
+	if archiver == nil {
+		return ArchiveStatusPending, nil, nil
+	}
+
+	switch archiver.Status {
+	case repo_model.ArchiverReady:
+		return ArchiveStatusReady, archiver, nil
+	case repo_model.ArchiverFailed:
+		return ArchiveStatusFailed, nil, nil
+	}
+	return ArchiveStatusPending, nil, nil
+}
+
+// Enqueue queues the generation of the archive unless it is ready already.
+// A failed archive gets generated again. It returns the resulting status.
+func (aReq *ArchiveRequest) Enqueue(ctx context.Context) (ArchiveStatus, *repo_model.RepoArchiver, error) {
+	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	if err != nil {
+		return "", nil, fmt.Errorf("models.GetRepoArchiver: %v", err)
+	}
+
+	if archiver != nil {
+		switch archiver.Status {
+		case repo_model.ArchiverReady:
+			return ArchiveStatusReady, archiver, nil
+		case repo_model.ArchiverFailed:
+			// forget the failure so the status is pending until the new attempt finished
+			if err := repo_model.DeleteRepoArchiver(ctx, archiver); err != nil {
+				return "", nil, fmt.Errorf("models.DeleteRepoArchiver: %v", err)
+			}
+		}
+	}
+
+	if err := StartArchive(aReq); err != nil {
+		return "", nil, fmt.Errorf("archiver.StartArchive: %v", err)
+	}
+	return ArchiveStatusPending, nil, nil
+}
+
 // Await awaits the completion of an ArchiveRequest. If the archive has
 // already been prepared the method returns immediately. Otherwise an archiver
 // process will be started and its completion awaited. On success the returned
@@ -137,20 +256,15 @@ func (aReq *ArchiveRequest) GetArchiveName() string {
 // context is cancelled/times out a started archiver will still continue to run
 // in the background.
 func (aReq *ArchiveRequest) Await(ctx context.Context) (*repo_model.RepoArchiver, error) {
-	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	status, archiver, err := aReq.Enqueue(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("models.GetRepoArchiver: %v", err)
+		return nil, err
 	}
-
-	if archiver != nil && archiver.Status == repo_model.ArchiverReady {
+	if status == ArchiveStatusReady {
 		// Archive already generated, we're done.
 		return archiver, nil
 	}
 
-	if err := StartArchive(aReq); err != nil {
-		return nil, fmt.Errorf("archiver.StartArchive: %v", err)
-	}
-
 	poll := time.NewTicker(time.Second * 1)
 	defer poll.Stop()
 
@@ -166,8 +280,13 @@ func (aReq *ArchiveRequest) Await(ctx context.Context) (*repo_model.RepoArchiver
 			if err != nil {
 				return nil, fmt.Errorf("repo_model.GetRepoArchiver: %v", err)
 			}
-			if archiver != nil && archiver.Status == repo_model.ArchiverReady {
-				return archiver, nil
+			if archiver != nil {
+				switch archiver.Status {
+				case repo_model.ArchiverReady:
+					return archiver, nil
+				case repo_model.ArchiverFailed:
+					return nil, ErrArchiveFailed
+				}
 			}
 		}
 	}
@@ -190,8 +309,14 @@ func doArchive(r *ArchiveRequest) (*repo_model.RepoArchiver, error) {
 	if archiver != nil {
 		// FIXME: If another process are generating it, we think it's not ready and just return
 		// Or we should wait until the archive generated.
-		if archiver.Status == repo_model.ArchiverGenerating {
+		switch archiver.Status {
+		case repo_model.ArchiverGenerating:
 			return nil, nil
+		case repo_model.ArchiverFailed:
+			archiver.Status = repo_model.ArchiverGenerating
+			if err := repo_model.UpdateRepoArchiverStatus(ctx, archiver); err != nil {
+				return nil, err
+			}
 		}
 	} else {
 		archiver = &repo_model.RepoArchiver{
@@ -267,7 +392,9 @@ func doArchive(r *ArchiveRequest) (*repo_model.RepoArchiver, error) {
 	// TODO: add lfs data to zip
 	// TODO: add submodule data to zip
 
-	if _, err := storage.RepoArchives.Save(rPath, rd, -1); err != nil {
+	// the checksums are calculated while the archive is written, so they don't have to be calculated on every request
+	digests := newArchiveDigests()
+	if _, err := storage.RepoArchives.Save(rPath, digests.Reader(rd), -1); err != nil {
 		return nil, fmt.Errorf("unable to write archive: %v", err)
 	}
 
@@ -276,6 +403,13 @@ func doArchive(r *ArchiveRequest) (*repo_model.RepoArchiver, error) {
 		return nil, err
 	}
 
+	if err := digests.Store(archiver); err != nil {
+		return nil, fmt.Errorf("unable to write archive checksums: %v", err)
+	}
+	if err := signArchive(ctx, repo, archiver); err != nil {
+		log.Error("Unable to sign archive %s: %v", rPath, err)
+	}
+
 	if archiver.Status == repo_model.ArchiverGenerating {
 		archiver.Status = repo_model.ArchiverReady
 		if err = repo_model.UpdateRepoArchiverStatus(ctx, archiver); err != nil {
@@ -286,6 +420,29 @@ func doArchive(r *ArchiveRequest) (*repo_model.RepoArchiver, error) {
 	return archiver, committer.Commit()
 }
 
+// markArchiveFailed records the failed generation so clients polling the status don't wait forever
+func markArchiveFailed(r *ArchiveRequest) error {
+	ctx := graceful.GetManager().HammerContext()
+
+	archiver, err := repo_model.GetRepoArchiver(ctx, r.RepoID, r.Type, r.CommitID)
+	if err != nil {
+		return err
+	}
+	if archiver == nil {
+		return repo_model.AddRepoArchiver(ctx, &repo_model.RepoArchiver{
+			RepoID:   r.RepoID,
+			Type:     r.Type,
+			CommitID: r.CommitID,
+			Status:   repo_model.ArchiverFailed,
+		})
+	}
+	if archiver.Status == repo_model.ArchiverReady {
+		return nil
+	}
+	archiver.Status = repo_model.ArchiverFailed
+	return repo_model.UpdateRepoArchiverStatus(ctx, archiver)
+}
+
 // ArchiveRepository satisfies the ArchiveRequest being passed in.  Processing
 // will occur in a separate goroutine, as this phase may take a while to
 // complete.  If the archive already exists, ArchiveRepository will not do
@@ -310,6 +467,9 @@ func Init() error {
 			log.Trace("ArchiverData Process: %#v", archiveReq)
 			if _, err := doArchive(archiveReq); err != nil {
 				log.Error("Archive %v failed: %v", datum, err)
+				if err := markArchiveFailed(archiveReq); err != nil {
+					log.Error("Unable to mark archive %v as failed: %v", datum, err)
+				}
 			}
 		}
 		return nil
@@ -341,9 +501,10 @@ func deleteOldRepoArchiver(ctx context.Context, archiver *repo_model.RepoArchive
 	if err := repo_model.DeleteRepoArchiver(ctx, archiver); err != nil {
 		return err
 	}
-	p := archiver.RelativePath()
-	if err := storage.RepoArchives.Delete(p); err != nil {
-		log.Error("delete repo archive file failed: %v", err)
+	for _, p := range archiver.StoredPaths() {
+		if err := storage.RepoArchives.Delete(p); err != nil {
+			log.Error("delete repo archive file failed: %v", err)
+		}
 	}
 	return nil
 }
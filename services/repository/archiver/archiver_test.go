@@ -5,12 +5,16 @@
 package archiver
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/test"
 
 	"github.com/stretchr/testify/assert"
@@ -129,6 +133,40 @@ func TestArchive_Basic(t *testing.T) {
 	assert.NotEqual(t, zipReq.GetArchiveName(), secondReq.GetArchiveName())
 }
 
+func TestArchiveChecksum(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	ctx := test.MockContext(t, "user27/repo49")
+	test.LoadRepo(t, ctx, 49)
+	test.LoadGitRepo(t, ctx)
+	defer ctx.Repo.GitRepo.Close()
+
+	req, err := NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "51f84af23134.bundle")
+	assert.NoError(t, err)
+	archiver, err := ArchiveRepository(req)
+	assert.NoError(t, err)
+	assert.NotNil(t, archiver)
+
+	// the checksum is stored when the archive is generated
+	stored, err := readArchiveSuffix(archiver, SuffixSHA256)
+	assert.NoError(t, err)
+
+	fr, err := storage.RepoArchives.Open(archiver.RelativePath())
+	assert.NoError(t, err)
+	defer fr.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, fr)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(h.Sum(nil)), stored)
+
+	checksum, err := GetArchiveChecksum(archiver, SuffixSHA256, "repo49-51f84af23134.bundle")
+	assert.NoError(t, err)
+	assert.Equal(t, stored+"  repo49-51f84af23134.bundle\n", checksum)
+
+	_, err = GetArchiveChecksum(archiver, ".md5", "repo49-51f84af23134.bundle")
+	assert.Error(t, err)
+}
+
 func TestErrUnknownArchiveFormat(t *testing.T) {
 	err := ErrUnknownArchiveFormat{RequestFormat: "master"}
 	assert.True(t, errors.Is(err, ErrUnknownArchiveFormat{}))
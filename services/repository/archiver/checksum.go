@@ -0,0 +1,174 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package archiver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	asymkey_service "code.gitea.io/gitea/services/asymkey"
+)
+
+// Suffixes which can be appended to an archive URI to request a checksum or signature of the archive
+const (
+	SuffixSHA256    = ".sha256"
+	SuffixSHA512    = ".sha512"
+	SuffixSignature = ".asc"
+)
+
+// ErrArchiveSignatureUnavailable is returned if archives are not signed by the instance
+var ErrArchiveSignatureUnavailable = errors.New("archive signatures are not available")
+
+// SplitArchiveSuffix splits a checksum or signature suffix from the archive URI
+func SplitArchiveSuffix(uri string) (string, string) {
+	for _, suffix := range []string{SuffixSHA256, SuffixSHA512, SuffixSignature} {
+		if strings.HasSuffix(uri, suffix) {
+			return strings.TrimSuffix(uri, suffix), suffix
+		}
+	}
+	return uri, ""
+}
+
+// archiveDigests calculates the checksums of an archive while it is written to the storage
+type archiveDigests struct {
+	sha256 hash.Hash
+	sha512 hash.Hash
+}
+
+func newArchiveDigests() *archiveDigests {
+	return &archiveDigests{
+		sha256: sha256.New(),
+		sha512: sha512.New(),
+	}
+}
+
+// Reader returns a reader which calculates the checksums of the content read from r
+func (d *archiveDigests) Reader(r io.Reader) io.Reader {
+	return io.TeeReader(r, io.MultiWriter(d.sha256, d.sha512))
+}
+
+// Store saves the checksums next to the archive in the repo-archive storage
+func (d *archiveDigests) Store(archiver *repo_model.RepoArchiver) error {
+	for suffix, h := range map[string]hash.Hash{SuffixSHA256: d.sha256, SuffixSHA512: d.sha512} {
+		if err := saveArchiveSuffix(archiver, suffix, hex.EncodeToString(h.Sum(nil))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveArchiveSuffix(archiver *repo_model.RepoArchiver, suffix, content string) error {
+	_, err := storage.RepoArchives.Save(archiver.RelativePath()+suffix, strings.NewReader(content), int64(len(content)))
+	return err
+}
+
+func readArchiveSuffix(archiver *repo_model.RepoArchiver, suffix string) (string, error) {
+	// the object storage only reports a missing object on stat
+	if _, err := storage.RepoArchives.Stat(archiver.RelativePath() + suffix); err != nil {
+		return "", err
+	}
+
+	fr, err := storage.RepoArchives.Open(archiver.RelativePath() + suffix)
+	if err != nil {
+		return "", err
+	}
+	defer fr.Close()
+
+	content, err := io.ReadAll(fr)
+	return string(content), err
+}
+
+// signArchive creates the detached signature of a generated archive and saves it next to the archive
+func signArchive(ctx context.Context, repo *repo_model.Repository, archiver *repo_model.RepoArchiver) error {
+	if !setting.Repository.Signing.Archives {
+		return nil
+	}
+
+	fr, err := storage.RepoArchives.Open(archiver.RelativePath())
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	signature, err := asymkey_service.SignDetached(ctx, repo.RepoPath(), fr)
+	if err != nil || signature == "" {
+		return err
+	}
+	return saveArchiveSuffix(archiver, SuffixSignature, signature)
+}
+
+// GetArchiveChecksum returns the checksum file content of the archive in the format of the sha256sum/sha512sum tools.
+// The checksums are calculated when the archive is generated, they are only calculated on request
+// (once) for archives which were generated before the checksums were stored.
+func GetArchiveChecksum(archiver *repo_model.RepoArchiver, suffix, downloadName string) (string, error) {
+	if suffix != SuffixSHA256 && suffix != SuffixSHA512 {
+		return "", ErrUnknownArchiveFormat{RequestFormat: suffix}
+	}
+
+	checksum, err := readArchiveSuffix(archiver, suffix)
+	if errors.Is(err, os.ErrNotExist) {
+		checksum, err = calculateArchiveChecksum(archiver, suffix)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return checksum + "  " + downloadName + "\n", nil
+}
+
+func calculateArchiveChecksum(archiver *repo_model.RepoArchiver, suffix string) (string, error) {
+	fr, err := storage.RepoArchives.Open(archiver.RelativePath())
+	if err != nil {
+		return "", err
+	}
+	defer fr.Close()
+
+	digests := newArchiveDigests()
+	if _, err := io.Copy(io.Discard, digests.Reader(fr)); err != nil {
+		return "", err
+	}
+	if err := digests.Store(archiver); err != nil {
+		return "", err
+	}
+
+	if suffix == SuffixSHA256 {
+		return hex.EncodeToString(digests.sha256.Sum(nil)), nil
+	}
+	return hex.EncodeToString(digests.sha512.Sum(nil)), nil
+}
+
+// GetArchiveSignature returns the armored detached signature of the archive created with the signing key of the instance
+// when the archive was generated. Archives generated before signing was enabled are signed on request (once).
+func GetArchiveSignature(ctx context.Context, repo *repo_model.Repository, archiver *repo_model.RepoArchiver) (string, error) {
+	if !setting.Repository.Signing.Archives {
+		return "", ErrArchiveSignatureUnavailable
+	}
+
+	signature, err := readArchiveSuffix(archiver, SuffixSignature)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := signArchive(ctx, repo, archiver); err != nil {
+			return "", err
+		}
+		signature, err = readArchiveSuffix(archiver, SuffixSignature)
+		if errors.Is(err, os.ErrNotExist) {
+			// the instance has no signing key
+			return "", ErrArchiveSignatureUnavailable
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return signature, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// badge colors of shields.io
+const (
+	badgeColorSuccess  = "brightgreen"
+	badgeColorWarning  = "yellow"
+	badgeColorFailure  = "red"
+	badgeColorInfo     = "blue"
+	badgeColorInactive = "lightgrey"
+)
+
+// maxLicenseFileSize limits the content of license files read to detect the license
+const maxLicenseFileSize = 64 * 1024
+
+func newBadge(label, message, color string) *api.Badge {
+	return &api.Badge{
+		SchemaVersion: 1,
+		Label:         label,
+		Message:       message,
+		Color:         color,
+	}
+}
+
+// ReleaseBadge returns the badge of the latest stable release of the repository
+func ReleaseBadge(repo *repo_model.Repository) (*api.Badge, error) {
+	rel, err := repo_model.GetLatestReleaseByRepoID(repo.ID)
+	if err != nil {
+		if repo_model.IsErrReleaseNotExist(err) {
+			return newBadge("release", "none", badgeColorInactive), nil
+		}
+		return nil, err
+	}
+	return newBadge("release", rel.TagName, badgeColorInfo), nil
+}
+
+// IssuesBadge returns the badge of the open issues of the repository
+func IssuesBadge(repo *repo_model.Repository) *api.Badge {
+	color := badgeColorWarning
+	if repo.NumOpenIssues == 0 {
+		color = badgeColorSuccess
+	}
+	return newBadge("issues", fmt.Sprintf("%d open", repo.NumOpenIssues), color)
+}
+
+// BuildBadge returns the badge of the combined commit status of the head of a branch
+func BuildBadge(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, branch string) (*api.Badge, error) {
+	commitID, err := gitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return newBadge("build", "unknown", badgeColorInactive), nil
+		}
+		return nil, err
+	}
+
+	statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, commitID, db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status := git_model.CalcCommitStatus(statuses)
+	if status == nil {
+		return newBadge("build", "unknown", badgeColorInactive), nil
+	}
+
+	color := badgeColorFailure
+	switch status.State {
+	case api.CommitStatusSuccess:
+		color = badgeColorSuccess
+	case api.CommitStatusPending, api.CommitStatusWarning:
+		color = badgeColorWarning
+	}
+	return newBadge("build", string(status.State), color), nil
+}
+
+// LicenseBadge returns the badge of the license found in the root directory of the default branch
+func LicenseBadge(repo *repo_model.Repository, gitRepo *git.Repository) (*api.Badge, error) {
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return newBadge("license", "none", badgeColorInactive), nil
+		}
+		return nil, err
+	}
+	entries, err := commit.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var licenseEntry *git.TreeEntry
+	preference := -1
+	for _, entry := range entries {
+		if !entry.IsRegular() {
+			continue
+		}
+		if p := repo_module.IsLicenseFile(entry.Name()); p >= 0 && (preference < 0 || p < preference) {
+			licenseEntry, preference = entry, p
+		}
+	}
+	if licenseEntry == nil {
+		return newBadge("license", "none", badgeColorInactive), nil
+	}
+
+	r, err := licenseEntry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(io.LimitReader(r, maxLicenseFileSize))
+	if err != nil {
+		return nil, err
+	}
+
+	license := repo_module.DetectLicense(content)
+	if license == "" {
+		return newBadge("license", "other", badgeColorInfo), nil
+	}
+	return newBadge("license", license, badgeColorInfo), nil
+}
+
+// PackageBadge returns the badge of the latest version of a package linked to the repository.
+// Nil is returned if there is no such package.
+func PackageBadge(ctx context.Context, repo *repo_model.Repository, packageType packages_model.Type, name string) (*api.Badge, error) {
+	pvs, _, err := packages_model.SearchLatestVersions(ctx, &packages_model.PackageSearchOptions{
+		OwnerID:     repo.OwnerID,
+		RepoID:      repo.ID,
+		Type:        packageType,
+		Name:        packages_model.SearchValue{Value: name, ExactMatch: true},
+		IsInternal:  util.OptionalBoolFalse,
+		HidePrivate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pvs) == 0 {
+		return nil, nil
+	}
+	return newBadge(name, pvs[0].Version, badgeColorInfo), nil
+}
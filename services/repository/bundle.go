@@ -0,0 +1,240 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const bundleRefPrefix = "refs/bundle/"
+
+// ErrInvalidBundle represents a "InvalidBundle" kind of error.
+type ErrInvalidBundle struct {
+	Reason string
+}
+
+// IsErrInvalidBundle checks if an error is a ErrInvalidBundle.
+func IsErrInvalidBundle(err error) bool {
+	_, ok := err.(ErrInvalidBundle)
+	return ok
+}
+
+func (err ErrInvalidBundle) Error() string {
+	return fmt.Sprintf("invalid git bundle: %s", err.Reason)
+}
+
+// ErrBundleNotFastForward represents a "BundleNotFastForward" kind of error.
+type ErrBundleNotFastForward struct {
+	RefName string
+}
+
+// IsErrBundleNotFastForward checks if an error is a ErrBundleNotFastForward.
+func IsErrBundleNotFastForward(err error) bool {
+	_, ok := err.(ErrBundleNotFastForward)
+	return ok
+}
+
+func (err ErrBundleNotFastForward) Error() string {
+	return fmt.Sprintf("reference of the git bundle is not a fast-forward [ref: %s]", err.RefName)
+}
+
+// ErrBundleTooLarge represents a "BundleTooLarge" kind of error.
+type ErrBundleTooLarge struct {
+	MaxSize int64
+}
+
+// IsErrBundleTooLarge checks if an error is a ErrBundleTooLarge.
+func IsErrBundleTooLarge(err error) bool {
+	_, ok := err.(ErrBundleTooLarge)
+	return ok
+}
+
+func (err ErrBundleTooLarge) Error() string {
+	return fmt.Sprintf("git bundle is larger than %s", base.FileSize(err.MaxSize))
+}
+
+// BundleRefUpdate is a reference of a repository updated by a git bundle
+type BundleRefUpdate struct {
+	RefName     string
+	OldCommitID string // empty if the reference was created
+	NewCommitID string
+}
+
+// UploadBundle updates the branches and tags of a repository with the ones of a git bundle.
+// Existing branches must be fast-forwarded and existing tags must not change.
+// The references are pushed as the doer, so the branch protections apply.
+func UploadBundle(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, bundle io.Reader) ([]*BundleRefUpdate, error) {
+	tmpPath, err := repo_module.CreateTemporaryPath("bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := repo_module.RemoveTemporaryPath(tmpPath); err != nil {
+			log.Error("UploadBundle: RemoveTemporaryPath: %v", err)
+		}
+	}()
+
+	bundlePath := filepath.Join(tmpPath, "upload.bundle")
+	f, err := os.Create(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := setting.Repository.Upload.BundleMaxSize << 20
+	n, err := io.Copy(f, io.LimitReader(bundle, maxSize+1))
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	if n > maxSize {
+		return nil, ErrBundleTooLarge{MaxSize: maxSize}
+	}
+
+	// the shared clone sees the objects of the repository, so the prerequisites of the bundle can be verified
+	repoPath := filepath.Join(tmpPath, "repo.git")
+	if err := git.Clone(ctx, repo.RepoPath(), repoPath, git.CloneRepoOptions{Bare: true, Shared: true, Quiet: true}); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+
+	if _, stderr, err := git.NewCommand(ctx, "bundle", "verify", "--quiet", bundlePath).RunStdString(&git.RunOpts{Dir: repoPath}); err != nil {
+		return nil, ErrInvalidBundle{strings.TrimSpace(stderr)}
+	}
+	heads, _, err := git.NewCommand(ctx, "bundle", "list-heads", bundlePath).RunStdString(&git.RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, err
+	}
+
+	headCommitID := ""
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(heads), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if fields[1] == "HEAD" {
+			headCommitID = fields[0]
+		} else if strings.HasPrefix(fields[1], git.BranchPrefix) || strings.HasPrefix(fields[1], git.TagPrefix) {
+			refs[fields[1]] = fields[0]
+		}
+	}
+	if len(refs) == 0 {
+		return nil, ErrInvalidBundle{"the bundle contains no branches or tags"}
+	}
+
+	if _, stderr, err := git.NewCommand(ctx, "fetch", "--no-tags", "--quiet", bundlePath,
+		"+"+git.BranchPrefix+"*:"+bundleRefPrefix+"heads/*",
+		"+"+git.TagPrefix+"*:"+bundleRefPrefix+"tags/*").RunStdString(&git.RunOpts{Dir: repoPath}); err != nil {
+		return nil, ErrInvalidBundle{strings.TrimSpace(stderr)}
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	updates := make([]*BundleRefUpdate, 0, len(refs))
+	for refName, newCommitID := range refs {
+		oldCommitID, err := gitRepo.GetRefCommitID(refName)
+		if err != nil {
+			if !git.IsErrNotExist(err) {
+				return nil, err
+			}
+			oldCommitID = ""
+		}
+		if oldCommitID == newCommitID {
+			continue
+		}
+		if oldCommitID != "" {
+			if strings.HasPrefix(refName, git.TagPrefix) {
+				return nil, ErrBundleNotFastForward{refName}
+			}
+			if err := git.NewCommand(ctx, "merge-base", "--is-ancestor", oldCommitID, newCommitID).Run(&git.RunOpts{Dir: repoPath}); err != nil {
+				return nil, ErrBundleNotFastForward{refName}
+			}
+		}
+		updates = append(updates, &BundleRefUpdate{
+			RefName:     refName,
+			OldCommitID: oldCommitID,
+			NewCommitID: newCommitID,
+		})
+	}
+	if len(updates) == 0 {
+		return updates, nil
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].RefName < updates[j].RefName
+	})
+
+	// the first pushed branch becomes the default branch of an empty repository, so it is pushed on its own
+	refspecs := make([]string, 0, 3)
+	hasBranches, hasTags := false, false
+	for _, u := range updates {
+		if strings.HasPrefix(u.RefName, git.BranchPrefix) {
+			hasBranches = true
+		} else {
+			hasTags = true
+		}
+	}
+	if repo.IsEmpty {
+		if branch := bundleDefaultBranch(repo, updates, headCommitID); branch != "" {
+			refspecs = append(refspecs, bundleRefPrefix+"heads/"+branch+":"+git.BranchPrefix+branch)
+		}
+	}
+	if hasBranches {
+		refspecs = append(refspecs, bundleRefPrefix+"heads/*:"+git.BranchPrefix+"*")
+	}
+	if hasTags {
+		refspecs = append(refspecs, bundleRefPrefix+"tags/*:"+git.TagPrefix+"*")
+	}
+
+	env := repo_module.PushingEnvironment(doer, repo)
+	for _, refspec := range refspecs {
+		if err := git.Push(ctx, repoPath, git.PushOptions{
+			Remote: repo.RepoPath(),
+			Branch: refspec,
+			Env:    env,
+		}); err != nil {
+			if git.IsErrPushOutOfDate(err) {
+				return nil, ErrBundleNotFastForward{strings.SplitN(refspec, ":", 2)[1]}
+			}
+			return nil, err
+		}
+	}
+	return updates, nil
+}
+
+// bundleDefaultBranch returns the branch of the bundle to become the default branch of an empty repository,
+// the default branch of the repository if the bundle has it or else the branch the HEAD of the bundle points to
+func bundleDefaultBranch(repo *repo_model.Repository, updates []*BundleRefUpdate, headCommitID string) string {
+	branch := ""
+	for _, u := range updates {
+		if !strings.HasPrefix(u.RefName, git.BranchPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(u.RefName, git.BranchPrefix)
+		if name == repo.DefaultBranch {
+			return name
+		}
+		if branch == "" && u.NewCommitID == headCommitID {
+			branch = name
+		}
+	}
+	return branch
+}
@@ -6,14 +6,82 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"strings"
 
+	git_model "code.gitea.io/gitea/models/git"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/setting"
 )
 
+// CacheRefRequest represents a request to cache the last commit information of a ref
+type CacheRefRequest struct {
+	RepoID      int64
+	RefFullName string
+}
+
+// cacheRefQueue represents a queue to handle last commit cache updates
+var cacheRefQueue queue.UniqueQueue
+
+func handleCacheRef(data ...queue.Data) []queue.Data {
+	for _, datum := range data {
+		req, ok := datum.(*CacheRefRequest)
+		if !ok {
+			log.Error("Unable to process provided datum: %v - not possible to cast to CacheRefRequest", datum)
+			continue
+		}
+		if err := cacheRefByRepoID(graceful.GetManager().HammerContext(), req.RepoID, req.RefFullName); err != nil {
+			log.Error("CacheRef %d %s failed: %v", req.RepoID, req.RefFullName, err)
+		}
+	}
+	return nil
+}
+
+func initCacheRefQueue() error {
+	cacheRefQueue = queue.CreateUniqueQueue("last_commit_cache", handleCacheRef, new(CacheRefRequest))
+	if cacheRefQueue == nil {
+		return errors.New("unable to create last_commit_cache Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(cacheRefQueue.Run)
+	return nil
+}
+
+// QueueCacheRef adds the ref to the queue to cache its last commit information
+func QueueCacheRef(repo *repo_model.Repository, fullRefName string) error {
+	if !setting.CacheService.LastCommit.Enabled {
+		return nil
+	}
+
+	if err := cacheRefQueue.Push(&CacheRefRequest{RepoID: repo.ID, RefFullName: fullRefName}); err != nil && err != queue.ErrAlreadyInQueue {
+		return err
+	}
+	return nil
+}
+
+func cacheRefByRepoID(ctx context.Context, repoID int64, fullRefName string) error {
+	repo, err := repo_model.GetRepositoryByIDCtx(ctx, repoID)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	return CacheRef(ctx, repo, gitRepo, fullRefName)
+}
+
 func getRefName(fullRefName string) string {
 	if strings.HasPrefix(fullRefName, git.TagPrefix) {
 		return fullRefName[len(git.TagPrefix):]
@@ -39,7 +107,7 @@ func CacheRef(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Rep
 		if err != nil {
 			return err
 		}
-		gitRepo.LastCommitCache = git.NewLastCommitCache(commitsCount, repo.FullName(), gitRepo, cache.GetCache())
+		gitRepo.LastCommitCache = git.NewLastCommitCache(commitsCount, repo.FullName(), gitRepo, git_model.GetLastCommitStore(repo.ID))
 	}
 
 	return commit.CacheCommit(ctx)
@@ -0,0 +1,115 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// RevokeExpiredCollaborations removes the collaborations which expired and
+// reminds the collaborators whose access expires within remindBefore.
+func RevokeExpiredCollaborations(ctx context.Context, remindBefore time.Duration) error {
+	now := timeutil.TimeStampNow()
+
+	expired, _, err := repo_model.FindCollaborations(ctx, &repo_model.FindCollaborationsOptions{
+		ExpiresBefore: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range expired {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before revoking collaboration %d", c.ID)
+		default:
+		}
+
+		repo, u, err := loadCollaboration(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			// the user does not exist anymore, just drop the relation
+			if _, err := db.DeleteByBean(ctx, &repo_model.Collaboration{ID: c.ID}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := models.DeleteCollaboration(repo, c.UserID); err != nil {
+			return err
+		}
+		log.Trace("Revoked expired collaboration of %-v on %-v", u, repo)
+
+		if err := mailer.SendCollaboratorExpiryMail(u, repo, c.ExpiresUnix, true); err != nil {
+			log.Error("SendCollaboratorExpiryMail: %v", err)
+		}
+	}
+
+	if remindBefore <= 0 {
+		return nil
+	}
+
+	expiring, _, err := repo_model.FindCollaborations(ctx, &repo_model.FindCollaborationsOptions{
+		ExpiresBefore: now.AddDuration(remindBefore),
+		NotReminded:   true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range expiring {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before reminding collaboration %d", c.ID)
+		default:
+		}
+
+		repo, u, err := loadCollaboration(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		if u != nil {
+			if err := mailer.SendCollaboratorExpiryMail(u, repo, c.ExpiresUnix, false); err != nil {
+				log.Error("SendCollaboratorExpiryMail: %v", err)
+			}
+		}
+
+		if err := repo_model.MarkCollaborationReminded(ctx, c.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadCollaboration loads the repository and the user of the collaboration, the user is nil if it does not exist
+func loadCollaboration(ctx context.Context, c *repo_model.Collaboration) (*repo_model.Repository, *user_model.User, error) {
+	repo, err := repo_model.GetRepositoryByIDCtx(ctx, c.RepoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u, err := user_model.GetUserByIDCtx(ctx, c.UserID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return repo, nil, nil
+		}
+		return nil, nil, err
+	}
+	return repo, u, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeExpiredCollaborations(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	now := timeutil.TimeStampNow()
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 4, now.Add(-60)))
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 29, now.Add(3600)))
+
+	assert.NoError(t, RevokeExpiredCollaborations(db.DefaultContext, 24*time.Hour))
+
+	unittest.AssertNotExistsBean(t, &repo_model.Collaboration{RepoID: 4, UserID: 4})
+	unittest.AssertNotExistsBean(t, &access_model.Access{RepoID: 4, UserID: 4})
+
+	reminded := unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: 4, UserID: 29})
+	assert.NotZero(t, reminded.RemindedUnix)
+
+	// collaborations without expiry are untouched
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: 3, UserID: 2})
+
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: 4})
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/outline"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ErrNotAFile represents a "NotAFile" kind of error.
+type ErrNotAFile struct {
+	Path string
+}
+
+// IsErrNotAFile checks if an error is a ErrNotAFile.
+func IsErrNotAFile(err error) bool {
+	_, ok := err.(ErrNotAFile)
+	return ok
+}
+
+func (err ErrNotAFile) Error() string {
+	return fmt.Sprintf("path is not a file [path: %s]", err.Path)
+}
+
+// GetFileOutline gets the outline of the functions, types and headings of a file. Ref can be a branch, commit or tag
+func GetFileOutline(ctx context.Context, repo *repo_model.Repository, treePath, ref string) (*api.FileOutline, error) {
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+
+	// Check that the path given in opts.treePath is valid (not a git path)
+	cleanTreePath := CleanUploadFileName(treePath)
+	if cleanTreePath == "" {
+		return nil, models.ErrFilenameInvalid{
+			Path: treePath,
+		}
+	}
+	treePath = cleanTreePath
+
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, ErrNotAFile{Path: treePath}
+	}
+
+	o, err := outline.GetBlobOutline(ctx, entry.Blob(), treePath)
+	if err != nil {
+		return nil, err
+	}
+	return &api.FileOutline{
+		Path:     treePath,
+		SHA:      entry.ID.String(),
+		Language: o.Language,
+		Symbols:  toOutlineSymbols(o.Symbols),
+	}, nil
+}
+
+func toOutlineSymbols(symbols []*outline.Symbol) []*api.OutlineSymbol {
+	result := make([]*api.OutlineSymbol, 0, len(symbols))
+	for _, s := range symbols {
+		result = append(result, &api.OutlineSymbol{
+			Name:     s.Name,
+			Kind:     string(s.Kind),
+			Line:     s.Line,
+			EndLine:  s.EndLine,
+			Children: toOutlineSymbols(s.Children),
+		})
+	}
+	return result
+}
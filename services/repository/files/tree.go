@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -73,6 +75,13 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	} else {
 		rangeEnd = len(entries)
 	}
+
+	// the sha is not necessarily a commit, without one the submodules can't be looked up in .gitmodules
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		commit = nil
+	}
+
 	tree.Entries = make([]api.GitEntry, rangeEnd-rangeStart)
 	for e := rangeStart; e < rangeEnd; e++ {
 		i := e - rangeStart
@@ -90,6 +99,42 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 			copy(blobURL[copyPos:], entries[e].ID.String())
 			tree.Entries[i].URL = string(blobURL)
 		}
+
+		if entries[e].IsLink() {
+			target, err := entries[e].Blob().GetBlobContent()
+			if err != nil {
+				return nil, err
+			}
+			inRepo := isSymlinkTargetInTree(gitTree, entries[e].Name(), target)
+			tree.Entries[i].Target = &target
+			tree.Entries[i].TargetInRepo = &inRepo
+		} else if entries[e].IsSubModule() && commit != nil {
+			submodule, err := commit.GetSubModule(entries[e].Name())
+			if err != nil {
+				return nil, err
+			}
+			if submodule != nil {
+				refURL := git.NewSubModuleFile(commit, submodule.URL, entries[e].ID.String()).RefURL(setting.AppURL, repo.FullName(), setting.SSH.Domain)
+				tree.Entries[i].SubmoduleGitURL = &submodule.URL
+				tree.Entries[i].SubmoduleURL = &refURL
+			}
+		}
 	}
 	return tree, nil
 }
+
+// isSymlinkTargetInTree checks if the target of the symlink at linkPath is an existing entry of the tree
+func isSymlinkTargetInTree(tree *git.Tree, linkPath, target string) bool {
+	if path.IsAbs(target) {
+		return false
+	}
+	targetPath := path.Join(path.Dir(linkPath), target)
+	if targetPath == "." {
+		return true
+	}
+	if targetPath == ".." || strings.HasPrefix(targetPath, "../") {
+		return false
+	}
+	_, err := tree.GetTreeEntryByPath(targetPath)
+	return err == nil
+}
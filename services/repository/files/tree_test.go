@@ -51,3 +51,21 @@ func TestGetTreeBySHA(t *testing.T) {
 
 	assert.EqualValues(t, expectedTree, tree)
 }
+
+func TestIsSymlinkTargetInTree(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	ctx := test.MockContext(t, "user2/repo1")
+	test.LoadRepo(t, ctx, 1)
+	test.LoadGitRepo(t, ctx)
+	defer ctx.Repo.GitRepo.Close()
+
+	tree, err := ctx.Repo.GitRepo.GetTree(ctx.Repo.Repository.DefaultBranch)
+	assert.NoError(t, err)
+
+	assert.True(t, isSymlinkTargetInTree(tree, "link", "README.md"))
+	assert.True(t, isSymlinkTargetInTree(tree, "docs/link", "../README.md"))
+	assert.True(t, isSymlinkTargetInTree(tree, "link", "."))
+	assert.False(t, isSymlinkTargetInTree(tree, "link", "missing.md"))
+	assert.False(t, isSymlinkTargetInTree(tree, "link", "../README.md"))
+	assert.False(t, isSymlinkTargetInTree(tree, "link", "/etc/passwd"))
+}
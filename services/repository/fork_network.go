@@ -0,0 +1,147 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ForkNetworkNode is a repository of a fork network together with its forks
+type ForkNetworkNode struct {
+	Repo  *repo_model.Repository
+	Forks []*ForkNetworkNode
+}
+
+// ForkComparison is the difference between a branch of a fork and a branch of the root of its fork network
+type ForkComparison struct {
+	Root       *repo_model.Repository
+	RootBranch string
+	Branch     string
+	MergeBase  string
+	AheadBy    int
+	BehindBy   int
+}
+
+func canReadRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) (bool, error) {
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanRead(unit.TypeCode), nil
+}
+
+// GetRootRepository returns the topmost repository of the fork network of the repository which the doer can read.
+// A repository which isn't a fork, or whose parent the doer can't read, is its own root.
+func GetRootRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) (*repo_model.Repository, error) {
+	root := repo
+	visited := map[int64]bool{repo.ID: true}
+	for root.IsFork && !visited[root.ForkID] {
+		parent, err := repo_model.GetRepositoryByIDCtx(ctx, root.ForkID)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				break
+			}
+			return nil, err
+		}
+		if canRead, err := canReadRepository(ctx, doer, parent); err != nil {
+			return nil, err
+		} else if !canRead {
+			break
+		}
+		visited[parent.ID] = true
+		root = parent
+	}
+	return root, nil
+}
+
+// MaxForkNetworkDepth is the maximum number of levels of forks below the root of a fork network
+const MaxForkNetworkDepth = 10
+
+// GetForkNetwork returns the tree of the forks, including the forks of forks, starting at the root of the fork network of the repository.
+// Repositories the doer can't read are left out together with their forks. Only forks at most maxDepth levels below the root are included
+// and at most maxForks forks are checked, so the size of the tree and the number of permission checks are limited.
+func GetForkNetwork(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, maxDepth, maxForks int) (*ForkNetworkNode, error) {
+	root, err := GetRootRepository(ctx, doer, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	rootNode := &ForkNetworkNode{Repo: root}
+	nodes := map[int64]*ForkNetworkNode{root.ID: rootNode}
+	parentIDs := []int64{root.ID}
+	checked := 0
+	for depth := 0; depth < maxDepth && len(parentIDs) > 0 && checked < maxForks; depth++ {
+		forks, err := repo_model.GetForksOfRepositories(ctx, parentIDs, maxForks-checked)
+		if err != nil {
+			return nil, err
+		}
+
+		parentIDs = make([]int64, 0, len(forks))
+		for _, fork := range forks {
+			checked++
+			if _, has := nodes[fork.ID]; has {
+				continue
+			}
+			if canRead, err := canReadRepository(ctx, doer, fork); err != nil {
+				return nil, err
+			} else if !canRead {
+				continue
+			}
+
+			node := &ForkNetworkNode{Repo: fork}
+			nodes[fork.ID] = node
+			nodes[fork.ForkID].Forks = append(nodes[fork.ForkID].Forks, node)
+			parentIDs = append(parentIDs, fork.ID)
+		}
+	}
+	return rootNode, nil
+}
+
+// CompareForkWithRoot counts the commits the branch of the fork is ahead and behind the branch of the root repository
+func CompareForkWithRoot(ctx context.Context, fork, root *repo_model.Repository, branch, rootBranch string) (*ForkComparison, error) {
+	gitRepo, err := git.OpenRepository(ctx, fork.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	tmpRemote := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := gitRepo.AddRemote(tmpRemote, root.RepoPath(), false); err != nil {
+		return nil, fmt.Errorf("AddRemote: %w", err)
+	}
+	defer func() {
+		if err := gitRepo.RemoveRemote(tmpRemote); err != nil {
+			log.Error("CompareForkWithRoot: RemoveRemote: %v", err)
+		}
+	}()
+
+	mergeBase, rootRef, err := gitRepo.GetMergeBase(tmpRemote, rootBranch, branch)
+	if err != nil {
+		return nil, fmt.Errorf("GetMergeBase: %w", err)
+	}
+	diverging, err := git.GetDivergingCommits(ctx, fork.RepoPath(), rootRef, branch)
+	if err != nil {
+		return nil, fmt.Errorf("GetDivergingCommits: %w", err)
+	}
+
+	return &ForkComparison{
+		Root:       root,
+		RootBranch: rootBranch,
+		Branch:     branch,
+		MergeBase:  mergeBase,
+		AheadBy:    diverging.Ahead,
+		BehindBy:   diverging.Behind,
+	}, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetForkNetwork(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repo11 of user13 is a fork of repo10
+	repo10 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 10})
+	network, err := GetForkNetwork(db.DefaultContext, nil, repo10, MaxForkNetworkDepth, 50)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, network.Repo.ID)
+	if assert.Len(t, network.Forks, 1) {
+		assert.EqualValues(t, 11, network.Forks[0].Repo.ID)
+		assert.Empty(t, network.Forks[0].Forks)
+	}
+
+	// the private fork 30 of the private repository 28 is hidden from users who can't read them
+	repo28 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 28})
+	network, err = GetForkNetwork(db.DefaultContext, nil, repo28, MaxForkNetworkDepth, 50)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 28, network.Repo.ID)
+	assert.Empty(t, network.Forks)
+
+	repo30 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 30})
+	root, err := GetRootRepository(db.DefaultContext, nil, repo30)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 30, root.ID)
+
+	// user20 owns the fork and is a member of the team of repository 28
+	user20 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 20})
+	network, err = GetForkNetwork(db.DefaultContext, user20, repo30, MaxForkNetworkDepth, 50)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 28, network.Repo.ID)
+	if assert.Len(t, network.Forks, 1) {
+		assert.EqualValues(t, 30, network.Forks[0].Repo.ID)
+	}
+
+	// the forks below the maximum depth are left out
+	network, err = GetForkNetwork(db.DefaultContext, nil, repo10, 0, 50)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, network.Repo.ID)
+	assert.Empty(t, network.Forks)
+
+	// the forks above the maximum count are left out
+	network, err = GetForkNetwork(db.DefaultContext, nil, repo10, MaxForkNetworkDepth, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, network.Repo.ID)
+	assert.Empty(t, network.Forks)
+}
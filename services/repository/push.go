@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	issue_service "code.gitea.io/gitea/services/issue"
+	pages_service "code.gitea.io/gitea/services/pages"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
 
@@ -249,6 +250,9 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				} else {
 					commits.CompareURL = ""
 				}
+				if commits.IsLargePush() {
+					commits.CommitsURL = repo.ComposeCommitsURL(opts.NewCommitID)
+				}
 
 				notification.NotifyPushCommits(pusher, repo, opts, commits)
 
@@ -257,8 +261,12 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				}
 
 				// Cache for big repository
-				if err := CacheRef(graceful.GetManager().HammerContext(), repo, gitRepo, opts.RefFullName); err != nil {
-					log.Error("repo_module.CacheRef %s/%s failed: %v", repo.ID, branch, err)
+				if err := QueueCacheRef(repo, opts.RefFullName); err != nil {
+					log.Error("QueueCacheRef %d/%s failed: %v", repo.ID, branch, err)
+				}
+
+				if err := pages_service.TriggerDeploy(ctx, repo, branch); err != nil {
+					log.Error("TriggerDeploy %d/%s failed: %v", repo.ID, branch, err)
 				}
 			} else {
 				notification.NotifyDeleteRef(pusher, repo, "branch", opts.RefFullName)
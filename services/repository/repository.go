@@ -25,18 +25,51 @@ import (
 )
 
 // CreateRepository creates a repository for the user/organization.
+// The repository must satisfy the repository policy of an organization.
 func CreateRepository(doer, owner *user_model.User, opts repo_module.CreateRepoOptions) (*repo_model.Repository, error) {
+	if owner.IsOrganization() {
+		if err := organization.CheckRepoPolicy(db.DefaultContext, owner.ID, opts.Name, opts.Topics, opts.Properties); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Properties) > 0 {
+		if err := repo_model.CheckRepoProperties(db.DefaultContext, owner.ID, opts.Properties); err != nil {
+			return nil, err
+		}
+	}
+
 	repo, err := repo_module.CreateRepository(doer, owner, opts)
 	if err != nil {
 		// No need to rollback here we should do this in CreateRepository...
 		return nil, err
 	}
 
+	if err := saveTopicsAndProperties(repo, opts.Topics, opts.Properties); err != nil {
+		if errDelete := models.DeleteRepository(doer, repo.OwnerID, repo.ID); errDelete != nil {
+			log.Error("Rollback deleteRepository: %v", errDelete)
+		}
+		return nil, err
+	}
+
 	notification.NotifyCreateRepository(doer, owner, repo)
 
 	return repo, nil
 }
 
+func saveTopicsAndProperties(repo *repo_model.Repository, topics []string, properties map[string]string) error {
+	if len(topics) > 0 {
+		if err := repo_model.SaveTopics(repo.ID, topics...); err != nil {
+			return fmt.Errorf("SaveTopics: %w", err)
+		}
+	}
+	if len(properties) > 0 {
+		if err := repo_model.SetRepoProperties(db.DefaultContext, repo, properties); err != nil {
+			return fmt.Errorf("SetRepoProperties: %w", err)
+		}
+	}
+	return nil
+}
+
 // DeleteRepository deletes a repository for a user or organization.
 func DeleteRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, notify bool) error {
 	if err := pull_service.CloseRepoBranchesPulls(ctx, doer, repo); err != nil {
@@ -85,6 +118,12 @@ func Init() error {
 	repo_module.LoadRepoConfig()
 	admin_model.RemoveAllWithNotice(db.DefaultContext, "Clean up temporary repository uploads", setting.Repository.Upload.TempPath)
 	admin_model.RemoveAllWithNotice(db.DefaultContext, "Clean up temporary repositories", repo_module.LocalCopyPath())
+	if err := initCacheRefQueue(); err != nil {
+		return err
+	}
+	if err := initStatsRecalculationQueue(); err != nil {
+		return err
+	}
 	return initPushQueue()
 }
 
@@ -0,0 +1,245 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// statsRecalculationBatchSize is the number of repositories which are queued at once
+const statsRecalculationBatchSize = 50
+
+// StatsRecalculationRequest represents a request to recalculate the statistics of a repository
+type StatsRecalculationRequest struct {
+	RecalculationID int64
+	RepoID          int64
+}
+
+// statsRecalculationQueue represents a queue to recalculate the statistics of repositories
+var statsRecalculationQueue queue.UniqueQueue
+
+func handleStatsRecalculation(data ...queue.Data) []queue.Data {
+	for _, datum := range data {
+		req, ok := datum.(*StatsRecalculationRequest)
+		if !ok {
+			log.Error("Unable to process provided datum: %v - not possible to cast to StatsRecalculationRequest", datum)
+			continue
+		}
+		if err := processStatsRecalculationRequest(graceful.GetManager().HammerContext(), req); err != nil {
+			log.Error("Recalculating the statistics of repository %d failed: %v", req.RepoID, err)
+		}
+	}
+	return nil
+}
+
+func initStatsRecalculationQueue() error {
+	statsRecalculationQueue = queue.CreateUniqueQueue("repo_stats_recalculation", handleStatsRecalculation, new(StatsRecalculationRequest))
+	if statsRecalculationQueue == nil {
+		return errors.New("unable to create repo_stats_recalculation Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(statsRecalculationQueue.Run)
+
+	// resume queueing the repositories of a recalculation which was interrupted by a shutdown
+	r, err := repo_model.GetRunningStatsRecalculation(db.DefaultContext)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		log.Info("Resuming the recalculation of the repository statistics %d after repository %d", r.ID, r.LastRepoID)
+		go queueStatsRecalculation(r.ID)
+	}
+	return nil
+}
+
+// StartStatsRecalculation starts to recalculate the statistics of all repositories in the background.
+// An empty list of kinds recalculates all kinds of statistics.
+func StartStatsRecalculation(ctx context.Context, doerID int64, kinds []repo_model.StatsRecalculationKind) (*repo_model.StatsRecalculation, error) {
+	if len(kinds) == 0 {
+		kinds = repo_model.StatsRecalculationKinds
+	}
+
+	r, err := repo_model.CreateStatsRecalculation(ctx, doerID, kinds)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Started the recalculation of the repository statistics %d for %d repositories: %v", r.ID, r.Total, r.Kinds)
+
+	go queueStatsRecalculation(r.ID)
+	return r, nil
+}
+
+// queueStatsRecalculation pushes the remaining repositories of the recalculation to the queue,
+// the progress is recorded after each batch so that queueing can be resumed after a restart
+func queueStatsRecalculation(id int64) {
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().ShutdownContext(), fmt.Sprintf("RepoStatsRecalculation[%d]: queue repositories", id))
+	defer finished()
+
+	for {
+		r, err := repo_model.GetStatsRecalculationByID(ctx, id)
+		if err != nil {
+			log.Error("GetStatsRecalculationByID[%d]: %v", id, err)
+			return
+		}
+		if r == nil || !r.IsRunning() {
+			return
+		}
+		if r.IsQueued() {
+			break
+		}
+
+		ids := make([]int64, 0, statsRecalculationBatchSize)
+		if err := db.GetEngine(ctx).Table("repository").Cols("id").
+			Where("id > ? AND id <= ?", r.LastRepoID, r.MaxRepoID).
+			Asc("id").Limit(statsRecalculationBatchSize).Find(&ids); err != nil {
+			log.Error("RepoStatsRecalculation[%d]: unable to find repositories: %v", id, err)
+			return
+		}
+
+		lastRepoID := r.MaxRepoID
+		for i, repoID := range ids {
+			select {
+			case <-ctx.Done():
+				log.Info("RepoStatsRecalculation[%d]: queueing cancelled at repository %d", id, repoID)
+				if err := repo_model.UpdateStatsRecalculationCursor(db.DefaultContext, id, repoID-1, int64(i)); err != nil {
+					log.Error("RepoStatsRecalculation[%d]: unable to record the progress: %v", id, err)
+				}
+				return
+			default:
+			}
+			// a request which was already in the queue when queueing was interrupted is still counted once
+			if err := statsRecalculationQueue.Push(&StatsRecalculationRequest{RecalculationID: id, RepoID: repoID}); err != nil && err != queue.ErrAlreadyInQueue {
+				log.Error("RepoStatsRecalculation[%d]: unable to queue repository %d: %v", id, repoID, err)
+				if err := repo_model.UpdateStatsRecalculationCursor(ctx, id, repoID-1, int64(i)); err != nil {
+					log.Error("RepoStatsRecalculation[%d]: unable to record the progress: %v", id, err)
+				}
+				return
+			}
+		}
+		if len(ids) == statsRecalculationBatchSize {
+			lastRepoID = ids[len(ids)-1]
+		}
+		if err := repo_model.UpdateStatsRecalculationCursor(ctx, id, lastRepoID, int64(len(ids))); err != nil {
+			log.Error("RepoStatsRecalculation[%d]: unable to record the progress: %v", id, err)
+			return
+		}
+	}
+
+	// all repositories may already have been processed before queueing completed
+	finishStatsRecalculation(ctx, id)
+}
+
+func finishStatsRecalculation(ctx context.Context, id int64) {
+	done, err := repo_model.FinishStatsRecalculation(ctx, id)
+	if err != nil {
+		log.Error("FinishStatsRecalculation[%d]: %v", id, err)
+	} else if done {
+		log.Info("Finished the recalculation of the repository statistics %d", id)
+	}
+}
+
+func processStatsRecalculationRequest(ctx context.Context, req *StatsRecalculationRequest) error {
+	r, err := repo_model.GetStatsRecalculationByID(ctx, req.RecalculationID)
+	if err != nil {
+		return err
+	}
+	if r == nil || !r.IsRunning() {
+		return nil
+	}
+
+	recalcErr := RecalculateRepoStats(ctx, req.RepoID, r.Kinds)
+	if err := repo_model.IncreaseStatsRecalculationProgress(ctx, r.ID, recalcErr != nil); err != nil {
+		return err
+	}
+	finishStatsRecalculation(ctx, r.ID)
+	return recalcErr
+}
+
+// RecalculateRepoStats recalculates the given kinds of statistics of a repository.
+// Repositories which were deleted in the meantime are ignored.
+func RecalculateRepoStats(ctx context.Context, repoID int64, kinds []string) error {
+	repo, err := repo_model.GetRepositoryByIDCtx(ctx, repoID)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var gitRepo *git.Repository
+	defer func() {
+		if gitRepo != nil {
+			gitRepo.Close()
+		}
+	}()
+
+	for _, kind := range kinds {
+		switch repo_model.StatsRecalculationKind(kind) {
+		case repo_model.StatsRecalculationSize:
+			if err := repo_module.UpdateRepoSize(ctx, repo); err != nil {
+				return fmt.Errorf("UpdateRepoSize: %w", err)
+			}
+		case repo_model.StatsRecalculationCounters:
+			if err := models.UpdateRepoStats(ctx, repo.ID); err != nil {
+				return fmt.Errorf("UpdateRepoStats: %w", err)
+			}
+		case repo_model.StatsRecalculationLanguages, repo_model.StatsRecalculationCommits:
+			if repo.IsEmpty || repo.IsBeingCreated() {
+				continue
+			}
+			if gitRepo == nil {
+				if gitRepo, err = git.OpenRepository(ctx, repo.RepoPath()); err != nil {
+					return fmt.Errorf("OpenRepository: %w", err)
+				}
+			}
+			commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+			if err != nil {
+				if git.IsErrNotExist(err) || git.IsErrBranchNotExist(err) {
+					continue
+				}
+				return fmt.Errorf("GetBranchCommit: %w", err)
+			}
+			if err := recalculateRepoGitStats(repo, gitRepo, commit, repo_model.StatsRecalculationKind(kind)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func recalculateRepoGitStats(repo *repo_model.Repository, gitRepo *git.Repository, commit *git.Commit, kind repo_model.StatsRecalculationKind) error {
+	commitID := commit.ID.String()
+	if kind == repo_model.StatsRecalculationLanguages {
+		// unlike the stats indexer the statistics are calculated even if they are recorded for the commit
+		stats, err := gitRepo.GetLanguageStats(commitID)
+		if err != nil {
+			return fmt.Errorf("GetLanguageStats: %w", err)
+		}
+		if err := repo_model.UpdateLanguageStats(repo, commitID, stats); err != nil {
+			return fmt.Errorf("UpdateLanguageStats: %w", err)
+		}
+		return nil
+	}
+
+	key := repo.GetCommitsCountCacheKey(repo.DefaultBranch, true)
+	cache.Remove(key)
+	if _, err := cache.GetInt64(key, commit.CommitsCount); err != nil {
+		return fmt.Errorf("CommitsCount: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"code.gitea.io/gitea/models"
@@ -18,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/notification"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
 )
 
 // repoWorkingPool represents a working pool to order the parallel changes to the same repository
@@ -60,6 +62,36 @@ func TransferOwnership(doer, newOwner *user_model.User, repo *repo_model.Reposit
 	return nil
 }
 
+// TransferAllRepositories transfers all repositories owned by oldOwner to newOwner.
+func TransferAllRepositories(doer, oldOwner, newOwner *user_model.User) error {
+	if oldOwner.ID == newOwner.ID {
+		return fmt.Errorf("cannot transfer repositories of %s to itself", oldOwner.Name)
+	}
+
+	for {
+		repos, _, err := repo_model.GetUserRepositories(&repo_model.SearchRepoOptions{
+			ListOptions: db.ListOptions{
+				PageSize: repo_model.RepositoryListDefaultPageSize,
+				Page:     1,
+			},
+			Private: true,
+			OwnerID: oldOwner.ID,
+			Actor:   oldOwner,
+		})
+		if err != nil {
+			return fmt.Errorf("GetUserRepositories: %w", err)
+		}
+		if len(repos) == 0 {
+			return nil
+		}
+		for _, repo := range repos {
+			if err := TransferOwnership(doer, newOwner, repo, nil); err != nil {
+				return fmt.Errorf("TransferOwnership [%s]: %w", repo.FullName(), err)
+			}
+		}
+	}
+}
+
 // ChangeRepositoryName changes all corresponding setting from old repository name to new one.
 func ChangeRepositoryName(doer *user_model.User, repo *repo_model.Repository, newRepoName string) error {
 	log.Trace("ChangeRepositoryName: %s/%s -> %s", doer.Name, repo.Name, newRepoName)
@@ -86,44 +118,65 @@ func ChangeRepositoryName(doer *user_model.User, repo *repo_model.Repository, ne
 // StartRepositoryTransfer transfer a repo from one owner to a new one.
 // it make repository into pending transfer state, if doer can not create repo for new owner.
 func StartRepositoryTransfer(doer, newOwner *user_model.User, repo *repo_model.Repository, teams []*organization.Team) error {
+	return StartScheduledRepositoryTransfer(doer, newOwner, repo, teams, 0)
+}
+
+// StartScheduledRepositoryTransfer transfers a repo from one owner to a new one not before the scheduled time.
+// If scheduled is 0 it behaves like StartRepositoryTransfer. Otherwise the repository is always put into the
+// pending transfer state. The transfer is accepted directly if the doer is allowed to and gets executed
+// by a cron task once the scheduled time has been reached.
+func StartScheduledRepositoryTransfer(doer, newOwner *user_model.User, repo *repo_model.Repository, teams []*organization.Team, scheduled timeutil.TimeStamp) error {
 	if err := models.TestRepositoryReadyForTransfer(repo.Status); err != nil {
 		return err
 	}
 
-	// Admin is always allowed to transfer || user transfer repo back to his account
-	if doer.IsAdmin || doer.ID == newOwner.ID {
+	canTransfer, err := canTransferDirectly(doer, newOwner)
+	if err != nil {
+		return err
+	}
+	if canTransfer && scheduled == 0 {
 		return TransferOwnership(doer, newOwner, repo, teams)
 	}
 
-	// If new owner is an org and user can create repos he can transfer directly too
-	if newOwner.IsOrganization() {
-		allowed, err := organization.CanCreateOrgRepo(newOwner.ID, doer.ID)
+	if !canTransfer {
+		// In case the new owner would not have sufficient access to the repo, give access rights for read
+		hasAccess, err := access_model.HasAccess(db.DefaultContext, newOwner.ID, repo)
 		if err != nil {
 			return err
 		}
-		if allowed {
-			return TransferOwnership(doer, newOwner, repo, teams)
+		if !hasAccess {
+			if err := repo_module.AddCollaborator(repo, newOwner); err != nil {
+				return err
+			}
+			if err := repo_model.ChangeCollaborationAccessMode(repo, newOwner.ID, perm.AccessModeRead); err != nil {
+				return err
+			}
 		}
 	}
 
-	// In case the new owner would not have sufficient access to the repo, give access rights for read
-	hasAccess, err := access_model.HasAccess(db.DefaultContext, newOwner.ID, repo)
-	if err != nil {
+	// Make repo as pending for transfer
+	repo.Status = repo_model.RepositoryPendingTransfer
+	if err := models.CreatePendingRepositoryTransfer(doer, newOwner, repo.ID, teams); err != nil {
 		return err
 	}
-	if !hasAccess {
-		if err := repo_module.AddCollaborator(repo, newOwner); err != nil {
+
+	if scheduled != 0 {
+		transfer, err := models.GetPendingRepositoryTransfer(repo)
+		if err != nil {
 			return err
 		}
-		if err := repo_model.ChangeCollaborationAccessMode(repo, newOwner.ID, perm.AccessModeRead); err != nil {
+		if err := models.SetRepositoryTransferSchedule(db.DefaultContext, transfer, scheduled); err != nil {
 			return err
 		}
-	}
 
-	// Make repo as pending for transfer
-	repo.Status = repo_model.RepositoryPendingTransfer
-	if err := models.CreatePendingRepositoryTransfer(doer, newOwner, repo.ID, teams); err != nil {
-		return err
+		if canTransfer {
+			if err := models.AcceptRepositoryTransfer(db.DefaultContext, transfer, doer); err != nil {
+				return err
+			}
+
+			notification.NotifyRepoTransferAccepted(doer, repo)
+			return nil
+		}
 	}
 
 	// notify users who are able to accept / reject transfer
@@ -131,3 +184,115 @@ func StartRepositoryTransfer(doer, newOwner *user_model.User, repo *repo_model.R
 
 	return nil
 }
+
+// canTransferDirectly checks if the doer can transfer the repository without the acceptance of the new owner
+func canTransferDirectly(doer, newOwner *user_model.User) (bool, error) {
+	// Admin is always allowed to transfer || user transfer repo back to his account
+	if doer.IsAdmin || doer.ID == newOwner.ID {
+		return true, nil
+	}
+
+	// If new owner is an org and user can create repos he can transfer directly too
+	if newOwner.IsOrganization() {
+		return organization.CanCreateOrgRepo(newOwner.ID, doer.ID)
+	}
+
+	return false, nil
+}
+
+// AcceptTransferOwnership accepts the pending transfer of the repository.
+// The transfer is executed directly if its scheduled time has been reached,
+// otherwise it gets executed by a cron task later.
+// The caller must check if the doer is allowed to accept the transfer.
+func AcceptTransferOwnership(doer *user_model.User, repo *repo_model.Repository, transfer *models.RepoTransfer) error {
+	if err := transfer.LoadAttributes(); err != nil {
+		return err
+	}
+
+	if transfer.IsDue() {
+		return TransferOwnership(transfer.Doer, transfer.Recipient, repo, transfer.Teams)
+	}
+
+	if err := models.AcceptRepositoryTransfer(db.DefaultContext, transfer, doer); err != nil {
+		return err
+	}
+
+	notification.NotifyRepoTransferAccepted(doer, repo)
+
+	return nil
+}
+
+// RejectTransferOwnership rejects the pending transfer of the repository.
+// The caller must check if the doer is allowed to reject the transfer.
+func RejectTransferOwnership(doer *user_model.User, repo *repo_model.Repository) error {
+	if err := models.CancelRepositoryTransfer(repo); err != nil {
+		return err
+	}
+
+	notification.NotifyRepoTransferRejected(doer, repo)
+
+	return nil
+}
+
+// CancelTransferOwnership cancels the pending transfer of the repository on behalf of the current owner
+func CancelTransferOwnership(doer *user_model.User, repo *repo_model.Repository) error {
+	if err := models.CancelRepositoryTransfer(repo); err != nil {
+		return err
+	}
+
+	notification.NotifyRepoTransferCanceled(doer, repo)
+
+	return nil
+}
+
+// ScheduleTransferOwnership changes the earliest time the pending transfer gets executed.
+// An accepted transfer is executed directly if the new scheduled time has already been reached.
+func ScheduleTransferOwnership(doer *user_model.User, repo *repo_model.Repository, transfer *models.RepoTransfer, scheduled timeutil.TimeStamp) error {
+	if err := models.SetRepositoryTransferSchedule(db.DefaultContext, transfer, scheduled); err != nil {
+		return err
+	}
+
+	if transfer.IsAccepted() && transfer.IsDue() {
+		if err := transfer.LoadAttributes(); err != nil {
+			return err
+		}
+		return TransferOwnership(transfer.Doer, transfer.Recipient, repo, transfer.Teams)
+	}
+
+	notification.NotifyRepoTransferScheduled(doer, repo)
+
+	return nil
+}
+
+// TransferScheduledRepositories executes the accepted repository transfers whose scheduled time has been reached
+func TransferScheduledRepositories(ctx context.Context) error {
+	transfers, err := models.FindDueRepositoryTransfers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before executing repository transfer %d", transfer.ID)
+		default:
+		}
+
+		if err := transfer.LoadAttributes(); err != nil {
+			log.Error("Unable to load attributes of repository transfer %d: %v", transfer.ID, err)
+			continue
+		}
+
+		repo, err := repo_model.GetRepositoryByID(transfer.RepoID)
+		if err != nil {
+			log.Error("Unable to get repository %d of transfer %d: %v", transfer.RepoID, transfer.ID, err)
+			continue
+		}
+
+		if err := TransferOwnership(transfer.Doer, transfer.Recipient, repo, transfer.Teams); err != nil {
+			log.Error("Unable to transfer repository %s to %s: %v", repo.FullName(), transfer.Recipient.Name, err)
+		}
+	}
+
+	return nil
+}
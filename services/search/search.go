@@ -0,0 +1,310 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// Type is a kind of search result
+type Type string
+
+// List of search result types
+const (
+	TypeRepository Type = "repo"
+	TypeCode       Type = "code"
+	TypeIssue      Type = "issue"
+	TypeUser       Type = "user"
+	TypePackage    Type = "package"
+)
+
+// Types are all search result types in the order they are searched
+var Types = []Type{TypeRepository, TypeCode, TypeIssue, TypeUser, TypePackage}
+
+// IsValid checks if the search result type is known
+func (t Type) IsValid() bool {
+	for _, typ := range Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnabled checks if the search of the type is enabled on this instance
+func (t Type) IsEnabled() bool {
+	switch t {
+	case TypeCode:
+		return setting.Indexer.RepoIndexerEnabled
+	case TypePackage:
+		return setting.Packages.Enabled
+	}
+	return true
+}
+
+// ErrInvalidType represents an unknown search result type
+type ErrInvalidType struct {
+	Type string
+}
+
+// IsErrInvalidType checks if an error is a ErrInvalidType.
+func IsErrInvalidType(err error) bool {
+	_, ok := err.(ErrInvalidType)
+	return ok
+}
+
+func (err ErrInvalidType) Error() string {
+	return fmt.Sprintf("invalid search type [type: %s]", err.Type)
+}
+
+// ParseTypes parses the requested search result types, no types select all enabled types
+func ParseTypes(names []string) ([]Type, error) {
+	types := make([]Type, 0, len(Types))
+	for _, name := range names {
+		t := Type(strings.ToLower(strings.TrimSpace(name)))
+		if !t.IsValid() {
+			return nil, ErrInvalidType{name}
+		}
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		types = append(types, Types...)
+	}
+
+	enabled := make([]Type, 0, len(types))
+	for _, t := range Types {
+		if !t.IsEnabled() {
+			continue
+		}
+		for _, typ := range types {
+			if t == typ {
+				enabled = append(enabled, t)
+				break
+			}
+		}
+	}
+	return enabled, nil
+}
+
+// Options are the options of a global search.
+// The pagination applies to every searched type.
+type Options struct {
+	db.ListOptions
+	Doer    *user_model.User
+	Keyword string
+	Types   []Type
+}
+
+// Results holds the results of a global search
+type Results struct {
+	// Counts holds the total number of results of every searched type
+	Counts       map[Type]int64
+	Repositories repo_model.RepositoryList
+	Code         []*code_indexer.Result
+	CodeRepos    map[int64]*repo_model.Repository
+	Issues       issues_model.IssueList
+	Users        []*user_model.User
+	Packages     []*packages_model.PackageDescriptor
+}
+
+// Search searches the repositories, code, issues, users and packages visible to the doer
+func Search(ctx context.Context, opts *Options) (*Results, error) {
+	opts.Keyword = strings.TrimSpace(opts.Keyword)
+	if strings.IndexByte(opts.Keyword, 0) >= 0 {
+		opts.Keyword = ""
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+
+	results := &Results{
+		Counts: make(map[Type]int64, len(opts.Types)),
+	}
+	for _, t := range opts.Types {
+		var err error
+		switch t {
+		case TypeRepository:
+			err = searchRepositories(opts, results)
+		case TypeCode:
+			err = searchCode(ctx, opts, results)
+		case TypeIssue:
+			err = searchIssues(ctx, opts, results)
+		case TypeUser:
+			err = searchUsers(opts, results)
+		case TypePackage:
+			err = searchPackages(ctx, opts, results)
+		default:
+			err = ErrInvalidType{string(t)}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("search %s: %w", t, err)
+		}
+	}
+	return results, nil
+}
+
+func visibleRepositoriesOptions(opts *Options) *repo_model.SearchRepoOptions {
+	return &repo_model.SearchRepoOptions{
+		Actor:       opts.Doer,
+		Private:     opts.Doer != nil,
+		AllPublic:   true,
+		AllLimited:  opts.Doer != nil,
+		Collaborate: util.OptionalBoolNone,
+		Template:    util.OptionalBoolNone,
+		// This needs to be a column that is not nil in fixtures or
+		// MySQL will return different results when sorting by null in some cases
+		OrderBy: db.SearchOrderByAlphabetically,
+	}
+}
+
+func searchRepositories(opts *Options, results *Results) error {
+	repoOpts := visibleRepositoriesOptions(opts)
+	repoOpts.ListOptions = opts.ListOptions
+	repoOpts.Keyword = opts.Keyword
+	repoOpts.IncludeDescription = true
+	repoOpts.OrderBy = db.SearchOrderByRecentUpdated
+
+	repos, count, err := repo_model.SearchRepository(repoOpts)
+	if err != nil {
+		return err
+	}
+	results.Repositories = repos
+	results.Counts[TypeRepository] = count
+	return nil
+}
+
+func searchCode(ctx context.Context, opts *Options, results *Results) error {
+	results.Counts[TypeCode] = 0
+	if opts.Keyword == "" {
+		return nil
+	}
+
+	var repoIDs []int64
+	if opts.Doer == nil || !opts.Doer.IsAdmin {
+		var err error
+		repoIDs, err = repo_model.FindUserCodeAccessibleRepoIDs(opts.Doer)
+		if err != nil {
+			return err
+		}
+		if len(repoIDs) == 0 {
+			return nil
+		}
+	}
+
+	total, codeResults, _, err := code_indexer.PerformSearch(ctx, repoIDs, "", opts.Keyword, opts.Page, opts.PageSize, false)
+	if err != nil {
+		return err
+	}
+
+	loadRepoIDs := make([]int64, 0, len(codeResults))
+	for _, result := range codeResults {
+		loadRepoIDs = append(loadRepoIDs, result.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(loadRepoIDs)
+	if err != nil {
+		return err
+	}
+
+	results.Code = codeResults
+	results.CodeRepos = repos
+	results.Counts[TypeCode] = int64(total)
+	return nil
+}
+
+func searchIssues(ctx context.Context, opts *Options, results *Results) error {
+	results.Counts[TypeIssue] = 0
+	if opts.Keyword == "" {
+		return nil
+	}
+
+	repoOpts := visibleRepositoriesOptions(opts)
+	repoIDs, _, err := repo_model.SearchRepositoryIDs(repoOpts)
+	if err != nil {
+		return err
+	}
+	if len(repoIDs) == 0 {
+		return nil
+	}
+
+	issueIDs, err := issue_indexer.SearchIssuesByKeyword(ctx, repoIDs, opts.Keyword)
+	if err != nil {
+		return err
+	}
+	// Searching without matching issue ids would return all issues
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	issuesOpts := &issues_model.IssuesOptions{
+		ListOptions: opts.ListOptions,
+		RepoCond:    repo_model.SearchRepositoryCondition(repoOpts),
+		IssueIDs:    issueIDs,
+		SortType:    "recentupdate",
+	}
+	issues, err := issues_model.Issues(issuesOpts)
+	if err != nil {
+		return err
+	}
+	issuesOpts.ListOptions = db.ListOptions{
+		Page: -1,
+	}
+	count, err := issues_model.CountIssues(issuesOpts)
+	if err != nil {
+		return err
+	}
+
+	results.Issues = issues
+	results.Counts[TypeIssue] = count
+	return nil
+}
+
+func searchUsers(opts *Options, results *Results) error {
+	users, count, err := user_model.SearchUsers(&user_model.SearchUserOptions{
+		Actor:       opts.Doer,
+		Keyword:     opts.Keyword,
+		Type:        user_model.UserTypeIndividual,
+		ListOptions: opts.ListOptions,
+	})
+	if err != nil {
+		return err
+	}
+	results.Users = users
+	results.Counts[TypeUser] = count
+	return nil
+}
+
+func searchPackages(ctx context.Context, opts *Options, results *Results) error {
+	listOptions := opts.ListOptions
+	pvs, count, err := packages_model.SearchLatestVersions(ctx, &packages_model.PackageSearchOptions{
+		Name:       packages_model.SearchValue{Value: opts.Keyword},
+		IsInternal: util.OptionalBoolFalse,
+		AllOwners:  true,
+		Actor:      opts.Doer,
+		Paginator:  &listOptions,
+	})
+	if err != nil {
+		return err
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		return err
+	}
+	results.Packages = pds
+	results.Counts[TypePackage] = count
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTypes(t *testing.T) {
+	defer func(repoIndexer, packages bool) {
+		setting.Indexer.RepoIndexerEnabled = repoIndexer
+		setting.Packages.Enabled = packages
+	}(setting.Indexer.RepoIndexerEnabled, setting.Packages.Enabled)
+
+	setting.Indexer.RepoIndexerEnabled = true
+	setting.Packages.Enabled = true
+
+	types, err := ParseTypes(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Types, types)
+
+	types, err = ParseTypes([]string{"user", " Repo "})
+	assert.NoError(t, err)
+	assert.Equal(t, []Type{TypeRepository, TypeUser}, types)
+
+	_, err = ParseTypes([]string{"wiki"})
+	assert.True(t, IsErrInvalidType(err))
+
+	setting.Indexer.RepoIndexerEnabled = false
+	types, err = ParseTypes([]string{"code", "issue"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Type{TypeIssue}, types)
+}
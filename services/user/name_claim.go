@@ -0,0 +1,128 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// ErrUserNotDormant represents a "UserNotDormant" kind of error.
+type ErrUserNotDormant struct {
+	Name string
+}
+
+// IsErrUserNotDormant checks if an error is a ErrUserNotDormant.
+func IsErrUserNotDormant(err error) bool {
+	_, ok := err.(ErrUserNotDormant)
+	return ok
+}
+
+func (err ErrUserNotDormant) Error() string {
+	return fmt.Sprintf("user is not dormant [name: %s]", err.Name)
+}
+
+// IsDormant checks if the user has not signed in during the dormant period
+func IsDormant(u *user_model.User) bool {
+	lastActivity := u.LastLoginUnix
+	if lastActivity == 0 {
+		lastActivity = u.CreatedUnix
+	}
+	return lastActivity.AddDuration(setting.Admin.NameClaimDormantPeriod) < timeutil.TimeStampNow()
+}
+
+// CreateNameClaim starts the reclaim of the name of a dormant user. The user is notified and the name
+// is released after the grace period unless the user signs in. If claimant is not nil, the claimant
+// is renamed to the released name.
+func CreateNameClaim(ctx context.Context, doer, u, claimant *user_model.User) (*user_model.NameClaim, error) {
+	// service accounts never sign in, they are managed by their organization
+	if u.IsOrganization() || u.IsBot() {
+		return nil, user_model.ErrUserNotExist{UID: u.ID, Name: u.Name}
+	}
+	if !IsDormant(u) {
+		return nil, ErrUserNotDormant{u.Name}
+	}
+
+	claim := &user_model.NameClaim{
+		UserID:       u.ID,
+		Name:         u.LowerName,
+		DoerID:       doer.ID,
+		DeadlineUnix: timeutil.TimeStampNow().AddDuration(setting.Admin.NameClaimGracePeriod),
+	}
+	if claimant != nil {
+		claim.ClaimantID = claimant.ID
+	}
+	if err := user_model.CreateNameClaim(ctx, claim); err != nil {
+		return nil, err
+	}
+
+	if err := mailer.SendNameClaimMail(u, claim); err != nil {
+		log.Error("SendNameClaimMail: %v", err)
+	}
+	return claim, nil
+}
+
+// ProcessNameClaims releases the names of the pending name claims whose grace period is over.
+// Claims of users who signed in or changed their name in the meantime are cancelled.
+func ProcessNameClaims(ctx context.Context) error {
+	claims, err := user_model.GetDueNameClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claims {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("Before processing name claim of %s", claim.Name)
+		default:
+		}
+
+		status, err := processNameClaim(ctx, claim)
+		if err != nil {
+			log.Error("Unable to process name claim %d of %s: %v", claim.ID, claim.Name, err)
+			continue
+		}
+		if err := user_model.UpdateNameClaimStatus(ctx, claim, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func processNameClaim(ctx context.Context, claim *user_model.NameClaim) (user_model.NameClaimStatus, error) {
+	u, err := user_model.GetUserByIDCtx(ctx, claim.UserID)
+	if err != nil && !user_model.IsErrUserNotExist(err) {
+		return "", err
+	}
+	if u != nil {
+		if u.LowerName != claim.Name || u.LastLoginUnix > claim.CreatedUnix {
+			return user_model.NameClaimStatusCancelled, nil
+		}
+		if err := RenameUser(ctx, u, fmt.Sprintf("dormant-%d", u.ID)); err != nil {
+			return "", err
+		}
+	}
+
+	if claim.ClaimantID > 0 {
+		claimant, err := user_model.GetUserByIDCtx(ctx, claim.ClaimantID)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				return user_model.NameClaimStatusCompleted, nil
+			}
+			return "", err
+		}
+		if err := RenameUser(ctx, claimant, claim.Name); err != nil {
+			return "", err
+		}
+	}
+	return user_model.NameClaimStatusCompleted, nil
+}
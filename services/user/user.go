@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"image/png"
 	"io"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models"
@@ -26,9 +27,30 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/agit"
 	"code.gitea.io/gitea/services/packages"
+	container_service "code.gitea.io/gitea/services/packages/container"
 )
 
+// RenameUser changes the name of a user and updates all places the name is referenced
+func RenameUser(ctx context.Context, u *user_model.User, newName string) error {
+	if err := user_model.ChangeUserName(u, newName); err != nil {
+		return err
+	}
+	if err := agit.UserNameChanged(u, newName); err != nil {
+		return err
+	}
+	if err := container_service.UpdateRepositoryNames(ctx, u, newName); err != nil {
+		return err
+	}
+
+	log.Trace("User name changed: %s -> %s", u.Name, newName)
+
+	u.Name = newName
+	u.LowerName = strings.ToLower(newName)
+	return user_model.UpdateUserCols(ctx, u, "name", "lower_name")
+}
+
 // DeleteUser completely and permanently deletes everything of a user,
 // but issues/comments/pulls will be kept and shown as someone has been deleted,
 // unless the user is younger than USER_DELETE_WITH_COMMENTS_MAX_DAYS.
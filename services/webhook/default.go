@@ -0,0 +1,78 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// ApplyDefaultWebhook copies a default webhook onto the existing repositories which don't have a copy of it yet.
+// Default webhooks of an organization are applied to the repositories of the organization, the pattern is matched
+// against the repository name. Admin-default webhooks are applied to all repositories, the pattern is matched against
+// "owner/name". An empty pattern matches all repositories. It returns the number of repositories the webhook was copied to.
+func ApplyDefaultWebhook(ctx context.Context, w *webhook_model.Webhook, pattern string) (int, error) {
+	if w.RepoID != 0 || w.IsSystemWebhook || (w.OrgID != 0 && !w.IsDefaultWebhook) {
+		return 0, fmt.Errorf("webhook %d is not a default webhook", w.ID)
+	}
+
+	var g glob.Glob
+	if pattern != "" {
+		var err error
+		if g, err = glob.Compile(pattern, '/'); err != nil {
+			return 0, err
+		}
+	}
+
+	cond := builder.NewCond()
+	if w.OrgID != 0 {
+		cond = builder.Eq{"owner_id": w.OrgID}
+	}
+
+	// collect the repositories first to not write to the table while iterating
+	repoIDs := make([]int64, 0, 50)
+	if err := db.Iterate(ctx, new(repo_model.Repository), cond, func(idx int, bean interface{}) error {
+		repo := bean.(*repo_model.Repository)
+		if g != nil {
+			name := repo.Name
+			if w.OrgID == 0 {
+				name = repo.OwnerName + "/" + repo.Name
+			}
+			if !g.Match(name) {
+				return nil
+			}
+		}
+		repoIDs = append(repoIDs, repo.ID)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, repoID := range repoIDs {
+		has, err := webhook_model.HasWebhookCopy(ctx, w.ID, repoID)
+		if err != nil {
+			return applied, err
+		}
+		if has {
+			continue
+		}
+		if err := webhook_model.CopyDefaultWebhookToRepo(ctx, w, repoID); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+
+	log.Trace("Default webhook %d copied to %d repositories", w.ID, applied)
+	return applied, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDefaultWebhook(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	w := &webhook_model.Webhook{
+		OrgID:            3,
+		URL:              "www.example.com/default",
+		ContentType:      webhook_model.ContentTypeJSON,
+		Events:           `{"push_only":true}`,
+		IsActive:         true,
+		Type:             webhook_model.GITEA,
+		IsDefaultWebhook: true,
+	}
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, w))
+
+	// repo3 and repo5 match, repo21 doesn't
+	applied, err := ApplyDefaultWebhook(db.DefaultContext, w, "repo[0-9]")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{RepoID: 3, DefaultWebhookID: w.ID, URL: w.URL})
+	unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{RepoID: 5, DefaultWebhookID: w.ID, URL: w.URL})
+	unittest.AssertNotExistsBean(t, &webhook_model.Webhook{RepoID: 32, DefaultWebhookID: w.ID})
+
+	// repositories which already have a copy are skipped
+	applied, err = ApplyDefaultWebhook(db.DefaultContext, w, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{RepoID: 32, DefaultWebhookID: w.ID})
+
+	// the default webhook itself is not delivered for the repositories of the organization
+	hooks, err := webhook_model.ListWebhooksByOpts(db.DefaultContext, &webhook_model.ListWebhookOptions{OrgID: 3, IsDefault: util.OptionalBoolFalse})
+	assert.NoError(t, err)
+	for _, hook := range hooks {
+		assert.NotEqual(t, w.ID, hook.ID)
+	}
+
+	_, err = ApplyDefaultWebhook(db.DefaultContext, &webhook_model.Webhook{ID: 1, RepoID: 1}, "")
+	assert.Error(t, err)
+}
@@ -67,12 +67,12 @@ func (d *DingtalkPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	)
 
 	var titleLink, linkText string
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 new commit"
 		titleLink = p.Commits[0].URL
 		linkText = fmt.Sprintf("view commit %s", p.Commits[0].ID[:7])
 	} else {
-		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
 		titleLink = p.CompareURL
 		linkText = fmt.Sprintf("view commit %s...%s", p.Commits[0].ID[:7], p.Commits[len(p.Commits)-1].ID[:7])
 	}
@@ -154,9 +154,10 @@ func (d *DingtalkPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 				Content: title,
 			},
 		}, nil
+	default:
+		title := fmt.Sprintf("[%s] %s", p.Repository.FullName, getRepositoryTransferText(p))
+		return createDingtalkPayload(title, title, "view repository", p.Repository.HTMLURL), nil
 	}
-
-	return nil, nil
 }
 
 // Release implements PayloadConvertor Release method
@@ -166,6 +167,20 @@ func (d *DingtalkPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createDingtalkPayload(text, text, "view release", p.Release.URL), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (d *DingtalkPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, issueTitle, link, _ := getReactionPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(issueTitle, text, "view reaction", link), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (d *DingtalkPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, link, text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return createDingtalkPayload(title, title+"\r\n\r\n"+text, "view digest", link), nil
+}
+
 func createDingtalkPayload(title, text, singleTitle, singleURL string) *DingtalkPayload {
 	return &DingtalkPayload{
 		MsgType: "actionCard",
@@ -141,11 +141,11 @@ func (d *DiscordPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	)
 
 	var titleLink string
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 new commit"
 		titleLink = p.Commits[0].URL
 	} else {
-		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
 		titleLink = p.CompareURL
 	}
 	if titleLink == "" {
@@ -230,6 +230,10 @@ func (d *DiscordPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		color = redColor
+	default:
+		title = fmt.Sprintf("[%s] %s", p.Repository.FullName, getRepositoryTransferText(p))
+		url = p.Repository.HTMLURL
+		color = purpleColor
 	}
 
 	return d.createPayload(p.Sender, title, "", url, color), nil
@@ -242,6 +246,35 @@ func (d *DiscordPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return d.createPayload(p.Sender, text, p.Release.Note, p.Release.URL, color), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (d *DiscordPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	title, _, link, color := getReactionPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, title, "", link, color), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (d *DiscordPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, name, link, text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return &DiscordPayload{
+		Username:  d.Username,
+		AvatarURL: d.AvatarURL,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         link,
+				Color:       purpleColor,
+				Author: DiscordEmbedAuthor{
+					Name: name,
+					URL:  link,
+				},
+			},
+		},
+	}, nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	s := new(DiscordPayload)
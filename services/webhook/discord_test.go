@@ -229,6 +229,37 @@ func TestDiscordPayload(t *testing.T) {
 		assert.Equal(t, setting.AppURL+p.Sender.UserName, pl.(*DiscordPayload).Embeds[0].Author.URL)
 		assert.Equal(t, p.Sender.AvatarURL, pl.(*DiscordPayload).Embeds[0].Author.IconURL)
 	})
+
+	t.Run("Reaction", func(t *testing.T) {
+		p := reactionTestPayload()
+
+		d := new(DiscordPayload)
+		pl, err := d.Reaction(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &DiscordPayload{}, pl)
+
+		assert.Len(t, pl.(*DiscordPayload).Embeds, 1)
+		assert.Equal(t, "[test/repo] Reaction heart added to comment on issue #2 crash", pl.(*DiscordPayload).Embeds[0].Title)
+		assert.Equal(t, "http://localhost:3000/test/repo/issues/2#issuecomment-4", pl.(*DiscordPayload).Embeds[0].URL)
+		assert.Equal(t, p.Sender.UserName, pl.(*DiscordPayload).Embeds[0].Author.Name)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(DiscordPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &DiscordPayload{}, pl)
+
+		assert.Len(t, pl.(*DiscordPayload).Embeds, 1)
+		assert.Equal(t, "[test/repo] Weekly digest 2022-10-09 - 2022-10-16", pl.(*DiscordPayload).Embeds[0].Title)
+		assert.Equal(t, "test/repo: 12 commits by 1 author, 1 merged pull request, 0 new issues, 1 release\n- Merged #12 Fix bug\n- Released v1.0", pl.(*DiscordPayload).Embeds[0].Description)
+		assert.Equal(t, "http://localhost:3000/test/repo", pl.(*DiscordPayload).Embeds[0].URL)
+		assert.Equal(t, "test/repo", pl.(*DiscordPayload).Embeds[0].Author.Name)
+	})
 }
 
 func TestDiscordJSONPayload(t *testing.T) {
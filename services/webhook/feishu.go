@@ -78,6 +78,12 @@ func (f *FeishuPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 		commitDesc string
 	)
 
+	if p.CommitCount() == 1 {
+		commitDesc = "1 new commit"
+	} else {
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
+	}
+
 	text := fmt.Sprintf("[%s:%s] %s\r\n", p.Repo.FullName, branchName, commitDesc)
 	// for each commit, generate attachment text
 	for i, commit := range p.Commits {
@@ -140,9 +146,10 @@ func (f *FeishuPayload) Repository(p *api.RepositoryPayload) (api.Payloader, err
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return newFeishuTextPayload(text), nil
+	default:
+		text = fmt.Sprintf("[%s] %s", p.Repository.FullName, getRepositoryTransferText(p))
+		return newFeishuTextPayload(text), nil
 	}
-
-	return nil, nil
 }
 
 // Release implements PayloadConvertor Release method
@@ -152,6 +159,20 @@ func (f *FeishuPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return newFeishuTextPayload(text), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (f *FeishuPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, issueTitle, _, _ := getReactionPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(issueTitle + "\r\n" + text), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (f *FeishuPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, _, text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return newFeishuTextPayload(title + "\r\n" + text), nil
+}
+
 // GetFeishuPayload converts a ding talk webhook into a FeishuPayload
 func GetFeishuPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(FeishuPayload), p, event)
@@ -161,6 +161,33 @@ func getReleasePayloadInfo(p *api.ReleasePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+// getRepositoryTransferText returns the description of a repository transfer action
+func getRepositoryTransferText(p *api.RepositoryPayload) string {
+	var recipient string
+	if p.Repository.RepoTransfer != nil && p.Repository.RepoTransfer.Recipient != nil {
+		recipient = " to " + p.Repository.RepoTransfer.Recipient.UserName
+	}
+
+	switch p.Action {
+	case api.HookRepoTransferRequested:
+		return "Repository transfer" + recipient + " requested"
+	case api.HookRepoTransferAccepted:
+		return "Repository transfer" + recipient + " accepted"
+	case api.HookRepoTransferScheduled:
+		if p.Repository.RepoTransfer != nil && p.Repository.RepoTransfer.ScheduledAt != nil {
+			return "Repository transfer" + recipient + " scheduled for " + p.Repository.RepoTransfer.ScheduledAt.UTC().Format("2006-01-02 15:04 MST")
+		}
+		return "Repository transfer" + recipient + " schedule removed"
+	case api.HookRepoTransferRejected:
+		return "Repository transfer rejected"
+	case api.HookRepoTransferCanceled:
+		return "Repository transfer canceled"
+	case api.HookRepoTransferred:
+		return "Repository transferred"
+	}
+	return ""
+}
+
 func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFormatter, withSender bool) (string, string, int) {
 	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
 	issueTitle := fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
@@ -196,3 +223,76 @@ func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFo
 
 	return text, issueTitle, color
 }
+
+func getReactionPayloadInfo(p *api.ReactionPayload, linkFormatter linkFormatter, withSender bool) (text, issueTitle, link string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	issueTitle = fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
+	titleLink := linkFormatter(p.Issue.HTMLURL, issueTitle)
+
+	target := "issue"
+	if p.IsPull {
+		target = "pull request"
+	}
+	link = p.Issue.HTMLURL
+	if p.Comment != nil {
+		target = "comment on " + target
+		link = p.Comment.HTMLURL
+	}
+
+	switch p.Action {
+	case api.HookReactionCreated:
+		text = fmt.Sprintf("[%s] Reaction %s added to %s %s", repoLink, p.Reaction.Reaction, target, titleLink)
+		color = greenColorLight
+	case api.HookReactionDeleted:
+		text = fmt.Sprintf("[%s] Reaction %s removed from %s %s", repoLink, p.Reaction.Reaction, target, titleLink)
+		color = greyColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, issueTitle, link, color
+}
+
+func pluralize(n int64, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// getDigestPayloadInfo returns the title, the name of the organization or repository, its link and the summary of a digest.
+// The summary has one line per repository followed by the merged pull requests, the new issues and the releases.
+func getDigestPayloadInfo(p *api.DigestPayload, linkFormatter linkFormatter) (title, name, link, text string) {
+	if p.Organization != nil {
+		name = p.Organization.UserName
+		link = setting.AppURL + url.PathEscape(p.Organization.UserName)
+	} else if len(p.Repositories) > 0 {
+		name = p.Repositories[0].Repository.FullName
+		link = p.Repositories[0].Repository.HTMLURL
+	}
+	title = fmt.Sprintf("[%s] Weekly digest %s - %s", linkFormatter(link, name), p.Since.Format("2006-01-02"), p.Until.Format("2006-01-02"))
+
+	lines := make([]string, 0, len(p.Repositories)*4)
+	for _, r := range p.Repositories {
+		lines = append(lines, fmt.Sprintf("%s: %s by %s, %s, %s, %s",
+			linkFormatter(r.Repository.HTMLURL, r.Repository.FullName),
+			pluralize(r.Commits, "commit", "commits"),
+			pluralize(r.CommitAuthors, "author", "authors"),
+			pluralize(int64(len(r.MergedPullRequests)), "merged pull request", "merged pull requests"),
+			pluralize(int64(len(r.NewIssues)), "new issue", "new issues"),
+			pluralize(int64(len(r.Releases)), "release", "releases"),
+		))
+		for _, e := range r.MergedPullRequests {
+			lines = append(lines, "- Merged "+linkFormatter(e.HTMLURL, fmt.Sprintf("#%d %s", e.Number, e.Title)))
+		}
+		for _, e := range r.NewIssues {
+			lines = append(lines, "- Opened "+linkFormatter(e.HTMLURL, fmt.Sprintf("#%d %s", e.Number, e.Title)))
+		}
+		for _, e := range r.Releases {
+			lines = append(lines, "- Released "+linkFormatter(e.HTMLURL, e.Title))
+		}
+	}
+
+	return title, name, link, strings.Join(lines, "\n")
+}
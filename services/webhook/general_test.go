@@ -6,6 +6,7 @@ package webhook
 
 import (
 	"testing"
+	"time"
 
 	api "code.gitea.io/gitea/modules/structs"
 
@@ -195,6 +196,42 @@ func pullRequestCommentTestPayload() *api.IssueCommentPayload {
 	}
 }
 
+func reactionTestPayload() *api.ReactionPayload {
+	comment := issueCommentTestPayload()
+	return &api.ReactionPayload{
+		Action: api.HookReactionCreated,
+		Reaction: &api.Reaction{
+			User:     comment.Sender,
+			Reaction: "heart",
+		},
+		Issue:      comment.Issue,
+		Comment:    comment.Comment,
+		Repository: comment.Repository,
+		Sender:     comment.Sender,
+	}
+}
+
+func digestTestPayload() *api.DigestPayload {
+	return &api.DigestPayload{
+		Since: time.Date(2022, 10, 9, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2022, 10, 16, 0, 0, 0, 0, time.UTC),
+		Repositories: []*api.DigestRepository{
+			{
+				Repository:    issueCommentTestPayload().Repository,
+				Commits:       12,
+				CommitAuthors: 1,
+				MergedPullRequests: []*api.DigestEntry{
+					{Number: 12, Title: "Fix bug", HTMLURL: "http://localhost:3000/test/repo/pulls/12"},
+				},
+				NewIssues: []*api.DigestEntry{},
+				Releases: []*api.DigestEntry{
+					{Title: "v1.0", HTMLURL: "http://localhost:3000/test/repo/releases/tag/v1.0"},
+				},
+			},
+		},
+	}
+}
+
 func pullReleaseTestPayload() *api.ReleasePayload {
 	return &api.ReleasePayload{
 		Action: api.HookReleasePublished,
@@ -502,6 +539,33 @@ func TestGetReleasePayloadInfo(t *testing.T) {
 	}
 }
 
+func TestGetRepositoryTransferText(t *testing.T) {
+	p := repositoryTestPayload()
+	scheduled := time.Date(2022, 10, 1, 22, 0, 0, 0, time.UTC)
+	p.Repository.RepoTransfer = &api.RepoTransfer{
+		Recipient:   &api.User{UserName: "user2"},
+		ScheduledAt: &scheduled,
+	}
+
+	cases := []struct {
+		action api.HookRepoAction
+		text   string
+	}{
+		{api.HookRepoTransferRequested, "Repository transfer to user2 requested"},
+		{api.HookRepoTransferAccepted, "Repository transfer to user2 accepted"},
+		{api.HookRepoTransferScheduled, "Repository transfer to user2 scheduled for 2022-10-01 22:00 UTC"},
+		{api.HookRepoTransferRejected, "Repository transfer rejected"},
+		{api.HookRepoTransferCanceled, "Repository transfer canceled"},
+		{api.HookRepoTransferred, "Repository transferred"},
+		{api.HookRepoCreated, ""},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		assert.Equal(t, c.text, getRepositoryTransferText(p), "case %d", i)
+	}
+}
+
 func TestGetIssueCommentPayloadInfo(t *testing.T) {
 	p := pullRequestCommentTestPayload()
 
@@ -539,3 +603,37 @@ func TestGetIssueCommentPayloadInfo(t *testing.T) {
 		assert.Equal(t, c.color, color, "case %d", i)
 	}
 }
+
+func TestGetReactionPayloadInfo(t *testing.T) {
+	p := reactionTestPayload()
+
+	text, issueTitle, link, color := getReactionPayloadInfo(p, noneLinkFormatter, true)
+	assert.Equal(t, "[test/repo] Reaction heart added to comment on issue #2 crash by user1", text)
+	assert.Equal(t, "#2 crash", issueTitle)
+	assert.Equal(t, "http://localhost:3000/test/repo/issues/2#issuecomment-4", link)
+	assert.Equal(t, greenColorLight, color)
+
+	p.Action = api.HookReactionDeleted
+	p.Comment = nil
+	p.IsPull = true
+	text, _, link, color = getReactionPayloadInfo(p, noneLinkFormatter, false)
+	assert.Equal(t, "[test/repo] Reaction heart removed from pull request #2 crash", text)
+	assert.Equal(t, "http://localhost:3000/test/repo/issues/2", link)
+	assert.Equal(t, greyColor, color)
+}
+
+func TestGetDigestPayloadInfo(t *testing.T) {
+	p := digestTestPayload()
+
+	title, name, link, text := getDigestPayloadInfo(p, noneLinkFormatter)
+	assert.Equal(t, "[test/repo] Weekly digest 2022-10-09 - 2022-10-16", title)
+	assert.Equal(t, "test/repo", name)
+	assert.Equal(t, "http://localhost:3000/test/repo", link)
+	assert.Equal(t, "test/repo: 12 commits by 1 author, 1 merged pull request, 0 new issues, 1 release\n- Merged #12 Fix bug\n- Released v1.0", text)
+
+	p.Organization = &api.Organization{UserName: "test"}
+	title, name, link, _ = getDigestPayloadInfo(p, htmlLinkFormatter)
+	assert.Equal(t, `[<a href="https://try.gitea.io/test">test</a>] Weekly digest 2022-10-09 - 2022-10-16`, title)
+	assert.Equal(t, "test", name)
+	assert.Equal(t, "https://try.gitea.io/test", link)
+}
@@ -150,14 +150,28 @@ func (m *MatrixPayloadUnsafe) Release(p *api.ReleasePayload) (api.Payloader, err
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (m *MatrixPayloadUnsafe) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, _, _, _ := getReactionPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (m *MatrixPayloadUnsafe) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, _, text := getDigestPayloadInfo(p, MatrixLinkFormatter)
+
+	return getMatrixPayloadUnsafe(title+"<br>"+strings.ReplaceAll(text, "\n", "<br>"), nil, m.AccessToken, m.MsgType), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (m *MatrixPayloadUnsafe) Push(p *api.PushPayload) (api.Payloader, error) {
 	var commitDesc string
 
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 commit"
 	} else {
-		commitDesc = fmt.Sprintf("%d commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d commits", p.CommitCount())
 	}
 
 	repoLink := MatrixLinkFormatter(p.Repo.HTMLURL, p.Repo.FullName)
@@ -216,6 +230,8 @@ func (m *MatrixPayloadUnsafe) Repository(p *api.RepositoryPayload) (api.Payloade
 		text = fmt.Sprintf("[%s] Repository created by %s", repoLink, senderLink)
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted by %s", repoLink, senderLink)
+	default:
+		text = fmt.Sprintf("[%s] %s by %s", repoLink, getRepositoryTransferText(p), senderLink)
 	}
 
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
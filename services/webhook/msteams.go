@@ -6,6 +6,7 @@ package webhook
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	webhook_model "code.gitea.io/gitea/models/webhook"
@@ -124,11 +125,11 @@ func (m *MSTeamsPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	)
 
 	var titleLink string
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 new commit"
 		titleLink = p.Commits[0].URL
 	} else {
-		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
 		titleLink = p.CompareURL
 	}
 	if titleLink == "" {
@@ -155,7 +156,7 @@ func (m *MSTeamsPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 		text,
 		titleLink,
 		greenColor,
-		&MSTeamsFact{"Commit count:", fmt.Sprintf("%d", len(p.Commits))},
+		&MSTeamsFact{"Commit count:", fmt.Sprintf("%d", p.CommitCount())},
 	), nil
 }
 
@@ -253,6 +254,10 @@ func (m *MSTeamsPayload) Repository(p *api.RepositoryPayload) (api.Payloader, er
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		color = yellowColor
+	default:
+		title = fmt.Sprintf("[%s] %s", p.Repository.FullName, getRepositoryTransferText(p))
+		url = p.Repository.HTMLURL
+		color = purpleColor
 	}
 
 	return createMSTeamsPayload(
@@ -281,6 +286,58 @@ func (m *MSTeamsPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (m *MSTeamsPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	title, _, link, color := getReactionPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		link,
+		color,
+		&MSTeamsFact{"Reaction:", p.Reaction.Reaction},
+	), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (m *MSTeamsPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, name, link, text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return &MSTeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%x", purpleColor),
+		Title:      title,
+		Summary:    title,
+		Sections: []MSTeamsSection{
+			{
+				ActivityTitle: name,
+				Text:          strings.ReplaceAll(text, "\n", "<br>"),
+				Facts: []MSTeamsFact{
+					{
+						Name:  "Repositories:",
+						Value: strconv.Itoa(len(p.Repositories)),
+					},
+				},
+			},
+		},
+		PotentialAction: []MSTeamsAction{
+			{
+				Type: "OpenUri",
+				Name: "View in Gitea",
+				Targets: []MSTeamsActionTarget{
+					{
+						Os:  "default",
+						URI: link,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 // GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
 func GetMSTeamsPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(MSTeamsPayload), p, event)
@@ -99,6 +99,16 @@ func (f *PackagistPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return nil, nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (f *PackagistPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (f *PackagistPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
 // GetPackagistPayload converts a packagist webhook into a PackagistPayload
 func GetPackagistPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	s := new(PackagistPayload)
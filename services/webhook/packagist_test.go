@@ -124,6 +124,24 @@ func TestPackagistPayload(t *testing.T) {
 		require.NoError(t, err)
 		require.Nil(t, pl)
 	})
+
+	t.Run("Reaction", func(t *testing.T) {
+		p := reactionTestPayload()
+
+		d := new(PackagistPayload)
+		pl, err := d.Reaction(p)
+		require.NoError(t, err)
+		require.Nil(t, pl)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(PackagistPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.Nil(t, pl)
+	})
 }
 
 func TestPackagistJSONPayload(t *testing.T) {
@@ -22,6 +22,8 @@ type PayloadConvertor interface {
 	Review(*api.PullRequestPayload, webhook_model.HookEventType) (api.Payloader, error)
 	Repository(*api.RepositoryPayload) (api.Payloader, error)
 	Release(*api.ReleasePayload) (api.Payloader, error)
+	Reaction(*api.ReactionPayload) (api.Payloader, error)
+	Digest(*api.DigestPayload) (api.Payloader, error)
 }
 
 func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_model.HookEventType) (api.Payloader, error) {
@@ -34,7 +36,8 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_model.H
 		return s.Fork(p.(*api.ForkPayload))
 	case webhook_model.HookEventIssues, webhook_model.HookEventIssueAssign, webhook_model.HookEventIssueLabel, webhook_model.HookEventIssueMilestone:
 		return s.Issue(p.(*api.IssuePayload))
-	case webhook_model.HookEventIssueComment, webhook_model.HookEventPullRequestComment:
+	case webhook_model.HookEventIssueComment, webhook_model.HookEventIssueCommentEdited, webhook_model.HookEventIssueCommentDeleted,
+		webhook_model.HookEventPullRequestComment, webhook_model.HookEventPullRequestCommentEdited, webhook_model.HookEventPullRequestCommentDeleted:
 		pl, ok := p.(*api.IssueCommentPayload)
 		if ok {
 			return s.IssueComment(pl)
@@ -49,8 +52,12 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_model.H
 		return s.Review(p.(*api.PullRequestPayload), event)
 	case webhook_model.HookEventRepository:
 		return s.Repository(p.(*api.RepositoryPayload))
-	case webhook_model.HookEventRelease:
+	case webhook_model.HookEventRelease, webhook_model.HookEventReleasePublished, webhook_model.HookEventReleaseEdited:
 		return s.Release(p.(*api.ReleasePayload))
+	case webhook_model.HookEventReaction:
+		return s.Reaction(p.(*api.ReactionPayload))
+	case webhook_model.HookEventDigest:
+		return s.Digest(p.(*api.DigestPayload))
 	}
 	return s, nil
 }
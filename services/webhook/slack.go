@@ -164,6 +164,27 @@ func (s *SlackPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return s.createPayload(text, nil), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (s *SlackPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, issueTitle, link, color := getReactionPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, []SlackAttachment{{
+		Color:     fmt.Sprintf("%x", color),
+		Title:     issueTitle,
+		TitleLink: link,
+	}}), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (s *SlackPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, _, text := getDigestPayloadInfo(p, SlackLinkFormatter)
+
+	return s.createPayload(title, []SlackAttachment{{
+		Color: fmt.Sprintf("%x", purpleColor),
+		Text:  text,
+	}}), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (s *SlackPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	// n new commits
@@ -172,10 +193,10 @@ func (s *SlackPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 		commitString string
 	)
 
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 new commit"
 	} else {
-		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
 	}
 	if len(p.CompareURL) > 0 {
 		commitString = SlackLinkFormatter(p.CompareURL, commitDesc)
@@ -256,6 +277,8 @@ func (s *SlackPayload) Repository(p *api.RepositoryPayload) (api.Payloader, erro
 		text = fmt.Sprintf("[%s] Repository created by %s", repoLink, senderLink)
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted by %s", repoLink, senderLink)
+	default:
+		text = fmt.Sprintf("[%s] %s by %s", repoLink, getRepositoryTransferText(p), senderLink)
 	}
 
 	return s.createPayload(text, nil), nil
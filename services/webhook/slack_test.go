@@ -63,6 +63,20 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>:<http://localhost:3000/test/repo/src/branch/test|test>] 2 new commits pushed by user1", pl.(*SlackPayload).Text)
 	})
 
+	t.Run("PushLarge", func(t *testing.T) {
+		p := pushTestPayload()
+		p.Commits = p.Commits[:1]
+		p.TotalCommits = 1500
+
+		d := new(SlackPayload)
+		pl, err := d.Push(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>:<http://localhost:3000/test/repo/src/branch/test|test>] 1500 new commits pushed by user1", pl.(*SlackPayload).Text)
+	})
+
 	t.Run("Issue", func(t *testing.T) {
 		p := issueTestPayload()
 
@@ -156,6 +170,31 @@ func TestSlackPayload(t *testing.T) {
 
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Release created: <http://localhost:3000/test/repo/releases/tag/v1.0|v1.0> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 	})
+
+	t.Run("Reaction", func(t *testing.T) {
+		p := reactionTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.Reaction(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Reaction heart added to comment on issue <http://localhost:3000/test/repo/issues/2|#2 crash> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Weekly digest 2022-10-09 - 2022-10-16", pl.(*SlackPayload).Text)
+		assert.Equal(t, "<http://localhost:3000/test/repo|test/repo>: 12 commits by 1 author, 1 merged pull request, 0 new issues, 1 release\n- Merged <http://localhost:3000/test/repo/pulls/12|#12 Fix bug>\n- Released <http://localhost:3000/test/repo/releases/tag/v1.0|v1.0>", pl.(*SlackPayload).Attachments[0].Text)
+	})
 }
 
 func TestSlackJSONPayload(t *testing.T) {
@@ -89,11 +89,11 @@ func (t *TelegramPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	)
 
 	var titleLink string
-	if len(p.Commits) == 1 {
+	if p.CommitCount() == 1 {
 		commitDesc = "1 new commit"
 		titleLink = p.Commits[0].URL
 	} else {
-		commitDesc = fmt.Sprintf("%d new commits", len(p.Commits))
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
 		titleLink = p.CompareURL
 	}
 	if titleLink == "" {
@@ -167,8 +167,10 @@ func (t *TelegramPayload) Repository(p *api.RepositoryPayload) (api.Payloader, e
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return createTelegramPayload(title), nil
+	default:
+		title = fmt.Sprintf(`[<a href="%s">%s</a>] %s`, p.Repository.HTMLURL, p.Repository.FullName, getRepositoryTransferText(p))
+		return createTelegramPayload(title), nil
 	}
-	return nil, nil
 }
 
 // Release implements PayloadConvertor Release method
@@ -178,6 +180,20 @@ func (t *TelegramPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createTelegramPayload(text), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (t *TelegramPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, _, _, _ := getReactionPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (t *TelegramPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, _, text := getDigestPayloadInfo(p, htmlLinkFormatter)
+
+	return createTelegramPayload(title + "\n" + text), nil
+}
+
 // GetTelegramPayload converts a telegram webhook into a TelegramPayload
 func GetTelegramPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(TelegramPayload), p, event)
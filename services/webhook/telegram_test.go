@@ -156,6 +156,30 @@ func TestTelegramPayload(t *testing.T) {
 
 		assert.Equal(t, `[<a href="http://localhost:3000/test/repo">test/repo</a>] Release created: <a href="http://localhost:3000/test/repo/releases/tag/v1.0">v1.0</a> by <a href="https://try.gitea.io/user1">user1</a>`, pl.(*TelegramPayload).Message)
 	})
+
+	t.Run("Reaction", func(t *testing.T) {
+		p := reactionTestPayload()
+
+		d := new(TelegramPayload)
+		pl, err := d.Reaction(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &TelegramPayload{}, pl)
+
+		assert.Equal(t, "[<a href=\"http://localhost:3000/test/repo\">test/repo</a>] Reaction heart added to comment on issue <a href=\"http://localhost:3000/test/repo/issues/2\">#2 crash</a> by <a href=\"https://try.gitea.io/user1\">user1</a>", pl.(*TelegramPayload).Message)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(TelegramPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &TelegramPayload{}, pl)
+
+		assert.Equal(t, "[<a href=\"http://localhost:3000/test/repo\">test/repo</a>] Weekly digest 2022-10-09 - 2022-10-16\n<a href=\"http://localhost:3000/test/repo\">test/repo</a>: 12 commits by 1 author, 1 merged pull request, 0 new issues, 1 release\n- Merged <a href=\"http://localhost:3000/test/repo/pulls/12\">#12 Fix bug</a>\n- Released <a href=\"http://localhost:3000/test/repo/releases/tag/v1.0\">v1.0</a>", pl.(*TelegramPayload).Message)
+	})
 }
 
 func TestTelegramJSONPayload(t *testing.T) {
@@ -140,13 +140,22 @@ func addToTask(repoID int64) error {
 
 // PrepareWebhook adds special webhook to task queue for given payload.
 func PrepareWebhook(w *webhook_model.Webhook, repo *repo_model.Repository, event webhook_model.HookEventType, p api.Payloader) error {
-	if err := prepareWebhook(w, repo, event, p); err != nil {
+	if err := prepareWebhook(w, repo.ID, event, p); err != nil {
 		return err
 	}
 
 	return addToTask(repo.ID)
 }
 
+// PrepareDigestWebhook adds the digest of the repository or the organization of the webhook to task queue.
+func PrepareDigestWebhook(w *webhook_model.Webhook, p *api.DigestPayload) error {
+	if err := prepareWebhook(w, w.RepoID, webhook_model.HookEventDigest, p); err != nil {
+		return err
+	}
+
+	return addToTask(w.RepoID)
+}
+
 func checkBranch(w *webhook_model.Webhook, branch string) bool {
 	if w.BranchFilter == "" || w.BranchFilter == "*" {
 		return true
@@ -162,7 +171,7 @@ func checkBranch(w *webhook_model.Webhook, branch string) bool {
 	return g.Match(branch)
 }
 
-func prepareWebhook(w *webhook_model.Webhook, repo *repo_model.Repository, event webhook_model.HookEventType, p api.Payloader) error {
+func prepareWebhook(w *webhook_model.Webhook, repoID int64, event webhook_model.HookEventType, p api.Payloader) error {
 	// Skip sending if webhooks are disabled.
 	if setting.DisableWebhooks {
 		return nil
@@ -208,7 +217,7 @@ func prepareWebhook(w *webhook_model.Webhook, repo *repo_model.Repository, event
 	}
 
 	if err = webhook_model.CreateHookTask(&webhook_model.HookTask{
-		RepoID:    repo.ID,
+		RepoID:    repoID,
 		HookID:    w.ID,
 		Payloader: payloader,
 		EventType: event,
@@ -240,8 +249,9 @@ func prepareWebhooks(ctx context.Context, repo *repo_model.Repository, event web
 	if repo.MustOwner().IsOrganization() {
 		// get hooks for org
 		orgHooks, err := webhook_model.ListWebhooksByOpts(ctx, &webhook_model.ListWebhookOptions{
-			OrgID:    repo.OwnerID,
-			IsActive: util.OptionalBoolTrue,
+			OrgID:     repo.OwnerID,
+			IsActive:  util.OptionalBoolTrue,
+			IsDefault: util.OptionalBoolFalse,
 		})
 		if err != nil {
 			return fmt.Errorf("GetActiveWebhooksByOrgID: %v", err)
@@ -261,7 +271,7 @@ func prepareWebhooks(ctx context.Context, repo *repo_model.Repository, event web
 	}
 
 	for _, w := range ws {
-		if err = prepareWebhook(w, repo, event, p); err != nil {
+		if err = prepareWebhook(w, repo.ID, event, p); err != nil {
 			return err
 		}
 	}
@@ -86,6 +86,12 @@ func (f *WechatworkPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 		commitDesc string
 	)
 
+	if p.CommitCount() == 1 {
+		commitDesc = "1 new commit"
+	} else {
+		commitDesc = fmt.Sprintf("%d new commits", p.CommitCount())
+	}
+
 	title := fmt.Sprintf("# %s:%s <font color=\"warning\">  %s  </font>", p.Repo.FullName, branchName, commitDesc)
 
 	var text string
@@ -161,9 +167,10 @@ func (f *WechatworkPayload) Repository(p *api.RepositoryPayload) (api.Payloader,
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		return newWechatworkMarkdownPayload(title), nil
+	default:
+		title = fmt.Sprintf("[%s] %s", p.Repository.FullName, getRepositoryTransferText(p))
+		return newWechatworkMarkdownPayload(title), nil
 	}
-
-	return nil, nil
 }
 
 // Release implements PayloadConvertor Release method
@@ -173,6 +180,22 @@ func (f *WechatworkPayload) Release(p *api.ReleasePayload) (api.Payloader, error
 	return newWechatworkMarkdownPayload(text), nil
 }
 
+// Reaction implements PayloadConvertor Reaction method
+func (f *WechatworkPayload) Reaction(p *api.ReactionPayload) (api.Payloader, error) {
+	text, issueTitle, link, _ := getReactionPayloadInfo(p, noneLinkFormatter, true)
+	content := fmt.Sprintf(" ><font color=\"info\">%s</font>\n ><font color=\"warning\">%s</font> \n [%s](%s)", text, issueTitle, link, link)
+
+	return newWechatworkMarkdownPayload(content), nil
+}
+
+// Digest implements PayloadConvertor Digest method
+func (f *WechatworkPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title, _, link, text := getDigestPayloadInfo(p, noneLinkFormatter)
+	content := fmt.Sprintf(" ><font color=\"info\">%s</font>\n%s\n [%s](%s)", title, text, link, link)
+
+	return newWechatworkMarkdownPayload(content), nil
+}
+
 // GetWechatworkPayload GetWechatworkPayload converts a ding talk webhook into a WechatworkPayload
 func GetWechatworkPayload(p api.Payloader, event webhook_model.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(WechatworkPayload), p, event)
@@ -8,6 +8,7 @@ function getArchive($target, url, first) {
     type: 'POST',
     data: {
       _csrf: csrfToken,
+      poll: !first,
     },
     complete(xhr) {
       if (xhr.status === 200) {
@@ -17,6 +18,12 @@ function getArchive($target, url, first) {
           return;
         }
 
+        if (xhr.responseJSON.status === 'failed') {
+          // The archive could not be generated, stop polling.
+          $target.closest('.dropdown').children('i').removeClass('loading');
+          return;
+        }
+
         if (!xhr.responseJSON.complete) {
           $target.closest('.dropdown').children('i').addClass('loading');
           // Wait for only three quarters of a second initially, in case it's
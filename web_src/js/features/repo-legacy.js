@@ -124,6 +124,19 @@ export function initRepoCommentForm() {
           }
         }
       } else {
+        // only one label of a scope can be selected, the server removes the others when adding a scoped label
+        const scope = $(this).data('scope');
+        if (scope) {
+          $(this).parent().find('.item.checked').each(function () {
+            if ($(this).data('scope') !== scope) return;
+            $(this).removeClass('checked');
+            $(this).find('.octicon-check').addClass('invisible');
+            if (hasUpdateAction && $(this).data('id') in items) {
+              delete items[$(this).data('id')];
+            }
+          });
+        }
+
         $(this).addClass('checked');
         $(this).find('.octicon-check').removeClass('invisible');
         if (hasUpdateAction) {